@@ -0,0 +1,162 @@
+import { $ } from "bun"
+import fs from "fs/promises"
+import path from "path"
+import { z } from "zod"
+import { App } from "../app/app"
+import { Identifier } from "../id/id"
+import { FileTime } from "../file/time"
+import { Storage } from "../storage/storage"
+import { Log } from "../util/log"
+
+// Checkpoints snapshot the project files into a git repository of their own
+// in the data directory, so the project's repository and index are never
+// touched and projects without git work the same way
+export namespace Checkpoint {
+  const log = Log.create({ service: "checkpoint" })
+
+  export const Info = z
+    .object({
+      id: Identifier.schema("checkpoint"),
+      sessionId: z.string(),
+      messageId: z.string(),
+      description: z.string(),
+      timestamp: z.number(),
+      // files changed since the previous checkpoint of the session
+      files: z.string().array(),
+      tree: z.string(),
+    })
+    .openapi({
+      ref: "Checkpoint",
+    })
+  export type Info = z.infer<typeof Info>
+
+  export const FileDiff = z
+    .object({
+      path: z.string(),
+      diff: z.string(),
+      additions: z.number(),
+      deletions: z.number(),
+    })
+    .openapi({
+      ref: "CheckpointFileDiff",
+    })
+  export type FileDiff = z.infer<typeof FileDiff>
+
+  const state = App.state("checkpoint", async () => {
+    const app = App.info()
+    const dir = path.join(app.path.data, "checkpoint")
+    const result = await $`git init --quiet`
+      .env({ ...process.env, GIT_DIR: dir, GIT_WORK_TREE: app.path.root })
+      .quiet()
+      .nothrow()
+    if (result.exitCode !== 0)
+      throw new Error(
+        `failed to create checkpoint repository: ${result.stderr.toString().trim()}`,
+      )
+    log.info("init", { path: dir })
+    return { dir, root: app.path.root }
+  })
+
+  async function git(args: string[]) {
+    const { dir, root } = await state()
+    const result = await $`git --git-dir ${dir} --work-tree ${root} ${args}`
+      .cwd(root)
+      .quiet()
+      .nothrow()
+    if (result.exitCode !== 0)
+      throw new Error(`git ${args[0]} failed: ${result.stderr.toString().trim()}`)
+    return result.stdout.toString()
+  }
+
+  function lines(output: string) {
+    return output.split("\n").filter(Boolean)
+  }
+
+  // stage the working tree into the checkpoint index and return its tree
+  async function snapshot() {
+    await git(["add", "--all", "."])
+    return (await git(["write-tree"])).trim()
+  }
+
+  function key(sessionID: string, id?: string) {
+    return ["session", "checkpoint", sessionID, id].filter(Boolean).join("/")
+  }
+
+  export async function create(input: {
+    sessionID: string
+    messageID: string
+    description: string
+  }) {
+    const tree = await snapshot()
+    const previous = (await list(input.sessionID))[0]
+    const files = previous
+      ? lines(await git(["diff-tree", "-r", "--name-only", previous.tree, tree]))
+      : []
+    const info: Info = {
+      id: Identifier.descending("checkpoint"),
+      sessionId: input.sessionID,
+      messageId: input.messageID,
+      description: input.description,
+      timestamp: Date.now(),
+      files,
+      tree,
+    }
+    await Storage.writeJSON(key(input.sessionID, info.id), info)
+    log.info("created", { id: info.id, files: files.length })
+    return info
+  }
+
+  // newest first, ids are descending
+  export async function list(sessionID: string) {
+    const result: Info[] = []
+    for await (const item of Storage.list(key(sessionID))) {
+      result.push(await Storage.readJSON<Info>(item))
+    }
+    return result.sort((a, b) => (a.id < b.id ? -1 : 1))
+  }
+
+  export async function get(id: string) {
+    for await (const item of Storage.list(key(""))) {
+      if (path.basename(item) === id) return Storage.readJSON<Info>(item)
+    }
+    throw new Error(`checkpoint ${id} not found`)
+  }
+
+  // the changes restoring the checkpoint would make to the working tree
+  export async function diff(id: string) {
+    const checkpoint = await get(id)
+    await snapshot()
+    const result: FileDiff[] = []
+    const stats = await git(["diff", "--cached", "-R", "--numstat", checkpoint.tree])
+    for (const line of lines(stats)) {
+      const [additions, deletions, file] = line.split("\t")
+      result.push({
+        path: file,
+        diff: await git(["diff", "--cached", "-R", checkpoint.tree, "--", file]),
+        // binary files have no line counts
+        additions: parseInt(additions) || 0,
+        deletions: parseInt(deletions) || 0,
+      })
+    }
+    return result
+  }
+
+  // put every file back the way it was when the checkpoint was taken,
+  // removing files that were created since
+  export async function restore(id: string) {
+    const checkpoint = await get(id)
+    const { root } = await state()
+    await snapshot()
+    const changed = lines(await git(["diff", "--cached", "--name-only", checkpoint.tree]))
+    const created = lines(
+      await git(["diff", "--cached", "--name-only", "--diff-filter=A", checkpoint.tree]),
+    )
+    for (const file of created) await fs.rm(path.join(root, file), { force: true })
+    await git(["read-tree", checkpoint.tree])
+    await git(["checkout-index", "--all", "--force"])
+    // the agent's next edit must not trip over the restored modification times
+    for (const file of changed) FileTime.read(checkpoint.sessionId, path.join(root, file))
+    log.info("restored", { id, files: changed.length })
+    return checkpoint
+  }
+}
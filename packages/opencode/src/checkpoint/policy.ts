@@ -0,0 +1,121 @@
+import { App } from "../app/app"
+import { Config } from "../config/config"
+import { Log } from "../util/log"
+import { Checkpoint } from "./index"
+
+// CheckpointPolicy takes a checkpoint right before a tool call that is risky
+// enough to want a way back, configured by the "checkpoint" config section
+export namespace CheckpointPolicy {
+  const log = Log.create({ service: "checkpoint.policy" })
+
+  export const DEFAULT_BASH_PATTERNS = [
+    "(^|[\\s;&|(])rm\\s",
+    "(^|[\\s;&|(])mv\\s",
+    "git\\s+(reset\\s+--hard|clean|checkout\\s+--)",
+  ]
+
+  export type Options = {
+    enabled: boolean
+    bashPatterns: RegExp[]
+    fileThreshold: number
+    cooldown: number
+  }
+
+  export function options(config: Config.Info["checkpoint"]): Options {
+    const patterns: RegExp[] = []
+    for (const pattern of config?.bash_patterns ?? DEFAULT_BASH_PATTERNS) {
+      try {
+        patterns.push(new RegExp(pattern))
+      } catch (e) {
+        log.warn("invalid bash pattern", { pattern, error: e })
+      }
+    }
+    return {
+      enabled: config?.enabled ?? true,
+      bashPatterns: patterns,
+      fileThreshold: config?.file_threshold ?? 5,
+      cooldown: (config?.cooldown_seconds ?? 60) * 1000,
+    }
+  }
+
+  export type Tracker = {
+    // files written per message
+    files: Map<string, Set<string>>
+    // messages that already got a checkpoint for their files
+    triggered: Set<string>
+    last: number
+  }
+
+  export function tracker(): Tracker {
+    return { files: new Map(), triggered: new Set(), last: 0 }
+  }
+
+  const state = App.state("checkpoint.policy", async () => {
+    return {
+      options: options((await Config.get()).checkpoint),
+      tracker: tracker(),
+    }
+  })
+
+  // evaluate returns why the tool call needs a checkpoint first, if it does
+  export function evaluate(
+    opts: Options,
+    tracker: Tracker,
+    input: { messageID: string; tool: string; args: any; now: number },
+  ) {
+    if (!opts.enabled) return
+    let reason: string | undefined
+    switch (input.tool) {
+      case "bash": {
+        const command: string = input.args?.command ?? ""
+        if (opts.bashPatterns.some((re) => re.test(command))) {
+          const short = command.length > 40 ? command.slice(0, 37) + "..." : command
+          reason = `before \`${short}\``
+        }
+        break
+      }
+      case "write":
+      case "edit":
+      case "multiedit": {
+        const file: string | undefined = input.args?.filePath
+        if (!file) break
+        const files = tracker.files.get(input.messageID) ?? new Set()
+        files.add(file)
+        tracker.files.set(input.messageID, files)
+        if (
+          opts.fileThreshold > 0 &&
+          files.size >= opts.fileThreshold &&
+          !tracker.triggered.has(input.messageID)
+        )
+          reason = `before editing ${files.size} files`
+        break
+      }
+    }
+    if (!reason) return
+    if (tracker.last && input.now - tracker.last < opts.cooldown) return
+    tracker.last = input.now
+    tracker.triggered.add(input.messageID)
+    return reason
+  }
+
+  // before runs ahead of every tool call. A failed checkpoint is logged and
+  // never keeps the tool from running.
+  export async function before(input: {
+    sessionID: string
+    messageID: string
+    tool: string
+    args: any
+  }) {
+    const { options, tracker } = await state()
+    const reason = evaluate(options, tracker, { ...input, now: Date.now() })
+    if (!reason) return
+    return Checkpoint.create({
+      sessionID: input.sessionID,
+      messageID: input.messageID,
+      description: "Auto: " + reason,
+    }).catch((e) => {
+      log.error("failed to create checkpoint", { reason, error: e })
+      return undefined
+    })
+  }
+}
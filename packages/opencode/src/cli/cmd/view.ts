@@ -0,0 +1,17 @@
+import { cmd } from "./cmd"
+import { TuiCommand } from "./tui"
+
+export const ViewCommand = cmd({
+  command: "view <target>",
+  describe: "open a session read-only by ID or share URL",
+  builder: (yargs) =>
+    yargs.positional("target", {
+      type: "string",
+      describe: "session ID or share URL",
+      demandOption: true,
+    }),
+  handler: async (args) => {
+    // the TUI reads the target from the arguments it is started with
+    await TuiCommand.handler({ ...args, project: undefined })
+  },
+})
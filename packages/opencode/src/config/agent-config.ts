@@ -12,6 +12,21 @@ export namespace AgentConfig {
   // Session-specific modes storage - using session ID as key
   const sessionModes = new Map<string, AgentMode>()
 
+  // Preset is a client-defined agent profile sent with a chat message
+  export const Preset = z.object({
+    name: z.string(),
+    tools: z
+      .string()
+      .array()
+      .optional()
+      .describe("Tools the agent may use, all tools of its mode when empty"),
+    temperature: z.number().min(0).max(2).optional(),
+  })
+  export type Preset = z.infer<typeof Preset>
+
+  // Presets by session ID, sub-sessions inherit the preset of their parent
+  const sessionPresets = new Map<string, Preset>()
+
   // Tool lists for different modes
   export const READ_ONLY_TOOLS = [
     "read",
@@ -59,6 +74,23 @@ export namespace AgentConfig {
     return sessionModes.get(sessionId)
   }
 
+  // Set the preset of a session, undefined clears it
+  export function setSessionPreset(sessionId: string, preset?: Preset) {
+    if (preset) sessionPresets.set(sessionId, preset)
+    else sessionPresets.delete(sessionId)
+  }
+
+  // Get the preset of a session
+  export function getSessionPreset(sessionId: string): Preset | undefined {
+    return sessionPresets.get(sessionId)
+  }
+
+  // Give a sub-session the preset of its parent
+  export function inheritPreset(parentId: string, sessionId: string) {
+    const preset = sessionPresets.get(parentId)
+    if (preset) sessionPresets.set(sessionId, preset)
+  }
+
   // Check if a session is a sub-agent (created by task tool)
   export function isSubAgentSession(
     _sessionId: string,
@@ -112,6 +144,11 @@ export namespace AgentConfig {
 
     const allowedTools = await getAllowedTools(sessionId, parentId)
     const isAllowed = allowedTools.includes(toolName)
+    // A preset can only narrow what the mode allows
+    const preset = sessionPresets.get(sessionId)
+    if (isAllowed && preset?.tools?.length) {
+      return preset.tools.includes(toolName)
+    }
     return isAllowed
   }
 }
@@ -222,6 +222,30 @@ export namespace Config {
         })
         .optional()
         .describe("Performance tracking configuration"),
+      checkpoint: z
+        .object({
+          enabled: z
+            .boolean()
+            .optional()
+            .default(true)
+            .describe("Create checkpoints automatically before risky tool calls"),
+          bash_patterns: z
+            .array(z.string())
+            .optional()
+            .describe("Regular expressions for bash commands that trigger a checkpoint"),
+          file_threshold: z
+            .number()
+            .optional()
+            .default(5)
+            .describe("Number of files edited in one message that triggers a checkpoint"),
+          cooldown_seconds: z
+            .number()
+            .optional()
+            .default(60)
+            .describe("Minimum number of seconds between automatic checkpoints"),
+        })
+        .optional()
+        .describe("Automatic checkpoint configuration"),
       agentMode: z
         .enum(["read-only", "all-tools"])
         .optional()
@@ -9,11 +9,27 @@ import path from "path"
 export namespace ConfigHooks {
   const log = Log.create({ service: "config.hooks" })
 
+  // hooks run commands from the config, which a workspace the tui has not
+  // trusted may have written, so they only run in workspaces trusted since
+  // the server started
+  const trusted = new Set<string>()
+
+  export function trust(root: string, value: boolean) {
+    log.info("trust", { root, value })
+    if (value) trusted.add(root)
+    else trusted.delete(root)
+  }
+
+  function restricted() {
+    return !trusted.has(App.info().path.root)
+  }
+
   export function init() {
     log.info("init")
     const app = App.info()
 
     Bus.subscribe(File.Event.Edited, async (payload) => {
+      if (restricted()) return
       const cfg = await Config.get()
       const ext = path.extname(payload.properties.file)
       for (const item of cfg.experimental?.hook?.file_edited?.[ext] ?? []) {
@@ -34,6 +50,7 @@ export namespace ConfigHooks {
     })
 
     Bus.subscribe(Session.Event.Idle, async () => {
+      if (restricted()) return
       const cfg = await Config.get()
       if (cfg.experimental?.hook?.session_completed) {
         for (const item of cfg.experimental.hook.session_completed) {
@@ -9,6 +9,9 @@ export const TaskStartedEvent = Bus.event(
     taskID: z.string(),
     agentName: z.string(),
     taskDescription: z.string(),
+    // canonical 1-based number of the agent among those of the session,
+    // clients number agents themselves when it is missing
+    agentNumber: z.number().optional(),
     timestamp: z.number(),
   }),
 )
@@ -20,6 +23,7 @@ export const TaskProgressEvent = Bus.event(
     taskID: z.string(),
     progress: z.number().min(0).max(100),
     message: z.string().optional(),
+    currentTool: z.string().optional(),
     timestamp: z.number(),
     startTime: z.number().optional(),
   }),
@@ -0,0 +1,82 @@
+import * as fs from "fs/promises"
+import * as path from "path"
+import { z } from "zod"
+import { App } from "../app/app"
+import { FileTime } from "./time"
+import { Log } from "../util/log"
+
+export namespace FileApply {
+  const log = Log.create({ service: "file.apply" })
+
+  // Change replaces the content of a file, null content deletes it
+  export const Change = z.object({
+    path: z.string(),
+    content: z.string().nullable(),
+  })
+  export type Change = z.infer<typeof Change>
+
+  // Original content of a file before the changes, null if it did not exist
+  type Backup = { file: string; content: string | null }
+
+  function resolve(file: string) {
+    const app = App.info()
+    const resolved = path.resolve(app.path.cwd, file)
+    const relative = path.relative(app.path.root, resolved)
+    if (relative.startsWith("..") || path.isAbsolute(relative)) {
+      throw new Error(`${file} is outside the project`)
+    }
+    return resolved
+  }
+
+  async function readOrNull(file: string) {
+    return fs.readFile(file, "utf8").catch((e) => {
+      if (e.code === "ENOENT") return null
+      throw e
+    })
+  }
+
+  async function write(file: string, content: string | null) {
+    if (content === null) {
+      await fs.rm(file, { force: true })
+      return
+    }
+    await fs.mkdir(path.dirname(file), { recursive: true })
+    // write next to the file and rename, so a file is never half written
+    const tmp = `${file}.${process.pid}.${Date.now()}.tmp`
+    await fs.writeFile(tmp, content)
+    await fs.rename(tmp, file).catch(async (e) => {
+      await fs.rm(tmp, { force: true })
+      throw e
+    })
+  }
+
+  // Apply all changes or none. Files already written are restored when a
+  // later one fails.
+  export async function atomic(sessionID: string, changes: Change[]) {
+    const files = changes.map((change) => resolve(change.path))
+    const backups: Backup[] = []
+    for (const file of files) {
+      backups.push({ file, content: await readOrNull(file) })
+    }
+
+    const written: Backup[] = []
+    try {
+      for (const [i, change] of changes.entries()) {
+        await write(files[i], change.content)
+        written.push(backups[i])
+      }
+    } catch (e) {
+      log.error("rolling back", { error: e })
+      for (const backup of written.reverse()) {
+        await write(backup.file, backup.content).catch((err) =>
+          log.error("failed to restore", { file: backup.file, error: err }),
+        )
+      }
+      throw e
+    }
+
+    // the agent's next edit must not trip over the new modification times
+    for (const file of files) FileTime.read(sessionID, file)
+    return files
+  }
+}
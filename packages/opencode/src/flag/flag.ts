@@ -1,6 +1,8 @@
 export namespace Flag {
   export const DGMO_AUTO_SHARE = truthy("DGMO_AUTO_SHARE")
   export const OPENCODE_AUTO_SHARE = truthy("OPENCODE_AUTO_SHARE") // Backwards compatibility
+  // Hold destructive tool calls until a client approves them
+  export const DGMO_APPROVAL = truthy("DGMO_APPROVAL")
 
   function truthy(key: string) {
     const value = process.env[key]?.toLowerCase()
@@ -6,6 +6,7 @@ export namespace Identifier {
     session: "ses",
     message: "msg",
     user: "usr",
+    checkpoint: "cpt",
   } as const
 
   export function schema(prefix: keyof typeof prefixes) {
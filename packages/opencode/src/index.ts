@@ -14,6 +14,7 @@ import { NamedError } from "./util/error"
 import { FormatError } from "./cli/error"
 import { ServeCommand } from "./cli/cmd/serve"
 import { TuiCommand } from "./cli/cmd/tui"
+import { ViewCommand } from "./cli/cmd/view"
 import { DebugCommand } from "./cli/cmd/debug"
 import { EvolveCommand } from "./cli/cmd/evolve"
 
@@ -49,6 +50,7 @@ const cli = yargs(hideBin(process.argv))
   })
   .usage("\n" + UI.logo())
   .command(TuiCommand)
+  .command(ViewCommand)
   .command(RunCommand)
   .command(GenerateCommand)
   .command(DebugCommand)
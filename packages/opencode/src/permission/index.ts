@@ -2,6 +2,7 @@ import { App } from "../app/app"
 import { z } from "zod"
 import { Bus } from "../bus"
 import { Log } from "../util/log"
+import { Flag } from "../flag/flag"
 
 export namespace Permission {
   const log = Log.create({ service: "permission" })
@@ -9,6 +10,7 @@ export namespace Permission {
   export const Info = z
     .object({
       id: z.string(),
+      tool: z.string(),
       sessionID: z.string(),
       title: z.string(),
       metadata: z.record(z.any()),
@@ -40,7 +42,7 @@ export namespace Permission {
 
       const approved: {
         [sessionID: string]: {
-          [permissionID: string]: Info
+          [tool: string]: Info
         }
       } = {}
 
@@ -60,17 +62,19 @@ export namespace Permission {
 
   export function ask(input: {
     id: Info["id"]
+    tool: Info["tool"]
     sessionID: Info["sessionID"]
     title: Info["title"]
     metadata: Info["metadata"]
   }) {
-    return
+    if (!Flag.DGMO_APPROVAL) return
     const { pending, approved } = state()
     log.info("asking", {
       sessionID: input.sessionID,
       permissionID: input.id,
+      tool: input.tool,
     })
-    if (approved[input.sessionID]?.[input.id]) {
+    if (approved[input.sessionID]?.[input.tool]) {
       log.info("previously approved", {
         sessionID: input.sessionID,
         permissionID: input.id,
@@ -79,6 +83,7 @@ export namespace Permission {
     }
     const info: Info = {
       id: input.id,
+      tool: input.tool,
       sessionID: input.sessionID,
       title: input.title,
       metadata: input.metadata,
@@ -93,13 +98,6 @@ export namespace Permission {
         resolve,
         reject,
       }
-      setTimeout(() => {
-        respond({
-          sessionID: input.sessionID,
-          permissionID: input.id,
-          response: "always",
-        })
-      }, 1000)
       Bus.publish(Event.Updated, info)
     })
   }
@@ -121,7 +119,7 @@ export namespace Permission {
     match.resolve()
     if (input.response === "always") {
       approved[input.sessionID] = approved[input.sessionID] || {}
-      approved[input.sessionID][input.permissionID] = match.info
+      approved[input.sessionID][match.info.tool] = match.info
     }
   }
 
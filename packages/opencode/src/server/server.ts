@@ -6,6 +6,7 @@ import { Hono } from "hono"
 import { streamSSE } from "hono/streaming"
 import { Session } from "../session"
 import { SubSession } from "../session/sub-session"
+import { SessionContinuation } from "../session/continuation"
 import { resolver, validator as zValidator } from "hono-openapi/zod"
 import { z } from "zod"
 import { Message } from "../session/message"
@@ -16,6 +17,13 @@ import { NamedError } from "../util/error"
 import { ModelsDev } from "../provider/models"
 import { Ripgrep } from "../file/ripgrep"
 import { Config } from "../config/config"
+import { ConfigHooks } from "../config/hooks"
+import { AgentConfig } from "../config/agent-config"
+import { File } from "../file"
+import { FileApply } from "../file/apply"
+import { Checkpoint } from "../checkpoint"
+import { Permission } from "../permission"
+import { Flag } from "../flag/flag"
 
 const ERRORS = {
   400: {
@@ -161,6 +169,33 @@ export namespace Server {
           return c.json(true)
         },
       )
+      .post(
+        "/app/trust",
+        describeRoute({
+          description:
+            "Set whether the workspace is trusted, config hooks only run in a trusted workspace",
+          responses: {
+            200: {
+              description: "Trust updated",
+              content: {
+                "application/json": {
+                  schema: resolver(z.boolean()),
+                },
+              },
+            },
+          },
+        }),
+        zValidator(
+          "json",
+          z.object({
+            trusted: z.boolean(),
+          }),
+        ),
+        async (c) => {
+          ConfigHooks.trust(App.info().path.root, c.req.valid("json").trusted)
+          return c.json(true)
+        },
+      )
       .get(
         "/config",
         describeRoute({
@@ -180,6 +215,45 @@ export namespace Server {
           return c.json(await Config.get())
         },
       )
+      .get(
+        "/capabilities",
+        describeRoute({
+          description: "List optional features supported by this server",
+          responses: {
+            200: {
+              description: "Supported features",
+              content: {
+                "application/json": {
+                  schema: resolver(
+                    z.object({
+                      features: z.string().array(),
+                    }),
+                  ),
+                },
+              },
+            },
+          },
+        }),
+        async (c) => {
+          const features = [
+            "tasks",
+            "rename",
+            "instructions",
+            "presets",
+            "patch",
+            "checkpoints",
+            "mcp",
+            "continuation",
+            "truncate",
+            "env",
+            "branch",
+            "analysis",
+            "trust",
+          ]
+          if (Flag.DGMO_APPROVAL) features.push("permissions")
+          return c.json({ features })
+        },
+      )
       .get(
         "/session",
         describeRoute({
@@ -247,6 +321,54 @@ export namespace Server {
           return c.json(true)
         },
       )
+      .patch(
+        "/session/:id",
+        describeRoute({
+          description:
+            "Update the title, description, instructions or environment of a session",
+          responses: {
+            200: {
+              description: "Updated session",
+              content: {
+                "application/json": {
+                  schema: resolver(Session.Info),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string(),
+          }),
+        ),
+        zValidator(
+          "json",
+          z.object({
+            title: z.string().trim().min(1).optional(),
+            description: z.string().trim().optional(),
+            instructions: z.string().trim().optional(),
+            env: z
+              .record(z.string().regex(/^[A-Za-z_][A-Za-z0-9_]*$/), z.string())
+              .optional(),
+          }),
+        ),
+        async (c) => {
+          const id = c.req.valid("param").id
+          const body = c.req.valid("json")
+          const session = await Session.update(id, (draft) => {
+            if (body.title !== undefined) draft.title = body.title
+            if (body.description !== undefined) draft.description = body.description || undefined
+            if (body.instructions !== undefined) draft.instructions = body.instructions || undefined
+            if (body.env !== undefined)
+              draft.env = Object.keys(body.env).length > 0 ? body.env : undefined
+          })
+          if (!session) throw new Error(`Session ${id} not found`)
+          return c.json(session)
+        },
+      )
       .post(
         "/session/:id/init",
         describeRoute({
@@ -273,6 +395,10 @@ export namespace Server {
           z.object({
             providerID: z.string(),
             modelID: z.string(),
+            analysis: z
+              .string()
+              .optional()
+              .openapi({ description: "Project findings reviewed by the user, such as languages and test commands" }),
           }),
         ),
         async (c) => {
@@ -307,6 +433,114 @@ export namespace Server {
           return c.json(Session.abort(c.req.valid("param").id))
         },
       )
+      .post(
+        "/session/:id/branch",
+        describeRoute({
+          description:
+            "Create a session with the messages of this one up to and including a message",
+          responses: {
+            200: {
+              description: "The new session",
+              content: {
+                "application/json": {
+                  schema: resolver(Session.Info),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string(),
+          }),
+        ),
+        zValidator(
+          "json",
+          z.object({
+            messageID: z.string(),
+          }),
+        ),
+        async (c) => {
+          const id = c.req.valid("param").id
+          const body = c.req.valid("json")
+          return c.json(await Session.branch(id, body.messageID))
+        },
+      )
+      .post(
+        "/session/:id/truncate",
+        describeRoute({
+          description:
+            "Remove a message and every message after it, to resend from that point",
+          responses: {
+            200: {
+              description: "Number of messages removed",
+              content: {
+                "application/json": {
+                  schema: resolver(z.number()),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string(),
+          }),
+        ),
+        zValidator(
+          "json",
+          z.object({
+            messageID: z.string(),
+          }),
+        ),
+        async (c) => {
+          const id = c.req.valid("param").id
+          const body = c.req.valid("json")
+          return c.json(await Session.truncate(id, body.messageID))
+        },
+      )
+      .post(
+        "/session/:id/permissions/:permissionID",
+        describeRoute({
+          description: "Respond to a permission request",
+          responses: {
+            200: {
+              description: "Permission processed",
+              content: {
+                "application/json": {
+                  schema: resolver(z.boolean()),
+                },
+              },
+            },
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string(),
+            permissionID: z.string(),
+          }),
+        ),
+        zValidator(
+          "json",
+          z.object({
+            response: z.enum(["once", "always", "reject"]),
+          }),
+        ),
+        async (c) => {
+          const params = c.req.valid("param")
+          Permission.respond({
+            sessionID: params.id,
+            permissionID: params.permissionID,
+            response: c.req.valid("json").response,
+          })
+          return c.json(true)
+        },
+      )
       .post(
         "/session/:id/share",
         describeRoute({
@@ -451,15 +685,207 @@ export namespace Server {
             providerID: z.string(),
             modelID: z.string(),
             parts: Message.MessagePart.array(),
+            preset: AgentConfig.Preset.optional(),
           }),
         ),
         async (c) => {
           const sessionID = c.req.valid("param").id
-          const body = c.req.valid("json")
+          const { preset, ...body } = c.req.valid("json")
+          // the client sends its selected preset, or none, with every message
+          AgentConfig.setSessionPreset(sessionID, preset)
           const msg = await Session.chat({ ...body, sessionID })
           return c.json(msg)
         },
       )
+      .post(
+        "/session/:id/patch",
+        describeRoute({
+          description:
+            "Write reviewed file changes all at once and note them in the session",
+          responses: {
+            200: {
+              description: "The note added to the session",
+              content: {
+                "application/json": {
+                  schema: resolver(Message.Info),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string().openapi({ description: "Session ID" }),
+          }),
+        ),
+        zValidator(
+          "json",
+          z.object({
+            changes: FileApply.Change.array().min(1),
+            summary: z.string(),
+          }),
+        ),
+        async (c) => {
+          const sessionID = c.req.valid("param").id
+          const body = c.req.valid("json")
+          await Session.get(sessionID)
+          const files = await FileApply.atomic(sessionID, body.changes)
+          for (const file of files) Bus.publish(File.Event.Edited, { file })
+          const note = await Session.note(sessionID, body.summary)
+          return c.json(note)
+        },
+      )
+      .post(
+        "/session/:id/continuation",
+        describeRoute({
+          description:
+            "Generate a prompt that lets a fresh agent continue the session's work",
+          responses: {
+            200: {
+              description: "The continuation prompt",
+              content: {
+                "application/json": {
+                  schema: resolver(SessionContinuation.Response),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string().openapi({ description: "Session ID" }),
+          }),
+        ),
+        zValidator("json", SessionContinuation.Request),
+        async (c) => {
+          const sessionID = c.req.valid("param").id
+          const body = c.req.valid("json")
+          return c.json(await SessionContinuation.generate(sessionID, body))
+        },
+      )
+      .get(
+        "/session/:id/checkpoints",
+        describeRoute({
+          description: "List the checkpoints of a session, newest first",
+          responses: {
+            200: {
+              description: "List of checkpoints",
+              content: {
+                "application/json": {
+                  schema: resolver(Checkpoint.Info.array()),
+                },
+              },
+            },
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string().openapi({ description: "Session ID" }),
+          }),
+        ),
+        async (c) => {
+          return c.json(await Checkpoint.list(c.req.valid("param").id))
+        },
+      )
+      .post(
+        "/session/:id/checkpoint",
+        describeRoute({
+          description:
+            "Snapshot the project files, anchored to the latest message of the session",
+          responses: {
+            200: {
+              description: "The created checkpoint",
+              content: {
+                "application/json": {
+                  schema: resolver(Checkpoint.Info),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string().openapi({ description: "Session ID" }),
+          }),
+        ),
+        zValidator(
+          "json",
+          z.object({
+            description: z.string().optional(),
+          }),
+        ),
+        async (c) => {
+          const sessionID = c.req.valid("param").id
+          const body = c.req.valid("json")
+          await Session.get(sessionID)
+          const messages = await Session.messages(sessionID)
+          const checkpoint = await Checkpoint.create({
+            sessionID,
+            messageID: messages.at(-1)?.id ?? "",
+            description: body.description ?? "",
+          })
+          return c.json(checkpoint)
+        },
+      )
+      .post(
+        "/checkpoint/:id/restore",
+        describeRoute({
+          description: "Restore the project files to a checkpoint",
+          responses: {
+            200: {
+              description: "The restored checkpoint",
+              content: {
+                "application/json": {
+                  schema: resolver(Checkpoint.Info),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string().openapi({ description: "Checkpoint ID" }),
+          }),
+        ),
+        async (c) => {
+          return c.json(await Checkpoint.restore(c.req.valid("param").id))
+        },
+      )
+      .get(
+        "/checkpoint/:id/diff",
+        describeRoute({
+          description: "Changes restoring a checkpoint would make, by file",
+          responses: {
+            200: {
+              description: "File diffs",
+              content: {
+                "application/json": {
+                  schema: resolver(Checkpoint.FileDiff.array()),
+                },
+              },
+            },
+            ...ERRORS,
+          },
+        }),
+        zValidator(
+          "param",
+          z.object({
+            id: z.string().openapi({ description: "Checkpoint ID" }),
+          }),
+        ),
+        async (c) => {
+          return c.json(await Checkpoint.diff(c.req.valid("param").id))
+        },
+      )
       .get(
         "/session/:id/sub-sessions",
         describeRoute({
@@ -0,0 +1,130 @@
+import path from "path"
+import { z } from "zod"
+import { App } from "../app/app"
+import { Message } from "./message"
+import { Session } from "./index"
+import {
+  continuationPromptGenerator,
+  type ProjectState,
+} from "./continuation-prompt-generator"
+
+// SessionContinuation turns what a session did so far into a prompt that
+// lets a fresh agent pick up the work
+export namespace SessionContinuation {
+  export const Request = z.object({
+    goal: z.string().optional(),
+    workingDirectory: z.string().optional(),
+  })
+  export type Request = z.infer<typeof Request>
+
+  export const Response = z
+    .object({
+      prompt: z.string(),
+      projectName: z.string(),
+      completionPercentage: z.number(),
+      remainingTasks: z.string().array(),
+    })
+    .openapi({
+      ref: "ContinuationResponse",
+    })
+  export type Response = z.infer<typeof Response>
+
+  type Todo = {
+    content: string
+    status: "pending" | "in_progress" | "completed"
+    priority: "high" | "medium" | "low"
+  }
+
+  const EDIT_TOOLS = new Set(["write", "edit", "multiedit"])
+  // the title a session has until one is generated from the first prompt
+  const DEFAULT_TITLE = /^(New Session|Child session) - /
+
+  function invocations(messages: Message.Info[]) {
+    return messages.flatMap((msg) =>
+      msg.parts.flatMap((part) =>
+        part.type === "tool-invocation" && part.toolInvocation.state !== "partial-call"
+          ? [part.toolInvocation]
+          : [],
+      ),
+    )
+  }
+
+  function firstPrompt(messages: Message.Info[]) {
+    for (const msg of messages) {
+      if (msg.role !== "user") continue
+      for (const part of msg.parts) {
+        if (part.type === "text" && part.text.trim()) return part.text.trim()
+      }
+    }
+  }
+
+  // state reads the project state from the session: the latest todo list
+  // gives progress and remaining work, edited files are the critical ones
+  export function state(input: {
+    messages: Message.Info[]
+    title: string
+    root: string
+    cwd: string
+    request: Request
+  }): ProjectState {
+    const calls = invocations(input.messages)
+    let todos: Todo[] = []
+    const files = new Set<string>()
+    for (const call of calls) {
+      const args = call.args as any
+      if (call.toolName === "todowrite" && Array.isArray(args?.todos)) todos = args.todos
+      if (EDIT_TOOLS.has(call.toolName) && typeof args?.filePath === "string")
+        files.add(path.relative(input.root, args.filePath) || args.filePath)
+    }
+    const completed = todos.filter((todo) => todo.status === "completed")
+    const remaining = todos.filter((todo) => todo.status !== "completed")
+
+    return {
+      projectName: path.basename(input.root),
+      projectGoal:
+        input.request.goal ||
+        (DEFAULT_TITLE.test(input.title) ? "" : input.title) ||
+        firstPrompt(input.messages) ||
+        "the current task",
+      completionPercentage: todos.length
+        ? Math.round((completed.length / todos.length) * 100)
+        : 0,
+      workingDirectory: input.request.workingDirectory || input.cwd,
+      completedComponents: completed.map((todo) => ({
+        name: todo.content,
+        description: "completed in the previous session",
+      })),
+      remainingTasks: remaining.map((todo) => ({
+        name: todo.content,
+        description: todo.status === "in_progress" ? "started, not finished" : "not started",
+        priority: todo.priority,
+      })),
+      criticalFiles: [...files].map((file) => ({
+        path: file,
+        description: "changed in the previous session",
+      })),
+      knownIssues: [],
+      architecturalConstraints: [],
+      successCriteria: [],
+      testingApproach: [],
+    }
+  }
+
+  export async function generate(sessionID: string, request: Request): Promise<Response> {
+    const app = App.info()
+    const session = await Session.get(sessionID)
+    const project = state({
+      messages: await Session.messages(sessionID),
+      title: session.title,
+      root: app.path.root,
+      cwd: app.path.cwd,
+      request,
+    })
+    return {
+      prompt: continuationPromptGenerator.generateContinuationPrompt(project),
+      projectName: project.projectName,
+      completionPercentage: project.completionPercentage,
+      remainingTasks: project.remainingTasks.map((task) => task.name),
+    }
+  }
+}
@@ -38,6 +38,7 @@ import { ProviderTransform } from "../provider/transform"
 import { SessionPerformance } from "./performance"
 import { PerformanceWrapper } from "../tool/performance-wrapper"
 import { AgentConfig } from "../config/agent-config"
+import { CheckpointPolicy } from "../checkpoint/policy"
 export namespace Session {
   const log = Log.create({ service: "session" })
 
@@ -51,6 +52,17 @@ export namespace Session {
         })
         .optional(),
       title: z.string(),
+      description: z.string().optional(),
+      instructions: z.string().optional(),
+      // environment variables added to the bash tool of this session
+      env: z.record(z.string(), z.string()).optional(),
+      // the session and message this session was branched from
+      branch: z
+        .object({
+          sessionID: z.string(),
+          messageID: z.string(),
+        })
+        .optional(),
       version: z.string(),
       time: z.object({
         created: z.number(),
@@ -283,6 +295,43 @@ export namespace Session {
     }
   }
 
+  // Start a new session with the transcript of another one up to and
+  // including a message, so the conversation can go another way from there
+  export async function branch(sessionID: string, messageID: string) {
+    const origin = await get(sessionID)
+    const msgs = await messages(sessionID)
+    if (!msgs.some((msg) => msg.id === messageID)) {
+      throw new Error(`Message ${messageID} not found in session ${sessionID}`)
+    }
+    const session = await create()
+    for (const msg of msgs.filter((msg) => msg.id <= messageID)) {
+      const copy = structuredClone(msg)
+      copy.metadata.sessionID = session.id
+      await Storage.writeJSON("session/message/" + session.id + "/" + copy.id, copy)
+    }
+    return update(session.id, (draft) => {
+      draft.title = "Branch of " + origin.title
+      draft.instructions = origin.instructions
+      draft.env = origin.env
+      draft.branch = { sessionID, messageID }
+    })
+  }
+
+  // Remove a message and every message after it, so the conversation can go
+  // on from that point. Returns how many messages were removed.
+  export async function truncate(sessionID: string, messageID: string) {
+    abort(sessionID)
+    const msgs = await messages(sessionID)
+    if (!msgs.some((msg) => msg.id === messageID)) {
+      throw new Error(`Message ${messageID} not found in session ${sessionID}`)
+    }
+    const removed = msgs.filter((msg) => msg.id >= messageID)
+    for (const msg of removed) {
+      await Storage.remove("session/message/" + sessionID + "/" + msg.id)
+    }
+    return removed.length
+  }
+
   async function updateMessage(msg: Message.Info) {
     await Storage.writeJSON(
       "session/message/" + msg.metadata.sessionID + "/" + msg.id,
@@ -293,6 +342,25 @@ export namespace Session {
     })
   }
 
+  // Record something the user did outside the conversation, so it shows in
+  // the transcript and the model sees it on the next message
+  export async function note(sessionID: string, text: string) {
+    const msg: Message.Info = {
+      role: "user",
+      id: Identifier.ascending("message"),
+      parts: [{ type: "text", text }],
+      metadata: {
+        time: {
+          created: Date.now(),
+        },
+        sessionID,
+        tool: {},
+      },
+    }
+    await updateMessage(msg)
+    return msg
+  }
+
   export async function chat(input: {
     sessionID: string
     providerID: string
@@ -387,6 +455,8 @@ export namespace Session {
     const system = input.system ?? SystemPrompt.provider(input.providerID)
     system.push(...(await SystemPrompt.environment()))
     system.push(...(await SystemPrompt.custom()))
+    // instructions set for this session only, after the project defaults
+    if (session.instructions) system.push(session.instructions)
 
     const next: Message.Info = {
       id: Identifier.ascending("message"),
@@ -467,12 +537,19 @@ export namespace Session {
         description: item.description,
         parameters: item.parameters as ZodSchema,
         async execute(args, opts) {
+          await CheckpointPolicy.before({
+            sessionID: input.sessionID,
+            messageID: next.id,
+            tool: item.id,
+            args,
+          })
           const start = Date.now()
           try {
             const result = await item.execute(args, {
               sessionID: input.sessionID,
               abort: abort.signal,
               messageID: next.id,
+              callID: opts.toolCallId,
               metadata: async (val) => {
                 next.metadata.tool[opts.toolCallId] = {
                   ...val,
@@ -615,7 +692,9 @@ export namespace Session {
           msgs.map(toUIMessage).filter((x) => x.parts.length > 0),
         ),
       ],
-      temperature: model.info.temperature ? 0 : undefined,
+      temperature:
+        AgentConfig.getSessionPreset(input.sessionID)?.temperature ??
+        (model.info.temperature ? 0 : undefined),
       tools: model.info.tool_call === false ? undefined : tools,
       model: wrapLanguageModel({
         model: model.language,
@@ -692,8 +771,27 @@ export namespace Session {
             })
             break
 
-          case "tool-call-delta":
-            continue
+          case "tool-call-delta": {
+            const [match] = next.parts.flatMap((p) =>
+              p.type === "tool-invocation" &&
+              p.toolInvocation.toolCallId === value.toolCallId &&
+              p.toolInvocation.state === "partial-call"
+                ? [p]
+                : [],
+            )
+            if (!match) continue
+            // the arguments stream as raw JSON text until the call completes,
+            // the tui parses what has arrived to show the path or command early
+            const args = match.toolInvocation.args
+            match.toolInvocation.args =
+              (typeof args === "string" ? args : "") + value.argsTextDelta
+            Bus.publish(Message.Event.PartUpdated, {
+              part: match,
+              messageID: next.id,
+              sessionID: next.metadata.sessionID,
+            })
+            break
+          }
 
           // for some reason ai sdk claims to not send this part but it does
           // @ts-expect-error
@@ -788,6 +886,10 @@ export namespace Session {
       ) {
         part.toolInvocation = {
           ...part.toolInvocation,
+          args:
+            typeof part.toolInvocation.args === "string"
+              ? {}
+              : part.toolInvocation.args,
           state: "result",
           result: "request was aborted",
         }
@@ -973,8 +1075,14 @@ export namespace Session {
     sessionID: string
     modelID: string
     providerID: string
+    analysis?: string
   }) {
     const app = App.info()
+    let text = PROMPT_INITIALIZE.replace("${path}", app.path.root)
+    if (input.analysis?.trim())
+      text +=
+        "\n\nThe user reviewed this analysis of the project, treat it as correct where it conflicts with what you find:\n" +
+        input.analysis.trim()
     await Session.chat({
       sessionID: input.sessionID,
       providerID: input.providerID,
@@ -982,7 +1090,7 @@ export namespace Session {
       parts: [
         {
           type: "text",
-          text: PROMPT_INITIALIZE.replace("${path}", app.path.root),
+          text,
         },
       ],
     })
@@ -12,6 +12,9 @@ export namespace SubSession {
     taskDescription: z.string(),
     status: z.enum(["pending", "running", "completed", "failed"]),
     createdAt: z.number(),
+    // 1-based position among the sub-sessions of the parent, missing for
+    // records written before it was stored
+    agentNumber: z.number().optional(),
     startedAt: z.number().optional(),
     completedAt: z.number().optional(),
     summary: z.string().optional(),
@@ -32,6 +35,14 @@ export namespace SubSession {
     // Get storage base path for debugging
     const { Storage } = await import("../storage/storage")
 
+    // Add to parent's sub-session index, the position in it is the agent
+    // number clients show
+    const indexPath = SUB_SESSION_INDEX_PATH + parentSessionId
+
+    const index = await getParentIndex(parentSessionId)
+    index.push(sessionId)
+    await Storage.writeJSON(indexPath, index)
+
     const info: Info = {
       id: sessionId,
       parentSessionId,
@@ -39,18 +50,12 @@ export namespace SubSession {
       taskDescription,
       status: "pending",
       createdAt: Date.now(),
+      agentNumber: index.length,
     }
 
     // Store sub-session info
     await Storage.writeJSON(SUB_SESSION_PATH + sessionId, info)
 
-    // Add to parent's sub-session index
-    const indexPath = SUB_SESSION_INDEX_PATH + parentSessionId
-
-    const index = await getParentIndex(parentSessionId)
-    index.push(sessionId)
-    await Storage.writeJSON(indexPath, index)
-
     return info
   }
 
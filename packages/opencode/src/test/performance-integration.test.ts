@@ -37,6 +37,7 @@ test("PerformanceWrapper wraps tool execution", async () => {
     {
       sessionID: "test-session",
       messageID: "test-message",
+      callID: "test-call",
       abort: new AbortController().signal,
       metadata: async () => {},
     }
@@ -74,6 +75,7 @@ test("PerformanceWrapper handles errors correctly", async () => {
       {
         sessionID: "test-session",
         messageID: "test-message",
+        callID: "test-call",
         abort: new AbortController().signal,
         metadata: async () => {},
       }
@@ -2,6 +2,8 @@ import { z } from "zod"
 import { Tool } from "./tool"
 import DESCRIPTION from "./bash.txt"
 import { App } from "../app/app"
+import { Permission } from "../permission"
+import { Session } from "../session"
 
 const MAX_OUTPUT_LENGTH = 30000
 const BANNED_COMMANDS = [
@@ -48,9 +50,23 @@ export const BashTool = Tool.define({
     if (BANNED_COMMANDS.some((item) => params.command.startsWith(item)))
       throw new Error(`Command '${params.command}' is not allowed`)
 
+    await Permission.ask({
+      id: ctx.callID,
+      tool: "bash",
+      sessionID: ctx.sessionID,
+      title: "Run this command: " + params.command,
+      metadata: {
+        command: params.command,
+        description: params.description,
+      },
+    })
+
+    // variables set for this session only, over the server's own
+    const session = await Session.get(ctx.sessionID)
     const process = Bun.spawn({
       cmd: ["bash", "-c", params.command],
       cwd: App.info().path.cwd,
+      env: { ...Bun.env, ...session.env },
       maxBuffer: MAX_OUTPUT_LENGTH,
       signal: ctx.abort,
       timeout: timeout,
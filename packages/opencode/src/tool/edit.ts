@@ -45,7 +45,8 @@ export const EditTool = Tool.define({
       : path.join(app.path.cwd, params.filePath)
 
     await Permission.ask({
-      id: "edit",
+      id: ctx.callID,
+      tool: "edit",
       sessionID: ctx.sessionID,
       title: "Edit this file: " + filepath,
       metadata: {
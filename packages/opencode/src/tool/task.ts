@@ -28,6 +28,12 @@ export const TaskTool = Tool.define({
       .enum(["read-only", "all-tools"])
       .optional()
       .describe("Tool access mode for the sub-agent (defaults to read-only)"),
+    model: z
+      .string()
+      .optional()
+      .describe(
+        "Model for the sub-agent as provider/model (defaults to the model of this session)",
+      ),
     autoDebug: z
       .boolean()
       .optional()
@@ -68,15 +74,18 @@ export const TaskTool = Tool.define({
     // Set the agent mode for this sub-session
     const mode = params.agentMode || "read-only"
     AgentConfig.setSessionAgentMode(subSession.id, mode)
+    AgentConfig.inheritPreset(ctx.sessionID, subSession.id)
 
     // Store sub-session info for navigation
+    let agentNumber: number | undefined
     try {
-      await SubSession.create(
+      const info = await SubSession.create(
         ctx.sessionID,
         subSession.id,
         params.description,
         params.prompt,
       )
+      agentNumber = info.agentNumber
       Debug.log("[TASK] SubSession.create completed successfully")
 
       // Verification Agent - verify sub-session creation in real-time
@@ -132,6 +141,7 @@ export const TaskTool = Tool.define({
       taskID,
       agentName: params.description,
       taskDescription: params.prompt,
+      agentNumber,
       timestamp: startTime,
     })
     function summary(input: Message.Info) {
@@ -153,6 +163,7 @@ export const TaskTool = Tool.define({
       ctx.metadata({
         title: params.description,
         summary: summary(evt.properties.info),
+        sessionID: subSession.id,
       })
 
       // Emit progress event with better estimation
@@ -160,6 +171,7 @@ export const TaskTool = Tool.define({
       const message = evt.properties.info
       let toolCount = 0
       let completedTools = 0
+      let currentTool: string | undefined
 
       if (message.parts) {
         message.parts.forEach((part) => {
@@ -170,6 +182,8 @@ export const TaskTool = Tool.define({
               part.toolInvocation?.state === "result"
             ) {
               completedTools++
+            } else {
+              currentTool = part.toolInvocation?.toolName
             }
           }
         })
@@ -189,6 +203,7 @@ export const TaskTool = Tool.define({
         taskID,
         progress,
         message: `Processing (${completedTools}/${toolCount} tools completed)...`,
+        currentTool,
         timestamp: Date.now(),
         startTime: startTime,
       })
@@ -197,11 +212,16 @@ export const TaskTool = Tool.define({
     ctx.abort.addEventListener("abort", () => {
       Session.abort(subSession.id)
     })
+    let model = { providerID: metadata.providerID, modelID: metadata.modelID }
+    if (params.model) {
+      const { Provider } = await import("../provider/provider")
+      model = Provider.parseModel(params.model)
+    }
     try {
       const result = await Session.chat({
         sessionID: subSession.id,
-        modelID: metadata.modelID,
-        providerID: metadata.providerID,
+        modelID: model.modelID,
+        providerID: model.providerID,
         parts: [
           {
             type: "text",
@@ -235,6 +255,7 @@ export const TaskTool = Tool.define({
         metadata: {
           title: params.description,
           summary: summary(result),
+          sessionID: subSession.id,
         },
         output,
       }
@@ -313,6 +334,7 @@ export const TaskTool = Tool.define({
               metadata: {
                 title: `${params.description} (recovered)`,
                 summary: summary(debugResult),
+                sessionID: subSession.id,
               },
               output: debugOutput,
             }
@@ -8,6 +8,7 @@ export namespace Tool {
   export type Context<M extends Metadata = Metadata> = {
     sessionID: string
     messageID: string
+    callID: string
     abort: AbortSignal
     metadata(meta: M): void
   }
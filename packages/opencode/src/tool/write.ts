@@ -31,7 +31,8 @@ export const WriteTool = Tool.define({
     if (exists) await FileTime.assert(ctx.sessionID, filepath)
 
     await Permission.ask({
-      id: "write",
+      id: ctx.callID,
+      tool: "write",
       sessionID: ctx.sessionID,
       title: exists
         ? "Overwrite this file: " + filepath
@@ -0,0 +1,68 @@
+import { describe, expect, test } from "bun:test"
+import * as fs from "fs/promises"
+import * as os from "os"
+import * as path from "path"
+import { App } from "../../src/app/app"
+import { Checkpoint } from "../../src/checkpoint"
+
+async function project(files: Record<string, string>) {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), "checkpoint-"))
+  for (const [file, content] of Object.entries(files)) {
+    await fs.mkdir(path.dirname(path.join(dir, file)), { recursive: true })
+    await fs.writeFile(path.join(dir, file), content)
+  }
+  return dir
+}
+
+async function read(dir: string, file: string) {
+  return fs.readFile(path.join(dir, file), "utf8").catch(() => null)
+}
+
+describe("checkpoint", () => {
+  test("lists checkpoints newest first with the files changed in between", async () => {
+    const dir = await project({ "a.txt": "a\n" })
+    await App.provide({ cwd: dir }, async () => {
+      const sessionID = "ses_" + path.basename(dir)
+      const first = await Checkpoint.create({ sessionID, messageID: "msg_1", description: "first" })
+      await fs.writeFile(path.join(dir, "b.txt"), "b\n")
+      const second = await Checkpoint.create({ sessionID, messageID: "msg_2", description: "second" })
+
+      const list = await Checkpoint.list(sessionID)
+      expect(list.map((x) => x.id)).toEqual([second.id, first.id])
+      expect(first.files).toEqual([])
+      expect(second.files).toEqual(["b.txt"])
+      expect((await Checkpoint.get(first.id)).description).toBe("first")
+    })
+  })
+
+  test("diffs and restores the working tree", async () => {
+    const dir = await project({ "a.txt": "a\n", "nested/b.txt": "b\n" })
+    await App.provide({ cwd: dir }, async () => {
+      const sessionID = "ses_" + path.basename(dir)
+      const checkpoint = await Checkpoint.create({ sessionID, messageID: "msg_1", description: "" })
+      await fs.writeFile(path.join(dir, "a.txt"), "a2\nmore\n")
+      await fs.rm(path.join(dir, "nested/b.txt"))
+      await fs.writeFile(path.join(dir, "new.txt"), "new\n")
+
+      const diff = await Checkpoint.diff(checkpoint.id)
+      const byPath = Object.fromEntries(diff.map((x) => [x.path, x]))
+      expect(Object.keys(byPath).sort()).toEqual(["a.txt", "nested/b.txt", "new.txt"])
+      expect(byPath["a.txt"]).toMatchObject({ additions: 1, deletions: 2 })
+      expect(byPath["a.txt"].diff).toContain("-more")
+      expect(byPath["nested/b.txt"]).toMatchObject({ additions: 1, deletions: 0 })
+      expect(byPath["new.txt"]).toMatchObject({ additions: 0, deletions: 1 })
+
+      await Checkpoint.restore(checkpoint.id)
+    })
+    expect(await read(dir, "a.txt")).toBe("a\n")
+    expect(await read(dir, "nested/b.txt")).toBe("b\n")
+    expect(await read(dir, "new.txt")).toBeNull()
+  })
+
+  test("fails for an unknown checkpoint", async () => {
+    const dir = await project({})
+    await App.provide({ cwd: dir }, async () => {
+      await expect(Checkpoint.restore("cpt_missing")).rejects.toThrow("not found")
+    })
+  })
+})
@@ -0,0 +1,93 @@
+import { describe, expect, test } from "bun:test"
+import { CheckpointPolicy } from "../../src/checkpoint/policy"
+
+function call(tool: string, args: any, messageID = "msg_1", now = 0) {
+  return { messageID, tool, args, now }
+}
+
+describe("checkpoint.policy", () => {
+  const opts = CheckpointPolicy.options({
+    enabled: true,
+    file_threshold: 3,
+    cooldown_seconds: 60,
+  })
+
+  test("flags destructive bash commands", () => {
+    const cases: [string, boolean][] = [
+      ["rm -rf build", true],
+      ["cd src && mv a.ts b.ts", true],
+      ["git reset --hard HEAD~1", true],
+      ["git clean -fd", true],
+      ["ls -la", false],
+      ["npm run format", false],
+      ["git status", false],
+    ]
+    for (const [command, flagged] of cases) {
+      const reason = CheckpointPolicy.evaluate(
+        opts,
+        CheckpointPolicy.tracker(),
+        call("bash", { command }),
+      )
+      expect(reason !== undefined).toBe(flagged)
+    }
+  })
+
+  test("shortens long commands in the reason", () => {
+    const command = "rm " + "very/long/path/".repeat(5)
+    const reason = CheckpointPolicy.evaluate(
+      opts,
+      CheckpointPolicy.tracker(),
+      call("bash", { command }),
+    )
+    expect(reason).toBe("before `" + command.slice(0, 37) + "...`")
+  })
+
+  test("flags the edit that reaches the file threshold once per message", () => {
+    const tracker = CheckpointPolicy.tracker()
+    const edit = (filePath: string, messageID = "msg_1") =>
+      CheckpointPolicy.evaluate(opts, tracker, call("edit", { filePath }, messageID, 1_000_000))
+    expect(edit("a.ts")).toBeUndefined()
+    expect(edit("a.ts")).toBeUndefined()
+    expect(edit("b.ts")).toBeUndefined()
+    expect(edit("c.ts")).toBe("before editing 3 files")
+    expect(edit("d.ts")).toBeUndefined()
+    // files are counted per message
+    expect(edit("a.ts", "msg_2")).toBeUndefined()
+  })
+
+  test("waits for the cooldown between checkpoints", () => {
+    const tracker = CheckpointPolicy.tracker()
+    const rm = (now: number) =>
+      CheckpointPolicy.evaluate(opts, tracker, call("bash", { command: "rm x" }, "msg_1", now))
+    expect(rm(1_000)).toBeDefined()
+    expect(rm(30_000)).toBeUndefined()
+    expect(rm(61_000)).toBeDefined()
+  })
+
+  test("does nothing when disabled or for other tools", () => {
+    const disabled = CheckpointPolicy.options({
+      enabled: false,
+      file_threshold: 1,
+      cooldown_seconds: 0,
+    })
+    expect(
+      CheckpointPolicy.evaluate(disabled, CheckpointPolicy.tracker(), call("bash", { command: "rm x" })),
+    ).toBeUndefined()
+    expect(
+      CheckpointPolicy.evaluate(opts, CheckpointPolicy.tracker(), call("read", { filePath: "a.ts" })),
+    ).toBeUndefined()
+  })
+
+  test("skips invalid patterns and falls back to the defaults", () => {
+    const custom = CheckpointPolicy.options({
+      enabled: true,
+      bash_patterns: ["(", "^make clean"],
+      file_threshold: 5,
+      cooldown_seconds: 60,
+    })
+    expect(custom.bashPatterns.map((re) => re.source)).toEqual(["^make clean"])
+    expect(CheckpointPolicy.options(undefined).bashPatterns).toHaveLength(
+      CheckpointPolicy.DEFAULT_BASH_PATTERNS.length,
+    )
+  })
+})
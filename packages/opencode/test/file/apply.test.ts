@@ -0,0 +1,68 @@
+import { describe, expect, test } from "bun:test"
+import * as fs from "fs/promises"
+import * as os from "os"
+import * as path from "path"
+import { App } from "../../src/app/app"
+import { FileApply } from "../../src/file/apply"
+
+async function project(files: Record<string, string>) {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), "apply-"))
+  for (const [file, content] of Object.entries(files)) {
+    await fs.mkdir(path.dirname(path.join(dir, file)), { recursive: true })
+    await fs.writeFile(path.join(dir, file), content)
+  }
+  return dir
+}
+
+async function read(dir: string, file: string) {
+  return fs.readFile(path.join(dir, file), "utf8").catch(() => null)
+}
+
+describe("file.apply", () => {
+  test("writes, creates and deletes files", async () => {
+    const dir = await project({ "a.txt": "a\n", "b.txt": "b\n" })
+    await App.provide({ cwd: dir }, async () => {
+      const files = await FileApply.atomic("test", [
+        { path: "a.txt", content: "a2\n" },
+        { path: "b.txt", content: null },
+        { path: "nested/c.txt", content: "c\n" },
+      ])
+      expect(files).toEqual([
+        path.join(dir, "a.txt"),
+        path.join(dir, "b.txt"),
+        path.join(dir, "nested/c.txt"),
+      ])
+    })
+    expect(await read(dir, "a.txt")).toBe("a2\n")
+    expect(await read(dir, "b.txt")).toBeNull()
+    expect(await read(dir, "nested/c.txt")).toBe("c\n")
+  })
+
+  test("restores written files when a later change fails", async () => {
+    const dir = await project({ "a.txt": "a\n", "b.txt": "b\n" })
+    await App.provide({ cwd: dir }, async () => {
+      // b.txt is a file, so nothing can be written below it
+      const result = FileApply.atomic("test", [
+        { path: "a.txt", content: "a2\n" },
+        { path: "new.txt", content: "new\n" },
+        { path: "b.txt/c.txt", content: "c\n" },
+      ])
+      await expect(result).rejects.toThrow()
+    })
+    expect(await read(dir, "a.txt")).toBe("a\n")
+    expect(await read(dir, "new.txt")).toBeNull()
+    expect(await read(dir, "b.txt")).toBe("b\n")
+  })
+
+  test("rejects files outside the project before writing", async () => {
+    const dir = await project({ "a.txt": "a\n" })
+    await App.provide({ cwd: dir }, async () => {
+      const result = FileApply.atomic("test", [
+        { path: "a.txt", content: "a2\n" },
+        { path: "../outside.txt", content: "x\n" },
+      ])
+      await expect(result).rejects.toThrow("outside the project")
+    })
+    expect(await read(dir, "a.txt")).toBe("a\n")
+  })
+})
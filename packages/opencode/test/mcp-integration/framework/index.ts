@@ -20,6 +20,7 @@ export namespace MCPTestFramework {
   export interface TestContext {
     sessionID: string
     messageID: string
+    callID: string
     abort: AbortSignal
     metadata: () => void
   }
@@ -119,6 +120,7 @@ export namespace MCPTestFramework {
       return {
         sessionID,
         messageID: `msg-${Date.now()}`,
+        callID: `call-${Date.now()}`,
         abort: AbortSignal.any([]),
         metadata: () => {},
       }
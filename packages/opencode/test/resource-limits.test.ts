@@ -312,6 +312,7 @@ const testConfig: TestConfig = {
 const ctx = {
   sessionID: "resource-test",
   messageID: "",
+  callID: "",
   abort: AbortSignal.any([]),
   metadata: () => {},
 }
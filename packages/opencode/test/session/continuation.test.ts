@@ -0,0 +1,82 @@
+import { describe, expect, test } from "bun:test"
+import type { Message } from "../../src/session/message"
+import { SessionContinuation } from "../../src/session/continuation"
+import { continuationPromptGenerator } from "../../src/session/continuation-prompt-generator"
+
+function message(role: "user" | "assistant", parts: Message.MessagePart[]): Message.Info {
+  return {
+    id: "msg_" + Math.random().toString(36).slice(2),
+    role,
+    parts,
+    metadata: { time: { created: 0 }, sessionID: "ses_test", tool: {} },
+  }
+}
+
+function call(toolName: string, args: any): Message.MessagePart {
+  return {
+    type: "tool-invocation",
+    toolInvocation: { state: "result", toolCallId: toolName + "_1", toolName, args, result: "" },
+  }
+}
+
+const todos = (statuses: string[]) =>
+  statuses.map((status, i) => ({
+    id: String(i),
+    content: `task ${i}`,
+    status,
+    priority: "medium",
+  }))
+
+describe("session.continuation", () => {
+  const base = {
+    title: "Add dark mode",
+    root: "/work/dgmo",
+    cwd: "/work/dgmo/packages/tui",
+    request: {},
+  }
+
+  test("reads progress and remaining work from the latest todo list", () => {
+    const state = SessionContinuation.state({
+      ...base,
+      messages: [
+        message("user", [{ type: "text", text: "add dark mode" }]),
+        message("assistant", [
+          call("todowrite", { todos: todos(["pending", "pending", "pending"]) }),
+          call("edit", { filePath: "/work/dgmo/src/theme.ts" }),
+          call("write", { filePath: "/work/dgmo/src/theme.ts" }),
+          call("todowrite", { todos: todos(["completed", "in_progress", "pending", "completed"]) }),
+        ]),
+      ],
+    })
+
+    expect(state.projectName).toBe("dgmo")
+    expect(state.projectGoal).toBe("Add dark mode")
+    expect(state.workingDirectory).toBe("/work/dgmo/packages/tui")
+    expect(state.completionPercentage).toBe(50)
+    expect(state.completedComponents.map((x) => x.name)).toEqual(["task 0", "task 3"])
+    expect(state.remainingTasks.map((x) => x.name)).toEqual(["task 1", "task 2"])
+    expect(state.criticalFiles.map((x) => x.path)).toEqual(["src/theme.ts"])
+    // the generator validates the state it is given
+    expect(continuationPromptGenerator.generateContinuationPrompt(state)).toContain("task 1")
+  })
+
+  test("prefers the requested goal and falls back to the first prompt", () => {
+    const messages = [message("user", [{ type: "text", text: "  fix the login bug  " }])]
+    const requested = SessionContinuation.state({
+      ...base,
+      messages,
+      request: { goal: "ship 1.0", workingDirectory: "/tmp/next" },
+    })
+    expect(requested.projectGoal).toBe("ship 1.0")
+    expect(requested.workingDirectory).toBe("/tmp/next")
+
+    const untitled = SessionContinuation.state({
+      ...base,
+      messages,
+      title: "New Session - 2026-01-01T00:00:00.000Z",
+    })
+    expect(untitled.projectGoal).toBe("fix the login bug")
+    expect(untitled.completionPercentage).toBe(0)
+    expect(untitled.remainingTasks).toEqual([])
+  })
+})
@@ -6,6 +6,7 @@ import { ListTool } from "../../src/tool/ls"
 const ctx = {
   sessionID: "test",
   messageID: "",
+  callID: "",
   abort: AbortSignal.any([]),
   metadata: () => {},
 }
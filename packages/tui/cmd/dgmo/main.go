@@ -3,15 +3,20 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/logging"
+	"github.com/sst/dgmo/internal/styles"
 	"github.com/sst/dgmo/internal/tui"
 	"github.com/sst/opencode-sdk-go"
 	"github.com/sst/opencode-sdk-go/option"
@@ -19,6 +24,9 @@ import (
 
 var Version = "dev"
 
+// defaultServerURL is where `opencode serve` listens unless told otherwise
+const defaultServerURL = "http://127.0.0.1:4096"
+
 func main() {
 	version := Version
 	if version != "dev" && !strings.HasPrefix(Version, "v") {
@@ -29,59 +37,78 @@ func main() {
 	nullLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	slog.SetDefault(nullLogger)
 
-	url := os.Getenv("DGMO_SERVER")
+	url := serverArg(os.Args[1:])
+	if url == "" {
+		url = os.Getenv("DGMO_SERVER")
+	}
 
 	appInfoStr := os.Getenv("DGMO_APP_INFO")
-	var appInfo opencode.App
-	err := json.Unmarshal([]byte(appInfoStr), &appInfo)
+	if appInfoStr == "" && url == "" {
+		url = defaultServerURL
+	}
+
+	httpClient := opencode.NewClient(
+		option.WithBaseURL(url),
+	)
+
+	appInfo, err := loadAppInfo(appInfoStr, httpClient)
 	if err != nil {
-		slog.Error("Failed to unmarshal app info", "error", err)
+		// logging is not set up yet, the user needs to see why we stopped
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 
-	logfile := filepath.Join(appInfo.Path.Data, "log", "tui.log")
-	if _, err := os.Stat(filepath.Dir(logfile)); os.IsNotExist(err) {
-		err := os.MkdirAll(filepath.Dir(logfile), 0755)
-		if err != nil {
-			slog.Error("Failed to create log directory", "error", err)
-			os.Exit(1)
-		}
-	}
-	file, err := os.Create(logfile)
+	file, err := logging.NewRotatingFile(logging.Path(appInfo.Path.Data))
 	if err != nil {
-		slog.Error("Failed to create log file", "error", err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 	defer file.Close()
-	logger := slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	level := logging.ParseLevel(os.Getenv(logging.LevelEnv))
+	logger := slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: level}))
 	slog.SetDefault(logger)
 
 	slog.Debug("TUI launched", "app", appInfo)
 
-	httpClient := opencode.NewClient(
-		option.WithBaseURL(url),
-	)
-
-	if err != nil {
-		slog.Error("Failed to create client", "error", err)
-		os.Exit(1)
-	}
-
 	// Create main context for the application
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if accessibleArg(os.Args[1:]) {
+		styles.SetAccessible(true)
+	}
+
 	app_, err := app.New(ctx, version, appInfo, httpClient)
 	if err != nil {
 		panic(err)
 	}
+	if target, ok := app.ParseViewArgs(os.Args[1:]); ok {
+		app_.ViewTarget = target
+		slog.Info("Viewing session read-only", "target", target)
+	}
 
 	program := tea.NewProgram(
 		tui.NewModel(app_),
 		tea.WithKeyboardEnhancements(),
 		tea.WithMouseCellMotion(),
+		tea.WithoutSignalHandler(),
 	)
 
+	// Signals take the same exit path as the quit command so the draft and
+	// state are saved. A second signal, or a stuck exit, kills the program.
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	go func() {
+		sig := <-signals
+		program.Send(app.ShutdownMsg{Signal: sig})
+		select {
+		case <-signals:
+		case <-time.After(5 * time.Second):
+		}
+		program.Kill()
+	}()
+
 	// Initialize task client with event handlers
 	taskClient := app.NewTaskClient(app.TaskEventHandlers{
 		OnTaskStarted: func(task app.TaskInfo) {
@@ -112,7 +139,9 @@ func main() {
 	})
 
 	// Connect to task event server
-	if err := taskClient.Connect(); err != nil {
+	if err := app_.Features.Require(app.FeatureTasks); err != nil {
+		slog.Info("Task progress unavailable", "reason", err)
+	} else if err := taskClient.Connect(); err != nil {
 		slog.Warn("Failed to connect to task event server", "error", err)
 		// Don't fail, just continue without task progress
 	} else {
@@ -140,3 +169,42 @@ func main() {
 
 	slog.Info("TUI exited", "result", result)
 }
+
+// serverArg returns the value of --server. The launcher forwards its own
+// arguments to the TUI, so anything else is ignored rather than rejected.
+func serverArg(args []string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--server="); ok {
+			return value
+		}
+		if arg == "--server" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// accessibleArg reports whether --accessible was passed
+func accessibleArg(args []string) bool {
+	return slices.Contains(args, "--accessible")
+}
+
+// loadAppInfo decodes the app info handed over by the launcher. When the TUI
+// is started directly there is none, so it is fetched from the server instead.
+func loadAppInfo(appInfoStr string, client *opencode.Client) (opencode.App, error) {
+	var appInfo opencode.App
+	if appInfoStr != "" {
+		if err := json.Unmarshal([]byte(appInfoStr), &appInfo); err != nil {
+			return appInfo, fmt.Errorf("failed to unmarshal app info: %w", err)
+		}
+		return appInfo, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	info, err := client.App.Get(ctx)
+	if err != nil {
+		return appInfo, fmt.Errorf("failed to reach the opencode server, start one with `opencode serve` or pass --server: %w", err)
+	}
+	return *info, nil
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// SessionActivity follows the server events to know which sessions have an
+// assistant response in progress, including sessions that are not open, how
+// many messages arrived in them since they were last open and which ones
+// wait for a reply
+type SessionActivity struct {
+	previews    map[string]string              // partial response by generating session
+	unread      map[string]map[string]struct{} // unseen message ids by session
+	lastActive  map[string]time.Time           // last event by session
+	idle        map[string]time.Time           // since when a session waits for a reply
+	reminded    map[string]bool                // idle sessions already reminded of
+	subSessions map[string]bool                // agent sessions, which wait on no one
+	finished    []string                       // sessions done responding since TakeFinished
+}
+
+// NewSessionActivity creates an empty activity tracker
+func NewSessionActivity() *SessionActivity {
+	return &SessionActivity{
+		previews:    make(map[string]string),
+		unread:      make(map[string]map[string]struct{}),
+		lastActive:  make(map[string]time.Time),
+		idle:        make(map[string]time.Time),
+		reminded:    make(map[string]bool),
+		subSessions: make(map[string]bool),
+	}
+}
+
+// Observe updates the tracker from a server event, other messages are ignored.
+// Messages of the current session are on screen, so they are never unread.
+func (s *SessionActivity) Observe(msg tea.Msg, currentSessionID string) {
+	switch msg := msg.(type) {
+	case opencode.EventListResponseEventMessageUpdated:
+		message := msg.Properties.Info
+		s.touch(message.Metadata.SessionID, message.ID, currentSessionID)
+		if message.Role != opencode.MessageRoleAssistant || message.Metadata.Time.Completed == 0 {
+			// a reply or a new response, the session is no longer waiting
+			s.clearIdle(message.Metadata.SessionID)
+		}
+		if message.Role != opencode.MessageRoleAssistant {
+			return
+		}
+		if message.Metadata.Time.Completed != 0 {
+			delete(s.previews, message.Metadata.SessionID)
+			return
+		}
+		preview := s.previews[message.Metadata.SessionID]
+		for _, part := range message.Parts {
+			if line := previewLine(part); line != "" {
+				preview = line
+			}
+		}
+		s.previews[message.Metadata.SessionID] = preview
+	case opencode.EventListResponseEventMessagePartUpdated:
+		s.touch(msg.Properties.SessionID, msg.Properties.MessageID, currentSessionID)
+		preview, ok := s.previews[msg.Properties.SessionID]
+		if !ok {
+			return
+		}
+		if line := previewLine(msg.Properties.Part); line != "" {
+			preview = line
+		}
+		s.previews[msg.Properties.SessionID] = preview
+	case opencode.EventListResponseEventSessionIdle:
+		delete(s.previews, msg.Properties.SessionID)
+		if !s.subSessions[msg.Properties.SessionID] {
+			s.finished = append(s.finished, msg.Properties.SessionID)
+		}
+		if _, ok := s.idle[msg.Properties.SessionID]; !ok {
+			s.idle[msg.Properties.SessionID] = time.Now()
+		}
+	case opencode.EventListResponseEventSessionUpdated:
+		if msg.Properties.Info.ParentID != "" {
+			s.subSessions[msg.Properties.Info.ID] = true
+		}
+	case opencode.EventListResponseEventSessionDeleted:
+		delete(s.previews, msg.Properties.Info.ID)
+		delete(s.unread, msg.Properties.Info.ID)
+		delete(s.lastActive, msg.Properties.Info.ID)
+		delete(s.subSessions, msg.Properties.Info.ID)
+		s.clearIdle(msg.Properties.Info.ID)
+	}
+}
+
+func (s *SessionActivity) clearIdle(sessionID string) {
+	delete(s.idle, sessionID)
+	delete(s.reminded, sessionID)
+}
+
+// IdleSince returns since when the assistant of a session has been done and
+// waiting for a reply. Agent sub-sessions never wait.
+func (s *SessionActivity) IdleSince(sessionID string) (time.Time, bool) {
+	since, ok := s.idle[sessionID]
+	if !ok || s.subSessions[sessionID] {
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+// TakeFinished returns the sessions whose assistant finished responding
+// since the last call. Agent sub-sessions are left out, their task reports
+// when it is done.
+func (s *SessionActivity) TakeFinished() []string {
+	finished := s.finished
+	s.finished = nil
+	return finished
+}
+
+// DueReminders returns the sessions that have been waiting for a reply for
+// longer than after and were not reminded of yet, and marks them reminded.
+// A session is reminded of once per wait.
+func (s *SessionActivity) DueReminders(after time.Duration, now time.Time) []string {
+	var due []string
+	for id := range s.idle {
+		since, ok := s.IdleSince(id)
+		if !ok || s.reminded[id] || now.Sub(since) < after {
+			continue
+		}
+		s.reminded[id] = true
+		due = append(due, id)
+	}
+	slices.Sort(due)
+	return due
+}
+
+// touch records a message event. A message is counted once however many
+// times it is updated while it streams.
+func (s *SessionActivity) touch(sessionID, messageID, currentSessionID string) {
+	if sessionID == "" {
+		return
+	}
+	s.lastActive[sessionID] = time.Now()
+	if sessionID == currentSessionID {
+		return
+	}
+	if s.unread[sessionID] == nil {
+		s.unread[sessionID] = make(map[string]struct{})
+	}
+	s.unread[sessionID][messageID] = struct{}{}
+}
+
+// Unread returns how many messages arrived in a session while it was not open
+func (s *SessionActivity) Unread(sessionID string) int {
+	return len(s.unread[sessionID])
+}
+
+// MarkRead clears the unread messages of a session once it is opened
+func (s *SessionActivity) MarkRead(sessionID string) {
+	delete(s.unread, sessionID)
+}
+
+// MostRecent returns the session with the latest activity, other than
+// exclude, preferring sessions with unread messages
+func (s *SessionActivity) MostRecent(exclude string) (string, bool) {
+	var best string
+	var bestTime time.Time
+	bestUnread := false
+	for id, at := range s.lastActive {
+		if id == exclude {
+			continue
+		}
+		unread := s.Unread(id) > 0
+		if best == "" || (unread && !bestUnread) || (unread == bestUnread && at.After(bestTime)) {
+			best, bestTime, bestUnread = id, at, unread
+		}
+	}
+	return best, best != ""
+}
+
+// Generating reports whether the assistant is responding in a session and
+// returns the last line of its partial response
+func (s *SessionActivity) Generating(sessionID string) (string, bool) {
+	preview, ok := s.previews[sessionID]
+	return preview, ok
+}
+
+// previewLine returns the last non-empty line of a text part, or the name of
+// the tool for tool calls
+func previewLine(part opencode.MessagePart) string {
+	switch part := part.AsUnion().(type) {
+	case opencode.TextPart:
+		lines := strings.Split(strings.TrimSpace(part.Text), "\n")
+		return strings.TrimSpace(lines[len(lines)-1])
+	case opencode.ToolInvocationPart:
+		return "→ " + part.ToolInvocation.ToolName
+	}
+	return ""
+}
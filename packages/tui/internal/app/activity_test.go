@@ -0,0 +1,66 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func decodeEvent[T any](t *testing.T, data string) T {
+	t.Helper()
+	var event T
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestIdleReminders(t *testing.T) {
+	activity := NewSessionActivity()
+	activity.Observe(decodeEvent[opencode.EventListResponseEventSessionUpdated](t,
+		`{"type":"session.updated","properties":{"info":{"id":"ses_agent","parentID":"ses_1"}}}`), "")
+	for _, id := range []string{"ses_1", "ses_2", "ses_agent"} {
+		activity.Observe(decodeEvent[opencode.EventListResponseEventSessionIdle](t,
+			`{"type":"session.idle","properties":{"sessionID":"`+id+`"}}`), "")
+	}
+	if _, ok := activity.IdleSince("ses_agent"); ok {
+		t.Error("an agent sub-session is waiting for a reply")
+	}
+
+	// a reply ends the wait
+	activity.Observe(decodeEvent[opencode.EventListResponseEventMessageUpdated](t,
+		`{"type":"message.updated","properties":{"info":{"id":"msg_1","role":"user","parts":[],`+
+			`"metadata":{"sessionID":"ses_2","time":{"created":1},"tool":{}}}}}`), "")
+	if _, ok := activity.IdleSince("ses_2"); ok {
+		t.Error("ses_2 is waiting after a reply")
+	}
+
+	now := time.Now()
+	if due := activity.DueReminders(time.Minute, now); len(due) != 0 {
+		t.Errorf("reminders before the wait is over: %v", due)
+	}
+	if due := activity.DueReminders(time.Minute, now.Add(time.Minute)); len(due) != 1 || due[0] != "ses_1" {
+		t.Errorf("DueReminders = %v, want ses_1", due)
+	}
+	if due := activity.DueReminders(time.Minute, now.Add(time.Hour)); len(due) != 0 {
+		t.Errorf("reminded again of the same wait: %v", due)
+	}
+}
+
+func TestTakeFinished(t *testing.T) {
+	activity := NewSessionActivity()
+	activity.Observe(decodeEvent[opencode.EventListResponseEventSessionUpdated](t,
+		`{"type":"session.updated","properties":{"info":{"id":"ses_agent","parentID":"ses_1"}}}`), "")
+	for _, id := range []string{"ses_1", "ses_agent"} {
+		activity.Observe(decodeEvent[opencode.EventListResponseEventSessionIdle](t,
+			`{"type":"session.idle","properties":{"sessionID":"`+id+`"}}`), "")
+	}
+	if finished := activity.TakeFinished(); len(finished) != 1 || finished[0] != "ses_1" {
+		t.Errorf("TakeFinished = %v, want ses_1", finished)
+	}
+	if finished := activity.TakeFinished(); len(finished) != 0 {
+		t.Errorf("finished sessions reported twice: %v", finished)
+	}
+}
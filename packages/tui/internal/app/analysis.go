@@ -0,0 +1,243 @@
+package app
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ProjectAnalyzedMsg carries what a quick look at the project found, to
+// be reviewed before the server writes AGENTS.md
+type ProjectAnalyzedMsg struct {
+	Analysis ProjectAnalysis
+}
+
+// ProjectInitConfirmedMsg asks to initialize the project with the
+// analysis as the user corrected it
+type ProjectInitConfirmedMsg struct {
+	Analysis string
+}
+
+// ProjectAnalysis is what the files of a project tell about it
+type ProjectAnalysis struct {
+	// Languages are ordered by how many files use them
+	Languages []LanguageCount
+	// Tools are the build tools and package managers found
+	Tools    []string
+	Commands []ProjectCommand
+}
+
+// LanguageCount is how many source files of a language a project has
+type LanguageCount struct {
+	Language string
+	Files    int
+}
+
+// ProjectCommand is a command that builds, tests or lints the project
+type ProjectCommand struct {
+	Kind    string // build, test or lint
+	Command string
+}
+
+// analysisMaxFiles caps the files counted, so huge trees stay quick
+const analysisMaxFiles = 20000
+
+// analysisSkipDirs are not counted, they hold dependencies or output
+var analysisSkipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "dist": true, "build": true,
+	"target": true, "out": true, "__pycache__": true, ".venv": true, "venv": true,
+}
+
+var languageExtensions = map[string]string{
+	".go": "Go", ".ts": "TypeScript", ".tsx": "TypeScript", ".js": "JavaScript",
+	".jsx": "JavaScript", ".mjs": "JavaScript", ".py": "Python", ".rs": "Rust",
+	".java": "Java", ".kt": "Kotlin", ".rb": "Ruby", ".php": "PHP", ".cs": "C#",
+	".c": "C", ".h": "C", ".cpp": "C++", ".cc": "C++", ".hpp": "C++",
+	".swift": "Swift", ".scala": "Scala", ".ex": "Elixir", ".exs": "Elixir",
+	".zig": "Zig", ".lua": "Lua", ".dart": "Dart", ".vue": "Vue", ".svelte": "Svelte",
+}
+
+var makeTarget = regexp.MustCompile(`(?m)^([A-Za-z][A-Za-z0-9_.-]*)\s*:([^=]|$)`)
+
+// AnalyzeProject looks at the files under root for the languages, build
+// tools and commands of a project. It reads manifests only, nothing runs.
+func AnalyzeProject(root string) (ProjectAnalysis, error) {
+	var analysis ProjectAnalysis
+	counts := make(map[string]int)
+	seen := 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != root && (strings.HasPrefix(name, ".") || analysisSkipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if seen++; seen > analysisMaxFiles {
+			return filepath.SkipAll
+		}
+		if language, ok := languageExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+			counts[language]++
+		}
+		return nil
+	})
+	if err != nil {
+		return analysis, fmt.Errorf("failed to analyze project: %w", err)
+	}
+	for language, files := range counts {
+		analysis.Languages = append(analysis.Languages, LanguageCount{Language: language, Files: files})
+	}
+	slices.SortFunc(analysis.Languages, func(x, y LanguageCount) int {
+		return cmp.Or(cmp.Compare(y.Files, x.Files), cmp.Compare(x.Language, y.Language))
+	})
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(root, name))
+		return err == nil
+	}
+	add := func(tool string, commands ...ProjectCommand) {
+		analysis.Tools = append(analysis.Tools, tool)
+		analysis.Commands = append(analysis.Commands, commands...)
+	}
+
+	if exists("go.mod") {
+		add("go modules",
+			ProjectCommand{"build", "go build ./..."},
+			ProjectCommand{"test", "go test ./..."},
+			ProjectCommand{"test", "go test ./path/to/pkg -run TestName"},
+			ProjectCommand{"lint", "go vet ./..."},
+		)
+	}
+	if exists("package.json") {
+		runner := "npm"
+		switch {
+		case exists("bun.lock") || exists("bun.lockb"):
+			runner = "bun"
+		case exists("pnpm-lock.yaml"):
+			runner = "pnpm"
+		case exists("yarn.lock"):
+			runner = "yarn"
+		}
+		add(runner, packageScripts(filepath.Join(root, "package.json"), runner)...)
+	}
+	if exists("Cargo.toml") {
+		add("cargo",
+			ProjectCommand{"build", "cargo build"},
+			ProjectCommand{"test", "cargo test"},
+			ProjectCommand{"test", "cargo test test_name"},
+			ProjectCommand{"lint", "cargo clippy"},
+		)
+	}
+	if exists("pyproject.toml") || exists("setup.py") || exists("requirements.txt") {
+		tool := "pip"
+		switch {
+		case exists("uv.lock"):
+			tool = "uv"
+		case exists("poetry.lock"):
+			tool = "poetry"
+		}
+		var commands []ProjectCommand
+		if exists("pytest.ini") || exists("conftest.py") || fileContains(filepath.Join(root, "pyproject.toml"), "pytest") {
+			commands = append(commands,
+				ProjectCommand{"test", "pytest"},
+				ProjectCommand{"test", "pytest path/to/test_file.py::test_name"},
+			)
+		}
+		add(tool, commands...)
+	}
+	if exists("pom.xml") {
+		add("maven", ProjectCommand{"build", "mvn package"}, ProjectCommand{"test", "mvn test"})
+	}
+	if exists("build.gradle") || exists("build.gradle.kts") {
+		gradle := "gradle"
+		if exists("gradlew") {
+			gradle = "./gradlew"
+		}
+		add("gradle", ProjectCommand{"build", gradle + " build"}, ProjectCommand{"test", gradle + " test"})
+	}
+	if exists("Gemfile") {
+		add("bundler")
+	}
+	if exists("CMakeLists.txt") {
+		add("cmake", ProjectCommand{"build", "cmake -B build && cmake --build build"})
+	}
+	if exists("Makefile") {
+		add("make", makeTargets(filepath.Join(root, "Makefile"))...)
+	}
+	return analysis, nil
+}
+
+// packageScripts returns the build, test and lint scripts of a
+// package.json, run with runner
+func packageScripts(path, runner string) []ProjectCommand {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var manifest struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if json.Unmarshal(data, &manifest) != nil {
+		return nil
+	}
+	var commands []ProjectCommand
+	for _, kind := range []string{"build", "test", "lint", "typecheck"} {
+		if _, ok := manifest.Scripts[kind]; ok {
+			commands = append(commands, ProjectCommand{Kind: strings.Replace(kind, "typecheck", "lint", 1), Command: runner + " run " + kind})
+		}
+	}
+	return commands
+}
+
+// makeTargets returns the build, test and lint targets of a Makefile
+func makeTargets(path string) []ProjectCommand {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	targets := make(map[string]bool)
+	for _, match := range makeTarget.FindAllStringSubmatch(string(data), -1) {
+		targets[match[1]] = true
+	}
+	var commands []ProjectCommand
+	for _, kind := range []string{"build", "test", "lint"} {
+		if targets[kind] {
+			commands = append(commands, ProjectCommand{Kind: kind, Command: "make " + kind})
+		}
+	}
+	return commands
+}
+
+func fileContains(path, text string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), text)
+}
+
+// Text lists the findings one per line, for the user to correct before
+// they are sent along with the init request
+func (p ProjectAnalysis) Text() string {
+	var lines []string
+	if len(p.Languages) > 0 {
+		languages := make([]string, 0, len(p.Languages))
+		for _, language := range p.Languages {
+			languages = append(languages, fmt.Sprintf("%s (%d files)", language.Language, language.Files))
+		}
+		lines = append(lines, "Languages: "+strings.Join(languages, ", "))
+	}
+	if len(p.Tools) > 0 {
+		lines = append(lines, "Build tools: "+strings.Join(p.Tools, ", "))
+	}
+	for _, command := range p.Commands {
+		lines = append(lines, fmt.Sprintf("%s: %s", strings.ToUpper(command.Kind[:1])+command.Kind[1:], command.Command))
+	}
+	return strings.Join(lines, "\n")
+}
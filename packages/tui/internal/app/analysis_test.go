@@ -0,0 +1,75 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeProject(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                     "module example.com/app\n",
+		"main.go":                    "package main\n",
+		"internal/server/server.go":  "package server\n",
+		"web/package.json":           `{"scripts": {"dev": "vite"}}`,
+		"web/src/app.ts":             "",
+		"node_modules/left-pad/a.js": "",
+		".git/hooks/pre-commit.py":   "",
+		"Makefile":                   ".PHONY: test\nVERSION := 1\ntest: build\n\tgo test ./...\nbuild:\n\tgo build\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysis, err := AnalyzeProject(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLanguages := []LanguageCount{{"Go", 2}, {"TypeScript", 1}}
+	if !slices.Equal(analysis.Languages, wantLanguages) {
+		t.Errorf("languages = %+v, want %+v", analysis.Languages, wantLanguages)
+	}
+	if want := []string{"go modules", "make"}; !slices.Equal(analysis.Tools, want) {
+		t.Errorf("tools = %v, want %v", analysis.Tools, want)
+	}
+	if !slices.Contains(analysis.Commands, ProjectCommand{"test", "make test"}) ||
+		!slices.Contains(analysis.Commands, ProjectCommand{"build", "make build"}) ||
+		slices.ContainsFunc(analysis.Commands, func(c ProjectCommand) bool { return c.Command == "make VERSION" }) {
+		t.Errorf("commands = %+v", analysis.Commands)
+	}
+
+	text := analysis.Text()
+	for _, line := range []string{"Languages: Go (2 files), TypeScript (1 files)", "Build tools: go modules, make", "Test: go test ./..."} {
+		if !strings.Contains(text, line) {
+			t.Errorf("text is missing %q:\n%s", line, text)
+		}
+	}
+}
+
+func TestPackageScripts(t *testing.T) {
+	root := t.TempDir()
+	manifest := `{"scripts": {"build": "tsc", "test": "bun test", "typecheck": "tsc --noEmit", "dev": "vite"}}`
+	if err := os.WriteFile(filepath.Join(root, "package.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "bun.lock"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	analysis, err := AnalyzeProject(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ProjectCommand{{"build", "bun run build"}, {"test", "bun run test"}, {"lint", "bun run typecheck"}}
+	if !slices.Equal(analysis.Commands, want) || !slices.Equal(analysis.Tools, []string{"bun"}) {
+		t.Errorf("tools %v, commands %+v, want %+v", analysis.Tools, analysis.Commands, want)
+	}
+}
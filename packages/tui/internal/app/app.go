@@ -2,8 +2,11 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -14,8 +17,14 @@ import (
 	"github.com/sst/dgmo/internal/commands"
 	"github.com/sst/dgmo/internal/components/toast"
 	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/dgmo/internal/git"
 	"github.com/sst/dgmo/internal/image"
+	"github.com/sst/dgmo/internal/index"
+	"github.com/sst/dgmo/internal/mirror"
+	"github.com/sst/dgmo/internal/plugin"
+	"github.com/sst/dgmo/internal/search"
 	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/styles/glyphs"
 	"github.com/sst/dgmo/internal/theme"
 	"github.com/sst/dgmo/internal/util"
 	"github.com/sst/opencode-sdk-go"
@@ -44,6 +53,57 @@ type App struct {
 
 	// Task tracking
 	TaskClient *TaskClient
+
+	Checkpoints *CheckpointService
+	// Continuation generates prompts for handing a session to a new agent
+	Continuation *ContinuationService
+	// Permissions holds tool calls waiting for approval
+	Permissions *PermissionService
+	// SubSessions lists the sessions of the agents a session spawned
+	SubSessions *SubSessionService
+	// Features tracks which optional subsystems the server supports
+	Features *FeatureFlags
+	// MCPStats tracks the latency and errors of MCP servers
+	MCPStats *MCPStats
+	// ToolTimers tracks how long tool calls have been running
+	ToolTimers *ToolTimers
+	// Activity knows which sessions have a response in progress
+	Activity *SessionActivity
+	// ImagePreviews renders the image links of assistant messages inline
+	ImagePreviews *ImagePreviews
+	// Search indexes messages, prompts and artifacts for local search
+	Search search.Index
+	// Files indexes the files of the workspace for completions and the file
+	// picker
+	Files *index.Index
+	// Mirror keeps a local copy of every received message for recovery
+	Mirror *mirror.Mirror
+	// Git is the last read git status of the project, nil outside a repository
+	Git *git.Status
+	// Bundle is the imported session bundle being viewed, nil otherwise
+	Bundle *SessionBundle
+	// ViewTarget is the session ID or share URL given to `dgmo view`, which
+	// shows it read-only. Empty in the normal mode.
+	ViewTarget string
+	// History holds every prompt sent, from any session, for history search
+	History *PromptHistory
+	// Snippets are the text snippets the editor expands
+	Snippets *Snippets
+	// Pending holds deletes and discards until their undo window closed
+	Pending *PendingOps
+	// Broadcast is the latest prompt sent to several sessions at once, nil
+	// until one is sent or loaded for comparison
+	Broadcast *Broadcast
+	// PromptBlocks is the stack of the prompt builder, kept until it is sent
+	PromptBlocks []PromptBlock
+	// Plugins are the executables in the plugins directory that extend the
+	// commands, panels and completions
+	Plugins       []*plugin.Plugin
+	pluginActions map[commands.CommandName]PluginAction
+	// Project is the per-project config overlay, nil if there is none
+	Project        *config.ProjectConfig
+	projectModTime time.Time
+	userLeader     string
 }
 
 type SessionSelectedMsg = *opencode.Session
@@ -56,6 +116,10 @@ type CompactSessionMsg struct{}
 type SendMsg struct {
 	Text        string
 	Attachments []Attachment
+	// Confirmed skips the large prompt check, the prompt was reviewed
+	Confirmed bool
+	// CompactPinned sends less of each pinned file
+	CompactPinned bool
 }
 type CompletionDialogTriggeredMsg struct {
 	InitialValue string
@@ -73,6 +137,18 @@ type SessionSwitchedMsg struct {
 
 type NavigateBackMsg struct{}
 
+// ShutdownMsg is sent when the process is asked to terminate by a signal
+type ShutdownMsg struct {
+	Signal os.Signal
+}
+
+// SessionsUpdatedMsg carries the sessions that changed since the last batch,
+// sent at most once per SessionRefreshInterval
+type SessionsUpdatedMsg []opencode.Session
+
+// SessionRefreshInterval is how often batched session updates reach lists
+const SessionRefreshInterval = 500 * time.Millisecond
+
 type NavigateToSiblingMsg struct {
 	Direction string // "next" or "prev"
 }
@@ -129,9 +205,33 @@ func New(
 		}
 		theme.SetTheme(appState.Theme)
 	}
+	if appState.Accessible {
+		styles.SetAccessible(true)
+	}
+	glyphs.SetSupport(glyphs.ParseSupport(appState.Glyphs))
+	styles.SetMarkdownOptions(styles.MarkdownOptions{
+		Emoji:       appState.Markdown.Emoji && !styles.Accessible() && glyphs.CurrentSupport() != glyphs.ASCII,
+		LineNumbers: appState.Markdown.LineNumbers,
+		Headings:    appState.Markdown.Headings,
+		Links:       appState.Markdown.Links,
+	})
+	styles.SetHyperlinks(styles.HyperlinkOptions{
+		Enabled: hyperlinksEnabled(appState.Hyperlinks),
+		Scheme:  appState.HyperlinkScheme,
+		Root:    appInfo.Path.Cwd,
+	})
+	// parse the remaining themes off the startup path so previews are instant
+	go theme.Preload()
 
 	slog.Debug("Loaded config", "config", configInfo)
 
+	features := NewFeatureFlags(appState.Features)
+	probeCtx, cancelProbe := context.WithTimeout(ctx, 2*time.Second)
+	if err := features.Probe(probeCtx, httpClient); err != nil {
+		slog.Warn("Failed to probe server capabilities", "error", err)
+	}
+	cancelProbe()
+
 	app := &App{
 		Info:      appInfo,
 		Version:   version,
@@ -140,6 +240,32 @@ func New(
 		Client:    httpClient,
 		State:     appState,
 		Commands:  commands.LoadFromConfig(configInfo),
+
+		userLeader: configInfo.Keybinds.Leader,
+
+		Features:   features,
+		MCPStats:   NewMCPStats(configInfo),
+		ToolTimers: NewToolTimers(),
+		Activity:   NewSessionActivity(),
+		ImagePreviews: NewImagePreviews(
+			appState.ImagePreviews,
+			appState.ImagePreviewDomains,
+		),
+		Checkpoints:  NewCheckpointService(httpClient, features),
+		Continuation: NewContinuationService(httpClient, features),
+		Permissions:  NewPermissionService(httpClient, features),
+		SubSessions:  NewSubSessionService(httpClient),
+		Search:       openSearch(ctx, appInfo.Path.Data),
+		Files:        index.Open(appInfo.Path.Root),
+		Mirror:       mirror.Open(filepath.Join(appInfo.Path.Data, "mirror")),
+		History:      NewPromptHistory(filepath.Join(appInfo.Path.State, "prompt-history.jsonl")),
+		Snippets:     NewSnippets(filepath.Join(appInfo.Path.Config, "snippets.toml")),
+		Pending:      NewPendingOps(),
+	}
+
+	app.Permissions.SetRestricted(app.Restricted())
+	if err := app.LoadProjectConfig(); err != nil {
+		slog.Warn("Failed to load project config", "error", err)
 	}
 
 	// Initialize navigation state
@@ -150,6 +276,18 @@ func New(
 	return app, nil
 }
 
+// hyperlinksEnabled resolves the hyperlinks setting, detecting the terminal
+// support for "auto" and unknown values
+func hyperlinksEnabled(setting string) bool {
+	switch setting {
+	case "on":
+		return true
+	case "off":
+		return false
+	}
+	return styles.DetectHyperlinks()
+}
+
 func (a *App) InitializeProvider() tea.Cmd {
 	return func() tea.Msg {
 		providersResponse, err := a.Client.Config.Providers(context.Background())
@@ -240,6 +378,20 @@ func (a *App) IsBusy() bool {
 	return lastMessage.Metadata.Time.Completed == 0
 }
 
+// RunningTasks returns the number of sub-agent tasks that have not finished
+func (a *App) RunningTasks() int {
+	if a.TaskClient == nil {
+		return 0
+	}
+	running := 0
+	for _, task := range a.TaskClient.Tasks() {
+		if task.Status == TaskStatusRunning {
+			running++
+		}
+	}
+	return running
+}
+
 func (a *App) SaveState() {
 	err := config.SaveState(a.StatePath, a.State)
 	if err != nil {
@@ -247,7 +399,9 @@ func (a *App) SaveState() {
 	}
 }
 
-func (a *App) InitializeProject(ctx context.Context) tea.Cmd {
+// InitializeProject starts a session that writes AGENTS.md. The reviewed
+// analysis, if any, goes along for servers that accept it.
+func (a *App) InitializeProject(ctx context.Context, analysis string) tea.Cmd {
 	cmds := []tea.Cmd{}
 
 	session, err := a.CreateSession(ctx)
@@ -259,11 +413,22 @@ func (a *App) InitializeProject(ctx context.Context) tea.Cmd {
 	a.Session = session
 	cmds = append(cmds, util.CmdHandler(SessionSelectedMsg(session)))
 
+	sessionID := a.Session.ID
 	go func() {
-		_, err := a.Client.Session.Init(ctx, a.Session.ID, opencode.SessionInitParams{
-			ProviderID: opencode.F(a.Provider.ID),
-			ModelID:    opencode.F(a.Model.ID),
-		})
+		var err error
+		if analysis != "" && a.Features.Enabled(FeatureAnalysis) {
+			params := map[string]any{
+				"providerID": a.Provider.ID,
+				"modelID":    a.Model.ID,
+				"analysis":   analysis,
+			}
+			err = a.Client.Post(ctx, "/session/"+sessionID+"/init", params, nil)
+		} else {
+			_, err = a.Client.Session.Init(ctx, sessionID, opencode.SessionInitParams{
+				ProviderID: opencode.F(a.Provider.ID),
+				ModelID:    opencode.F(a.Model.ID),
+			})
+		}
 		if err != nil {
 			slog.Error("Failed to initialize project", "error", err)
 			// status.Error(err.Error())
@@ -273,19 +438,6 @@ func (a *App) InitializeProject(ctx context.Context) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-func (a *App) CompactSession(ctx context.Context) tea.Cmd {
-	go func() {
-		_, err := a.Client.Session.Summarize(ctx, a.Session.ID, opencode.SessionSummarizeParams{
-			ProviderID: opencode.F(a.Provider.ID),
-			ModelID:    opencode.F(a.Model.ID),
-		})
-		if err != nil {
-			slog.Error("Failed to compact session", "error", err)
-		}
-	}()
-	return nil
-}
-
 func (a *App) MarkProjectInitialized(ctx context.Context) error {
 	_, err := a.Client.App.Init(ctx)
 	if err != nil {
@@ -303,7 +455,7 @@ func (a *App) CreateSession(ctx context.Context) (*opencode.Session, error) {
 	return session, nil
 }
 
-func (a *App) SendChatMessage(ctx context.Context, text string, attachments []Attachment) tea.Cmd {
+func (a *App) SendChatMessage(ctx context.Context, text string, attachments []Attachment, compactPinned bool) tea.Cmd {
 	var cmds []tea.Cmd
 	if a.Session == nil || a.Session.ID == "" {
 		session, err := a.CreateSession(ctx)
@@ -395,6 +547,11 @@ func (a *App) SendChatMessage(ctx context.Context, text string, attachments []At
 
 		// Add all image parts
 		parts = append(parts, imageParts...)
+		pinnedLimit := pinnedContextMaxBytes
+		if compactPinned {
+			pinnedLimit = PinnedContextCompactBytes
+		}
+		parts = append(parts, a.pinnedContextParts(pinnedLimit)...)
 
 		// Show feedback about loaded images
 		if len(imagePaths) > 0 {
@@ -411,7 +568,7 @@ func (a *App) SendChatMessage(ctx context.Context, text string, attachments []At
 			Parts:      opencode.F(parts),
 			ProviderID: opencode.F(a.Provider.ID),
 			ModelID:    opencode.F(a.Model.ID),
-		})
+		}, a.presetOptions()...)
 		if err != nil {
 			errormsg := fmt.Sprintf("failed to send message: %v", err)
 			slog.Error(errormsg)
@@ -455,6 +612,7 @@ func (a *App) DeleteSession(ctx context.Context, sessionID string) error {
 		slog.Error("Failed to delete session", "error", err)
 		return err
 	}
+	a.setSessionMeta(sessionID, config.SessionMeta{})
 	return nil
 }
 
@@ -483,6 +641,39 @@ func (a *App) ListProviders(ctx context.Context) ([]opencode.Provider, error) {
 	return providers.Providers, nil
 }
 
+// ErrNoFavoriteModels is returned when there is no favorite model to cycle to
+var ErrNoFavoriteModels = errors.New("no favorite models, press ctrl+f in the model list to add one")
+
+// NextFavoriteModel returns the favorite model after the current one, wrapping
+// around. Favorites the server no longer provides are skipped.
+func (a *App) NextFavoriteModel(ctx context.Context) (opencode.Provider, opencode.Model, error) {
+	favorites := a.State.FavoriteModels
+	if len(favorites) == 0 {
+		return opencode.Provider{}, opencode.Model{}, ErrNoFavoriteModels
+	}
+	providers, err := a.ListProviders(ctx)
+	if err != nil {
+		return opencode.Provider{}, opencode.Model{}, err
+	}
+
+	current := -1
+	if a.Provider != nil && a.Model != nil {
+		current = slices.Index(favorites, config.ModelRef{ProviderID: a.Provider.ID, ModelID: a.Model.ID})
+	}
+	for i := 1; i <= len(favorites); i++ {
+		favorite := favorites[(current+i+len(favorites))%len(favorites)]
+		for _, provider := range providers {
+			if provider.ID != favorite.ProviderID {
+				continue
+			}
+			if model, ok := provider.Models[favorite.ModelID]; ok {
+				return provider, model, nil
+			}
+		}
+	}
+	return opencode.Provider{}, opencode.Model{}, ErrNoFavoriteModels
+}
+
 // func (a *App) loadCustomKeybinds() {
 //
 // }
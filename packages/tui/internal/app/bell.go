@@ -0,0 +1,88 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/config"
+)
+
+// BellEvent names finished work that can sound an alert
+type BellEvent string
+
+const (
+	BellResponse BellEvent = "response"
+	BellTask     BellEvent = "task"
+)
+
+// defaultBellIdleAfter is how long the user has to be away from the
+// keyboard before an alert sounds, unless configured otherwise
+const defaultBellIdleAfter = 30 * time.Second
+
+const (
+	// bellSequence is the terminal bell
+	bellSequence = "\a"
+	// toneSequence plays two short rising notes with DECPS
+	toneSequence = "\x1b[4;3;17,~\x1b[4;3;22,~"
+)
+
+// BellDue reports whether an event sounds an alert after the user has
+// been idle for idle
+func BellDue(bell config.BellConfig, event BellEvent, idle time.Duration) bool {
+	if bell.Muted {
+		return false
+	}
+	switch event {
+	case BellResponse:
+		if !bell.Response {
+			return false
+		}
+	case BellTask:
+		if !bell.Task {
+			return false
+		}
+	default:
+		return false
+	}
+	after := defaultBellIdleAfter
+	if bell.IdleAfter > 0 {
+		after = time.Duration(bell.IdleAfter) * time.Second
+	}
+	return idle >= after
+}
+
+// BellSequence returns what is written to the terminal to sound an alert
+func BellSequence(sound string) string {
+	if sound == "tone" {
+		return toneSequence
+	}
+	return bellSequence
+}
+
+// Ring sounds an alert for an event, unless it is off, muted, the user was
+// active within the idle threshold, or quiet hours are active
+func (a *App) Ring(event BellEvent, idle time.Duration) tea.Cmd {
+	if !BellDue(a.State.Bell, event, idle) || a.QuietHoursActive(time.Now()) {
+		return nil
+	}
+	return tea.Raw(BellSequence(a.State.Bell.Sound))
+}
+
+// ToggleBellEvent turns alerts for an event on or off and reports whether
+// they are on now. Turning one on unmutes the alerts.
+func (a *App) ToggleBellEvent(event BellEvent) bool {
+	var on bool
+	switch event {
+	case BellResponse:
+		a.State.Bell.Response = !a.State.Bell.Response
+		on = a.State.Bell.Response
+	case BellTask:
+		a.State.Bell.Task = !a.State.Bell.Task
+		on = a.State.Bell.Task
+	}
+	if on {
+		a.State.Bell.Muted = false
+	}
+	a.SaveState()
+	return on
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sst/dgmo/internal/config"
+)
+
+func TestBellDue(t *testing.T) {
+	tests := []struct {
+		name  string
+		bell  config.BellConfig
+		event BellEvent
+		idle  time.Duration
+		want  bool
+	}{
+		{"off by default", config.BellConfig{}, BellResponse, time.Hour, false},
+		{"response", config.BellConfig{Response: true}, BellResponse, time.Minute, true},
+		{"user active", config.BellConfig{Response: true}, BellResponse, 10 * time.Second, false},
+		{"other event", config.BellConfig{Response: true}, BellTask, time.Minute, false},
+		{"muted", config.BellConfig{Task: true, Muted: true}, BellTask, time.Minute, false},
+		{"custom idle", config.BellConfig{Task: true, IdleAfter: 5}, BellTask, 5 * time.Second, true},
+	}
+	for _, tc := range tests {
+		if got := BellDue(tc.bell, tc.event, tc.idle); got != tc.want {
+			t.Errorf("%s: BellDue = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBellSequence(t *testing.T) {
+	if got := BellSequence(""); got != "\a" {
+		t.Errorf("default sound = %q", got)
+	}
+	if got := BellSequence("tone"); got != toneSequence {
+		t.Errorf("tone = %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sst/dgmo/internal/config"
+)
+
+// BookmarkJumpMsg asks the transcript to scroll back to a bookmark
+type BookmarkJumpMsg struct {
+	Bookmark config.Bookmark
+}
+
+// SessionBookmarks returns the bookmarks of a session, oldest first
+func (a *App) SessionBookmarks(sessionID string) []config.Bookmark {
+	return a.SessionMeta(sessionID).Bookmarks
+}
+
+// SetSessionBookmark adds a bookmark to a session and returns it with the
+// name it was given
+func (a *App) SetSessionBookmark(sessionID string, bookmark config.Bookmark) config.Bookmark {
+	meta := a.SessionMeta(sessionID)
+	meta.Bookmarks = SetBookmark(meta.Bookmarks, bookmark)
+	a.setSessionMeta(sessionID, meta)
+	return meta.Bookmarks[len(meta.Bookmarks)-1]
+}
+
+// DeleteSessionBookmark removes the bookmark of a session with a name
+func (a *App) DeleteSessionBookmark(sessionID, name string) {
+	meta := a.SessionMeta(sessionID)
+	meta.Bookmarks = slices.DeleteFunc(slices.Clone(meta.Bookmarks), func(b config.Bookmark) bool {
+		return b.Name == name
+	})
+	a.setSessionMeta(sessionID, meta)
+}
+
+// SetBookmark returns bookmarks with a bookmark added last. It replaces a
+// bookmark of the same name, and one without a name is numbered.
+func SetBookmark(bookmarks []config.Bookmark, bookmark config.Bookmark) []config.Bookmark {
+	bookmark.Name = strings.Join(strings.Fields(bookmark.Name), " ")
+	if bookmark.Name == "" {
+		taken := func(name string) bool {
+			return slices.ContainsFunc(bookmarks, func(b config.Bookmark) bool { return b.Name == name })
+		}
+		for n := len(bookmarks) + 1; ; n++ {
+			if name := fmt.Sprintf("Bookmark %d", n); !taken(name) {
+				bookmark.Name = name
+				break
+			}
+		}
+	}
+	updated := slices.DeleteFunc(slices.Clone(bookmarks), func(b config.Bookmark) bool {
+		return b.Name == bookmark.Name
+	})
+	return append(updated, bookmark)
+}
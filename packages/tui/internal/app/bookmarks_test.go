@@ -0,0 +1,42 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sst/dgmo/internal/config"
+)
+
+func TestSetBookmark(t *testing.T) {
+	var bookmarks []config.Bookmark
+	bookmarks = SetBookmark(bookmarks, config.Bookmark{MessageID: "msg_1", Offset: 4})
+	bookmarks = SetBookmark(bookmarks, config.Bookmark{Name: "  failing   test ", MessageID: "msg_2"})
+	bookmarks = SetBookmark(bookmarks, config.Bookmark{MessageID: "msg_3"})
+	want := []config.Bookmark{
+		{Name: "Bookmark 1", MessageID: "msg_1", Offset: 4},
+		{Name: "failing test", MessageID: "msg_2"},
+		{Name: "Bookmark 3", MessageID: "msg_3"},
+	}
+	if !slices.Equal(bookmarks, want) {
+		t.Fatalf("bookmarks = %+v, want %+v", bookmarks, want)
+	}
+
+	// setting a name again moves the bookmark
+	moved := SetBookmark(bookmarks, config.Bookmark{Name: "Bookmark 1", MessageID: "msg_9"})
+	want = []config.Bookmark{
+		{Name: "failing test", MessageID: "msg_2"},
+		{Name: "Bookmark 3", MessageID: "msg_3"},
+		{Name: "Bookmark 1", MessageID: "msg_9"},
+	}
+	if !slices.Equal(moved, want) {
+		t.Errorf("moved = %+v, want %+v", moved, want)
+	}
+
+	// numbers in use are skipped
+	if got := SetBookmark(moved, config.Bookmark{})[3].Name; got != "Bookmark 4" {
+		t.Errorf("next default name = %q", got)
+	}
+	if got := SetBookmark(bookmarks[2:], config.Bookmark{})[1].Name; got != "Bookmark 2" {
+		t.Errorf("default name after Bookmark 3 = %q", got)
+	}
+}
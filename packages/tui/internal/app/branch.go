@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// SessionBranch is where a session was branched from
+type SessionBranch struct {
+	SessionID string `json:"sessionID"`
+	MessageID string `json:"messageID"`
+}
+
+// SessionBranchedMsg is sent after a session was branched, Prompt is the
+// prompt the branch point replaced, to be edited and sent again
+type SessionBranchedMsg struct {
+	Session opencode.Session
+	Prompt  string
+}
+
+// BranchOf returns where a session was branched from
+func BranchOf(session *opencode.Session) (SessionBranch, bool) {
+	field, ok := session.JSON.ExtraFields["branch"]
+	if !ok || field.IsNull() {
+		return SessionBranch{}, false
+	}
+	var branch SessionBranch
+	if err := json.Unmarshal([]byte(field.Raw()), &branch); err != nil || branch.SessionID == "" {
+		return SessionBranch{}, false
+	}
+	return branch, true
+}
+
+// BranchPoint returns the last message a branch from the selected message
+// keeps. Branching from a response keeps it. Branching from a prompt keeps
+// what came before it and returns the prompt, so it can be sent another way.
+func BranchPoint(messages []opencode.Message, selectedID string) (messageID, prompt string, err error) {
+	sent := slices.DeleteFunc(slices.Clone(messages), func(message opencode.Message) bool {
+		return !sentMessage(message)
+	})
+	i := len(sent) - 1
+	if selectedID != "" {
+		i = slices.IndexFunc(sent, func(message opencode.Message) bool { return message.ID == selectedID })
+	}
+	if i < 0 {
+		return "", "", errors.New("no message to branch from")
+	}
+	if sent[i].Role != opencode.MessageRoleUser {
+		return sent[i].ID, "", nil
+	}
+	if i == 0 {
+		return "", "", errors.New("nothing comes before the first prompt, start a new session instead")
+	}
+	return sent[i-1].ID, PromptText(sent[i]), nil
+}
+
+// BranchSession creates a session with the messages of a session up to and
+// including a message
+func (a *App) BranchSession(ctx context.Context, sessionID, messageID string) (*opencode.Session, error) {
+	if err := a.Features.Require(FeatureBranch); err != nil {
+		return nil, err
+	}
+	var session opencode.Session
+	endpoint := fmt.Sprintf("/session/%s/branch", sessionID)
+	if err := a.Client.Post(ctx, endpoint, map[string]any{"messageID": messageID}, &session); err != nil {
+		return nil, fmt.Errorf("failed to branch session: %w", err)
+	}
+	return &session, nil
+}
+
+// GroupBranches orders sessions so each branch follows the session it was
+// branched from, and returns how deep each session is in its branch tree.
+// Sessions whose origin is not listed stay where they are.
+func GroupBranches(sessions []opencode.Session) ([]opencode.Session, map[string]int) {
+	listed := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		listed[session.ID] = true
+	}
+	branches := make(map[string][]opencode.Session)
+	var roots []opencode.Session
+	for _, session := range sessions {
+		if branch, ok := BranchOf(&session); ok && listed[branch.SessionID] && branch.SessionID != session.ID {
+			branches[branch.SessionID] = append(branches[branch.SessionID], session)
+			continue
+		}
+		roots = append(roots, session)
+	}
+
+	grouped := make([]opencode.Session, 0, len(sessions))
+	depths := make(map[string]int, len(sessions))
+	var add func(session opencode.Session, depth int)
+	add = func(session opencode.Session, depth int) {
+		if _, seen := depths[session.ID]; seen {
+			return
+		}
+		grouped = append(grouped, session)
+		depths[session.ID] = depth
+		for _, branch := range branches[session.ID] {
+			add(branch, depth+1)
+		}
+	}
+	for _, session := range roots {
+		add(session, 0)
+	}
+	// a loop of branches has no root, its sessions are listed as they come
+	for _, session := range sessions {
+		add(session, 0)
+	}
+	return grouped, depths
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestBranchPoint(t *testing.T) {
+	messages := resendMessages(t)
+	tests := []struct {
+		selected, id, prompt string
+	}{
+		// the whole transcript, the optimistic prompt is not on the server
+		{"", "msg_4", ""},
+		{"msg_2", "msg_2", ""},
+		{"msg_3", "msg_2", "now add a test"},
+	}
+	for _, tc := range tests {
+		id, prompt, err := BranchPoint(messages, tc.selected)
+		if err != nil || id != tc.id || prompt != tc.prompt {
+			t.Errorf("BranchPoint(%q) = %q, %q, %v, want %q, %q", tc.selected, id, prompt, err, tc.id, tc.prompt)
+		}
+	}
+	for _, selected := range []string{"msg_1", "msg_9"} {
+		if _, _, err := BranchPoint(messages, selected); err == nil {
+			t.Errorf("BranchPoint(%q) should fail", selected)
+		}
+	}
+}
+
+func TestGroupBranches(t *testing.T) {
+	raw := `[
+		{"id": "ses_d", "title": "d", "branch": {"sessionID": "ses_b", "messageID": "msg_2"}},
+		{"id": "ses_c", "title": "c", "branch": {"sessionID": "ses_gone", "messageID": "msg_1"}},
+		{"id": "ses_b", "title": "b", "branch": {"sessionID": "ses_a", "messageID": "msg_1"}},
+		{"id": "ses_a", "title": "a"}
+	]`
+	var sessions []opencode.Session
+	if err := json.Unmarshal([]byte(raw), &sessions); err != nil {
+		t.Fatal(err)
+	}
+	if branch, ok := BranchOf(&sessions[0]); !ok || branch != (SessionBranch{"ses_b", "msg_2"}) {
+		t.Errorf("BranchOf = %+v, %v", branch, ok)
+	}
+
+	grouped, depths := GroupBranches(sessions)
+	var ids []string
+	for _, session := range grouped {
+		ids = append(ids, session.ID)
+	}
+	if want := []string{"ses_c", "ses_a", "ses_b", "ses_d"}; !slices.Equal(ids, want) {
+		t.Errorf("grouped = %v, want %v", ids, want)
+	}
+	if depths["ses_c"] != 0 || depths["ses_a"] != 0 || depths["ses_b"] != 1 || depths["ses_d"] != 2 {
+		t.Errorf("depths = %v", depths)
+	}
+}
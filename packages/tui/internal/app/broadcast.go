@@ -0,0 +1,347 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/opencode-sdk-go"
+)
+
+const (
+	// MaxBroadcastTargets bounds how many sessions one broadcast prompts
+	MaxBroadcastTargets = 8
+	// maxBroadcastRecords is how many broadcasts are kept for evaluation
+	maxBroadcastRecords = 20
+	// BroadcastTag is put on every session a broadcast prompted, so they
+	// can be found again to evaluate
+	BroadcastTag = "eval"
+)
+
+// BroadcastTarget is a session and model a broadcast prompt goes to. An
+// empty session ID creates a new session.
+type BroadcastTarget struct {
+	SessionID  string
+	Title      string // title of an existing session
+	ProviderID string
+	ModelID    string
+}
+
+// Label names the target in the broadcast and comparison dialogs
+func (t BroadcastTarget) Label() string {
+	model := t.ProviderID + "/" + t.ModelID
+	if t.SessionID == "" {
+		return model
+	}
+	return fmt.Sprintf("%s (%s)", t.Title, model)
+}
+
+// BroadcastStatus is how far the prompt of a broadcast got in one session
+type BroadcastStatus string
+
+const (
+	BroadcastSending BroadcastStatus = "sending"
+	BroadcastRunning BroadcastStatus = "running"
+	BroadcastDone    BroadcastStatus = "done"
+	BroadcastFailed  BroadcastStatus = "failed"
+)
+
+// BroadcastRun is the response of one target to a broadcast
+type BroadcastRun struct {
+	Target    BroadcastTarget
+	SessionID string
+	Status    BroadcastStatus
+	Error     string
+	// Response is the text of the latest assistant message
+	Response string
+	Cost     float64
+	Tokens   float64 // output tokens
+	Started  time.Time
+	Finished time.Time
+	// Rating is the score the user gave the response, 0 if unrated
+	Rating int
+}
+
+// Elapsed is how long the response took, or has taken so far
+func (r BroadcastRun) Elapsed() time.Duration {
+	switch {
+	case r.Started.IsZero():
+		return 0
+	case r.Finished.IsZero():
+		return time.Since(r.Started)
+	}
+	return r.Finished.Sub(r.Started)
+}
+
+// Broadcast is one prompt sent to several sessions at once
+type Broadcast struct {
+	ID     string
+	Prompt string
+	Time   time.Time
+	Runs   []BroadcastRun
+}
+
+// BroadcastConfirmedMsg asks to send a prompt to several targets
+type BroadcastConfirmedMsg struct {
+	Prompt  string
+	Targets []BroadcastTarget
+}
+
+// BroadcastRunMsg reports a step of one run: its session is ready and the
+// prompt goes out next, or sending failed
+type BroadcastRunMsg struct {
+	BroadcastID string
+	Index       int
+	SessionID   string
+	Err         error
+}
+
+// BroadcastLoadedMsg carries a recorded broadcast with the responses read
+// back from its sessions
+type BroadcastLoadedMsg struct {
+	Broadcast *Broadcast
+}
+
+// NewBroadcast checks a prompt and its targets and returns the broadcast
+// to send
+func NewBroadcast(prompt string, targets []BroadcastTarget) (*Broadcast, error) {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return nil, fmt.Errorf("write the prompt to broadcast first")
+	}
+	if len(targets) == 0 || len(targets) > MaxBroadcastTargets {
+		return nil, fmt.Errorf("choose between 1 and %d sessions or models", MaxBroadcastTargets)
+	}
+	now := time.Now()
+	broadcast := &Broadcast{
+		ID:     fmt.Sprintf("broadcast-%d", now.UnixNano()),
+		Prompt: prompt,
+		Time:   now,
+	}
+	for _, target := range targets {
+		if target.ProviderID == "" || target.ModelID == "" {
+			return nil, fmt.Errorf("%s has no model", target.Label())
+		}
+		broadcast.Runs = append(broadcast.Runs, BroadcastRun{
+			Target:    target,
+			SessionID: target.SessionID,
+			Status:    BroadcastSending,
+		})
+	}
+	return broadcast, nil
+}
+
+// Observe updates the run answering in the session of a message and
+// reports whether it changed
+func (b *Broadcast) Observe(message opencode.Message) bool {
+	if message.Role != opencode.MessageRoleAssistant {
+		return false
+	}
+	i := slices.IndexFunc(b.Runs, func(run BroadcastRun) bool {
+		return run.SessionID != "" && run.SessionID == message.Metadata.SessionID
+	})
+	if i < 0 {
+		return false
+	}
+	run := &b.Runs[i]
+	created := time.UnixMilli(int64(message.Metadata.Time.Created))
+	if !run.Started.IsZero() && created.Before(run.Started.Add(-time.Second)) {
+		// an earlier response of an existing session
+		return false
+	}
+	if run.Started.IsZero() {
+		run.Started = created
+	}
+	var text []string
+	for _, part := range message.Parts {
+		if part, ok := part.AsUnion().(opencode.TextPart); ok && strings.TrimSpace(part.Text) != "" {
+			text = append(text, strings.TrimSpace(part.Text))
+		}
+	}
+	if len(text) > 0 {
+		run.Response = strings.Join(text, "\n\n")
+	}
+	run.Cost = message.Metadata.Assistant.Cost
+	run.Tokens = message.Metadata.Assistant.Tokens.Output
+	if message.Metadata.Error.AsUnion() != nil {
+		run.Status = BroadcastFailed
+		run.Error = messageError(message)
+	} else if run.Status != BroadcastFailed {
+		run.Status = BroadcastRunning
+	}
+	if message.Metadata.Time.Completed != 0 {
+		run.Finished = time.UnixMilli(int64(message.Metadata.Time.Completed))
+		if run.Status == BroadcastRunning {
+			run.Status = BroadcastDone
+		}
+	}
+	return true
+}
+
+// messageError returns why an assistant message failed
+func messageError(message opencode.Message) string {
+	switch err := message.Metadata.Error.AsUnion().(type) {
+	case opencode.MessageMetadataErrorMessageOutputLengthError:
+		return "Message output length exceeded"
+	case opencode.ProviderAuthError:
+		return err.Data.Message
+	case opencode.UnknownError:
+		return err.Data.Message
+	}
+	return "the response failed"
+}
+
+// Record is what is kept of a broadcast to evaluate it later
+func (b *Broadcast) Record() config.BroadcastRecord {
+	record := config.BroadcastRecord{ID: b.ID, Prompt: b.Prompt, Time: b.Time}
+	for _, run := range b.Runs {
+		if run.SessionID == "" {
+			continue
+		}
+		record.Runs = append(record.Runs, config.BroadcastEntry{
+			SessionID:  run.SessionID,
+			Title:      run.Target.Title,
+			ProviderID: run.Target.ProviderID,
+			ModelID:    run.Target.ModelID,
+			Rating:     run.Rating,
+		})
+	}
+	return record
+}
+
+// StartBroadcast sends a prompt to every target, creating the sessions of
+// targets without one first
+func (a *App) StartBroadcast(ctx context.Context, prompt string, targets []BroadcastTarget) (tea.Cmd, error) {
+	broadcast, err := NewBroadcast(prompt, targets)
+	if err != nil {
+		return nil, err
+	}
+	a.Broadcast = broadcast
+
+	cmds := make([]tea.Cmd, len(broadcast.Runs))
+	for i, run := range broadcast.Runs {
+		cmds[i] = func() tea.Msg {
+			sessionID := run.SessionID
+			if sessionID == "" {
+				session, err := a.CreateSession(ctx)
+				if err != nil {
+					return BroadcastRunMsg{BroadcastID: broadcast.ID, Index: i, Err: err}
+				}
+				sessionID = session.ID
+			}
+			return BroadcastRunMsg{BroadcastID: broadcast.ID, Index: i, SessionID: sessionID}
+		}
+	}
+	return tea.Batch(cmds...), nil
+}
+
+// BroadcastRunUpdate applies a step of a run of the current broadcast.
+// Once its session is ready the session is tagged and the prompt sent.
+func (a *App) BroadcastRunUpdate(ctx context.Context, msg BroadcastRunMsg) tea.Cmd {
+	broadcast := a.Broadcast
+	if broadcast == nil || broadcast.ID != msg.BroadcastID || msg.Index >= len(broadcast.Runs) {
+		return nil
+	}
+	run := &broadcast.Runs[msg.Index]
+	if msg.Err != nil {
+		run.Status = BroadcastFailed
+		run.Error = msg.Err.Error()
+		return nil
+	}
+	if run.Status != BroadcastSending {
+		return nil
+	}
+	run.SessionID = msg.SessionID
+	run.Status = BroadcastRunning
+	run.Started = time.Now()
+	if !slices.Contains(a.SessionMeta(msg.SessionID).Tags, BroadcastTag) {
+		a.ToggleSessionTag(msg.SessionID, BroadcastTag)
+	}
+	a.saveBroadcast(broadcast)
+
+	target := run.Target
+	return func() tea.Msg {
+		_, err := a.Client.Session.Chat(ctx, msg.SessionID, opencode.SessionChatParams{
+			Parts: opencode.F([]opencode.MessagePartUnionParam{
+				opencode.TextPartParam{
+					Type: opencode.F(opencode.TextPartTypeText),
+					Text: opencode.F(broadcast.Prompt),
+				},
+			}),
+			ProviderID: opencode.F(target.ProviderID),
+			ModelID:    opencode.F(target.ModelID),
+		})
+		if err != nil {
+			return BroadcastRunMsg{
+				BroadcastID: broadcast.ID,
+				Index:       msg.Index,
+				SessionID:   msg.SessionID,
+				Err:         fmt.Errorf("failed to send the prompt: %w", err),
+			}
+		}
+		return nil
+	}
+}
+
+// RateBroadcastRun scores the response of a run of the current broadcast,
+// 0 clears the rating
+func (a *App) RateBroadcastRun(index, rating int) {
+	if a.Broadcast == nil || index < 0 || index >= len(a.Broadcast.Runs) {
+		return
+	}
+	a.Broadcast.Runs[index].Rating = rating
+	a.saveBroadcast(a.Broadcast)
+}
+
+// saveBroadcast stores the record of a broadcast, dropping the oldest
+// records beyond the limit
+func (a *App) saveBroadcast(broadcast *Broadcast) {
+	record := broadcast.Record()
+	records := slices.DeleteFunc(slices.Clone(a.State.Broadcasts), func(r config.BroadcastRecord) bool {
+		return r.ID == record.ID
+	})
+	records = append(records, record)
+	a.State.Broadcasts = records[max(0, len(records)-maxBroadcastRecords):]
+	a.SaveState()
+}
+
+// LoadLastBroadcast reads the responses of the latest recorded broadcast
+// back from its sessions. It returns nil if nothing was broadcast yet.
+func (a *App) LoadLastBroadcast(ctx context.Context) tea.Cmd {
+	if len(a.State.Broadcasts) == 0 {
+		return nil
+	}
+	record := a.State.Broadcasts[len(a.State.Broadcasts)-1]
+	return func() tea.Msg {
+		broadcast := &Broadcast{ID: record.ID, Prompt: record.Prompt, Time: record.Time}
+		for _, entry := range record.Runs {
+			broadcast.Runs = append(broadcast.Runs, BroadcastRun{
+				Target: BroadcastTarget{
+					SessionID:  entry.SessionID,
+					Title:      entry.Title,
+					ProviderID: entry.ProviderID,
+					ModelID:    entry.ModelID,
+				},
+				SessionID: entry.SessionID,
+				Status:    BroadcastDone,
+				Rating:    entry.Rating,
+				Started:   record.Time,
+			})
+			messages, err := a.ListMessages(ctx, entry.SessionID)
+			if err != nil {
+				run := &broadcast.Runs[len(broadcast.Runs)-1]
+				run.Status = BroadcastFailed
+				run.Error = err.Error()
+				continue
+			}
+			for _, message := range messages {
+				broadcast.Observe(message)
+			}
+		}
+		return BroadcastLoadedMsg{Broadcast: broadcast}
+	}
+}
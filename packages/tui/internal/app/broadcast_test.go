@@ -0,0 +1,91 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestNewBroadcast(t *testing.T) {
+	target := BroadcastTarget{ProviderID: "anthropic", ModelID: "claude"}
+	if _, err := NewBroadcast("  ", []BroadcastTarget{target}); err == nil {
+		t.Error("an empty prompt should fail")
+	}
+	if _, err := NewBroadcast("hi", nil); err == nil {
+		t.Error("no targets should fail")
+	}
+	if _, err := NewBroadcast("hi", []BroadcastTarget{{SessionID: "ses_1", Title: "old"}}); err == nil {
+		t.Error("a target without a model should fail")
+	}
+	broadcast, err := NewBroadcast(" hi ", []BroadcastTarget{target, {SessionID: "ses_1", Title: "old", ProviderID: "openai", ModelID: "gpt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if broadcast.Prompt != "hi" || len(broadcast.Runs) != 2 || broadcast.Runs[1].SessionID != "ses_1" {
+		t.Errorf("broadcast = %+v", broadcast)
+	}
+	if label := broadcast.Runs[1].Target.Label(); label != "old (openai/gpt)" {
+		t.Errorf("label = %q", label)
+	}
+}
+
+func TestBroadcastObserve(t *testing.T) {
+	broadcast, err := NewBroadcast("hi", []BroadcastTarget{
+		{ProviderID: "anthropic", ModelID: "claude"},
+		{ProviderID: "openai", ModelID: "gpt"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.UnixMilli(time.Now().UnixMilli())
+	broadcast.Runs[0].SessionID = "ses_a"
+	broadcast.Runs[0].Status = BroadcastRunning
+	broadcast.Runs[0].Started = start
+
+	message := func(raw string) opencode.Message {
+		var message opencode.Message
+		if err := json.Unmarshal([]byte(raw), &message); err != nil {
+			t.Fatal(err)
+		}
+		return message
+	}
+	created := start.UnixMilli()
+
+	// an earlier response of an existing session is not this run
+	old := message(`{"id": "msg_0", "role": "assistant", "parts": [{"type": "text", "text": "old"}],
+		"metadata": {"sessionID": "ses_a", "time": {"created": 1000, "completed": 2000}, "tool": {}}}`)
+	if broadcast.Observe(old) {
+		t.Error("an earlier response should be ignored")
+	}
+
+	streaming := message(`{"id": "msg_1", "role": "assistant", "parts": [{"type": "text", "text": "Hello"}],
+		"metadata": {"sessionID": "ses_a", "time": {"created": ` + fmt.Sprint(created+500) + `}, "tool": {}}}`)
+	if !broadcast.Observe(streaming) {
+		t.Fatal("the response should update the run")
+	}
+	if run := broadcast.Runs[0]; run.Status != BroadcastRunning || run.Response != "Hello" {
+		t.Errorf("streaming run = %+v", run)
+	}
+
+	done := message(`{"id": "msg_1", "role": "assistant", "parts": [{"type": "text", "text": "Hello there"}],
+		"metadata": {"sessionID": "ses_a", "time": {"created": ` + fmt.Sprint(created+500) + `, "completed": ` + fmt.Sprint(created+3000) + `}, "tool": {},
+		"assistant": {"cost": 0.02, "modelID": "claude", "providerID": "anthropic", "path": {"cwd": "", "root": ""}, "system": [],
+		"tokens": {"input": 10, "output": 42, "reasoning": 0, "cache": {"read": 0, "write": 0}}}}}`)
+	broadcast.Observe(done)
+	run := broadcast.Runs[0]
+	if run.Status != BroadcastDone || run.Response != "Hello there" || run.Tokens != 42 || run.Cost != 0.02 {
+		t.Errorf("finished run = %+v", run)
+	}
+	if run.Elapsed() != 3*time.Second {
+		t.Errorf("elapsed = %v", run.Elapsed())
+	}
+
+	// the run without a session yet is left out of the record
+	record := broadcast.Record()
+	if len(record.Runs) != 1 || record.Runs[0].SessionID != "ses_a" || record.Runs[0].ModelID != "claude" {
+		t.Errorf("record = %+v", record)
+	}
+}
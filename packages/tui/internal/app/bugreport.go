@@ -0,0 +1,106 @@
+package app
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/bugreport"
+	"github.com/sst/dgmo/internal/logging"
+	"github.com/sst/dgmo/internal/paths"
+)
+
+// bugReportLogBytes is how much of the end of the log goes into a report
+const bugReportLogBytes = 512 << 10
+
+// BugReportBuiltMsg carries a redacted report waiting for review
+type BugReportBuiltMsg struct {
+	Report *bugreport.Report
+	// PublicKey seals the report when it is written, nil writes it plain
+	PublicKey crypto.PublicKey
+	// KeyPath is the file PublicKey was read from
+	KeyPath string
+	Err     error
+}
+
+// BuildBugReport collects the system details, the end of the log and the
+// transcript of the session into a report with its secrets redacted. With
+// keyPath the report is sealed for the public key in that file.
+func (a *App) BuildBugReport(keyPath string) tea.Cmd {
+	var transcript string
+	if a.Session != nil && a.Session.ID != "" {
+		transcript = exportMarkdown(a.Session, a.Messages)
+	}
+	system := a.bugReportSystem()
+	logPath := logging.Path(a.Info.Path.Data)
+	keyPath = paths.ExpandHome(keyPath)
+	return func() tea.Msg {
+		msg := BugReportBuiltMsg{KeyPath: keyPath}
+		if keyPath != "" {
+			data, err := os.ReadFile(keyPath)
+			if err == nil {
+				msg.PublicKey, err = bugreport.ParsePublicKey(data)
+			}
+			if err != nil {
+				msg.Err = fmt.Errorf("failed to read public key %s: %w", keyPath, err)
+				return msg
+			}
+		}
+
+		files := []bugreport.File{{Name: "system.txt", Content: []byte(system)}}
+		if log, err := readTail(logPath, bugReportLogBytes); err == nil {
+			files = append(files, bugreport.File{Name: "tui.log", Content: log})
+		}
+		if transcript != "" {
+			files = append(files, bugreport.File{Name: "transcript.md", Content: []byte(transcript)})
+		}
+		msg.Report = bugreport.New(files)
+		return msg
+	}
+}
+
+// WriteBugReport writes a reviewed report to the exports directory
+func (a *App) WriteBugReport(report *bugreport.Report, publicKey crypto.PublicKey) (string, error) {
+	return report.Write(filepath.Join(a.Info.Path.Data, "exports"), publicKey)
+}
+
+// bugReportSystem describes the versions and the terminal
+func (a *App) bugReportSystem() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "dgmo %s\n", a.Version)
+	fmt.Fprintf(&b, "os %s/%s, %s\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	for _, name := range []string{"TERM", "TERM_PROGRAM", "COLORTERM", "LANG"} {
+		fmt.Fprintf(&b, "%s=%s\n", name, os.Getenv(name))
+	}
+	if a.Provider != nil && a.Model != nil {
+		fmt.Fprintf(&b, "model %s/%s\n", a.Provider.ID, a.Model.ID)
+	}
+	if a.Session != nil && a.Session.ID != "" {
+		fmt.Fprintf(&b, "session %s, %d messages\n", a.Session.ID, len(a.Messages))
+	}
+	return b.String()
+}
+
+// readTail reads at most limit bytes from the end of a file
+func readTail(path string, limit int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if offset := info.Size() - limit; offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(io.LimitReader(file, limit))
+}
@@ -0,0 +1,289 @@
+package app
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// BundleExtension is the file extension of session bundles
+const BundleExtension = ".dgmo-bundle"
+
+// bundleVersion is bumped when the layout of a bundle changes
+const bundleVersion = 1
+
+// Files inside a bundle tarball
+const (
+	bundleManifestFile   = "manifest.json"
+	bundleSessionFile    = "session.json"
+	bundleMessagesFile   = "messages.json"
+	bundleTranscriptFile = "transcript.md"
+)
+
+// bundleMaxFileSize guards against unpacking huge or malicious entries
+const bundleMaxFileSize = 256 << 20
+
+// BundleArtifact is a file the agents created or changed during the session
+type BundleArtifact struct {
+	Path string `json:"path"`
+	Tool string `json:"tool"`
+}
+
+// BundleHandoff is a prompt that handed work to another agent
+type BundleHandoff struct {
+	Kind   string `json:"kind"` // "task" or "continuation"
+	Title  string `json:"title"`
+	Prompt string `json:"prompt"`
+}
+
+// BundleManifest describes the contents of a session bundle
+type BundleManifest struct {
+	Version     int              `json:"version"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	Source      string           `json:"source"` // project root on the exporting machine
+	SessionID   string           `json:"sessionId"`
+	Title       string           `json:"title"`
+	Checkpoints []Checkpoint     `json:"checkpoints"`
+	Artifacts   []BundleArtifact `json:"artifacts"`
+	Handoffs    []BundleHandoff  `json:"handoffs"`
+}
+
+// SessionBundle is an imported bundle. It is only ever shown read-only.
+type SessionBundle struct {
+	Path     string
+	Manifest BundleManifest
+	Session  opencode.Session
+	Messages []opencode.Message
+}
+
+// BundleImportedMsg is sent when a bundle was opened
+type BundleImportedMsg struct {
+	Bundle *SessionBundle
+}
+
+// ErrBundleReadOnly is returned when trying to continue an imported session
+var ErrBundleReadOnly = errors.New("imported sessions are read-only")
+
+// ExportBundle packs the current session, its checkpoints, the files the
+// agents touched and the handoff prompts into a bundle in dir
+func (a *App) ExportBundle(ctx context.Context, dir string) (string, error) {
+	if a.Session == nil || a.Session.ID == "" {
+		return "", fmt.Errorf("no session to export")
+	}
+
+	checkpoints, err := a.Checkpoints.ListCheckpoints(ctx, a.Session.ID)
+	if err != nil {
+		// checkpoints are optional, the transcript is what matters
+		slog.Warn("Exporting bundle without checkpoints", "error", err)
+	}
+	manifest := BundleManifest{
+		Version:     bundleVersion,
+		CreatedAt:   time.Now(),
+		Source:      a.Info.Path.Root,
+		SessionID:   a.Session.ID,
+		Title:       a.Session.Title,
+		Checkpoints: checkpoints,
+		Artifacts:   bundleArtifacts(a.Messages),
+		Handoffs:    bundleHandoffs(a.Messages),
+	}
+	if last := a.Continuation.Last(a.Session.ID); last != nil {
+		manifest.Handoffs = append(manifest.Handoffs, BundleHandoff{
+			Kind:   "continuation",
+			Title:  last.ProjectName,
+			Prompt: last.Prompt,
+		})
+	}
+
+	var session any = a.Session
+	if raw := a.Session.JSON.RawJSON(); raw != "" {
+		session = json.RawMessage(raw)
+	}
+	files := []struct {
+		name  string
+		value any
+	}{
+		{bundleManifestFile, manifest},
+		{bundleSessionFile, session},
+		{bundleMessagesFile, rawMessages(a.Messages)},
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create exports directory %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("dgmo-%s-%s%s", a.Session.ID, time.Now().Format("20060102-150405"), BundleExtension)
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create bundle %s: %w", path, err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	write := func(name string, content []byte) error {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(content)),
+			ModTime: manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(content)
+		return err
+	}
+	for _, f := range files {
+		content, err := json.MarshalIndent(f.value, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode %s: %w", f.name, err)
+		}
+		if err := write(f.name, content); err != nil {
+			return "", fmt.Errorf("failed to write bundle %s: %w", path, err)
+		}
+	}
+	if err := write(bundleTranscriptFile, []byte(exportMarkdown(a.Session, a.Messages))); err != nil {
+		return "", fmt.Errorf("failed to write bundle %s: %w", path, err)
+	}
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("failed to write bundle %s: %w", path, err)
+	}
+	if err := gz.Close(); err != nil {
+		return "", fmt.Errorf("failed to write bundle %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ImportBundle reads a bundle written by ExportBundle
+func ImportBundle(path string) (*SessionBundle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a session bundle: %w", path, err)
+	}
+	defer gz.Close()
+
+	contents := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Size > bundleMaxFileSize {
+			return nil, fmt.Errorf("bundle entry %s is too large", header.Name)
+		}
+		content, err := io.ReadAll(io.LimitReader(tr, bundleMaxFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		contents[header.Name] = content
+	}
+
+	bundle := &SessionBundle{Path: path}
+	for _, f := range []struct {
+		name   string
+		target any
+	}{
+		{bundleManifestFile, &bundle.Manifest},
+		{bundleSessionFile, &bundle.Session},
+		{bundleMessagesFile, &bundle.Messages},
+	} {
+		content, ok := contents[f.name]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing %s", f.name)
+		}
+		if err := json.Unmarshal(content, f.target); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", f.name, err)
+		}
+	}
+	if bundle.Manifest.Version > bundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this dgmo supports", bundle.Manifest.Version)
+	}
+	return bundle, nil
+}
+
+// OpenBundle shows an imported bundle in place of the current session. The
+// bundle stays read-only until another session is opened.
+func (a *App) OpenBundle(bundle *SessionBundle) {
+	a.Bundle = bundle
+	a.Session = &bundle.Session
+	a.Messages = bundle.Messages
+}
+
+func rawMessages(messages []opencode.Message) []json.RawMessage {
+	raw := make([]json.RawMessage, 0, len(messages))
+	for _, message := range messages {
+		if message.JSON.RawJSON() == "" {
+			continue
+		}
+		raw = append(raw, json.RawMessage(message.JSON.RawJSON()))
+	}
+	return raw
+}
+
+// bundleArtifacts lists the files written by edit and write tool calls
+func bundleArtifacts(messages []opencode.Message) []BundleArtifact {
+	var artifacts []BundleArtifact
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			toolCall, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok {
+				continue
+			}
+			name := toolCall.ToolInvocation.ToolName
+			if name != "edit" && name != "write" {
+				continue
+			}
+			args, _ := toolCall.ToolInvocation.Args.(map[string]any)
+			path, _ := args["filePath"].(string)
+			if path == "" || slices.ContainsFunc(artifacts, func(a BundleArtifact) bool {
+				return a.Path == path
+			}) {
+				continue
+			}
+			artifacts = append(artifacts, BundleArtifact{Path: path, Tool: name})
+		}
+	}
+	return artifacts
+}
+
+// bundleHandoffs lists the prompts given to sub-agents through the task tool
+func bundleHandoffs(messages []opencode.Message) []BundleHandoff {
+	var handoffs []BundleHandoff
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			toolCall, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok || toolCall.ToolInvocation.ToolName != "task" {
+				continue
+			}
+			args, _ := toolCall.ToolInvocation.Args.(map[string]any)
+			prompt, _ := args["prompt"].(string)
+			if prompt == "" {
+				continue
+			}
+			description, _ := args["description"].(string)
+			handoffs = append(handoffs, BundleHandoff{Kind: "task", Title: description, Prompt: prompt})
+		}
+	}
+	return handoffs
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// Checkpoint is a snapshot of the project files taken during a session
+type Checkpoint struct {
+	ID          string   `json:"id"`
+	SessionID   string   `json:"sessionId"`
+	MessageID   string   `json:"messageId"`
+	Description string   `json:"description"`
+	Timestamp   float64  `json:"timestamp"`
+	Files       []string `json:"files"`
+}
+
+// Time returns when the checkpoint was created
+func (c Checkpoint) Time() time.Time {
+	return time.UnixMilli(int64(c.Timestamp))
+}
+
+// CheckpointFileDiff is the change to a single file that restoring a
+// checkpoint would apply
+type CheckpointFileDiff struct {
+	Path      string `json:"path"`
+	Diff      string `json:"diff"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// CheckpointRestoredMsg is sent after a checkpoint has been restored
+type CheckpointRestoredMsg struct {
+	Checkpoint Checkpoint
+}
+
+// CheckpointCreatedMsg is sent after a checkpoint has been created from the TUI
+type CheckpointCreatedMsg struct {
+	Checkpoint Checkpoint
+}
+
+// CheckpointService talks to the server's checkpoint endpoints
+type CheckpointService struct {
+	client   *opencode.Client
+	features *FeatureFlags
+
+	mu      sync.Mutex
+	created map[string][]Checkpoint // checkpoints created by this client, by session
+}
+
+// NewCheckpointService creates a checkpoint service using the given client.
+// Calls fail with ErrFeatureUnsupported when the server has no checkpoints.
+func NewCheckpointService(client *opencode.Client, features *FeatureFlags) *CheckpointService {
+	return &CheckpointService{
+		client:   client,
+		features: features,
+		created:  make(map[string][]Checkpoint),
+	}
+}
+
+// CreateCheckpoint snapshots the project files of a session. The label is
+// optional and becomes the checkpoint description.
+func (s *CheckpointService) CreateCheckpoint(ctx context.Context, sessionID, label string) (*Checkpoint, error) {
+	if err := s.features.Require(FeatureCheckpoints); err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	endpoint := fmt.Sprintf("/session/%s/checkpoint", sessionID)
+	params := map[string]any{}
+	if label != "" {
+		params["description"] = label
+	}
+	if err := s.client.Post(ctx, endpoint, params, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	if checkpoint.SessionID == "" {
+		checkpoint.SessionID = sessionID
+	}
+	if checkpoint.Description == "" {
+		checkpoint.Description = label
+	}
+	if checkpoint.Timestamp == 0 {
+		checkpoint.Timestamp = float64(time.Now().UnixMilli())
+	}
+
+	s.mu.Lock()
+	s.created[sessionID] = append(s.created[sessionID], checkpoint)
+	s.mu.Unlock()
+
+	return &checkpoint, nil
+}
+
+// ListCheckpoints returns the checkpoints of a session, newest first
+func (s *CheckpointService) ListCheckpoints(ctx context.Context, sessionID string) ([]Checkpoint, error) {
+	if err := s.features.Require(FeatureCheckpoints); err != nil {
+		return nil, err
+	}
+	var checkpoints []Checkpoint
+	endpoint := fmt.Sprintf("/session/%s/checkpoints", sessionID)
+	if err := s.client.Get(ctx, endpoint, nil, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	return s.mergeCreated(sessionID, checkpoints), nil
+}
+
+// mergeCreated adds checkpoints created by this client that the server does
+// not list yet, so they show up right away
+func (s *CheckpointService) mergeCreated(sessionID string, checkpoints []Checkpoint) []Checkpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	listed := make(map[string]bool, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		listed[checkpoint.ID] = true
+	}
+
+	var pending []Checkpoint
+	for _, checkpoint := range s.created[sessionID] {
+		if !listed[checkpoint.ID] {
+			pending = append(pending, checkpoint)
+		}
+	}
+	s.created[sessionID] = pending
+
+	// newest first
+	for _, checkpoint := range pending {
+		checkpoints = append([]Checkpoint{checkpoint}, checkpoints...)
+	}
+	return checkpoints
+}
+
+// RestoreCheckpoint reverts the project files to the given checkpoint
+func (s *CheckpointService) RestoreCheckpoint(ctx context.Context, checkpointID string) error {
+	if err := s.features.Require(FeatureCheckpoints); err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/checkpoint/%s/restore", checkpointID)
+	if err := s.client.Post(ctx, endpoint, nil, nil); err != nil {
+		return fmt.Errorf("failed to restore checkpoint: %w", err)
+	}
+	return nil
+}
+
+// GetCheckpointDiff returns the file-level changes restoring the checkpoint
+// would make to the current working tree
+func (s *CheckpointService) GetCheckpointDiff(ctx context.Context, checkpointID string) ([]CheckpointFileDiff, error) {
+	if err := s.features.Require(FeatureCheckpoints); err != nil {
+		return nil, err
+	}
+	var diffs []CheckpointFileDiff
+	endpoint := fmt.Sprintf("/checkpoint/%s/diff", checkpointID)
+	if err := s.client.Get(ctx, endpoint, nil, &diffs); err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint diff: %w", err)
+	}
+	return diffs, nil
+}
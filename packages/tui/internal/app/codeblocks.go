@@ -0,0 +1,117 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// FencedBlock is a fenced code block of a message
+type FencedBlock struct {
+	Language string
+	Code     string
+}
+
+// Lines counts the lines of the code
+func (b FencedBlock) Lines() int {
+	if b.Code == "" {
+		return 0
+	}
+	return strings.Count(b.Code, "\n") + 1
+}
+
+// FencedBlocks returns the fenced code blocks of markdown text in order. A
+// block still open at the end, as in a response that is streaming, runs to
+// the end of the text.
+func FencedBlocks(text string) []FencedBlock {
+	var blocks []FencedBlock
+	var fence string
+	var indent int
+	var block FencedBlock
+	var code []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence == "" {
+			marker := fenceMarker(trimmed)
+			if marker == "" || len(line)-len(trimmed) > 3 {
+				continue
+			}
+			info := strings.TrimSpace(trimmed[len(marker):])
+			// a backtick fence cannot have backticks in its info string
+			if marker[0] == '`' && strings.Contains(info, "`") {
+				continue
+			}
+			fence, indent = marker, len(line)-len(trimmed)
+			block, code = FencedBlock{}, nil
+			if fields := strings.Fields(info); len(fields) > 0 {
+				block.Language = fields[0]
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" && marker[0] == fence[0] &&
+			len(marker) >= len(fence) && strings.TrimSpace(trimmed[len(marker):]) == "" {
+			block.Code = strings.Join(code, "\n")
+			blocks = append(blocks, block)
+			fence = ""
+			continue
+		}
+		// the content loses as much indentation as the opening fence had
+		code = append(code, line[min(indent, len(line)-len(trimmed)):])
+	}
+	if fence != "" {
+		block.Code = strings.TrimRight(strings.Join(code, "\n"), "\n")
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// fenceMarker returns the run of three or more backticks or tildes a line
+// starts with
+func fenceMarker(line string) string {
+	if line == "" || line[0] != '`' && line[0] != '~' {
+		return ""
+	}
+	n := len(line) - len(strings.TrimLeft(line, line[:1]))
+	if n < 3 {
+		return ""
+	}
+	return line[:n]
+}
+
+// CodeBlockOf picks a code block to copy: from the selected message, or
+// else from the last response with any. n counts the blocks of the message
+// from 1, 0 picks the last block. It returns the block, its number and how
+// many blocks the message has.
+func CodeBlockOf(messages []opencode.Message, selectedID string, n int) (FencedBlock, int, int, error) {
+	var blocks []FencedBlock
+	for i := len(messages) - 1; i >= 0 && blocks == nil; i-- {
+		message := messages[i]
+		if selectedID != "" {
+			if message.ID == selectedID {
+				blocks = FencedBlocks(messageText(message))
+				if blocks == nil {
+					return FencedBlock{}, 0, 0, errors.New("the selected message has no code blocks")
+				}
+			}
+			continue
+		}
+		if message.Role == opencode.MessageRoleAssistant {
+			blocks = FencedBlocks(messageText(message))
+		}
+	}
+	if blocks == nil && selectedID != "" {
+		return FencedBlock{}, 0, 0, errors.New("the selected message was not found")
+	}
+	if blocks == nil {
+		return FencedBlock{}, 0, 0, errors.New("no code blocks in the responses")
+	}
+	if n == 0 {
+		n = len(blocks)
+	}
+	if n < 1 || n > len(blocks) {
+		return FencedBlock{}, 0, 0, fmt.Errorf("there is no code block %d, the message has %d", n, len(blocks))
+	}
+	return blocks[n-1], n, len(blocks), nil
+}
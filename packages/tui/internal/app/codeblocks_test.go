@@ -0,0 +1,78 @@
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestFencedBlocks(t *testing.T) {
+	text := "Run this:\n" +
+		"```bash\ngo test ./...\n```\n" +
+		"Then\n" +
+		"  ~~~\n  indented\n    more\n  ~~~\n" +
+		"````markdown title=README\n```go\nfmt.Println()\n```\n````\n" +
+		"``inline`` is not a fence\n" +
+		"```go\nfunc streaming() {\n"
+	want := []FencedBlock{
+		{Language: "bash", Code: "go test ./..."},
+		{Code: "indented\n  more"},
+		{Language: "markdown", Code: "```go\nfmt.Println()\n```"},
+		{Language: "go", Code: "func streaming() {"},
+	}
+	if got := FencedBlocks(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("FencedBlocks = %#v, want %#v", got, want)
+	}
+	if lines := want[2].Lines(); lines != 3 {
+		t.Errorf("Lines = %d, want 3", lines)
+	}
+}
+
+func TestCodeBlockOf(t *testing.T) {
+	raw := "[" +
+		`{"id": "msg_1", "role": "assistant", "parts": [{"type": "text", "text": "` + "```" + `sh\nls\n` + "```" + `"}], "metadata": {"sessionID": "ses_1", "time": {"created": 1000}, "tool": {}}},` +
+		`{"id": "msg_2", "role": "user", "parts": [{"type": "text", "text": "` + "```" + `\nuser\n` + "```" + `"}], "metadata": {"sessionID": "ses_1", "time": {"created": 2000}, "tool": {}}},` +
+		`{"id": "msg_3", "role": "assistant", "parts": [{"type": "text", "text": "` + "```" + `go\na\n` + "```" + `\n` + "```" + `go\nb\n` + "```" + `"}], "metadata": {"sessionID": "ses_1", "time": {"created": 3000}, "tool": {}}},` +
+		`{"id": "msg_4", "role": "assistant", "parts": [{"type": "text", "text": "no code"}], "metadata": {"sessionID": "ses_1", "time": {"created": 4000}, "tool": {}}}` +
+		"]"
+	var messages []opencode.Message
+	if err := json.Unmarshal([]byte(raw), &messages); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		selected string
+		n        int
+		code     string
+		index    int
+		total    int
+		err      string
+	}{
+		// the last response with code, its last block
+		{"", 0, "b", 2, 2, ""},
+		{"", 1, "a", 1, 2, ""},
+		{"msg_1", 0, "ls", 1, 1, ""},
+		{"msg_2", 0, "user", 1, 1, ""},
+		{"", 3, "", 0, 0, "there is no code block 3, the message has 2"},
+		{"msg_4", 0, "", 0, 0, "the selected message has no code blocks"},
+		{"msg_9", 0, "", 0, 0, "the selected message was not found"},
+	}
+	for _, tc := range tests {
+		block, index, total, err := CodeBlockOf(messages, tc.selected, tc.n)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("CodeBlockOf(%q, %d) failed with %v, want %q", tc.selected, tc.n, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || block.Code != tc.code || index != tc.index || total != tc.total {
+			t.Errorf("CodeBlockOf(%q, %d) = %q, %d, %d, %v", tc.selected, tc.n, block.Code, index, total, err)
+		}
+	}
+	// only the response without code is left
+	if _, _, _, err := CodeBlockOf(messages[3:], "", 0); err == nil || err.Error() != "no code blocks in the responses" {
+		t.Errorf("CodeBlockOf without code failed with %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// CompactedMessage is a message that a compaction folded into its summary
+type CompactedMessage struct {
+	ID      string
+	Role    opencode.MessageRole
+	Preview string
+	// Tokens is what the message took in the context, estimated from its
+	// text when the server reported no usage for it
+	Tokens float64
+}
+
+// CompactionReport describes what a compaction removed from the context
+type CompactionReport struct {
+	SummaryID    string
+	Compacted    []CompactedMessage
+	TokensBefore float64
+	TokensAfter  float64
+}
+
+// Saved returns the tokens the compaction removed from the context
+func (r *CompactionReport) Saved() float64 {
+	return max(0, r.TokensBefore-r.TokensAfter)
+}
+
+// SessionCompactedMsg is sent when a compaction requested by the TUI finished
+type SessionCompactedMsg struct {
+	Report *CompactionReport
+}
+
+// ContextTokens returns the tokens in the context after the last message
+// that reported usage, which is what the next prompt is sent with
+func ContextTokens(messages []opencode.Message) float64 {
+	tokens := float64(0)
+	for _, message := range messages {
+		usage := message.Metadata.Assistant.Tokens
+		if usage.Output <= 0 {
+			continue
+		}
+		if message.Metadata.Assistant.Summary {
+			tokens = usage.Output
+			continue
+		}
+		tokens = usage.Input +
+			usage.Cache.Write +
+			usage.Cache.Read +
+			usage.Output +
+			usage.Reasoning
+	}
+	return tokens
+}
+
+// estimateTokens approximates the tokens of text at four bytes per token
+func estimateTokens(text string) float64 {
+	return float64((len(text) + 3) / 4)
+}
+
+// messageText joins the text parts of a message
+func messageText(message opencode.Message) string {
+	var texts []string
+	for _, part := range message.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// messageTokens returns the tokens a message adds to the context
+func messageTokens(message opencode.Message) float64 {
+	usage := message.Metadata.Assistant.Tokens
+	if tokens := usage.Output + usage.Reasoning; tokens > 0 {
+		return tokens
+	}
+	tokens := estimateTokens(messageText(message))
+	for _, part := range message.Parts {
+		if tool, ok := part.AsUnion().(opencode.ToolInvocationPart); ok {
+			tokens += estimateTokens(tool.ToolInvocation.Result)
+		}
+	}
+	return tokens
+}
+
+// NewCompactionReport describes the compaction that produced the summary
+// message summaryID. The messages it covers are those since the previous
+// summary, which the server no longer sends to the model.
+func NewCompactionReport(messages []opencode.Message, summaryID string) (*CompactionReport, bool) {
+	index := -1
+	for i, message := range messages {
+		if message.ID == summaryID && message.Metadata.Assistant.Summary {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, false
+	}
+
+	start := 0
+	for i := index - 1; i >= 0; i-- {
+		if messages[i].Metadata.Assistant.Summary {
+			start = i
+			break
+		}
+	}
+
+	report := &CompactionReport{
+		SummaryID:    summaryID,
+		TokensBefore: ContextTokens(messages[:index]),
+		TokensAfter:  messages[index].Metadata.Assistant.Tokens.Output,
+	}
+	for _, message := range messages[start:index] {
+		preview := strings.Join(strings.Fields(messageText(message)), " ")
+		report.Compacted = append(report.Compacted, CompactedMessage{
+			ID:      message.ID,
+			Role:    message.Role,
+			Preview: preview,
+			Tokens:  messageTokens(message),
+		})
+	}
+	return report, true
+}
+
+// CompactionReport describes the compaction that produced a summary message
+// of the current session
+func (a *App) CompactionReport(summaryID string) (*CompactionReport, bool) {
+	return NewCompactionReport(a.Messages, summaryID)
+}
+
+// CompactSession summarizes the session and reports which messages the
+// summary replaced once the server is done
+func (a *App) CompactSession(ctx context.Context) tea.Cmd {
+	sessionID := a.Session.ID
+	providerID, modelID := a.Provider.ID, a.Model.ID
+	return func() tea.Msg {
+		_, err := a.Client.Session.Summarize(ctx, sessionID, opencode.SessionSummarizeParams{
+			ProviderID: opencode.F(providerID),
+			ModelID:    opencode.F(modelID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to compact session: %w", err)
+		}
+		messages, err := a.ListMessages(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("failed to load the compacted session: %w", err)
+		}
+		for i := len(messages) - 1; i >= 0; i-- {
+			if !messages[i].Metadata.Assistant.Summary {
+				continue
+			}
+			report, _ := NewCompactionReport(messages, messages[i].ID)
+			return SessionCompactedMsg{Report: report}
+		}
+		return fmt.Errorf("failed to compact session: no summary was written")
+	}
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// ContinuationRequest asks the server for a prompt that lets a fresh agent
+// pick up where a session left off
+type ContinuationRequest struct {
+	SessionID string `json:"-"`
+	// Goal overrides the project goal the server infers from the session
+	Goal string `json:"goal,omitempty"`
+	// WorkingDirectory is the directory the next agent should work in
+	WorkingDirectory string `json:"workingDirectory,omitempty"`
+}
+
+// ContinuationResponse is a generated continuation prompt
+type ContinuationResponse struct {
+	Prompt               string   `json:"prompt"`
+	ProjectName          string   `json:"projectName"`
+	CompletionPercentage float64  `json:"completionPercentage"`
+	RemainingTasks       []string `json:"remainingTasks"`
+}
+
+// ContinuationStage is a step of generating a continuation prompt
+type ContinuationStage string
+
+const (
+	ContinuationRequesting ContinuationStage = "requesting"
+	ContinuationWaiting    ContinuationStage = "waiting"
+	ContinuationDone       ContinuationStage = "done"
+)
+
+// ContinuationProgress reports how far along a continuation request is
+type ContinuationProgress struct {
+	Stage   ContinuationStage
+	Elapsed time.Duration
+}
+
+// ContinuationProgressMsg carries progress of a running continuation request
+type ContinuationProgressMsg struct {
+	Progress ContinuationProgress
+}
+
+// ContinuationGeneratedMsg is sent when a continuation request finishes
+type ContinuationGeneratedMsg struct {
+	Response *ContinuationResponse
+	Err      error
+}
+
+// ErrEmptyContinuation is returned when the server generated no prompt
+var ErrEmptyContinuation = errors.New("server returned an empty continuation prompt")
+
+// continuationHeartbeat is how often a waiting request reports progress
+const continuationHeartbeat = 5 * time.Second
+
+// ContinuationService talks to the server's continuation prompt endpoint
+type ContinuationService struct {
+	client    *opencode.Client
+	features  *FeatureFlags
+	heartbeat time.Duration
+
+	mu        sync.Mutex
+	generated map[string]*ContinuationResponse // last prompt generated per session
+}
+
+// NewContinuationService creates a continuation service using the given client.
+// Calls fail with ErrFeatureUnsupported when the server can't generate prompts.
+func NewContinuationService(client *opencode.Client, features *FeatureFlags) *ContinuationService {
+	return &ContinuationService{
+		client:    client,
+		features:  features,
+		heartbeat: continuationHeartbeat,
+		generated: make(map[string]*ContinuationResponse),
+	}
+}
+
+// Last returns the last continuation prompt generated for a session, or nil
+func (s *ContinuationService) Last(sessionID string) *ContinuationResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generated[sessionID]
+}
+
+// Generate requests a continuation prompt for a session. Generation can take
+// a while, so onProgress, if set, is called when the request starts, every
+// few seconds while it is waiting and when it is done. Cancelling ctx aborts
+// the request.
+func (s *ContinuationService) Generate(
+	ctx context.Context,
+	req ContinuationRequest,
+	onProgress func(ContinuationProgress),
+) (*ContinuationResponse, error) {
+	if err := s.features.Require(FeatureContinuation); err != nil {
+		return nil, err
+	}
+	if req.SessionID == "" {
+		return nil, errors.New("no session to continue")
+	}
+	progress := func(p ContinuationProgress) {
+		if onProgress != nil {
+			onProgress(p)
+		}
+	}
+
+	type result struct {
+		response ContinuationResponse
+		err      error
+	}
+	done := make(chan result, 1)
+	start := time.Now()
+	progress(ContinuationProgress{Stage: ContinuationRequesting})
+	go func() {
+		var r result
+		endpoint := fmt.Sprintf("/session/%s/continuation", req.SessionID)
+		r.err = s.client.Post(ctx, endpoint, req, &r.response)
+		done <- r
+	}()
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case r := <-done:
+			if r.err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				return nil, fmt.Errorf("failed to generate continuation prompt: %w", r.err)
+			}
+			if r.response.Prompt == "" {
+				return nil, ErrEmptyContinuation
+			}
+			s.mu.Lock()
+			s.generated[req.SessionID] = &r.response
+			s.mu.Unlock()
+			progress(ContinuationProgress{Stage: ContinuationDone, Elapsed: time.Since(start)})
+			return &r.response, nil
+		case <-ticker.C:
+			progress(ContinuationProgress{Stage: ContinuationWaiting, Elapsed: time.Since(start)})
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+	"github.com/sst/opencode-sdk-go/option"
+)
+
+func newTestContinuationService(t *testing.T, handler http.HandlerFunc, features ...Feature) *ContinuationService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := opencode.NewClient(
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+	flags := NewFeatureFlags(nil)
+	flags.setServer(features)
+	return NewContinuationService(client, flags)
+}
+
+// progressRecorder collects the stages reported to a progress callback
+type progressRecorder struct {
+	mu     sync.Mutex
+	stages []ContinuationStage
+}
+
+func (r *progressRecorder) record(p ContinuationProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stages = append(r.stages, p.Stage)
+}
+
+func (r *progressRecorder) Stages() []ContinuationStage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.stages)
+}
+
+func TestContinuationGenerate(t *testing.T) {
+	var received map[string]any
+	service := newTestContinuationService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/session/ses_1/continuation" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"prompt": "# Instructions for Next dgmo Agent",
+			"projectName": "dgmo",
+			"completionPercentage": 80,
+			"remainingTasks": ["tests", "docs"]
+		}`))
+	}, FeatureContinuation)
+
+	progress := &progressRecorder{}
+	response, err := service.Generate(context.Background(), ContinuationRequest{
+		SessionID: "ses_1",
+		Goal:      "ship it",
+	}, progress.record)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if response.Prompt != "# Instructions for Next dgmo Agent" {
+		t.Errorf("Unexpected prompt %q", response.Prompt)
+	}
+	if response.ProjectName != "dgmo" || response.CompletionPercentage != 80 {
+		t.Errorf("Unexpected project state %+v", response)
+	}
+	if !slices.Equal(response.RemainingTasks, []string{"tests", "docs"}) {
+		t.Errorf("Unexpected remaining tasks %v", response.RemainingTasks)
+	}
+	if received["goal"] != "ship it" {
+		t.Errorf("Expected goal in request body, got %v", received)
+	}
+	if _, ok := received["SessionID"]; ok {
+		t.Errorf("Session ID should only be part of the path, got %v", received)
+	}
+
+	expected := []ContinuationStage{ContinuationRequesting, ContinuationDone}
+	if stages := progress.Stages(); !slices.Equal(stages, expected) {
+		t.Errorf("Expected progress %v, got %v", expected, stages)
+	}
+}
+
+func TestContinuationUnsupported(t *testing.T) {
+	service := newTestContinuationService(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Server should not be called")
+	})
+
+	_, err := service.Generate(context.Background(), ContinuationRequest{SessionID: "ses_1"}, nil)
+	if !errors.Is(err, ErrFeatureUnsupported) {
+		t.Errorf("Expected ErrFeatureUnsupported, got %v", err)
+	}
+}
+
+func TestContinuationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "server error", status: http.StatusInternalServerError, body: `{"error":"boom"}`},
+		{name: "empty prompt", status: http.StatusOK, body: `{"prompt":""}`, wantErr: ErrEmptyContinuation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestContinuationService(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}, FeatureContinuation)
+
+			response, err := service.Generate(context.Background(), ContinuationRequest{SessionID: "ses_1"}, nil)
+			if err == nil {
+				t.Fatalf("Expected an error, got %+v", response)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestContinuationCancel(t *testing.T) {
+	started := make(chan struct{})
+	service := newTestContinuationService(t, func(w http.ResponseWriter, r *http.Request) {
+		// the server only notices the client going away once the body is read
+		io.Copy(io.Discard, r.Body)
+		close(started)
+		<-r.Context().Done()
+	}, FeatureContinuation)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := service.Generate(ctx, ContinuationRequest{SessionID: "ses_1"}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestContinuationHeartbeat(t *testing.T) {
+	release := make(chan struct{})
+	service := newTestContinuationService(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"prompt":"continue"}`))
+	}, FeatureContinuation)
+	service.heartbeat = 10 * time.Millisecond
+
+	progress := &progressRecorder{}
+	go func() {
+		// release the response once the service reported that it is waiting
+		for !slices.Contains(progress.Stages(), ContinuationWaiting) {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+	}()
+
+	if _, err := service.Generate(context.Background(), ContinuationRequest{SessionID: "ses_1"}, progress.record); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	stages := progress.Stages()
+	if stages[0] != ContinuationRequesting || stages[len(stages)-1] != ContinuationDone {
+		t.Errorf("Unexpected progress %v", stages)
+	}
+}
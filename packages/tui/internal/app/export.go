@@ -0,0 +1,93 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// ExportFormat is the file format of a session export
+type ExportFormat string
+
+const (
+	ExportMarkdown ExportFormat = "md"
+	ExportJSON     ExportFormat = "json"
+	// ExportBundle is a portable tarball, see ExportBundle
+	ExportBundle ExportFormat = "bundle"
+)
+
+// ExportSession writes the messages of the current session to a new file in
+// dir and returns its path
+func (a *App) ExportSession(dir string, format ExportFormat) (string, error) {
+	if a.Session == nil || a.Session.ID == "" {
+		return "", fmt.Errorf("no session to export")
+	}
+
+	var content []byte
+	switch format {
+	case ExportMarkdown:
+		content = []byte(exportMarkdown(a.Session, a.Messages))
+	case ExportJSON:
+		encoded, err := json.MarshalIndent(rawMessages(a.Messages), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode messages: %w", err)
+		}
+		content = encoded
+	default:
+		return "", fmt.Errorf("unsupported export format %q", format)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create exports directory %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("dgmo-%s-%s.%s", a.Session.ID, time.Now().Format("20060102-150405"), format)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write export %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// exportMarkdown renders the text and tool calls of a transcript as markdown
+func exportMarkdown(session *opencode.Session, messages []opencode.Message) string {
+	var b strings.Builder
+	title := session.Title
+	if title == "" {
+		title = session.ID
+	}
+	fmt.Fprintf(&b, "# %s\n", title)
+
+	// the variables the bash tool ran with, secrets masked
+	if vars := SessionEnv(session); len(vars) > 0 {
+		b.WriteString("\n## Environment\n\n")
+		for _, v := range vars {
+			fmt.Fprintf(&b, "- `%s=%s`\n", v.Name, MaskedEnvValue(v))
+		}
+	}
+
+	for _, message := range messages {
+		role := "User"
+		if message.Role == opencode.MessageRoleAssistant {
+			role = "Assistant"
+		}
+		created := time.UnixMilli(int64(message.Metadata.Time.Created)).Local().Format(time.DateTime)
+		fmt.Fprintf(&b, "\n## %s\n\n_%s_\n", role, created)
+
+		for _, part := range message.Parts {
+			switch part := part.AsUnion().(type) {
+			case opencode.TextPart:
+				if text := strings.TrimSpace(part.Text); text != "" {
+					fmt.Fprintf(&b, "\n%s\n", text)
+				}
+			case opencode.ToolInvocationPart:
+				fmt.Fprintf(&b, "\n- tool `%s`\n", part.ToolInvocation.ToolName)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// Feature names an optional subsystem that depends on server support
+type Feature string
+
+const (
+	FeatureCheckpoints  Feature = "checkpoints"
+	FeatureTasks        Feature = "tasks"
+	FeatureMCP          Feature = "mcp"
+	FeatureContinuation Feature = "continuation"
+	FeaturePermissions  Feature = "permissions"
+	FeatureRename       Feature = "rename"
+	FeatureInstructions Feature = "instructions"
+	FeaturePresets      Feature = "presets"
+	FeaturePatch        Feature = "patch"
+	FeatureTruncate     Feature = "truncate"
+	FeatureEnv          Feature = "env"
+	FeatureBranch       Feature = "branch"
+	FeatureAnalysis     Feature = "analysis"
+	FeatureTrust        Feature = "trust"
+)
+
+// ErrFeatureUnsupported is returned when the server does not advertise a feature
+var ErrFeatureUnsupported = errors.New("not supported by server")
+
+// ErrFeatureDisabled is returned when a feature is turned off in the tui config
+var ErrFeatureDisabled = errors.New("disabled in config")
+
+// legacyCapabilities are assumed for servers that predate the capabilities
+// handshake. The task event server shipped before it, everything else did not.
+var legacyCapabilities = []Feature{FeatureTasks}
+
+// FeatureFlags combines the client config with the capabilities advertised
+// by the server to decide which optional subsystems are available
+type FeatureFlags struct {
+	mu        sync.RWMutex
+	overrides map[string]bool
+	server    map[Feature]bool
+}
+
+// NewFeatureFlags creates feature flags with the given client overrides.
+// Until Probe succeeds only the legacy capabilities are assumed.
+func NewFeatureFlags(overrides map[string]bool) *FeatureFlags {
+	f := &FeatureFlags{overrides: overrides}
+	f.setServer(legacyCapabilities)
+	return f
+}
+
+func (f *FeatureFlags) setServer(features []Feature) {
+	server := make(map[Feature]bool, len(features))
+	for _, feature := range features {
+		server[feature] = true
+	}
+	f.mu.Lock()
+	f.server = server
+	f.mu.Unlock()
+}
+
+// Probe asks the server which features it supports
+func (f *FeatureFlags) Probe(ctx context.Context, client *opencode.Client) error {
+	var capabilities struct {
+		Features []Feature `json:"features"`
+	}
+	err := client.Get(ctx, "/capabilities", nil, &capabilities)
+	var apiErr *opencode.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		slog.Info("Server has no capabilities endpoint, assuming legacy features")
+		f.setServer(legacyCapabilities)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get server capabilities: %w", err)
+	}
+	f.setServer(capabilities.Features)
+	slog.Info("Server capabilities", "features", capabilities.Features)
+	return nil
+}
+
+// Require returns nil if the feature can be used, or an error explaining why not
+func (f *FeatureFlags) Require(feature Feature) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if enabled, ok := f.overrides[string(feature)]; ok && !enabled {
+		return fmt.Errorf("%s %w", feature, ErrFeatureDisabled)
+	}
+	if !f.server[feature] {
+		return fmt.Errorf("%s %w", feature, ErrFeatureUnsupported)
+	}
+	return nil
+}
+
+// Enabled reports whether the feature can be used
+func (f *FeatureFlags) Enabled(feature Feature) bool {
+	return f.Require(feature) == nil
+}
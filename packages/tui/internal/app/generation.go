@@ -0,0 +1,59 @@
+package app
+
+import (
+	"encoding/json"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// Generation is the output of a response and what it costs. While the
+// response generates the figures are estimated from its text, once it
+// completes they are the ones the provider reported.
+type Generation struct {
+	Tokens float64
+	Cost   float64
+	Final  bool
+}
+
+// CurrentGeneration returns the output of the last response of the session,
+// false when the session has none
+func (a *App) CurrentGeneration() (Generation, bool) {
+	if len(a.Messages) == 0 {
+		return Generation{}, false
+	}
+	message := a.Messages[len(a.Messages)-1]
+	if message.Role != opencode.MessageRoleAssistant {
+		return Generation{}, false
+	}
+	var outputCost float64
+	if a.Model != nil {
+		outputCost = a.Model.Cost.Output
+	}
+	return generationOf(message, outputCost), true
+}
+
+// generationOf measures the output of a response, outputCost is the price of
+// a million output tokens
+func generationOf(message opencode.Message, outputCost float64) Generation {
+	if message.Metadata.Time.Completed != 0 {
+		usage := message.Metadata.Assistant.Tokens
+		return Generation{
+			Tokens: usage.Output + usage.Reasoning,
+			Cost:   message.Metadata.Assistant.Cost,
+			Final:  true,
+		}
+	}
+	tokens := estimateTokens(messageText(message))
+	for _, part := range message.Parts {
+		if tool, ok := part.AsUnion().(opencode.ToolInvocationPart); ok && tool.ToolInvocation.Args != nil {
+			// the model writes the arguments of its tool calls
+			if args, err := json.Marshal(tool.ToolInvocation.Args); err == nil {
+				tokens += estimateTokens(string(args))
+			}
+		}
+	}
+	return Generation{
+		Tokens: tokens,
+		Cost:   tokens * outputCost / 1_000_000,
+	}
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestGenerationOf(t *testing.T) {
+	var message opencode.Message
+	data := `{"id":"msg_1","role":"assistant","parts":[{"type":"text","text":"` + strings.Repeat("a", 400) + `"}],` +
+		`"metadata":{"sessionID":"ses_1","time":{"created":1},"assistant":{"cost":0,"tokens":{"output":0,"reasoning":0}}}}`
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		t.Fatal(err)
+	}
+
+	running := generationOf(message, 10)
+	if running.Final || running.Tokens != 100 || running.Cost != 0.001 {
+		t.Errorf("running generation = %+v, want an estimate of 100 tokens for $0.001", running)
+	}
+
+	message.Metadata.Time.Completed = 2
+	message.Metadata.Assistant.Tokens.Output = 80
+	message.Metadata.Assistant.Tokens.Reasoning = 40
+	message.Metadata.Assistant.Cost = 0.25
+	done := generationOf(message, 10)
+	if !done.Final || done.Tokens != 120 || done.Cost != 0.25 {
+		t.Errorf("completed generation = %+v, want the reported 120 tokens for $0.25", done)
+	}
+}
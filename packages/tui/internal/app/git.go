@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/git"
+)
+
+// GitRefreshInterval is how often the git status of the project is re-read
+const GitRefreshInterval = 5 * time.Second
+
+// GitStatusMsg carries a fresh git status of the project, nil if it could
+// not be read
+type GitStatusMsg struct {
+	Status *git.Status
+}
+
+// RefreshGit reads the git status of the project root. It does nothing when
+// the project is not a git repository.
+func (a *App) RefreshGit() tea.Cmd {
+	if !a.Info.Git {
+		return nil
+	}
+	root := a.Info.Path.Root
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		status, err := git.Read(ctx, root)
+		if err != nil && !errors.Is(err, git.ErrNotRepository) {
+			slog.Debug("Failed to read git status", "error", err)
+		}
+		return GitStatusMsg{Status: status}
+	}
+}
+
+// DirtyWarning returns a warning when the working tree has uncommitted
+// changes that an action could mix with or overwrite, and "" otherwise
+func (a *App) DirtyWarning() string {
+	if a.Git == nil || a.Git.Clean() {
+		return ""
+	}
+	files := "files"
+	if a.Git.Dirty == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("The working tree has %d uncommitted %s", a.Git.Dirty, files)
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/image"
+)
+
+const (
+	// imagePreviewMaxBytes caps the size of a downloaded image
+	imagePreviewMaxBytes = 5 << 20
+	imagePreviewTimeout  = 10 * time.Second
+	// imagePreviewWidth and imagePreviewRows bound the rendered preview
+	imagePreviewWidth = 48
+	imagePreviewRows  = 16
+)
+
+// ImagePreviewLoadedMsg is sent when the preview of an image link was
+// fetched, or failed to be
+type ImagePreviewLoadedMsg struct {
+	URL string
+}
+
+type imagePreview struct {
+	rendered string
+	done     bool
+}
+
+// ImagePreviews fetches and caches small inline previews of the image links
+// in assistant messages. Only links to allowed domains are fetched, and
+// nothing is fetched unless previews are enabled.
+type ImagePreviews struct {
+	mu       sync.Mutex
+	enabled  bool
+	domains  []string
+	client   *http.Client
+	previews map[string]*imagePreview // by URL, failed fetches stay empty
+	queued   []string
+}
+
+// NewImagePreviews creates the preview cache. domains lists the hosts
+// previews are fetched from, subdomains included.
+func NewImagePreviews(enabled bool, domains []string) *ImagePreviews {
+	return &ImagePreviews{
+		enabled:  enabled,
+		domains:  domains,
+		client:   &http.Client{Timeout: imagePreviewTimeout},
+		previews: make(map[string]*imagePreview),
+	}
+}
+
+// Allowed reports whether previews are enabled and link is on an allowed
+// domain
+func (p *ImagePreviews) Allowed(link string) bool {
+	if !p.enabled {
+		return false
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range p.domains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Preview returns the rendered preview of an image link. A link seen for
+// the first time is queued for Fetch.
+func (p *ImagePreviews) Preview(link string) (string, bool) {
+	if !p.Allowed(link) {
+		return "", false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	preview, ok := p.previews[link]
+	if !ok {
+		p.previews[link] = &imagePreview{}
+		p.queued = append(p.queued, link)
+		return "", false
+	}
+	return preview.rendered, preview.done && preview.rendered != ""
+}
+
+// Fetch downloads the queued previews in the background
+func (p *ImagePreviews) Fetch() tea.Cmd {
+	p.mu.Lock()
+	queued := p.queued
+	p.queued = nil
+	p.mu.Unlock()
+
+	var cmds []tea.Cmd
+	for _, link := range queued {
+		cmds = append(cmds, func() tea.Msg {
+			rendered, err := image.RemotePreview(
+				context.Background(),
+				p.client,
+				link,
+				imagePreviewMaxBytes,
+				imagePreviewWidth,
+				imagePreviewRows,
+			)
+			if err != nil {
+				slog.Warn("Failed to fetch image preview", "url", link, "error", err)
+			}
+			p.mu.Lock()
+			p.previews[link] = &imagePreview{rendered: rendered, done: true}
+			p.mu.Unlock()
+			return ImagePreviewLoadedMsg{URL: link}
+		})
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// SessionIntegrity is how a session on the server compares to what the
+// client last knew of it
+type SessionIntegrity int
+
+const (
+	SessionIntact SessionIntegrity = iota
+	// SessionAdvanced means messages were added elsewhere
+	SessionAdvanced
+	// SessionTruncated means known messages are gone, usually because the
+	// session was compacted elsewhere
+	SessionTruncated
+	// SessionDeleted means the session no longer exists
+	SessionDeleted
+)
+
+// SessionIntegrityMsg is sent once the last session was checked against the
+// server. Session is nil when it was deleted.
+type SessionIntegrityMsg struct {
+	Snapshot  config.SessionSnapshot
+	Session   *opencode.Session
+	Integrity SessionIntegrity
+	Notice    string
+}
+
+// RememberSession records the open session so the next start can offer to
+// resume it
+func (a *App) RememberSession() {
+	if a.Session == nil || a.Session.ID == "" {
+		a.State.LastSession = nil
+		return
+	}
+	snapshot := &config.SessionSnapshot{
+		ID:       a.Session.ID,
+		Title:    a.Session.Title,
+		Messages: len(a.Messages),
+	}
+	if len(a.Messages) > 0 {
+		snapshot.LastMessageID = a.Messages[len(a.Messages)-1].ID
+	}
+	a.State.LastSession = snapshot
+}
+
+// CompareSnapshot checks the messages on the server against a snapshot and
+// describes any difference
+func CompareSnapshot(snapshot config.SessionSnapshot, messages []opencode.Message) (SessionIntegrity, string) {
+	known := slices.IndexFunc(messages, func(m opencode.Message) bool {
+		return m.ID == snapshot.LastMessageID
+	})
+	switch {
+	case snapshot.LastMessageID != "" && known < 0:
+		return SessionTruncated, "its history was compacted or rewritten elsewhere, showing the server copy"
+	case len(messages) < snapshot.Messages:
+		return SessionTruncated, fmt.Sprintf("%d messages were removed elsewhere, showing the server copy", snapshot.Messages-len(messages))
+	case len(messages) > snapshot.Messages:
+		return SessionAdvanced, fmt.Sprintf("%d new messages since you left", len(messages)-snapshot.Messages)
+	}
+	return SessionIntact, ""
+}
+
+// CheckLastSession verifies the session open on the last exit against the
+// server, so it is never offered or shown from stale knowledge
+func (a *App) CheckLastSession() tea.Cmd {
+	if a.State.LastSession == nil || a.State.LastSession.ID == "" {
+		return nil
+	}
+	snapshot := *a.State.LastSession
+	return func() tea.Msg {
+		ctx := context.Background()
+		sessions, err := a.ListSessions(ctx)
+		if err != nil {
+			slog.Warn("Failed to check the last session", "session", snapshot.ID, "error", err)
+			return nil
+		}
+		idx := slices.IndexFunc(sessions, func(s opencode.Session) bool {
+			return s.ID == snapshot.ID
+		})
+		if idx < 0 {
+			return SessionIntegrityMsg{
+				Snapshot:  snapshot,
+				Integrity: SessionDeleted,
+				Notice:    fmt.Sprintf("Your last session %q was deleted on the server", snapshot.Title),
+			}
+		}
+		session := sessions[idx]
+		messages, err := a.ListMessages(ctx, snapshot.ID)
+		if err != nil {
+			slog.Warn("Failed to check the last session", "session", snapshot.ID, "error", err)
+			return nil
+		}
+		integrity, notice := CompareSnapshot(snapshot, messages)
+		if notice != "" {
+			notice = fmt.Sprintf("Your last session %q changed: %s", session.Title, notice)
+		}
+		return SessionIntegrityMsg{
+			Snapshot:  snapshot,
+			Session:   &session,
+			Integrity: integrity,
+			Notice:    notice,
+		}
+	}
+}
+
+// ForgetSession drops the local knowledge of a session deleted elsewhere
+func (a *App) ForgetSession(sessionID string) {
+	if a.State.LastSession != nil && a.State.LastSession.ID == sessionID {
+		a.State.LastSession = nil
+	}
+	a.SessionStack = slices.DeleteFunc(a.SessionStack, func(id string) bool {
+		return id == sessionID
+	})
+	a.setSessionMeta(sessionID, config.SessionMeta{})
+	a.SaveState()
+}
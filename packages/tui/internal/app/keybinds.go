@@ -0,0 +1,60 @@
+package app
+
+import (
+	"errors"
+	"maps"
+
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/config"
+)
+
+// DefaultKeybindings returns the keybindings of a command without the
+// project overlay, which is what resetting a keybind goes back to
+func (a *App) DefaultKeybindings(name commands.CommandName) []commands.Keybinding {
+	registry := commands.LoadFromConfig(a.Config)
+	a.registerPluginCommands(registry)
+	return registry[name].Keybindings
+}
+
+// KeybindOverridden reports whether the project overlay rebinds a command
+func (a *App) KeybindOverridden(name commands.CommandName) bool {
+	return a.Project != nil && a.Project.Keybinds[string(name)] != ""
+}
+
+// KeybindConflicts returns the other commands already bound to a keybinding
+func (a *App) KeybindConflicts(name commands.CommandName, binding commands.Keybinding) []commands.Command {
+	var conflicts []commands.Command
+	for _, command := range a.Commands.Sorted() {
+		if command.Name == name {
+			continue
+		}
+		for _, existing := range command.Keybindings {
+			if existing == binding {
+				conflicts = append(conflicts, command)
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// SetKeybind rebinds a command in the project config file and applies it
+// right away. An empty keybind resets the command to its default binding.
+func (a *App) SetKeybind(name commands.CommandName, keybind string) error {
+	if a.Restricted() {
+		return errors.New("the project config is ignored in a restricted workspace, /trust it first")
+	}
+	keybinds := map[string]string{}
+	if a.Project != nil {
+		maps.Copy(keybinds, a.Project.Keybinds)
+	}
+	if keybind == "" {
+		delete(keybinds, string(name))
+	} else {
+		keybinds[string(name)] = keybind
+	}
+	if err := config.SaveProjectKeybinds(a.ProjectConfigPath(), keybinds); err != nil {
+		return err
+	}
+	return a.LoadProjectConfig()
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sst/dgmo/internal/layout"
+)
+
+const (
+	// DefaultMaxWidth is the content width when none is configured
+	DefaultMaxWidth = 80
+	// minMaxWidth keeps a configured width readable
+	minMaxWidth = 40
+)
+
+// maxWidthSteps are the widths the width command cycles through, 0 for the
+// default and -1 for the whole terminal
+var maxWidthSteps = []int{0, 120, -1}
+
+// ZoomLevels are the zoom percentages zooming in and out steps through
+var ZoomLevels = []int{50, 75, 90, 100, 110, 125, 150, 200}
+
+const (
+	// DefaultZoom is the zoom when none is configured
+	DefaultZoom = 100
+	minZoom     = 50
+	maxZoom     = 200
+)
+
+// LayoutChangedMsg is sent after the content width, density or zoom changed
+type LayoutChangedMsg struct{}
+
+// ContentWidth returns the width of the centered column holding the
+// transcript and the editor in a terminal of the given width
+func (a *App) ContentWidth(width int) int {
+	switch {
+	case a.State.MaxWidth < 0:
+		return width
+	case a.State.MaxWidth == 0:
+		return min(width, DefaultMaxWidth*a.Zoom()/DefaultZoom)
+	}
+	return min(width, max(a.State.MaxWidth, minMaxWidth))
+}
+
+// Density returns the configured transcript density
+func (a *App) Density() layout.Density {
+	if layout.Density(a.State.Density) == layout.DensityCompact {
+		return layout.DensityCompact
+	}
+	return layout.DensityComfortable
+}
+
+// Zoom returns the configured zoom in percent
+func (a *App) Zoom() int {
+	if a.State.Zoom == 0 {
+		return DefaultZoom
+	}
+	return max(minZoom, min(maxZoom, a.State.Zoom))
+}
+
+// StepZoom returns the zoom level after zoom, the next larger one for a
+// positive step and the next smaller one otherwise. It stays put at the
+// ends.
+func StepZoom(zoom, step int) int {
+	if step > 0 {
+		if i := slices.IndexFunc(ZoomLevels, func(level int) bool { return level > zoom }); i >= 0 {
+			return ZoomLevels[i]
+		}
+		return ZoomLevels[len(ZoomLevels)-1]
+	}
+	for _, level := range slices.Backward(ZoomLevels) {
+		if level < zoom {
+			return level
+		}
+	}
+	return ZoomLevels[0]
+}
+
+// ParseZoom reads a zoom given as a percentage, "in", "out" or "reset",
+// relative to the current zoom
+func ParseZoom(value string, current int) (int, error) {
+	switch value {
+	case "in":
+		return StepZoom(current, 1), nil
+	case "out":
+		return StepZoom(current, -1), nil
+	case "reset", "default":
+		return DefaultZoom, nil
+	}
+	zoom, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || zoom < minZoom || zoom > maxZoom {
+		return 0, fmt.Errorf("zoom must be in, out, reset or %d%% to %d%%", minZoom, maxZoom)
+	}
+	return zoom, nil
+}
+
+// NextMaxWidth returns the width following the configured one in the
+// widths the width command cycles through
+func (a *App) NextMaxWidth() int {
+	for i, step := range maxWidthSteps {
+		if step == a.State.MaxWidth {
+			return maxWidthSteps[(i+1)%len(maxWidthSteps)]
+		}
+	}
+	return maxWidthSteps[0]
+}
+
+// ParseMaxWidth reads a width given as columns, "full" or "default"
+func ParseMaxWidth(value string) (int, error) {
+	switch value {
+	case "default":
+		return 0, nil
+	case "full":
+		return -1, nil
+	}
+	columns, err := strconv.Atoi(value)
+	if err != nil || columns < minMaxWidth {
+		return 0, fmt.Errorf("width must be full, default or at least %d columns", minMaxWidth)
+	}
+	return columns, nil
+}
+
+// FormatMaxWidth describes a configured width
+func FormatMaxWidth(width int) string {
+	switch {
+	case width < 0:
+		return "full"
+	case width == 0:
+		return strconv.Itoa(DefaultMaxWidth)
+	}
+	return strconv.Itoa(width)
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/sst/dgmo/internal/config"
+)
+
+func TestContentWidth(t *testing.T) {
+	tests := []struct {
+		maxWidth int
+		terminal int
+		want     int
+	}{
+		{0, 200, DefaultMaxWidth},
+		{0, 60, 60},
+		{120, 200, 120},
+		{120, 100, 100},
+		{10, 200, minMaxWidth},
+		{-1, 200, 200},
+	}
+	for _, test := range tests {
+		a := &App{State: &config.State{MaxWidth: test.maxWidth}}
+		if got := a.ContentWidth(test.terminal); got != test.want {
+			t.Errorf("max width %d in %d columns = %d, want %d", test.maxWidth, test.terminal, got, test.want)
+		}
+	}
+}
+
+func TestParseMaxWidth(t *testing.T) {
+	for value, want := range map[string]int{"full": -1, "default": 0, "100": 100} {
+		if got, err := ParseMaxWidth(value); err != nil || got != want {
+			t.Errorf("ParseMaxWidth(%q) = %d, %v, want %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"wide", "20"} {
+		if _, err := ParseMaxWidth(value); err == nil {
+			t.Errorf("ParseMaxWidth(%q) succeeded", value)
+		}
+	}
+}
+
+func TestZoom(t *testing.T) {
+	a := &App{State: &config.State{Zoom: 125}}
+	if got := a.ContentWidth(200); got != 100 {
+		t.Errorf("default width at 125%% = %d, want 100", got)
+	}
+	a.State.MaxWidth = 120
+	if got := a.ContentWidth(200); got != 120 {
+		t.Errorf("a configured width should not zoom, got %d", got)
+	}
+
+	if got := StepZoom(100, 1); got != 110 {
+		t.Errorf("zoom in from 100 = %d", got)
+	}
+	if got := StepZoom(100, -1); got != 90 {
+		t.Errorf("zoom out from 100 = %d", got)
+	}
+	if got := StepZoom(130, -1); got != 125 {
+		t.Errorf("zoom out from 130 = %d", got)
+	}
+	if got := StepZoom(200, 1); got != 200 {
+		t.Errorf("zoom in from the top = %d", got)
+	}
+	if got := StepZoom(50, -1); got != 50 {
+		t.Errorf("zoom out from the bottom = %d", got)
+	}
+
+	for value, want := range map[string]int{"in": 125, "out": 100, "reset": 100, "150%": 150, "80": 80} {
+		if got, err := ParseZoom(value, 110); err != nil || got != want {
+			t.Errorf("ParseZoom(%q) = %d, %v, want %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"big", "20", "300%"} {
+		if _, err := ParseZoom(value, 100); err == nil {
+			t.Errorf("ParseZoom(%q) succeeded", value)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+const (
+	// MCPLatencyThreshold is the p95 latency above which a server is degraded
+	MCPLatencyThreshold = 10 * time.Second
+	// MCPErrorRateThreshold is the share of failed calls above which a server is degraded
+	MCPErrorRateThreshold = 0.2
+	// MCPMinCalls is how many calls a server needs before it can be degraded
+	MCPMinCalls = 5
+	// mcpMaxSamples caps the latencies kept per server
+	mcpMaxSamples = 500
+)
+
+// MCPServerStats is a snapshot of the latency and errors of one MCP server
+type MCPServerStats struct {
+	Name     string
+	Calls    int
+	Errors   int
+	P50      time.Duration
+	P95      time.Duration
+	Degraded bool
+}
+
+// ErrorRate returns the share of calls that failed
+func (s MCPServerStats) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+// MCPAlert reports a server that degraded or recovered
+type MCPAlert struct {
+	Server   string
+	Degraded bool
+	Reason   string
+}
+
+type mcpServer struct {
+	latencies []time.Duration
+	calls     int
+	errors    int
+	degraded  bool
+}
+
+// MCPStats tracks the latency of MCP tool calls per server over the session.
+// MCP tools are named after their server, "<server>_<tool>".
+type MCPStats struct {
+	names   []string
+	servers map[string]*mcpServer
+	seen    map[string]bool
+}
+
+// NewMCPStats creates stats for the MCP servers in the config
+func NewMCPStats(config *opencode.Config) *MCPStats {
+	s := &MCPStats{
+		servers: make(map[string]*mcpServer),
+		seen:    make(map[string]bool),
+	}
+	if config == nil {
+		return s
+	}
+	for name := range config.Mcp {
+		s.names = append(s.names, name)
+		s.servers[name] = &mcpServer{}
+	}
+	// longest first so that "foo_bar" wins over "foo" when matching tool names
+	slices.SortFunc(s.names, func(a, b string) int {
+		return len(b) - len(a)
+	})
+	return s
+}
+
+// server returns the MCP server that provides the given tool
+func (s *MCPStats) server(toolName string) string {
+	for _, name := range s.names {
+		if strings.HasPrefix(toolName, name+"_") {
+			return name
+		}
+	}
+	return ""
+}
+
+// Observe records the finished MCP calls in message and returns alerts for
+// the servers whose health changed
+func (s *MCPStats) Observe(message opencode.Message) []MCPAlert {
+	if len(s.names) == 0 {
+		return nil
+	}
+	var changed []string
+	for _, part := range message.Parts {
+		toolCall, ok := part.AsUnion().(opencode.ToolInvocationPart)
+		if !ok || s.seen[toolCall.ToolInvocation.ToolCallID] {
+			continue
+		}
+		name := s.server(toolCall.ToolInvocation.ToolName)
+		if name == "" {
+			continue
+		}
+		metadata, ok := message.Metadata.Tool[toolCall.ToolInvocation.ToolCallID]
+		if !ok || metadata.Time.End == 0 {
+			continue
+		}
+		s.seen[toolCall.ToolInvocation.ToolCallID] = true
+
+		server := s.servers[name]
+		server.calls++
+		if failed, _ := metadata.ExtraFields["error"].(bool); failed {
+			server.errors++
+		}
+		latency := time.Duration(metadata.Time.End-metadata.Time.Start) * time.Millisecond
+		server.latencies = append(server.latencies, latency)
+		if len(server.latencies) > mcpMaxSamples {
+			server.latencies = server.latencies[1:]
+		}
+		if !slices.Contains(changed, name) {
+			changed = append(changed, name)
+		}
+	}
+
+	var alerts []MCPAlert
+	for _, name := range changed {
+		server := s.servers[name]
+		stats := server.stats(name)
+		if stats.Degraded == server.degraded {
+			continue
+		}
+		server.degraded = stats.Degraded
+		alerts = append(alerts, MCPAlert{
+			Server:   name,
+			Degraded: stats.Degraded,
+			Reason:   stats.reason(),
+		})
+	}
+	return alerts
+}
+
+// Servers returns the stats of every configured MCP server, sorted by name
+func (s *MCPStats) Servers() []MCPServerStats {
+	stats := make([]MCPServerStats, 0, len(s.servers))
+	for name, server := range s.servers {
+		stats = append(stats, server.stats(name))
+	}
+	slices.SortFunc(stats, func(a, b MCPServerStats) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return stats
+}
+
+// Degraded returns the names of the servers that are currently degraded
+func (s *MCPStats) Degraded() []string {
+	var names []string
+	for _, stats := range s.Servers() {
+		if stats.Degraded {
+			names = append(names, stats.Name)
+		}
+	}
+	return names
+}
+
+func (m *mcpServer) stats(name string) MCPServerStats {
+	stats := MCPServerStats{
+		Name:   name,
+		Calls:  m.calls,
+		Errors: m.errors,
+		P50:    percentile(m.latencies, 0.50),
+		P95:    percentile(m.latencies, 0.95),
+	}
+	stats.Degraded = stats.Calls >= MCPMinCalls &&
+		(stats.P95 > MCPLatencyThreshold || stats.ErrorRate() > MCPErrorRateThreshold)
+	return stats
+}
+
+func (s MCPServerStats) reason() string {
+	if s.ErrorRate() > MCPErrorRateThreshold {
+		return fmt.Sprintf("%.0f%% of calls failing", s.ErrorRate()*100)
+	}
+	return fmt.Sprintf("p95 latency %s", s.P95.Round(100*time.Millisecond))
+}
+
+// percentile returns the nearest-rank percentile p of latencies
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/sst/dgmo/internal/mirror"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// MirrorMessage appends a copy of a received message to the local mirror
+func (a *App) MirrorMessage(message opencode.Message) {
+	raw := []byte(message.JSON.RawJSON())
+	if len(raw) == 0 {
+		encoded, err := json.Marshal(message)
+		if err != nil {
+			slog.Error("Failed to encode message for the mirror", "error", err)
+			return
+		}
+		raw = encoded
+	}
+	a.Mirror.Append(message.Metadata.SessionID, message.ID, raw)
+}
+
+// RecoverMessages rebuilds the transcript of a session from the local
+// mirror, for when the server can't list its messages
+func (a *App) RecoverMessages(sessionID string) ([]opencode.Message, error) {
+	raws, err := a.Mirror.Load(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]opencode.Message, 0, len(raws))
+	for _, raw := range raws {
+		var message opencode.Message
+		if err := json.Unmarshal(raw, &message); err != nil {
+			slog.Warn("Skipping unreadable mirrored message", "error", err)
+			continue
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return nil, mirror.ErrNoMirror
+	}
+	return messages, nil
+}
+
+// TranscriptRecoveredMsg carries a transcript rebuilt from the local mirror
+type TranscriptRecoveredMsg struct {
+	Session  *opencode.Session
+	Messages []opencode.Message
+}
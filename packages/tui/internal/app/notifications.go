@@ -0,0 +1,87 @@
+package app
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/dgmo/internal/notify"
+)
+
+// NotificationPriority decides which notifications get through quiet hours
+type NotificationPriority int
+
+const (
+	NotificationNormal NotificationPriority = iota
+	// NotificationCritical is for failures, which surface during quiet hours
+	NotificationCritical
+)
+
+// QuietHoursActive reports whether t falls into one of the configured quiet
+// hours. Invalid rules are logged and ignored.
+func (a *App) QuietHoursActive(t time.Time) bool {
+	for _, rule := range a.State.QuietHours {
+		quiet, err := notify.ParseQuietHours(rule.Days, rule.Start, rule.End)
+		if err != nil {
+			slog.Warn("Ignoring invalid quiet hours", "start", rule.Start, "end", rule.End, "error", err)
+			continue
+		}
+		if quiet.Active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify sends a desktop notification, unless notifications are off or it
+// is not critical and quiet hours are active
+func (a *App) Notify(n notify.Notification, priority NotificationPriority) tea.Cmd {
+	if !a.State.Notifications {
+		return nil
+	}
+	if priority < NotificationCritical && a.QuietHoursActive(time.Now()) {
+		slog.Debug("Notification held back during quiet hours", "title", n.Title)
+		return nil
+	}
+	return func() tea.Msg {
+		err := notify.Send(n)
+		if err == nil {
+			return nil
+		}
+		slog.Debug("Native notification failed, using terminal fallback", "error", err)
+		// written raw to the program's output, as tea.SetClipboard does,
+		// since a line printed above the frame is dropped in the alt screen
+		return tea.RawMsg{Msg: notify.Sequence(n)}
+	}
+}
+
+// defaultIdleReminderAfter is how long a session waits for a reply before a
+// reminder, unless configured otherwise
+const defaultIdleReminderAfter = 10 * time.Minute
+
+// IdleReminderAfter returns how long a session waits for a reply before a
+// reminder, 0 when reminders are off
+func (a *App) IdleReminderAfter() time.Duration {
+	switch {
+	case a.State.IdleReminderAfter < 0:
+		return 0
+	case a.State.IdleReminderAfter > 0:
+		return time.Duration(a.State.IdleReminderAfter) * time.Minute
+	}
+	return defaultIdleReminderAfter
+}
+
+// formatQuietHours lists quiet hours as "22:00-08:00 mon,tue"
+func formatQuietHours(rules []config.QuietHoursRule) string {
+	var spans []string
+	for _, rule := range rules {
+		span := rule.Start + "-" + rule.End
+		if len(rule.Days) > 0 {
+			span += " " + strings.Join(rule.Days, ",")
+		}
+		spans = append(spans, span)
+	}
+	return strings.Join(spans, "; ")
+}
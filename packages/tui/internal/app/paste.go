@@ -0,0 +1,72 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sst/dgmo/internal/config"
+)
+
+// SanitizePaste cleans up pasted text before it goes into the editor. Line
+// endings become \n and escape sequences and other control characters are
+// dropped, so colored shell output pastes as plain text. Trailing whitespace
+// is trimmed and tabs expanded when the paste settings ask for it.
+func SanitizePaste(text string, settings config.PasteConfig) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = ansi.Strip(text)
+	text = strings.Map(func(r rune) rune {
+		if r < ' ' && r != '\n' && r != '\t' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if settings.TabWidth > 0 {
+			line = expandTabs(line, settings.TabWidth)
+		}
+		if settings.TrimTrailingSpace {
+			line = strings.TrimRight(line, " \t")
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// expandTabs replaces the tabs of a line with spaces up to the next tab stop
+func expandTabs(line string, width int) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	column := 0
+	for _, r := range line {
+		if r == '\t' {
+			spaces := width - column%width
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+		b.WriteRune(r)
+		column++
+	}
+	return b.String()
+}
+
+// CodeBlock fences text as a markdown code block, with a fence longer than
+// any run of backticks in the text so it cannot close early
+func CodeBlock(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + "\n" + strings.TrimRight(text, "\n") + "\n" + fence
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/sst/dgmo/internal/config"
+)
+
+func TestSanitizePaste(t *testing.T) {
+	pasted := "\x1b[32mok\x1b[0m  \r\n\tgo test ./...\x07\r\nFAIL\t \r\n"
+
+	if got, want := SanitizePaste(pasted, config.PasteConfig{}), "ok  \n\tgo test ./...\nFAIL\t \n"; got != want {
+		t.Errorf("SanitizePaste = %q, want %q", got, want)
+	}
+	settings := config.PasteConfig{TrimTrailingSpace: true, TabWidth: 4}
+	if got, want := SanitizePaste(pasted, settings), "ok\n    go test ./...\nFAIL\n"; got != want {
+		t.Errorf("SanitizePaste trimming and expanding tabs = %q, want %q", got, want)
+	}
+	if got, want := expandTabs("ab\tc\td", 4), "ab  c   d"; got != want {
+		t.Errorf("expandTabs = %q, want %q", got, want)
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	if got, want := CodeBlock("x := 1\n"), "```\nx := 1\n```"; got != want {
+		t.Errorf("CodeBlock = %q, want %q", got, want)
+	}
+	if got, want := CodeBlock("```go\nx\n```"), "````\n```go\nx\n```\n````"; got != want {
+		t.Errorf("CodeBlock of a fenced block = %q, want %q", got, want)
+	}
+}
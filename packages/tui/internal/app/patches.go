@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// patchSummaryTitle starts the note the server adds to a session after a
+// reviewed patch was applied, which is how the transcript recognizes it
+const patchSummaryTitle = "Applied a reviewed patch"
+
+var patchBlockPattern = regexp.MustCompile("(?s)```(?:diff|patch)[^\\n]*\\n(.*?)```")
+
+// FileChange is the new content of a file, nil content deletes it
+type FileChange struct {
+	Path    string  `json:"path"`
+	Content *string `json:"content"`
+}
+
+// PatchedFile is how one file of a reviewed patch was handled
+type PatchedFile struct {
+	Path     string
+	Accepted int
+	Hunks    int
+	Deleted  bool
+}
+
+// PatchAppliedMsg is sent after the accepted hunks of a reviewed patch were
+// written, with the note the server added to the session
+type PatchAppliedMsg struct {
+	Message opencode.Message
+	Summary string
+}
+
+// MultiFilePatch returns the unified diff of the last diff block in text
+// that changes more than one file, or "" if there is none
+func MultiFilePatch(text string) string {
+	blocks := patchBlockPattern.FindAllStringSubmatch(text, -1)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if PatchFileCount(blocks[i][1]) > 1 {
+			return blocks[i][1]
+		}
+	}
+	return ""
+}
+
+// PatchFileCount counts the file headers of a unified diff
+func PatchFileCount(patch string) int {
+	count := 0
+	lines := strings.Split(patch, "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "--- ") && strings.HasPrefix(lines[i+1], "+++ ") {
+			count++
+			i++
+		}
+	}
+	return count
+}
+
+// LatestPatch returns the most recent multi-file patch the assistant wrote
+// in the given messages, or "" if there is none
+func LatestPatch(messages []opencode.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != opencode.MessageRoleAssistant {
+			continue
+		}
+		if patch := MultiFilePatch(messageText(messages[i])); patch != "" {
+			return patch
+		}
+	}
+	return ""
+}
+
+// PatchSummary describes the outcome of a review for the transcript
+func PatchSummary(files []PatchedFile) string {
+	accepted, hunks, changed := 0, 0, 0
+	var lines []string
+	for _, file := range files {
+		accepted += file.Accepted
+		hunks += file.Hunks
+		switch {
+		case file.Accepted == 0:
+			lines = append(lines, fmt.Sprintf("- %s: rejected", file.Path))
+			continue
+		case file.Deleted:
+			lines = append(lines, fmt.Sprintf("- %s: deleted", file.Path))
+		default:
+			lines = append(lines, fmt.Sprintf("- %s: %d/%d hunks", file.Path, file.Accepted, file.Hunks))
+		}
+		changed++
+	}
+	noun := "files"
+	if changed == 1 {
+		noun = "file"
+	}
+	title := fmt.Sprintf("%s: %d of %d hunks in %d %s", patchSummaryTitle, accepted, hunks, changed, noun)
+	return title + "\n\n" + strings.Join(lines, "\n")
+}
+
+// ParsePatchSummary splits a note written by PatchSummary into its title
+// and file lines
+func ParsePatchSummary(text string) (string, []string, bool) {
+	if !strings.HasPrefix(text, patchSummaryTitle) {
+		return "", nil, false
+	}
+	title, rest, _ := strings.Cut(text, "\n")
+	var files []string
+	for _, line := range strings.Split(rest, "\n") {
+		if line = strings.TrimPrefix(line, "- "); line != "" {
+			files = append(files, line)
+		}
+	}
+	return title, files, true
+}
+
+// ApplyPatch writes the changes all at once, so either every file is
+// changed or none is, and adds the summary to the session
+func (a *App) ApplyPatch(ctx context.Context, sessionID string, changes []FileChange, summary string) (*opencode.Message, error) {
+	if err := a.Features.Require(FeaturePatch); err != nil {
+		return nil, err
+	}
+	params := map[string]any{"changes": changes, "summary": summary}
+	var message opencode.Message
+	if err := a.Client.Post(ctx, "/session/"+sessionID+"/patch", params, &message); err != nil {
+		return nil, fmt.Errorf("failed to apply patch: %w", err)
+	}
+	return &message, nil
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMultiFilePatch(t *testing.T) {
+	twoFiles := "--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n--- a/b.go\n+++ b/b.go\n@@ -1 +1 @@\n-c\n+d\n"
+	oneFile := "--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"no block", "Change a to b in a.go", ""},
+		{"single file", "```diff\n" + oneFile + "```", ""},
+		{"two files", "Here:\n```diff\n" + twoFiles + "```\nDone.", twoFiles},
+		{"patch fence", "```patch\n" + twoFiles + "```", twoFiles},
+		{"last multi-file block", "```diff\n" + twoFiles + "```\n```diff\n" + oneFile + "```", twoFiles},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MultiFilePatch(tt.text); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchSummaryRoundTrip(t *testing.T) {
+	summary := PatchSummary([]PatchedFile{
+		{Path: "a.go", Accepted: 2, Hunks: 3},
+		{Path: "b.go", Accepted: 0, Hunks: 1},
+		{Path: "c.go", Accepted: 1, Hunks: 1, Deleted: true},
+	})
+
+	title, files, ok := ParsePatchSummary(summary)
+	if !ok {
+		t.Fatalf("summary %q was not recognized", summary)
+	}
+	if want := "Applied a reviewed patch: 3 of 5 hunks in 2 files"; title != want {
+		t.Errorf("title = %q, want %q", title, want)
+	}
+	want := []string{"a.go: 2/3 hunks", "b.go: rejected", "c.go: deleted"}
+	if !slices.Equal(files, want) {
+		t.Errorf("files = %q, want %q", files, want)
+	}
+
+	if _, _, ok := ParsePatchSummary("Please apply the patch"); ok {
+		t.Error("a plain message was taken for a summary")
+	}
+}
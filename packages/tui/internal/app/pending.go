@@ -0,0 +1,117 @@
+package app
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/components/toast"
+)
+
+// UndoWindow is how long a destructive action can be undone before it is
+// carried out
+const UndoWindow = 10 * time.Second
+
+// PendingOpDueMsg is sent when the undo window of a pending operation closed
+type PendingOpDueMsg struct {
+	ID int
+}
+
+// pendingOp is a destructive action waiting out its undo window
+type pendingOp struct {
+	id     int
+	key    string
+	label  string
+	commit tea.Cmd
+	undo   tea.Cmd
+}
+
+// PendingOps holds destructive actions until their undo window closed.
+// Undoing an action drops it and runs its undo instead of its commit.
+type PendingOps struct {
+	mu   sync.Mutex
+	next int
+	ops  []pendingOp
+}
+
+// NewPendingOps creates an empty queue of pending operations
+func NewPendingOps() *PendingOps {
+	return &PendingOps{}
+}
+
+// Defer queues an action under a key, such as "session:<id>", and returns
+// the command that reports it due once the undo window closed
+func (p *PendingOps) Defer(key, label string, commit, undo tea.Cmd) tea.Cmd {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.next++
+	id := p.next
+	p.ops = append(p.ops, pendingOp{id: id, key: key, label: label, commit: commit, undo: undo})
+	return tea.Tick(UndoWindow, func(time.Time) tea.Msg {
+		return PendingOpDueMsg{ID: id}
+	})
+}
+
+// Due takes the operation whose undo window closed and returns its commit,
+// nil when it was undone or flushed already
+func (p *PendingOps) Due(id int) tea.Cmd {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.ops, func(op pendingOp) bool { return op.id == id })
+	if i < 0 {
+		return nil
+	}
+	op := p.ops[i]
+	p.ops = slices.Delete(p.ops, i, i+1)
+	return op.commit
+}
+
+// Undo takes the newest pending operation and returns its key, label and
+// undo, false when nothing is pending
+func (p *PendingOps) Undo() (key, label string, undo tea.Cmd, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ops) == 0 {
+		return "", "", nil, false
+	}
+	op := p.ops[len(p.ops)-1]
+	p.ops = p.ops[:len(p.ops)-1]
+	return op.key, op.label, op.undo, true
+}
+
+// Pending reports whether an operation is queued under a key
+func (p *PendingOps) Pending(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.ContainsFunc(p.ops, func(op pendingOp) bool { return op.key == key })
+}
+
+// Flush takes every pending operation and returns their commits, oldest
+// first, to carry them out before exiting
+func (p *PendingOps) Flush() []tea.Cmd {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	commits := make([]tea.Cmd, 0, len(p.ops))
+	for _, op := range p.ops {
+		if op.commit != nil {
+			commits = append(commits, op.commit)
+		}
+	}
+	p.ops = nil
+	return commits
+}
+
+// Defer holds back a destructive action for the undo window and shows a
+// toast saying how to undo it
+func (a *App) Defer(key, label string, commit, undo tea.Cmd) tea.Cmd {
+	hint := "/undo to undo"
+	if binding := a.CommandKey(commands.UndoCommand); binding != "" {
+		hint = binding + " or " + hint
+	}
+	return tea.Batch(
+		a.Pending.Defer(key, label, commit, undo),
+		toast.NewInfoToast(label, toast.WithTitle(hint), toast.WithDuration(UndoWindow)),
+	)
+}
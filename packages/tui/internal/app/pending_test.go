@@ -0,0 +1,65 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+func TestPendingOps(t *testing.T) {
+	var committed, undone []string
+	op := func(name string) (tea.Cmd, tea.Cmd) {
+		commit := func() tea.Msg { committed = append(committed, name); return nil }
+		undo := func() tea.Msg { undone = append(undone, name); return nil }
+		return commit, undo
+	}
+
+	p := NewPendingOps()
+	commitA, undoA := op("a")
+	p.Defer("session:a", "Deleted a", commitA, undoA)
+	commitB, undoB := op("b")
+	p.Defer("session:b", "Deleted b", commitB, undoB)
+	if !p.Pending("session:a") || p.Pending("session:c") {
+		t.Fatal("Pending should report queued keys only")
+	}
+
+	key, label, undo, ok := p.Undo()
+	if !ok || key != "session:b" || label != "Deleted b" {
+		t.Fatalf("Undo = %q, %q, %v, want the newest operation", key, label, ok)
+	}
+	undo()
+	if p.Due(2) != nil {
+		t.Error("an undone operation should not commit")
+	}
+	if commit := p.Due(1); commit == nil {
+		t.Error("Due should return the commit of a queued operation")
+	} else {
+		commit()
+	}
+	if p.Due(1) != nil || p.Pending("session:a") {
+		t.Error("a committed operation should leave the queue")
+	}
+	if len(committed) != 1 || committed[0] != "a" || len(undone) != 1 || undone[0] != "b" {
+		t.Errorf("committed %v, undone %v", committed, undone)
+	}
+	if _, _, _, ok := p.Undo(); ok {
+		t.Error("Undo on an empty queue should report nothing")
+	}
+}
+
+func TestPendingOpsFlush(t *testing.T) {
+	p := NewPendingOps()
+	var order []string
+	p.Defer("draft", "Input cleared", nil, nil)
+	p.Defer("session:a", "Deleted a", func() tea.Msg { order = append(order, "a"); return nil }, nil)
+	p.Defer("session:b", "Deleted b", func() tea.Msg { order = append(order, "b"); return nil }, nil)
+	for _, commit := range p.Flush() {
+		commit()
+	}
+	if len(order) != 2 || order[0] != "a" || order[1] != "b" {
+		t.Errorf("flushed %v, want oldest first", order)
+	}
+	if p.Pending("session:a") || p.Due(2) != nil {
+		t.Error("Flush should empty the queue")
+	}
+}
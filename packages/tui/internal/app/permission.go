@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// PermissionResponse answers a permission request
+type PermissionResponse string
+
+const (
+	// PermissionOnce approves this tool call only
+	PermissionOnce PermissionResponse = "once"
+	// PermissionAlways approves the tool for the rest of the session
+	PermissionAlways PermissionResponse = "always"
+	// PermissionReject denies the tool call
+	PermissionReject PermissionResponse = "reject"
+)
+
+// PermissionRequest is a tool call the server holds until it is approved.
+// The ID is that of the tool call, Tool names the tool the call uses.
+type PermissionRequest struct {
+	ID        string
+	Tool      string
+	SessionID string
+	Title     string
+	Metadata  map[string]any
+	// Restricted is set when the workspace is restricted and the tool can
+	// only be allowed call by call
+	Restricted bool
+}
+
+// PermissionRespondedMsg is sent when a response to a permission request
+// reached the server
+type PermissionRespondedMsg struct {
+	Request  PermissionRequest
+	Response PermissionResponse
+	Err      error
+}
+
+// PermissionService queues the permission requests of the server and sends
+// back the user's responses
+type PermissionService struct {
+	client   *opencode.Client
+	features *FeatureFlags
+
+	mu         sync.Mutex
+	pending    []PermissionRequest
+	allowed    map[string][]string // tools always allowed per session
+	restricted bool
+}
+
+// restrictedTools are the tools a restricted workspace approves call by
+// call, never for a whole session
+var restrictedTools = []string{"bash"}
+
+// NewPermissionService creates a permission service using the given client.
+// Requests are only surfaced when the server runs in approval mode.
+func NewPermissionService(client *opencode.Client, features *FeatureFlags) *PermissionService {
+	return &PermissionService{
+		client:   client,
+		features: features,
+		allowed:  make(map[string][]string),
+	}
+}
+
+// Enqueue queues a permission request from the event stream. It reports
+// whether the request should be prompted for now, which is when approval
+// mode is on and no other request is waiting ahead of it.
+func (s *PermissionService) Enqueue(e opencode.EventListResponseEventPermissionUpdated) (PermissionRequest, bool) {
+	req := PermissionRequest{
+		ID:        e.Properties.ID,
+		SessionID: e.Properties.SessionID,
+		Title:     e.Properties.Title,
+		Metadata:  e.Properties.Metadata,
+		Tool:      permissionTool(e.Properties),
+	}
+	if !s.features.Enabled(FeaturePermissions) {
+		return req, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req.Restricted = s.restrictedLocked(req)
+	for _, p := range s.pending {
+		if p.ID == req.ID && p.SessionID == req.SessionID {
+			return req, false
+		}
+	}
+	s.pending = append(s.pending, req)
+	return req, len(s.pending) == 1
+}
+
+// permissionTool reads the tool of a request. Older servers leave it out and
+// use the tool name as the ID instead.
+func permissionTool(p opencode.EventListResponseEventPermissionUpdatedProperties) string {
+	field, ok := p.JSON.ExtraFields["tool"]
+	if !ok || field.IsNull() {
+		return p.ID
+	}
+	var tool string
+	if err := json.Unmarshal([]byte(field.Raw()), &tool); err != nil || tool == "" {
+		return p.ID
+	}
+	return tool
+}
+
+// Next returns the request waiting at the head of the queue
+func (s *PermissionService) Next() (PermissionRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 {
+		return PermissionRequest{}, false
+	}
+	return s.pending[0], true
+}
+
+// SetRestricted turns restricted mode on or off. In restricted mode bash
+// is asked for on every call, even if it was allowed for the session.
+func (s *PermissionService) SetRestricted(restricted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restricted = restricted
+}
+
+func (s *PermissionService) restrictedLocked(req PermissionRequest) bool {
+	return s.restricted && slices.Contains(restrictedTools, req.Tool)
+}
+
+// AlwaysAllowed reports whether the tool of a request was approved for the
+// rest of its session
+func (s *PermissionService) AlwaysAllowed(req PermissionRequest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.restrictedLocked(req) {
+		return false
+	}
+	return slices.Contains(s.allowed[req.SessionID], req.Tool)
+}
+
+// Respond sends the response to a permission request and removes it from
+// the queue
+func (s *PermissionService) Respond(ctx context.Context, req PermissionRequest, response PermissionResponse) error {
+	if err := s.features.Require(FeaturePermissions); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if response == PermissionAlways && s.restrictedLocked(req) {
+		// the server would stop asking for the rest of the session
+		response = PermissionOnce
+	}
+	s.pending = slices.DeleteFunc(s.pending, func(p PermissionRequest) bool {
+		return p.ID == req.ID && p.SessionID == req.SessionID
+	})
+	s.mu.Unlock()
+
+	endpoint := fmt.Sprintf("/session/%s/permissions/%s", req.SessionID, req.ID)
+	body := map[string]PermissionResponse{"response": response}
+	var ok bool
+	if err := s.client.Post(ctx, endpoint, body, &ok); err != nil {
+		return fmt.Errorf("failed to respond to permission request: %w", err)
+	}
+	if response == PermissionAlways {
+		s.mu.Lock()
+		if !slices.Contains(s.allowed[req.SessionID], req.Tool) {
+			s.allowed[req.SessionID] = append(s.allowed[req.SessionID], req.Tool)
+		}
+		s.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/plugin"
+)
+
+// pluginCompleteTimeout keeps a slow completion plugin from holding the
+// completion dialog open, it runs on every key typed
+const pluginCompleteTimeout = 2 * time.Second
+
+// reservedTriggers are the completion triggers plugins cannot take
+const reservedTriggers = "/@#$"
+
+// PluginsLoadedMsg carries the plugins that described themselves at startup
+type PluginsLoadedMsg struct {
+	Plugins []*plugin.Plugin
+	// Err reports the plugins that failed to load
+	Err error
+}
+
+// PluginAction is what a plugin command runs, a command or a panel
+type PluginAction struct {
+	Plugin *plugin.Plugin
+	Name   string
+	// Panel is set for the command that opens a panel
+	Panel *plugin.Panel
+}
+
+// PluginCommandResultMsg carries the response of a plugin to a command
+type PluginCommandResultMsg struct {
+	Action   PluginAction
+	Response plugin.Response
+	Err      error
+}
+
+// PluginPanelMsg carries a panel a plugin rendered
+type PluginPanelMsg struct {
+	Action   PluginAction
+	Response plugin.Response
+	Err      error
+}
+
+// PluginsDir returns the directory the plugins are loaded from
+func (a *App) PluginsDir() string {
+	return filepath.Join(a.Info.Path.Config, "plugins")
+}
+
+// LoadPlugins describes every plugin in the plugins directory
+func (a *App) LoadPlugins() tea.Cmd {
+	dir, cwd := a.PluginsDir(), a.Info.Path.Cwd
+	return func() tea.Msg {
+		plugins, err := plugin.Load(context.Background(), dir, cwd)
+		if err != nil {
+			slog.Error("Failed to load plugins", "error", err)
+		}
+		for _, p := range plugins {
+			slog.Info("Loaded plugin", "name", p.Name, "path", p.Path)
+		}
+		return PluginsLoadedMsg{Plugins: plugins, Err: err}
+	}
+}
+
+// SetPlugins registers the commands, panels and completions of plugins
+func (a *App) SetPlugins(plugins []*plugin.Plugin) {
+	a.Plugins = plugins
+	a.applyProjectConfig()
+}
+
+// PluginCommandName is the name of the command running a plugin command or
+// opening a plugin panel, which is what keybinds refer to
+func PluginCommandName(pluginName, name string) commands.CommandName {
+	return commands.CommandName("plugin_" + pluginName + "_" + name)
+}
+
+// registerPluginCommands adds a command to registry for every command and
+// panel of the plugins and returns what they run. A trigger taken by a
+// built-in command is prefixed with the plugin name.
+func (a *App) registerPluginCommands(registry commands.CommandRegistry) map[commands.CommandName]PluginAction {
+	actions := make(map[commands.CommandName]PluginAction)
+	keybinds := make(map[string]string)
+	register := func(action PluginAction, description, keybind string, args []string) {
+		name := PluginCommandName(action.Plugin.Name, action.Name)
+		if _, ok := registry[name]; ok {
+			slog.Warn("Plugin registers a name twice", "plugin", action.Plugin.Name, "name", action.Name)
+			return
+		}
+		trigger := action.Name
+		if _, taken := registry.FindTrigger(trigger); taken {
+			trigger = action.Plugin.Name + "-" + action.Name
+		}
+		if _, taken := registry.FindTrigger(trigger); taken {
+			slog.Warn("Plugin command trigger is taken", "plugin", action.Plugin.Name, "trigger", trigger)
+			return
+		}
+		command := commands.Command{
+			Name:        name,
+			Description: description,
+			Trigger:     trigger,
+		}
+		for _, arg := range args {
+			command.Args = append(command.Args, commands.Argument{Name: arg})
+		}
+		registry[name] = command
+		actions[name] = action
+		if keybind != "" {
+			keybinds[string(name)] = keybind
+		}
+	}
+	for _, p := range a.Plugins {
+		for _, command := range p.Manifest.Commands {
+			register(PluginAction{Plugin: p, Name: command.Name}, command.Description, command.Keybind, command.Args)
+		}
+		for _, panel := range p.Manifest.Panels {
+			register(PluginAction{Plugin: p, Name: panel.Name, Panel: &panel}, panel.Description, "", nil)
+		}
+	}
+	registry.ApplyKeybinds(keybinds)
+	return actions
+}
+
+// PluginAction returns what a plugin command runs
+func (a *App) PluginAction(name commands.CommandName) (PluginAction, bool) {
+	action, ok := a.pluginActions[name]
+	return action, ok
+}
+
+func (a *App) pluginRequest(call, name string) plugin.Request {
+	request := plugin.Request{Call: call, Name: name, Cwd: a.Info.Path.Cwd}
+	if a.Session != nil {
+		request.SessionID = a.Session.ID
+	}
+	return request
+}
+
+// RunPluginCommand runs a plugin command with the arguments typed after its
+// trigger
+func (a *App) RunPluginCommand(action PluginAction, args []string) tea.Cmd {
+	request := a.pluginRequest(plugin.CallCommand, action.Name)
+	request.Args = args
+	return func() tea.Msg {
+		response, err := action.Plugin.Call(context.Background(), request)
+		return PluginCommandResultMsg{Action: action, Response: response, Err: err}
+	}
+}
+
+// RenderPluginPanel asks a plugin for the content of a panel
+func (a *App) RenderPluginPanel(action PluginAction, width, height int) tea.Cmd {
+	request := a.pluginRequest(plugin.CallPanel, action.Name)
+	request.Width, request.Height = width, height
+	return func() tea.Msg {
+		response, err := action.Plugin.Call(context.Background(), request)
+		return PluginPanelMsg{Action: action, Response: response, Err: err}
+	}
+}
+
+// PluginCompletion returns the plugin providing completions for a word,
+// the one whose trigger the word starts with. Triggers are one character,
+// / is kept for commands and paths, and @, # and $ for the built-in
+// completions.
+func (a *App) PluginCompletion(word string) (*plugin.Plugin, plugin.Completion, bool) {
+	for _, p := range a.Plugins {
+		for _, completion := range p.Manifest.Completions {
+			if utf8.RuneCountInString(completion.Trigger) != 1 || strings.Contains(reservedTriggers, completion.Trigger) {
+				continue
+			}
+			if strings.HasPrefix(word, completion.Trigger) {
+				return p, completion, true
+			}
+		}
+	}
+	return nil, plugin.Completion{}, false
+}
+
+// CompletePlugin asks a plugin for the completions of a query
+func (a *App) CompletePlugin(p *plugin.Plugin, query string) ([]plugin.Item, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginCompleteTimeout)
+	defer cancel()
+	request := a.pluginRequest(plugin.CallComplete, "")
+	request.Query = query
+	response, err := p.Call(ctx, request)
+	return response.Items, err
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/plugin"
+)
+
+func TestRegisterPluginCommands(t *testing.T) {
+	jira := &plugin.Plugin{Name: "jira", Manifest: plugin.Manifest{
+		Commands: []plugin.Command{
+			{Name: "issue", Description: "open an issue", Args: []string{"key"}, Keybind: "ctrl+j"},
+			{Name: "help", Description: "jira help"},
+		},
+		Panels: []plugin.Panel{{Name: "board", Description: "sprint board"}},
+	}}
+	a := &App{Plugins: []*plugin.Plugin{jira}}
+	registry := commands.CommandRegistry{
+		commands.AppHelpCommand: {Name: commands.AppHelpCommand, Trigger: "help"},
+	}
+	actions := a.registerPluginCommands(registry)
+
+	issue := registry[PluginCommandName("jira", "issue")]
+	if issue.Trigger != "issue" || len(issue.Args) != 1 || issue.Args[0].Name != "key" {
+		t.Errorf("issue command = %+v", issue)
+	}
+	if len(issue.Keybindings) != 1 || issue.Keybindings[0].Key != "ctrl+j" {
+		t.Errorf("issue keybindings = %+v, want ctrl+j", issue.Keybindings)
+	}
+	if help := registry[PluginCommandName("jira", "help")]; help.Trigger != "jira-help" {
+		t.Errorf("help trigger = %q, want the plugin prefix as /help is taken", help.Trigger)
+	}
+	board, ok := actions[PluginCommandName("jira", "board")]
+	if !ok || board.Panel == nil || board.Panel.Name != "board" {
+		t.Errorf("board action = %+v, want the panel", board)
+	}
+	if action := actions[PluginCommandName("jira", "issue")]; action.Panel != nil || action.Plugin != jira {
+		t.Errorf("issue action = %+v, want a command of jira", action)
+	}
+}
+
+func TestPluginCompletion(t *testing.T) {
+	a := &App{Plugins: []*plugin.Plugin{{Name: "jira", Manifest: plugin.Manifest{
+		Completions: []plugin.Completion{
+			{Trigger: "/", Description: "taken by commands"},
+			{Trigger: "@", Description: "taken by workspace files"},
+			{Trigger: "!!", Description: "too long"},
+			{Trigger: "!", Description: "issues"},
+		},
+	}}}}
+	if _, completion, ok := a.PluginCompletion("!ABC"); !ok || completion.Description != "issues" {
+		t.Errorf("PluginCompletion(!ABC) = %+v, %v", completion, ok)
+	}
+	if _, _, ok := a.PluginCompletion("/usr"); ok {
+		t.Error("PluginCompletion(/usr) matched, / is kept for commands")
+	}
+	if _, _, ok := a.PluginCompletion("@main.go"); ok {
+		t.Error("PluginCompletion(@main.go) matched, @ is kept for workspace files")
+	}
+	if _, _, ok := a.PluginCompletion("ABC"); ok {
+		t.Error("PluginCompletion(ABC) matched a word without a trigger")
+	}
+}
@@ -0,0 +1,273 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sst/dgmo/internal/paths"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// PRFlavor is the forge a pull request description is written for
+type PRFlavor string
+
+const (
+	PRGitHub PRFlavor = "github"
+	PRGitLab PRFlavor = "gitlab"
+)
+
+const (
+	// prIntentMaxBytes caps the summary of intent taken from the first prompt
+	prIntentMaxBytes = 1200
+	// prOutputLines is how many of the last output lines of a test run are
+	// quoted as evidence
+	prOutputLines = 15
+	// prMaxQuestions caps the open questions taken from the responses
+	prMaxQuestions = 5
+)
+
+// testCommand matches bash commands that run tests, builds or linters
+var testCommand = regexp.MustCompile(`\b(test|tests|pytest|jest|vitest|mocha|rspec|tox|go vet|lint|tsc|typecheck|check|build)\b`)
+
+// prFileChange is a file the session changed
+type prFileChange struct {
+	path    string
+	action  string // "edited" or "written"
+	added   int
+	removed int
+}
+
+// prTestRun is a test command the session ran and the end of its output
+type prTestRun struct {
+	command string
+	output  string
+}
+
+// PRDescription generates a pull request description from a session: the
+// intent from the first prompt, the files changed by tool calls, the test
+// runs as evidence and the questions the responses left open
+func PRDescription(session *opencode.Session, messages []opencode.Message, root string, flavor PRFlavor) string {
+	headings := []string{"Summary", "Changes", "Testing", "Open questions"}
+	if flavor == PRGitLab {
+		// the sections of GitLab's default merge request template
+		headings = []string{"What does this MR do and why?", "Changes", "How to validate", "Open questions"}
+	}
+
+	var b strings.Builder
+	if session != nil && session.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", session.Title)
+	}
+
+	fmt.Fprintf(&b, "## %s\n\n", headings[0])
+	intent := prIntent(messages)
+	if intent == "" {
+		intent = "_No prompt in this session._"
+	}
+	b.WriteString(intent + "\n")
+	if outcome := prOutcome(messages); outcome != "" {
+		b.WriteString("\n" + outcome + "\n")
+	}
+
+	fmt.Fprintf(&b, "\n## %s\n\n", headings[1])
+	changes := prChanges(messages, root)
+	if len(changes) == 0 {
+		b.WriteString("_No files were changed by tool calls._\n")
+	}
+	for _, change := range changes {
+		fmt.Fprintf(&b, "- `%s` %s", change.path, change.action)
+		if change.added+change.removed > 0 {
+			fmt.Fprintf(&b, " (+%d −%d)", change.added, change.removed)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\n## %s\n\n", headings[2])
+	runs := prTestRuns(messages)
+	if len(runs) == 0 {
+		b.WriteString("_No test runs in this session._\n")
+	}
+	for _, run := range runs {
+		fmt.Fprintf(&b, "<details>\n<summary><code>%s</code></summary>\n\n```console\n%s\n```\n\n</details>\n", run.command, run.output)
+	}
+
+	fmt.Fprintf(&b, "\n## %s\n\n", headings[3])
+	questions := prQuestions(messages)
+	if len(questions) == 0 {
+		b.WriteString("None.\n")
+	}
+	for _, question := range questions {
+		fmt.Fprintf(&b, "- [ ] %s\n", question)
+	}
+	return b.String()
+}
+
+// WritePRDescription writes a pull request description to a new file in dir
+// and returns its path
+func (a *App) WritePRDescription(dir, description string) (string, error) {
+	if a.Session == nil || a.Session.ID == "" {
+		return "", fmt.Errorf("no session to describe")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create exports directory %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("dgmo-pr-%s-%s.md", a.Session.ID, time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(description), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write PR description %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// prIntent is the first prompt of the session, cut at a paragraph
+func prIntent(messages []opencode.Message) string {
+	for _, message := range messages {
+		if message.Role != opencode.MessageRoleUser {
+			continue
+		}
+		text := strings.TrimSpace(messageText(message))
+		if text == "" {
+			continue
+		}
+		if len(text) > prIntentMaxBytes {
+			cut := strings.LastIndex(text[:prIntentMaxBytes], "\n\n")
+			if cut <= 0 {
+				cut = prIntentMaxBytes
+			}
+			text = strings.TrimSpace(text[:cut]) + " …"
+		}
+		return text
+	}
+	return ""
+}
+
+// prOutcome is the first paragraph of the last response
+func prOutcome(messages []opencode.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != opencode.MessageRoleAssistant {
+			continue
+		}
+		text := strings.TrimSpace(messageText(messages[i]))
+		if text == "" {
+			continue
+		}
+		paragraph, _, _ := strings.Cut(text, "\n\n")
+		return paragraph
+	}
+	return ""
+}
+
+// prChanges lists the files changed by edit and write calls in the order
+// they were first changed, with the lines the edits added and removed
+func prChanges(messages []opencode.Message, root string) []prFileChange {
+	var changes []prFileChange
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			tool, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok {
+				continue
+			}
+			action := ""
+			switch tool.ToolInvocation.ToolName {
+			case "edit", "multiedit":
+				action = "edited"
+			case "write":
+				action = "written"
+			default:
+				continue
+			}
+			args, _ := tool.ToolInvocation.Args.(map[string]any)
+			path, _ := args["filePath"].(string)
+			if path == "" {
+				continue
+			}
+			path = paths.Relative(path, root)
+			i := slices.IndexFunc(changes, func(change prFileChange) bool { return change.path == path })
+			if i < 0 {
+				changes = append(changes, prFileChange{path: path, action: action})
+				i = len(changes) - 1
+			}
+			metadata := message.Metadata.Tool[tool.ToolInvocation.ToolCallID]
+			if diff, ok := metadata.ExtraFields["diff"].(string); ok {
+				added, removed := diffStat(diff)
+				changes[i].added += added
+				changes[i].removed += removed
+			}
+		}
+	}
+	return changes
+}
+
+// diffStat counts the added and removed lines of a unified diff
+func diffStat(diff string) (added, removed int) {
+	for line := range strings.SplitSeq(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// prTestRuns returns the last run of every test command, with the end of its
+// output
+func prTestRuns(messages []opencode.Message) []prTestRun {
+	var runs []prTestRun
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			tool, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok || tool.ToolInvocation.ToolName != "bash" {
+				continue
+			}
+			args, _ := tool.ToolInvocation.Args.(map[string]any)
+			command, _ := args["command"].(string)
+			if command == "" || !testCommand.MatchString(command) {
+				continue
+			}
+			output := tool.ToolInvocation.Result
+			metadata := message.Metadata.Tool[tool.ToolInvocation.ToolCallID]
+			if stdout, ok := metadata.ExtraFields["stdout"].(string); ok && stdout != "" {
+				output = stdout
+			}
+			lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+			if len(lines) > prOutputLines {
+				lines = lines[len(lines)-prOutputLines:]
+			}
+			run := prTestRun{command: command, output: "$ " + command + "\n" + strings.Join(lines, "\n")}
+			// a command run again replaces its earlier evidence
+			runs = slices.DeleteFunc(runs, func(r prTestRun) bool { return r.command == command })
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// prQuestions collects the questions of the last responses
+func prQuestions(messages []opencode.Message) []string {
+	var questions []string
+	responses := 0
+	for i := len(messages) - 1; i >= 0 && responses < 3; i-- {
+		if messages[i].Role != opencode.MessageRoleAssistant {
+			continue
+		}
+		responses++
+		for line := range strings.SplitSeq(messageText(messages[i]), "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(line, "-*0123456789. "))
+			if !strings.HasSuffix(line, "?") || strings.HasPrefix(line, "```") || slices.Contains(questions, line) {
+				continue
+			}
+			questions = append(questions, line)
+			if len(questions) == prMaxQuestions {
+				return questions
+			}
+		}
+	}
+	return questions
+}
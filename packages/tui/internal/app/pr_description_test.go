@@ -0,0 +1,51 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestDiffStat(t *testing.T) {
+	diff := "--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,4 @@\n package main\n-old\n+new\n+added\n"
+	if added, removed := diffStat(diff); added != 2 || removed != 1 {
+		t.Errorf("diffStat = +%d -%d, want +2 -1", added, removed)
+	}
+}
+
+func TestTestCommand(t *testing.T) {
+	for _, command := range []string{"go test ./...", "bun run typecheck", "npx vitest run"} {
+		if !testCommand.MatchString(command) {
+			t.Errorf("%q is not taken as a test run", command)
+		}
+	}
+	for _, command := range []string{"ls -la", "git status", "cat testdata.txt"} {
+		if testCommand.MatchString(command) {
+			t.Errorf("%q is taken as a test run", command)
+		}
+	}
+}
+
+func TestPRDescription(t *testing.T) {
+	var messages []opencode.Message
+	data := `[
+		{"id":"msg_1","role":"user","parts":[{"type":"text","text":"Add retries to the uploader"}],"metadata":{"sessionID":"ses_1","time":{"created":1}}},
+		{"id":"msg_2","role":"assistant","parts":[{"type":"text","text":"Retries are added.\n\nShould the backoff be configurable?"}],"metadata":{"sessionID":"ses_1","time":{"created":2,"completed":3}}}
+	]`
+	if err := json.Unmarshal([]byte(data), &messages); err != nil {
+		t.Fatal(err)
+	}
+	session := &opencode.Session{ID: "ses_1", Title: "Uploader retries"}
+
+	github := PRDescription(session, messages, "/repo", PRGitHub)
+	for _, want := range []string{"# Uploader retries", "## Summary\n\nAdd retries to the uploader\n\nRetries are added.", "- [ ] Should the backoff be configurable?"} {
+		if !strings.Contains(github, want) {
+			t.Errorf("description lacks %q:\n%s", want, github)
+		}
+	}
+	if gitlab := PRDescription(session, messages, "/repo", PRGitLab); !strings.Contains(gitlab, "## What does this MR do and why?") {
+		t.Errorf("GitLab description lacks the MR template heading:\n%s", gitlab)
+	}
+}
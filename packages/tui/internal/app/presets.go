@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/opencode-sdk-go/option"
+)
+
+// presetParams is the preset as the chat endpoint expects it
+type presetParams struct {
+	Name        string   `json:"name"`
+	Tools       []string `json:"tools,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+}
+
+// ActivePreset returns the selected agent preset, nil when none is selected
+// or it was removed from the config
+func (a *App) ActivePreset() *config.AgentPreset {
+	if a.State.ActivePreset == "" {
+		return nil
+	}
+	for i, preset := range a.State.AgentPresets {
+		if preset.Name == a.State.ActivePreset {
+			return &a.State.AgentPresets[i]
+		}
+	}
+	return nil
+}
+
+// presetOptions adds the active preset to a chat request. Servers without
+// presets get nothing, the selection then only changes the model.
+func (a *App) presetOptions() []option.RequestOption {
+	preset := a.ActivePreset()
+	if preset == nil || a.Features.Require(FeaturePresets) != nil {
+		return nil
+	}
+	return []option.RequestOption{option.WithJSONSet("preset", presetParams{
+		Name:        preset.Name,
+		Tools:       preset.Tools,
+		Temperature: preset.Temperature,
+	})}
+}
+
+// SelectPreset makes the named preset active, empty deselects it. The
+// returned command switches to the model of the preset.
+func (a *App) SelectPreset(name string) (tea.Cmd, error) {
+	previous := a.State.ActivePreset
+	a.State.ActivePreset = name
+	preset := a.ActivePreset()
+	if name != "" && preset == nil {
+		a.State.ActivePreset = previous
+		return nil, fmt.Errorf("no agent preset named %q", name)
+	}
+	a.SaveState()
+	if preset == nil || preset.Model == "" {
+		return nil, nil
+	}
+
+	providerID, modelID, ok := strings.Cut(preset.Model, "/")
+	if !ok {
+		return nil, fmt.Errorf("preset %s: model %q is not provider/model", preset.Name, preset.Model)
+	}
+	return func() tea.Msg {
+		providers, err := a.ListProviders(context.Background())
+		if err != nil {
+			return fmt.Errorf("preset %s: failed to list providers: %w", preset.Name, err)
+		}
+		for _, provider := range providers {
+			if provider.ID != providerID {
+				continue
+			}
+			if model, ok := provider.Models[modelID]; ok {
+				return ModelSelectedMsg{Provider: provider, Model: model}
+			}
+		}
+		return fmt.Errorf("preset %s: model %s is not available", preset.Name, preset.Model)
+	}, nil
+}
@@ -0,0 +1,295 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/dgmo/internal/paths"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/opencode-sdk-go"
+)
+
+const (
+	// projectConfigPollInterval is how often the overlay is checked for changes
+	projectConfigPollInterval = 2 * time.Second
+	// pinnedContextMaxBytes caps how much of a pinned file is sent
+	pinnedContextMaxBytes = 64 << 10
+	defaultAgentMode      = "read-only"
+)
+
+// ProjectConfigCheckMsg asks for the project config overlay to be checked
+// for changes
+type ProjectConfigCheckMsg struct{}
+
+// ProjectConfigReloadedMsg is sent after the project config overlay changed
+type ProjectConfigReloadedMsg struct {
+	Err error
+}
+
+// ConfigSetting is one effective setting and where its value comes from
+type ConfigSetting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+// ProjectConfigPath returns the path of the project config overlay
+func (a *App) ProjectConfigPath() string {
+	return filepath.Join(a.Info.Path.Root, config.ProjectConfigFile)
+}
+
+// LoadProjectConfig reads the project config overlay and applies it on top
+// of the user settings, unless the workspace is restricted
+func (a *App) LoadProjectConfig() error {
+	path := a.ProjectConfigPath()
+	if info, err := os.Stat(path); err == nil {
+		a.projectModTime = info.ModTime()
+	} else {
+		a.projectModTime = time.Time{}
+	}
+	if a.Restricted() {
+		// a restricted workspace keeps only the user settings
+		a.Project = nil
+		a.applyProjectConfig()
+		return nil
+	}
+
+	project, err := config.LoadProjectConfig(path)
+	if err != nil {
+		return err
+	}
+	a.Project = project
+	a.applyProjectConfig()
+	if project != nil {
+		slog.Info("Loaded project config", "path", path)
+	}
+	return nil
+}
+
+// WatchProjectConfig schedules the next check of the project config overlay
+func (a *App) WatchProjectConfig() tea.Cmd {
+	return tea.Tick(projectConfigPollInterval, func(time.Time) tea.Msg {
+		return ProjectConfigCheckMsg{}
+	})
+}
+
+// CheckProjectConfig reloads the overlay if it was created, changed or
+// removed since it was last loaded
+func (a *App) CheckProjectConfig() tea.Cmd {
+	var modTime time.Time
+	if info, err := os.Stat(a.ProjectConfigPath()); err == nil {
+		modTime = info.ModTime()
+	}
+	if modTime.Equal(a.projectModTime) {
+		return nil
+	}
+	err := a.LoadProjectConfig()
+	if err != nil {
+		// keep the last good overlay, but don't report the same error again
+		a.projectModTime = modTime
+		slog.Error("Failed to reload project config", "error", err)
+	}
+	return func() tea.Msg {
+		return ProjectConfigReloadedMsg{Err: err}
+	}
+}
+
+// applyProjectConfig rebuilds the settings derived from the user config and
+// the project overlay
+func (a *App) applyProjectConfig() {
+	a.Config.Keybinds.Leader = a.userLeader
+	a.Commands = commands.LoadFromConfig(a.Config)
+	a.pluginActions = a.registerPluginCommands(a.Commands)
+
+	project := a.Project
+	if project == nil {
+		project = &config.ProjectConfig{}
+	}
+	if leader := project.Keybinds["leader"]; leader != "" {
+		a.Config.Keybinds.Leader = leader
+	}
+	a.Commands.ApplyKeybinds(project.Keybinds)
+
+	themeName := a.State.Theme
+	if project.Theme != "" {
+		themeName = project.Theme
+	}
+	if themeName != "" && themeName != theme.CurrentThemeName() {
+		if err := theme.SetTheme(themeName); err != nil {
+			slog.Warn("Failed to set theme", "theme", themeName, "error", err)
+		}
+	}
+}
+
+// ProjectOverrides reports whether the project overlay sets the named
+// setting, so it should not be persisted to the user state
+func (a *App) ProjectOverrides(setting string) bool {
+	if a.Project == nil {
+		return false
+	}
+	switch setting {
+	case "theme":
+		return a.Project.Theme != ""
+	case "autonomy":
+		return a.Project.Autonomy != ""
+	}
+	return false
+}
+
+// AgentMode returns the effective autonomy profile of sub-agents
+func (a *App) AgentMode() string {
+	if a.Project != nil && a.Project.Autonomy != "" {
+		return a.Project.Autonomy
+	}
+	if a.State.AgentMode != "" {
+		return a.State.AgentMode
+	}
+	return defaultAgentMode
+}
+
+// PinnedContext returns the absolute paths of the user's and the project's
+// pinned files, without duplicates
+func (a *App) PinnedContext() []string {
+	var paths []string
+	add := func(path string) {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(a.Info.Path.Root, path)
+		}
+		path = filepath.Clean(path)
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range a.State.PinnedContext {
+		add(path)
+	}
+	if a.Project != nil {
+		for _, path := range a.Project.Context {
+			add(path)
+		}
+	}
+	return paths
+}
+
+// pinnedContextParts reads the pinned files into text parts, each cut to
+// limit bytes. Files that can't be read are skipped so a stale entry doesn't
+// block sending.
+func (a *App) pinnedContextParts(limit int) []opencode.MessagePartUnionParam {
+	var parts []opencode.MessagePartUnionParam
+	for _, path := range a.PinnedContext() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			slog.Warn("Failed to read pinned context", "path", path, "error", err)
+			continue
+		}
+		if len(data) > limit {
+			data = data[:limit]
+		}
+		name := paths.Relative(path, a.Info.Path.Root)
+		parts = append(parts, opencode.TextPartParam{
+			Type: opencode.F(opencode.TextPartTypeText),
+			Text: opencode.F(fmt.Sprintf("<pinned-context path=%q>\n%s\n</pinned-context>", name, data)),
+		})
+	}
+	return parts
+}
+
+func formatKeybindings(bindings []commands.Keybinding) string {
+	keys := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.RequiresLeader {
+			keys = append(keys, "<leader>"+binding.Key)
+			continue
+		}
+		keys = append(keys, binding.Key)
+	}
+	return strings.Join(keys, ", ")
+}
+
+// CommandKey returns the first key of a command with the leader spelled
+// out, empty when the command has no key
+func (a *App) CommandKey(name commands.CommandName) string {
+	command, ok := a.Commands[name]
+	if !ok || len(command.Keybindings) == 0 {
+		return ""
+	}
+	binding := command.Keybindings[0]
+	if binding.RequiresLeader {
+		return a.Config.Keybinds.Leader + " " + binding.Key
+	}
+	return binding.Key
+}
+
+// EffectiveConfig lists the merged settings with the source of each value
+func (a *App) EffectiveConfig() []ConfigSetting {
+	project := a.Project
+	if project == nil {
+		project = &config.ProjectConfig{}
+	}
+	source := func(overridden bool) string {
+		if overridden {
+			return "project"
+		}
+		return "user"
+	}
+
+	markdown := styles.GetMarkdownOptions()
+	largeTokens, largeBytes := a.largePromptThresholds()
+	settings := []ConfigSetting{
+		{Name: "theme", Value: theme.CurrentThemeName(), Source: source(project.Theme != "")},
+		{Name: "autonomy", Value: a.AgentMode(), Source: source(project.Autonomy != "")},
+		{Name: "active_preset", Value: a.State.ActivePreset, Source: "user"},
+		{Name: "task_toasts", Value: a.taskToasts(), Source: "user"},
+		{Name: "quiet_hours", Value: formatQuietHours(a.State.QuietHours), Source: "user"},
+		{Name: "idle_reminder_after", Value: a.IdleReminderAfter().String(), Source: "user"},
+		{Name: "leader", Value: a.Config.Keybinds.Leader, Source: source(project.Keybinds["leader"] != "")},
+		{Name: "image_previews", Value: strconv.FormatBool(a.State.ImagePreviews), Source: "user"},
+		{Name: "max_width", Value: FormatMaxWidth(a.State.MaxWidth), Source: "user"},
+		{Name: "density", Value: string(a.Density()), Source: "user"},
+		{Name: "markdown.emoji", Value: strconv.FormatBool(markdown.Emoji), Source: "user"},
+		{Name: "markdown.line_numbers", Value: strconv.FormatBool(markdown.LineNumbers), Source: "user"},
+		{Name: "markdown.headings", Value: markdown.Headings, Source: "user"},
+		{Name: "markdown.links", Value: markdown.Links, Source: "user"},
+		{Name: "hyperlinks", Value: strconv.FormatBool(styles.HyperlinksEnabled()), Source: "user"},
+		{Name: "large_prompt.tokens", Value: strconv.Itoa(largeTokens), Source: "user"},
+		{Name: "large_prompt.bytes", Value: strconv.Itoa(largeBytes), Source: "user"},
+		{Name: "paste.trim_trailing_space", Value: strconv.FormatBool(a.State.Paste.TrimTrailingSpace), Source: "user"},
+		{Name: "paste.tab_width", Value: strconv.Itoa(a.State.Paste.TabWidth), Source: "user"},
+		{Name: "fold_agent_summaries", Value: strconv.FormatBool(a.State.FoldAgentSummaries), Source: "user"},
+		{Name: "image_preview_domains", Value: strings.Join(a.State.ImagePreviewDomains, ", "), Source: "user"},
+	}
+	for _, command := range a.Commands.Sorted() {
+		if len(command.Keybindings) == 0 {
+			continue
+		}
+		settings = append(settings, ConfigSetting{
+			Name:   "keybinds." + string(command.Name),
+			Value:  formatKeybindings(command.Keybindings),
+			Source: source(project.Keybinds[string(command.Name)] != ""),
+		})
+	}
+
+	pinned := make(map[string]bool)
+	for _, path := range project.Context {
+		pinned[path] = true
+	}
+	for _, path := range a.PinnedContext() {
+		name := paths.Relative(path, a.Info.Path.Root)
+		settings = append(settings, ConfigSetting{
+			Name:   "context",
+			Value:  name,
+			Source: source(pinned[name] && !slices.Contains(a.State.PinnedContext, name)),
+		})
+	}
+	return settings
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// commandBlockTimeout bounds how long a command block may run
+const commandBlockTimeout = 30 * time.Second
+
+// PromptBlockKind is the source of a prompt builder block
+type PromptBlockKind string
+
+const (
+	PromptBlockText    PromptBlockKind = "text"
+	PromptBlockFile    PromptBlockKind = "file"
+	PromptBlockQuote   PromptBlockKind = "quote"
+	PromptBlockCommand PromptBlockKind = "command"
+)
+
+// PromptBlock is one part of a message assembled in the prompt builder
+type PromptBlock struct {
+	Kind PromptBlockKind
+	// Title describes the source, e.g. the file path and line range
+	Title   string
+	Content string
+}
+
+// TextBlock creates a block of free text
+func TextBlock(text string) PromptBlock {
+	return PromptBlock{Kind: PromptBlockText, Title: "text", Content: text}
+}
+
+// FileBlock reads an excerpt given as "path", "path:line" or
+// "path:start-end", with paths relative to the project root
+func FileBlock(root, spec string) (PromptBlock, error) {
+	path, lines, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	if path == "" {
+		return PromptBlock{}, fmt.Errorf("missing file path")
+	}
+	start, end := 1, 0
+	if lines != "" {
+		first, last, isRange := strings.Cut(lines, "-")
+		var err error
+		if start, err = strconv.Atoi(first); err != nil || start < 1 {
+			return PromptBlock{}, fmt.Errorf("invalid line %q", first)
+		}
+		end = start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil || end < start {
+				return PromptBlock{}, fmt.Errorf("invalid line range %q", lines)
+			}
+		}
+	}
+
+	fullPath := path
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(root, path)
+	}
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return PromptBlock{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if start > len(content) {
+		return PromptBlock{}, fmt.Errorf("%s has only %d lines", path, len(content))
+	}
+	if end == 0 || end > len(content) {
+		end = len(content)
+	}
+
+	title := path
+	if lines != "" {
+		title = fmt.Sprintf("%s:%d-%d", path, start, end)
+	}
+	return PromptBlock{
+		Kind:    PromptBlockFile,
+		Title:   title,
+		Content: strings.Join(content[start-1:end], "\n"),
+	}, nil
+}
+
+// QuoteBlock quotes the text of a previous message
+func QuoteBlock(message opencode.Message) PromptBlock {
+	var texts []string
+	for _, part := range message.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok && strings.TrimSpace(text.Text) != "" {
+			texts = append(texts, strings.TrimSpace(text.Text))
+		}
+	}
+	return PromptBlock{
+		Kind:    PromptBlockQuote,
+		Title:   string(message.Role) + " message",
+		Content: strings.Join(texts, "\n\n"),
+	}
+}
+
+// CommandBlock runs command in dir with the user's shell and captures its
+// combined output. A failing command still produces a block, since its
+// output is usually what the prompt is about.
+func CommandBlock(ctx context.Context, dir, command string) (PromptBlock, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandBlockTimeout)
+	defer cancel()
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+	cmd := exec.CommandContext(ctx, shell, "-c", command)
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return PromptBlock{}, fmt.Errorf("%s timed out after %s", command, commandBlockTimeout)
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return PromptBlock{}, fmt.Errorf("failed to run %s: %w", command, err)
+	}
+
+	title := "$ " + command
+	if exitErr != nil {
+		title += fmt.Sprintf(" (exit %d)", exitErr.ExitCode())
+	}
+	return PromptBlock{
+		Kind:    PromptBlockCommand,
+		Title:   title,
+		Content: strings.TrimRight(output.String(), "\n"),
+	}, nil
+}
+
+// ComposePrompt joins the blocks into one message, fencing file excerpts and
+// command output and quoting previous messages
+func ComposePrompt(blocks []PromptBlock) string {
+	sections := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		switch block.Kind {
+		case PromptBlockFile:
+			sections = append(sections, fmt.Sprintf("%s\n```%s\n%s\n```",
+				block.Title, fenceLanguage(block.Title), block.Content))
+		case PromptBlockCommand:
+			sections = append(sections, fmt.Sprintf("%s\n```\n%s\n```", block.Title, block.Content))
+		case PromptBlockQuote:
+			quoted := "> " + strings.ReplaceAll(block.Content, "\n", "\n> ")
+			sections = append(sections, fmt.Sprintf("Quoting the %s:\n%s", block.Title, quoted))
+		default:
+			sections = append(sections, block.Content)
+		}
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// fenceLanguage guesses the code fence language from a file excerpt title
+func fenceLanguage(title string) string {
+	path, _, _ := strings.Cut(title, ":")
+	return strings.TrimPrefix(filepath.Ext(path), ".")
+}
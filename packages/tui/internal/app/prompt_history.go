@@ -0,0 +1,158 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// maxPromptHistory is how many prompts the history keeps, the oldest are
+// dropped when the file is compacted
+const maxPromptHistory = 2000
+
+// HistoryEntry is a prompt that was sent
+type HistoryEntry struct {
+	Text      string    `json:"text"`
+	SessionID string    `json:"sessionID,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// PromptHistory keeps every prompt sent from any session, newest last, in
+// a JSON lines file in the state directory
+type PromptHistory struct {
+	path string
+
+	mu      sync.Mutex
+	entries []HistoryEntry
+	lines   int // lines in the file, duplicates included
+}
+
+// NewPromptHistory loads the history of a file. A missing file is an empty
+// history, broken lines are skipped.
+func NewPromptHistory(path string) *PromptHistory {
+	h := &PromptHistory{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("Failed to read prompt history", "path", path, "error", err)
+		}
+		return h
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Text == "" {
+			continue
+		}
+		h.lines++
+		h.push(entry)
+	}
+	return h
+}
+
+// push appends an entry, dropping an earlier copy of the same prompt
+func (h *PromptHistory) push(entry HistoryEntry) {
+	h.entries = slices.DeleteFunc(h.entries, func(e HistoryEntry) bool { return e.Text == entry.Text })
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxPromptHistory {
+		h.entries = slices.Delete(h.entries, 0, len(h.entries)-maxPromptHistory)
+	}
+}
+
+// Add records a sent prompt. The file is appended to and rewritten without
+// duplicates and dropped prompts once it holds twice as many lines as
+// prompts.
+func (h *PromptHistory) Add(sessionID, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	entry := HistoryEntry{Text: text, SessionID: sessionID, Time: time.Now()}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.push(entry)
+	if h.lines >= 2*max(len(h.entries), 100) {
+		return h.compact()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt history entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open prompt history %s: %w", h.path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write prompt history %s: %w", h.path, err)
+	}
+	h.lines++
+	return nil
+}
+
+// compact rewrites the file with the kept entries only
+func (h *PromptHistory) compact() error {
+	var buf bytes.Buffer
+	for _, entry := range h.entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode prompt history entry: %w", err)
+		}
+		buf.Write(append(line, '\n'))
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write prompt history %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("failed to replace prompt history %s: %w", h.path, err)
+	}
+	h.lines = len(h.entries)
+	return nil
+}
+
+// Search returns the prompts matching a fuzzy query, the closest matches
+// first and the newest first among equally close ones. An empty query
+// returns every prompt, newest first.
+func (h *PromptHistory) Search(query string) []HistoryEntry {
+	h.mu.Lock()
+	entries := slices.Clone(h.entries)
+	h.mu.Unlock()
+	slices.Reverse(entries)
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return entries
+	}
+	targets := make([]string, len(entries))
+	for i, entry := range entries {
+		targets[i] = entry.Text
+	}
+	matches := fuzzy.RankFindFold(query, targets)
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].OriginalIndex < matches[j].OriginalIndex
+	})
+	found := make([]HistoryEntry, 0, len(matches))
+	for _, match := range matches {
+		found = append(found, entries[match.OriginalIndex])
+	}
+	return found
+}
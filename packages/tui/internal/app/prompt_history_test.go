@@ -0,0 +1,48 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "prompt-history.jsonl")
+	history := NewPromptHistory(path)
+	for _, prompt := range []string{"fix the failing tests", "add a README", "refactor the parser", "fix the failing tests"} {
+		if err := history.Add("ses_1", prompt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded := NewPromptHistory(path)
+	all := reloaded.Search("")
+	if len(all) != 3 || all[0].Text != "fix the failing tests" || all[2].Text != "add a README" {
+		t.Fatalf("history = %+v, want three prompts newest first", all)
+	}
+	if found := reloaded.Search("fxfail"); len(found) != 1 || found[0].Text != "fix the failing tests" {
+		t.Errorf("Search(fxfail) = %+v", found)
+	}
+	if found := reloaded.Search("re"); len(found) != 2 || found[0].Text != "add a README" {
+		t.Errorf("Search(re) = %+v, want the closest match first", found)
+	}
+}
+
+func TestPromptHistoryCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt-history.jsonl")
+	history := NewPromptHistory(path)
+	for range 200 {
+		history.Add("ses_1", "again")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines > 200 || lines == 0 {
+		t.Errorf("%d lines in the file, want it compacted", lines)
+	}
+	if len(NewPromptHistory(path).Search("")) != 1 {
+		t.Error("duplicates kept after reloading")
+	}
+}
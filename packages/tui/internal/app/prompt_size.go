@@ -0,0 +1,136 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sst/dgmo/internal/image"
+	"github.com/sst/dgmo/internal/paths"
+)
+
+const (
+	// defaultLargePromptTokens is the estimated size of a prompt that is
+	// confirmed before sending
+	defaultLargePromptTokens = 30_000
+	// defaultLargePromptBytes is the size of a prompt with its images that is
+	// confirmed before sending
+	defaultLargePromptBytes = 4 << 20
+	// PinnedContextCompactBytes caps a pinned file when pinned context is
+	// compacted for a prompt
+	PinnedContextCompactBytes = 8 << 10
+)
+
+// PromptPartKind tells where a part of a prompt comes from
+type PromptPartKind string
+
+const (
+	PromptPartText   PromptPartKind = "prompt"
+	PromptPartImage  PromptPartKind = "image"
+	PromptPartPinned PromptPartKind = "pinned"
+)
+
+// PromptPart is one contributor to the size of a prompt
+type PromptPart struct {
+	Kind  PromptPartKind
+	Name  string
+	Bytes int
+}
+
+// PromptSize is what a prompt sends, the biggest parts first
+type PromptSize struct {
+	Parts  []PromptPart
+	Bytes  int
+	Tokens float64
+}
+
+// Has reports whether the prompt has parts of a kind
+func (s PromptSize) Has(kind PromptPartKind) bool {
+	return slices.ContainsFunc(s.Parts, func(part PromptPart) bool { return part.Kind == kind })
+}
+
+// MeasurePrompt adds up the text, the images and the pinned context a prompt
+// sends. Images count towards the bytes only, the tokens an image takes
+// depend on the provider.
+func (a *App) MeasurePrompt(text string, compactPinned bool) PromptSize {
+	size := PromptSize{}
+	add := func(kind PromptPartKind, name string, bytes int) {
+		size.Parts = append(size.Parts, PromptPart{Kind: kind, Name: name, Bytes: bytes})
+		size.Bytes += bytes
+		if kind != PromptPartImage {
+			size.Tokens += float64((bytes + 3) / 4)
+		}
+	}
+
+	add(PromptPartText, "message", len(text))
+	for _, path := range image.ExtractImagePaths(text) {
+		if info, err := os.Stat(path); err == nil {
+			add(PromptPartImage, filepath.Base(path), int(info.Size()))
+		}
+	}
+	limit := pinnedContextMaxBytes
+	if compactPinned {
+		limit = PinnedContextCompactBytes
+	}
+	for _, path := range a.PinnedContext() {
+		if info, err := os.Stat(path); err == nil {
+			add(PromptPartPinned, paths.Relative(path, a.Info.Path.Root), min(int(info.Size()), limit))
+		}
+	}
+
+	slices.SortStableFunc(size.Parts, func(x, y PromptPart) int {
+		return cmp.Compare(y.Bytes, x.Bytes)
+	})
+	return size
+}
+
+// LargePrompt returns why a prompt of the given size asks for confirmation
+// before it is sent, or an empty string when it does not
+func (a *App) LargePrompt(size PromptSize) string {
+	tokens, bytes := a.largePromptThresholds()
+	var reasons []string
+	if tokens > 0 && size.Tokens > float64(tokens) {
+		reasons = append(reasons, fmt.Sprintf("about %.0f tokens, more than %d", size.Tokens, tokens))
+	}
+	if bytes > 0 && size.Bytes > bytes {
+		reasons = append(reasons, fmt.Sprintf("%s, more than %s", FormatBytes(size.Bytes), FormatBytes(bytes)))
+	}
+	return strings.Join(reasons, " and ")
+}
+
+// largePromptThresholds returns the token and byte thresholds of the large
+// prompt check, negative when a check is off
+func (a *App) largePromptThresholds() (tokens, bytes int) {
+	tokens, bytes = a.State.LargePrompt.Tokens, a.State.LargePrompt.Bytes
+	if tokens == 0 {
+		tokens = defaultLargePromptTokens
+	}
+	if bytes == 0 {
+		bytes = defaultLargePromptBytes
+	}
+	return tokens, bytes
+}
+
+// StripImagePaths removes the image paths from a prompt, so the images are
+// not sent
+func StripImagePaths(text string) string {
+	for _, path := range image.ExtractImagePaths(text) {
+		text = strings.ReplaceAll(text, path, "")
+	}
+	return strings.TrimSpace(text)
+}
+
+// FormatBytes shows a size as 512 B, 12.3 KB or 4.0 MB
+func FormatBytes(bytes int) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
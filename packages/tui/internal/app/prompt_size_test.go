@@ -0,0 +1,25 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/sst/dgmo/internal/config"
+)
+
+func TestLargePrompt(t *testing.T) {
+	small := PromptSize{Bytes: 1000, Tokens: 250}
+	big := PromptSize{Bytes: 5 << 20, Tokens: 40_000}
+
+	a := &App{State: &config.State{}}
+	if reason := a.LargePrompt(small); reason != "" {
+		t.Errorf("small prompt is large: %s", reason)
+	}
+	if reason := a.LargePrompt(big); reason != "about 40000 tokens, more than 30000 and 5.0 MB, more than 4.0 MB" {
+		t.Errorf("big prompt reason = %q", reason)
+	}
+
+	a.State.LargePrompt = config.LargePromptConfig{Tokens: -1, Bytes: 100}
+	if reason := a.LargePrompt(small); reason != "1000 B, more than 100 B" {
+		t.Errorf("reason with the token check off = %q", reason)
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// ResendRequest is a user message to send again, once it and every message
+// after it are removed from the session
+type ResendRequest struct {
+	SessionID string
+	MessageID string // the message resent, the first one removed
+	Text      string
+	Removed   int // messages removed, the resent one included
+	// Regenerate is set when the last prompt is resent as it was
+	Regenerate bool
+	// Provider and Model send with another model, nil keeps the current one
+	Provider *opencode.Provider
+	Model    *opencode.Model
+}
+
+// ResendRequestedMsg asks to confirm a resend before any message is removed
+type ResendRequestedMsg struct {
+	Request ResendRequest
+}
+
+// ResendConfirmedMsg is sent when a resend was confirmed
+type ResendConfirmedMsg struct {
+	Request ResendRequest
+}
+
+// MessagesTruncatedMsg is sent once the server removed the messages of a
+// resend, the prompt is sent next
+type MessagesTruncatedMsg struct {
+	Request ResendRequest
+}
+
+// PromptText returns the text typed for a user message. Pinned files are
+// sent as text parts after it and are added again when it is resent.
+func PromptText(message opencode.Message) string {
+	for _, part := range message.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// sentMessage reports whether a message is on the server, the optimistic
+// copy of a prompt being sent is not
+func sentMessage(message opencode.Message) bool {
+	return !strings.HasPrefix(message.ID, "optimistic-")
+}
+
+// UserMessages returns the prompts sent in a session, newest first
+func UserMessages(messages []opencode.Message) []opencode.Message {
+	var prompts []opencode.Message
+	for _, message := range slices.Backward(messages) {
+		if message.Role == opencode.MessageRoleUser && sentMessage(message) && PromptText(message) != "" {
+			prompts = append(prompts, message)
+		}
+	}
+	return prompts
+}
+
+// RegenerateRequest resends the last prompt of messages to get a new
+// response to it
+func RegenerateRequest(messages []opencode.Message) (ResendRequest, error) {
+	prompts := UserMessages(messages)
+	if len(prompts) == 0 {
+		return ResendRequest{}, errors.New("no prompt to regenerate the response of")
+	}
+	request, err := EditRequest(messages, prompts[0].ID, PromptText(prompts[0]))
+	request.Regenerate = true
+	return request, err
+}
+
+// EditRequest resends the user message with an id with new text, dropping
+// everything said after it
+func EditRequest(messages []opencode.Message, messageID, text string) (ResendRequest, error) {
+	if strings.TrimSpace(text) == "" {
+		return ResendRequest{}, errors.New("the message cannot be empty")
+	}
+	i := slices.IndexFunc(messages, func(message opencode.Message) bool { return message.ID == messageID })
+	if i < 0 {
+		return ResendRequest{}, fmt.Errorf("message %s is not in this session", messageID)
+	}
+	if messages[i].Role != opencode.MessageRoleUser {
+		return ResendRequest{}, fmt.Errorf("message %s is not a prompt", messageID)
+	}
+	return ResendRequest{
+		SessionID: messages[i].Metadata.SessionID,
+		MessageID: messageID,
+		Text:      text,
+		Removed:   len(messages) - i,
+	}, nil
+}
+
+// TruncateMessages returns messages without the message with an id and
+// every message after it
+func TruncateMessages(messages []opencode.Message, messageID string) []opencode.Message {
+	i := slices.IndexFunc(messages, func(message opencode.Message) bool { return message.ID == messageID })
+	if i < 0 {
+		return messages
+	}
+	return slices.Clone(messages[:i])
+}
+
+// TruncateSession removes a message and every message after it from a
+// session on the server
+func (a *App) TruncateSession(ctx context.Context, sessionID, messageID string) error {
+	if err := a.Features.Require(FeatureTruncate); err != nil {
+		return err
+	}
+	var removed int
+	endpoint := fmt.Sprintf("/session/%s/truncate", sessionID)
+	params := map[string]any{"messageID": messageID}
+	if err := a.Client.Post(ctx, endpoint, params, &removed); err != nil {
+		return fmt.Errorf("failed to remove messages: %w", err)
+	}
+	return nil
+}
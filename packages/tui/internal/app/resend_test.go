@@ -0,0 +1,88 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func resendMessages(t *testing.T) []opencode.Message {
+	t.Helper()
+	raw := `[
+		{"id": "msg_1", "role": "user", "parts": [{"type": "text", "text": "fix the login bug"},
+		  {"type": "text", "text": "<pinned-context path=\"a.go\">\n</pinned-context>"}],
+		 "metadata": {"sessionID": "ses_1", "time": {"created": 1000}, "tool": {}}},
+		{"id": "msg_2", "role": "assistant", "parts": [{"type": "text", "text": "Done"}],
+		 "metadata": {"sessionID": "ses_1", "time": {"created": 2000}, "tool": {}}},
+		{"id": "msg_3", "role": "user", "parts": [{"type": "text", "text": "now add a test"}],
+		 "metadata": {"sessionID": "ses_1", "time": {"created": 3000}, "tool": {}}},
+		{"id": "msg_4", "role": "assistant", "parts": [{"type": "text", "text": "Added"}],
+		 "metadata": {"sessionID": "ses_1", "time": {"created": 4000}, "tool": {}}},
+		{"id": "optimistic-1", "role": "user", "parts": [{"type": "text", "text": "sending"}],
+		 "metadata": {"sessionID": "ses_1", "time": {"created": 5000}, "tool": {}}}
+	]`
+	var messages []opencode.Message
+	if err := json.Unmarshal([]byte(raw), &messages); err != nil {
+		t.Fatal(err)
+	}
+	return messages
+}
+
+func TestUserMessages(t *testing.T) {
+	prompts := UserMessages(resendMessages(t))
+	if len(prompts) != 2 || prompts[0].ID != "msg_3" || prompts[1].ID != "msg_1" {
+		t.Fatalf("prompts = %+v", prompts)
+	}
+	// pinned files are not part of the prompt
+	if got := PromptText(prompts[1]); got != "fix the login bug" {
+		t.Errorf("PromptText = %q", got)
+	}
+}
+
+func TestRegenerateRequest(t *testing.T) {
+	request, err := RegenerateRequest(resendMessages(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ResendRequest{SessionID: "ses_1", MessageID: "msg_3", Text: "now add a test", Removed: 3, Regenerate: true}
+	if request != want {
+		t.Errorf("request = %+v, want %+v", request, want)
+	}
+
+	if _, err := RegenerateRequest(nil); err == nil {
+		t.Error("regenerating without a prompt should fail")
+	}
+}
+
+func TestEditRequest(t *testing.T) {
+	messages := resendMessages(t)
+	request, err := EditRequest(messages, "msg_1", "fix the logout bug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Text != "fix the logout bug" || request.Removed != 5 || request.Regenerate {
+		t.Errorf("request = %+v", request)
+	}
+
+	for _, tc := range []struct{ id, text string }{
+		{"msg_2", "a response"},
+		{"msg_9", "missing"},
+		{"msg_1", "  \n"},
+	} {
+		if _, err := EditRequest(messages, tc.id, tc.text); err == nil {
+			t.Errorf("EditRequest(%s, %q) should fail", tc.id, tc.text)
+		}
+	}
+}
+
+func TestTruncateMessages(t *testing.T) {
+	messages := resendMessages(t)
+	truncated := TruncateMessages(messages, "msg_3")
+	if len(truncated) != 2 || truncated[1].ID != "msg_2" {
+		t.Fatalf("truncated = %+v", truncated)
+	}
+	if len(TruncateMessages(messages, "msg_9")) != len(messages) {
+		t.Error("an unknown message should remove nothing")
+	}
+}
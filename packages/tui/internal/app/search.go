@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sst/dgmo/internal/search"
+	"github.com/sst/opencode-sdk-go"
+)
+
+const (
+	// searchMaxDocuments and searchMaxBytes cap the local search index, the
+	// oldest entries are dropped first
+	searchMaxDocuments = 20_000
+	searchMaxBytes     = 32 << 20
+	// searchCompactInterval is how often the index is capped and saved
+	searchCompactInterval = time.Minute
+)
+
+// openSearch opens the search index in the data directory and maintains it
+// until ctx is done. If the index can't be read a fresh one is kept in
+// memory, search is a convenience and should not block startup.
+func openSearch(ctx context.Context, dataDir string) search.Index {
+	opts := search.Options{
+		Path:         filepath.Join(dataDir, "search.idx"),
+		MaxDocuments: searchMaxDocuments,
+		MaxBytes:     searchMaxBytes,
+	}
+	index, err := search.Open(search.DefaultBackend, opts)
+	if err != nil {
+		slog.Warn("Failed to open search index, using an in-memory index", "error", err)
+		opts.Path = ""
+		index, _ = search.Open(search.DefaultBackend, opts)
+	}
+	go search.Maintain(ctx, index, searchCompactInterval)
+	return index
+}
+
+// IndexPrompt adds a sent prompt to the search index for recall
+func (a *App) IndexPrompt(sessionID, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	now := time.Now()
+	err := a.Search.Put(search.Document{
+		ID:        fmt.Sprintf("prompt:%d", now.UnixNano()),
+		Kind:      search.KindPrompt,
+		SessionID: sessionID,
+		Text:      text,
+		Time:      now,
+	})
+	if err != nil {
+		slog.Error("Failed to index prompt", "error", err)
+	}
+}
+
+// IndexMessage adds the text of a completed message and the files its tool
+// calls wrote to the search index
+func (a *App) IndexMessage(message opencode.Message) {
+	if message.Metadata.Time.Completed == 0 {
+		return
+	}
+	created := time.UnixMilli(int64(message.Metadata.Time.Created))
+	var text []string
+	for _, part := range message.Parts {
+		if part, ok := part.AsUnion().(opencode.TextPart); ok && strings.TrimSpace(part.Text) != "" {
+			text = append(text, part.Text)
+		}
+	}
+	var docs []search.Document
+	if len(text) > 0 {
+		docs = append(docs, search.Document{
+			ID:        "message:" + message.ID,
+			Kind:      search.KindMessage,
+			SessionID: message.Metadata.SessionID,
+			Title:     string(message.Role),
+			Text:      strings.Join(text, "\n"),
+			Time:      created,
+		})
+	}
+	for _, artifact := range bundleArtifacts([]opencode.Message{message}) {
+		docs = append(docs, search.Document{
+			ID:        "artifact:" + message.Metadata.SessionID + ":" + artifact.Path,
+			Kind:      search.KindArtifact,
+			SessionID: message.Metadata.SessionID,
+			Title:     artifact.Tool,
+			Text:      artifact.Path,
+			Time:      created,
+		})
+	}
+	if len(docs) == 0 {
+		return
+	}
+	if err := a.Search.Put(docs...); err != nil {
+		slog.Error("Failed to index message", "error", err)
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// SessionEnvChangedMsg is sent after the environment variables of a session
+// were changed from the TUI
+type SessionEnvChangedMsg struct {
+	Session opencode.Session
+}
+
+// EnvVar is an environment variable the server sets for the bash tool of a
+// session
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// envName is what the server accepts as a variable name
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// secretEnvName matches the names of variables that usually hold secrets
+var secretEnvName = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|pwd|api_?key|private|credential|auth|session_?key|access_?key)`)
+
+// SessionEnv returns the environment variables of a session sorted by name,
+// none for servers without session environments
+func SessionEnv(session *opencode.Session) []EnvVar {
+	if session == nil {
+		return nil
+	}
+	field, ok := session.JSON.ExtraFields["env"]
+	if !ok || field.IsNull() {
+		return nil
+	}
+	var env map[string]string
+	if err := json.Unmarshal([]byte(field.Raw()), &env); err != nil {
+		return nil
+	}
+	vars := make([]EnvVar, 0, len(env))
+	for name, value := range env {
+		vars = append(vars, EnvVar{Name: name, Value: value})
+	}
+	slices.SortFunc(vars, func(x, y EnvVar) int { return cmp.Compare(x.Name, y.Name) })
+	return vars
+}
+
+// SetSessionEnv replaces the environment variables of a session, no
+// variables removes them all
+func (a *App) SetSessionEnv(ctx context.Context, sessionID string, vars []EnvVar) (*opencode.Session, error) {
+	if err := a.Features.Require(FeatureEnv); err != nil {
+		return nil, err
+	}
+	env := make(map[string]string, len(vars))
+	for _, v := range vars {
+		env[v.Name] = v.Value
+	}
+	var session opencode.Session
+	if err := a.Client.Patch(ctx, "/session/"+sessionID, map[string]any{"env": env}, &session); err != nil {
+		return nil, fmt.Errorf("failed to update session environment: %w", err)
+	}
+	return &session, nil
+}
+
+// SetEnvVar returns vars with a variable added, or replacing the one named
+// previous when it is not empty
+func SetEnvVar(vars []EnvVar, previous string, v EnvVar) ([]EnvVar, error) {
+	v.Name = strings.TrimSpace(v.Name)
+	if !envName.MatchString(v.Name) {
+		return nil, fmt.Errorf("%q is not a variable name, use letters, digits and _", v.Name)
+	}
+	env := make(map[string]string, len(vars))
+	for _, existing := range vars {
+		if existing.Name != previous {
+			env[existing.Name] = existing.Value
+		}
+	}
+	if _, ok := env[v.Name]; ok {
+		return nil, fmt.Errorf("%s is set already", v.Name)
+	}
+	env[v.Name] = v.Value
+
+	updated := make([]EnvVar, 0, len(env))
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		updated = append(updated, EnvVar{Name: name, Value: env[name]})
+	}
+	return updated, nil
+}
+
+// SecretEnv reports whether a variable looks like it holds a secret, by its
+// name or by a password in a URL value
+func SecretEnv(v EnvVar) bool {
+	if secretEnvName.MatchString(v.Name) {
+		return true
+	}
+	parsed, err := url.Parse(v.Value)
+	if err != nil || parsed.User == nil {
+		return false
+	}
+	_, hasPassword := parsed.User.Password()
+	return hasPassword
+}
+
+// MaskedEnvValue returns the value of a variable to show: secrets are
+// masked, and of a URL only the password is
+func MaskedEnvValue(v EnvVar) string {
+	if !SecretEnv(v) || v.Value == "" {
+		return v.Value
+	}
+	if parsed, err := url.Parse(v.Value); err == nil && parsed.User != nil {
+		if _, ok := parsed.User.Password(); ok && parsed.Host != "" {
+			return parsed.Redacted()
+		}
+	}
+	return strings.Repeat("•", 8)
+}
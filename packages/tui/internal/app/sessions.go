@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// SessionTags are the tags that can be put on a session
+var SessionTags = []string{"bug", "feature", "research", BroadcastTag}
+
+// SessionRenamedMsg is sent after the title or description of a session
+// was changed from the TUI
+type SessionRenamedMsg struct {
+	Session opencode.Session
+}
+
+// SessionInstructionsChangedMsg is sent after the instructions of a session
+// were changed from the TUI
+type SessionInstructionsChangedMsg struct {
+	Session opencode.Session
+}
+
+// sessionField reads a string field the SDK does not know about yet
+func sessionField(session *opencode.Session, name string) string {
+	field, ok := session.JSON.ExtraFields[name]
+	if !ok || field.IsNull() {
+		return ""
+	}
+	var value string
+	if err := json.Unmarshal([]byte(field.Raw()), &value); err != nil {
+		return ""
+	}
+	return value
+}
+
+// SessionDescription returns the description of a session, which servers
+// without descriptions never set
+func (a *App) SessionDescription(session *opencode.Session) string {
+	return sessionField(session, "description")
+}
+
+// SessionInstructions returns the instructions the server appends to the
+// system prompt of a session, empty when it only uses the project defaults
+func (a *App) SessionInstructions(session *opencode.Session) string {
+	if session == nil {
+		return ""
+	}
+	return sessionField(session, "instructions")
+}
+
+// SetSessionInstructions stores instructions with a session. Empty
+// instructions go back to the project defaults.
+func (a *App) SetSessionInstructions(ctx context.Context, sessionID, instructions string) (*opencode.Session, error) {
+	if err := a.Features.Require(FeatureInstructions); err != nil {
+		return nil, err
+	}
+	params := map[string]any{"instructions": strings.TrimSpace(instructions)}
+	var session opencode.Session
+	if err := a.Client.Patch(ctx, "/session/"+sessionID, params, &session); err != nil {
+		return nil, fmt.Errorf("failed to update session instructions: %w", err)
+	}
+	return &session, nil
+}
+
+// UpdateSession changes the title and description of a session. An empty
+// description removes it.
+func (a *App) UpdateSession(ctx context.Context, sessionID, title, description string) (*opencode.Session, error) {
+	if err := a.Features.Require(FeatureRename); err != nil {
+		return nil, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("the session title cannot be empty")
+	}
+	params := map[string]any{
+		"title":       title,
+		"description": strings.TrimSpace(description),
+	}
+	var session opencode.Session
+	if err := a.Client.Patch(ctx, "/session/"+sessionID, params, &session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+	return &session, nil
+}
+
+// SessionMeta returns the local tags, archive flag and bookmarks of a
+// session
+func (a *App) SessionMeta(sessionID string) config.SessionMeta {
+	return a.State.Sessions[sessionID]
+}
+
+// ToggleSessionTag adds tag to a session, or removes it if already set
+func (a *App) ToggleSessionTag(sessionID, tag string) {
+	meta := a.SessionMeta(sessionID)
+	if idx := slices.Index(meta.Tags, tag); idx >= 0 {
+		meta.Tags = slices.Delete(slices.Clone(meta.Tags), idx, idx+1)
+	} else {
+		meta.Tags = append(slices.Clone(meta.Tags), tag)
+		slices.SortFunc(meta.Tags, func(x, y string) int {
+			return slices.Index(SessionTags, x) - slices.Index(SessionTags, y)
+		})
+	}
+	a.setSessionMeta(sessionID, meta)
+}
+
+// SetSessionArchived moves a session in or out of the archive
+func (a *App) SetSessionArchived(sessionID string, archived bool) {
+	meta := a.SessionMeta(sessionID)
+	meta.Archived = archived
+	a.setSessionMeta(sessionID, meta)
+}
+
+// setSessionMeta stores meta for a session, dropping empty entries so the
+// state file only lists sessions that carry metadata
+func (a *App) setSessionMeta(sessionID string, meta config.SessionMeta) {
+	_, exists := a.State.Sessions[sessionID]
+	if len(meta.Tags) == 0 && !meta.Archived && len(meta.Bookmarks) == 0 {
+		if !exists {
+			return
+		}
+		delete(a.State.Sessions, sessionID)
+	} else {
+		if a.State.Sessions == nil {
+			a.State.Sessions = make(map[string]config.SessionMeta)
+		}
+		a.State.Sessions[sessionID] = meta
+	}
+	a.SaveState()
+}
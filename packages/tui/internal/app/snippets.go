@@ -0,0 +1,138 @@
+package app
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Snippet is text that replaces its trigger when the trigger is typed in
+// the editor and followed by a space
+type Snippet struct {
+	Trigger string `toml:"trigger"`
+	Text    string `toml:"text"`
+}
+
+// snippetsFile is the layout of the snippets file
+type snippetsFile struct {
+	Snippets []Snippet `toml:"snippets"`
+}
+
+// Snippets holds the editor snippets, kept in a TOML file in the config
+// directory so they can be edited by hand as well
+type Snippets struct {
+	path string
+
+	mu       sync.Mutex
+	snippets []Snippet
+}
+
+// NewSnippets loads the snippets of a file. A missing file holds no
+// snippets, a broken one is logged and left alone until it is saved over.
+func NewSnippets(path string) *Snippets {
+	s := &Snippets{path: path}
+	if err := s.Reload(); err != nil {
+		slog.Warn("Failed to load snippets", "path", path, "error", err)
+	}
+	return s
+}
+
+// Path returns the location of the snippets file
+func (s *Snippets) Path() string {
+	return s.path
+}
+
+// Reload reads the snippets file again
+func (s *Snippets) Reload() error {
+	var file snippetsFile
+	if _, err := toml.DecodeFile(s.path, &file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read snippets file %s: %w", s.path, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snippets = file.Snippets
+	return nil
+}
+
+// List returns the snippets sorted by trigger
+func (s *Snippets) List() []Snippet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snippets := slices.Clone(s.snippets)
+	slices.SortFunc(snippets, func(a, b Snippet) int { return cmp.Compare(a.Trigger, b.Trigger) })
+	return snippets
+}
+
+// Expand returns the text of the snippet a word triggers
+func (s *Snippets) Expand(word string) (string, bool) {
+	if word == "" {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, snippet := range s.snippets {
+		if snippet.Trigger == word {
+			return snippet.Text, true
+		}
+	}
+	return "", false
+}
+
+// Set adds a snippet, or replaces the one with the trigger previous when it
+// is not empty, and saves the file
+func (s *Snippets) Set(previous string, snippet Snippet) error {
+	snippet.Trigger = strings.TrimSpace(snippet.Trigger)
+	switch {
+	case snippet.Trigger == "":
+		return errors.New("a snippet needs a trigger")
+	case strings.ContainsFunc(snippet.Trigger, unicode.IsSpace):
+		return fmt.Errorf("the trigger %q has whitespace, it would never be typed as one word", snippet.Trigger)
+	case snippet.Text == "":
+		return errors.New("a snippet needs text to expand to")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snippets := slices.DeleteFunc(slices.Clone(s.snippets), func(existing Snippet) bool {
+		return existing.Trigger == previous
+	})
+	if slices.ContainsFunc(snippets, func(existing Snippet) bool { return existing.Trigger == snippet.Trigger }) {
+		return fmt.Errorf("there is a snippet for %s already", snippet.Trigger)
+	}
+	return s.save(append(snippets, snippet))
+}
+
+// Delete removes the snippet with a trigger and saves the file
+func (s *Snippets) Delete(trigger string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(slices.DeleteFunc(slices.Clone(s.snippets), func(snippet Snippet) bool {
+		return snippet.Trigger == trigger
+	}))
+}
+
+// save writes the snippets file and keeps the snippets once it is written
+func (s *Snippets) save(snippets []Snippet) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(snippetsFile{Snippets: snippets}); err != nil {
+		return fmt.Errorf("failed to encode snippets: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write snippets file %s: %w", s.path, err)
+	}
+	s.snippets = snippets
+	return nil
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnippets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "snippets.toml")
+	snippets := NewSnippets(path)
+	if len(snippets.List()) != 0 {
+		t.Fatalf("snippets without a file: %v", snippets.List())
+	}
+
+	if err := snippets.Set("", Snippet{Trigger: ";;rev", Text: "Please review the following code for bugs:"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := snippets.Set("", Snippet{Trigger: ";;t", Text: "Add tests"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := snippets.Set("", Snippet{Trigger: ";;t", Text: "again"}); err == nil {
+		t.Error("added a second snippet with the same trigger")
+	}
+	if err := snippets.Set("", Snippet{Trigger: ";; t", Text: "x"}); err == nil {
+		t.Error("added a trigger with whitespace")
+	}
+	if err := snippets.Set(";;t", Snippet{Trigger: ";;test", Text: "Add tests"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewSnippets(path)
+	if text, ok := reloaded.Expand(";;rev"); !ok || !strings.HasPrefix(text, "Please review") {
+		t.Errorf("Expand(;;rev) = %q, %t after reloading", text, ok)
+	}
+	if _, ok := reloaded.Expand(";;t"); ok {
+		t.Error("the renamed trigger still expands")
+	}
+	if list := reloaded.List(); len(list) != 2 || list[0].Trigger != ";;rev" || list[1].Trigger != ";;test" {
+		t.Errorf("List = %v", list)
+	}
+
+	if err := reloaded.Delete(";;rev"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), ";;rev") {
+		t.Errorf("deleted snippet still in the file:\n%s", data)
+	}
+}
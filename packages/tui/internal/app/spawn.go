@@ -0,0 +1,106 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MaxSpawnAgents bounds how many agents the spawn dialog launches at once
+const MaxSpawnAgents = 8
+
+// AgentSpec is what the user assigned to one agent in the spawn dialog
+type AgentSpec struct {
+	// Task is the part of the goal the agent works on, empty to let the
+	// agent pick an angle on its own
+	Task string
+	// Model is given as provider/model, empty for the model of the session
+	Model string
+}
+
+// TaskInvocation holds the parameters of one call to the task tool
+type TaskInvocation struct {
+	Description string `json:"description"`
+	Prompt      string `json:"prompt"`
+	AgentMode   string `json:"agentMode"`
+	Model       string `json:"model,omitempty"`
+}
+
+// summarize shortens text to the three to five words the task tool wants
+// as a description
+func summarize(text string) string {
+	words := strings.Fields(text)
+	if len(words) > 5 {
+		words = words[:5]
+	}
+	return strings.Join(words, " ")
+}
+
+// SpawnInvocations turns a goal and the agents assigned to it into task tool
+// calls, one per agent
+func SpawnInvocations(goal string, specs []AgentSpec, writable bool) ([]TaskInvocation, error) {
+	goal = strings.TrimSpace(goal)
+	if goal == "" {
+		return nil, fmt.Errorf("describe the goal first")
+	}
+	if len(specs) == 0 || len(specs) > MaxSpawnAgents {
+		return nil, fmt.Errorf("choose between 1 and %d agents", MaxSpawnAgents)
+	}
+	mode := "read-only"
+	if writable {
+		mode = "all-tools"
+	}
+
+	invocations := make([]TaskInvocation, len(specs))
+	for i, spec := range specs {
+		task := strings.TrimSpace(spec.Task)
+		model := strings.TrimSpace(spec.Model)
+		if model != "" && !strings.Contains(model, "/") {
+			return nil, fmt.Errorf("agent %d: model %q is not provider/model", i+1, model)
+		}
+
+		var prompt strings.Builder
+		fmt.Fprintf(&prompt, "Overall goal: %s\n\n", goal)
+		description := summarize(task)
+		if task != "" {
+			fmt.Fprintf(&prompt, "Your part: %s\n\n", task)
+		} else {
+			description = fmt.Sprintf("%s (agent %d)", summarize(goal), i+1)
+			fmt.Fprintf(&prompt, "You are agent %d of %d working on this goal. ", i+1, len(specs))
+		}
+		if len(specs) > 1 {
+			prompt.WriteString("Other agents work on the rest in parallel, stay within your part.\n\n")
+		}
+		prompt.WriteString("Report what you found or changed in your final message.")
+
+		invocations[i] = TaskInvocation{
+			Description: description,
+			Prompt:      prompt.String(),
+			AgentMode:   mode,
+			Model:       model,
+		}
+	}
+	return invocations, nil
+}
+
+// SpawnPrompt is the message that asks the assistant to make the task tool
+// calls exactly as previewed, all in one response so the agents run together
+func SpawnPrompt(invocations []TaskInvocation) (string, error) {
+	var text strings.Builder
+	fmt.Fprintf(
+		&text,
+		"Launch %d agents in parallel by calling the task tool %d times in a single response, with exactly these parameters:\n\n",
+		len(invocations), len(invocations),
+	)
+	for _, invocation := range invocations {
+		params, err := json.MarshalIndent(invocation, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		text.WriteString("```json\n")
+		text.Write(params)
+		text.WriteString("\n```\n\n")
+	}
+	text.WriteString("Once all agents are done, summarize their results.")
+	return text.String(), nil
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// subSessionCacheTTL is how long a listing of sub-sessions is reused. The
+// dialog and sibling navigation ask for the same parents in quick
+// succession, agents that start later show after that long at most.
+const subSessionCacheTTL = 5 * time.Second
+
+// SubSessionStatus is the state of the agent working in a sub-session
+type SubSessionStatus string
+
+const (
+	SubSessionPending   SubSessionStatus = "pending"
+	SubSessionRunning   SubSessionStatus = "running"
+	SubSessionCompleted SubSessionStatus = "completed"
+	SubSessionFailed    SubSessionStatus = "failed"
+)
+
+// SubSession is a session an agent works in on behalf of its parent
+type SubSession struct {
+	ID        string `json:"id"`
+	ParentID  string `json:"parentSessionId"`
+	AgentName string `json:"agentName"`
+	// AgentNumber is the 1-based position among the sub-sessions of the
+	// parent, 0 for records written before the server stored it
+	AgentNumber int              `json:"agentNumber"`
+	Task        string           `json:"taskDescription"`
+	Status      SubSessionStatus `json:"status"`
+	CreatedAt   float64          `json:"createdAt"`
+	Summary     string           `json:"summary,omitempty"`
+}
+
+// Created returns when the sub-session was created
+func (s SubSession) Created() time.Time {
+	return time.UnixMilli(int64(s.CreatedAt))
+}
+
+// ErrNotSubSession is returned when sibling navigation starts from a session
+// without a parent
+var ErrNotSubSession = errors.New("not in a sub-session")
+
+// ErrNoSiblings is returned when a sub-session has no siblings to move to
+var ErrNoSiblings = errors.New("no sibling sub-sessions")
+
+// SubSessionError is returned when the sub-sessions of a parent fail to load
+type SubSessionError struct {
+	ParentID string
+	Err      error
+}
+
+func (e *SubSessionError) Error() string {
+	return fmt.Sprintf("failed to list sub-sessions of %s: %v", e.ParentID, e.Err)
+}
+
+func (e *SubSessionError) Unwrap() error {
+	return e.Err
+}
+
+type subSessionListing struct {
+	subSessions []SubSession
+	loaded      time.Time
+}
+
+// SubSessionService talks to the server's sub-session endpoints
+type SubSessionService struct {
+	client *opencode.Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]subSessionListing // by parent session ID
+}
+
+// NewSubSessionService creates a sub-session service using the given client
+func NewSubSessionService(client *opencode.Client) *SubSessionService {
+	return &SubSessionService{
+		client: client,
+		ttl:    subSessionCacheTTL,
+		now:    time.Now,
+		cache:  make(map[string]subSessionListing),
+	}
+}
+
+// List returns the sub-sessions of a parent session, oldest first. Listings
+// are cached for a few seconds, Invalidate drops them.
+func (s *SubSessionService) List(ctx context.Context, parentID string) ([]SubSession, error) {
+	s.mu.Lock()
+	listing, ok := s.cache[parentID]
+	s.mu.Unlock()
+	if ok && s.now().Sub(listing.loaded) < s.ttl {
+		return slices.Clone(listing.subSessions), nil
+	}
+
+	var subSessions []SubSession
+	endpoint := fmt.Sprintf("/session/%s/sub-sessions", parentID)
+	if err := s.client.Get(ctx, endpoint, nil, &subSessions); err != nil {
+		return nil, &SubSessionError{ParentID: parentID, Err: err}
+	}
+	slices.SortStableFunc(subSessions, func(a, b SubSession) int {
+		return cmp.Compare(a.CreatedAt, b.CreatedAt)
+	})
+
+	s.mu.Lock()
+	s.cache[parentID] = subSessionListing{subSessions: subSessions, loaded: s.now()}
+	s.mu.Unlock()
+	return slices.Clone(subSessions), nil
+}
+
+// Invalidate drops the cached listings, of every parent when none is given
+func (s *SubSessionService) Invalidate(parentIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(parentIDs) == 0 {
+		clear(s.cache)
+		return
+	}
+	for _, parentID := range parentIDs {
+		delete(s.cache, parentID)
+	}
+}
+
+// Sibling returns the ID of the sub-session next to session among the
+// sub-sessions of its parent, the next one for a positive step and the
+// previous one otherwise, wrapping around the ends
+func (s *SubSessionService) Sibling(ctx context.Context, session *opencode.Session, step int) (string, error) {
+	if session == nil || session.ParentID == "" {
+		return "", ErrNotSubSession
+	}
+	siblings, err := s.List(ctx, session.ParentID)
+	if err != nil {
+		return "", err
+	}
+	if len(siblings) <= 1 {
+		return "", ErrNoSiblings
+	}
+	current := slices.IndexFunc(siblings, func(sub SubSession) bool { return sub.ID == session.ID })
+	if current < 0 {
+		return "", fmt.Errorf("session %s is not a sub-session of %s", session.ID, session.ParentID)
+	}
+	next := current - 1
+	if step > 0 {
+		next = current + 1
+	}
+	next = (next + len(siblings)) % len(siblings)
+	return siblings[next].ID, nil
+}
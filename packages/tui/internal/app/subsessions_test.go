@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+	"github.com/sst/opencode-sdk-go/option"
+)
+
+func newTestSubSessionService(t *testing.T, handler http.HandlerFunc) *SubSessionService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := opencode.NewClient(
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+	return NewSubSessionService(client)
+}
+
+const testSubSessions = `[
+	{"id":"ses_b","parentSessionId":"ses_p","agentName":"review","agentNumber":2,"taskDescription":"review","status":"running","createdAt":2000},
+	{"id":"ses_a","parentSessionId":"ses_p","agentName":"build","agentNumber":1,"taskDescription":"build","status":"completed","createdAt":1000},
+	{"id":"ses_c","parentSessionId":"ses_p","agentName":"docs","taskDescription":"docs","status":"pending","createdAt":3000}
+]`
+
+func TestSubSessionList(t *testing.T) {
+	var requests atomic.Int32
+	service := newTestSubSessionService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/session/ses_p/sub-sessions" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testSubSessions))
+	})
+
+	subSessions, err := service.List(context.Background(), "ses_p")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(subSessions) != 3 || subSessions[0].ID != "ses_a" || subSessions[2].ID != "ses_c" {
+		t.Fatalf("List = %+v, want the sub-sessions oldest first", subSessions)
+	}
+	first := subSessions[0]
+	if first.ParentID != "ses_p" || first.AgentNumber != 1 || first.Status != SubSessionCompleted || !first.Created().Equal(time.UnixMilli(1000)) {
+		t.Errorf("decoded sub-session = %+v", first)
+	}
+
+	// the listing is cached until it expires or is invalidated
+	service.List(context.Background(), "ses_p")
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d requests for a cached listing, want 1", got)
+	}
+	service.Invalidate("ses_p")
+	service.List(context.Background(), "ses_p")
+	now := time.Now()
+	service.now = func() time.Time { return now.Add(subSessionCacheTTL) }
+	service.List(context.Background(), "ses_p")
+	if got := requests.Load(); got != 3 {
+		t.Errorf("%d requests after invalidating and expiring, want 3", got)
+	}
+}
+
+func TestSubSessionListError(t *testing.T) {
+	service := newTestSubSessionService(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	_, err := service.List(context.Background(), "ses_p")
+	var listErr *SubSessionError
+	if !errors.As(err, &listErr) || listErr.ParentID != "ses_p" {
+		t.Errorf("List error = %v, want a SubSessionError for ses_p", err)
+	}
+}
+
+func TestSubSessionSibling(t *testing.T) {
+	service := newTestSubSessionService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testSubSessions))
+	})
+	ctx := context.Background()
+	session := &opencode.Session{ID: "ses_c", ParentID: "ses_p"}
+
+	if next, err := service.Sibling(ctx, session, 1); err != nil || next != "ses_a" {
+		t.Errorf("next sibling = %q, %v, want ses_a after wrapping", next, err)
+	}
+	if previous, err := service.Sibling(ctx, session, -1); err != nil || previous != "ses_b" {
+		t.Errorf("previous sibling = %q, %v, want ses_b", previous, err)
+	}
+	if _, err := service.Sibling(ctx, &opencode.Session{ID: "ses_p"}, 1); !errors.Is(err, ErrNotSubSession) {
+		t.Errorf("sibling of a main session: %v, want ErrNotSubSession", err)
+	}
+}
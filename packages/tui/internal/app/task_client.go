@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,14 +14,46 @@ import (
 
 // TaskClient manages WebSocket connection for task events
 type TaskClient struct {
-	url       string
-	conn      *websocket.Conn
-	mu        sync.RWMutex
-	tasks     map[string]*TaskInfo
-	handlers  TaskEventHandlers
+	url      string
+	conn     *websocket.Conn
+	mu       sync.RWMutex
+	tasks    map[string]*TaskInfo
+	handlers TaskEventHandlers
+	// numbers holds the agent number of every task seen, it outlives the
+	// task itself so reconnects and replayed events keep their numbering
+	numbers map[string]int
+	// agents counts the agents started per parent session
+	agents    map[string]int
 	reconnect bool
 	ctx       context.Context
 	cancel    context.CancelFunc
+	// latency is the round trip of the last answered ping
+	latency  time.Duration
+	lastPing time.Time
+	lastPong time.Time
+}
+
+const (
+	// taskPingInterval is how often the task connection is pinged
+	taskPingInterval = 10 * time.Second
+	// taskLagThreshold is the round trip above which the connection is
+	// reported as lagging
+	taskLagThreshold = time.Second
+)
+
+// ConnectionStats describes the health of the task event connection
+type ConnectionStats struct {
+	Connected bool
+	// Latency is the round trip of the last ping, or how long the pending
+	// ping has waited if that is longer
+	Latency  time.Duration
+	LastPing time.Time
+	LastPong time.Time
+}
+
+// Lagging reports whether the connection answers pings too slowly
+func (s ConnectionStats) Lagging() bool {
+	return s.Connected && s.Latency > taskLagThreshold
 }
 
 // TaskEventHandlers contains callbacks for task events
@@ -43,17 +76,21 @@ type TaskStartedData struct {
 	TaskID      string `json:"taskID"`
 	AgentName   string `json:"agentName"`
 	Description string `json:"taskDescription"`
-	Timestamp   int64  `json:"timestamp"`
+	// AgentNumber is the number the server assigned, 0 from servers that
+	// don't send one
+	AgentNumber int   `json:"agentNumber,omitempty"`
+	Timestamp   int64 `json:"timestamp"`
 }
 
 // TaskProgressData represents task.progress event data
 type TaskProgressData struct {
-	SessionID string `json:"sessionID"`
-	TaskID    string `json:"taskID"`
-	Progress  int    `json:"progress"`
-	Message   string `json:"message,omitempty"`
-	Timestamp int64  `json:"timestamp"`
-	StartTime int64  `json:"startTime,omitempty"`
+	SessionID   string `json:"sessionID"`
+	TaskID      string `json:"taskID"`
+	Progress    int    `json:"progress"`
+	Message     string `json:"message,omitempty"`
+	CurrentTool string `json:"currentTool,omitempty"`
+	Timestamp   int64  `json:"timestamp"`
+	StartTime   int64  `json:"startTime,omitempty"`
 }
 
 // TaskCompletedData represents task.completed event data
@@ -81,6 +118,8 @@ func NewTaskClient(handlers TaskEventHandlers) *TaskClient {
 	return &TaskClient{
 		url:       "ws://localhost:5747",
 		tasks:     make(map[string]*TaskInfo),
+		numbers:   make(map[string]int),
+		agents:    make(map[string]int),
 		handlers:  handlers,
 		reconnect: true,
 		ctx:       ctx,
@@ -103,7 +142,22 @@ func (tc *TaskClient) Connect() error {
 	}
 
 	tc.conn = conn
+	tc.latency, tc.lastPing, tc.lastPong = 0, time.Time{}, time.Time{}
+	// the pong echoes the send time of its ping, answered pings are read
+	// by the read loop
+	conn.SetPongHandler(func(payload string) error {
+		sent, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return nil
+		}
+		tc.mu.Lock()
+		tc.lastPong = time.Now()
+		tc.latency = tc.lastPong.Sub(time.Unix(0, sent))
+		tc.mu.Unlock()
+		return nil
+	})
 	go tc.readLoop()
+	go tc.pingLoop(conn)
 	slog.Info("Connected to task event server", "url", tc.url)
 	return nil
 }
@@ -130,6 +184,96 @@ func (tc *TaskClient) GetTask(taskID string) (*TaskInfo, bool) {
 	return task, ok
 }
 
+// Tasks returns a snapshot of all known tasks, oldest first
+func (tc *TaskClient) Tasks() []TaskInfo {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	tasks := make([]TaskInfo, 0, len(tc.tasks))
+	for _, task := range tc.tasks {
+		tasks = append(tasks, *task)
+	}
+	SortTasks(tasks)
+	return tasks
+}
+
+// agentNumber returns the number of taskID. The number the server sent wins,
+// without one the next free number of its parent session is assigned on
+// first sight. Callers must hold the lock.
+func (tc *TaskClient) agentNumber(sessionID, taskID string, canonical int) int {
+	if canonical > 0 {
+		tc.numbers[taskID] = canonical
+		// numbers counted locally continue after the server's
+		tc.agents[sessionID] = max(tc.agents[sessionID], canonical)
+		return canonical
+	}
+	if number, ok := tc.numbers[taskID]; ok {
+		return number
+	}
+	tc.agents[sessionID]++
+	tc.numbers[taskID] = tc.agents[sessionID]
+	return tc.numbers[taskID]
+}
+
+// IsConnected reports whether the task event connection is open
+func (tc *TaskClient) IsConnected() bool {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.conn != nil
+}
+
+// GetConnectionStats returns the state and latency of the task connection
+func (tc *TaskClient) GetConnectionStats() ConnectionStats {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	stats := ConnectionStats{
+		Connected: tc.conn != nil,
+		Latency:   tc.latency,
+		LastPing:  tc.lastPing,
+		LastPong:  tc.lastPong,
+	}
+	// a ping that is still unanswered counts for as long as it waits
+	if tc.lastPing.After(tc.lastPong) {
+		stats.Latency = max(stats.Latency, time.Since(tc.lastPing))
+	}
+	return stats
+}
+
+// pingLoop pings conn until it is replaced or closed, measuring the round
+// trip so a lagging task channel shows up before events go missing
+func (tc *TaskClient) pingLoop(conn *websocket.Conn) {
+	ticker := time.NewTicker(taskPingInterval)
+	defer ticker.Stop()
+	for {
+		tc.ping(conn)
+		select {
+		case <-tc.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		tc.mu.RLock()
+		current := tc.conn == conn
+		tc.mu.RUnlock()
+		if !current {
+			return
+		}
+	}
+}
+
+func (tc *TaskClient) ping(conn *websocket.Conn) {
+	now := time.Now()
+	payload := []byte(strconv.FormatInt(now.UnixNano(), 10))
+	if err := conn.WriteControl(websocket.PingMessage, payload, now.Add(taskPingInterval)); err != nil {
+		slog.Debug("Failed to ping task event server", "error", err)
+		return
+	}
+	tc.mu.Lock()
+	// keep the oldest unanswered ping so a stalled connection keeps aging
+	if !tc.lastPing.After(tc.lastPong) {
+		tc.lastPing = now
+	}
+	tc.mu.Unlock()
+}
+
 // readLoop handles incoming WebSocket messages
 func (tc *TaskClient) readLoop() {
 	defer func() {
@@ -186,17 +330,23 @@ func (tc *TaskClient) handleEvent(event TaskEvent) {
 			return
 		}
 
+		tc.mu.Lock()
+		if existing, ok := tc.tasks[data.TaskID]; ok {
+			// a replayed start must not reset the progress made since
+			tc.mu.Unlock()
+			slog.Debug("Ignoring repeated task.started event", "taskID", existing.ID)
+			return
+		}
 		task := TaskInfo{
 			ID:          data.TaskID,
 			SessionID:   data.SessionID,
 			AgentName:   data.AgentName,
+			AgentNumber: tc.agentNumber(data.SessionID, data.TaskID, data.AgentNumber),
 			Description: data.Description,
 			Status:      TaskStatusRunning,
 			Progress:    0,
 			StartTime:   time.Unix(0, data.Timestamp*int64(time.Millisecond)),
 		}
-
-		tc.mu.Lock()
 		tc.tasks[data.TaskID] = &task
 		tc.mu.Unlock()
 
@@ -214,6 +364,8 @@ func (tc *TaskClient) handleEvent(event TaskEvent) {
 		tc.mu.Lock()
 		if task, ok := tc.tasks[data.TaskID]; ok {
 			task.Progress = data.Progress
+			task.Message = data.Message
+			task.CurrentTool = data.CurrentTool
 			if data.StartTime > 0 {
 				task.StartTime = time.Unix(0, data.StartTime*int64(time.Millisecond))
 			}
@@ -236,6 +388,7 @@ func (tc *TaskClient) handleEvent(event TaskEvent) {
 		if task, ok := tc.tasks[data.TaskID]; ok {
 			task.Status = TaskStatusCompleted
 			task.Progress = 100
+			task.CurrentTool = ""
 			task.Duration = time.Duration(data.Duration) * time.Millisecond
 		}
 		tc.mu.Unlock()
@@ -263,6 +416,7 @@ func (tc *TaskClient) handleEvent(event TaskEvent) {
 		if task, ok := tc.tasks[data.TaskID]; ok {
 			task.Status = TaskStatusFailed
 			task.Error = data.Error
+			task.CurrentTool = ""
 		}
 		tc.mu.Unlock()
 
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func taskEvent(t *testing.T, eventType string, data any) TaskEvent {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return TaskEvent{Type: eventType, Data: raw}
+}
+
+func startTask(t *testing.T, tc *TaskClient, sessionID, taskID string, timestamp int64) {
+	t.Helper()
+	tc.handleEvent(taskEvent(t, "task.started", TaskStartedData{
+		SessionID: sessionID,
+		TaskID:    taskID,
+		AgentName: "agent " + taskID,
+		Timestamp: timestamp,
+	}))
+}
+
+func agentNumbers(tc *TaskClient) map[string]int {
+	numbers := make(map[string]int)
+	for _, task := range tc.Tasks() {
+		numbers[task.ID] = task.AgentNumber
+	}
+	return numbers
+}
+
+func TestTaskClientNumbersAgentsPerSession(t *testing.T) {
+	tc := NewTaskClient(TaskEventHandlers{})
+	startTask(t, tc, "ses_a", "t1", 1000)
+	startTask(t, tc, "ses_a", "t2", 2000)
+	startTask(t, tc, "ses_b", "t3", 3000)
+
+	want := map[string]int{"t1": 1, "t2": 2, "t3": 1}
+	got := agentNumbers(tc)
+	for id, number := range want {
+		if got[id] != number {
+			t.Errorf("%s is agent %d, want %d", id, got[id], number)
+		}
+	}
+}
+
+func TestTaskClientReplayedStart(t *testing.T) {
+	tc := NewTaskClient(TaskEventHandlers{})
+	startTask(t, tc, "ses_a", "t1", 1000)
+	tc.handleEvent(taskEvent(t, "task.progress", TaskProgressData{
+		SessionID: "ses_a",
+		TaskID:    "t1",
+		Progress:  50,
+		Message:   "halfway",
+	}))
+
+	startTask(t, tc, "ses_a", "t1", 1000)
+
+	task, ok := tc.GetTask("t1")
+	if !ok {
+		t.Fatal("task was dropped by the replayed start")
+	}
+	if task.Progress != 50 || task.Message != "halfway" {
+		t.Errorf("progress was reset to %d %q", task.Progress, task.Message)
+	}
+	if task.AgentNumber != 1 {
+		t.Errorf("task is agent %d, want 1", task.AgentNumber)
+	}
+}
+
+func TestTaskClientKeepsNumbersAcrossReconnect(t *testing.T) {
+	tc := NewTaskClient(TaskEventHandlers{})
+	startTask(t, tc, "ses_a", "t1", 1000)
+	startTask(t, tc, "ses_a", "t2", 2000)
+
+	// finished tasks are cleaned up, then the server replays them after a
+	// reconnect in a different order
+	tc.mu.Lock()
+	clear(tc.tasks)
+	tc.mu.Unlock()
+	startTask(t, tc, "ses_a", "t2", 2000)
+	startTask(t, tc, "ses_a", "t1", 1000)
+	startTask(t, tc, "ses_a", "t3", 3000)
+
+	want := map[string]int{"t1": 1, "t2": 2, "t3": 3}
+	got := agentNumbers(tc)
+	for id, number := range want {
+		if got[id] != number {
+			t.Errorf("%s is agent %d, want %d", id, got[id], number)
+		}
+	}
+}
+
+func TestTaskClientTrustsServerNumbers(t *testing.T) {
+	tc := NewTaskClient(TaskEventHandlers{})
+	// t1 started before the client connected, the server numbers it 2
+	tc.handleEvent(taskEvent(t, "task.started", TaskStartedData{
+		SessionID:   "ses_a",
+		TaskID:      "t1",
+		AgentNumber: 2,
+		Timestamp:   1000,
+	}))
+	// an older server without numbers, counted on after the server's
+	startTask(t, tc, "ses_a", "t2", 2000)
+
+	want := map[string]int{"t1": 2, "t2": 3}
+	got := agentNumbers(tc)
+	for id, number := range want {
+		if got[id] != number {
+			t.Errorf("%s is agent %d, want %d", id, got[id], number)
+		}
+	}
+}
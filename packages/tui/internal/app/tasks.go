@@ -1,6 +1,8 @@
 package app
 
 import (
+	"fmt"
+	"sort"
 	"time"
 )
 
@@ -9,14 +11,43 @@ type TaskInfo struct {
 	ID          string
 	SessionID   string
 	AgentName   string
+	AgentNumber int // 1-based position among the agents of the parent session
 	Description string
 	Status      TaskStatus
 	Progress    int
+	Message     string // latest progress message
+	CurrentTool string // tool the agent is running, if any
 	StartTime   time.Time
 	Duration    time.Duration
 	Error       string
 }
 
+// Label returns the agent number and name, e.g. "Agent 2: review"
+func (t TaskInfo) Label() string {
+	if t.AgentNumber == 0 {
+		return t.AgentName
+	}
+	if t.AgentName == "" {
+		return fmt.Sprintf("Agent %d", t.AgentNumber)
+	}
+	return fmt.Sprintf("Agent %d: %s", t.AgentNumber, t.AgentName)
+}
+
+// SortTasks orders tasks by start time, then agent number, so the order is
+// the same no matter in which order the events arrived
+func SortTasks(tasks []TaskInfo) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		if a.AgentNumber != b.AgentNumber {
+			return a.AgentNumber < b.AgentNumber
+		}
+		return a.ID < b.ID
+	})
+}
+
 // TaskStatus represents the status of a task
 type TaskStatus int
 
@@ -53,3 +84,30 @@ type TaskFailedMsg struct {
 	Error       string
 	Recoverable bool
 }
+
+// TaskToastEvent is an agent task event that may show a toast
+type TaskToastEvent int
+
+const (
+	TaskToastStarted TaskToastEvent = iota
+	TaskToastCompleted
+	TaskToastFailed
+)
+
+// TaskToastsFor reports whether the configured task toasts include event
+func (a *App) TaskToastsFor(event TaskToastEvent) bool {
+	switch a.State.TaskToasts {
+	case "off":
+		return false
+	case "failures":
+		return event == TaskToastFailed
+	}
+	return true
+}
+
+func (a *App) taskToasts() string {
+	if a.State.TaskToasts == "" {
+		return "all"
+	}
+	return a.State.TaskToasts
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSortTasks(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tasks := []TaskInfo{
+		{ID: "late", AgentNumber: 1, StartTime: start.Add(time.Second)},
+		{ID: "b", AgentNumber: 2, StartTime: start},
+		{ID: "c2", AgentNumber: 3, StartTime: start},
+		{ID: "a", AgentNumber: 1, StartTime: start},
+		{ID: "c1", AgentNumber: 3, StartTime: start},
+	}
+	want := []string{"a", "b", "c1", "c2", "late"}
+
+	// the order must not depend on the order the events arrived in
+	for i := range tasks {
+		shuffled := append(slices.Clone(tasks[i:]), tasks[:i]...)
+		SortTasks(shuffled)
+		var got []string
+		for _, task := range shuffled {
+			got = append(got, task.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("rotation %d sorted to %v, want %v", i, got, want)
+		}
+	}
+}
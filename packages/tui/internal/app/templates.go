@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sst/dgmo/internal/config"
+)
+
+// templateVariablePattern matches {{name}} placeholders in prompt templates
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// TemplateVariables returns the placeholder names of prompt in order of
+// first appearance
+func TemplateVariables(prompt string) []string {
+	var names []string
+	for _, match := range templateVariablePattern.FindAllStringSubmatch(prompt, -1) {
+		if !slices.Contains(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// FillTemplate replaces the placeholders of prompt with values, leaving
+// placeholders without a value as they are
+func FillTemplate(prompt string, values map[string]string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(prompt, func(placeholder string) string {
+		name := templateVariablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// TemplatesDir returns the directory holding the prompt template library,
+// one markdown file per template
+func (a *App) TemplatesDir() string {
+	return filepath.Join(a.Info.Path.Config, "templates")
+}
+
+// ListTemplates returns the pinned templates followed by the templates in
+// the library, sorted by name
+func (a *App) ListTemplates() ([]config.PromptTemplate, error) {
+	templates := slices.Clone(a.State.Templates)
+
+	entries, err := os.ReadDir(a.TemplatesDir())
+	if os.IsNotExist(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return templates, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(a.TemplatesDir(), entry.Name()))
+		if err != nil {
+			return templates, fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
+		}
+		templates = append(templates, config.PromptTemplate{
+			Name:   strings.TrimSuffix(entry.Name(), ".md"),
+			Prompt: strings.TrimSpace(string(data)),
+		})
+	}
+	return templates, nil
+}
+
+// FindTemplate returns the template with the given name
+func (a *App) FindTemplate(name string) (config.PromptTemplate, bool) {
+	templates, _ := a.ListTemplates()
+	for _, template := range templates {
+		if template.Name == name {
+			return template, true
+		}
+	}
+	return config.PromptTemplate{}, false
+}
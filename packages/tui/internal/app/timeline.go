@@ -0,0 +1,112 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+// TimelineKind is what a timeline entry stands for
+type TimelineKind int
+
+const (
+	TimelinePrompt TimelineKind = iota
+	TimelineResponse
+	TimelineTool
+	TimelineAgent
+	TimelineCheckpoint
+)
+
+// TimelineEntry is one event of a session. Duration is 0 for events that
+// have none or have not finished.
+type TimelineEntry struct {
+	Kind      TimelineKind
+	Time      time.Time
+	Duration  time.Duration
+	Label     string
+	MessageID string
+}
+
+// timelineArgs are the tool arguments that best describe a call, by
+// preference
+var timelineArgs = []string{"description", "filePath", "command", "pattern", "url", "path"}
+
+// BuildTimeline orders the prompts, responses, tool calls, sub-agent spawns
+// and checkpoints of a session by time
+func BuildTimeline(messages []opencode.Message, checkpoints []Checkpoint) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, message := range messages {
+		created := time.UnixMilli(int64(message.Metadata.Time.Created))
+		entry := TimelineEntry{
+			Kind:      TimelinePrompt,
+			Time:      created,
+			Label:     timelineText(message),
+			MessageID: message.ID,
+		}
+		if message.Role == opencode.MessageRoleAssistant {
+			entry.Kind = TimelineResponse
+			if completed := message.Metadata.Time.Completed; completed > 0 {
+				entry.Duration = time.Duration(completed-message.Metadata.Time.Created) * time.Millisecond
+			}
+			if entry.Label == "" {
+				entry.Label = message.Metadata.Assistant.ModelID
+			}
+		}
+		entries = append(entries, entry)
+
+		for _, part := range message.Parts {
+			toolCall, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok {
+				continue
+			}
+			call := TimelineEntry{
+				Kind:      TimelineTool,
+				Time:      created,
+				Label:     toolCall.ToolInvocation.ToolName,
+				MessageID: message.ID,
+			}
+			if toolCall.ToolInvocation.ToolName == "task" {
+				call.Kind = TimelineAgent
+			}
+			if args, ok := toolCall.ToolInvocation.Args.(map[string]any); ok {
+				for _, name := range timelineArgs {
+					if value, ok := args[name].(string); ok && value != "" {
+						call.Label += " " + value
+						break
+					}
+				}
+			}
+			if metadata, ok := message.Metadata.Tool[toolCall.ToolInvocation.ToolCallID]; ok && metadata.Time.Start > 0 {
+				call.Time = time.UnixMilli(int64(metadata.Time.Start))
+				if metadata.Time.End > 0 {
+					call.Duration = time.Duration(metadata.Time.End-metadata.Time.Start) * time.Millisecond
+				}
+			}
+			entries = append(entries, call)
+		}
+	}
+	for _, checkpoint := range checkpoints {
+		entries = append(entries, TimelineEntry{
+			Kind:      TimelineCheckpoint,
+			Time:      checkpoint.Time(),
+			Label:     checkpoint.Description,
+			MessageID: checkpoint.MessageID,
+		})
+	}
+	slices.SortStableFunc(entries, func(a, b TimelineEntry) int {
+		return a.Time.Compare(b.Time)
+	})
+	return entries
+}
+
+// timelineText returns the first text of a message on a single line
+func timelineText(message opencode.Message) string {
+	for _, part := range message.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok && strings.TrimSpace(text.Text) != "" {
+			return strings.Join(strings.Fields(text.Text), " ")
+		}
+	}
+	return ""
+}
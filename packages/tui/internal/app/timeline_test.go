@@ -0,0 +1,49 @@
+package app
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	raw := `[
+		{"id": "msg_1", "role": "user", "parts": [{"type": "text", "text": "fix\n the  login bug"}],
+		 "metadata": {"sessionID": "ses_1", "time": {"created": 1000}, "tool": {}}},
+		{"id": "msg_2", "role": "assistant", "parts": [
+			{"type": "text", "text": "Looking"},
+			{"type": "tool-invocation", "toolInvocation": {"state": "result", "toolCallId": "c1", "toolName": "read", "args": {"filePath": "login.go"}}},
+			{"type": "tool-invocation", "toolInvocation": {"state": "call", "toolCallId": "c2", "toolName": "task", "args": {"description": "write tests", "prompt": "..."}}}
+		 ],
+		 "metadata": {"sessionID": "ses_1", "time": {"created": 2000, "completed": 9000},
+		  "tool": {"c1": {"title": "", "time": {"start": 3000, "end": 3500}}}}}
+	]`
+	var messages []opencode.Message
+	if err := json.Unmarshal([]byte(raw), &messages); err != nil {
+		t.Fatal(err)
+	}
+	checkpoints := []Checkpoint{{ID: "cpt_1", Description: "Auto: before editing", Timestamp: 2500}}
+
+	entries := BuildTimeline(messages, checkpoints)
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Label)
+	}
+	// the running task has no metadata yet and sits at its message's time
+	want := []string{"fix the login bug", "Looking", "task write tests", "Auto: before editing", "read login.go"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("labels = %q, want %q", got, want)
+	}
+	if entries[1].Kind != TimelineResponse || entries[1].Duration != 7*time.Second {
+		t.Errorf("response = %+v", entries[1])
+	}
+	if entries[2].Kind != TimelineAgent || entries[2].Duration != 0 {
+		t.Errorf("agent = %+v", entries[2])
+	}
+	if entries[4].Kind != TimelineTool || entries[4].Duration != 500*time.Millisecond {
+		t.Errorf("tool = %+v", entries[4])
+	}
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/opencode-sdk-go"
+)
+
+const (
+	// defaultToolStuckAfter is how long a tool call runs before it is
+	// considered possibly stuck, unless configured otherwise
+	defaultToolStuckAfter = 2 * time.Minute
+	toolTimerInterval     = time.Second
+)
+
+// ToolTimerTickMsg is sent every second, so the elapsed times and spinners
+// of tool calls and agent tasks in flight are redrawn
+type ToolTimerTickMsg struct{}
+
+// RunningTool is a tool call that has not returned yet
+type RunningTool struct {
+	CallID  string
+	Name    string
+	Started time.Time
+}
+
+// Elapsed returns how long the call has been running
+func (r RunningTool) Elapsed() time.Duration {
+	return time.Since(r.Started)
+}
+
+// ToolStats summarizes the tool calls in flight
+type ToolStats struct {
+	Running int
+	Stuck   int
+	// Longest is the call that has been running the longest
+	Longest RunningTool
+}
+
+// ToolTimers tracks how long the tool calls of the current session have
+// been running. The server reports no start time until a call returns, so
+// a call is timed from when the client first saw it.
+type ToolTimers struct {
+	mu      sync.Mutex
+	running map[string]RunningTool
+	warned  map[string]bool
+}
+
+func NewToolTimers() *ToolTimers {
+	return &ToolTimers{
+		running: make(map[string]RunningTool),
+		warned:  make(map[string]bool),
+	}
+}
+
+// Observe starts timing the unfinished tool calls in messages and forgets
+// the ones that returned or are no longer shown
+func (t *ToolTimers) Observe(messages []opencode.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seen := make(map[string]bool)
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			toolCall, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok || toolCall.ToolInvocation.State == "result" {
+				continue
+			}
+			id := toolCall.ToolInvocation.ToolCallID
+			seen[id] = true
+			if _, ok := t.running[id]; !ok {
+				t.running[id] = RunningTool{
+					CallID:  id,
+					Name:    toolCall.ToolInvocation.ToolName,
+					Started: time.Now(),
+				}
+			}
+		}
+	}
+	for id := range t.running {
+		if !seen[id] {
+			delete(t.running, id)
+			delete(t.warned, id)
+		}
+	}
+}
+
+// Running returns the calls in flight, longest running first
+func (t *ToolTimers) Running() []RunningTool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	running := make([]RunningTool, 0, len(t.running))
+	for _, tool := range t.running {
+		running = append(running, tool)
+	}
+	slices.SortFunc(running, func(a, b RunningTool) int {
+		return a.Started.Compare(b.Started)
+	})
+	return running
+}
+
+// Get returns the timing of a call in flight
+func (t *ToolTimers) Get(callID string) (RunningTool, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tool, ok := t.running[callID]
+	return tool, ok
+}
+
+// Stats counts the calls in flight and those running longer than threshold.
+// Each stuck call is logged once.
+func (t *ToolTimers) Stats(threshold time.Duration) ToolStats {
+	running := t.Running()
+	stats := ToolStats{Running: len(running)}
+	if len(running) > 0 {
+		stats.Longest = running[0]
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tool := range running {
+		if tool.Elapsed() < threshold {
+			continue
+		}
+		stats.Stuck++
+		if !t.warned[tool.CallID] {
+			t.warned[tool.CallID] = true
+			slog.Warn("Tool call may be stuck", "tool", tool.Name, "call", tool.CallID, "elapsed", tool.Elapsed().Round(time.Second))
+		}
+	}
+	return stats
+}
+
+// ToolStuckAfter returns how long a tool call runs before it is flagged as
+// possibly stuck
+func (a *App) ToolStuckAfter() time.Duration {
+	if a.State.ToolStuckAfter > 0 {
+		return time.Duration(a.State.ToolStuckAfter) * time.Second
+	}
+	return defaultToolStuckAfter
+}
+
+// TickToolTimers times the tool calls of the open session and schedules
+// the next tick. Nothing is redrawn on a tick unless something is running.
+func (a *App) TickToolTimers() tea.Cmd {
+	a.ToolTimers.Observe(a.Messages)
+	return tea.Tick(toolTimerInterval, func(time.Time) tea.Msg {
+		return ToolTimerTickMsg{}
+	})
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/config"
+)
+
+// workspaceRoot is the directory trust is decided for
+func (a *App) workspaceRoot() string {
+	if a.Info.Path.Root != "" {
+		return filepath.Clean(a.Info.Path.Root)
+	}
+	return filepath.Clean(a.Info.Path.Cwd)
+}
+
+// WorkspaceTrust returns the trust decision for the workspace, inherited
+// from the closest parent directory with one, empty when there is none
+func (a *App) WorkspaceTrust() config.WorkspaceTrust {
+	dir := a.workspaceRoot()
+	for {
+		if trust, ok := a.State.Workspaces[dir]; ok {
+			return trust
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// TrustDecided reports whether the user chose to trust the workspace or to
+// keep it restricted, otherwise the trust prompt is shown
+func (a *App) TrustDecided() bool {
+	return a.WorkspaceTrust() != ""
+}
+
+// Restricted reports whether the workspace runs in restricted mode, which
+// ignores the project config overlay, turns off the config hooks of the
+// server and asks for every bash command
+func (a *App) Restricted() bool {
+	return a.WorkspaceTrust() != config.WorkspaceTrusted
+}
+
+// SetWorkspaceTrust stores the trust decision for the workspace and applies
+// it right away
+func (a *App) SetWorkspaceTrust(trust config.WorkspaceTrust) tea.Cmd {
+	if a.State.Workspaces == nil {
+		a.State.Workspaces = make(map[string]config.WorkspaceTrust)
+	}
+	a.State.Workspaces[a.workspaceRoot()] = trust
+	a.SaveState()
+
+	a.Permissions.SetRestricted(a.Restricted())
+	if err := a.LoadProjectConfig(); err != nil {
+		slog.Warn("Failed to load project config", "error", err)
+	}
+	return a.SyncWorkspaceTrust()
+}
+
+// SyncWorkspaceTrust tells the server whether the workspace is trusted, so
+// it runs the config hooks only in a trusted one
+func (a *App) SyncWorkspaceTrust() tea.Cmd {
+	if !a.Features.Enabled(FeatureTrust) {
+		return nil
+	}
+	trusted := !a.Restricted()
+	return func() tea.Msg {
+		body := map[string]bool{"trusted": trusted}
+		var ok bool
+		if err := a.Client.Post(context.Background(), "/app/trust", body, &ok); err != nil {
+			slog.Error("Failed to send workspace trust", "error", err)
+		}
+		return nil
+	}
+}
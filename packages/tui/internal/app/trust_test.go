@@ -0,0 +1,70 @@
+package app
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestWorkspaceTrust(t *testing.T) {
+	home := filepath.FromSlash("/home/dev")
+	a := &App{State: &config.State{}}
+	a.Info.Path.Root = filepath.Join(home, "src", "project")
+
+	if a.TrustDecided() || !a.Restricted() {
+		t.Error("a new workspace is trusted, want restricted until decided")
+	}
+
+	a.State.Workspaces = map[string]config.WorkspaceTrust{home: config.WorkspaceTrusted}
+	if !a.TrustDecided() || a.Restricted() {
+		t.Error("a workspace inside a trusted directory is restricted")
+	}
+
+	a.State.Workspaces[a.Info.Path.Root] = config.WorkspaceRestricted
+	if !a.TrustDecided() || !a.Restricted() {
+		t.Error("the decision for the workspace itself does not win over its parent")
+	}
+}
+
+func TestPermissionServiceRestricted(t *testing.T) {
+	features := NewFeatureFlags(nil)
+	features.setServer([]Feature{FeaturePermissions})
+	s := NewPermissionService(nil, features)
+	s.allowed["ses"] = []string{"bash", "edit"}
+	s.SetRestricted(true)
+
+	event := func(raw string) opencode.EventListResponseEventPermissionUpdated {
+		var e opencode.EventListResponseEventPermissionUpdated
+		if err := json.Unmarshal([]byte(raw), &e.Properties); err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	bash, prompt := s.Enqueue(event(`{"id": "call_1", "tool": "bash", "sessionID": "ses"}`))
+	if bash.Tool != "bash" || !prompt {
+		t.Errorf("Enqueue = %+v, %v, want bash prompted", bash, prompt)
+	}
+	if !bash.Restricted || s.AlwaysAllowed(bash) {
+		t.Errorf("bash in a restricted workspace = %+v, allowed %v, want asked for", bash, s.AlwaysAllowed(bash))
+	}
+	// a concurrent call of the same tool waits for its own answer
+	if second, prompt := s.Enqueue(event(`{"id": "call_2", "tool": "bash", "sessionID": "ses"}`)); prompt || len(s.pending) != 2 {
+		t.Errorf("a second bash call = %+v, prompted %v, %d pending, want queued", second, prompt, len(s.pending))
+	}
+	// older servers use the tool name as the ID
+	if legacy, _ := s.Enqueue(event(`{"id": "edit", "sessionID": "ses"}`)); legacy.Tool != "edit" {
+		t.Errorf("a request without a tool has tool %q, want edit", legacy.Tool)
+	}
+	edit := PermissionRequest{ID: "call_3", Tool: "edit", SessionID: "ses"}
+	if !s.AlwaysAllowed(edit) {
+		t.Error("a restricted workspace asks for edit again, only bash is restricted")
+	}
+
+	s.SetRestricted(false)
+	if !s.AlwaysAllowed(bash) {
+		t.Error("bash allowed for the session is asked for in a trusted workspace")
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// ErrViewOnly is returned for actions that would change the viewed session
+var ErrViewOnly = errors.New("the session is open read-only")
+
+// ViewOnly reports whether the TUI was started with `dgmo view`
+func (a *App) ViewOnly() bool {
+	return a.ViewTarget != ""
+}
+
+// ParseViewArgs returns the target of `view <session-id-or-share-url>`.
+// The launcher forwards its own arguments, so other arguments are skipped.
+func ParseViewArgs(args []string) (string, bool) {
+	for i, arg := range args {
+		if arg == "view" && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// matchesViewTarget reports whether a session is the one a view target
+// names: its ID, its share URL, or the short ID share URLs end with
+func matchesViewTarget(session opencode.Session, target string) bool {
+	if session.ID == target || (session.Share.URL != "" && session.Share.URL == target) {
+		return true
+	}
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	short := path.Base(parsed.Path)
+	return short != "" && short != "/" && strings.HasSuffix(session.ID, short)
+}
+
+// OpenView loads the session named by ViewTarget
+func (a *App) OpenView(ctx context.Context) tea.Cmd {
+	target := a.ViewTarget
+	return func() tea.Msg {
+		sessions, err := a.ListSessions(ctx)
+		if err != nil {
+			return toast.NewErrorToast(fmt.Sprintf("Failed to list sessions: %v", err))()
+		}
+		for _, session := range sessions {
+			if matchesViewTarget(session, target) {
+				return SessionSelectedMsg(&session)
+			}
+		}
+		return toast.NewErrorToast(fmt.Sprintf("No session matches %s on this server", target))()
+	}
+}
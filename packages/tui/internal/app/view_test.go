@@ -0,0 +1,38 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sst/opencode-sdk-go"
+)
+
+func TestParseViewArgs(t *testing.T) {
+	if target, ok := ParseViewArgs([]string{"--server", "http://x", "view", "ses_1"}); !ok || target != "ses_1" {
+		t.Errorf("ParseViewArgs = %q, %t, want ses_1", target, ok)
+	}
+	if _, ok := ParseViewArgs([]string{"view", "--print-logs"}); ok {
+		t.Error("a flag was taken for the view target")
+	}
+	if _, ok := ParseViewArgs([]string{"project"}); ok {
+		t.Error("view mode without the view argument")
+	}
+}
+
+func TestMatchesViewTarget(t *testing.T) {
+	var session opencode.Session
+	data := `{"id":"ses_0123456789abcdef","share":{"url":"https://opencode.ai/s/89abcdef"}}`
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range []string{"ses_0123456789abcdef", "https://opencode.ai/s/89abcdef", "https://example.com/s/89abcdef"} {
+		if !matchesViewTarget(session, target) {
+			t.Errorf("%s does not match the session", target)
+		}
+	}
+	for _, target := range []string{"ses_other", "89abcdef", "https://opencode.ai/s/00000000"} {
+		if matchesViewTarget(session, target) {
+			t.Errorf("%s matches the session", target)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Package browser opens URLs with the default browser of the platform
+package browser
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Open opens an http or https URL in the default browser. Other schemes are
+// refused so a link in model output can never launch a local program.
+func Open(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("refusing to open %q: only http and https links are opened", rawURL)
+	}
+	cmd, err := command(u.String())
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	// reap the launcher, the browser itself keeps running on its own
+	go cmd.Wait() //nolint:errcheck
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build darwin
+
+package browser
+
+import (
+	"os/exec"
+)
+
+func command(url string) (*exec.Cmd, error) {
+	return exec.Command("open", url), nil //nolint:gosec
+}
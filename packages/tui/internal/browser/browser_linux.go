@@ -0,0 +1,15 @@
+//go:build linux
+
+package browser
+
+import (
+	"os/exec"
+)
+
+func command(url string) (*exec.Cmd, error) {
+	path, err := exec.LookPath("xdg-open")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(path, url), nil //nolint:gosec
+}
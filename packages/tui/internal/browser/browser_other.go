@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package browser
+
+import (
+	"errors"
+	"os/exec"
+)
+
+func command(url string) (*exec.Cmd, error) {
+	return nil, errors.New("no browser backend for this platform")
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package browser
+
+import (
+	"os/exec"
+)
+
+func command(url string) (*exec.Cmd, error) {
+	return exec.Command("rundll32", "url.dll,FileProtocolHandler", url), nil //nolint:gosec
+}
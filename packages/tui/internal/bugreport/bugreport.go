@@ -0,0 +1,101 @@
+// Package bugreport packs the logs and the transcript of a session into a
+// report to attach to an issue. Secrets are redacted before anything is
+// written, and the report can be sealed for the public key of whoever
+// reads it.
+package bugreport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File is a file of the report
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Report is a bug report with its secrets redacted
+type Report struct {
+	CreatedAt  time.Time
+	Files      []File
+	Redactions []Redaction
+}
+
+// New redacts the files of a report
+func New(files []File) *Report {
+	report := &Report{CreatedAt: time.Now()}
+	for _, file := range files {
+		content, redactions := Redact(file.Name, string(file.Content))
+		report.Files = append(report.Files, File{Name: file.Name, Content: []byte(content)})
+		report.Redactions = append(report.Redactions, redactions...)
+	}
+	return report
+}
+
+// Size is the size of the files of the report before compression
+func (r *Report) Size() int {
+	size := 0
+	for _, file := range r.Files {
+		size += len(file.Content)
+	}
+	return size
+}
+
+// Archive packs the files of the report into a gzipped tarball
+func (r *Report) Archive() ([]byte, error) {
+	var buffer bytes.Buffer
+	gz := gzip.NewWriter(&buffer)
+	tw := tar.NewWriter(gz)
+	for _, file := range r.Files {
+		header := &tar.Header{
+			Name:    file.Name,
+			Mode:    0o644,
+			Size:    int64(len(file.Content)),
+			ModTime: r.CreatedAt,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Write writes the report to dir, sealed for publicKey unless it is nil,
+// and returns its path
+func (r *Report) Write(dir string, publicKey crypto.PublicKey) (string, error) {
+	data, err := r.Archive()
+	if err != nil {
+		return "", fmt.Errorf("failed to pack the bug report: %w", err)
+	}
+	name := fmt.Sprintf("dgmo-bugreport-%s.tar.gz", r.CreatedAt.Format("20060102-150405"))
+	if publicKey != nil {
+		if data, err = Seal(data, publicKey); err != nil {
+			return "", fmt.Errorf("failed to seal the bug report: %w", err)
+		}
+		name += ".sealed"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create exports directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write bug report %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,99 @@
+package bugreport
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redaction is a secret taken out of a file of the report
+type Redaction struct {
+	File string
+	Line int
+	Kind string
+	// Hint is the start and the length of the secret, enough to recognize
+	// it without giving it away
+	Hint string
+}
+
+// redactionRule finds one kind of secret. Group is the submatch holding the
+// secret, 0 for the whole match.
+type redactionRule struct {
+	kind    string
+	pattern *regexp.Regexp
+	group   int
+}
+
+// redactionRules come most specific first: of two overlapping matches the
+// one of the earlier rule is redacted
+var redactionRules = []redactionRule{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), 0},
+	{"anthropic key", regexp.MustCompile(`sk-ant-[A-Za-z0-9_\-]{20,}`), 0},
+	{"openai key", regexp.MustCompile(`sk-(?:proj-)?[A-Za-z0-9_\-]{20,}`), 0},
+	{"github token", regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}`), 0},
+	{"aws access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+	{"slack token", regexp.MustCompile(`xox[abprs]-[A-Za-z0-9\-]{10,}`), 0},
+	{"google api key", regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`), 0},
+	{"jwt", regexp.MustCompile(`eyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`), 0},
+	{"bearer token", regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/\-]{16,}=*)`), 1},
+	{"url credentials", regexp.MustCompile(`[a-z][a-z0-9+.\-]*://[^\s:/@]+:([^\s@/]+)@`), 1},
+	{"secret assignment", regexp.MustCompile(`(?i)[\w.\-]*(?:secret|token|passw(?:or)?d|api_?key|access_?key|private_?key|credential)[\w.\-]*["']?\s*[:=]\s*["']?([^\s"',;\[\]{}]{8,})`), 1},
+}
+
+// Redact replaces the secrets in the text of a file with a marker naming
+// their kind, and lists what it replaced
+func Redact(file, text string) (string, []Redaction) {
+	type match struct {
+		start, end int
+		rule       int
+	}
+	var matches []match
+	for i, rule := range redactionRules {
+		for _, loc := range rule.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2*rule.group], loc[2*rule.group+1]
+			if start < 0 || start == end {
+				continue
+			}
+			matches = append(matches, match{start: start, end: end, rule: i})
+		}
+	}
+	if len(matches) == 0 {
+		return text, nil
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].rule < matches[j].rule
+	})
+
+	var b strings.Builder
+	var redactions []Redaction
+	last := 0
+	for _, m := range matches {
+		if m.start < last {
+			continue
+		}
+		kind := redactionRules[m.rule].kind
+		b.WriteString(text[last:m.start])
+		fmt.Fprintf(&b, "[REDACTED:%s]", kind)
+		redactions = append(redactions, Redaction{
+			File: file,
+			Line: strings.Count(text[:m.start], "\n") + 1,
+			Kind: kind,
+			Hint: hint(text[m.start:m.end]),
+		})
+		last = m.end
+	}
+	b.WriteString(text[last:])
+	return b.String(), redactions
+}
+
+// hint shows the first characters of a secret, at most a quarter of it,
+// and how long it was
+func hint(secret string) string {
+	runes := []rune(secret)
+	shown := min(4, len(runes)/4)
+	return fmt.Sprintf("%s… (%d chars)", string(runes[:shown]), len(runes))
+}
@@ -0,0 +1,157 @@
+package bugreport
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Algorithms of a sealed report, one per kind of public key
+const (
+	AlgorithmX25519 = "X25519-HKDF-SHA256-AES-256-GCM"
+	AlgorithmRSA    = "RSA-OAEP-SHA256-AES-256-GCM"
+)
+
+// sealInfo binds the key derived for X25519 to this use
+const sealInfo = "dgmo bug report"
+
+// Envelope is a sealed report. The report is encrypted with a random
+// AES-256-GCM key, which is wrapped for the public key: with RSA-OAEP, or
+// derived from an X25519 exchange with an ephemeral key.
+type Envelope struct {
+	Version   int    `json:"version"`
+	Algorithm string `json:"algorithm"`
+	// Key is the wrapped AES key for RSA, the ephemeral public key for X25519
+	Key        []byte `json:"key"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ParsePublicKey reads a PEM encoded RSA or X25519 public key, as written by
+// `openssl pkey -pubout`
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("not a PEM encoded public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return key, nil
+	case *ecdh.PublicKey:
+		if key.Curve() == ecdh.X25519() {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported public key %T, use RSA or X25519", key)
+}
+
+// Seal encrypts data for the holder of the private key of publicKey
+func Seal(data []byte, publicKey crypto.PublicKey) ([]byte, error) {
+	envelope := Envelope{Version: 1}
+	var key []byte
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, key, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap the report key: %w", err)
+		}
+		envelope.Algorithm, envelope.Key = AlgorithmRSA, wrapped
+	case *ecdh.PublicKey:
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		key, err = deriveKey(ephemeral, publicKey, ephemeral.PublicKey())
+		if err != nil {
+			return nil, err
+		}
+		envelope.Algorithm, envelope.Key = AlgorithmX25519, ephemeral.PublicKey().Bytes()
+	default:
+		return nil, fmt.Errorf("unsupported public key %T", publicKey)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return nil, err
+	}
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, data, []byte(envelope.Algorithm))
+	return json.MarshalIndent(envelope, "", "  ")
+}
+
+// Open decrypts a sealed report with the private key it was sealed for
+func Open(sealed []byte, privateKey crypto.PrivateKey) ([]byte, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(sealed, &envelope); err != nil {
+		return nil, fmt.Errorf("not a sealed report: %w", err)
+	}
+	var key []byte
+	var err error
+	switch envelope.Algorithm {
+	case AlgorithmRSA:
+		privateKey, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("the report is sealed for an RSA key")
+		}
+		key, err = rsa.DecryptOAEP(sha256.New(), nil, privateKey, envelope.Key, nil)
+	case AlgorithmX25519:
+		privateKey, ok := privateKey.(*ecdh.PrivateKey)
+		if !ok {
+			return nil, errors.New("the report is sealed for an X25519 key")
+		}
+		var ephemeral *ecdh.PublicKey
+		ephemeral, err = ecdh.X25519().NewPublicKey(envelope.Key)
+		if err == nil {
+			key, err = deriveKey(privateKey, ephemeral, ephemeral)
+		}
+	default:
+		return nil, fmt.Errorf("unknown algorithm %q", envelope.Algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap the report key: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, envelope.Nonce, envelope.Ciphertext, []byte(envelope.Algorithm))
+}
+
+// deriveKey derives the AES key from an X25519 exchange, salted with the
+// ephemeral public key so every report has its own key
+func deriveKey(private *ecdh.PrivateKey, public, ephemeral *ecdh.PublicKey) ([]byte, error) {
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, err
+	}
+	return hkdf.Key(sha256.New, shared, ephemeral.Bytes(), sealInfo, 32)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
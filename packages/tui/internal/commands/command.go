@@ -18,6 +18,14 @@ type Keybinding struct {
 	Key            string
 }
 
+// String formats the keybinding the way it is written in the config
+func (k Keybinding) String() string {
+	if k.RequiresLeader {
+		return "<leader>" + k.Key
+	}
+	return k.Key
+}
+
 func (k Keybinding) Matches(msg tea.KeyPressMsg, leader bool) bool {
 	key := k.Key
 	key = strings.TrimSpace(key)
@@ -30,6 +38,9 @@ type Command struct {
 	Description string
 	Keybindings []Keybinding
 	Trigger     string
+	// Aliases are alternative triggers, e.g. /theme for /themes
+	Aliases []string
+	Args    []Argument
 }
 
 func (c Command) Keys() []string {
@@ -74,21 +85,75 @@ const (
 	EditorOpenCommand           CommandName = "editor_open"
 	SessionNewCommand           CommandName = "session_new"
 	SessionListCommand          CommandName = "session_list"
+	SessionLatestCommand        CommandName = "session_latest"
 	SessionShareCommand         CommandName = "session_share"
 	SessionInterruptCommand     CommandName = "session_interrupt"
 	SessionCompactCommand       CommandName = "session_compact"
 	ToolDetailsCommand          CommandName = "tool_details"
+	MessagesRawCommand          CommandName = "messages_raw"
 	ModelListCommand            CommandName = "model_list"
+	ModelCycleCommand           CommandName = "model_cycle"
 	ThemeListCommand            CommandName = "theme_list"
 	ProjectInitCommand          CommandName = "project_init"
+	ProjectOpenCommand          CommandName = "project_open"
 	AgentModeCommand            CommandName = "agent_mode"
 	SubSessionCommand           CommandName = "sub_session"
+	SpawnAgentsCommand          CommandName = "spawn_agents"
+	SessionBroadcastCommand     CommandName = "session_broadcast"
+	SessionCompareCommand       CommandName = "session_compare"
+	NotificationsToggleCommand  CommandName = "notifications_toggle"
+	DiffViewCommand             CommandName = "diff_view"
+	DiagnosticsCommand          CommandName = "diagnostics"
+	PatchReviewCommand          CommandName = "patch_review"
+	SessionRecordCommand        CommandName = "session_record"
+	SessionRevertCommand        CommandName = "session_revert"
+	SessionCheckpointCommand    CommandName = "session_checkpoint"
+	MessageInspectCommand       CommandName = "message_inspect"
+	SwarmDashboardCommand       CommandName = "swarm_dashboard"
+	AppLogsCommand              CommandName = "app_logs"
+	AppConfigCommand            CommandName = "app_config"
+	TemplateListCommand         CommandName = "template_list"
+	SnippetsCommand             CommandName = "snippets"
+	PromptBuilderCommand        CommandName = "prompt_builder"
+	MCPServersCommand           CommandName = "mcp_servers"
+	CommandPaletteCommand       CommandName = "command_palette"
+	SessionExportCommand        CommandName = "session_export"
+	SessionContinueCommand      CommandName = "session_continue"
+	SessionPRCommand            CommandName = "session_pr"
+	SessionImportCommand        CommandName = "session_import"
+	SessionRenameCommand        CommandName = "session_rename"
+	SessionInstructionsCommand  CommandName = "session_instructions"
+	SessionEnvCommand           CommandName = "session_env"
+	SessionRegenerateCommand    CommandName = "session_regenerate"
+	SessionBranchCommand        CommandName = "session_branch"
+	MessageEditCommand          CommandName = "message_edit"
+	SessionTimelineCommand      CommandName = "session_timeline"
+	SessionRecoverCommand       CommandName = "session_recover"
+	SessionTabNewCommand        CommandName = "session_tab_new"
+	SessionTabNextCommand       CommandName = "session_tab_next"
+	SessionTabCloseCommand      CommandName = "session_tab_close"
+	SearchCommand               CommandName = "search"
+	KeybindsCommand             CommandName = "app_keybinds"
+	TutorialCommand             CommandName = "app_tutorial"
+	PerfHUDCommand              CommandName = "app_perf"
+	TraceCommand                CommandName = "app_trace"
+	LayoutWidthCommand          CommandName = "app_width"
+	LayoutDensityCommand        CommandName = "app_density"
+	LayoutZoomCommand           CommandName = "app_zoom"
+	LayoutZoomInCommand         CommandName = "app_zoom_in"
+	LayoutZoomOutCommand        CommandName = "app_zoom_out"
+	UndoCommand                 CommandName = "app_undo"
+	BellToggleCommand           CommandName = "app_bell"
+	WorkspaceTrustCommand       CommandName = "app_trust"
+	BugReportCommand            CommandName = "app_bugreport"
 	InputClearCommand           CommandName = "input_clear"
 	InputPasteCommand           CommandName = "input_paste"
+	InputPasteCodeCommand       CommandName = "input_paste_code"
 	InputSubmitCommand          CommandName = "input_submit"
 	InputNewlineCommand         CommandName = "input_newline"
 	HistoryPreviousCommand      CommandName = "history_previous"
 	HistoryNextCommand          CommandName = "history_next"
+	HistorySearchCommand        CommandName = "history_search"
 	MessagesPageUpCommand       CommandName = "messages_page_up"
 	MessagesPageDownCommand     CommandName = "messages_page_down"
 	MessagesHalfPageUpCommand   CommandName = "messages_half_page_up"
@@ -97,6 +162,9 @@ const (
 	MessagesNextCommand         CommandName = "messages_next"
 	MessagesFirstCommand        CommandName = "messages_first"
 	MessagesLastCommand         CommandName = "messages_last"
+	MessagesBookmarkCommand     CommandName = "messages_bookmark"
+	MessagesBookmarksCommand    CommandName = "messages_bookmarks"
+	MessagesCopyCodeCommand     CommandName = "messages_copy_code"
 	AppExitCommand              CommandName = "app_exit"
 )
 
@@ -145,16 +213,37 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 			Keybindings: parseBindings("<leader>n"),
 			Trigger:     "new",
 		},
+		{
+			Name:        SessionTabNewCommand,
+			Description: "open a session tab",
+			Trigger:     "tab",
+		},
+		{
+			Name:        SessionTabNextCommand,
+			Description: "next session tab",
+			Keybindings: parseBindings("<leader>tab"),
+		},
+		{
+			Name:        SessionTabCloseCommand,
+			Description: "close the session tab",
+			Trigger:     "close",
+		},
 		{
 			Name:        SessionListCommand,
 			Description: "list sessions",
 			Keybindings: parseBindings("<leader>l"),
 			Trigger:     "sessions",
 		},
+		{
+			Name:        SessionLatestCommand,
+			Description: "jump to most recently active session",
+			Keybindings: parseBindings("<leader>g"),
+			Trigger:     "latest",
+		},
 		{
 			Name:        SessionShareCommand,
 			Description: "share session",
-			Keybindings: parseBindings("<leader>s"),
+			Keybindings: parseBindings("<leader>p"),
 			Trigger:     "share",
 		},
 		{
@@ -174,17 +263,31 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 			Keybindings: parseBindings("<leader>d"),
 			Trigger:     "details",
 		},
+		{
+			Name:        MessagesRawCommand,
+			Description: "toggle markdown source of a message",
+			Trigger:     "raw",
+		},
 		{
 			Name:        ModelListCommand,
 			Description: "list models",
 			Keybindings: parseBindings("<leader>m"),
 			Trigger:     "models",
+			Aliases:     []string{"model"},
+			Args:        []Argument{{Name: "model"}},
+		},
+		{
+			Name:        ModelCycleCommand,
+			Description: "next favorite model",
+			Keybindings: parseBindings("<leader>."),
 		},
 		{
 			Name:        ThemeListCommand,
 			Description: "list themes",
 			Keybindings: parseBindings("<leader>t"),
 			Trigger:     "themes",
+			Aliases:     []string{"theme"},
+			Args:        []Argument{{Name: "theme"}},
 		},
 		{
 			Name:        ProjectInitCommand,
@@ -192,6 +295,11 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 			Keybindings: parseBindings("<leader>i"),
 			Trigger:     "init",
 		},
+		{
+			Name:        ProjectOpenCommand,
+			Description: "open the project in your editor",
+			Trigger:     "open",
+		},
 		{
 			Name:        AgentModeCommand,
 			Description: "set agent mode (read-only/all-tools)",
@@ -200,9 +308,276 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 		},
 		{
 			Name:        SubSessionCommand,
-			Description: "navigate sub-sessions",
+			Description: "navigate sub-sessions, or open one by ID",
 			Keybindings: parseBindings("<leader>u"),
 			Trigger:     "sub-session",
+			Args:        []Argument{{Name: "id"}},
+		},
+		{
+			Name:        SpawnAgentsCommand,
+			Description: "spawn agents on a goal",
+			Keybindings: parseBindings("<leader>w"),
+			Trigger:     "spawn",
+		},
+		{
+			Name:        SessionBroadcastCommand,
+			Description: "send a prompt to several models at once",
+			Trigger:     "broadcast",
+		},
+		{
+			Name:        SessionCompareCommand,
+			Description: "compare and rate the responses to a broadcast",
+			Trigger:     "compare",
+		},
+		{
+			Name:        DiffViewCommand,
+			Description: "view file diffs",
+			Keybindings: parseBindings("<leader>v"),
+			Trigger:     "diff",
+		},
+		{
+			Name:        DiagnosticsCommand,
+			Description: "view lsp diagnostics",
+			Keybindings: parseBindings("<leader>x"),
+			Trigger:     "diagnostics",
+		},
+		{
+			Name:        PatchReviewCommand,
+			Description: "review and apply a multi-file patch",
+			Keybindings: parseBindings("<leader>y"),
+			Trigger:     "patch",
+		},
+		{
+			Name:        SessionRevertCommand,
+			Description: "revert to a checkpoint",
+			Keybindings: parseBindings("<leader>r"),
+			Trigger:     "revert",
+		},
+		{
+			Name:        SessionCheckpointCommand,
+			Description: "create checkpoint",
+			Keybindings: parseBindings("<leader>s"),
+			Trigger:     "checkpoint",
+		},
+		{
+			Name:        SwarmDashboardCommand,
+			Description: "agent dashboard",
+			Keybindings: parseBindings("<leader>o"),
+			Trigger:     "swarm",
+		},
+		{
+			Name:        CommandPaletteCommand,
+			Description: "command palette",
+			Keybindings: parseBindings("ctrl+p"),
+			Trigger:     "palette",
+		},
+		{
+			Name:        AppLogsCommand,
+			Description: "view logs",
+			Trigger:     "logs",
+		},
+		{
+			Name:        MCPServersCommand,
+			Description: "show mcp server latency",
+			Trigger:     "mcp",
+		},
+		{
+			Name:        AppConfigCommand,
+			Description: "show effective config",
+			Trigger:     "config",
+		},
+		{
+			Name:        TemplateListCommand,
+			Description: "insert a prompt template",
+			Trigger:     "templates",
+			Aliases:     []string{"template"},
+			Args:        []Argument{{Name: "name"}},
+		},
+		{
+			Name:        SnippetsCommand,
+			Description: "manage the editor snippets",
+			Trigger:     "snippets",
+		},
+		{
+			Name:        PromptBuilderCommand,
+			Description: "build a prompt from blocks",
+			Keybindings: parseBindings("<leader>b"),
+			Trigger:     "build",
+		},
+		{
+			Name:        SessionContinueCommand,
+			Description: "generate a continuation prompt",
+			Trigger:     "continue",
+			Args:        []Argument{{Name: "goal"}},
+		},
+		{
+			Name:        MessagesCopyCodeCommand,
+			Description: "copy the last code block of a response, or the nth",
+			Keybindings: parseBindings("<leader>`"),
+			Trigger:     "copycode",
+			Args:        []Argument{{Name: "n", Count: true}},
+		},
+		{
+			Name:        MessageInspectCommand,
+			Description: "inspect message json",
+			Keybindings: parseBindings("<leader>j"),
+			Trigger:     "inspect",
+		},
+		{
+			Name:        SessionRecordCommand,
+			Description: "start/stop recording a .cast, or save a range like 0:30-2:00",
+			Trigger:     "record",
+			Args:        []Argument{{Name: "range"}},
+		},
+		{
+			Name:        SessionExportCommand,
+			Description: "export the session",
+			Trigger:     "export",
+			Args:        []Argument{{Name: "format", Choices: []string{"md", "json", "bundle"}}},
+		},
+		{
+			Name:        SessionPRCommand,
+			Description: "write a pull request description of the session",
+			Trigger:     "pr",
+			Args: []Argument{
+				{Name: "flavor", Choices: []string{"github", "gitlab"}},
+				{Name: "output", Choices: []string{"clipboard", "file"}},
+			},
+		},
+		{
+			Name:        SessionImportCommand,
+			Description: "open a session bundle read-only",
+			Trigger:     "import",
+			Args:        []Argument{{Name: "path", Required: true}},
+		},
+		{
+			Name:        SessionRenameCommand,
+			Description: "rename the session",
+			Trigger:     "rename",
+			Args:        []Argument{{Name: "title"}},
+		},
+		{
+			Name:        SessionInstructionsCommand,
+			Description: "set instructions for the session",
+			Trigger:     "instructions",
+		},
+		{
+			Name:        SessionEnvCommand,
+			Description: "set environment variables for the bash commands of the session",
+			Trigger:     "env",
+		},
+		{
+			Name:        SessionRegenerateCommand,
+			Description: "regenerate the last response, optionally with another model",
+			Trigger:     "regenerate",
+			Args:        []Argument{{Name: "model"}},
+		},
+		{
+			Name:        MessageEditCommand,
+			Description: "edit a sent message and resend from there",
+			Trigger:     "edit",
+		},
+		{
+			Name:        SessionBranchCommand,
+			Description: "branch a new session from the selected message",
+			Trigger:     "branch",
+		},
+		{
+			Name:        SessionTimelineCommand,
+			Description: "show the session as a timeline",
+			Trigger:     "timeline",
+		},
+		{
+			Name:        SessionRecoverCommand,
+			Description: "rebuild the transcript from the local copy",
+			Trigger:     "recover",
+		},
+		{
+			Name:        KeybindsCommand,
+			Description: "rebind keys",
+			Keybindings: parseBindings("<leader>k"),
+			Trigger:     "keybinds",
+		},
+		{
+			Name:        SearchCommand,
+			Description: "search messages, prompts and files",
+			Keybindings: parseBindings("<leader>f"),
+			Trigger:     "search",
+			Args:        []Argument{{Name: "query"}},
+		},
+		{
+			Name:        UndoCommand,
+			Description: "undo the last delete or discard",
+			Keybindings: parseBindings("<leader>z"),
+			Trigger:     "undo",
+		},
+		{
+			Name:        BellToggleCommand,
+			Description: "mute or unmute alerts for finished work",
+			Trigger:     "bell",
+			Args:        []Argument{{Name: "event", Choices: []string{"response", "task"}}},
+		},
+		{
+			Name:        TutorialCommand,
+			Description: "walk through the core flows",
+			Trigger:     "tutorial",
+			Args:        []Argument{{Name: "action", Choices: []string{"skip", "stop", "restart"}}},
+		},
+		{
+			Name:        PerfHUDCommand,
+			Description: "toggle render timings and cache metrics",
+			Trigger:     "perf",
+		},
+		{
+			Name:        TraceCommand,
+			Description: "toggle tracing of every message to the log",
+			Trigger:     "trace",
+		},
+		{
+			Name:        LayoutWidthCommand,
+			Description: "set or cycle the content width",
+			Trigger:     "width",
+			Args:        []Argument{{Name: "columns"}},
+		},
+		{
+			Name:        LayoutDensityCommand,
+			Description: "toggle compact layout",
+			Trigger:     "density",
+			Args:        []Argument{{Name: "density", Choices: []string{"comfortable", "compact"}}},
+		},
+		{
+			Name:        LayoutZoomCommand,
+			Description: "set the zoom of paddings and widths, or reset it",
+			Trigger:     "zoom",
+			Args:        []Argument{{Name: "percent"}},
+		},
+		{
+			Name:        LayoutZoomInCommand,
+			Description: "zoom in",
+			Keybindings: parseBindings("ctrl+alt+="),
+			Trigger:     "zoom-in",
+		},
+		{
+			Name:        LayoutZoomOutCommand,
+			Description: "zoom out",
+			Keybindings: parseBindings("ctrl+alt+-"),
+			Trigger:     "zoom-out",
+		},
+		{
+			Name:        WorkspaceTrustCommand,
+			Description: "trust the workspace or keep it restricted",
+			Trigger:     "trust",
+		},
+		{
+			Name:        BugReportCommand,
+			Description: "pack logs and transcript, secrets redacted, for a bug report",
+			Trigger:     "bugreport",
+			Args:        []Argument{{Name: "public-key"}},
+		},
+		{
+			Name:        NotificationsToggleCommand,
+			Description: "toggle desktop notifications",
+			Trigger:     "notifications",
 		},
 		{
 			Name:        InputClearCommand,
@@ -213,6 +588,11 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 			Description: "paste content",
 			Keybindings: parseBindings("ctrl+v"),
 		},
+		{
+			Name:        InputPasteCodeCommand,
+			Description: "paste as code block",
+			Trigger:     "codeblock",
+		},
 		{
 			Name:        InputSubmitCommand,
 			Description: "submit message",
@@ -233,6 +613,12 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 		// 	Description: "next prompt",
 		// 	Keybindings: parseBindings("down"),
 		// },
+		{
+			Name:        HistorySearchCommand,
+			Description: "search previous prompts",
+			Keybindings: parseBindings("ctrl+r"),
+			Trigger:     "history",
+		},
 		{
 			Name:        MessagesPageUpCommand,
 			Description: "page up",
@@ -273,6 +659,19 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 			Description: "last message",
 			Keybindings: parseBindings("ctrl+alt+g"),
 		},
+		{
+			Name:        MessagesBookmarkCommand,
+			Description: "bookmark the spot in view",
+			Keybindings: parseBindings("ctrl+alt+b"),
+			Trigger:     "bookmark",
+			Args:        []Argument{{Name: "name"}},
+		},
+		{
+			Name:        MessagesBookmarksCommand,
+			Description: "jump to a bookmark",
+			Keybindings: parseBindings("ctrl+alt+m"),
+			Trigger:     "bookmarks",
+		},
 		{
 			Name:        AppExitCommand,
 			Description: "exit the app",
@@ -285,10 +684,20 @@ func LoadFromConfig(config *opencode.Config) CommandRegistry {
 	marshalled, _ := json.Marshal(config.Keybinds)
 	json.Unmarshal(marshalled, &keybinds)
 	for _, command := range defaults {
-		if keybind, ok := keybinds[string(command.Name)]; ok && keybind != "" {
-			command.Keybindings = parseBindings(keybind)
-		}
 		registry[command.Name] = command
 	}
+	registry.ApplyKeybinds(keybinds)
 	return registry
 }
+
+// ApplyKeybinds replaces the keybindings of the commands named in keybinds
+func (r CommandRegistry) ApplyKeybinds(keybinds map[string]string) {
+	for name, keybind := range keybinds {
+		command, ok := r[CommandName(name)]
+		if !ok || keybind == "" {
+			continue
+		}
+		command.Keybindings = parseBindings(keybind)
+		r[command.Name] = command
+	}
+}
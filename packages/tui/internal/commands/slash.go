@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownCommand is returned when the input names no command trigger
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Argument describes a positional argument of a slash command
+type Argument struct {
+	Name     string
+	Required bool
+	// Choices restricts the argument to a fixed set of values. Arguments
+	// without choices are validated by the command itself.
+	Choices []string
+	// Count is filled by a number typed between the leader and the key of
+	// the command, e.g. <leader>2y
+	Count bool
+}
+
+// SlashCommandMsg runs a command with the arguments typed after its trigger
+type SlashCommandMsg struct {
+	Command Command
+	Args    []string
+}
+
+// Usage returns the trigger of the command followed by its arguments
+func (c Command) Usage() string {
+	usage := "/" + c.Trigger
+	for _, arg := range c.Args {
+		if arg.Required {
+			usage += " <" + arg.Name + ">"
+		} else {
+			usage += " [" + arg.Name + "]"
+		}
+	}
+	return usage
+}
+
+// MatchesTrigger reports whether trigger is the trigger or an alias of the command
+func (c Command) MatchesTrigger(trigger string) bool {
+	if c.Trigger == "" {
+		return false
+	}
+	return c.Trigger == trigger || slices.Contains(c.Aliases, trigger)
+}
+
+// ValidateArgs checks the number of arguments and their fixed choices
+func (c Command) ValidateArgs(args []string) error {
+	if len(args) > len(c.Args) {
+		return fmt.Errorf("too many arguments, usage: %s", c.Usage())
+	}
+	for i, arg := range c.Args {
+		if i >= len(args) {
+			if arg.Required {
+				return fmt.Errorf("missing %s, usage: %s", arg.Name, c.Usage())
+			}
+			continue
+		}
+		if len(arg.Choices) > 0 && !slices.Contains(arg.Choices, args[i]) {
+			return fmt.Errorf("invalid %s %q, expected one of %s", arg.Name, args[i], strings.Join(arg.Choices, ", "))
+		}
+	}
+	return nil
+}
+
+// FindTrigger returns the command with the given trigger or alias
+func (r CommandRegistry) FindTrigger(trigger string) (Command, bool) {
+	for _, command := range r.Sorted() {
+		if command.MatchesTrigger(trigger) {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+// ParseSlash parses input like `/theme dark` into the command to run and its
+// arguments. Input that names no command returns ErrUnknownCommand, so it can
+// be sent as a regular message instead (e.g. a path such as /usr/bin).
+func (r CommandRegistry) ParseSlash(input string) (SlashCommandMsg, error) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, "/") || strings.Contains(input, "\n") {
+		return SlashCommandMsg{}, ErrUnknownCommand
+	}
+	fields := SplitArgs(input[1:])
+	if len(fields) == 0 {
+		return SlashCommandMsg{}, ErrUnknownCommand
+	}
+	command, ok := r.FindTrigger(fields[0])
+	if !ok {
+		return SlashCommandMsg{}, ErrUnknownCommand
+	}
+	args := fields[1:]
+	if err := command.ValidateArgs(args); err != nil {
+		return SlashCommandMsg{}, err
+	}
+	return SlashCommandMsg{Command: command, Args: args}, nil
+}
+
+// SplitArgs splits input on whitespace, keeping double-quoted text together
+func SplitArgs(input string) []string {
+	var args []string
+	var current strings.Builder
+	quoted, started := false, false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case !quoted && (r == ' ' || r == '\t'):
+			if started {
+				args = append(args, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, current.String())
+	}
+	return args
+}
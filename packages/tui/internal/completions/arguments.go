@@ -0,0 +1,113 @@
+package completions
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// ArgumentChoices returns the values the argument at index of command can take
+func ArgumentChoices(app *app.App, command commands.Command, index int) []string {
+	if index < 0 || index >= len(command.Args) {
+		return nil
+	}
+	if choices := command.Args[index].Choices; len(choices) > 0 {
+		return choices
+	}
+
+	switch command.Name {
+	case commands.ThemeListCommand:
+		return theme.AvailableThemes()
+	case commands.TemplateListCommand:
+		templates, err := app.ListTemplates()
+		if err != nil {
+			slog.Error("Failed to load templates", "error", err)
+		}
+		names := make([]string, 0, len(templates))
+		for _, template := range templates {
+			names = append(names, template.Name)
+		}
+		return names
+	case commands.ModelListCommand, commands.SessionRegenerateCommand:
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		providers, err := app.ListProviders(ctx)
+		if err != nil {
+			slog.Error("Failed to list providers", "error", err)
+			return nil
+		}
+		var models []string
+		for _, provider := range providers {
+			for id := range provider.Models {
+				models = append(models, provider.ID+"/"+id)
+			}
+		}
+		slices.Sort(models)
+		return models
+	}
+	return nil
+}
+
+// CompleteArgument completes the last argument of a slash command input. It
+// returns the completed input and the candidates that matched, the input is
+// only extended as far as all candidates agree.
+func CompleteArgument(app *app.App, input string) (string, []string) {
+	if !strings.HasPrefix(input, "/") || strings.Contains(input, "\n") {
+		return input, nil
+	}
+	fields := commands.SplitArgs(input[1:])
+	if len(fields) == 0 {
+		return input, nil
+	}
+	command, ok := app.Commands.FindTrigger(fields[0])
+	if !ok {
+		return input, nil
+	}
+
+	// a trailing space starts the next argument
+	prefix := ""
+	index := len(fields) - 1
+	if strings.HasSuffix(input, " ") {
+		index++
+	} else {
+		prefix = fields[len(fields)-1]
+	}
+	if index == 0 {
+		return input, nil
+	}
+
+	var matches []string
+	for _, choice := range ArgumentChoices(app, command, index-1) {
+		if strings.HasPrefix(strings.ToLower(choice), strings.ToLower(prefix)) {
+			matches = append(matches, choice)
+		}
+	}
+	if len(matches) == 0 {
+		return input, nil
+	}
+
+	completed := commonPrefix(matches)
+	if len(matches) == 1 {
+		completed += " "
+	}
+	if len(completed) < len(prefix) {
+		return input, matches
+	}
+	return input[:len(input)-len(prefix)] + completed, matches
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
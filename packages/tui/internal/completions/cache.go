@@ -0,0 +1,35 @@
+package completions
+
+import (
+	"sync"
+	"time"
+)
+
+// listCache keeps what a provider listed for a while, so the keys typed
+// after a trigger filter the list instead of listing again
+type listCache[T any] struct {
+	ttl    time.Duration
+	mu     sync.Mutex
+	items  []T
+	loaded time.Time
+}
+
+func newListCache[T any](ttl time.Duration) *listCache[T] {
+	return &listCache[T]{ttl: ttl}
+}
+
+// get returns the cached list, or the list load returns once the cached one
+// is older than the ttl. A failed load keeps the cached list.
+func (c *listCache[T]) get(load func() ([]T, error)) ([]T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded.IsZero() && time.Since(c.loaded) < c.ttl {
+		return c.items, nil
+	}
+	items, err := load()
+	if err != nil {
+		return c.items, err
+	}
+	c.items, c.loaded = items, time.Now()
+	return items, nil
+}
@@ -40,21 +40,15 @@ func (cg *filesAndFoldersContextGroup) getFiles(query string) ([]string, error)
 }
 
 func (cg *filesAndFoldersContextGroup) GetChildEntries(query string) ([]dialog.CompletionItemI, error) {
+	// the server searches until the local index finished its first walk
+	if cg.app.Files.Ready() {
+		return fileItems(cg.app.Files.Search(query, maxCompletions)), nil
+	}
 	matches, err := cg.getFiles(query)
 	if err != nil {
 		return nil, err
 	}
-
-	items := make([]dialog.CompletionItemI, 0, len(matches))
-	for _, file := range matches {
-		item := dialog.NewCompletionItem(dialog.CompletionItem{
-			Title: file,
-			Value: file,
-		})
-		items = append(items, item)
-	}
-
-	return items, nil
+	return fileItems(matches), nil
 }
 
 func NewFileAndFolderContextGroup(app *app.App) dialog.CompletionProvider {
@@ -7,19 +7,42 @@ import (
 	"github.com/sst/dgmo/internal/components/dialog"
 )
 
+// triggers map the first character of a word to the built-in provider
+// completing it. Plugins cannot take these triggers.
+var triggers = map[string]string{
+	"@": "workspace",
+	"#": "git",
+	"$": "variables",
+}
+
 type CompletionManager struct {
+	app       *app.App
 	providers map[string]dialog.CompletionProvider
 }
 
 func NewCompletionManager(app *app.App) *CompletionManager {
 	return &CompletionManager{
+		app: app,
 		providers: map[string]dialog.CompletionProvider{
-			"files":    NewFileAndFolderContextGroup(app),
-			"commands": NewCommandCompletionProvider(app),
+			"files":     NewFileAndFolderContextGroup(app),
+			"commands":  NewCommandCompletionProvider(app),
+			"workspace": NewWorkspaceFileProvider(app),
+			"git":       NewGitRefProvider(app),
+			"variables": NewVariableProvider(app),
 		},
 	}
 }
 
+// IsTrigger reports whether a character starting a word opens a completion
+// provider, a built-in one or one of a plugin
+func (m *CompletionManager) IsTrigger(char string) bool {
+	if _, ok := triggers[char]; ok {
+		return true
+	}
+	_, _, ok := m.app.PluginCompletion(char)
+	return ok
+}
+
 func (m *CompletionManager) DefaultProvider() dialog.CompletionProvider {
 	return m.providers["commands"]
 }
@@ -28,5 +51,15 @@ func (m *CompletionManager) GetProvider(input string) dialog.CompletionProvider
 	if strings.HasPrefix(input, "/") {
 		return m.providers["commands"]
 	}
+	words := strings.Fields(input)
+	if len(words) > 0 && !strings.HasSuffix(input, " ") {
+		word := words[len(words)-1]
+		if name, ok := triggers[word[:1]]; ok {
+			return m.providers[name]
+		}
+		if p, completion, ok := m.app.PluginCompletion(word); ok {
+			return NewPluginCompletionProvider(m.app, p, completion)
+		}
+	}
 	return m.providers["files"]
 }
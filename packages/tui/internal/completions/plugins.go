@@ -0,0 +1,53 @@
+package completions
+
+import (
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/dialog"
+	"github.com/sst/dgmo/internal/plugin"
+)
+
+type pluginCompletionProvider struct {
+	app        *app.App
+	plugin     *plugin.Plugin
+	completion plugin.Completion
+}
+
+// NewPluginCompletionProvider completes the words starting with the
+// trigger of a plugin completion with the items the plugin returns
+func NewPluginCompletionProvider(app *app.App, p *plugin.Plugin, completion plugin.Completion) dialog.CompletionProvider {
+	return &pluginCompletionProvider{app: app, plugin: p, completion: completion}
+}
+
+func (p *pluginCompletionProvider) GetId() string {
+	return "plugin:" + p.plugin.Name + ":" + p.completion.Trigger
+}
+
+func (p *pluginCompletionProvider) GetEntry() dialog.CompletionItemI {
+	return dialog.NewCompletionItem(dialog.CompletionItem{
+		Title: p.completion.Description,
+		Value: p.GetId(),
+	})
+}
+
+func (p *pluginCompletionProvider) GetEmptyMessage() string {
+	return "no matches from " + p.plugin.Name
+}
+
+func (p *pluginCompletionProvider) GetChildEntries(query string) ([]dialog.CompletionItemI, error) {
+	items, err := p.app.CompletePlugin(p.plugin, query)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]dialog.CompletionItemI, 0, len(items))
+	for _, item := range items {
+		title := item.Title
+		if title == "" {
+			title = item.Value
+		}
+		entries = append(entries, dialog.NewCompletionItem(dialog.CompletionItem{
+			Title: title,
+			Value: item.Value,
+		}))
+	}
+	return entries, nil
+}
@@ -0,0 +1,96 @@
+package completions
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/dialog"
+	"github.com/sst/dgmo/internal/git"
+)
+
+const (
+	// gitRefsTTL is how long the listed refs are reused
+	gitRefsTTL = 10 * time.Second
+	// gitRefCommits is how many commits of HEAD are listed after the refs
+	gitRefCommits = 30
+)
+
+type gitRefProvider struct {
+	app  *app.App
+	refs *listCache[git.Ref]
+}
+
+// NewGitRefProvider completes the words starting with # with the branches,
+// tags and recent commits of the repository
+func NewGitRefProvider(app *app.App) dialog.CompletionProvider {
+	return &gitRefProvider{
+		app:  app,
+		refs: newListCache[git.Ref](gitRefsTTL),
+	}
+}
+
+func (p *gitRefProvider) GetId() string {
+	return "git"
+}
+
+func (p *gitRefProvider) GetEntry() dialog.CompletionItemI {
+	return dialog.NewCompletionItem(dialog.CompletionItem{
+		Title: "Git branches & commits",
+		Value: "git",
+	})
+}
+
+func (p *gitRefProvider) GetEmptyMessage() string {
+	return "no matching branches or commits"
+}
+
+func (p *gitRefProvider) GetChildEntries(query string) ([]dialog.CompletionItemI, error) {
+	refs, err := p.refs.get(func() ([]git.Ref, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		refs, err := git.Refs(ctx, p.app.Info.Path.Cwd, gitRefCommits)
+		if errors.Is(err, git.ErrNotRepository) {
+			return nil, nil
+		}
+		return refs, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, len(refs))
+	byTitle := make(map[string]git.Ref, len(refs))
+	for i, ref := range refs {
+		titles[i] = gitRefTitle(ref)
+		byTitle[titles[i]] = ref
+	}
+	if query != "" {
+		ranks := fuzzy.RankFindFold(query, titles)
+		sort.Sort(ranks)
+		titles = titles[:0]
+		for _, rank := range ranks {
+			titles = append(titles, rank.Target)
+		}
+	}
+
+	items := make([]dialog.CompletionItemI, 0, min(len(titles), maxCompletions))
+	for _, title := range titles[:min(len(titles), maxCompletions)] {
+		items = append(items, dialog.NewCompletionItem(dialog.CompletionItem{
+			Title: title,
+			Value: byTitle[title].Name,
+		}))
+	}
+	return items, nil
+}
+
+// gitRefTitle shows a ref with its kind, and a commit with its subject
+func gitRefTitle(ref git.Ref) string {
+	if ref.Kind == "commit" {
+		return ref.Name + " " + ref.Subject
+	}
+	return ref.Name + " (" + ref.Kind + ")"
+}
@@ -0,0 +1,146 @@
+package completions
+
+import (
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/dialog"
+)
+
+// environmentTTL is how long the environment is reused, it only changes
+// when the TUI is restarted but is cheap to read again
+const environmentTTL = time.Minute
+
+// secretMarkers leave out the environment variables likely holding secrets,
+// which would otherwise be one key away from a prompt sent to a provider
+var secretMarkers = []string{"KEY", "TOKEN", "SECRET", "PASSWORD", "PASSWD", "CREDENTIAL", "AUTH", "COOKIE"}
+
+// variable is a name completed after $ and the value it inserts
+type variable struct {
+	Name  string
+	Value string
+}
+
+type variableProvider struct {
+	app         *app.App
+	environment *listCache[variable]
+}
+
+// NewVariableProvider completes the words starting with $ with the value
+// of a session variable or an environment variable
+func NewVariableProvider(app *app.App) dialog.CompletionProvider {
+	return &variableProvider{
+		app:         app,
+		environment: newListCache[variable](environmentTTL),
+	}
+}
+
+func (p *variableProvider) GetId() string {
+	return "variables"
+}
+
+func (p *variableProvider) GetEntry() dialog.CompletionItemI {
+	return dialog.NewCompletionItem(dialog.CompletionItem{
+		Title: "Variables",
+		Value: "variables",
+	})
+}
+
+func (p *variableProvider) GetEmptyMessage() string {
+	return "no matching variables"
+}
+
+// sessionVariables are the variables of the session being viewed, read on
+// every query since they change with it
+func (p *variableProvider) sessionVariables() []variable {
+	variables := []variable{
+		{Name: "CWD", Value: p.app.Info.Path.Cwd},
+		{Name: "ROOT", Value: p.app.Info.Path.Root},
+	}
+	if p.app.Session != nil && p.app.Session.ID != "" {
+		variables = append(variables,
+			variable{Name: "SESSION_ID", Value: p.app.Session.ID},
+			variable{Name: "SESSION_TITLE", Value: p.app.Session.Title},
+		)
+	}
+	if p.app.Provider != nil && p.app.Model != nil {
+		variables = append(variables, variable{Name: "MODEL", Value: p.app.Provider.ID + "/" + p.app.Model.ID})
+	}
+	if p.app.Git != nil && p.app.Git.Branch != "" {
+		variables = append(variables, variable{Name: "BRANCH", Value: p.app.Git.Branch})
+	}
+	return variables
+}
+
+func (p *variableProvider) GetChildEntries(query string) ([]dialog.CompletionItemI, error) {
+	environment, _ := p.environment.get(func() ([]variable, error) {
+		return environmentVariables(os.Environ()), nil
+	})
+	variables := append(p.sessionVariables(), environment...)
+
+	names := make([]string, 0, len(variables))
+	byName := make(map[string]variable, len(variables))
+	for _, v := range variables {
+		// a session variable hides the environment variable of the same name
+		if _, ok := byName[v.Name]; ok || v.Value == "" {
+			continue
+		}
+		names = append(names, v.Name)
+		byName[v.Name] = v
+	}
+	if query != "" {
+		ranks := fuzzy.RankFindFold(query, names)
+		sort.Sort(ranks)
+		names = names[:0]
+		for _, rank := range ranks {
+			names = append(names, rank.Target)
+		}
+	}
+
+	items := make([]dialog.CompletionItemI, 0, min(len(names), maxCompletions))
+	for _, name := range names[:min(len(names), maxCompletions)] {
+		v := byName[name]
+		items = append(items, dialog.NewCompletionItem(dialog.CompletionItem{
+			Title: "$" + v.Name + " = " + displayValue(v.Value),
+			Value: v.Value,
+		}))
+	}
+	return items, nil
+}
+
+// environmentVariables parses os.Environ, sorted by name and without the
+// variables that look like secrets
+func environmentVariables(environ []string) []variable {
+	var variables []variable
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			continue
+		}
+		upper := strings.ToUpper(name)
+		if slices.ContainsFunc(secretMarkers, func(marker string) bool {
+			return strings.Contains(upper, marker)
+		}) {
+			continue
+		}
+		variables = append(variables, variable{Name: name, Value: value})
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables
+}
+
+// displayValue shortens a value to one line of the completion list
+func displayValue(value string) string {
+	line, _, _ := strings.Cut(value, "\n")
+	if runes := []rune(line); len(runes) > 60 {
+		line = string(runes[:59]) + "…"
+	}
+	return line
+}
@@ -0,0 +1,50 @@
+package completions
+
+import (
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/dialog"
+)
+
+// maxCompletions is how many matches a provider returns
+const maxCompletions = 50
+
+type workspaceFileProvider struct {
+	app *app.App
+}
+
+// NewWorkspaceFileProvider completes the words starting with @ with the
+// files of the workspace, searched in the local file index instead of on
+// the server
+func NewWorkspaceFileProvider(app *app.App) dialog.CompletionProvider {
+	return &workspaceFileProvider{app: app}
+}
+
+func (p *workspaceFileProvider) GetId() string {
+	return "workspace"
+}
+
+func (p *workspaceFileProvider) GetEntry() dialog.CompletionItemI {
+	return dialog.NewCompletionItem(dialog.CompletionItem{
+		Title: "Workspace files",
+		Value: "workspace",
+	})
+}
+
+func (p *workspaceFileProvider) GetEmptyMessage() string {
+	return "no matching files"
+}
+
+func (p *workspaceFileProvider) GetChildEntries(query string) ([]dialog.CompletionItemI, error) {
+	return fileItems(p.app.Files.Search(query, maxCompletions)), nil
+}
+
+func fileItems(files []string) []dialog.CompletionItemI {
+	items := make([]dialog.CompletionItemI, 0, len(files))
+	for _, file := range files {
+		items = append(items, dialog.NewCompletionItem(dialog.CompletionItem{
+			Title: file,
+			Value: file,
+		}))
+	}
+	return items
+}
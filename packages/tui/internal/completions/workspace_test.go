@@ -0,0 +1,35 @@
+package completions
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListCache(t *testing.T) {
+	cache := newListCache[string](time.Hour)
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"a"}, nil
+	}
+	cache.get(load)
+	if items, _ := cache.get(load); loads != 1 || len(items) != 1 {
+		t.Errorf("second get loaded again, %d loads", loads)
+	}
+
+	cache.ttl = 0
+	items, err := cache.get(func() ([]string, error) { return nil, errors.New("failed") })
+	if err == nil || !reflect.DeepEqual(items, []string{"a"}) {
+		t.Errorf("failed load = %v, %v, want the cached list and the error", items, err)
+	}
+}
+
+func TestEnvironmentVariables(t *testing.T) {
+	variables := environmentVariables([]string{"SHELL=/bin/zsh", "OPENAI_API_KEY=sk-1", "GITHUB_TOKEN=ghp", "EDITOR=vim"})
+	want := []variable{{Name: "EDITOR", Value: "vim"}, {Name: "SHELL", Value: "/bin/zsh"}}
+	if !reflect.DeepEqual(variables, want) {
+		t.Errorf("environmentVariables = %v, want %v without the secrets", variables, want)
+	}
+}
@@ -1,48 +1,142 @@
 package chat
 
 import (
+	"container/list"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"sync"
+
+	"github.com/sst/dgmo/internal/theme"
+)
+
+const (
+	// messageCacheWidths is how many widths keep their renders, so going
+	// back and forth between two window sizes reuses both
+	messageCacheWidths = 3
+	// messageCacheEntries is how many renders each width keeps
+	messageCacheEntries = 512
 )
 
-// MessageCache caches rendered messages to avoid re-rendering
+// MessageCacheStats reports message render cache usage
+type MessageCacheStats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+	// Widths maps every cached width to its number of renders
+	Widths map[int]int
+	// Capacity is the limit of renders per width
+	Capacity int
+}
+
+func (s MessageCacheStats) String() string {
+	total := s.Hits + s.Misses
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(s.Hits) / float64(total) * 100
+	}
+	return fmt.Sprintf("hits=%d misses=%d evictions=%d widths=%d hit_ratio=%.1f%%",
+		s.Hits, s.Misses, s.Evictions, len(s.Widths), ratio)
+}
+
+type messageCacheEntry struct {
+	key   string
+	value string
+}
+
+// widthCache is an LRU of the renders at one width
+type widthCache struct {
+	width   int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// MessageCache caches rendered messages to avoid re-rendering. Renders are
+// kept per width, the least recently used width is dropped as a whole once
+// messageCacheWidths are cached.
 type MessageCache struct {
-	mu    sync.RWMutex
-	cache map[string]string
+	mu       sync.Mutex
+	capacity int
+	widths   *list.List // of *widthCache, most recently used first
+	stats    MessageCacheStats
 }
 
 // NewMessageCache creates a new message cache
 func NewMessageCache() *MessageCache {
 	return &MessageCache{
-		cache: make(map[string]string),
+		capacity: messageCacheEntries,
+		widths:   list.New(),
 	}
 }
 
-// generateKey creates a unique key for a message based on its content and rendering parameters
+// generateKey creates a unique key for a message based on its content and rendering parameters.
+// The current theme is part of every key, so switching themes needs no invalidation
+// and switching back reuses the earlier renders.
 func (c *MessageCache) GenerateKey(params ...any) string {
 	h := sha256.New()
+	h.Write(fmt.Appendf(nil, "%p", theme.CurrentTheme()))
 	for _, param := range params {
 		h.Write(fmt.Appendf(nil, ":%v", param))
 	}
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-// Get retrieves a cached rendered message
-func (c *MessageCache) Get(key string) (string, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// bucket returns the renders at width, creating them if create is set.
+// Callers must hold the lock.
+func (c *MessageCache) bucket(width int, create bool) *widthCache {
+	for element := c.widths.Front(); element != nil; element = element.Next() {
+		if bucket := element.Value.(*widthCache); bucket.width == width {
+			c.widths.MoveToFront(element)
+			return bucket
+		}
+	}
+	if !create {
+		return nil
+	}
+	bucket := &widthCache{width: width, entries: make(map[string]*list.Element), order: list.New()}
+	c.widths.PushFront(bucket)
+	for c.widths.Len() > messageCacheWidths {
+		oldest := c.widths.Back()
+		c.widths.Remove(oldest)
+		c.stats.Evictions += oldest.Value.(*widthCache).order.Len()
+	}
+	return bucket
+}
+
+// Get retrieves a message rendered at width
+func (c *MessageCache) Get(width int, key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	content, exists := c.cache[key]
-	return content, exists
+	if bucket := c.bucket(width, false); bucket != nil {
+		if element, ok := bucket.entries[key]; ok {
+			bucket.order.MoveToFront(element)
+			c.stats.Hits++
+			return element.Value.(*messageCacheEntry).value, true
+		}
+	}
+	c.stats.Misses++
+	return "", false
 }
 
-// Set stores a rendered message in the cache
-func (c *MessageCache) Set(key string, content string) {
+// Set stores a message rendered at width
+func (c *MessageCache) Set(width int, key string, content string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.cache[key] = content
+
+	bucket := c.bucket(width, true)
+	if element, ok := bucket.entries[key]; ok {
+		element.Value.(*messageCacheEntry).value = content
+		bucket.order.MoveToFront(element)
+		return
+	}
+	bucket.entries[key] = bucket.order.PushFront(&messageCacheEntry{key: key, value: content})
+	for bucket.order.Len() > c.capacity {
+		oldest := bucket.order.Back()
+		bucket.order.Remove(oldest)
+		delete(bucket.entries, oldest.Value.(*messageCacheEntry).key)
+		c.stats.Evictions++
+	}
 }
 
 // Clear removes all entries from the cache
@@ -50,13 +144,32 @@ func (c *MessageCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.cache = make(map[string]string)
+	c.widths.Init()
 }
 
 // Size returns the number of cached entries
 func (c *MessageCache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	size := 0
+	for element := c.widths.Front(); element != nil; element = element.Next() {
+		size += element.Value.(*widthCache).order.Len()
+	}
+	return size
+}
 
-	return len(c.cache)
+// Stats returns the cache metrics
+func (c *MessageCache) Stats() MessageCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Widths = make(map[int]int)
+	for element := c.widths.Front(); element != nil; element = element.Next() {
+		bucket := element.Value.(*widthCache)
+		stats.Widths[bucket.width] = bucket.order.Len()
+	}
+	stats.Capacity = c.capacity
+	return stats
 }
@@ -0,0 +1,50 @@
+package chat
+
+import "testing"
+
+func TestMessageCacheKeepsRendersPerWidth(t *testing.T) {
+	c := NewMessageCache()
+	c.Set(80, "a", "a at 80")
+	c.Set(120, "a", "a at 120")
+
+	// toggling between two sizes keeps both renders
+	if content, ok := c.Get(80, "a"); !ok || content != "a at 80" {
+		t.Errorf("Get(80) = %q, %v", content, ok)
+	}
+	if content, ok := c.Get(120, "a"); !ok || content != "a at 120" {
+		t.Errorf("Get(120) = %q, %v", content, ok)
+	}
+
+	// a new width drops the least recently used one
+	c.Set(100, "a", "a at 100")
+	c.Set(60, "a", "a at 60")
+	if _, ok := c.Get(80, "a"); ok {
+		t.Error("the least recently used width was kept")
+	}
+	stats := c.Stats()
+	if len(stats.Widths) != messageCacheWidths {
+		t.Errorf("%d widths cached, want %d", len(stats.Widths), messageCacheWidths)
+	}
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestMessageCacheLimitsEntries(t *testing.T) {
+	c := NewMessageCache()
+	c.capacity = 2
+	c.Set(80, "a", "a")
+	c.Set(80, "b", "b")
+	c.Get(80, "a")
+	c.Set(80, "c", "c")
+
+	if _, ok := c.Get(80, "b"); ok {
+		t.Error("the least recently used render was kept")
+	}
+	if _, ok := c.Get(80, "a"); !ok {
+		t.Error("a recently used render was evicted")
+	}
+	if stats := c.Stats(); stats.Widths[80] != 2 || stats.Evictions != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -1,6 +1,7 @@
 package chat
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/sst/dgmo/internal/commands"
 	"github.com/sst/dgmo/internal/components/dialog"
 	"github.com/sst/dgmo/internal/components/textarea"
+	"github.com/sst/dgmo/internal/components/toast"
 	"github.com/sst/dgmo/internal/image"
 	"github.com/sst/dgmo/internal/layout"
 	"github.com/sst/dgmo/internal/styles"
@@ -27,12 +29,15 @@ type EditorComponent interface {
 	Content(width int, align lipgloss.Position) string
 	Lines() int
 	Value() string
+	SetValue(value string)
 	Focused() bool
 	Focus() (tea.Model, tea.Cmd)
 	Blur()
 	Submit() (tea.Model, tea.Cmd)
 	Clear() (tea.Model, tea.Cmd)
 	Paste() (tea.Model, tea.Cmd)
+	PasteText(text string) (tea.Model, tea.Cmd)
+	PasteCode() (tea.Model, tea.Cmd)
 	Newline() (tea.Model, tea.Cmd)
 	Previous() (tea.Model, tea.Cmd)
 	Next() (tea.Model, tea.Cmd)
@@ -66,6 +71,9 @@ func (m *editorComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyPressMsg:
 		// Maximize editor responsiveness for printable characters
 		if msg.Text != "" {
+			if msg.Text == " " {
+				m.expandSnippet()
+			}
 			m.textarea, cmd = m.textarea.Update(msg)
 			cmds = append(cmds, cmd)
 			return m, tea.Batch(cmds...)
@@ -125,7 +133,7 @@ func (m *editorComponent) Content(width int, align lipgloss.Position) string {
 		Width(width).
 		PaddingTop(1).
 		PaddingBottom(1).
-		BorderStyle(lipgloss.ThickBorder()).
+		BorderStyle(styles.BlockBorder()).
 		BorderForeground(t.Border()).
 		BorderBackground(t.Background()).
 		BorderLeft(true).
@@ -133,13 +141,25 @@ func (m *editorComponent) Content(width int, align lipgloss.Position) string {
 		Render(textarea)
 
 	hint := base(m.getSubmitKeyText()) + muted(" send   ")
+	generation, hasGeneration := m.app.CurrentGeneration()
 	if m.app.IsBusy() {
 		keyText := m.getInterruptKeyText()
+		working := muted("working") + m.spinner.View()
+		if styles.Accessible() {
+			working = muted(styles.StatusRunning + " working")
+		}
+		if hasGeneration {
+			// the estimate ticks with the spinner, so a runaway response
+			// shows before it finishes
+			working += muted(" " + formatGeneration(generation) + " ")
+		}
 		if m.interruptKeyInDebounce {
-			hint = muted("working") + m.spinner.View() + muted("  ") + base(keyText+" again") + muted(" interrupt")
+			hint = working + muted("  ") + base(keyText+" again") + muted(" interrupt")
 		} else {
-			hint = muted("working") + m.spinner.View() + muted("  ") + base(keyText) + muted(" interrupt")
+			hint = working + muted("  ") + base(keyText) + muted(" interrupt")
 		}
+	} else if hasGeneration {
+		hint += muted("last " + formatGeneration(generation) + "   ")
 	}
 
 	model := ""
@@ -157,6 +177,16 @@ func (m *editorComponent) Content(width int, align lipgloss.Position) string {
 	return content
 }
 
+// formatGeneration shows the output tokens and the cost of a response, with
+// a tilde while they are estimated
+func formatGeneration(generation app.Generation) string {
+	approx := "~"
+	if generation.Final {
+		approx = ""
+	}
+	return fmt.Sprintf("%s%s tok · %s$%.3f", approx, util.FormatTokens(generation.Tokens), approx, generation.Cost)
+}
+
 func (m *editorComponent) View(width int, align lipgloss.Position) string {
 	if m.Lines() > 1 {
 		t := theme.CurrentTheme()
@@ -202,7 +232,12 @@ func (m *editorComponent) Value() string {
 	return m.textarea.Value()
 }
 
+func (m *editorComponent) SetValue(value string) {
+	m.textarea.SetValue(value)
+}
+
 func (m *editorComponent) Submit() (tea.Model, tea.Cmd) {
+	m.expandSnippet()
 	value := strings.TrimSpace(m.Value())
 	if value == "" {
 		return m, nil
@@ -213,6 +248,12 @@ func (m *editorComponent) Submit() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	slash, err := m.app.Commands.ParseSlash(value)
+	if err != nil && !errors.Is(err, commands.ErrUnknownCommand) {
+		// keep the input so the arguments can be fixed
+		return m, toast.NewErrorToast(err.Error())
+	}
+
 	var cmds []tea.Cmd
 	updated, cmd := m.Clear()
 	m = updated.(*editorComponent)
@@ -229,12 +270,32 @@ func (m *editorComponent) Submit() (tea.Model, tea.Cmd) {
 		m.currentMessage = ""
 	}
 
+	if err == nil {
+		cmds = append(cmds, util.CmdHandler(slash))
+		return m, tea.Batch(cmds...)
+	}
+
 	m.attachments = nil
 
 	cmds = append(cmds, util.CmdHandler(app.SendMsg{Text: value, Attachments: attachments}))
 	return m, tea.Batch(cmds...)
 }
 
+// expandSnippet replaces the snippet trigger just before the cursor with the
+// text of the snippet
+func (m *editorComponent) expandSnippet() {
+	if m.app.Snippets == nil {
+		return
+	}
+	word := m.textarea.WordBeforeCursor()
+	text, ok := m.app.Snippets.Expand(word)
+	if !ok {
+		return
+	}
+	m.textarea.DeleteBeforeCursor(len([]rune(word)))
+	m.textarea.InsertString(text)
+}
+
 func (m *editorComponent) Clear() (tea.Model, tea.Cmd) {
 	m.textarea.Reset()
 	return m, nil
@@ -251,8 +312,34 @@ func (m *editorComponent) Paste() (tea.Model, tea.Cmd) {
 		attachment := app.Attachment{FilePath: attachmentName, FileName: attachmentName, Content: imageBytes, MimeType: "image/png"}
 		m.attachments = append(m.attachments, attachment)
 	} else {
-		m.textarea.SetValue(m.textarea.Value() + text)
+		m.textarea.SetValue(m.textarea.Value() + app.SanitizePaste(text, m.app.State.Paste))
+	}
+	return m, nil
+}
+
+// PasteText inserts pasted text at the cursor, as text even when it holds
+// newlines or characters bound to commands
+func (m *editorComponent) PasteText(text string) (tea.Model, tea.Cmd) {
+	m.textarea.InsertString(app.SanitizePaste(text, m.app.State.Paste))
+	return m, nil
+}
+
+// PasteCode appends the text on the clipboard fenced as a code block
+func (m *editorComponent) PasteCode() (tea.Model, tea.Cmd) {
+	_, text, err := image.GetImageFromClipboard()
+	if err != nil {
+		slog.Error(err.Error())
+		return m, nil
+	}
+	text = app.SanitizePaste(text, m.app.State.Paste)
+	if strings.TrimSpace(text) == "" {
+		return m, toast.NewInfoToast("No text on the clipboard")
+	}
+	value := m.textarea.Value()
+	if value != "" && !strings.HasSuffix(value, "\n") {
+		value += "\n"
 	}
+	m.textarea.SetValue(value + app.CodeBlock(text) + "\n")
 	return m, nil
 }
 
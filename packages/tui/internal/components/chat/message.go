@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
-	"regexp"
 	"slices"
 	"strings"
 	"sync"
@@ -17,8 +16,11 @@ import (
 	"github.com/sst/dgmo/internal/app"
 	"github.com/sst/dgmo/internal/components/diff"
 	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/paths"
 	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/styles/glyphs"
 	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
 	"github.com/sst/opencode-sdk-go"
 	"github.com/tidwall/gjson"
 	"golang.org/x/text/cases"
@@ -30,9 +32,60 @@ var (
 	taskStartTimes  = make(map[string]time.Time)
 	taskProgress    = make(map[string]int)
 	taskCurrentTool = make(map[string]string)
+	taskAgents      = make(map[string]int)
+	taskMessages    = make(map[string]string)
+	taskDurations   = make(map[string]time.Duration)
 	taskMutex       sync.RWMutex
 )
 
+// UpdateTaskStart records when the task server says a task started, which
+// is more accurate than when its box was first drawn
+func UpdateTaskStart(taskID string, start time.Time) {
+	if start.UnixMilli() <= 0 {
+		return
+	}
+	taskMutex.Lock()
+	defer taskMutex.Unlock()
+	taskStartTimes[taskID] = start
+}
+
+// UpdateTaskDuration records how long a finished task ran, so its box
+// stops counting
+func UpdateTaskDuration(taskID string, duration time.Duration) {
+	taskMutex.Lock()
+	defer taskMutex.Unlock()
+	taskDurations[taskID] = duration
+}
+
+// UpdateTaskMessage records the last progress message of a task
+func UpdateTaskMessage(taskID string, message string) {
+	taskMutex.Lock()
+	defer taskMutex.Unlock()
+	taskMessages[taskID] = message
+}
+
+// GetTaskMessage gets the last progress message of a task
+func GetTaskMessage(taskID string) string {
+	taskMutex.RLock()
+	defer taskMutex.RUnlock()
+	return taskMessages[taskID]
+}
+
+// UpdateTaskAgent records the agent number of a task, as sent by the server
+// or counted by the task client for servers that don't send one
+func UpdateTaskAgent(taskID string, number int) {
+	taskMutex.Lock()
+	defer taskMutex.Unlock()
+	taskAgents[taskID] = number
+}
+
+// GetTaskAgent gets the agent number of a task, 0 if it is unknown
+func GetTaskAgent(taskID string) int {
+	taskMutex.RLock()
+	defer taskMutex.RUnlock()
+	return taskAgents[taskID]
+}
+
 // UpdateTaskProgress updates the progress for a task
 func UpdateTaskProgress(taskID string, progress int) {
 	taskMutex.Lock()
@@ -68,9 +121,8 @@ func GetTaskTool(taskID string) string {
 }
 
 func toMarkdown(content string, width int, backgroundColor compat.AdaptiveColor) string {
-	r := styles.GetMarkdownRenderer(width-7, backgroundColor)
 	content = strings.ReplaceAll(content, app.RootPath+"/", "")
-	rendered, _ := r.Render(content)
+	rendered, _ := styles.RenderMarkdown(content, width-7, backgroundColor)
 	lines := strings.Split(rendered, "\n")
 
 	if len(lines) > 0 {
@@ -172,10 +224,14 @@ func renderContentBlock(
 	t := theme.CurrentTheme()
 	renderer := &blockRenderer{
 		border:        true,
-		paddingTop:    1,
-		paddingBottom: 1,
-		paddingLeft:   2,
-		paddingRight:  2,
+		paddingTop:    layout.Scale(1),
+		paddingBottom: layout.Scale(1),
+		paddingLeft:   layout.Scale(2),
+		paddingRight:  layout.Scale(2),
+	}
+	if layout.Current.Density == layout.DensityCompact {
+		renderer.paddingTop, renderer.paddingBottom = 0, 0
+		renderer.paddingLeft, renderer.paddingRight = layout.Scale(1), layout.Scale(1)
 	}
 	for _, option := range options {
 		option(renderer)
@@ -198,7 +254,7 @@ func renderContentBlock(
 
 	if renderer.border {
 		style = style.
-			BorderStyle(lipgloss.ThickBorder()).
+			BorderStyle(styles.BlockBorder()).
 			BorderLeft(true).
 			BorderRight(true).
 			BorderLeftForeground(borderColor).
@@ -237,7 +293,8 @@ func renderText(
 	message opencode.Message,
 	text string,
 	author string,
-	showToolDetails bool,
+	selected bool,
+	raw bool,
 	width int,
 	align lipgloss.Position,
 	toolCalls ...opencode.ToolInvocationPart,
@@ -260,16 +317,20 @@ func renderText(
 
 	content := messageStyle.Render(text)
 	if message.Role == opencode.MessageRoleAssistant {
-		content = toMarkdown(text, width, t.BackgroundPanel())
+		if raw {
+			content = messageStyle.Width(width - 6).Render(text)
+		} else {
+			content = toMarkdown(text, width, t.BackgroundPanel())
+		}
 	}
 
-	if !showToolDetails && toolCalls != nil && len(toolCalls) > 0 {
+	if len(toolCalls) > 0 {
 		content = content + "\n\n"
 		for _, toolCall := range toolCalls {
 			// Special handling for task tool to preserve multi-line format
 			if toolCall.ToolInvocation.ToolName == "task" {
 				taskContent := renderToolTitle(toolCall, message.Metadata, width)
-				// For tasks, don't add the "∟ " prefix as it breaks the box formatting
+				// For tasks, don't add the branch prefix as it breaks the box formatting
 				content = content + taskContent + "\n"
 			} else {
 				title := renderToolTitle(toolCall, message.Metadata, width)
@@ -278,11 +339,13 @@ func renderText(
 					metadata = message.Metadata.Tool[toolCall.ToolInvocation.ToolCallID]
 				}
 				style := styles.NewStyle()
+				prefix := styles.StatusMark(glyphs.Current().Branch, "")
 				if _, ok := metadata.ExtraFields["error"]; ok {
 					style = style.Foreground(t.Error())
+					prefix = styles.StatusMark(prefix, styles.StatusFailed+" ")
 				}
 				title = style.Render(title)
-				title = "∟ " + title + "\n"
+				title = prefix + title + "\n"
 				content = content + title
 			}
 		}
@@ -290,23 +353,184 @@ func renderText(
 
 	content = strings.Join([]string{content, info}, "\n")
 
+	var borderColor compat.AdaptiveColor
 	switch message.Role {
 	case opencode.MessageRoleUser:
-		return renderContentBlock(
-			content,
-			width,
-			align,
-			WithBorderColor(t.Secondary()),
-		)
+		borderColor = t.Secondary()
 	case opencode.MessageRoleAssistant:
-		return renderContentBlock(
-			content,
-			width,
-			align,
-			WithBorderColor(t.Accent()),
-		)
+		borderColor = t.Accent()
+	default:
+		return ""
 	}
-	return ""
+	if selected {
+		borderColor = t.Primary()
+	}
+	return renderContentBlock(
+		content,
+		width,
+		align,
+		WithBorderColor(borderColor),
+	)
+}
+
+// renderCompactionMarker marks where a summary replaced the history above it
+func renderCompactionMarker(report *app.CompactionReport, width int, align lipgloss.Position) string {
+	t := theme.CurrentTheme()
+	title := styles.NewStyle().
+		Foreground(t.Warning()).
+		Background(t.BackgroundPanel()).
+		Bold(true).
+		Render("Compacted summary")
+	detail := fmt.Sprintf(
+		"%d earlier messages summarized, context %s → %s tokens",
+		len(report.Compacted),
+		util.FormatTokens(report.TokensBefore),
+		util.FormatTokens(report.TokensAfter),
+	)
+	return renderContentBlock(
+		title+"\n"+detail,
+		width,
+		align,
+		WithBorderColor(t.Warning()),
+	)
+}
+
+// renderPatchHint points at the review of a multi-file patch below the
+// message that proposed it
+func renderPatchHint(files int, width int, align lipgloss.Position) string {
+	t := theme.CurrentTheme()
+	hint := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundPanel()).
+		Render(fmt.Sprintf("Patch for %d files · /patch to review and apply", files))
+	return renderContentBlock(hint, width, align, WithBorderColor(t.BorderSubtle()))
+}
+
+// agentSummaryLines is how much of a sub-agent's final answer is folded
+// into the parent transcript
+const agentSummaryLines = 6
+
+// agentChangedFiles returns the files a sub-agent wrote or edited, from the
+// tool calls the task tool reports in its metadata
+func agentChangedFiles(metadata opencode.MessageMetadataTool) []string {
+	summary := metadata.JSON.ExtraFields["summary"]
+	if summary.IsNull() {
+		return nil
+	}
+	var files []string
+	for _, call := range gjson.Parse(summary.Raw()).Array() {
+		switch call.Get("toolInvocation.toolName").String() {
+		case "write", "edit", "multiedit":
+			path := call.Get("toolInvocation.args.filePath").String()
+			if path == "" {
+				continue
+			}
+			if !slices.Contains(files, path) {
+				files = append(files, path)
+			}
+		}
+	}
+	return files
+}
+
+// renderAgentSummary folds the final answer and changed files of a finished
+// sub-agent into the parent transcript, pointing at its full sub-session
+func renderAgentSummary(
+	toolCall opencode.ToolInvocationPart,
+	messageMetadata opencode.MessageMetadata,
+	width int,
+	align lipgloss.Position,
+) string {
+	metadata := messageMetadata.Tool[toolCall.ToolInvocation.ToolCallID]
+	sessionID, _ := metadata.ExtraFields["sessionID"].(string)
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel())
+	text := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundPanel())
+
+	name := "Sub-agent"
+	if args, ok := toolCall.ToolInvocation.Args.(map[string]any); ok {
+		if description, ok := args["description"].(string); ok && description != "" {
+			name = description
+		}
+	}
+	if number := GetTaskAgent(sessionID); number > 0 {
+		name = fmt.Sprintf("Agent %d: %s", number, name)
+	}
+	content := styles.NewStyle().
+		Foreground(t.Success()).
+		Background(t.BackgroundPanel()).
+		Bold(true).
+		Render(name + " finished")
+
+	answer := strings.TrimSpace(toolCall.ToolInvocation.Result)
+	if answer != "" {
+		lines := strings.Split(answer, "\n")
+		for _, line := range lines[:min(len(lines), agentSummaryLines)] {
+			content += "\n" + text.Render(ansi.Truncate(line, max(0, width-6), "…"))
+		}
+		if len(lines) > agentSummaryLines {
+			content += "\n" + muted.Render(fmt.Sprintf("… %d more lines", len(lines)-agentSummaryLines))
+		}
+	}
+	for _, file := range agentChangedFiles(metadata) {
+		content += "\n" + muted.Render(glyphs.Current().Branch) + styles.FileLink(file, muted.Render(relative(file)))
+	}
+	if sessionID != "" {
+		content += "\n" + muted.Render("/sub-session "+sessionID+" opens the full transcript")
+	}
+	return renderContentBlock(content, width, align, WithBorderColor(t.Success()))
+}
+
+// renderPatchResult summarizes what a reviewed patch changed
+func renderPatchResult(title string, files []string, width int, align lipgloss.Position) string {
+	t := theme.CurrentTheme()
+	content := styles.NewStyle().
+		Foreground(t.Success()).
+		Background(t.BackgroundPanel()).
+		Bold(true).
+		Render(title)
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel())
+	for _, file := range files {
+		content += "\n" + muted.Render(glyphs.Current().Branch+file)
+	}
+	return renderContentBlock(content, width, align, WithBorderColor(t.Success()))
+}
+
+// renderImagePreview shows an image linked from a message below the link
+func renderImagePreview(preview, url string, width int, align lipgloss.Position) string {
+	t := theme.CurrentTheme()
+	caption := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundPanel()).
+		Render(ansi.Truncate(url, max(0, width-6), "…"))
+	return renderContentBlock(
+		preview+"\n"+caption,
+		width,
+		align,
+		WithBorderColor(t.BorderSubtle()),
+	)
+}
+
+// diffStart returns the line within the details of an edit tool call where
+// its diff starts, below the title
+func diffStart(
+	toolCall opencode.ToolInvocationPart,
+	messageMetadata opencode.MessageMetadata,
+	width int,
+	align lipgloss.Position,
+) (int, bool) {
+	if toolCall.ToolInvocation.ToolName != "edit" || toolCall.ToolInvocation.State != "result" {
+		return 0, false
+	}
+	metadata, ok := messageMetadata.Tool[toolCall.ToolInvocation.ToolCallID]
+	if !ok {
+		return 0, false
+	}
+	if _, ok := metadata.ExtraFields["diff"].(string); !ok {
+		return 0, false
+	}
+	title := renderContentBlock(renderToolTitle(toolCall, messageMetadata, width), width, align)
+	return lipgloss.Height(title), true
 }
 
 func renderToolDetails(
@@ -374,7 +598,7 @@ func renderToolDetails(
 				)
 				formattedDiff = strings.TrimSpace(formattedDiff)
 				formattedDiff = styles.NewStyle().
-					BorderStyle(lipgloss.ThickBorder()).
+					BorderStyle(styles.BlockBorder()).
 					BorderBackground(t.Background()).
 					BorderForeground(t.BackgroundPanel()).
 					BorderLeft(true).
@@ -419,7 +643,7 @@ func renderToolDetails(
 	case "webfetch":
 		if format, ok := toolArgsMap["format"].(string); ok && result != nil {
 			body = *result
-			body = truncateHeight(body, 10)
+			body = truncateHeight(body, layout.Scale(10))
 			if format == "html" || format == "markdown" {
 				body = toMarkdown(body, width, t.BackgroundPanel())
 			}
@@ -462,7 +686,7 @@ func renderToolDetails(
 						_ = json.Unmarshal(data, &toolMetadata)
 
 						step := renderToolTitle(toolCall, messageMetadata, width)
-						step = "∟ " + step
+						step = glyphs.Current().Branch + step
 						steps = append(steps, step)
 					}
 				}
@@ -475,7 +699,7 @@ func renderToolDetails(
 			result = &empty
 		}
 		body = *result
-		body = truncateHeight(body, 10)
+		body = truncateHeight(body, layout.Scale(10))
 	}
 
 	error := ""
@@ -489,12 +713,12 @@ func renderToolDetails(
 		body = styles.NewStyle().
 			Foreground(t.Error()).
 			Background(t.BackgroundPanel()).
-			Render(error)
+			Render(styles.StatusMark("", styles.StatusFailed+" ") + error)
 	}
 
 	if body == "" && error == "" && result != nil {
 		body = *result
-		body = truncateHeight(body, 10)
+		body = truncateHeight(body, layout.Scale(10))
 	}
 
 	title := renderToolTitle(toolCall, messageMetadata, width)
@@ -517,20 +741,11 @@ func renderToolName(name string) string {
 	}
 }
 
-// extractAgentNumber extracts the agent number from task descriptions
-func extractAgentNumber(description string) string {
-	// Look for patterns like "Agent 1", "agent 2", etc.
-	re := regexp.MustCompile(`(?i)agent\s*(\d+)`)
-	matches := re.FindStringSubmatch(description)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	// If no agent number found, generate one based on current tasks
-	return "1"
-}
-
 // getTaskIcon returns an appropriate icon based on the task description
 func getTaskIcon(description string) string {
+	if glyphs.CurrentSupport() == glyphs.ASCII {
+		return glyphs.Current().Icon("task")
+	}
 	desc := strings.ToLower(description)
 	switch {
 	case strings.Contains(desc, "search") || strings.Contains(desc, "find"):
@@ -563,10 +778,10 @@ func renderToolTitle(
 	messageMetadata opencode.MessageMetadata,
 	width int,
 ) string {
-	// TODO: handle truncate to width
-
 	if toolCall.ToolInvocation.State == "partial-call" {
-		return renderToolAction(toolCall.ToolInvocation.ToolName)
+		args, _ := toolCall.ToolInvocation.Args.(string)
+		name := toolCall.ToolInvocation.ToolName
+		return renderToolAction(name, partialToolDetail(name, parsePartialArgs(args)))
 	}
 
 	toolArgs := ""
@@ -616,18 +831,28 @@ func renderToolTitle(
 				status = "completed"
 			}
 
-			// Track task start time
+			// Track task start time. Task events are keyed by the
+			// sub-session, which the task tool reports in its metadata.
 			taskKey := toolCall.ToolInvocation.ToolCallID
+			if metadata, ok := messageMetadata.Tool[taskKey]; ok {
+				if sessionID, ok := metadata.ExtraFields["sessionID"].(string); ok && sessionID != "" {
+					taskKey = sessionID
+				}
+			}
 			taskMutex.Lock()
 			if _, exists := taskStartTimes[taskKey]; !exists && status == "running" {
 				taskStartTimes[taskKey] = time.Now()
 			}
 			startTime, exists := taskStartTimes[taskKey]
+			finalDuration, finished := taskDurations[taskKey]
 			taskMutex.Unlock()
 
 			// Calculate duration
 			var duration time.Duration
-			if exists {
+			switch {
+			case finished && status != "running":
+				duration = finalDuration
+			case exists:
 				duration = time.Since(startTime)
 			}
 
@@ -642,8 +867,13 @@ func renderToolTitle(
 			// Get current tool for dynamic status
 			currentTool := GetTaskTool(taskKey)
 
+			taskName := description
+			if number := GetTaskAgent(taskKey); number > 0 {
+				taskName = fmt.Sprintf("Agent %d: %s", number, description)
+			}
+
 			// Use the beautiful task renderer with tool info
-			return RenderTaskBoxWithTool(icon, description, "", status, progress, duration, width, currentTool)
+			return RenderTaskBoxWithProgress(icon, taskName, "", status, progress, duration, width, currentTool, GetTaskMessage(taskKey))
 		}
 	case "webfetch":
 		toolArgs = renderArgs(&toolArgsMap, "url")
@@ -654,59 +884,60 @@ func renderToolTitle(
 		toolName := renderToolName(toolCall.ToolInvocation.ToolName)
 		title = fmt.Sprintf("%s %s", toolName, toolArgs)
 	}
+	title = truncateTitle(title, width-layout.Scale(toolTitlePadding))
+	if filePath, ok := toolArgsMap["filePath"].(string); ok {
+		title = styles.FileLink(filePath, title)
+	}
 	return title
 }
 
-func renderToolAction(name string) string {
+// renderToolAction renders a tool call whose arguments are still streaming,
+// with detail, the target known so far, next to the action
+func renderToolAction(name, detail string) string {
 	spinner := GetSpinnerFrame()
 	t := theme.CurrentTheme()
 	spinnerStyle := lipgloss.NewStyle().Foreground(t.Primary()).Bold(true)
 	textStyle := lipgloss.NewStyle().Foreground(t.Text()).Italic(true)
 
-	var icon, action string
+	var action string
 	switch name {
 	case "task":
-		icon = "🚀"
 		action = "Creating agent"
 	case "bash":
-		icon = "⚡"
 		action = "Writing command"
 	case "edit":
-		icon = "✏️"
 		action = "Preparing edit"
 	case "webfetch":
-		icon = "🌐"
 		action = "Fetching from web"
 	case "glob":
-		icon = "🔎"
 		action = "Finding files"
 	case "grep":
-		icon = "🔍"
 		action = "Searching content"
 	case "list":
-		icon = "📁"
 		action = "Listing directory"
 	case "read":
-		icon = "📖"
 		action = "Reading file"
 	case "write":
-		icon = "💾"
 		action = "Preparing write"
 	case "todowrite":
-		icon = "📋"
 		action = "Writing tasks"
 	case "todoread":
-		icon = "📋"
 		action = "Reading tasks"
 	case "patch":
-		icon = "🔧"
 		action = "Preparing patch"
 	default:
-		icon = "⚙️"
 		action = "Working"
 	}
 
-	return fmt.Sprintf("%s %s %s", icon, spinnerStyle.Render(spinner), textStyle.Render(action+"..."))
+	text := textStyle.Render(action + "...")
+	if detail != "" {
+		text = textStyle.Render(action) + " " + lipgloss.NewStyle().Foreground(t.TextMuted()).Render(detail)
+	}
+	if styles.Accessible() {
+		return styles.StatusRunning + " " + text
+	}
+	icon := glyphs.Current().Icon(name)
+	return fmt.Sprintf("%s %s %s", icon, spinnerStyle.Render(spinner), text)
 }
 
 type fileRenderer struct {
@@ -795,8 +1026,7 @@ func truncateHeight(content string, height int) string {
 }
 
 func relative(path string) string {
-	path = strings.TrimPrefix(path, app.CwdPath+"/")
-	return strings.TrimPrefix(path, app.RootPath+"/")
+	return paths.Relative(path, app.CwdPath, app.RootPath)
 }
 
 func extension(path string) string {
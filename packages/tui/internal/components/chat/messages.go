@@ -1,13 +1,18 @@
 package chat
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/v2/viewport"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
 	"github.com/sst/dgmo/internal/components/dialog"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/image"
 	"github.com/sst/dgmo/internal/layout"
 	"github.com/sst/dgmo/internal/styles"
 	"github.com/sst/dgmo/internal/theme"
@@ -29,6 +34,16 @@ type MessagesComponent interface {
 	// Previous() (tea.Model, tea.Cmd)
 	// Next() (tea.Model, tea.Cmd)
 	ToolDetailsVisible() bool
+	// SelectedMessage returns the ID of the message selected with the
+	// mouse, or an empty string
+	SelectedMessage() string
+	// Following reports whether the view sticks to the newest message
+	Following() bool
+	// ScrollPosition returns the message at the top of the view and how
+	// many of its lines are scrolled past, to bookmark the spot
+	ScrollPosition() (messageID string, offset int, ok bool)
+	// RenderStats reports the last render and the render cache metrics
+	RenderStats() RenderStats
 }
 
 type messagesComponent struct {
@@ -40,17 +55,48 @@ type messagesComponent struct {
 	rendering       bool
 	showToolDetails bool
 	tail            bool
+	lineCounts      map[string]int // rendered line count per message ID
+	renderedWindow  [2]int         // transcript lines fully rendered by the last renderView
+	layoutIDs       []string       // message IDs in transcript order at the last renderView
+	layoutStarts    []int          // first transcript line of each message in layoutIDs
+	anchors         map[string]scrollAnchor
+	restorePending  bool // restore the session's anchor once rendering finishes
+	velocity        float64
+	remainder       float64
+	momentumActive  bool
+	focusedTitle    string                  // full text of the truncated tool title that was clicked
+	selectedID      string                  // message selected by clicking it
+	rawMessages     map[string]bool         // message IDs showing their markdown source
+	toggledTools    map[string]bool         // tool call IDs whose details differ from showToolDetails
+	toolRegions     map[string][]toolRegion // clickable tool lines per message ID
+	stats           RenderStats             // of the last renderView
+}
+
+// RenderStats describe the last render of the transcript
+type RenderStats struct {
+	Duration time.Duration
+	// Rendered is the number of messages rendered, from the cache or not
+	Rendered int
+	Cache    MessageCacheStats
 }
 type renderFinishedMsg struct{}
 type ToggleToolDetailsMsg struct{}
 
+// TranscriptShownMsg is sent when the component is shown again, after it
+// was put aside with its session in a background tab
+type TranscriptShownMsg struct{}
+
+// ToggleRawMarkdownMsg switches the selected message, or the last response
+// when none is selected, between rendered markdown and its source
+type ToggleRawMarkdownMsg struct{}
+
 func (m *messagesComponent) Init() tea.Cmd {
 	return tea.Batch(m.viewport.Init())
 }
 
 func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case app.SendMsg:
 		m.viewport.GotoBottom()
 		m.tail = true
@@ -61,31 +107,129 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.GotoBottom()
 		}
 		return m, nil
+	case tea.MouseWheelMsg:
+		m.focusedTitle = ""
+		return m, m.handleWheel(msg)
+	case tea.MouseClickMsg:
+		m.focusedTitle = m.titleAt(msg.Y)
+		return m, m.handleClick(msg)
+	case scrollMomentumMsg:
+		return m, m.stepMomentum()
 	case dialog.ThemeSelectedMsg:
-		m.cache.Clear()
-		return m, m.Reload()
+		// heights do not depend on the theme, so only the messages in view
+		// render again, in place, and the old frame stays up until then
+		m.renderView()
+		if m.tail {
+			m.viewport.GotoBottom()
+		}
+		return m, nil
+	case ToggleRawMarkdownMsg:
+		id := m.rawTarget()
+		if id == "" {
+			return m, nil
+		}
+		m.rawMessages[id] = !m.rawMessages[id]
+		delete(m.lineCounts, id)
+		m.renderView()
+		if m.tail {
+			m.viewport.GotoBottom()
+		}
+		return m, nil
+	case app.BookmarkJumpMsg:
+		m.stopMomentum()
+		if !m.scrollTo(scrollAnchor{messageID: msg.Bookmark.MessageID, offset: msg.Bookmark.Offset}) {
+			return m, toast.NewWarningToast("The message of " + msg.Bookmark.Name + " is no longer in the transcript")
+		}
+		m.rememberAnchor()
+		return m, nil
 	case ToggleToolDetailsMsg:
 		m.showToolDetails = !m.showToolDetails
+		clear(m.toggledTools)
+		clear(m.lineCounts)
+		m.restorePending = true
 		return m, m.Reload()
 	case app.SessionSelectedMsg:
-		m.cache.Clear()
+		m.resetLayout()
+		m.stopMomentum()
 		m.tail = true
+		m.restorePending = true
 		return m, m.Reload()
+	case app.LayoutChangedMsg:
+		// renders are cached per viewport width, which did not change
+		m.resetLayout()
+		m.renderView()
+		if m.tail {
+			m.viewport.GotoBottom()
+		}
+		return m, nil
+	case TranscriptShownMsg:
+		// messages may have arrived in the background, the renders of the
+		// others are still good
+		m.relayout()
+		m.renderView()
+		if m.tail {
+			m.viewport.GotoBottom()
+		}
+		return m, nil
 	case app.SessionClearedMsg:
-		m.cache.Clear()
+		m.resetLayout()
 		cmd := m.Reload()
 		return m, cmd
-	case app.SessionSwitchedMsg:
+	case app.SessionSwitchedMsg, app.BundleImportedMsg, app.TranscriptRecoveredMsg:
 		// Clear cache and reload when session switches
-		m.cache.Clear()
+		m.resetLayout()
+		m.stopMomentum()
 		m.tail = true
+		m.restorePending = true
 		return m, m.Reload()
 	case renderFinishedMsg:
 		m.rendering = false
+		if m.restorePending {
+			m.restorePending = false
+			if m.restoreAnchor() {
+				return m, nil
+			}
+		}
+		if m.tail {
+			m.viewport.GotoBottom()
+		}
+	case opencode.EventListResponseEventMessageUpdated:
+		delete(m.lineCounts, msg.Properties.Info.ID)
+		m.renderView()
+		if m.tail {
+			m.viewport.GotoBottom()
+		}
+	case app.TaskStartedMsg, app.TaskProgressMsg, app.TaskCompletedMsg, app.TaskFailedMsg:
+		// the task boxes of unfinished calls are not cached
+		m.renderView()
 		if m.tail {
 			m.viewport.GotoBottom()
 		}
-	case opencode.EventListResponseEventSessionUpdated, opencode.EventListResponseEventMessageUpdated:
+		return m, nil
+	case app.ToolTimerTickMsg:
+		// unfinished tool calls are not cached, so this redraws their timers
+		// and the task boxes of running agents
+		if len(m.app.ToolTimers.Running()) > 0 || m.app.RunningTasks() > 0 {
+			m.renderView()
+			if m.tail {
+				m.viewport.GotoBottom()
+			}
+		}
+		return m, nil
+	case app.ImagePreviewLoadedMsg:
+		for _, message := range m.app.Messages {
+			for _, part := range message.Parts {
+				if text, ok := part.AsUnion().(opencode.TextPart); ok && strings.Contains(text.Text, msg.URL) {
+					delete(m.lineCounts, message.ID)
+				}
+			}
+		}
+		m.rerender()
+		return m, nil
+	case opencode.EventListResponseEventSessionUpdated:
+		if m.app.Session == nil || msg.Properties.Info.ID != m.app.Session.ID {
+			break
+		}
 		m.renderView()
 		if m.tail {
 			m.viewport.GotoBottom()
@@ -95,7 +239,8 @@ func (m *messagesComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	viewport, cmd := m.viewport.Update(msg)
 	m.viewport = viewport
 	m.tail = m.viewport.AtBottom()
-	cmds = append(cmds, cmd)
+	m.ensureRendered()
+	cmds = append(cmds, cmd, m.app.ImagePreviews.Fetch())
 
 	return m, tea.Batch(cmds...)
 }
@@ -106,160 +251,381 @@ func (m *messagesComponent) renderView() {
 	}
 
 	measure := util.Measure("messages.renderView")
-	defer measure("messageCount", len(m.app.Messages))
+	start := time.Now()
+	rendered := 0
+	defer func() {
+		m.stats = RenderStats{Duration: time.Since(start), Rendered: rendered}
+		measure(
+			"messageCount", len(m.app.Messages),
+			"renderedCount", rendered,
+			"markdownCache", styles.GetMarkdownCacheStats().String(),
+		)
+	}()
+
+	messages := m.app.Messages
+	contents := make([]*string, len(messages))
+
+	// Messages we have never rendered need a full render to learn their height
+	var unknown []int
+	for i, message := range messages {
+		if _, ok := m.lineCounts[message.ID]; !ok {
+			unknown = append(unknown, i)
+		}
+	}
+	m.renderMessages(unknown, contents)
+	rendered += len(unknown)
+
+	// Every height is known now, so lay out the transcript and render only
+	// the messages that intersect the visible window plus a buffer
+	starts := make([]int, len(messages))
+	ids := make([]string, len(messages))
+	total := 1 // content starts with a blank line
+	for i, message := range messages {
+		starts[i] = total
+		ids[i] = message.ID
+		total += m.lineCounts[message.ID]
+	}
+	m.layoutIDs, m.layoutStarts = ids, starts
+	lo, hi := m.visibleWindow(total)
+
+	var visible []int
+	for i, message := range messages {
+		if contents[i] != nil {
+			continue
+		}
+		end := starts[i] + m.lineCounts[message.ID]
+		if end >= lo && starts[i] <= hi {
+			visible = append(visible, i)
+		}
+	}
+	m.renderMessages(visible, contents)
+	rendered += len(visible)
+	m.renderedWindow = [2]int{lo, hi}
+
+	sb := strings.Builder{}
+	for i, message := range messages {
+		if contents[i] != nil {
+			sb.WriteString(*contents[i])
+			continue
+		}
+		// Off-screen messages are replaced by blank lines of the same height
+		sb.WriteString(strings.Repeat("\n", m.lineCounts[message.ID]))
+	}
+
+	content := sb.String()
+
+	m.viewport.SetHeight(m.height - lipgloss.Height(m.header()) + 1)
+	m.viewport.SetContent("\n" + content)
+}
+
+// renderMessages renders the messages at the given indices in parallel,
+// storing the output in contents and recording each message's line count
+func (m *messagesComponent) renderMessages(indices []int, contents []*string) {
+	if len(indices) == 0 {
+		return
+	}
+	messages := m.app.Messages
+	util.MapReducePar(indices, contents, func(i int) func([]*string) []*string {
+		content, regions := m.renderMessage(messages[i])
+		return func(contents []*string) []*string {
+			contents[i] = &content
+			m.lineCounts[messages[i].ID] = strings.Count(content, "\n")
+			m.toolRegions[messages[i].ID] = regions
+			return contents
+		}
+	})
+}
+
+// visibleWindow returns the range of transcript lines that must be fully
+// rendered: the viewport plus one screen of buffer above and below
+func (m *messagesComponent) visibleWindow(total int) (int, int) {
+	height := max(1, m.viewport.Height())
+	top := m.viewport.YOffset
+	if m.tail {
+		top = max(0, total-height)
+	}
+	return top - height, top + 2*height
+}
+
+// ensureRendered re-renders when scrolling has moved the viewport outside the
+// region that was fully rendered last time
+func (m *messagesComponent) ensureRendered() {
+	top := m.viewport.YOffset
+	bottom := top + m.viewport.Height()
+	if top < m.renderedWindow[0] || bottom > m.renderedWindow[1] {
+		m.renderView()
+	}
+}
+
+// rawTarget returns the message the raw markdown toggle applies to
+func (m *messagesComponent) rawTarget() string {
+	if m.selectedID != "" {
+		return m.selectedID
+	}
+	for i := len(m.app.Messages) - 1; i >= 0; i-- {
+		if m.app.Messages[i].Role == opencode.MessageRoleAssistant {
+			return m.app.Messages[i].ID
+		}
+	}
+	return ""
+}
+
+// resetLayout drops cached renders and message heights, forcing a full render
+func (m *messagesComponent) resetLayout() {
+	m.cache.Clear()
+	m.relayout()
+}
 
+// relayout drops the message heights, forcing every message to be measured
+// again. Renders cached for the current width are reused.
+func (m *messagesComponent) relayout() {
+	clear(m.lineCounts)
+	clear(m.toolRegions)
+	m.focusedTitle = ""
+}
+
+// titleAt returns the full text of a truncated tool title at screen row y
+func (m *messagesComponent) titleAt(y int) string {
+	row := y - lipgloss.Height(m.header())
+	if row < 0 || row >= m.viewport.Height() {
+		return ""
+	}
+	lines := strings.Split(m.viewport.GetContent(), "\n")
+	idx := m.viewport.YOffset + row
+	if idx >= len(lines) {
+		return ""
+	}
+	full, _ := fullTitleAt(lines[idx])
+	return full
+}
+
+// footer shows the full text of the focused tool title
+func (m *messagesComponent) footer() string {
+	t := theme.CurrentTheme()
+	return styles.NewStyle().
+		Foreground(t.Text()).
+		Background(t.BackgroundElement()).
+		Width(m.width).
+		Padding(0, 1).
+		Render(m.focusedTitle)
+}
+
+func (m *messagesComponent) renderMessage(message opencode.Message) (string, []toolRegion) {
 	t := theme.CurrentTheme()
 
 	align := lipgloss.Center
 	width := layout.Current.Container.Width
+	// renders are cached per viewport width
+	cacheWidth := layout.Current.Viewport.Width
+	selected := message.ID == m.selectedID
+	raw := m.rawMessages[message.ID]
+
+	var content string
+	var cached bool
+	blocks := make([]string, 0)
+	var regions []toolRegion
+	// line is the first line of the next block within the message
+	line := 0
+	// blocks are separated by a blank line, except in the compact layout
+	gap := "\n\n"
+	if layout.Current.Density == layout.DensityCompact {
+		gap = "\n"
+	}
+	addBlock := func(block string) {
+		blocks = append(blocks, block)
+		line += strings.Count(block, "\n") + len(gap)
+	}
 
-	sb := strings.Builder{}
-	util.WriteStringsPar(&sb, m.app.Messages, func(message opencode.Message) string {
-		var content string
-		var cached bool
-		blocks := make([]string, 0)
-
-		switch message.Role {
-		case opencode.MessageRoleUser:
-			for _, part := range message.Parts {
-				switch part := part.AsUnion().(type) {
-				case opencode.TextPart:
-					key := m.cache.GenerateKey(message.ID, part.Text, layout.Current.Viewport.Width)
-					content, cached = m.cache.Get(key)
-					if !cached {
+	switch message.Role {
+	case opencode.MessageRoleUser:
+		for _, part := range message.Parts {
+			switch part := part.AsUnion().(type) {
+			case opencode.TextPart:
+				key := m.cache.GenerateKey(message.ID, part.Text, selected)
+				content, cached = m.cache.Get(cacheWidth, key)
+				if !cached {
+					if title, files, ok := app.ParsePatchSummary(part.Text); ok {
+						content = renderPatchResult(title, files, width, align)
+					} else {
 						content = renderText(
 							message,
 							part.Text,
 							m.app.Info.User,
-							m.showToolDetails,
+							selected,
+							false,
 							width,
 							align,
 						)
-						m.cache.Set(key, content)
-					}
-					if content != "" {
-						blocks = append(blocks, content)
 					}
+					m.cache.Set(cacheWidth, key, content)
+				}
+				if content != "" {
+					addBlock(content)
 				}
 			}
+		}
 
-		case opencode.MessageRoleAssistant:
-			for i, p := range message.Parts {
-				switch part := p.AsUnion().(type) {
-				case opencode.TextPart:
-					finished := message.Metadata.Time.Completed > 0
-					remainingParts := message.Parts[i+1:]
-					toolCallParts := make([]opencode.ToolInvocationPart, 0)
-					for _, part := range remainingParts {
-						switch part := part.AsUnion().(type) {
-						case opencode.TextPart:
-							// we only want tool calls associated with the current text part.
-							// if we hit another text part, we're done.
-							break
-						case opencode.ToolInvocationPart:
-							toolCallParts = append(toolCallParts, part)
-							if part.ToolInvocation.State != "result" {
-								// i don't think there's a case where a tool call isn't in result state
-								// and the message time is 0, but just in case
-								finished = false
-							}
+	case opencode.MessageRoleAssistant:
+		// a report scans the session, so only look for one for a summary
+		if message.Metadata.Assistant.Summary {
+			if report, ok := m.app.CompactionReport(message.ID); ok {
+				addBlock(renderCompactionMarker(report, width, align))
+			}
+		}
+		for i, p := range message.Parts {
+			switch part := p.AsUnion().(type) {
+			case opencode.TextPart:
+				finished := message.Metadata.Time.Completed > 0
+				remainingParts := message.Parts[i+1:]
+				toolCallParts := make([]opencode.ToolInvocationPart, 0)
+				collapsed := make([]string, 0)
+				for _, part := range remainingParts {
+					switch part := part.AsUnion().(type) {
+					case opencode.TextPart:
+						// we only want tool calls associated with the current text part.
+						// if we hit another text part, we're done.
+						break
+					case opencode.ToolInvocationPart:
+						if part.ToolInvocation.State != "result" {
+							// i don't think there's a case where a tool call isn't in result state
+							// and the message time is 0, but just in case
+							finished = false
 						}
+						// tools showing their details get a block of their own
+						if m.toolDetailsVisible(part.ToolInvocation.ToolCallID) {
+							continue
+						}
+						toolCallParts = append(toolCallParts, part)
+						collapsed = append(collapsed, part.ToolInvocation.ToolCallID)
 					}
+				}
 
-					if finished {
-						key := m.cache.GenerateKey(message.ID, p.Text, layout.Current.Viewport.Width, m.showToolDetails)
-						content, cached = m.cache.Get(key)
-						if !cached {
-							content = renderText(
-								message,
-								p.Text,
-								message.Metadata.Assistant.ModelID,
-								m.showToolDetails,
-								width,
-								align,
-								toolCallParts...,
-							)
-							m.cache.Set(key, content)
-						}
-					} else {
+				if finished {
+					key := m.cache.GenerateKey(message.ID, p.Text, selected, raw, strings.Join(collapsed, ","))
+					content, cached = m.cache.Get(cacheWidth, key)
+					if !cached {
 						content = renderText(
 							message,
 							p.Text,
 							message.Metadata.Assistant.ModelID,
-							m.showToolDetails,
+							selected,
+							raw,
 							width,
 							align,
 							toolCallParts...,
 						)
+						m.cache.Set(cacheWidth, key, content)
 					}
-					if content != "" {
-						blocks = append(blocks, content)
+				} else {
+					content = renderText(
+						message,
+						p.Text,
+						message.Metadata.Assistant.ModelID,
+						selected,
+						raw,
+						width,
+						align,
+						toolCallParts...,
+					)
+				}
+				if content != "" {
+					regions = append(regions, titleRegions(content, line, collapsed)...)
+					addBlock(content)
+				}
+				if m.app.State.FoldAgentSummaries {
+					for _, toolCall := range toolCallParts {
+						if toolCall.ToolInvocation.ToolName != "task" || toolCall.ToolInvocation.State != "result" {
+							continue
+						}
+						metadata := message.Metadata.Tool[toolCall.ToolInvocation.ToolCallID]
+						if failed, _ := metadata.ExtraFields["error"].(bool); failed {
+							continue
+						}
+						addBlock(renderAgentSummary(toolCall, message.Metadata, width, align))
 					}
-				case opencode.ToolInvocationPart:
-					if !m.showToolDetails {
-						continue
+				}
+				if finished {
+					if patch := app.MultiFilePatch(p.Text); patch != "" {
+						addBlock(renderPatchHint(app.PatchFileCount(patch), width, align))
 					}
-
-					if part.ToolInvocation.State == "result" {
-						key := m.cache.GenerateKey(message.ID,
-							part.ToolInvocation.ToolCallID,
-							m.showToolDetails,
-							layout.Current.Viewport.Width,
-						)
-						content, cached = m.cache.Get(key)
-						if !cached {
-							content = renderToolDetails(
-								part,
-								message.Metadata,
-								width,
-								align,
-							)
-							m.cache.Set(key, content)
+					for _, url := range image.ExtractImageURLs(p.Text) {
+						if preview, ok := m.app.ImagePreviews.Preview(url); ok {
+							addBlock(renderImagePreview(preview, url, width, align))
 						}
-					} else {
-						// if the tool call isn't finished, don't cache
+					}
+				}
+			case opencode.ToolInvocationPart:
+				id := part.ToolInvocation.ToolCallID
+				if !m.toolDetailsVisible(id) {
+					continue
+				}
+
+				if part.ToolInvocation.State == "result" {
+					key := m.cache.GenerateKey(message.ID,
+						id,
+						true,
+					)
+					content, cached = m.cache.Get(cacheWidth, key)
+					if !cached {
 						content = renderToolDetails(
 							part,
 							message.Metadata,
 							width,
 							align,
 						)
+						m.cache.Set(cacheWidth, key, content)
 					}
+				} else {
+					// if the tool call isn't finished, don't cache
+					content = renderToolDetails(
+						part,
+						message.Metadata,
+						width,
+						align,
+					)
 					if content != "" {
-						blocks = append(blocks, content)
+						content += "\n" + m.toolTimer(id)
 					}
 				}
+				if content != "" {
+					end := line + strings.Count(content, "\n") + 1
+					if split, ok := diffStart(part, message.Metadata, width, align); ok {
+						// the title still toggles, the diff below it opens the viewer
+						regions = append(regions,
+							toolRegion{toolCallID: id, start: line, end: line + split},
+							toolRegion{toolCallID: id, start: line + split, end: end, diff: true},
+						)
+					} else {
+						regions = append(regions, toolRegion{toolCallID: id, start: line, end: end})
+					}
+					addBlock(content)
+				}
 			}
 		}
+	}
 
-		error := ""
-		switch err := message.Metadata.Error.AsUnion().(type) {
-		case nil:
-		case opencode.MessageMetadataErrorMessageOutputLengthError:
-			error = "Message output length exceeded"
-		case opencode.ProviderAuthError:
-			error = err.Data.Message
-		case opencode.UnknownError:
-			error = err.Data.Message
-		}
-
-		if error != "" {
-			error = renderContentBlock(
-				error,
-				width,
-				align,
-				WithBorderColor(t.Error()),
-			)
-			blocks = append(blocks, error)
-		}
-
-		return strings.Join(blocks, "\n\n")
-	})
+	error := ""
+	switch err := message.Metadata.Error.AsUnion().(type) {
+	case nil:
+	case opencode.MessageMetadataErrorMessageOutputLengthError:
+		error = "Message output length exceeded"
+	case opencode.ProviderAuthError:
+		error = err.Data.Message
+	case opencode.UnknownError:
+		error = err.Data.Message
+	}
 
-	content := sb.String()
+	if error != "" {
+		error = renderContentBlock(
+			error,
+			width,
+			align,
+			WithBorderColor(t.Error()),
+		)
+		addBlock(error)
+	}
 
-	m.viewport.SetHeight(m.height - lipgloss.Height(m.header()) + 1)
-	m.viewport.SetContent("\n" + content)
+	return strings.Join(blocks, gap), regions
 }
 
 func (m *messagesComponent) header() string {
@@ -272,6 +638,9 @@ func (m *messagesComponent) header() string {
 	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).Render
 	headerLines := []string{}
 	headerLines = append(headerLines, toMarkdown("# "+m.app.Session.Title, width-6, t.Background()))
+	if description := m.app.SessionDescription(m.app.Session); description != "" {
+		headerLines = append(headerLines, base(description))
+	}
 	if m.app.Session.Share.URL != "" {
 		headerLines = append(headerLines, muted(m.app.Session.Share.URL))
 	} else {
@@ -282,13 +651,13 @@ func (m *messagesComponent) header() string {
 	header = styles.NewStyle().
 		Background(t.Background()).
 		Width(width).
-		PaddingLeft(2).
-		PaddingRight(2).
+		PaddingLeft(layout.Scale(2)).
+		PaddingRight(layout.Scale(2)).
 		BorderLeft(true).
 		BorderRight(true).
 		BorderBackground(t.Background()).
 		BorderForeground(t.BackgroundElement()).
-		BorderStyle(lipgloss.ThickBorder()).
+		BorderStyle(styles.BlockBorder()).
 		Render(header)
 
 	return "\n" + header + "\n"
@@ -312,18 +681,29 @@ func (m *messagesComponent) View() string {
 		m.header(),
 		styles.WhitespaceStyle(t.Background()),
 	)
+	view := header + "\n" + m.viewport.View()
+	if m.focusedTitle != "" {
+		// the footer covers the bottom of the viewport
+		lines := strings.Split(view, "\n")
+		footer := strings.Split(m.footer(), "\n")
+		if len(lines) > len(footer) {
+			view = strings.Join(append(lines[:len(lines)-len(footer)], footer...), "\n")
+		}
+	}
 	return styles.NewStyle().
 		Background(t.Background()).
-		Render(header + "\n" + m.viewport.View())
+		Render(view)
 }
 
+// SetSize lays the transcript out again for a new terminal size. It also
+// runs when the size did not change, since a redraw is requested that way
+// after switching screens. The message at the top of the view stays there.
 func (m *messagesComponent) SetSize(width, height int) tea.Cmd {
-	if m.width == width && m.height == height {
-		return nil
-	}
-	// Clear cache on resize since width affects rendering
+	anchor, anchored := m.anchor()
+	// every render wraps to the width, so the heights change. The renders
+	// are cached per width, going back to an earlier width reuses them.
 	if m.width != width {
-		m.cache.Clear()
+		m.relayout()
 	}
 	m.width = width
 	m.height = height
@@ -332,6 +712,12 @@ func (m *messagesComponent) SetSize(width, height int) tea.Cmd {
 	m.attachments.SetWidth(width + 40)
 	m.attachments.SetHeight(3)
 	m.renderView()
+	switch {
+	case m.tail:
+		m.viewport.GotoBottom()
+	case anchored:
+		m.scrollTo(anchor)
+	}
 	return nil
 }
 
@@ -349,33 +735,45 @@ func (m *messagesComponent) Reload() tea.Cmd {
 
 func (m *messagesComponent) PageUp() (tea.Model, tea.Cmd) {
 	m.viewport.ViewUp()
+	m.ensureRendered()
+	m.rememberAnchor()
 	return m, nil
 }
 
 func (m *messagesComponent) PageDown() (tea.Model, tea.Cmd) {
 	m.viewport.ViewDown()
+	m.ensureRendered()
+	m.rememberAnchor()
 	return m, nil
 }
 
 func (m *messagesComponent) HalfPageUp() (tea.Model, tea.Cmd) {
 	m.viewport.HalfViewUp()
+	m.ensureRendered()
+	m.rememberAnchor()
 	return m, nil
 }
 
 func (m *messagesComponent) HalfPageDown() (tea.Model, tea.Cmd) {
 	m.viewport.HalfViewDown()
+	m.ensureRendered()
+	m.rememberAnchor()
 	return m, nil
 }
 
 func (m *messagesComponent) First() (tea.Model, tea.Cmd) {
 	m.viewport.GotoTop()
 	m.tail = false
+	m.ensureRendered()
+	m.rememberAnchor()
 	return m, nil
 }
 
 func (m *messagesComponent) Last() (tea.Model, tea.Cmd) {
 	m.viewport.GotoBottom()
 	m.tail = true
+	m.ensureRendered()
+	m.rememberAnchor()
 	return m, nil
 }
 
@@ -383,6 +781,36 @@ func (m *messagesComponent) ToolDetailsVisible() bool {
 	return m.showToolDetails
 }
 
+// toolTimer shows how long an unfinished tool call has been running, and
+// how to interrupt it once it may be stuck
+func (m *messagesComponent) toolTimer(callID string) string {
+	t := theme.CurrentTheme()
+	style := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).PaddingLeft(2)
+	tool, ok := m.app.ToolTimers.Get(callID)
+	if !ok {
+		return style.Render("running")
+	}
+	elapsed := tool.Elapsed().Round(time.Second)
+	if elapsed < m.app.ToolStuckAfter() {
+		return style.Render(fmt.Sprintf("running %s", elapsed))
+	}
+	text := fmt.Sprintf("running %s · possibly stuck", elapsed)
+	if key := m.app.CommandKey(commands.SessionInterruptCommand); key != "" {
+		text += fmt.Sprintf(" · %s to interrupt", key)
+	}
+	return style.Foreground(t.Warning()).Render(text)
+}
+
+func (m *messagesComponent) Following() bool {
+	return m.tail
+}
+
+func (m *messagesComponent) RenderStats() RenderStats {
+	stats := m.stats
+	stats.Cache = m.cache.Stats()
+	return stats
+}
+
 func NewMessagesComponent(app *app.App) MessagesComponent {
 	vp := viewport.New()
 	attachments := viewport.New()
@@ -395,5 +823,10 @@ func NewMessagesComponent(app *app.App) MessagesComponent {
 		showToolDetails: true,
 		cache:           NewMessageCache(),
 		tail:            true,
+		lineCounts:      make(map[string]int),
+		anchors:         make(map[string]scrollAnchor),
+		toggledTools:    make(map[string]bool),
+		rawMessages:     make(map[string]bool),
+		toolRegions:     make(map[string][]toolRegion),
 	}
 }
@@ -0,0 +1,176 @@
+package chat
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sst/dgmo/internal/browser"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/styles/glyphs"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// toolRegion is the range of lines, relative to the start of its message,
+// that toggles the details of a tool call when clicked. Clicking the diff of
+// an edit opens it in the diff viewer instead.
+type toolRegion struct {
+	toolCallID string
+	start, end int
+	diff       bool
+}
+
+// EditClickedMsg is sent when the diff of an edit tool call is clicked
+type EditClickedMsg struct {
+	MessageID  string
+	ToolCallID string
+}
+
+// linkPattern matches http and https links in rendered text
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// toolDetailsVisible reports whether a tool call is rendered with its
+// details, which clicking the tool flips from the global setting
+func (m *messagesComponent) toolDetailsVisible(toolCallID string) bool {
+	return m.showToolDetails != m.toggledTools[toolCallID]
+}
+
+func (m *messagesComponent) SelectedMessage() string {
+	return m.selectedID
+}
+
+// lineAt returns the transcript line shown at screen row y
+func (m *messagesComponent) lineAt(y int) (int, bool) {
+	row := y - lipgloss.Height(m.header())
+	if row < 0 || row >= m.viewport.Height() {
+		return 0, false
+	}
+	return m.viewport.YOffset + row, true
+}
+
+// messageAt returns the message covering a transcript line and the line
+// within that message
+func (m *messagesComponent) messageAt(line int) (string, int, bool) {
+	for i, id := range m.layoutIDs {
+		start := m.layoutStarts[i]
+		if line >= start && line <= start+m.lineCounts[id] {
+			return id, line - start, true
+		}
+	}
+	return "", 0, false
+}
+
+// handleClick opens a link under the pointer, opens the diff of a clicked
+// edit or toggles the details of a clicked tool call, or selects the clicked
+// message, in that order
+func (m *messagesComponent) handleClick(msg tea.MouseClickMsg) tea.Cmd {
+	if msg.Button != tea.MouseLeft {
+		return nil
+	}
+	line, ok := m.lineAt(msg.Y)
+	if !ok {
+		return nil
+	}
+	lines := strings.Split(m.viewport.GetContent(), "\n")
+	if line < len(lines) {
+		if url := linkAt(lines[line], msg.X); url != "" {
+			return func() tea.Msg {
+				if err := browser.Open(url); err != nil {
+					return toast.NewErrorToast(err.Error())()
+				}
+				return nil
+			}
+		}
+	}
+
+	id, offset, ok := m.messageAt(line)
+	if !ok {
+		return nil
+	}
+	for _, region := range m.toolRegions[id] {
+		if offset >= region.start && offset < region.end {
+			if region.diff {
+				return util.CmdHandler(EditClickedMsg{MessageID: id, ToolCallID: region.toolCallID})
+			}
+			m.toggledTools[region.toolCallID] = !m.toggledTools[region.toolCallID]
+			m.rerender(id)
+			return nil
+		}
+	}
+
+	previous := m.selectedID
+	if previous == id {
+		m.selectedID = ""
+	} else {
+		m.selectedID = id
+	}
+	m.rerender(id, previous)
+	return nil
+}
+
+// rerender renders messages again after their appearance changed, keeping
+// the viewport where it was
+func (m *messagesComponent) rerender(ids ...string) {
+	for _, id := range ids {
+		delete(m.lineCounts, id)
+	}
+	anchor, ok := m.anchor()
+	m.renderView()
+	if m.tail {
+		m.viewport.GotoBottom()
+		return
+	}
+	if ok {
+		m.scrollTo(anchor)
+	}
+}
+
+// linkAt returns the link shown at column x of a rendered line
+func linkAt(line string, x int) string {
+	plain := ansi.Strip(line)
+	for _, match := range linkPattern.FindAllStringIndex(plain, -1) {
+		url := strings.TrimRight(plain[match[0]:match[1]], ".,;:!?)]}")
+		start := ansi.StringWidth(plain[:match[0]])
+		if x >= start && x < start+ansi.StringWidth(url) {
+			return url
+		}
+	}
+	return ""
+}
+
+// titleRegions locates the collapsed tool titles listed at the end of a text
+// block. Each title starts with the branch glyph, or a box corner for tasks,
+// and runs until the next title; the last one ends before the author line.
+func titleRegions(block string, offset int, toolCallIDs []string) []toolRegion {
+	if len(toolCallIDs) == 0 {
+		return nil
+	}
+	g := glyphs.Current()
+	lines := strings.Split(block, "\n")
+	var starts []int
+	for i, line := range lines {
+		inner := strings.TrimLeft(ansi.Strip(line), " "+styles.BlockBorder().Left)
+		if strings.HasPrefix(inner, g.Branch) || strings.HasPrefix(inner, g.TopLeft) {
+			starts = append(starts, i)
+		}
+	}
+	if len(starts) < len(toolCallIDs) {
+		return nil
+	}
+	// the titles follow the text, so only the last matches are titles
+	starts = starts[len(starts)-len(toolCallIDs):]
+	// the block ends with the author line and the bottom padding
+	last := max(starts[len(starts)-1]+1, len(lines)-3)
+	regions := make([]toolRegion, len(toolCallIDs))
+	for i, id := range toolCallIDs {
+		end := last
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		regions[i] = toolRegion{toolCallID: id, start: offset + starts[i], end: offset + end}
+	}
+	return regions
+}
@@ -0,0 +1,118 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// parsePartialArgs parses the arguments of a tool call while they stream in,
+// JSON text cut off anywhere. A string value cut off is kept as far as it
+// arrived, a key or a literal cut off is dropped with what follows it.
+func parsePartialArgs(text string) map[string]any {
+	type cut struct {
+		end     int
+		closers string
+	}
+	var closers []byte
+	var cuts []cut
+	mark := func(end int) {
+		cuts = append(cuts, cut{end: end, closers: string(closers)})
+	}
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				mark(i + 1)
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			closers = append(closers, '}')
+			mark(i + 1)
+		case '[':
+			closers = append(closers, ']')
+			mark(i + 1)
+		case '}', ']':
+			if len(closers) > 0 {
+				closers = closers[:len(closers)-1]
+			}
+			mark(i + 1)
+		case ' ', '\t', '\n', '\r', ',', ':':
+		default:
+			mark(i + 1)
+		}
+	}
+
+	parse := func(prefix, closers string) map[string]any {
+		var args map[string]any
+		var b strings.Builder
+		b.WriteString(prefix)
+		for i := len(closers) - 1; i >= 0; i-- {
+			b.WriteByte(closers[i])
+		}
+		if json.Unmarshal([]byte(b.String()), &args) != nil {
+			return nil
+		}
+		return args
+	}
+
+	if inString {
+		prefix := text
+		if escaped {
+			prefix = prefix[:len(prefix)-1]
+		}
+		if args := parse(prefix+`"`, string(closers)); args != nil {
+			return args
+		}
+	} else if args := parse(text, string(closers)); args != nil {
+		return args
+	}
+	for i := len(cuts) - 1; i >= 0; i-- {
+		if args := parse(text[:cuts[i].end], cuts[i].closers); args != nil {
+			return args
+		}
+	}
+	return nil
+}
+
+// partialToolDetail returns what is known of the target of a tool call
+// while its arguments stream in, the file, command or pattern it works on
+func partialToolDetail(name string, args map[string]any) string {
+	keys := []string{}
+	switch name {
+	case "read", "edit", "write", "patch":
+		keys = []string{"filePath"}
+	case "bash":
+		keys = []string{"description", "command"}
+	case "glob", "grep":
+		keys = []string{"pattern"}
+	case "list":
+		keys = []string{"path"}
+	case "webfetch":
+		keys = []string{"url"}
+	case "task":
+		keys = []string{"description"}
+	}
+	for _, key := range keys {
+		value, ok := args[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if key == "filePath" || key == "path" {
+			return relative(value)
+		}
+		line, _, _ := strings.Cut(value, "\n")
+		return line
+	}
+	return ""
+}
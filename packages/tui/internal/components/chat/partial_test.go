@@ -0,0 +1,45 @@
+package chat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePartialArgs(t *testing.T) {
+	tests := []struct {
+		text string
+		want map[string]any
+	}{
+		{``, nil},
+		{`{`, map[string]any{}},
+		{`{"file`, map[string]any{}},
+		{`{"filePath"`, map[string]any{}},
+		{`{"filePath": "/src/ma`, map[string]any{"filePath": "/src/ma"}},
+		{`{"filePath": "C:\\src\`, map[string]any{"filePath": `C:\src`}},
+		{`{"filePath": "/src/main.go", "off`, map[string]any{"filePath": "/src/main.go"}},
+		{`{"filePath": "/src/main.go", "offset": 1`, map[string]any{"filePath": "/src/main.go", "offset": float64(1)}},
+		{`{"command": "ls", "wait": tr`, map[string]any{"command": "ls"}},
+		{`{"todos": [{"id": "1", "content": "wr`, map[string]any{"todos": []any{map[string]any{"id": "1", "content": "wr"}}}},
+		{`{"pattern": "\u00`, map[string]any{}},
+		{`{"url": "https://example.com"}`, map[string]any{"url": "https://example.com"}},
+	}
+	for _, tt := range tests {
+		if got := parsePartialArgs(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePartialArgs(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPartialToolDetail(t *testing.T) {
+	args := parsePartialArgs(`{"command": "go test ./...\ngo vet`)
+	if detail := partialToolDetail("bash", args); detail != "go test ./..." {
+		t.Errorf("bash detail = %q, want the first line of the command", detail)
+	}
+	args = parsePartialArgs(`{"command": "make", "description": "Builds`)
+	if detail := partialToolDetail("bash", args); detail != "Builds" {
+		t.Errorf("bash detail = %q, want the description", detail)
+	}
+	if detail := partialToolDetail("grep", parsePartialArgs(`{"path": "src"`)); detail != "" {
+		t.Errorf("grep detail = %q, want nothing before the pattern", detail)
+	}
+}
@@ -0,0 +1,172 @@
+package chat
+
+import (
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+const (
+	// defaultScrollStep is the number of lines a single wheel event scrolls
+	defaultScrollStep = 3
+	// momentumFrame is the interval between momentum steps
+	momentumFrame = 33 * time.Millisecond
+	// momentumDecay is the share of velocity kept after each frame
+	momentumDecay = 0.75
+	// momentumMaxVelocity caps the lines scrolled per frame
+	momentumMaxVelocity = 40.0
+)
+
+type scrollMomentumMsg struct{}
+
+// scrollAnchor pins a scroll position to a line within a message, so it
+// survives re-layout and session switches
+type scrollAnchor struct {
+	messageID string
+	offset    int
+}
+
+func momentumTick() tea.Cmd {
+	return tea.Tick(momentumFrame, func(time.Time) tea.Msg {
+		return scrollMomentumMsg{}
+	})
+}
+
+func (m *messagesComponent) scrollStep() int {
+	if m.app.State.ScrollStep > 0 {
+		return m.app.State.ScrollStep
+	}
+	return defaultScrollStep
+}
+
+// handleWheel scrolls one step right away and, with momentum enabled, adds
+// the step to the velocity that keeps scrolling after the wheel stops
+func (m *messagesComponent) handleWheel(msg tea.MouseWheelMsg) tea.Cmd {
+	var direction float64
+	switch msg.Button {
+	case tea.MouseWheelUp:
+		direction = -1
+	case tea.MouseWheelDown:
+		direction = 1
+	default:
+		return nil
+	}
+
+	step := float64(m.scrollStep())
+	m.scrollBy(int(direction * step))
+	if !m.app.State.ScrollMomentum {
+		return nil
+	}
+
+	// reversing direction cancels the momentum built up so far
+	if m.velocity*direction < 0 {
+		m.velocity, m.remainder = 0, 0
+	}
+	m.velocity = math.Max(-momentumMaxVelocity, math.Min(momentumMaxVelocity, m.velocity+direction*step/2))
+	if m.momentumActive {
+		return nil
+	}
+	m.momentumActive = true
+	return momentumTick()
+}
+
+// stepMomentum applies one frame of momentum. Ticks stop as soon as the
+// velocity has decayed or the viewport hits an edge, so an idle transcript
+// causes no redraws.
+func (m *messagesComponent) stepMomentum() tea.Cmd {
+	m.velocity *= momentumDecay
+	if math.Abs(m.velocity) < 0.5 {
+		m.stopMomentum()
+		return nil
+	}
+
+	m.remainder += m.velocity
+	lines := int(m.remainder)
+	m.remainder -= float64(lines)
+	before := m.viewport.YOffset
+	m.scrollBy(lines)
+	if lines != 0 && m.viewport.YOffset == before {
+		m.stopMomentum()
+		return nil
+	}
+	return momentumTick()
+}
+
+func (m *messagesComponent) stopMomentum() {
+	m.velocity, m.remainder = 0, 0
+	m.momentumActive = false
+}
+
+// scrollBy moves the viewport by lines, negative values scroll up
+func (m *messagesComponent) scrollBy(lines int) {
+	switch {
+	case lines < 0:
+		m.viewport.LineUp(-lines)
+	case lines > 0:
+		m.viewport.LineDown(lines)
+	}
+	m.tail = m.viewport.AtBottom()
+	m.ensureRendered()
+	m.rememberAnchor()
+}
+
+// anchor returns the message at the top of the viewport and how far into
+// it the viewport is scrolled
+func (m *messagesComponent) anchor() (scrollAnchor, bool) {
+	top := m.viewport.YOffset
+	for i, id := range m.layoutIDs {
+		if top < m.layoutStarts[i]+m.lineCounts[id] {
+			return scrollAnchor{messageID: id, offset: max(0, top-m.layoutStarts[i])}, true
+		}
+	}
+	return scrollAnchor{}, false
+}
+
+func (m *messagesComponent) ScrollPosition() (string, int, bool) {
+	anchor, ok := m.anchor()
+	return anchor.messageID, anchor.offset, ok
+}
+
+// rememberAnchor stores the scroll position of the current session. Sessions
+// that follow the tail have no anchor.
+func (m *messagesComponent) rememberAnchor() {
+	if m.app.Session == nil || m.app.Session.ID == "" {
+		return
+	}
+	if m.tail {
+		delete(m.anchors, m.app.Session.ID)
+		return
+	}
+	if anchor, ok := m.anchor(); ok {
+		m.anchors[m.app.Session.ID] = anchor
+	}
+}
+
+// restoreAnchor scrolls back to the remembered position of the current
+// session, returning false if there is none or its message is gone
+func (m *messagesComponent) restoreAnchor() bool {
+	if m.app.Session == nil {
+		return false
+	}
+	anchor, ok := m.anchors[m.app.Session.ID]
+	if !ok {
+		return false
+	}
+	return m.scrollTo(anchor)
+}
+
+// scrollTo puts the anchored line at the top of the view. The offset is
+// kept within the message, which may have fewer lines after a resize.
+func (m *messagesComponent) scrollTo(anchor scrollAnchor) bool {
+	for i, id := range m.layoutIDs {
+		if id == anchor.messageID {
+			offset := min(anchor.offset, max(0, m.lineCounts[id]-1))
+			m.viewport.SetYOffset(m.layoutStarts[i] + offset)
+			m.tail = m.viewport.AtBottom()
+			m.ensureRendered()
+			return true
+		}
+	}
+	return false
+}
@@ -7,30 +7,21 @@ import (
 
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/styles/glyphs"
 	"github.com/sst/dgmo/internal/theme"
 )
 
-// Spinner frames for animated display
-var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-
-// Different spinner styles based on elapsed time
-var spinnerEvolutions = []struct {
-	afterSeconds int
-	frames       []string
-}{
-	{0, []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}},                      // Dots
-	{10, []string{"◐", "◓", "◑", "◒"}},                                                   // Circle quarters
-	{20, []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█", "▇", "▆", "▅", "▄", "▃", "▂"}}, // Blocks
-	{30, []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}},                               // Complex dots
-	{45, []string{"◴", "◷", "◶", "◵"}},                                                   // Diamonds
-	{60, []string{"⊙", "⊗", "⊕", "⊗"}},                                                   // Pulsing
-}
+// Seconds after which the spinner of a running task changes to the next
+// style of the glyph set, the last style stays
+var spinnerStages = []int{0, 10, 20, 30, 45, 60}
 
 // GetSpinnerFrame returns the appropriate spinner frame based on time
 func GetSpinnerFrame() string {
 	// Use current time to determine which frame to show
-	frame := int(time.Now().UnixMilli()/100) % len(spinnerFrames)
-	return spinnerFrames[frame]
+	frames := glyphs.Current().Spinner
+	frame := int(time.Now().UnixMilli()/100) % len(frames)
+	return frames[frame]
 }
 
 // GetEvolvingSpinner returns a spinner that changes style based on elapsed time
@@ -38,13 +29,15 @@ func GetEvolvingSpinner(elapsed time.Duration) string {
 	seconds := int(elapsed.Seconds())
 
 	// Find the appropriate spinner style based on elapsed time
-	var frames []string
-	for i := len(spinnerEvolutions) - 1; i >= 0; i-- {
-		if seconds >= spinnerEvolutions[i].afterSeconds {
-			frames = spinnerEvolutions[i].frames
+	stage := 0
+	for i := len(spinnerStages) - 1; i >= 0; i-- {
+		if seconds >= spinnerStages[i] {
+			stage = i
 			break
 		}
 	}
+	spinners := glyphs.Current().Spinners
+	frames := spinners[min(stage, len(spinners)-1)]
 
 	// Calculate frame based on time
 	frame := int(time.Now().UnixMilli()/100) % len(frames)
@@ -168,6 +161,7 @@ func RenderTaskProgress(progress int, width int) string {
 	empty := barWidth - filled
 
 	t := theme.CurrentTheme()
+	g := glyphs.Current()
 
 	// Create smooth gradient effect
 	var bar strings.Builder
@@ -188,16 +182,16 @@ func RenderTaskProgress(progress int, width int) string {
 	for i := 0; i < filled; i++ {
 		// Add slight variation in the middle for depth
 		if i > filled/3 && i < 2*filled/3 && progress > 30 && progress < 70 {
-			bar.WriteString(lipgloss.NewStyle().Foreground(t.Secondary()).Render("█"))
+			bar.WriteString(lipgloss.NewStyle().Foreground(t.Secondary()).Render(g.BarFull))
 		} else {
-			bar.WriteString(fillStyle.Render("█"))
+			bar.WriteString(fillStyle.Render(g.BarFull))
 		}
 	}
 
 	// Empty part with subtle dots
 	emptyStyle := lipgloss.NewStyle().Foreground(t.TextMuted())
 	for i := 0; i < empty; i++ {
-		bar.WriteString(emptyStyle.Render("░"))
+		bar.WriteString(emptyStyle.Render(g.BarEmpty))
 	}
 
 	bar.WriteString("]")
@@ -218,7 +212,11 @@ func RenderTaskProgress(progress int, width int) string {
 
 // RenderTaskStatus renders a beautiful task status line
 func RenderTaskStatus(icon string, agentNum string, description string, status string, progress int) string {
+	if styles.Accessible() {
+		return fmt.Sprintf("%s %s: %s", taskStatusLabel(status), agentNum, description)
+	}
 	t := theme.CurrentTheme()
+	g := glyphs.Current()
 
 	// Styles
 	iconStyle := lipgloss.NewStyle().Bold(true)
@@ -238,13 +236,13 @@ func RenderTaskStatus(icon string, agentNum string, description string, status s
 		}
 	case "completed":
 		successStyle := lipgloss.NewStyle().Foreground(t.Success()).Bold(true)
-		statusPart = " " + successStyle.Render("✓ Completed")
+		statusPart = " " + successStyle.Render(g.Done+" Completed")
 	case "failed":
 		errorStyle := lipgloss.NewStyle().Foreground(t.Error()).Bold(true)
-		statusPart = " " + errorStyle.Render("✗ Failed")
+		statusPart = " " + errorStyle.Render(g.Failed+" Failed")
 	default:
 		pendingStyle := lipgloss.NewStyle().Foreground(t.TextMuted())
-		statusPart = " " + pendingStyle.Render("○ Pending")
+		statusPart = " " + pendingStyle.Render(g.Pending+" Pending")
 	}
 
 	return fmt.Sprintf("%s %s %s%s",
@@ -273,16 +271,6 @@ func RenderElapsedTime(duration time.Duration) string {
 	}
 }
 
-// Box drawing characters for custom borders
-const (
-	TopLeft     = "╭"
-	TopRight    = "╮"
-	BottomLeft  = "╰"
-	BottomRight = "╯"
-	Horizontal  = "─"
-	Vertical    = "│"
-)
-
 // RenderTaskBox renders a task in a beautiful box with custom borders
 func RenderTaskBox(icon string, taskName string, description string, status string, progress int, duration time.Duration, width int) string {
 	return RenderTaskBoxWithTool(icon, taskName, description, status, progress, duration, width, "")
@@ -290,6 +278,13 @@ func RenderTaskBox(icon string, taskName string, description string, status stri
 
 // RenderTaskBoxWithTool renders a task with dynamic status based on current tool
 func RenderTaskBoxWithTool(icon string, taskName string, description string, status string, progress int, duration time.Duration, width int, currentTool string) string {
+	return RenderTaskBoxWithProgress(icon, taskName, description, status, progress, duration, width, currentTool, "")
+}
+
+// RenderTaskBoxWithProgress renders a task with a progress bar while it
+// runs. The last progress message reported by the task replaces the dynamic
+// status when there is one.
+func RenderTaskBoxWithProgress(icon string, taskName string, description string, status string, progress int, duration time.Duration, width int, currentTool string, message string) string {
 	t := theme.CurrentTheme()
 
 	// Ensure minimum width
@@ -308,30 +303,39 @@ func RenderTaskBoxWithTool(icon string, taskName string, description string, sta
 		}
 	}
 
+	if styles.Accessible() {
+		return renderTaskPlain(agentNum, taskDesc, status, progress, duration, width, currentTool, message)
+	}
+
+	g := glyphs.Current()
+
 	// Build the header line
 	headerContent := fmt.Sprintf(" %s: %s ", agentNum, taskDesc)
-	headerWidth := len(headerContent)
-	remainingWidth := width - headerWidth - 2 // -2 for corners
+	remainingWidth := width - lipgloss.Width(headerContent) - 4 // corners and the rule around the title
 	if remainingWidth < 0 {
-		// Truncate if too long
-		maxLen := width - 10 // Leave space for corners and padding
-		if maxLen > 0 {
-			headerContent = headerContent[:maxLen] + "..."
-		}
-		remainingWidth = 0
+		// Shorten the description, keeping the agent label intact
+		descWidth := lipgloss.Width(taskDesc) + remainingWidth
+		headerContent = fmt.Sprintf(" %s: %s ", agentNum, truncateTitle(taskDesc, descWidth))
+		remainingWidth = max(0, width-lipgloss.Width(headerContent)-4)
 	}
 
 	headerStyle := lipgloss.NewStyle().Foreground(t.Primary()).Bold(true)
-	header := TopLeft + Horizontal + headerStyle.Render(headerContent) + " " + strings.Repeat(Horizontal, remainingWidth) + TopRight
+	header := g.TopLeft + g.Horizontal + headerStyle.Render(headerContent) + " " + strings.Repeat(g.Horizontal, remainingWidth) + g.TopRight
 
 	// Build the content lines
 	var lines []string
 	lines = append(lines, header)
 
+	// Content lines are padded to the full width, so the right border lines
+	// up whatever the width of the glyphs
+	contentPadding := "   " // 3 spaces for inner padding
+	boxLine := func(content string) string {
+		padding := width - lipgloss.Width(content) - len(contentPadding) - 2
+		return g.Vertical + contentPadding + content + strings.Repeat(" ", max(1, padding)) + g.Vertical
+	}
+
 	// Status line with spinner/progress
 	var statusLine string
-	contentPadding := "  " // 2 spaces for inner padding
-
 	switch status {
 	case "running":
 		// Use evolving spinner based on elapsed time
@@ -340,58 +344,40 @@ func RenderTaskBoxWithTool(icon string, taskName string, description string, sta
 
 		// Get dynamic status message
 		statusMsg := GetDynamicStatus(currentTool, duration)
+		if message != "" {
+			statusMsg = truncateTitle(strings.ReplaceAll(message, "\n", " "), max(10, width-8-lipgloss.Width(spinner)))
+		}
 		statusText := lipgloss.NewStyle().Foreground(t.Secondary()).Italic(true).Render(statusMsg)
 
-		statusLine = fmt.Sprintf("%s %s%s %s",
-			Vertical,
-			contentPadding,
-			spinnerStyle.Render(spinner),
-			statusText)
+		statusLine = spinnerStyle.Render(spinner) + " " + statusText
 	case "completed":
 		successStyle := lipgloss.NewStyle().Foreground(t.Success()).Bold(true)
-		statusLine = fmt.Sprintf("%s %s%s",
-			Vertical,
-			contentPadding,
-			successStyle.Render("✓ Completed"))
+		statusLine = successStyle.Render(g.Done + " Completed")
 	case "failed":
 		errorStyle := lipgloss.NewStyle().Foreground(t.Error()).Bold(true)
-		statusLine = fmt.Sprintf("%s %s%s",
-			Vertical,
-			contentPadding,
-			errorStyle.Render("✗ Failed"))
+		statusLine = errorStyle.Render(g.Failed + " Failed")
 	default:
 		pendingStyle := lipgloss.NewStyle().Foreground(t.TextMuted())
-		statusLine = fmt.Sprintf("%s %s%s",
-			Vertical,
-			contentPadding,
-			pendingStyle.Render("○ Pending"))
+		statusLine = pendingStyle.Render(g.Pending + " Pending")
 	}
+	lines = append(lines, boxLine(statusLine))
 
-	// Pad status line to full width
-	statusContent := statusLine[3:] // Remove the vertical bar temporarily
-	statusPadding := width - lipgloss.Width(statusContent) - 2
-	if statusPadding > 0 {
-		statusLine = Vertical + " " + statusContent + strings.Repeat(" ", statusPadding) + Vertical
-	} else {
-		statusLine = Vertical + " " + statusContent + " " + Vertical
+	// Progress line while running
+	if status == "running" {
+		lines = append(lines, boxLine(RenderTaskProgress(progress, width-8)))
 	}
-	lines = append(lines, statusLine)
 
 	// Time line (if running or completed)
 	if status == "running" || status == "completed" {
-		timeDisplay := RenderElapsedTime(duration)
-		timeLine := fmt.Sprintf("%s %s⏱  %s", Vertical, contentPadding, timeDisplay)
-		timePadding := width - lipgloss.Width(timeLine) + 1 // +1 because vertical bar is 1 char
-		if timePadding > 0 {
-			timeLine = timeLine + strings.Repeat(" ", timePadding) + Vertical
-		} else {
-			timeLine = timeLine + " " + Vertical
+		timeLine := RenderElapsedTime(duration)
+		if g.Timer != "" {
+			timeLine = g.Timer + " " + timeLine
 		}
-		lines = append(lines, timeLine)
+		lines = append(lines, boxLine(timeLine))
 	}
 
 	// Footer
-	footer := BottomLeft + strings.Repeat(Horizontal, width-2) + BottomRight
+	footer := g.BottomLeft + strings.Repeat(g.Horizontal, width-2) + g.BottomRight
 	lines = append(lines, footer)
 
 	// Apply border color to the entire box
@@ -403,3 +389,39 @@ func RenderTaskBoxWithTool(icon string, taskName string, description string, sta
 
 	return strings.Join(styledLines, "\n")
 }
+
+// taskStatusLabel spells out a task status for accessible mode
+func taskStatusLabel(status string) string {
+	switch status {
+	case "running":
+		return styles.StatusRunning
+	case "completed":
+		return styles.StatusDone
+	case "failed":
+		return styles.StatusFailed
+	default:
+		return styles.StatusPending
+	}
+}
+
+// renderTaskPlain renders a task as plain lines for accessible mode, with
+// no box, spinner or progress bar
+func renderTaskPlain(agentNum string, description string, status string, progress int, duration time.Duration, width int, currentTool string, message string) string {
+	t := theme.CurrentTheme()
+	header := fmt.Sprintf("%s %s: %s", taskStatusLabel(status), agentNum, description)
+	lines := []string{lipgloss.NewStyle().Foreground(t.Text()).Render(truncateTitle(header, width))}
+
+	detailStyle := lipgloss.NewStyle().Foreground(t.TextMuted())
+	switch status {
+	case "running":
+		statusMsg := GetDynamicStatus(currentTool, duration)
+		if message != "" {
+			statusMsg = strings.ReplaceAll(message, "\n", " ")
+		}
+		detail := fmt.Sprintf("%s %d%% ", statusMsg, progress)
+		lines = append(lines, detailStyle.Render(truncateTitle(detail, width-10))+RenderElapsedTime(duration))
+	case "completed":
+		lines = append(lines, detailStyle.Render("Elapsed ")+RenderElapsedTime(duration))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,32 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/styles/glyphs"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+func TestRenderTaskBoxAligned(t *testing.T) {
+	if err := theme.LoadThemesFromJSON(); err != nil {
+		t.Fatal(err)
+	}
+	if err := theme.SetTheme("ayu"); err != nil {
+		t.Fatal(err)
+	}
+	defer glyphs.SetSupport(glyphs.Unicode)
+	for _, support := range []glyphs.Support{glyphs.Unicode, glyphs.ASCII, glyphs.NerdFont} {
+		glyphs.SetSupport(support)
+		for _, status := range []string{"running", "completed", "failed", "pending"} {
+			box := RenderTaskBoxWithProgress("", "Agent 1: search the tree", "", status, 40, 75*time.Second, 50, "grep", "")
+			for _, line := range strings.Split(box, "\n") {
+				if got := lipgloss.Width(line); got != 50 {
+					t.Errorf("support %d, %s: line %q is %d wide, want 50", support, status, line, got)
+				}
+			}
+		}
+	}
+}
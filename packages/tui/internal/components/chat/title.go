@@ -0,0 +1,56 @@
+package chat
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// toolTitlePadding is the space taken by the block border and padding around
+// a tool title
+const toolTitlePadding = 8
+
+// maxTruncatedTitles bounds the titles remembered for the focus footer
+const maxTruncatedTitles = 512
+
+// truncatedTitles maps the plain text of truncated tool titles to the full
+// title, so the full title can be shown when a truncated one is clicked.
+// Messages render in parallel, hence the lock.
+var (
+	truncatedTitles   = make(map[string]string)
+	truncatedTitlesMu sync.Mutex
+)
+
+// truncateTitle shortens a title to width with a middle ellipsis, which keeps
+// both the start of a path and its file name visible
+func truncateTitle(title string, width int) string {
+	truncated := util.TruncateMiddle(title, width)
+	if truncated == title {
+		return title
+	}
+	truncatedTitlesMu.Lock()
+	if len(truncatedTitles) >= maxTruncatedTitles {
+		clear(truncatedTitles)
+	}
+	truncatedTitles[ansi.Strip(truncated)] = ansi.Strip(title)
+	truncatedTitlesMu.Unlock()
+	return truncated
+}
+
+// fullTitleAt returns the full text of a truncated title shown on line
+func fullTitleAt(line string) (string, bool) {
+	plain := ansi.Strip(line)
+	if !strings.Contains(plain, "…") {
+		return "", false
+	}
+	truncatedTitlesMu.Lock()
+	defer truncatedTitlesMu.Unlock()
+	for truncated, full := range truncatedTitles {
+		if strings.Contains(plain, truncated) {
+			return full, true
+		}
+	}
+	return "", false
+}
@@ -2,12 +2,14 @@ package dialog
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/sst/dgmo/internal/app"
 	"github.com/sst/dgmo/internal/components/list"
 	"github.com/sst/dgmo/internal/components/modal"
 	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/config"
 	"github.com/sst/dgmo/internal/layout"
 	"github.com/sst/dgmo/internal/util"
 )
@@ -27,15 +29,33 @@ type agentDialog struct {
 	modeApplied  bool
 }
 
+// presetItemPrefix marks the list items that select an agent preset
+const presetItemPrefix = "preset: "
+
 func NewAgentDialog(app *app.App) AgentDialog {
 	modes := []string{"read-only", "all-tools"}
+	items := append([]string{}, modes...)
+	for _, preset := range app.State.AgentPresets {
+		items = append(items, presetItemPrefix+preset.Name)
+	}
 
-	// Default to read-only
 	selectedIdx := 0
+	for i, mode := range modes {
+		if mode == app.AgentMode() {
+			selectedIdx = i
+		}
+	}
+	if preset := app.ActivePreset(); preset != nil {
+		for i, item := range items {
+			if item == presetItemPrefix+preset.Name {
+				selectedIdx = i
+			}
+		}
+	}
 
 	modeList := list.NewStringList(
-		modes,
-		2, // maxVisible
+		items,
+		min(len(items), 8), // maxVisible
 		"No modes available",
 		true, // showHelp
 	)
@@ -44,14 +64,14 @@ func NewAgentDialog(app *app.App) AgentDialog {
 	d := &agentDialog{
 		app:          app,
 		list:         modeList,
-		originalMode: "read-only",
+		originalMode: app.AgentMode(),
 		modeApplied:  false,
 	}
 
 	d.modal = modal.New(
 		modal.WithTitle("Select Agent Mode"),
 		modal.WithMaxWidth(60),
-		modal.WithMaxHeight(10),
+		modal.WithMaxHeight(14+len(app.State.AgentPresets)),
 	)
 
 	return d
@@ -72,14 +92,28 @@ func (d *agentDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "enter":
 			if item, idx := d.list.GetSelectedItem(); idx >= 0 {
+				if name, ok := strings.CutPrefix(string(item), presetItemPrefix); ok {
+					return d, d.selectPreset(name)
+				}
 				selectedMode := string(item)
+				// a mode replaces the preset
+				if _, err := d.app.SelectPreset(""); err != nil {
+					return d, toast.NewErrorToast(err.Error())
+				}
 				d.modeApplied = true
-
-				// For now, just show a success message
+				d.app.State.AgentMode = selectedMode
+				d.app.SaveState()
+
+				if d.app.ProjectOverrides("autonomy") {
+					return d, tea.Sequence(
+						util.CmdHandler(modal.CloseModalMsg{}),
+						toast.NewInfoToast(fmt.Sprintf("Agent mode saved, but this project uses %s", d.app.AgentMode())),
+					)
+				}
 				// TODO: Implement actual config update through API
 				return d, tea.Sequence(
 					util.CmdHandler(modal.CloseModalMsg{}),
-					toast.NewSuccessToast(fmt.Sprintf("Agent mode set to %s for this session", selectedMode)),
+					toast.NewSuccessToast(fmt.Sprintf("Agent mode set to %s", selectedMode)),
 				)
 			}
 		case "esc", "ctrl+c":
@@ -93,11 +127,54 @@ func (d *agentDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return d, cmd
 }
 
+// selectPreset makes a preset active and switches to its model
+func (d *agentDialog) selectPreset(name string) tea.Cmd {
+	cmd, err := d.app.SelectPreset(name)
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	d.modeApplied = true
+	message := fmt.Sprintf("Agent preset set to %s", name)
+	if d.app.Features.Require(app.FeaturePresets) != nil {
+		message += ", the server ignores its tools and temperature"
+	}
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		cmd,
+		toast.NewSuccessToast(message),
+	)
+}
+
+// presetSummary describes the restrictions of a preset in one line
+func presetSummary(preset config.AgentPreset) string {
+	var parts []string
+	if preset.Model != "" {
+		parts = append(parts, preset.Model)
+	}
+	if len(preset.Tools) > 0 {
+		parts = append(parts, "tools "+strings.Join(preset.Tools, ","))
+	}
+	if preset.Temperature != nil {
+		parts = append(parts, fmt.Sprintf("temperature %.1f", *preset.Temperature))
+	}
+	if len(parts) == 0 {
+		return "no restrictions"
+	}
+	return strings.Join(parts, " · ")
+}
+
 func (d *agentDialog) Render(background string) string {
 	content := d.list.View()
 
 	// Add help text
-	helpText := "\n\nread-only: Sub-agents can only read files and search\nall-tools: Sub-agents have full access to all tools\n\n↑/↓: Navigate • Enter: Select • Esc: Cancel"
+	helpText := "\n\nread-only: Sub-agents can only read files and search\nall-tools: Sub-agents have full access to all tools"
+	for _, preset := range d.app.State.AgentPresets {
+		helpText += fmt.Sprintf("\n%s: %s", preset.Name, presetSummary(preset))
+	}
+	if len(d.app.State.AgentPresets) == 0 {
+		helpText += "\nAdd [[agent_presets]] to the state file to define presets"
+	}
+	helpText += "\n\n↑/↓: Navigate • Enter: Select • Esc: Cancel"
 
 	return d.modal.Render(content+helpText, background)
 }
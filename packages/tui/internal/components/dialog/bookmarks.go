@@ -0,0 +1,149 @@
+package dialog
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// BookmarksDialog interface for jumping to the bookmarks of a session
+type BookmarksDialog interface {
+	layout.Modal
+}
+
+type bookmarkItem struct {
+	bookmark config.Bookmark
+	preview  string // first line of the bookmarked message, empty if it is gone
+}
+
+func (b bookmarkItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	nameStyle := baseStyle.Foreground(t.Text())
+	previewStyle := baseStyle.Foreground(t.TextMuted())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.Primary())
+		nameStyle = baseStyle.Foreground(t.BackgroundElement()).Bold(true)
+		previewStyle = baseStyle.Foreground(t.BackgroundElement())
+	}
+
+	name := nameStyle.Render(" " + b.bookmark.Name)
+	preview := b.preview
+	if preview == "" {
+		preview = "message no longer in the transcript"
+	}
+	preview = truncate.StringWithTail("  "+preview, uint(max(0, width-lipgloss.Width(name))), "…")
+	line := name + previewStyle.Render(preview)
+	gap := max(0, width-lipgloss.Width(line))
+	return line + baseStyle.Render(strings.Repeat(" ", gap))
+}
+
+func (b bookmarkItem) FilterValue() string {
+	return b.bookmark.Name
+}
+
+type bookmarksDialog struct {
+	app       *app.App
+	sessionID string
+	modal     *modal.Modal
+	list      list.List[bookmarkItem]
+}
+
+func (d *bookmarksDialog) Init() tea.Cmd {
+	return nil
+}
+
+// refresh lists the bookmarks of the session, newest first
+func (d *bookmarksDialog) refresh() {
+	bookmarks := d.app.SessionBookmarks(d.sessionID)
+	items := make([]bookmarkItem, 0, len(bookmarks))
+	for _, bookmark := range slices.Backward(bookmarks) {
+		item := bookmarkItem{bookmark: bookmark}
+		if i := slices.IndexFunc(d.app.Messages, func(message opencode.Message) bool {
+			return message.ID == bookmark.MessageID
+		}); i >= 0 {
+			item.preview = strings.Join(strings.Fields(app.PromptText(d.app.Messages[i])), " ")
+		}
+		items = append(items, item)
+	}
+	d.list.SetItems(items)
+}
+
+func (d *bookmarksDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.list.SetMaxWidth(layout.Current.Container.Width - 12)
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "enter":
+			item, idx := d.list.GetSelectedItem()
+			if idx < 0 {
+				return d, nil
+			}
+			return d, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(app.BookmarkJumpMsg{Bookmark: item.bookmark}),
+			)
+		case "ctrl+d", "delete":
+			item, idx := d.list.GetSelectedItem()
+			if idx < 0 {
+				return d, nil
+			}
+			d.app.DeleteSessionBookmark(d.sessionID, item.bookmark.Name)
+			d.refresh()
+			d.list.SetSelectedIndex(max(0, min(idx, len(d.app.SessionBookmarks(d.sessionID))-1)))
+			return d, nil
+		}
+	}
+
+	listModel, cmd := d.list.Update(msg)
+	d.list = listModel.(list.List[bookmarkItem])
+	return d, cmd
+}
+
+func (d *bookmarksDialog) View() string {
+	t := theme.CurrentTheme()
+	help := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement()).PaddingTop(1).
+		Render("enter jump · ctrl+d delete · type to filter")
+	return d.list.View() + "\n" + help
+}
+
+func (d *bookmarksDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *bookmarksDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewBookmarksDialog creates a dialog that lists the bookmarks of a
+// session, newest first, to jump back to one
+func NewBookmarksDialog(app *app.App, sessionID string) BookmarksDialog {
+	bookmarks := list.NewListComponent([]bookmarkItem{}, 10, "No bookmarks, /bookmark sets one", false)
+	bookmarks.SetFilterable(true)
+	bookmarks.SetMaxWidth(layout.Current.Container.Width - 12)
+
+	d := &bookmarksDialog{
+		app:       app,
+		sessionID: sessionID,
+		list:      bookmarks,
+		modal: modal.New(
+			modal.WithTitle("Bookmarks"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.refresh()
+	return d
+}
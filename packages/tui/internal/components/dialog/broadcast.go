@@ -0,0 +1,458 @@
+package dialog
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/styles/glyphs"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// BroadcastDialog interface for sending one prompt to several sessions
+type BroadcastDialog interface {
+	layout.Modal
+}
+
+// broadcastSessionTargets is how many recent sessions are offered as targets
+const broadcastSessionTargets = 6
+
+// broadcastSessionsMsg carries the sessions a broadcast can go to
+type broadcastSessionsMsg struct {
+	dialog   *broadcastDialog
+	sessions []opencode.Session
+}
+
+type broadcastOption struct {
+	target  app.BroadcastTarget
+	checked bool
+}
+
+type broadcastDialog struct {
+	app     *app.App
+	modal   *modal.Modal
+	prompt  textinput.Model
+	options []broadcastOption
+	// focus is 0 for the prompt, then 1 for the first option
+	focus int
+	width int
+}
+
+func (b *broadcastDialog) Init() tea.Cmd {
+	return tea.Batch(b.prompt.Focus(), func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		sessions, err := b.app.ListSessions(ctx)
+		if err != nil {
+			return broadcastSessionsMsg{dialog: b}
+		}
+		return broadcastSessionsMsg{dialog: b, sessions: sessions}
+	})
+}
+
+// modelOptions offers the current, favorite and recent models, each in a
+// new session
+func (b *broadcastDialog) modelOptions() []broadcastOption {
+	var refs [][2]string
+	add := func(providerID, modelID string) {
+		ref := [2]string{providerID, modelID}
+		if providerID != "" && modelID != "" && !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	if b.app.Provider != nil && b.app.Model != nil {
+		add(b.app.Provider.ID, b.app.Model.ID)
+	}
+	for _, favorite := range b.app.State.FavoriteModels {
+		add(favorite.ProviderID, favorite.ModelID)
+	}
+	for _, recent := range b.app.State.RecentlyUsedModels {
+		add(recent.ProviderID, recent.ModelID)
+	}
+	options := make([]broadcastOption, len(refs))
+	for i, ref := range refs {
+		options[i] = broadcastOption{target: app.BroadcastTarget{ProviderID: ref[0], ModelID: ref[1]}}
+	}
+	return options
+}
+
+// addSessions offers the most recent top level sessions, answered with the
+// current model
+func (b *broadcastDialog) addSessions(sessions []opencode.Session) {
+	if b.app.Provider == nil || b.app.Model == nil {
+		return
+	}
+	sessions = slices.DeleteFunc(slices.Clone(sessions), func(session opencode.Session) bool {
+		return session.ParentID != ""
+	})
+	slices.SortFunc(sessions, func(x, y opencode.Session) int {
+		return cmp.Compare(y.Time.Updated, x.Time.Updated)
+	})
+	for _, session := range sessions[:min(len(sessions), broadcastSessionTargets)] {
+		b.options = append(b.options, broadcastOption{target: app.BroadcastTarget{
+			SessionID:  session.ID,
+			Title:      session.Title,
+			ProviderID: b.app.Provider.ID,
+			ModelID:    b.app.Model.ID,
+		}})
+	}
+}
+
+func (b *broadcastDialog) targets() []app.BroadcastTarget {
+	var targets []app.BroadcastTarget
+	for _, option := range b.options {
+		if option.checked {
+			targets = append(targets, option.target)
+		}
+	}
+	return targets
+}
+
+func (b *broadcastDialog) moveFocus(delta int) tea.Cmd {
+	fields := len(b.options) + 1
+	b.focus = (b.focus + delta + fields) % fields
+	if b.focus == 0 {
+		return b.prompt.Focus()
+	}
+	b.prompt.Blur()
+	return nil
+}
+
+func (b *broadcastDialog) send() tea.Cmd {
+	targets := b.targets()
+	if _, err := app.NewBroadcast(b.prompt.Value(), targets); err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(app.BroadcastConfirmedMsg{Prompt: b.prompt.Value(), Targets: targets}),
+	)
+}
+
+func (b *broadcastDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case broadcastSessionsMsg:
+		if msg.dialog == b {
+			b.addSessions(msg.sessions)
+		}
+		return b, nil
+	case tea.WindowSizeMsg:
+		b.setSize()
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "enter":
+			return b, b.send()
+		case "tab", "down":
+			return b, b.moveFocus(1)
+		case "shift+tab", "up":
+			return b, b.moveFocus(-1)
+		case "space":
+			if b.focus > 0 {
+				b.options[b.focus-1].checked = !b.options[b.focus-1].checked
+				return b, nil
+			}
+		}
+	}
+
+	if b.focus == 0 {
+		var cmd tea.Cmd
+		b.prompt, cmd = b.prompt.Update(msg)
+		return b, cmd
+	}
+	return b, nil
+}
+
+func (b *broadcastDialog) setSize() {
+	b.width = min(76, layout.Current.Container.Width-12)
+	b.prompt.SetWidth(b.width)
+}
+
+func (b *broadcastDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	label := func(focused bool, text string) string {
+		if focused {
+			return base.Foreground(t.Primary()).Bold(true).Render(text)
+		}
+		return muted.Render(text)
+	}
+
+	lines := []string{label(b.focus == 0, "Prompt"), b.prompt.View(), ""}
+	section := ""
+	for i, option := range b.options {
+		heading := "New session with"
+		if option.target.SessionID != "" {
+			heading = "Existing session"
+		}
+		if heading != section {
+			if section != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, muted.Render(heading))
+			section = heading
+		}
+		box := "[ ] "
+		if option.checked {
+			box = "[x] "
+		}
+		text := truncate.StringWithTail(option.target.Label(), uint(max(0, b.width-4)), "…")
+		lines = append(lines, label(b.focus == i+1, box)+base.Render(text))
+	}
+	if len(b.options) == 0 {
+		lines = append(lines, muted.Render("No models to broadcast to, pick one with /models first"))
+	}
+	help := muted.PaddingTop(1).Render(fmt.Sprintf(
+		"tab next · space select (%d/%d) · enter send · esc cancel",
+		len(b.targets()), app.MaxBroadcastTargets,
+	))
+	return strings.Join(lines, "\n") + "\n" + help
+}
+
+func (b *broadcastDialog) Render(background string) string {
+	return b.modal.Render(b.View(), background)
+}
+
+func (b *broadcastDialog) Close() tea.Cmd {
+	b.prompt.Blur()
+	return nil
+}
+
+// NewBroadcastDialog creates a dialog that sends a prompt to several models
+// and sessions at once, starting with the prompt of the editor
+func NewBroadcastDialog(app *app.App, prompt string) BroadcastDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "What should every model answer?"
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred = input.Styles.Focused
+	input.Styles.Cursor.Color = t.Primary()
+	input.SetValue(strings.TrimSpace(prompt))
+
+	b := &broadcastDialog{
+		app:    app,
+		prompt: input,
+		modal: modal.New(
+			modal.WithTitle("Broadcast Prompt"),
+			modal.WithMaxWidth(80),
+		),
+	}
+	b.options = b.modelOptions()
+	b.setSize()
+	return b
+}
+
+// BroadcastCompareDialog interface for comparing the responses to a
+// broadcast
+type BroadcastCompareDialog interface {
+	layout.Modal
+}
+
+// compareTickMsg carries its dialog so ticks of a closed dialog die out
+type compareTickMsg struct{ dialog *broadcastCompareDialog }
+
+// compareResponseLines is how much of an expanded response is shown
+const compareResponseLines = 8
+
+type compareItem struct {
+	run      app.BroadcastRun
+	expanded bool
+}
+
+func (c compareItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.BackgroundPanel())
+	}
+
+	g := glyphs.Current()
+	icon, iconColor := g.Running, t.Primary()
+	switch c.run.Status {
+	case app.BroadcastSending:
+		icon, iconColor = g.Dot, t.TextMuted()
+	case app.BroadcastDone:
+		icon, iconColor = g.Done, t.Success()
+	case app.BroadcastFailed:
+		icon, iconColor = g.Failed, t.Error()
+	}
+	icon = styles.StatusMark(icon, "["+strings.ToUpper(string(c.run.Status))+"]")
+
+	rating := strings.Repeat("★", c.run.Rating) + strings.Repeat("☆", 5-c.run.Rating)
+	if c.run.Rating == 0 {
+		rating = "unrated"
+	} else if styles.Accessible() || glyphs.CurrentSupport() == glyphs.ASCII {
+		rating = fmt.Sprintf("%d/5", c.run.Rating)
+	}
+	right := fmt.Sprintf(" %6s  %5.0f tok  $%.3f  %s",
+		c.run.Elapsed().Round(time.Second), c.run.Tokens, c.run.Cost, rating)
+
+	left := baseStyle.Foreground(iconColor).Render(" " + icon + " ")
+	nameStyle := baseStyle.Foreground(t.Text())
+	if selected {
+		nameStyle = nameStyle.Bold(true)
+	}
+	nameWidth := max(0, width-lipgloss.Width(left)-lipgloss.Width(right))
+	name := nameStyle.Render(truncate.StringWithTail(c.run.Target.Label(), uint(nameWidth), "…"))
+	gap := max(0, width-lipgloss.Width(left)-lipgloss.Width(name)-lipgloss.Width(right))
+	line := left + name + baseStyle.Render(strings.Repeat(" ", gap)) + baseStyle.Foreground(t.TextMuted()).Render(right)
+	if !c.expanded {
+		return line
+	}
+
+	text := c.run.Response
+	switch {
+	case c.run.Error != "":
+		text = c.run.Error
+	case text == "":
+		text = "no response yet"
+	}
+	wrapped := strings.Split(lipgloss.NewStyle().Width(max(10, width-8)).Render(text), "\n")
+	if len(wrapped) > compareResponseLines {
+		hidden := len(wrapped) - compareResponseLines
+		wrapped = append(wrapped[:compareResponseLines], fmt.Sprintf("… %d more lines, enter opens the session", hidden))
+	}
+	tailStyle := styles.NewStyle().Background(t.BackgroundElement()).Foreground(t.TextMuted()).Width(width)
+	lines := []string{line}
+	for _, text := range wrapped {
+		lines = append(lines, tailStyle.Render("     │ "+text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c compareItem) FilterValue() string {
+	return c.run.Target.Label()
+}
+
+type broadcastCompareDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	list     list.List[compareItem]
+	expanded map[int]bool
+}
+
+func (c *broadcastCompareDialog) Init() tea.Cmd {
+	return c.tick()
+}
+
+func (c *broadcastCompareDialog) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return compareTickMsg{dialog: c}
+	})
+}
+
+// refresh reloads the runs of the current broadcast, keeping the selection
+func (c *broadcastCompareDialog) refresh() {
+	_, selected := c.list.GetSelectedItem()
+	var items []compareItem
+	if c.app.Broadcast != nil {
+		for i, run := range c.app.Broadcast.Runs {
+			items = append(items, compareItem{run: run, expanded: c.expanded[i]})
+		}
+	}
+	c.list.SetItems(items)
+	c.list.SetSelectedIndex(max(0, selected))
+}
+
+func (c *broadcastCompareDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case compareTickMsg:
+		if msg.dialog != c {
+			return c, nil
+		}
+		c.refresh()
+		return c, c.tick()
+	case app.BroadcastLoadedMsg, app.BroadcastRunMsg, opencode.EventListResponseEventMessageUpdated:
+		c.refresh()
+		return c, nil
+	case tea.WindowSizeMsg:
+		c.list.SetMaxWidth(layout.Current.Container.Width - 12)
+	case tea.KeyPressMsg:
+		item, idx := c.list.GetSelectedItem()
+		switch msg.String() {
+		case "tab":
+			if idx >= 0 {
+				c.expanded[idx] = !c.expanded[idx]
+				c.refresh()
+			}
+			return c, nil
+		case "enter":
+			if idx < 0 || item.run.SessionID == "" {
+				return c, nil
+			}
+			return c, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				c.app.SwitchToSession(context.Background(), item.run.SessionID),
+			)
+		case "0", "1", "2", "3", "4", "5":
+			if idx >= 0 {
+				c.app.RateBroadcastRun(idx, int(msg.String()[0]-'0'))
+				c.refresh()
+			}
+			return c, nil
+		}
+	}
+
+	listModel, cmd := c.list.Update(msg)
+	c.list = listModel.(list.List[compareItem])
+	return c, cmd
+}
+
+func (c *broadcastCompareDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	if c.app.Broadcast == nil {
+		return muted.Render("Nothing was broadcast yet, /broadcast sends a prompt to several models")
+	}
+	width := layout.Current.Container.Width - 12
+	prompt := strings.Join(strings.Fields(c.app.Broadcast.Prompt), " ")
+	header := muted.PaddingBottom(1).Render(truncate.StringWithTail(prompt, uint(max(0, width)), "…"))
+	help := muted.PaddingTop(1).Render("enter open session · tab toggle response · 1-5 rate · 0 clear rating")
+	return header + "\n" + c.list.View() + "\n" + help
+}
+
+func (c *broadcastCompareDialog) Render(background string) string {
+	return c.modal.Render(c.View(), background)
+}
+
+func (c *broadcastCompareDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewBroadcastCompareDialog creates a live comparison of the responses to
+// the latest broadcast, where each response can be rated
+func NewBroadcastCompareDialog(app *app.App) BroadcastCompareDialog {
+	runs := list.NewListComponent([]compareItem{}, 10, "Waiting for the sessions to be created", true)
+	runs.SetMaxWidth(layout.Current.Container.Width - 12)
+
+	c := &broadcastCompareDialog{
+		app:      app,
+		list:     runs,
+		expanded: make(map[int]bool),
+		modal: modal.New(
+			modal.WithTitle("Compare Responses"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	c.refresh()
+	return c
+}
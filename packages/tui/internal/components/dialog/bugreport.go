@@ -0,0 +1,117 @@
+package dialog
+
+import (
+	"crypto"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/bugreport"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// BugReportConfirmedMsg is sent when the reviewed report should be written
+type BugReportConfirmedMsg struct {
+	Report    *bugreport.Report
+	PublicKey crypto.PublicKey
+}
+
+// BugReportDialog interface for the review of a bug report
+type BugReportDialog interface {
+	layout.Modal
+}
+
+type bugReportDialog struct {
+	report    *bugreport.Report
+	publicKey crypto.PublicKey
+	keyPath   string
+	modal     *modal.Modal
+	viewport  viewport.Model
+	width     int
+}
+
+func (d *bugReportDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *bugReportDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyPressMsg); ok && msg.String() == "enter" {
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(BugReportConfirmedMsg{Report: d.report, PublicKey: d.publicKey}),
+		)
+	}
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+// content lists the files of the report and every secret redacted from them
+func (d *bugReportDialog) content() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	heading := base.Bold(true)
+	muted := base.Foreground(t.TextMuted())
+	warning := base.Foreground(t.Warning())
+
+	lines := []string{heading.Render("Files")}
+	for _, file := range d.report.Files {
+		lines = append(lines, base.Render(fmt.Sprintf("  %-16s %s", file.Name, app.FormatBytes(len(file.Content)))))
+	}
+	lines = append(lines, "", heading.Render(fmt.Sprintf("Redactions (%d)", len(d.report.Redactions))))
+	if len(d.report.Redactions) == 0 {
+		lines = append(lines, muted.Render("  no secrets found, the files still hold your prompts and paths"))
+	}
+	for _, r := range d.report.Redactions {
+		location := fmt.Sprintf("%s:%d", r.File, r.Line)
+		lines = append(lines, base.Render(fmt.Sprintf("  %-20s %-18s ", location, r.Kind))+muted.Render(r.Hint))
+	}
+	lines = append(lines, "")
+	if d.publicKey != nil {
+		lines = append(lines, base.Render("Sealed for "+filepath.Base(d.keyPath)+", only its private key opens it."))
+	} else {
+		lines = append(lines, warning.Render("Not sealed, /bugreport <public key file> seals it for the reader."))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (d *bugReportDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	footer := muted.Width(d.width).Render("enter write · ↑/↓ scroll · esc cancel")
+	return lipgloss.JoinVertical(lipgloss.Left, d.viewport.View(), "", footer)
+}
+
+func (d *bugReportDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *bugReportDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewBugReportDialog creates the review of a redacted bug report before it
+// is written
+func NewBugReportDialog(report *bugreport.Report, publicKey crypto.PublicKey, keyPath string) BugReportDialog {
+	d := &bugReportDialog{
+		report:    report,
+		publicKey: publicKey,
+		keyPath:   keyPath,
+		viewport:  viewport.New(),
+		modal:     modal.New(modal.WithTitle("Review Bug Report")),
+	}
+	d.width = max(40, layout.Current.Viewport.Width-12)
+	d.viewport.SetWidth(d.width)
+	content := d.content()
+	d.viewport.SetContent(content)
+	d.viewport.SetHeight(min(lipgloss.Height(content), max(5, layout.Current.Viewport.Height-8)))
+	return d
+}
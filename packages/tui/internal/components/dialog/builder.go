@@ -0,0 +1,347 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// PromptBuilderDialog interface for the prompt builder
+type PromptBuilderDialog interface {
+	layout.Modal
+}
+
+// builderBlockMsg carries a block that was built off the update loop
+type builderBlockMsg struct {
+	dialog *promptBuilderDialog
+	block  app.PromptBlock
+	err    error
+}
+
+type builderItem struct {
+	block app.PromptBlock
+}
+
+func (b builderItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.BackgroundPanel())
+	}
+
+	kind := baseStyle.Foreground(t.Accent()).Render(fmt.Sprintf(" %-8s", b.block.Kind))
+	title := b.block.Title
+	if b.block.Kind == app.PromptBlockText {
+		title = strings.Join(strings.Fields(b.block.Content), " ")
+	}
+	titleStyle := baseStyle.Foreground(t.Text())
+	if selected {
+		titleStyle = titleStyle.Bold(true)
+	}
+	lines := baseStyle.Foreground(t.TextMuted()).Render(fmt.Sprintf(" %d lines ", strings.Count(b.block.Content, "\n")+1))
+	titleWidth := max(0, width-lipgloss.Width(kind)-lipgloss.Width(lines)-1)
+	line := kind + titleStyle.Render(" "+truncate.StringWithTail(title, uint(titleWidth), "…"))
+	gap := max(0, width-lipgloss.Width(line)-lipgloss.Width(lines))
+	return line + baseStyle.Render(strings.Repeat(" ", gap)) + lines
+}
+
+type builderStage int
+
+const (
+	builderStageStack builderStage = iota
+	builderStageInput
+	builderStageQuote
+)
+
+type promptBuilderDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	stage    builderStage
+	blocks   list.List[builderItem]
+	messages list.List[inspectMessageItem]
+	input    textinput.Model
+	kind     app.PromptBlockKind
+	running  bool
+}
+
+func (d *promptBuilderDialog) Init() tea.Cmd {
+	return nil
+}
+
+// sync shows the blocks of the app, keeping the selection at selected
+func (d *promptBuilderDialog) sync(selected int) {
+	items := make([]builderItem, 0, len(d.app.PromptBlocks))
+	for _, block := range d.app.PromptBlocks {
+		items = append(items, builderItem{block: block})
+	}
+	d.blocks.SetItems(items)
+	d.blocks.SetSelectedIndex(max(0, min(selected, len(items)-1)))
+}
+
+func (d *promptBuilderDialog) add(block app.PromptBlock) {
+	d.app.PromptBlocks = append(d.app.PromptBlocks, block)
+	d.sync(len(d.app.PromptBlocks) - 1)
+}
+
+// move swaps the selected block with its neighbour in direction delta
+func (d *promptBuilderDialog) move(delta int) {
+	_, idx := d.blocks.GetSelectedItem()
+	target := idx + delta
+	if idx < 0 || target < 0 || target >= len(d.app.PromptBlocks) {
+		return
+	}
+	blocks := d.app.PromptBlocks
+	blocks[idx], blocks[target] = blocks[target], blocks[idx]
+	d.sync(target)
+}
+
+func (d *promptBuilderDialog) remove() {
+	_, idx := d.blocks.GetSelectedItem()
+	if idx < 0 {
+		return
+	}
+	d.app.PromptBlocks = slices.Delete(d.app.PromptBlocks, idx, idx+1)
+	d.sync(idx)
+}
+
+// prompt switches to the input stage for a block of the given kind
+func (d *promptBuilderDialog) prompt(kind app.PromptBlockKind) tea.Cmd {
+	d.stage = builderStageInput
+	d.kind = kind
+	d.input.Reset()
+	switch kind {
+	case app.PromptBlockFile:
+		d.input.Placeholder = "path/to/file.go:10-40"
+	case app.PromptBlockCommand:
+		d.input.Placeholder = "command to run, e.g. go test ./..."
+	default:
+		d.input.Placeholder = "text"
+	}
+	return d.input.Focus()
+}
+
+func (d *promptBuilderDialog) back() {
+	d.stage = builderStageStack
+	d.input.Blur()
+}
+
+// submitInput turns the input into a block. Commands run in the background
+// since they may take a while.
+func (d *promptBuilderDialog) submitInput() tea.Cmd {
+	value := strings.TrimSpace(d.input.Value())
+	if value == "" {
+		return nil
+	}
+	switch d.kind {
+	case app.PromptBlockFile:
+		block, err := app.FileBlock(d.app.Info.Path.Root, value)
+		if err != nil {
+			return toast.NewErrorToast(err.Error())
+		}
+		d.add(block)
+	case app.PromptBlockCommand:
+		d.running = true
+		dir := d.app.Info.Path.Cwd
+		d.back()
+		return func() tea.Msg {
+			block, err := app.CommandBlock(context.Background(), dir, value)
+			return builderBlockMsg{dialog: d, block: block, err: err}
+		}
+	default:
+		d.add(app.TextBlock(d.input.Value()))
+	}
+	d.back()
+	return nil
+}
+
+func (d *promptBuilderDialog) send() tea.Cmd {
+	if len(d.app.PromptBlocks) == 0 {
+		return toast.NewInfoToast("Add a block first")
+	}
+	text := app.ComposePrompt(d.app.PromptBlocks)
+	d.app.PromptBlocks = nil
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(app.SendMsg{Text: text}),
+	)
+}
+
+func (d *promptBuilderDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case builderBlockMsg:
+		if msg.dialog != d {
+			return d, nil
+		}
+		d.running = false
+		if msg.err != nil {
+			return d, toast.NewErrorToast(msg.err.Error())
+		}
+		d.add(msg.block)
+		return d, nil
+	case tea.WindowSizeMsg:
+		d.setSize()
+	case tea.KeyPressMsg:
+		switch d.stage {
+		case builderStageInput:
+			switch msg.String() {
+			case "enter":
+				return d, d.submitInput()
+			case "backspace":
+				if d.input.Value() == "" {
+					d.back()
+					return d, nil
+				}
+			}
+			var cmd tea.Cmd
+			d.input, cmd = d.input.Update(msg)
+			return d, cmd
+		case builderStageQuote:
+			switch msg.String() {
+			case "enter":
+				if item, idx := d.messages.GetSelectedItem(); idx >= 0 {
+					d.add(app.QuoteBlock(item.message))
+				}
+				d.back()
+				return d, nil
+			case "backspace":
+				if d.messages.FilterQuery() == "" {
+					d.back()
+					return d, nil
+				}
+			}
+			listModel, cmd := d.messages.Update(msg)
+			d.messages = listModel.(list.List[inspectMessageItem])
+			return d, cmd
+		}
+
+		switch msg.String() {
+		case "t":
+			return d, d.prompt(app.PromptBlockText)
+		case "f":
+			return d, d.prompt(app.PromptBlockFile)
+		case "c":
+			return d, d.prompt(app.PromptBlockCommand)
+		case "q":
+			d.stage = builderStageQuote
+			return d, nil
+		case "d", "delete":
+			d.remove()
+			return d, nil
+		case "K", "shift+up":
+			d.move(-1)
+			return d, nil
+		case "J", "shift+down":
+			d.move(1)
+			return d, nil
+		case "e":
+			// edit the composed message before sending
+			text := app.ComposePrompt(d.app.PromptBlocks)
+			d.app.PromptBlocks = nil
+			return d, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(TemplateFilledMsg{Prompt: text}),
+			)
+		case "enter":
+			return d, d.send()
+		}
+	}
+
+	if d.stage == builderStageStack {
+		listModel, cmd := d.blocks.Update(msg)
+		d.blocks = listModel.(list.List[builderItem])
+		return d, cmd
+	}
+	return d, nil
+}
+
+func (d *promptBuilderDialog) setSize() {
+	width := layout.Current.Container.Width - 12
+	d.blocks.SetMaxWidth(width)
+	d.messages.SetMaxWidth(width)
+	d.input.SetWidth(width - 2)
+}
+
+func (d *promptBuilderDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	switch d.stage {
+	case builderStageInput:
+		label := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement()).
+			Render(fmt.Sprintf("New %s block", d.kind))
+		help := muted.PaddingTop(1).Render("enter add · backspace on empty input goes back")
+		return label + "\n" + d.input.View() + "\n" + help
+	case builderStageQuote:
+		help := muted.PaddingTop(1).Render("enter quote message · type to filter · backspace back")
+		return d.messages.View() + "\n" + help
+	}
+
+	status := fmt.Sprintf("%d blocks", len(d.app.PromptBlocks))
+	if d.running {
+		status += " · running command…"
+	}
+	header := muted.PaddingBottom(1).Render(status)
+	help := muted.PaddingTop(1).Render(
+		"t text · f file · q quote · c command · d remove · J/K move · e edit · enter send",
+	)
+	return header + "\n" + d.blocks.View() + "\n" + help
+}
+
+func (d *promptBuilderDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *promptBuilderDialog) Close() tea.Cmd {
+	d.input.Blur()
+	return nil
+}
+
+// NewPromptBuilderDialog creates a dialog that assembles a message from a
+// stack of text, file excerpt, quote and command output blocks
+func NewPromptBuilderDialog(app *app.App) PromptBuilderDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Styles.Focused.Prompt = styles.NewStyle().Foreground(t.Primary()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred = input.Styles.Focused
+	input.Styles.Cursor.Color = t.Primary()
+
+	quotable := make([]inspectMessageItem, 0, len(app.Messages))
+	for i := len(app.Messages) - 1; i >= 0; i-- {
+		quotable = append(quotable, inspectMessageItem{message: app.Messages[i]})
+	}
+	messages := list.NewListComponent(quotable, 10, "No messages to quote", false)
+	messages.SetFilterable(true)
+
+	d := &promptBuilderDialog{
+		app:      app,
+		input:    input,
+		blocks:   list.NewListComponent([]builderItem{}, 10, "Empty, add a block to start", false),
+		messages: messages,
+		modal: modal.New(
+			modal.WithTitle("Prompt Builder"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.setSize()
+	d.sync(0)
+	return d
+}
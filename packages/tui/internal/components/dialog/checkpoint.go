@@ -0,0 +1,118 @@
+package dialog
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// CheckpointDialog interface for the checkpoint creation dialog
+type CheckpointDialog interface {
+	layout.Modal
+}
+
+type checkpointCreateResultMsg struct {
+	checkpoint *app.Checkpoint
+	err        error
+}
+
+type checkpointDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	input    textinput.Model
+	creating bool
+}
+
+func (c *checkpointDialog) Init() tea.Cmd {
+	return c.input.Focus()
+}
+
+func (c *checkpointDialog) create() tea.Cmd {
+	c.creating = true
+	sessionID := c.app.Session.ID
+	label := c.input.Value()
+	return func() tea.Msg {
+		checkpoint, err := c.app.Checkpoints.CreateCheckpoint(context.Background(), sessionID, label)
+		return checkpointCreateResultMsg{checkpoint: checkpoint, err: err}
+	}
+}
+
+func (c *checkpointDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case checkpointCreateResultMsg:
+		c.creating = false
+		if msg.err != nil {
+			return c, toast.NewErrorToast(msg.err.Error())
+		}
+		return c, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.CheckpointCreatedMsg{Checkpoint: *msg.checkpoint}),
+		)
+	case tea.KeyPressMsg:
+		if c.creating {
+			return c, nil
+		}
+		if msg.String() == "enter" {
+			return c, c.create()
+		}
+	}
+
+	var cmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	return c, cmd
+}
+
+func (c *checkpointDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if c.creating {
+		return muted.Render("Creating checkpoint...")
+	}
+	help := muted.PaddingTop(1).Render("enter create · esc cancel")
+	return c.input.View() + "\n" + help
+}
+
+func (c *checkpointDialog) Render(background string) string {
+	return c.modal.Render(c.View(), background)
+}
+
+func (c *checkpointDialog) Close() tea.Cmd {
+	c.input.Blur()
+	return nil
+}
+
+// NewCheckpointDialog creates a dialog that asks for an optional label and
+// creates a checkpoint of the current session
+func NewCheckpointDialog(app *app.App) CheckpointDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "Label (optional)"
+	input.CharLimit = 100
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred.Text = input.Styles.Focused.Text
+	input.Styles.Blurred.Placeholder = input.Styles.Focused.Placeholder
+	input.Styles.Cursor.Color = t.Primary()
+	input.SetWidth(44)
+
+	return &checkpointDialog{
+		app:   app,
+		input: input,
+		modal: modal.New(
+			modal.WithTitle("Create Checkpoint"),
+			modal.WithMaxWidth(50),
+		),
+	}
+}
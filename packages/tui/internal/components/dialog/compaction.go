@@ -0,0 +1,130 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// CompactionDialog interface for the report of a compaction
+type CompactionDialog interface {
+	layout.Modal
+}
+
+type compactedItem struct {
+	message app.CompactedMessage
+}
+
+func (c compactedItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	roleStyle := baseStyle.Foreground(t.Accent())
+	if c.message.Role == opencode.MessageRoleUser {
+		roleStyle = baseStyle.Foreground(t.Secondary())
+	}
+	textStyle := baseStyle.Foreground(t.Text())
+	tokensStyle := baseStyle.Foreground(t.TextMuted())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.Primary())
+		roleStyle = baseStyle.Foreground(t.BackgroundElement()).Bold(true)
+		textStyle = baseStyle.Foreground(t.BackgroundElement())
+		tokensStyle = textStyle
+	}
+
+	role := roleStyle.Render(fmt.Sprintf(" %-9s", c.message.Role))
+	tokens := tokensStyle.Render(" " + util.FormatTokens(c.message.Tokens) + " ")
+	preview := c.message.Preview
+	if preview == "" {
+		preview = "(tool calls only)"
+	}
+	preview = truncate.StringWithTail(
+		preview,
+		uint(max(0, width-lipgloss.Width(role)-lipgloss.Width(tokens)-1)),
+		"…",
+	)
+	left := role + textStyle.Render(preview)
+	gap := max(0, width-lipgloss.Width(left)-lipgloss.Width(tokens))
+	return left + baseStyle.Render(strings.Repeat(" ", gap)) + tokens
+}
+
+type compactionDialog struct {
+	report *app.CompactionReport
+	modal  *modal.Modal
+	list   list.List[compactedItem]
+	width  int
+}
+
+func (c *compactionDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (c *compactionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+	case tea.WindowSizeMsg:
+		c.setSize()
+	}
+	listModel, cmd := c.list.Update(msg)
+	c.list = listModel.(list.List[compactedItem])
+	return c, cmd
+}
+
+func (c *compactionDialog) setSize() {
+	c.width = min(80, layout.Current.Container.Width-12)
+	c.list.SetMaxWidth(c.width)
+}
+
+func (c *compactionDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	success := base.Foreground(t.Success()).Bold(true)
+
+	summary := base.Render(fmt.Sprintf(
+		"Context %s → %s tokens, ",
+		util.FormatTokens(c.report.TokensBefore),
+		util.FormatTokens(c.report.TokensAfter),
+	)) + success.Render(util.FormatTokens(c.report.Saved())+" saved")
+	count := muted.Render(fmt.Sprintf(
+		"%d messages were folded into the summary", len(c.report.Compacted),
+	))
+	help := muted.PaddingTop(1).Render("esc close")
+	return summary + "\n" + count + "\n\n" + c.list.View() + "\n" + help
+}
+
+func (c *compactionDialog) Render(background string) string {
+	return c.modal.Render(c.View(), background)
+}
+
+func (c *compactionDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewCompactionDialog creates a dialog listing the messages a compaction
+// summarized and how the context size changed
+func NewCompactionDialog(report *app.CompactionReport) CompactionDialog {
+	items := make([]compactedItem, 0, len(report.Compacted))
+	for _, message := range report.Compacted {
+		items = append(items, compactedItem{message: message})
+	}
+	c := &compactionDialog{
+		report: report,
+		list:   list.NewListComponent(items, 12, "No messages were compacted", false),
+		modal: modal.New(
+			modal.WithTitle("Compaction Report"),
+			modal.WithMaxWidth(84),
+		),
+	}
+	c.setSize()
+	return c
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/charmbracelet/bubbles/v2/key"
 	"github.com/charmbracelet/bubbles/v2/textarea"
 	tea "github.com/charmbracelet/bubbletea/v2"
-	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/sst/dgmo/internal/app"
 	"github.com/sst/dgmo/internal/components/list"
 	"github.com/sst/dgmo/internal/styles"
@@ -202,7 +201,7 @@ func (c *completionDialogComponent) View() string {
 	return baseStyle.
 		Padding(0, 0).
 		Background(t.BackgroundElement()).
-		BorderStyle(lipgloss.ThickBorder()).
+		BorderStyle(styles.BlockBorder()).
 		BorderLeft(true).
 		BorderRight(true).
 		BorderForeground(t.Border()).
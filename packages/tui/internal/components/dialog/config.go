@@ -0,0 +1,115 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// ConfigDialog interface for the effective config dialog
+type ConfigDialog interface {
+	layout.Modal
+}
+
+type configDialog struct {
+	app      *app.App
+	width    int
+	modal    *modal.Modal
+	viewport viewport.Model
+}
+
+func (c *configDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (c *configDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+	case tea.WindowSizeMsg:
+		c.setSize()
+		c.render()
+	case app.ProjectConfigReloadedMsg:
+		c.render()
+	}
+
+	var cmd tea.Cmd
+	c.viewport, cmd = c.viewport.Update(msg)
+	return c, cmd
+}
+
+func (c *configDialog) setSize() {
+	c.width = layout.Current.Container.Width - 12
+	c.viewport.SetWidth(c.width)
+	c.viewport.SetHeight(max(5, layout.Current.Viewport.Height-16))
+}
+
+// render lists the settings with project overrides highlighted
+func (c *configDialog) render() {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+
+	settings := c.app.EffectiveConfig()
+	nameWidth := 0
+	for _, setting := range settings {
+		nameWidth = max(nameWidth, lipgloss.Width(setting.Name))
+	}
+
+	lines := make([]string, 0, len(settings))
+	for _, setting := range settings {
+		sourceStyle := base.Foreground(t.TextMuted())
+		if setting.Source == "project" {
+			sourceStyle = base.Foreground(t.Accent())
+		}
+		source := sourceStyle.Render(fmt.Sprintf(" %8s", setting.Source))
+		name := base.Foreground(t.TextMuted()).Render(fmt.Sprintf("%-*s  ", nameWidth, setting.Name))
+		valueWidth := max(0, c.width-lipgloss.Width(name)-lipgloss.Width(source))
+		value := truncate.StringWithTail(setting.Value, uint(valueWidth), "…")
+		value = base.Foreground(t.Text()).Width(valueWidth).Render(value)
+		lines = append(lines, name+value+source)
+	}
+	c.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func (c *configDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	overlay := "No project config, create " + c.app.ProjectConfigPath() + " to override settings"
+	if c.app.Project != nil {
+		overlay = "Project overrides from " + c.app.ProjectConfigPath()
+	}
+	header := muted.PaddingBottom(1).Render(truncate.StringWithTail(overlay, uint(c.width), "…"))
+	return header + "\n" + c.viewport.View()
+}
+
+func (c *configDialog) Render(background string) string {
+	return c.modal.Render(c.View(), background)
+}
+
+func (c *configDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewConfigDialog creates a dialog showing the user settings merged with the
+// project config overlay
+func NewConfigDialog(app *app.App) ConfigDialog {
+	c := &configDialog{
+		app:      app,
+		viewport: viewport.New(),
+		modal: modal.New(
+			modal.WithTitle("Config"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	c.setSize()
+	c.render()
+	return c
+}
@@ -0,0 +1,123 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/textarea"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// ContinuationAcceptedMsg is sent when the reviewed continuation prompt
+// should be sent to a fresh session
+type ContinuationAcceptedMsg struct {
+	Prompt string
+}
+
+// ContinuationDialog interface for reviewing a continuation prompt
+type ContinuationDialog interface {
+	layout.Modal
+}
+
+type continuationDialog struct {
+	response *app.ContinuationResponse
+	modal    *modal.Modal
+	textarea textarea.Model
+}
+
+func (c *continuationDialog) Init() tea.Cmd {
+	return c.textarea.Focus()
+}
+
+func (c *continuationDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.setSize()
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+s" {
+			prompt := strings.TrimSpace(c.textarea.Value())
+			if prompt == "" {
+				return c, toast.NewErrorToast("The continuation prompt is empty")
+			}
+			return c, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(ContinuationAcceptedMsg{Prompt: prompt}),
+			)
+		}
+	}
+
+	var cmd tea.Cmd
+	c.textarea, cmd = c.textarea.Update(msg)
+	return c, cmd
+}
+
+func (c *continuationDialog) setSize() {
+	c.textarea.SetWidth(layout.Current.Container.Width - 14)
+	c.textarea.SetHeight(max(5, layout.Current.Viewport.Height-14))
+}
+
+func (c *continuationDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	var header string
+	if c.response.ProjectName != "" {
+		header = fmt.Sprintf("%s, %.0f%% complete", c.response.ProjectName, c.response.CompletionPercentage)
+		if len(c.response.RemainingTasks) > 0 {
+			header += fmt.Sprintf(", %d tasks remaining", len(c.response.RemainingTasks))
+		}
+		header = muted.PaddingBottom(1).Render(header) + "\n"
+	}
+	help := muted.PaddingTop(1).Render("ctrl+s send to a new session · esc keep the current session")
+	return header + c.textarea.View() + "\n" + help
+}
+
+func (c *continuationDialog) Render(background string) string {
+	return c.modal.Render(c.View(), background)
+}
+
+func (c *continuationDialog) Close() tea.Cmd {
+	c.textarea.Blur()
+	return nil
+}
+
+// NewContinuationDialog creates a dialog to review and edit a continuation
+// prompt before it is sent to a fresh session
+func NewContinuationDialog(response *app.ContinuationResponse) ContinuationDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	ta := textarea.New()
+	ta.Styles.Blurred.Base = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.CursorLine = styles.NewStyle().Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Focused = ta.Styles.Blurred
+	ta.Styles.Cursor.Color = t.Primary()
+	ta.Prompt = ""
+	ta.ShowLineNumbers = false
+	ta.CharLimit = -1
+
+	c := &continuationDialog{
+		response: response,
+		textarea: ta,
+		modal: modal.New(
+			modal.WithTitle("Review Continuation Prompt"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	c.setSize()
+	c.textarea.SetValue(response.Prompt)
+	// review from the top
+	for range c.textarea.LineCount() {
+		c.textarea.CursorUp()
+	}
+	c.textarea.CursorStart()
+	return c
+}
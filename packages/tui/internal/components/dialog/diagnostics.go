@@ -0,0 +1,330 @@
+package dialog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// DiagnosticSeverity follows the LSP numbering, lower is more severe
+type DiagnosticSeverity int
+
+const (
+	SeverityError DiagnosticSeverity = iota + 1
+	SeverityWarning
+	SeverityInfo
+	SeverityHint
+)
+
+func (s DiagnosticSeverity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return "hint"
+}
+
+// FileDiagnostic is one LSP diagnostic, with 1-based positions
+type FileDiagnostic struct {
+	Line     int
+	Column   int
+	Severity DiagnosticSeverity
+	Message  string
+}
+
+// FileDiagnostics are the diagnostics of one file
+type FileDiagnostics struct {
+	FilePath    string
+	Diagnostics []FileDiagnostic
+}
+
+type lspDiagnostic struct {
+	Range struct {
+		Start struct {
+			Line      int `json:"line"`
+			Character int `json:"character"`
+		} `json:"start"`
+	} `json:"range"`
+	Severity int    `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// CollectDiagnostics gathers the diagnostics reported with tool results in
+// the given messages. Tools report every diagnostic of the files they
+// touched, so the latest report of a file replaces the earlier ones.
+func CollectDiagnostics(messages []opencode.Message) []FileDiagnostics {
+	latest := make(map[string][]FileDiagnostic)
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			toolCall, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok {
+				continue
+			}
+			metadata, ok := message.Metadata.Tool[toolCall.ToolInvocation.ToolCallID]
+			if !ok {
+				continue
+			}
+			files, ok := metadata.ExtraFields["diagnostics"].(map[string]any)
+			if !ok {
+				continue
+			}
+			for path, raw := range files {
+				data, err := json.Marshal(raw)
+				if err != nil {
+					continue
+				}
+				var reported []lspDiagnostic
+				if err := json.Unmarshal(data, &reported); err != nil {
+					continue
+				}
+				diagnostics := make([]FileDiagnostic, 0, len(reported))
+				for _, d := range reported {
+					severity := DiagnosticSeverity(d.Severity)
+					if severity < SeverityError || severity > SeverityHint {
+						severity = SeverityError
+					}
+					diagnostics = append(diagnostics, FileDiagnostic{
+						Line:     d.Range.Start.Line + 1,
+						Column:   d.Range.Start.Character + 1,
+						Severity: severity,
+						Message:  d.Message,
+					})
+				}
+				latest[path] = diagnostics
+			}
+		}
+	}
+
+	var result []FileDiagnostics
+	for path, diagnostics := range latest {
+		if len(diagnostics) == 0 {
+			continue
+		}
+		slices.SortFunc(diagnostics, func(a, b FileDiagnostic) int {
+			if a.Line != b.Line {
+				return a.Line - b.Line
+			}
+			return a.Column - b.Column
+		})
+		result = append(result, FileDiagnostics{FilePath: path, Diagnostics: diagnostics})
+	}
+	slices.SortFunc(result, func(a, b FileDiagnostics) int {
+		return strings.Compare(a.FilePath, b.FilePath)
+	})
+	return result
+}
+
+// DiagnosticsDialog interface for the session diagnostics panel
+type DiagnosticsDialog interface {
+	layout.Modal
+}
+
+// diagnosticRow is a selectable line of the panel
+type diagnosticRow struct {
+	file       string
+	diagnostic FileDiagnostic
+}
+
+type diagnosticsDialog struct {
+	width, height int
+	modal         *modal.Modal
+	viewport      viewport.Model
+	files         []FileDiagnostics
+	// shown holds the severities that pass the filter
+	shown    map[DiagnosticSeverity]bool
+	rows     []diagnosticRow
+	rowLines []int // viewport line of each row
+	selected int
+}
+
+func (d *diagnosticsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *diagnosticsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.setSize(msg.Width, msg.Height)
+		d.render()
+		return d, nil
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "up", "k":
+			d.selected = max(0, d.selected-1)
+			d.render()
+			return d, nil
+		case "down", "j":
+			d.selected = max(0, min(len(d.rows)-1, d.selected+1))
+			d.render()
+			return d, nil
+		case "1", "2", "3", "4":
+			severity := DiagnosticSeverity(msg.String()[0] - '0')
+			d.shown[severity] = !d.shown[severity]
+			d.selected = 0
+			d.render()
+			return d, nil
+		case "enter":
+			return d, d.openInEditor()
+		}
+	}
+
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+func (d *diagnosticsDialog) setSize(width, height int) {
+	d.width = max(40, width-12)
+	d.height = max(5, height-8)
+	d.viewport.SetWidth(d.width)
+	d.viewport.SetHeight(d.height)
+}
+
+// render lists the diagnostics that pass the filter grouped by file, and
+// keeps the selected one in view
+func (d *diagnosticsDialog) render() {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	colors := map[DiagnosticSeverity]styles.Style{
+		SeverityError:   base.Foreground(t.Error()),
+		SeverityWarning: base.Foreground(t.Warning()),
+		SeverityInfo:    base.Foreground(t.Info()),
+		SeverityHint:    muted,
+	}
+
+	d.rows = nil
+	d.rowLines = nil
+	var lines []string
+	for _, file := range d.files {
+		var rows []diagnosticRow
+		for _, diagnostic := range file.Diagnostics {
+			if d.shown[diagnostic.Severity] {
+				rows = append(rows, diagnosticRow{file: file.FilePath, diagnostic: diagnostic})
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, styles.FileLink(file.FilePath, base.Bold(true).Render(relativePath(file.FilePath))))
+		for _, row := range rows {
+			marker := "  "
+			style := base
+			if len(d.rows) == d.selected {
+				marker = "> "
+				style = base.Background(t.BackgroundPanel())
+			}
+			position := fmt.Sprintf("%d:%d", row.diagnostic.Line, row.diagnostic.Column)
+			line := marker +
+				colors[row.diagnostic.Severity].Render(fmt.Sprintf("%-7s", row.diagnostic.Severity)) + " " +
+				styles.FileLineLink(row.file, row.diagnostic.Line, muted.Render(fmt.Sprintf("%-8s", position))) + " " +
+				style.Render(strings.ReplaceAll(row.diagnostic.Message, "\n", " "))
+			d.rowLines = append(d.rowLines, len(lines))
+			d.rows = append(d.rows, row)
+			lines = append(lines, lipgloss.NewStyle().MaxWidth(d.width).Render(line))
+		}
+	}
+	if len(d.rows) == 0 {
+		lines = []string{muted.Render("No diagnostics match the filter")}
+	}
+	d.viewport.SetContent(strings.Join(lines, "\n"))
+
+	if d.selected < len(d.rowLines) {
+		line := d.rowLines[d.selected]
+		if line < d.viewport.YOffset {
+			d.viewport.SetYOffset(line)
+		} else if line >= d.viewport.YOffset+d.height {
+			d.viewport.SetYOffset(line - d.height + 1)
+		}
+	}
+}
+
+func (d *diagnosticsDialog) openInEditor() tea.Cmd {
+	if d.selected >= len(d.rows) {
+		return nil
+	}
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		return toast.NewErrorToast("No EDITOR set, can't open editor")
+	}
+	row := d.rows[d.selected]
+	c := exec.Command(editor, fmt.Sprintf("+%d", row.diagnostic.Line), row.file) //nolint:gosec
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			slog.Error("Failed to open editor", "error", err)
+		}
+		return nil
+	})
+}
+
+func (d *diagnosticsDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	if len(d.files) == 0 {
+		return muted.Render("No diagnostics reported in this session")
+	}
+
+	var filter []string
+	for severity := SeverityError; severity <= SeverityHint; severity++ {
+		mark := " "
+		if d.shown[severity] {
+			mark = "x"
+		}
+		filter = append(filter, fmt.Sprintf("%d [%s] %s", severity, mark, severity))
+	}
+	footer := muted.Width(d.width).Render(
+		strings.Join(filter, "  ") + " · ↑/↓ select · enter open in editor",
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, d.viewport.View(), "", footer)
+}
+
+func (d *diagnosticsDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *diagnosticsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewDiagnosticsDialog creates a panel with the latest LSP diagnostics of
+// every file the tools of the current session reported on. Errors and
+// warnings are shown until the filter says otherwise.
+func NewDiagnosticsDialog(app *app.App) DiagnosticsDialog {
+	d := &diagnosticsDialog{
+		viewport: viewport.New(),
+		files:    CollectDiagnostics(app.Messages),
+		shown: map[DiagnosticSeverity]bool{
+			SeverityError:   true,
+			SeverityWarning: true,
+		},
+		modal: modal.New(modal.WithTitle("Diagnostics")),
+	}
+	d.setSize(layout.Current.Viewport.Width, layout.Current.Viewport.Height)
+	d.render()
+	return d
+}
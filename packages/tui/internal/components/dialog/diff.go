@@ -0,0 +1,373 @@
+package dialog
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/diff"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/paths"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// collapseContext is the number of unchanged lines kept around each change
+// when unchanged regions are collapsed
+const collapseContext = 3
+
+// FileEdit is a single edit tool result with its unified diff
+type FileEdit struct {
+	MessageID  string
+	ToolCallID string
+	FilePath   string
+	Patch      string
+}
+
+// CollectFileEdits returns every edit tool result in the given messages, in order
+func CollectFileEdits(messages []opencode.Message) []FileEdit {
+	var edits []FileEdit
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			toolCall, ok := part.AsUnion().(opencode.ToolInvocationPart)
+			if !ok || toolCall.ToolInvocation.ToolName != "edit" {
+				continue
+			}
+			metadata, ok := message.Metadata.Tool[toolCall.ToolInvocation.ToolCallID]
+			if !ok {
+				continue
+			}
+			patch, ok := metadata.ExtraFields["diff"].(string)
+			if !ok || patch == "" {
+				continue
+			}
+			args, _ := toolCall.ToolInvocation.Args.(map[string]any)
+			filePath, _ := args["filePath"].(string)
+			edits = append(edits, FileEdit{
+				MessageID:  message.ID,
+				ToolCallID: toolCall.ToolInvocation.ToolCallID,
+				FilePath:   filePath,
+				Patch:      patch,
+			})
+		}
+	}
+	return edits
+}
+
+// DiffDialog interface for the full screen diff viewer
+type DiffDialog interface {
+	layout.Modal
+}
+
+type diffDialog struct {
+	width, height int
+	modal         *modal.Modal
+	viewport      viewport.Model
+	edits         []FileEdit
+	index         int
+	parsed        diff.DiffResult
+	sideBySide    bool
+	collapse      bool
+	hunk          int
+	hunkOffsets   []int
+}
+
+func (d *diffDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *diffDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.setSize(msg.Width, msg.Height)
+		d.render()
+		return d, nil
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "s":
+			d.sideBySide = !d.sideBySide
+			d.render()
+			d.gotoHunk(d.hunk)
+			return d, nil
+		case "c":
+			d.collapse = !d.collapse
+			d.render()
+			d.gotoHunk(d.hunk)
+			return d, nil
+		case "n":
+			d.gotoHunk(d.hunk + 1)
+			return d, nil
+		case "p":
+			d.gotoHunk(d.hunk - 1)
+			return d, nil
+		case "]":
+			d.load(d.index + 1)
+			return d, nil
+		case "[":
+			d.load(d.index - 1)
+			return d, nil
+		case "e":
+			return d, d.openInEditor()
+		}
+	}
+
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+func (d *diffDialog) setSize(width, height int) {
+	d.width = max(40, width-12)
+	d.height = max(5, height-8)
+	d.viewport.SetWidth(d.width)
+	d.viewport.SetHeight(d.height)
+}
+
+// load parses the edit at index and resets the view to its first hunk
+func (d *diffDialog) load(index int) {
+	if len(d.edits) == 0 {
+		return
+	}
+	index = max(0, min(index, len(d.edits)-1))
+	parsed, err := diff.ParseUnifiedDiff(d.edits[index].Patch)
+	if err != nil {
+		slog.Error("Failed to parse diff", "error", err)
+		return
+	}
+	d.index = index
+	d.parsed = parsed
+	d.hunk = 0
+	d.modal.SetTitle(d.title())
+	d.render()
+	d.viewport.GotoTop()
+}
+
+func (d *diffDialog) title() string {
+	edit := d.edits[d.index]
+	title := styles.FileLink(edit.FilePath, relativePath(edit.FilePath))
+	if len(d.edits) > 1 {
+		title = fmt.Sprintf("%s (%d/%d)", title, d.index+1, len(d.edits))
+	}
+	return title
+}
+
+// render draws every hunk into the viewport and records where each one starts
+func (d *diffDialog) render() {
+	if len(d.edits) == 0 {
+		return
+	}
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundElement()).
+		Width(d.width)
+	fileName := d.edits[d.index].FilePath
+
+	renderHunk := func(h diff.Hunk) string {
+		if d.sideBySide {
+			return diff.RenderSideBySideHunk(fileName, h, diff.WithTotalWidth(d.width))
+		}
+		return diff.RenderUnifiedHunk(fileName, h, diff.WithWidth(d.width))
+	}
+
+	var lines []string
+	d.hunkOffsets = make([]int, 0, len(d.parsed.Hunks))
+	for _, h := range d.parsed.Hunks {
+		d.hunkOffsets = append(d.hunkOffsets, len(lines))
+		lines = append(lines, muted.Render(h.Header))
+		for _, segment := range d.segments(h) {
+			if segment.skipped > 0 {
+				lines = append(lines, muted.Render(fmt.Sprintf("  ⋯ %d unchanged lines", segment.skipped)))
+				continue
+			}
+			rendered := strings.TrimSuffix(renderHunk(segment.hunk), "\n")
+			lines = append(lines, strings.Split(rendered, "\n")...)
+		}
+	}
+
+	d.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+type hunkSegment struct {
+	hunk    diff.Hunk
+	skipped int
+}
+
+// segments splits a hunk around long runs of unchanged lines when collapsing
+// is enabled, keeping collapseContext lines of context next to each change
+func (d *diffDialog) segments(h diff.Hunk) []hunkSegment {
+	if !d.collapse {
+		return []hunkSegment{{hunk: h}}
+	}
+
+	var result []hunkSegment
+	var current []diff.DiffLine
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, hunkSegment{hunk: diff.Hunk{Lines: current}})
+			current = nil
+		}
+	}
+
+	lines := h.Lines
+	for i := 0; i < len(lines); {
+		if lines[i].Kind != diff.LineContext {
+			current = append(current, lines[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(lines) && lines[end].Kind == diff.LineContext {
+			end++
+		}
+		run := lines[i:end]
+		keepBefore, keepAfter := collapseContext, collapseContext
+		if i == 0 {
+			keepBefore = 0
+		}
+		if end == len(lines) {
+			keepAfter = 0
+		}
+		if len(run) <= keepBefore+keepAfter+1 {
+			current = append(current, run...)
+		} else {
+			current = append(current, run[:keepBefore]...)
+			flush()
+			result = append(result, hunkSegment{skipped: len(run) - keepBefore - keepAfter})
+			current = append(current, run[len(run)-keepAfter:]...)
+		}
+		i = end
+	}
+	flush()
+	return result
+}
+
+func (d *diffDialog) gotoHunk(index int) {
+	if len(d.hunkOffsets) == 0 {
+		return
+	}
+	d.hunk = max(0, min(index, len(d.hunkOffsets)-1))
+	d.viewport.SetYOffset(d.hunkOffsets[d.hunk])
+}
+
+// hunkLine returns the first changed line of the current hunk in the new file
+func (d *diffDialog) hunkLine() int {
+	if d.hunk >= len(d.parsed.Hunks) {
+		return 1
+	}
+	lines := d.parsed.Hunks[d.hunk].Lines
+	for _, line := range lines {
+		if line.Kind != diff.LineContext && line.NewLineNo > 0 {
+			return line.NewLineNo
+		}
+	}
+	for _, line := range lines {
+		if line.NewLineNo > 0 {
+			return line.NewLineNo
+		}
+	}
+	return 1
+}
+
+func (d *diffDialog) openInEditor() tea.Cmd {
+	if len(d.edits) == 0 {
+		return nil
+	}
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		return toast.NewErrorToast("No EDITOR set, can't open editor")
+	}
+	c := exec.Command(editor, fmt.Sprintf("+%d", d.hunkLine()), d.edits[d.index].FilePath) //nolint:gosec
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			slog.Error("Failed to open editor", "error", err)
+		}
+		return nil
+	})
+}
+
+func (d *diffDialog) View() string {
+	t := theme.CurrentTheme()
+	if len(d.edits) == 0 {
+		return styles.NewStyle().
+			Foreground(t.TextMuted()).
+			Background(t.BackgroundElement()).
+			Render("No edits in this session")
+	}
+
+	mode := "unified"
+	if d.sideBySide {
+		mode = "side-by-side"
+	}
+	hint := fmt.Sprintf(
+		"hunk %d/%d · %s · s mode · c collapse · n/p hunk · [/] edit · e editor",
+		min(d.hunk+1, len(d.hunkOffsets)),
+		len(d.hunkOffsets),
+		mode,
+	)
+	footer := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundElement()).
+		Width(d.width).
+		Render(hint)
+
+	return lipgloss.JoinVertical(lipgloss.Left, d.viewport.View(), "", footer)
+}
+
+func (d *diffDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *diffDialog) Close() tea.Cmd {
+	return nil
+}
+
+func relativePath(path string) string {
+	return paths.Relative(path, app.CwdPath)
+}
+
+// focusedEdit returns the index of the edit made by the given tool call, or
+// else of the last edit in the given message, or else of the latest edit
+func focusedEdit(edits []FileEdit, messageID, toolCallID string) int {
+	if toolCallID != "" {
+		for i, edit := range edits {
+			if edit.ToolCallID == toolCallID {
+				return i
+			}
+		}
+	}
+	if messageID != "" {
+		for i := len(edits) - 1; i >= 0; i-- {
+			if edits[i].MessageID == messageID {
+				return i
+			}
+		}
+	}
+	return len(edits) - 1
+}
+
+// NewDiffDialog opens the diff viewer on the edit made by the given tool
+// call, or on the last edit of the given message. Either may be empty, and
+// without a match the viewer opens on the most recent edit in the session.
+func NewDiffDialog(app *app.App, messageID, toolCallID string) DiffDialog {
+	d := &diffDialog{
+		modal:    modal.New(modal.WithTitle("Diff")),
+		viewport: viewport.New(),
+		edits:    CollectFileEdits(app.Messages),
+	}
+	d.setSize(layout.Current.Viewport.Width, layout.Current.Viewport.Height)
+	d.load(focusedEdit(d.edits, messageID, toolCallID))
+	return d
+}
@@ -0,0 +1,190 @@
+package dialog
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/textarea"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// EditMessageDialog interface for editing a sent message to resend it
+type EditMessageDialog interface {
+	layout.Modal
+}
+
+type promptItem struct {
+	message opencode.Message
+}
+
+func (p promptItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	textStyle := baseStyle.Foreground(t.Text())
+	timeStyle := baseStyle.Foreground(t.TextMuted())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.Primary())
+		textStyle = baseStyle.Foreground(t.BackgroundElement()).Bold(true)
+		timeStyle = baseStyle.Foreground(t.BackgroundElement())
+	}
+
+	created := time.UnixMilli(int64(p.message.Metadata.Time.Created)).Local()
+	when := timeStyle.Render(created.Format("15:04") + " ")
+	text := " " + strings.Join(strings.Fields(app.PromptText(p.message)), " ")
+	text = truncate.StringWithTail(text, uint(max(0, width-lipgloss.Width(when)-1)), "…")
+	left := textStyle.Render(text)
+	gap := max(1, width-lipgloss.Width(left)-lipgloss.Width(when))
+	return left + baseStyle.Render(strings.Repeat(" ", gap)) + when
+}
+
+func (p promptItem) FilterValue() string {
+	return app.PromptText(p.message)
+}
+
+type editMessageDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	list     list.List[promptItem]
+	textarea textarea.Model
+
+	// edit stage, the message picked from the list
+	editing   bool
+	messageID string
+	err       error
+}
+
+func (d *editMessageDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *editMessageDialog) refresh() {
+	prompts := app.UserMessages(d.app.Messages)
+	items := make([]promptItem, 0, len(prompts))
+	for _, message := range prompts {
+		items = append(items, promptItem{message: message})
+	}
+	d.list.SetItems(items)
+}
+
+// edit starts editing a sent message
+func (d *editMessageDialog) edit(message opencode.Message) tea.Cmd {
+	d.editing = true
+	d.messageID = message.ID
+	d.err = nil
+	d.textarea.SetValue(app.PromptText(message))
+	return d.textarea.Focus()
+}
+
+// resend asks to confirm resending the edited message
+func (d *editMessageDialog) resend() tea.Cmd {
+	request, err := app.EditRequest(d.app.Messages, d.messageID, d.textarea.Value())
+	if err != nil {
+		d.err = err
+		return nil
+	}
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(app.ResendRequestedMsg{Request: request}),
+	)
+}
+
+func (d *editMessageDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.setSize()
+	case tea.KeyPressMsg:
+		switch {
+		case d.editing && msg.String() == "ctrl+s":
+			return d, d.resend()
+		case !d.editing && msg.String() == "enter":
+			if item, idx := d.list.GetSelectedItem(); idx >= 0 {
+				return d, d.edit(item.message)
+			}
+			return d, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if d.editing {
+		d.textarea, cmd = d.textarea.Update(msg)
+		return d, cmd
+	}
+	listModel, cmd := d.list.Update(msg)
+	d.list = listModel.(list.List[promptItem])
+	return d, cmd
+}
+
+func (d *editMessageDialog) setSize() {
+	d.list.SetMaxWidth(layout.Current.Container.Width - 12)
+	d.textarea.SetWidth(layout.Current.Container.Width - 14)
+	d.textarea.SetHeight(max(5, min(12, layout.Current.Viewport.Height-14)))
+}
+
+func (d *editMessageDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if !d.editing {
+		help := muted.PaddingTop(1).Render("enter edit · type to filter · esc cancel")
+		return d.list.View() + "\n" + help
+	}
+	lines := []string{d.textarea.View()}
+	if d.err != nil {
+		lines = append(lines, styles.NewStyle().Foreground(t.Error()).Background(t.BackgroundElement()).PaddingTop(1).Render(d.err.Error()))
+	}
+	lines = append(lines, muted.PaddingTop(1).Render("ctrl+s resend from here · esc cancel"))
+	return strings.Join(lines, "\n")
+}
+
+func (d *editMessageDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *editMessageDialog) Close() tea.Cmd {
+	d.textarea.Blur()
+	return nil
+}
+
+// NewEditMessageDialog creates a dialog that lists the prompts of the
+// session, newest first, to edit one and resend the session from it
+func NewEditMessageDialog(app *app.App) EditMessageDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	prompts := list.NewListComponent([]promptItem{}, 10, "No messages sent yet", false)
+	prompts.SetFilterable(true)
+
+	ta := textarea.New()
+	ta.Styles.Blurred.Base = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.CursorLine = styles.NewStyle().Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	ta.Styles.Focused = ta.Styles.Blurred
+	ta.Styles.Cursor.Color = t.Primary()
+	ta.Prompt = ""
+	ta.ShowLineNumbers = false
+	ta.CharLimit = -1
+
+	d := &editMessageDialog{
+		app:      app,
+		list:     prompts,
+		textarea: ta,
+		modal: modal.New(
+			modal.WithTitle("Edit Message"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.setSize()
+	d.refresh()
+	return d
+}
@@ -0,0 +1,261 @@
+package dialog
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// EnvDialog interface for editing the environment variables of a session
+type EnvDialog interface {
+	layout.Modal
+}
+
+type envItem struct {
+	v app.EnvVar
+}
+
+func (e envItem) Render(selected bool, width int) string {
+	th := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(th.BackgroundElement())
+	if selected {
+		baseStyle = styles.NewStyle().Background(th.BackgroundPanel())
+	}
+
+	nameStyle := baseStyle.Foreground(th.Text())
+	if selected {
+		nameStyle = nameStyle.Foreground(th.Primary()).Bold(true)
+	}
+	name := nameStyle.Render(" " + e.v.Name)
+	valueWidth := max(0, width-lipgloss.Width(name)-1)
+	value := baseStyle.Foreground(th.TextMuted()).Render("=" + truncate.StringWithTail(app.MaskedEnvValue(e.v), uint(valueWidth), "…"))
+	line := name + value
+	gap := max(0, width-lipgloss.Width(line))
+	return line + baseStyle.Render(strings.Repeat(" ", gap))
+}
+
+func (e envItem) FilterValue() string {
+	return e.v.Name
+}
+
+type envResultMsg struct {
+	session *opencode.Session
+	err     error
+}
+
+type envDialog struct {
+	app       *app.App
+	sessionID string
+	modal     *modal.Modal
+	list      list.List[envItem]
+	vars      []app.EnvVar
+	saving    bool
+
+	// edit stage, active while a variable is added or edited
+	editing  bool
+	previous string // name of the edited variable, empty when adding
+	name     textinput.Model
+	value    textinput.Model
+	err      error
+}
+
+func (d *envDialog) Init() tea.Cmd {
+	return nil
+}
+
+// load lists the variables of a session
+func (d *envDialog) load(session *opencode.Session) {
+	d.vars = app.SessionEnv(session)
+	items := make([]envItem, 0, len(d.vars))
+	for _, v := range d.vars {
+		items = append(items, envItem{v: v})
+	}
+	d.list.SetItems(items)
+}
+
+// edit starts editing a variable, a new one when v is empty
+func (d *envDialog) edit(v app.EnvVar) tea.Cmd {
+	d.editing = true
+	d.previous = v.Name
+	d.err = nil
+	d.name.SetValue(v.Name)
+	d.value.SetValue(v.Value)
+	d.maskValue()
+	d.value.Blur()
+	return d.name.Focus()
+}
+
+// maskValue hides the value being typed when the name looks like a secret
+func (d *envDialog) maskValue() {
+	d.value.EchoMode = textinput.EchoNormal
+	if app.SecretEnv(app.EnvVar{Name: d.name.Value()}) {
+		d.value.EchoMode = textinput.EchoPassword
+	}
+}
+
+// save sends the variables to the server, they apply from the next command
+// the bash tool runs
+func (d *envDialog) save(vars []app.EnvVar) tea.Cmd {
+	d.saving = true
+	sessionID := d.sessionID
+	return func() tea.Msg {
+		session, err := d.app.SetSessionEnv(context.Background(), sessionID, vars)
+		return envResultMsg{session: session, err: err}
+	}
+}
+
+func (d *envDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case envResultMsg:
+		d.saving = false
+		if msg.err != nil {
+			return d, toast.NewErrorToast(msg.err.Error())
+		}
+		d.editing = false
+		d.name.Blur()
+		d.value.Blur()
+		d.load(msg.session)
+		return d, util.CmdHandler(app.SessionEnvChangedMsg{Session: *msg.session})
+	case tea.WindowSizeMsg:
+		d.list.SetMaxWidth(layout.Current.Container.Width - 12)
+		d.name.SetWidth(layout.Current.Container.Width - 14)
+		d.value.SetWidth(layout.Current.Container.Width - 14)
+	case tea.KeyPressMsg:
+		if d.saving {
+			return d, nil
+		}
+		if d.editing {
+			switch msg.String() {
+			case "enter":
+				vars, err := app.SetEnvVar(d.vars, d.previous, app.EnvVar{Name: d.name.Value(), Value: d.value.Value()})
+				if err != nil {
+					d.err = err
+					return d, nil
+				}
+				return d, d.save(vars)
+			case "tab", "shift+tab":
+				if d.name.Focused() {
+					d.name.Blur()
+					return d, d.value.Focus()
+				}
+				d.value.Blur()
+				return d, d.name.Focus()
+			}
+			var cmd tea.Cmd
+			if d.name.Focused() {
+				d.name, cmd = d.name.Update(msg)
+				d.maskValue()
+			} else {
+				d.value, cmd = d.value.Update(msg)
+			}
+			return d, cmd
+		}
+		switch msg.String() {
+		case "enter":
+			if item, idx := d.list.GetSelectedItem(); idx >= 0 {
+				return d, d.edit(item.v)
+			}
+			return d, nil
+		case "ctrl+a":
+			return d, d.edit(app.EnvVar{})
+		case "ctrl+d", "delete":
+			item, idx := d.list.GetSelectedItem()
+			if idx < 0 {
+				return d, nil
+			}
+			return d, d.save(slices.DeleteFunc(slices.Clone(d.vars), func(v app.EnvVar) bool {
+				return v.Name == item.v.Name
+			}))
+		}
+	case tea.PasteMsg:
+		if d.editing {
+			var cmd tea.Cmd
+			if d.name.Focused() {
+				d.name, cmd = d.name.Update(msg)
+				d.maskValue()
+			} else {
+				d.value, cmd = d.value.Update(msg)
+			}
+			return d, cmd
+		}
+	}
+
+	if d.editing {
+		return d, nil
+	}
+	listModel, cmd := d.list.Update(msg)
+	d.list = listModel.(list.List[envItem])
+	return d, cmd
+}
+
+func (d *envDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if d.saving {
+		return muted.Render("Saving...")
+	}
+	if d.editing {
+		label := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+		lines := []string{
+			label.Render("Name"), d.name.View(),
+			label.PaddingTop(1).Render("Value"), d.value.View(),
+		}
+		if d.err != nil {
+			lines = append(lines, styles.NewStyle().Foreground(t.Error()).Background(t.BackgroundElement()).PaddingTop(1).Render(d.err.Error()))
+		}
+		lines = append(lines, muted.PaddingTop(1).Render("tab next field · enter save · esc close"))
+		return strings.Join(lines, "\n")
+	}
+
+	help := muted.PaddingTop(1).Width(layout.Current.Container.Width - 12).Render(
+		"enter edit · ctrl+a add · ctrl+d delete · set for the bash commands of this session only",
+	)
+	return d.list.View() + "\n" + help
+}
+
+func (d *envDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *envDialog) Close() tea.Cmd {
+	d.name.Blur()
+	d.value.Blur()
+	return nil
+}
+
+// NewEnvDialog creates a dialog to add, edit and delete the environment
+// variables the server sets for the bash tool of a session
+func NewEnvDialog(app *app.App, session *opencode.Session) EnvDialog {
+	vars := list.NewListComponent([]envItem{}, 10, "No variables, ctrl+a adds one", false)
+	vars.SetFilterable(true)
+	vars.SetMaxWidth(layout.Current.Container.Width - 12)
+
+	d := &envDialog{
+		app:       app,
+		sessionID: session.ID,
+		list:      vars,
+		name:      newFieldInput("TEST_DATABASE_URL"),
+		value:     newFieldInput("postgres://localhost/test"),
+		modal: modal.New(
+			modal.WithTitle("Session Environment"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.load(session)
+	return d
+}
@@ -0,0 +1,190 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// historyPreviewLines is how many lines of the selected prompt are previewed
+const historyPreviewLines = 6
+
+// HistorySearchDialog interface for searching the prompts sent before
+type HistorySearchDialog interface {
+	layout.Modal
+}
+
+// HistorySelectedMsg is sent with a prompt picked from the history, to be
+// put into the editor
+type HistorySelectedMsg struct {
+	Text string
+}
+
+type historyItem struct {
+	entry app.HistoryEntry
+}
+
+func (h historyItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	textStyle := baseStyle.Foreground(t.Text())
+	timeStyle := baseStyle.Foreground(t.TextMuted())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.Primary())
+		textStyle = baseStyle.Foreground(t.BackgroundElement()).Bold(true)
+		timeStyle = baseStyle.Foreground(t.BackgroundElement())
+	}
+
+	when := timeStyle.Render(h.entry.Time.Format("Jan 2 15:04") + " ")
+	text := " " + strings.Join(strings.Fields(h.entry.Text), " ")
+	text = truncate.StringWithTail(text, uint(max(0, width-lipgloss.Width(when)-1)), "…")
+	left := textStyle.Render(text)
+	gap := max(1, width-lipgloss.Width(left)-lipgloss.Width(when))
+	return left + baseStyle.Render(strings.Repeat(" ", gap)) + when
+}
+
+type historySearchDialog struct {
+	app   *app.App
+	modal *modal.Modal
+	input textinput.Model
+	list  list.List[historyItem]
+	query string
+	width int
+}
+
+func (h *historySearchDialog) Init() tea.Cmd {
+	return h.input.Focus()
+}
+
+// search lists the prompts matching the query, closest first
+func (h *historySearchDialog) search(force bool) {
+	query := strings.TrimSpace(h.input.Value())
+	if query == h.query && !force {
+		return
+	}
+	h.query = query
+	entries := h.app.History.Search(query)
+	items := make([]historyItem, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, historyItem{entry: entry})
+	}
+	h.list.SetItems(items)
+}
+
+func (h *historySearchDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h.setSize()
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "enter":
+			item, idx := h.list.GetSelectedItem()
+			if idx < 0 {
+				return h, nil
+			}
+			return h, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(HistorySelectedMsg{Text: item.entry.Text}),
+			)
+		case "up", "down", "ctrl+p", "ctrl+n", "pgup", "pgdown":
+			listModel, cmd := h.list.Update(msg)
+			h.list = listModel.(list.List[historyItem])
+			return h, cmd
+		// ctrl+r again moves on to the next older match, as in a shell
+		case "ctrl+r":
+			listModel, cmd := h.list.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+			h.list = listModel.(list.List[historyItem])
+			return h, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	h.input, cmd = h.input.Update(msg)
+	h.search(false)
+	return h, cmd
+}
+
+func (h *historySearchDialog) setSize() {
+	h.width = min(90, layout.Current.Container.Width-12)
+	h.input.SetWidth(h.width - 2)
+	h.list.SetMaxWidth(h.width)
+}
+
+func (h *historySearchDialog) View() string {
+	t := theme.CurrentTheme()
+	prompt := styles.NewStyle().
+		Foreground(t.Primary()).
+		Background(t.BackgroundElement()).
+		Render("> ")
+	view := prompt + h.input.View() + "\n\n" + h.list.View()
+
+	item, idx := h.list.GetSelectedItem()
+	if idx < 0 {
+		return view
+	}
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	lines := strings.Split(strings.TrimSpace(item.entry.Text), "\n")
+	if len(lines) > historyPreviewLines {
+		hidden := len(lines) - historyPreviewLines
+		lines = append(lines[:historyPreviewLines], fmt.Sprintf("… %d more lines", hidden))
+	}
+	for i, line := range lines {
+		lines[i] = truncate.StringWithTail(line, uint(h.width), "…")
+	}
+	preview := styles.NewStyle().
+		Foreground(t.Text()).
+		Background(t.BackgroundPanel()).
+		Width(h.width).
+		Render(strings.Join(lines, "\n"))
+	help := muted.Render("enter insert · ctrl+r older match · " + item.entry.SessionID)
+	return view + "\n\n" + preview + "\n" + help
+}
+
+func (h *historySearchDialog) Render(background string) string {
+	return h.modal.Render(h.View(), background)
+}
+
+func (h *historySearchDialog) Close() tea.Cmd {
+	h.input.Blur()
+	return nil
+}
+
+// NewHistorySearchDialog creates a dialog that fuzzy-searches the prompts
+// sent from any session, newest first
+func NewHistorySearchDialog(app *app.App) HistorySearchDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "Search previous prompts"
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred.Text = input.Styles.Focused.Text
+	input.Styles.Blurred.Placeholder = input.Styles.Focused.Placeholder
+	input.Styles.Cursor.Color = t.Primary()
+
+	h := &historySearchDialog{
+		app:   app,
+		input: input,
+		list:  list.NewListComponent([]historyItem{}, 10, "No matching prompts", false),
+		modal: modal.New(
+			modal.WithTitle("Prompt history"),
+			modal.WithMaxWidth(94),
+		),
+	}
+	h.setSize()
+	h.search(true)
+	return h
+}
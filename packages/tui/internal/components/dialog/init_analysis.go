@@ -0,0 +1,100 @@
+package dialog
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/textarea"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// InitAnalysisDialog interface for reviewing the project analysis before
+// AGENTS.md is generated
+type InitAnalysisDialog interface {
+	layout.Modal
+}
+
+type initAnalysisDialog struct {
+	modal    *modal.Modal
+	textarea textarea.Model
+}
+
+func (d *initAnalysisDialog) Init() tea.Cmd {
+	return d.textarea.Focus()
+}
+
+func (d *initAnalysisDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.setSize()
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+s" {
+			return d, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(app.ProjectInitConfirmedMsg{Analysis: strings.TrimSpace(d.textarea.Value())}),
+			)
+		}
+	}
+	var cmd tea.Cmd
+	d.textarea, cmd = d.textarea.Update(msg)
+	return d, cmd
+}
+
+func (d *initAnalysisDialog) setSize() {
+	d.textarea.SetWidth(layout.Current.Container.Width - 14)
+	d.textarea.SetHeight(max(5, min(14, layout.Current.Viewport.Height-16)))
+}
+
+func (d *initAnalysisDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	intro := muted.Width(layout.Current.Container.Width - 14).PaddingBottom(1).Render(
+		"Found in the project files. Correct or add to it, it is sent along when AGENTS.md is generated.",
+	)
+	help := muted.PaddingTop(1).Render("ctrl+s initialize · esc cancel")
+	return strings.Join([]string{intro, d.textarea.View(), help}, "\n")
+}
+
+func (d *initAnalysisDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *initAnalysisDialog) Close() tea.Cmd {
+	d.textarea.Blur()
+	return nil
+}
+
+// NewInitAnalysisDialog creates a dialog that shows what a local analysis
+// found about the project, for the user to correct before initializing
+func NewInitAnalysisDialog(analysis app.ProjectAnalysis) InitAnalysisDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	ta := textarea.New()
+	ta.Styles.Blurred.Base = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.CursorLine = styles.NewStyle().Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	ta.Styles.Focused = ta.Styles.Blurred
+	ta.Styles.Cursor.Color = t.Primary()
+	ta.Prompt = ""
+	ta.ShowLineNumbers = false
+	ta.CharLimit = -1
+	ta.Placeholder = "Nothing recognized, describe the languages and the build and test commands"
+	ta.SetValue(analysis.Text())
+
+	d := &initAnalysisDialog{
+		textarea: ta,
+		modal: modal.New(
+			modal.WithTitle("Initialize Project"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.setSize()
+	return d
+}
@@ -0,0 +1,454 @@
+package dialog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// InspectDialog interface for the raw message JSON inspector
+type InspectDialog interface {
+	layout.Modal
+}
+
+type inspectMessageItem struct {
+	message opencode.Message
+}
+
+func (i inspectMessageItem) summary() string {
+	for _, part := range i.message.Parts {
+		if text, ok := part.AsUnion().(opencode.TextPart); ok && strings.TrimSpace(text.Text) != "" {
+			return strings.Join(strings.Fields(text.Text), " ")
+		}
+	}
+	return fmt.Sprintf("%d parts", len(i.message.Parts))
+}
+
+func (i inspectMessageItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle()
+
+	created := time.UnixMilli(int64(i.message.Metadata.Time.Created)).Local().Format("15:04:05")
+	text := fmt.Sprintf("%-9s %s  %s", i.message.Role, created, i.summary())
+	truncatedStr := truncate.StringWithTail(text, uint(width-1), "...")
+
+	if selected {
+		return baseStyle.
+			Background(t.Primary()).
+			Foreground(t.BackgroundElement()).
+			Width(width).
+			PaddingLeft(1).
+			Render(truncatedStr)
+	}
+	return baseStyle.
+		Foreground(t.Text()).
+		PaddingLeft(1).
+		Render(truncatedStr)
+}
+
+func (i inspectMessageItem) FilterValue() string {
+	return string(i.message.Role) + " " + i.message.ID + " " + i.summary()
+}
+
+// jsonNode is a single value of a decoded JSON document, keeping the key
+// order of the original payload
+type jsonNode struct {
+	key       string // empty for array elements and the root
+	index     int    // position within a parent array, -1 otherwise
+	kind      json.Delim
+	scalar    string // raw scalar value when kind is 0
+	children  []*jsonNode
+	collapsed bool
+	extra     bool // not part of the SDK struct, only reachable via ExtraFields
+	depth     int
+}
+
+// parseJSONTree decodes raw into an ordered tree of nodes
+func parseJSONTree(raw string) (*jsonNode, error) {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	root, err := decodeJSONNode(decoder, 0)
+	if err != nil {
+		return nil, err
+	}
+	root.index = -1
+	return root, nil
+}
+
+func decodeJSONNode(decoder *json.Decoder, depth int) (*jsonNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &jsonNode{depth: depth, index: -1}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		encoded, _ := json.Marshal(token)
+		node.scalar = string(encoded)
+		return node, nil
+	}
+	node.kind = delim
+
+	for decoder.More() {
+		key := ""
+		if delim == '{' {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ = keyToken.(string)
+		}
+		child, err := decodeJSONNode(decoder, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		child.key = key
+		if delim == '[' {
+			child.index = len(node.children)
+		}
+		node.children = append(node.children, child)
+	}
+	// consume the closing delimiter
+	if _, err := decoder.Token(); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return node, nil
+}
+
+// markExtraFields flags the nodes whose path is one of the given extra paths
+func (n *jsonNode) markExtraFields(path string, extras map[string]bool) {
+	for _, child := range n.children {
+		childPath := child.key
+		if child.index >= 0 {
+			childPath = fmt.Sprint(child.index)
+		}
+		if path != "" {
+			childPath = path + "." + childPath
+		}
+		child.extra = extras[childPath]
+		child.markExtraFields(childPath, extras)
+	}
+}
+
+// flatten returns the nodes that are currently visible, in display order
+func (n *jsonNode) flatten(out []*jsonNode) []*jsonNode {
+	out = append(out, n)
+	if n.kind != 0 && !n.collapsed {
+		for _, child := range n.children {
+			out = child.flatten(out)
+		}
+	}
+	return out
+}
+
+func (n *jsonNode) setCollapsed(collapsed bool) {
+	for _, child := range n.children {
+		child.setCollapsed(collapsed)
+	}
+	if n.kind != 0 && n.depth > 0 {
+		n.collapsed = collapsed
+	}
+}
+
+// messageExtraFieldPaths returns the dotted paths of every field the SDK
+// decoded into ExtraFields rather than a typed struct field
+func messageExtraFieldPaths(message opencode.Message) map[string]bool {
+	extras := map[string]bool{}
+	for key := range message.JSON.ExtraFields {
+		extras[key] = true
+	}
+	for key := range message.Metadata.JSON.ExtraFields {
+		extras["metadata."+key] = true
+	}
+	for key := range message.Metadata.Assistant.JSON.ExtraFields {
+		extras["metadata.assistant."+key] = true
+	}
+	for id, tool := range message.Metadata.Tool {
+		for key := range tool.ExtraFields {
+			extras["metadata.tool."+id+"."+key] = true
+		}
+	}
+	return extras
+}
+
+// messageRawJSON returns the payload the message was decoded from, falling
+// back to re-encoding messages that were built locally
+func messageRawJSON(message opencode.Message) string {
+	if raw := message.JSON.RawJSON(); raw != "" {
+		return raw
+	}
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return "{}"
+	}
+	return string(encoded)
+}
+
+type inspectDialog struct {
+	width, height int
+	app           *app.App
+	modal         *modal.Modal
+	list          list.List[inspectMessageItem]
+
+	inspecting *opencode.Message
+	root       *jsonNode
+	pretty     string
+	cursor     int
+	offset     int
+	parseErr   error
+}
+
+func (d *inspectDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *inspectDialog) inspect(message opencode.Message) {
+	raw := messageRawJSON(message)
+	d.inspecting = &message
+	d.cursor, d.offset = 0, 0
+	d.modal.SetTitle("Message " + message.ID)
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(raw), "", "  "); err == nil {
+		d.pretty = buf.String()
+	} else {
+		d.pretty = raw
+	}
+
+	d.root, d.parseErr = parseJSONTree(raw)
+	if d.parseErr == nil {
+		d.root.markExtraFields("", messageExtraFieldPaths(message))
+	}
+}
+
+func (d *inspectDialog) back() {
+	d.inspecting = nil
+	d.root = nil
+	d.modal.SetTitle("Inspect Message")
+}
+
+func (d *inspectDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.setSize()
+	case tea.KeyPressMsg:
+		if d.inspecting != nil {
+			return d, d.updateTree(msg)
+		}
+		if msg.String() == "enter" {
+			if item, idx := d.list.GetSelectedItem(); idx >= 0 {
+				d.inspect(item.message)
+			}
+			return d, nil
+		}
+	}
+
+	listModel, cmd := d.list.Update(msg)
+	d.list = listModel.(list.List[inspectMessageItem])
+	return d, cmd
+}
+
+func (d *inspectDialog) updateTree(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "backspace":
+		d.back()
+		return nil
+	case "c", "y":
+		return tea.Batch(
+			tea.SetClipboard(d.pretty),
+			toast.NewSuccessToast("Message JSON copied to clipboard"),
+		)
+	}
+	if d.root == nil {
+		return nil
+	}
+
+	nodes := d.root.flatten(nil)
+	current := nodes[min(d.cursor, len(nodes)-1)]
+	switch msg.String() {
+	case "up", "k":
+		d.cursor = max(0, d.cursor-1)
+	case "down", "j":
+		d.cursor = min(len(nodes)-1, d.cursor+1)
+	case "pgup":
+		d.cursor = max(0, d.cursor-d.height)
+	case "pgdown":
+		d.cursor = min(len(nodes)-1, d.cursor+d.height)
+	case "home", "g":
+		d.cursor = 0
+	case "end", "G":
+		d.cursor = len(nodes) - 1
+	case "enter", "space", " ":
+		if current.kind != 0 && current.depth > 0 {
+			current.collapsed = !current.collapsed
+		}
+	case "left", "h":
+		if current.kind != 0 && current.depth > 0 {
+			current.collapsed = true
+		}
+	case "right", "l":
+		current.collapsed = false
+	case "-":
+		d.root.setCollapsed(true)
+		d.cursor = 0
+	case "+", "=":
+		d.root.setCollapsed(false)
+	}
+	d.scrollToCursor()
+	return nil
+}
+
+func (d *inspectDialog) scrollToCursor() {
+	if d.cursor < d.offset {
+		d.offset = d.cursor
+	}
+	if d.cursor >= d.offset+d.height {
+		d.offset = d.cursor - d.height + 1
+	}
+}
+
+func (d *inspectDialog) setSize() {
+	d.width = layout.Current.Container.Width - 12
+	d.height = max(5, layout.Current.Viewport.Height-14)
+	d.list.SetMaxWidth(d.width)
+}
+
+// renderNode renders a single line of the JSON tree
+func (d *inspectDialog) renderNode(node *jsonNode, selected bool) string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+	punct := base.Foreground(t.SyntaxPunctuation())
+
+	var line strings.Builder
+	line.WriteString(strings.Repeat("  ", node.depth))
+
+	if node.kind != 0 && node.depth > 0 {
+		marker := "▾ "
+		if node.collapsed {
+			marker = "▸ "
+		}
+		line.WriteString(base.Foreground(t.TextMuted()).Render(marker))
+	} else {
+		line.WriteString(base.Render("  "))
+	}
+
+	if node.key != "" {
+		keyStyle := base.Foreground(t.SyntaxKeyword())
+		if node.extra {
+			keyStyle = base.Foreground(t.Warning()).Bold(true)
+		}
+		line.WriteString(keyStyle.Render(fmt.Sprintf("%q", node.key)))
+		line.WriteString(punct.Render(": "))
+	}
+
+	switch node.kind {
+	case '{', '[':
+		closing := "}"
+		if node.kind == '[' {
+			closing = "]"
+		}
+		if node.collapsed {
+			line.WriteString(punct.Render(string(node.kind) + "…" + closing))
+			line.WriteString(base.Foreground(t.TextMuted()).Render(fmt.Sprintf(" %d items", len(node.children))))
+		} else {
+			line.WriteString(punct.Render(string(node.kind)))
+		}
+	default:
+		valueStyle := base.Foreground(t.SyntaxNumber())
+		switch {
+		case strings.HasPrefix(node.scalar, `"`):
+			valueStyle = base.Foreground(t.SyntaxString())
+		case node.scalar == "true" || node.scalar == "false" || node.scalar == "null":
+			valueStyle = base.Foreground(t.SyntaxType())
+		}
+		line.WriteString(valueStyle.Render(node.scalar))
+	}
+
+	if node.extra {
+		line.WriteString(base.Foreground(t.Warning()).Render("  extra"))
+	}
+
+	rendered := truncate.StringWithTail(line.String(), uint(d.width), "…")
+	if selected {
+		return styles.NewStyle().Background(t.BackgroundPanel()).Width(d.width).Render(rendered)
+	}
+	return rendered
+}
+
+func (d *inspectDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if d.inspecting == nil {
+		help := muted.PaddingTop(1).Render("enter inspect · type to filter")
+		return d.list.View() + "\n" + help
+	}
+
+	if d.parseErr != nil {
+		lines := strings.Split(d.pretty, "\n")
+		body := strings.Join(lines[:min(len(lines), d.height)], "\n")
+		return body + "\n" + muted.PaddingTop(1).Render("invalid JSON: "+d.parseErr.Error())
+	}
+
+	nodes := d.root.flatten(nil)
+	d.cursor = min(d.cursor, len(nodes)-1)
+	d.scrollToCursor()
+	end := min(d.offset+d.height, len(nodes))
+
+	lines := make([]string, 0, end-d.offset)
+	for i := d.offset; i < end; i++ {
+		lines = append(lines, d.renderNode(nodes[i], i == d.cursor))
+	}
+	help := muted.PaddingTop(1).Render("enter fold · -/+ fold all · c copy · backspace back")
+	return strings.Join(lines, "\n") + "\n" + help
+}
+
+func (d *inspectDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *inspectDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewInspectDialog creates a dialog to inspect the raw SDK payload of the
+// messages in the current session, starting at selectedID if it is set
+func NewInspectDialog(app *app.App, selectedID string) InspectDialog {
+	items := make([]inspectMessageItem, 0, len(app.Messages))
+	selected := 0
+	for i := len(app.Messages) - 1; i >= 0; i-- {
+		if app.Messages[i].ID == selectedID {
+			selected = len(items)
+		}
+		items = append(items, inspectMessageItem{message: app.Messages[i]})
+	}
+	messages := list.NewListComponent(items, 10, "No messages in this session", true)
+	messages.SetFilterable(true)
+	messages.SetSelectedIndex(selected)
+
+	d := &inspectDialog{
+		app:  app,
+		list: messages,
+		modal: modal.New(
+			modal.WithTitle("Inspect Message"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.setSize()
+	return d
+}
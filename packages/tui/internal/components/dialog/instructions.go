@@ -0,0 +1,135 @@
+package dialog
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/textarea"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// InstructionsDialog interface for editing the instructions of a session
+type InstructionsDialog interface {
+	layout.Modal
+}
+
+type instructionsResultMsg struct {
+	session *opencode.Session
+	err     error
+}
+
+type instructionsDialog struct {
+	app       *app.App
+	sessionID string
+	modal     *modal.Modal
+	textarea  textarea.Model
+	saving    bool
+}
+
+func (d *instructionsDialog) Init() tea.Cmd {
+	return d.textarea.Focus()
+}
+
+func (d *instructionsDialog) save(instructions string) tea.Cmd {
+	d.saving = true
+	sessionID := d.sessionID
+	return func() tea.Msg {
+		session, err := d.app.SetSessionInstructions(context.Background(), sessionID, instructions)
+		return instructionsResultMsg{session: session, err: err}
+	}
+}
+
+func (d *instructionsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case instructionsResultMsg:
+		d.saving = false
+		if msg.err != nil {
+			return d, toast.NewErrorToast(msg.err.Error())
+		}
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.SessionInstructionsChangedMsg{Session: *msg.session}),
+		)
+	case tea.WindowSizeMsg:
+		d.setSize()
+	case tea.KeyPressMsg:
+		if d.saving {
+			return d, nil
+		}
+		switch msg.String() {
+		case "ctrl+s":
+			return d, d.save(d.textarea.Value())
+		case "ctrl+r":
+			return d, d.save("")
+		}
+	}
+
+	var cmd tea.Cmd
+	d.textarea, cmd = d.textarea.Update(msg)
+	return d, cmd
+}
+
+func (d *instructionsDialog) setSize() {
+	d.textarea.SetWidth(layout.Current.Container.Width - 14)
+	d.textarea.SetHeight(max(5, min(12, layout.Current.Viewport.Height-14)))
+}
+
+func (d *instructionsDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if d.saving {
+		return muted.Render("Saving...")
+	}
+	header := muted.PaddingBottom(1).Render("Appended to the project instructions for this session only")
+	help := muted.PaddingTop(1).Render("ctrl+s save · ctrl+r reset to project defaults · esc cancel")
+	return header + "\n" + d.textarea.View() + "\n" + help
+}
+
+func (d *instructionsDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *instructionsDialog) Close() tea.Cmd {
+	d.textarea.Blur()
+	return nil
+}
+
+// NewInstructionsDialog creates a dialog that edits the instructions the
+// server adds to the system prompt of a session
+func NewInstructionsDialog(app *app.App, session *opencode.Session) InstructionsDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	ta := textarea.New()
+	ta.Styles.Blurred.Base = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.CursorLine = styles.NewStyle().Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	ta.Styles.Blurred.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	ta.Styles.Focused = ta.Styles.Blurred
+	ta.Styles.Cursor.Color = t.Primary()
+	ta.Prompt = ""
+	ta.Placeholder = "e.g. Answer in French. Never touch files under vendor/."
+	ta.ShowLineNumbers = false
+	ta.CharLimit = -1
+
+	d := &instructionsDialog{
+		app:       app,
+		sessionID: session.ID,
+		textarea:  ta,
+		modal: modal.New(
+			modal.WithTitle("Session Instructions"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.setSize()
+	d.textarea.SetValue(app.SessionInstructions(session))
+	return d
+}
@@ -0,0 +1,233 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// KeybindsDialog interface for rebinding command keys
+type KeybindsDialog interface {
+	layout.Modal
+}
+
+type keybindsStage int
+
+const (
+	keybindsBrowsing keybindsStage = iota
+	keybindsCapturing
+	keybindsConfirming
+)
+
+type keybindItem struct {
+	command    commands.Command
+	keys       string
+	overridden bool
+}
+
+func (k keybindItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	descriptionStyle := baseStyle.Foreground(t.Text())
+	keysStyle := baseStyle.Foreground(t.TextMuted())
+	if k.overridden {
+		keysStyle = keysStyle.Foreground(t.Accent())
+	}
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.Primary())
+		descriptionStyle = baseStyle.Foreground(t.BackgroundElement()).Bold(true)
+		keysStyle = baseStyle.Foreground(t.BackgroundElement())
+	}
+
+	keys := keysStyle.Render(k.keys + " ")
+	left := truncate.StringWithTail(
+		descriptionStyle.Render(" "+k.command.Description),
+		uint(max(0, width-lipgloss.Width(keys)-1)),
+		"…",
+	)
+	gap := max(1, width-lipgloss.Width(left)-lipgloss.Width(keys))
+	return left + baseStyle.Render(strings.Repeat(" ", gap)) + keys
+}
+
+type keybindsDialog struct {
+	app       *app.App
+	modal     *modal.Modal
+	list      list.List[keybindItem]
+	stage     keybindsStage
+	editing   commands.Command
+	leader    bool // the leader was pressed while capturing
+	captured  commands.Keybinding
+	conflicts []commands.Command
+	width     int
+}
+
+func (k *keybindsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (k *keybindsDialog) items() []keybindItem {
+	var items []keybindItem
+	for _, command := range k.app.Commands.Sorted() {
+		items = append(items, keybindItem{
+			command:    command,
+			keys:       formatKeybindings(command, k.app.Config.Keybinds.Leader),
+			overridden: k.app.KeybindOverridden(command.Name),
+		})
+	}
+	return items
+}
+
+// refresh reloads the list after a binding changed, keeping the selection
+func (k *keybindsDialog) refresh() {
+	_, idx := k.list.GetSelectedItem()
+	k.list.SetItems(k.items())
+	if idx >= 0 {
+		k.list.SetSelectedIndex(idx)
+	}
+}
+
+func (k *keybindsDialog) save(keybind string) tea.Cmd {
+	name := k.editing.Name
+	k.stage = keybindsBrowsing
+	if err := k.app.SetKeybind(name, keybind); err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	k.refresh()
+	if keybind == "" {
+		return toast.NewSuccessToast(fmt.Sprintf("Reset %s to its default keys", name))
+	}
+	return toast.NewSuccessToast(fmt.Sprintf("Bound %s to %s", name, keybind))
+}
+
+func (k *keybindsDialog) capture(msg tea.KeyPressMsg) {
+	key := msg.String()
+	if !k.leader && key == k.app.Config.Keybinds.Leader {
+		k.leader = true
+		return
+	}
+	k.captured = commands.Keybinding{RequiresLeader: k.leader, Key: key}
+	k.conflicts = k.app.KeybindConflicts(k.editing.Name, k.captured)
+	k.stage = keybindsConfirming
+}
+
+func (k *keybindsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		k.setSize()
+	case tea.KeyPressMsg:
+		switch k.stage {
+		case keybindsCapturing:
+			k.capture(msg)
+			return k, nil
+		case keybindsConfirming:
+			switch msg.String() {
+			case "enter":
+				return k, k.save(k.captured.String())
+			case "backspace":
+				k.stage = keybindsCapturing
+				k.leader = false
+			}
+			return k, nil
+		}
+
+		switch msg.String() {
+		case "enter":
+			item, idx := k.list.GetSelectedItem()
+			if idx < 0 {
+				return k, nil
+			}
+			k.editing = item.command
+			k.leader = false
+			k.stage = keybindsCapturing
+			return k, nil
+		case "r":
+			item, idx := k.list.GetSelectedItem()
+			if idx < 0 {
+				return k, nil
+			}
+			if !k.app.KeybindOverridden(item.command.Name) {
+				return k, toast.NewInfoToast("This command already uses its default keys")
+			}
+			k.editing = item.command
+			return k, k.save("")
+		}
+	}
+
+	listModel, cmd := k.list.Update(msg)
+	k.list = listModel.(list.List[keybindItem])
+	return k, cmd
+}
+
+func (k *keybindsDialog) setSize() {
+	k.width = min(70, layout.Current.Container.Width-12)
+	k.list.SetMaxWidth(k.width)
+}
+
+func (k *keybindsDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	leader := k.app.Config.Keybinds.Leader
+
+	switch k.stage {
+	case keybindsCapturing:
+		prompt := "Press the new keys for " + base.Bold(true).Render(k.editing.Description)
+		hint := fmt.Sprintf("press %s first to bind after the leader · esc cancel", leader)
+		if k.leader {
+			hint = leader + " pressed, now press the key that follows it"
+		}
+		return base.Render(prompt) + "\n" + muted.PaddingTop(1).Render(hint)
+	case keybindsConfirming:
+		binding := formatKeybindings(commands.Command{Keybindings: []commands.Keybinding{k.captured}}, leader)
+		lines := []string{base.Render(k.editing.Description+" → ") + base.Bold(true).Render(binding)}
+		if len(k.conflicts) > 0 {
+			names := make([]string, len(k.conflicts))
+			for i, command := range k.conflicts {
+				names[i] = command.Description
+			}
+			warning := base.Foreground(t.Warning())
+			lines = append(lines, warning.PaddingTop(1).Render("Already bound to "+strings.Join(names, ", ")))
+		}
+		lines = append(lines, muted.PaddingTop(1).Render("enter save · backspace press again · esc cancel"))
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+
+	help := muted.PaddingTop(1).Render(
+		"enter rebind · r reset to default · saved to " + k.app.ProjectConfigPath(),
+	)
+	return k.list.View() + "\n" + truncate.StringWithTail(help, uint(k.width), "…")
+}
+
+func (k *keybindsDialog) Render(background string) string {
+	return k.modal.Render(k.View(), background)
+}
+
+func (k *keybindsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewKeybindsDialog creates a dialog that rebinds commands by capturing the
+// keys pressed and saves them to the project config
+func NewKeybindsDialog(app *app.App) KeybindsDialog {
+	k := &keybindsDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Keybindings"),
+			modal.WithMaxWidth(74),
+		),
+	}
+	k.list = list.NewListComponent(k.items(), 12, "No commands", false)
+	k.setSize()
+	return k
+}
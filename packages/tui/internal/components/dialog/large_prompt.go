@@ -0,0 +1,141 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// largePromptParts is how many of the biggest parts of a prompt are listed
+const largePromptParts = 6
+
+// LargePromptCancelledMsg is sent when a large prompt is not sent, so it
+// goes back to the editor
+type LargePromptCancelledMsg struct {
+	Text string
+}
+
+// LargePromptDialog interface for confirming a large prompt before sending
+type LargePromptDialog interface {
+	layout.Modal
+}
+
+type largePromptDialog struct {
+	msg     app.SendMsg
+	size    app.PromptSize
+	reason  string
+	modal   *modal.Modal
+	decided bool
+}
+
+func (d *largePromptDialog) Init() tea.Cmd {
+	return nil
+}
+
+// send closes the dialog and sends the prompt with the choices made
+func (d *largePromptDialog) send(msg app.SendMsg) tea.Cmd {
+	d.decided = true
+	msg.Confirmed = true
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(msg),
+	)
+}
+
+func (d *largePromptDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return d, nil
+	}
+	switch keyMsg.String() {
+	case "enter":
+		return d, d.send(d.msg)
+	case "i":
+		if d.size.Has(app.PromptPartImage) {
+			trimmed := d.msg
+			trimmed.Text = app.StripImagePaths(trimmed.Text)
+			trimmed.Attachments = nil
+			return d, d.send(trimmed)
+		}
+	case "p":
+		if d.size.Has(app.PromptPartPinned) && !d.msg.CompactPinned {
+			compacted := d.msg
+			compacted.CompactPinned = true
+			return d, d.send(compacted)
+		}
+	}
+	return d, nil
+}
+
+func (d *largePromptDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	warning := base.Foreground(t.Warning()).Bold(true)
+	width := min(70, layout.Current.Container.Width-12)
+
+	lines := []string{
+		warning.Render("This prompt is large: ") + base.Render(d.reason),
+		"",
+	}
+	for i, part := range d.size.Parts {
+		if i == largePromptParts {
+			lines = append(lines, muted.Render(fmt.Sprintf("and %d more", len(d.size.Parts)-i)))
+			break
+		}
+		kind := muted.Render(fmt.Sprintf("%-7s ", part.Kind))
+		bytes := muted.Render(" " + app.FormatBytes(part.Bytes))
+		name := truncate.StringWithTail(
+			part.Name,
+			uint(max(0, width-lipgloss.Width(kind)-lipgloss.Width(bytes))),
+			"…",
+		)
+		gap := max(0, width-lipgloss.Width(kind)-lipgloss.Width(name)-lipgloss.Width(bytes))
+		lines = append(lines, kind+base.Render(name+strings.Repeat(" ", gap))+bytes)
+	}
+
+	help := []string{"enter send"}
+	if d.size.Has(app.PromptPartImage) {
+		help = append(help, "i send without images")
+	}
+	if d.size.Has(app.PromptPartPinned) && !d.msg.CompactPinned {
+		help = append(help, fmt.Sprintf("p cut pinned files to %s", app.FormatBytes(app.PinnedContextCompactBytes)))
+	}
+	help = append(help, "esc edit")
+	lines = append(lines, "", muted.Render(strings.Join(help, " · ")))
+	return strings.Join(lines, "\n")
+}
+
+func (d *largePromptDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *largePromptDialog) Close() tea.Cmd {
+	if d.decided {
+		return nil
+	}
+	return util.CmdHandler(LargePromptCancelledMsg{Text: d.msg.Text})
+}
+
+// NewLargePromptDialog creates a dialog that lists the biggest parts of a
+// prompt and offers to trim them before it is sent
+func NewLargePromptDialog(msg app.SendMsg, size app.PromptSize, reason string) LargePromptDialog {
+	return &largePromptDialog{
+		msg:    msg,
+		size:   size,
+		reason: reason,
+		modal: modal.New(
+			modal.WithTitle("Send Large Prompt?"),
+			modal.WithMaxWidth(74),
+		),
+	}
+}
@@ -0,0 +1,234 @@
+package dialog
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/logging"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// LogsDialog interface for the log viewer dialog
+type LogsDialog interface {
+	layout.Modal
+}
+
+// logTailBytes is how much of the end of the log file the viewer reads
+const logTailBytes = 256 << 10
+
+var logLevels = []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+
+// logsTickMsg carries its dialog so ticks of a closed dialog die out
+type logsTickMsg struct{ dialog *logsDialog }
+
+type logsLoadedMsg struct {
+	lines []string
+	err   error
+}
+
+type logsDialog struct {
+	width, height int
+	path          string
+	modal         *modal.Modal
+	viewport      viewport.Model
+	lines         []string
+	minLevel      slog.Level
+	follow        bool
+	err           error
+}
+
+func (l *logsDialog) Init() tea.Cmd {
+	return l.load()
+}
+
+func (l *logsDialog) load() tea.Cmd {
+	path := l.path
+	return func() tea.Msg {
+		lines, err := readLogTail(path, logTailBytes)
+		return logsLoadedMsg{lines: lines, err: err}
+	}
+}
+
+func (l *logsDialog) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return logsTickMsg{dialog: l}
+	})
+}
+
+// readLogTail returns the complete lines within the last size bytes of path
+func readLogTail(path string, size int64) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(0, info.Size()-size)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimRight(string(data), "\n")
+	if offset > 0 {
+		// the first line is most likely cut off
+		if i := strings.IndexByte(content, '\n'); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+	if content == "" {
+		return nil, nil
+	}
+	return strings.Split(content, "\n"), nil
+}
+
+// logLineLevel extracts the level of a line written by slog's text handler
+func logLineLevel(line string) slog.Level {
+	i := strings.Index(line, "level=")
+	if i < 0 {
+		return slog.LevelInfo
+	}
+	name := line[i+len("level="):]
+	if end := strings.IndexByte(name, ' '); end >= 0 {
+		name = name[:end]
+	}
+	return logging.ParseLevel(name)
+}
+
+func (l *logsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logsTickMsg:
+		if msg.dialog != l {
+			return l, nil
+		}
+		return l, l.load()
+	case logsLoadedMsg:
+		l.err = msg.err
+		l.lines = msg.lines
+		l.render()
+		return l, l.tick()
+	case tea.WindowSizeMsg:
+		l.setSize()
+		l.render()
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "tab":
+			l.cycleLevel(1)
+			return l, nil
+		case "shift+tab":
+			l.cycleLevel(-1)
+			return l, nil
+		case "end", "G":
+			l.follow = true
+			l.viewport.GotoBottom()
+			return l, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	l.viewport, cmd = l.viewport.Update(msg)
+	l.follow = l.viewport.AtBottom()
+	return l, cmd
+}
+
+func (l *logsDialog) cycleLevel(delta int) {
+	current := 0
+	for i, level := range logLevels {
+		if level == l.minLevel {
+			current = i
+		}
+	}
+	next := (current + delta + len(logLevels)) % len(logLevels)
+	l.minLevel = logLevels[next]
+	l.render()
+}
+
+func (l *logsDialog) setSize() {
+	l.width = layout.Current.Container.Width - 12
+	l.height = max(5, layout.Current.Viewport.Height-14)
+	l.viewport.SetWidth(l.width)
+	l.viewport.SetHeight(l.height)
+}
+
+// render filters the loaded lines by level and colors them
+func (l *logsDialog) render() {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+
+	var rendered []string
+	for _, line := range l.lines {
+		level := logLineLevel(line)
+		if level < l.minLevel {
+			continue
+		}
+		style := base.Foreground(t.Text())
+		switch {
+		case level >= slog.LevelError:
+			style = base.Foreground(t.Error())
+		case level >= slog.LevelWarn:
+			style = base.Foreground(t.Warning())
+		case level < slog.LevelInfo:
+			style = base.Foreground(t.TextMuted())
+		}
+		rendered = append(rendered, style.Render(truncate.StringWithTail(line, uint(l.width), "…")))
+	}
+
+	l.viewport.SetContent(strings.Join(rendered, "\n"))
+	if l.follow {
+		l.viewport.GotoBottom()
+	}
+}
+
+func (l *logsDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if l.err != nil {
+		return muted.Render("Unable to read " + l.path + ": " + l.err.Error())
+	}
+	help := muted.PaddingTop(1).Render(
+		"level ≥ " + l.minLevel.String() + " · tab change level · ↑/↓ scroll · G follow",
+	)
+	return l.viewport.View() + "\n" + help
+}
+
+func (l *logsDialog) Render(background string) string {
+	return l.modal.Render(l.View(), background)
+}
+
+func (l *logsDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewLogsDialog creates a dialog that tails the TUI log file
+func NewLogsDialog(app *app.App) LogsDialog {
+	l := &logsDialog{
+		path:     logging.Path(app.Info.Path.Data),
+		minLevel: slog.LevelInfo,
+		follow:   true,
+		viewport: viewport.New(),
+		modal: modal.New(
+			modal.WithTitle("Logs"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	l.setSize()
+	return l
+}
@@ -0,0 +1,112 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// MCPDialog interface for the MCP servers dialog
+type MCPDialog interface {
+	layout.Modal
+}
+
+type mcpDialog struct {
+	app   *app.App
+	modal *modal.Modal
+}
+
+func (m *mcpDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (m *mcpDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// stats are read on every render, so new calls show up as they finish
+	return m, nil
+}
+
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+func (m *mcpDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+
+	servers := m.app.MCPStats.Servers()
+	if len(servers) == 0 {
+		return muted.Render("No MCP servers configured")
+	}
+
+	nameWidth := len("server")
+	for _, server := range servers {
+		nameWidth = max(nameWidth, lipgloss.Width(server.Name))
+	}
+	row := func(name, calls, errors, p50, p95 string) string {
+		return fmt.Sprintf("%-*s  %6s  %7s  %8s  %8s", nameWidth, name, calls, errors, p50, p95)
+	}
+
+	lines := []string{muted.Render(row("server", "calls", "errors", "p50", "p95"))}
+	for _, server := range servers {
+		style := base.Foreground(t.Text())
+		if server.Degraded {
+			style = base.Foreground(t.Warning())
+		}
+		errors := "-"
+		if server.Calls > 0 {
+			errors = fmt.Sprintf("%.0f%%", server.ErrorRate()*100)
+		}
+		line := row(
+			server.Name,
+			fmt.Sprint(server.Calls),
+			errors,
+			formatLatency(server.P50),
+			formatLatency(server.P95),
+		)
+		if server.Degraded && styles.Accessible() {
+			line += "  " + styles.StatusDegraded
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	help := muted.PaddingTop(1).Render(fmt.Sprintf(
+		"degraded after %d+ calls with p95 over %s or over %.0f%% errors",
+		app.MCPMinCalls, app.MCPLatencyThreshold, app.MCPErrorRateThreshold*100,
+	))
+	return strings.Join(lines, "\n") + "\n" + help
+}
+
+func (m *mcpDialog) Render(background string) string {
+	return m.modal.Render(m.View(), background)
+}
+
+func (m *mcpDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewMCPDialog creates a dialog showing the latency and error rate of each
+// MCP server over the session
+func NewMCPDialog(app *app.App) MCPDialog {
+	return &mcpDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("MCP Servers"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
@@ -47,13 +47,19 @@ type ModelWithProvider struct {
 type ModelItem struct {
 	ModelName    string
 	ProviderName string
+	Favorite     bool
 }
 
 func (m ModelItem) Render(selected bool, width int) string {
 	t := theme.CurrentTheme()
 
+	star := ""
+	if m.Favorite {
+		star = "★ "
+	}
+
 	if selected {
-		displayText := fmt.Sprintf("%s (%s)", m.ModelName, m.ProviderName)
+		displayText := fmt.Sprintf("%s%s (%s)", star, m.ModelName, m.ProviderName)
 		return styles.NewStyle().
 			Background(t.Primary()).
 			Foreground(t.BackgroundElement()).
@@ -69,6 +75,12 @@ func (m ModelItem) Render(selected bool, width int) string {
 			Background(t.BackgroundElement())
 
 		modelPart := modelStyle.Render(m.ModelName)
+		if star != "" {
+			modelPart = styles.NewStyle().
+				Foreground(t.Warning()).
+				Background(t.BackgroundElement()).
+				Render(star) + modelPart
+		}
 		providerPart := providerStyle.Render(fmt.Sprintf(" (%s)", m.ProviderName))
 
 		combinedText := modelPart + providerPart
@@ -79,9 +91,14 @@ func (m ModelItem) Render(selected bool, width int) string {
 	}
 }
 
+func (m ModelItem) FilterValue() string {
+	return m.ModelName + " " + m.ProviderName
+}
+
 type modelKeyMap struct {
-	Enter  key.Binding
-	Escape key.Binding
+	Enter    key.Binding
+	Escape   key.Binding
+	Favorite key.Binding
 }
 
 var modelKeys = modelKeyMap{
@@ -93,6 +110,10 @@ var modelKeys = modelKeyMap{
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "close"),
 	),
+	Favorite: key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("ctrl+f", "toggle favorite"),
+	),
 }
 
 func (m *modelDialog) Init() tea.Cmd {
@@ -120,6 +141,21 @@ func (m *modelDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, util.CmdHandler(modal.CloseModalMsg{})
 		case key.Matches(msg, modelKeys.Escape):
 			return m, util.CmdHandler(modal.CloseModalMsg{})
+		case key.Matches(msg, modelKeys.Favorite):
+			item, selectedIndex := m.modelList.GetSelectedItem()
+			if selectedIndex >= 0 && selectedIndex < len(m.allModels) {
+				selectedModel := m.allModels[selectedIndex]
+				item.Favorite = m.app.State.ToggleFavoriteModel(
+					selectedModel.Provider.ID,
+					selectedModel.Model.ID,
+				)
+				m.app.SaveState()
+				items := m.modelList.GetItems()
+				items[selectedIndex] = item
+				m.modelList.SetItems(items)
+				m.modelList.SetSelectedIndex(selectedIndex)
+			}
+			return m, nil
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -133,7 +169,13 @@ func (m *modelDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *modelDialog) View() string {
-	return m.modelList.View()
+	t := theme.CurrentTheme()
+	help := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundElement()).
+		PaddingTop(1).
+		Render("ctrl+f favorite · <leader>. cycles favorites")
+	return m.modelList.View() + "\n" + help
 }
 
 func (m *modelDialog) calculateOptimalWidth(modelItems []ModelItem) int {
@@ -142,7 +184,7 @@ func (m *modelDialog) calculateOptimalWidth(modelItems []ModelItem) int {
 	for _, item := range modelItems {
 		// Calculate the width needed for this item: "ModelName (ProviderName)"
 		// Add 4 for the parentheses, space, and some padding
-		itemWidth := len(item.ModelName) + len(item.ProviderName) + 4
+		itemWidth := len(item.ModelName) + len(item.ProviderName) + 6
 		if itemWidth > maxWidth {
 			maxWidth = itemWidth
 		}
@@ -175,6 +217,10 @@ func (m *modelDialog) setupAllModels() {
 		modelItems[i] = ModelItem{
 			ModelName:    modelWithProvider.Model.Name,
 			ProviderName: modelWithProvider.Provider.Name,
+			Favorite: m.app.State.IsFavoriteModel(
+				modelWithProvider.Provider.ID,
+				modelWithProvider.Model.ID,
+			),
 		}
 	}
 
@@ -182,6 +228,7 @@ func (m *modelDialog) setupAllModels() {
 
 	m.modelList = list.NewListComponent(modelItems, numVisibleModels, "No models available", true)
 	m.modelList.SetMaxWidth(m.dialogWidth)
+	m.modelList.SetFilterable(true)
 
 	if len(m.allModels) > 0 {
 		m.modelList.SetSelectedIndex(0)
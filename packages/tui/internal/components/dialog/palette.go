@@ -0,0 +1,206 @@
+package dialog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// CommandPaletteDialog interface for the command palette
+type CommandPaletteDialog interface {
+	layout.Modal
+}
+
+type paletteItem struct {
+	command commands.Command
+	keys    string
+}
+
+func (p paletteItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	descriptionStyle := baseStyle.Foreground(t.Text())
+	triggerStyle := baseStyle.Foreground(t.TextMuted())
+	keysStyle := baseStyle.Foreground(t.TextMuted())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.Primary())
+		descriptionStyle = baseStyle.Foreground(t.BackgroundElement()).Bold(true)
+		triggerStyle = baseStyle.Foreground(t.BackgroundElement())
+		keysStyle = baseStyle.Foreground(t.BackgroundElement())
+	}
+
+	keys := keysStyle.Render(p.keys + " ")
+	left := " " + p.command.Description
+	if p.command.Trigger != "" {
+		left += triggerStyle.Render("  /" + p.command.Trigger)
+	}
+	left = truncate.StringWithTail(descriptionStyle.Render(left), uint(max(0, width-lipgloss.Width(keys)-1)), "…")
+	gap := max(1, width-lipgloss.Width(left)-lipgloss.Width(keys))
+	return left + baseStyle.Render(strings.Repeat(" ", gap)) + keys
+}
+
+func (p paletteItem) searchText() string {
+	return p.command.Description + " " + p.command.Trigger + " " + string(p.command.Name)
+}
+
+type commandPaletteDialog struct {
+	app   *app.App
+	modal *modal.Modal
+	input textinput.Model
+	list  list.List[paletteItem]
+	items []paletteItem
+	query string
+	width int
+}
+
+func (p *commandPaletteDialog) Init() tea.Cmd {
+	return p.input.Focus()
+}
+
+// filter ranks the commands against the query, best match first
+func (p *commandPaletteDialog) filter() {
+	query := strings.TrimSpace(p.input.Value())
+	if query == p.query {
+		return
+	}
+	p.query = query
+	if query == "" {
+		p.list.SetItems(p.items)
+		return
+	}
+
+	targets := make([]string, len(p.items))
+	for i, item := range p.items {
+		targets[i] = item.searchText()
+	}
+	matches := fuzzy.RankFindFold(query, targets)
+	sort.Stable(matches)
+
+	filtered := make([]paletteItem, 0, len(matches))
+	for _, match := range matches {
+		filtered = append(filtered, p.items[match.OriginalIndex])
+	}
+	p.list.SetItems(filtered)
+}
+
+func (p *commandPaletteDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.setSize()
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "enter":
+			item, idx := p.list.GetSelectedItem()
+			if idx < 0 {
+				return p, nil
+			}
+			return p, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(commands.ExecuteCommandMsg(item.command)),
+			)
+		case "up", "down", "ctrl+p", "ctrl+n", "pgup", "pgdown":
+			listModel, cmd := p.list.Update(msg)
+			p.list = listModel.(list.List[paletteItem])
+			return p, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return p, cmd
+}
+
+func (p *commandPaletteDialog) setSize() {
+	p.width = min(70, layout.Current.Container.Width-12)
+	p.input.SetWidth(p.width - 2)
+	p.list.SetMaxWidth(p.width)
+}
+
+func (p *commandPaletteDialog) View() string {
+	t := theme.CurrentTheme()
+	prompt := styles.NewStyle().
+		Foreground(t.Primary()).
+		Background(t.BackgroundElement()).
+		Render("> ")
+	return prompt + p.input.View() + "\n\n" + p.list.View()
+}
+
+func (p *commandPaletteDialog) Render(background string) string {
+	return p.modal.Render(p.View(), background)
+}
+
+func (p *commandPaletteDialog) Close() tea.Cmd {
+	p.input.Blur()
+	return nil
+}
+
+// formatKeybindings renders a command's keybindings with the leader expanded
+func formatKeybindings(command commands.Command, leader string) string {
+	var keys []string
+	for _, binding := range command.Keybindings {
+		if binding.RequiresLeader {
+			keys = append(keys, leader+" "+binding.Key)
+			continue
+		}
+		keys = append(keys, binding.Key)
+	}
+	return strings.Join(keys, ", ")
+}
+
+// NewCommandPaletteDialog creates a palette that fuzzy-searches and runs the
+// registered commands
+func NewCommandPaletteDialog(app *app.App) CommandPaletteDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	var items []paletteItem
+	for _, command := range app.Commands.Sorted() {
+		name := string(command.Name)
+		// editor commands only make sense with the editor focused
+		if command.Name == commands.CommandPaletteCommand ||
+			strings.HasPrefix(name, "input_") ||
+			strings.HasPrefix(name, "history_") {
+			continue
+		}
+		items = append(items, paletteItem{
+			command: command,
+			keys:    formatKeybindings(command, app.Config.Keybinds.Leader),
+		})
+	}
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "Search commands"
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred.Text = input.Styles.Focused.Text
+	input.Styles.Blurred.Placeholder = input.Styles.Focused.Placeholder
+	input.Styles.Cursor.Color = t.Primary()
+
+	p := &commandPaletteDialog{
+		app:   app,
+		input: input,
+		items: items,
+		list:  list.NewListComponent(items, 10, "No matching commands", false),
+		modal: modal.New(
+			modal.WithTitle("Commands"),
+			modal.WithMaxWidth(74),
+		),
+	}
+	p.setSize()
+	return p
+}
@@ -0,0 +1,326 @@
+package dialog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/diff"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// PatchDialog interface for reviewing a multi-file patch hunk by hunk
+type PatchDialog interface {
+	layout.Modal
+}
+
+// patchFile is one file of the patch with the decision on each hunk
+type patchFile struct {
+	diff     diff.FileDiff
+	accepted []bool
+}
+
+func (f patchFile) acceptedHunks() []diff.Hunk {
+	var hunks []diff.Hunk
+	for i, hunk := range f.diff.Hunks {
+		if f.accepted[i] {
+			hunks = append(hunks, hunk)
+		}
+	}
+	return hunks
+}
+
+type patchResultMsg struct {
+	message *app.PatchAppliedMsg
+	err     error
+}
+
+type patchDialog struct {
+	app           *app.App
+	sessionID     string
+	width, height int
+	modal         *modal.Modal
+	viewport      viewport.Model
+	files         []patchFile
+	err           error
+	file          int
+	hunk          int
+	hunkOffsets   []int
+	applying      bool
+}
+
+func (d *patchDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *patchDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case patchResultMsg:
+		d.applying = false
+		if msg.err != nil {
+			return d, toast.NewErrorToast(msg.err.Error())
+		}
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(*msg.message),
+		)
+	case tea.WindowSizeMsg:
+		d.setSize(msg.Width, msg.Height)
+		d.render()
+		return d, nil
+	case tea.KeyPressMsg:
+		if d.applying || len(d.files) == 0 {
+			break
+		}
+		switch msg.String() {
+		case "]", "tab":
+			d.selectFile(d.file + 1)
+			return d, nil
+		case "[", "shift+tab":
+			d.selectFile(d.file - 1)
+			return d, nil
+		case "n", "down", "j":
+			d.gotoHunk(d.hunk + 1)
+			return d, nil
+		case "p", "up", "k":
+			d.gotoHunk(d.hunk - 1)
+			return d, nil
+		case "space":
+			accepted := d.files[d.file].accepted
+			if d.hunk < len(accepted) {
+				accepted[d.hunk] = !accepted[d.hunk]
+				d.render()
+			}
+			return d, nil
+		case "a", "r":
+			accepted := d.files[d.file].accepted
+			for i := range accepted {
+				accepted[i] = msg.String() == "a"
+			}
+			d.render()
+			return d, nil
+		case "enter":
+			return d, d.apply()
+		}
+	}
+
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+func (d *patchDialog) setSize(width, height int) {
+	d.width = max(40, width-12)
+	// room for the file tabs and the footer
+	d.height = max(5, height-11)
+	d.viewport.SetWidth(d.width)
+	d.viewport.SetHeight(d.height)
+}
+
+func (d *patchDialog) selectFile(index int) {
+	index = max(0, min(index, len(d.files)-1))
+	if index == d.file {
+		return
+	}
+	d.file = index
+	d.hunk = 0
+	d.render()
+	d.viewport.GotoTop()
+}
+
+func (d *patchDialog) gotoHunk(index int) {
+	if len(d.hunkOffsets) == 0 {
+		return
+	}
+	d.hunk = max(0, min(index, len(d.hunkOffsets)-1))
+	d.render()
+	d.viewport.SetYOffset(d.hunkOffsets[d.hunk])
+}
+
+// render draws the hunks of the selected file into the viewport, each under
+// a header that shows whether it will be applied
+func (d *patchDialog) render() {
+	if len(d.files) == 0 {
+		return
+	}
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement()).Width(d.width)
+	file := d.files[d.file]
+	fileName := file.diff.Path()
+
+	var lines []string
+	d.hunkOffsets = make([]int, 0, len(file.diff.Hunks))
+	for i, hunk := range file.diff.Hunks {
+		d.hunkOffsets = append(d.hunkOffsets, len(lines))
+		marker, style := "✗ rejected", base.Foreground(t.Error())
+		if file.accepted[i] {
+			marker, style = "✓ accepted", base.Foreground(t.Success())
+		}
+		if i == d.hunk {
+			style = style.Background(t.BackgroundPanel()).Bold(true)
+			marker = "> " + marker
+		} else {
+			marker = "  " + marker
+		}
+		lines = append(lines, style.Render(marker+"  "+hunk.Header))
+		rendered := strings.TrimSuffix(diff.RenderUnifiedHunk(fileName, hunk, diff.WithWidth(d.width)), "\n")
+		lines = append(lines, strings.Split(rendered, "\n")...)
+	}
+	d.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// tabs lists the files of the patch with how many of their hunks are accepted
+func (d *patchDialog) tabs() string {
+	t := theme.CurrentTheme()
+	var tabs []string
+	for i, file := range d.files {
+		accepted := len(file.acceptedHunks())
+		label := fmt.Sprintf(" %s %d/%d ", filepath.Base(file.diff.Path()), accepted, len(file.diff.Hunks))
+		style := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel())
+		if accepted == 0 {
+			style = style.Strikethrough(true)
+		}
+		if i == d.file {
+			style = style.Foreground(t.Background()).Background(t.Primary()).Bold(true)
+		}
+		tabs = append(tabs, style.Render(label))
+	}
+	return lipgloss.NewStyle().MaxWidth(d.width).Render(strings.Join(tabs, " "))
+}
+
+// changes computes the new content of every file with an accepted hunk from
+// what is on disk now, and describes every file for the summary
+func (d *patchDialog) changes() ([]app.FileChange, []app.PatchedFile, error) {
+	var changes []app.FileChange
+	var summary []app.PatchedFile
+	for _, file := range d.files {
+		hunks := file.acceptedHunks()
+		patched := app.PatchedFile{
+			Path:     file.diff.Path(),
+			Accepted: len(hunks),
+			Hunks:    len(file.diff.Hunks),
+		}
+		if len(hunks) == 0 {
+			summary = append(summary, patched)
+			continue
+		}
+
+		if file.diff.NewFile == "" && len(hunks) == len(file.diff.Hunks) {
+			patched.Deleted = true
+			changes = append(changes, app.FileChange{Path: file.diff.OldFile})
+			summary = append(summary, patched)
+			continue
+		}
+
+		original := ""
+		if file.diff.OldFile != "" {
+			data, err := os.ReadFile(filepath.Join(app.CwdPath, file.diff.OldFile))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read %s: %w", file.diff.OldFile, err)
+			}
+			original = string(data)
+		}
+		content, err := diff.ApplyHunks(original, hunks)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", file.diff.Path(), err)
+		}
+		changes = append(changes, app.FileChange{Path: file.diff.Path(), Content: &content})
+		// a rename moves the file once the new one is written
+		if file.diff.OldFile != "" && file.diff.NewFile != "" && file.diff.OldFile != file.diff.NewFile {
+			changes = append(changes, app.FileChange{Path: file.diff.OldFile})
+		}
+		summary = append(summary, patched)
+	}
+	if len(changes) == 0 {
+		return nil, nil, errors.New("every hunk is rejected, nothing to apply")
+	}
+	return changes, summary, nil
+}
+
+func (d *patchDialog) apply() tea.Cmd {
+	changes, summary, err := d.changes()
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	d.applying = true
+	sessionID := d.sessionID
+	return func() tea.Msg {
+		text := app.PatchSummary(summary)
+		message, err := d.app.ApplyPatch(context.Background(), sessionID, changes, text)
+		if err != nil {
+			return patchResultMsg{err: err}
+		}
+		return patchResultMsg{message: &app.PatchAppliedMsg{Message: *message, Summary: text}}
+	}
+}
+
+func (d *patchDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	if d.err != nil {
+		return muted.Render(d.err.Error())
+	}
+	if d.applying {
+		return muted.Render("Applying...")
+	}
+
+	hint := fmt.Sprintf(
+		"hunk %d/%d · space accept/reject · a/r whole file · n/p hunk · [/] file · enter apply",
+		min(d.hunk+1, len(d.hunkOffsets)),
+		len(d.hunkOffsets),
+	)
+	footer := muted.Width(d.width).Render(hint)
+	return lipgloss.JoinVertical(lipgloss.Left, d.tabs(), "", d.viewport.View(), "", footer)
+}
+
+func (d *patchDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *patchDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewPatchDialog opens a review of the latest multi-file patch the assistant
+// wrote in the session, with every hunk accepted until rejected
+func NewPatchDialog(a *app.App) PatchDialog {
+	d := &patchDialog{
+		app:       a,
+		sessionID: a.Session.ID,
+		modal:     modal.New(modal.WithTitle("Review Patch")),
+		viewport:  viewport.New(),
+	}
+	d.setSize(layout.Current.Viewport.Width, layout.Current.Viewport.Height)
+
+	patch := app.LatestPatch(a.Messages)
+	if patch == "" {
+		d.err = errors.New("No multi-file patch in this session")
+		return d
+	}
+	files, err := diff.ParseMultiFileDiff(patch)
+	if err != nil {
+		d.err = fmt.Errorf("Failed to parse the patch: %w", err)
+		return d
+	}
+	for _, file := range files {
+		accepted := make([]bool, len(file.Hunks))
+		for i := range accepted {
+			accepted[i] = true
+		}
+		d.files = append(d.files, patchFile{diff: file, accepted: accepted})
+	}
+	d.render()
+	return d
+}
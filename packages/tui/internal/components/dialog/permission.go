@@ -2,36 +2,31 @@ package dialog
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/v2/key"
-	"github.com/charmbracelet/bubbles/v2/viewport"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
 	"github.com/sst/dgmo/internal/styles"
 	"github.com/sst/dgmo/internal/theme"
 	"github.com/sst/dgmo/internal/util"
-	"strings"
-)
-
-type PermissionAction string
-
-// Permission responses
-const (
-	PermissionAllow           PermissionAction = "allow"
-	PermissionAllowForSession PermissionAction = "allow_session"
-	PermissionDeny            PermissionAction = "deny"
 )
 
 // PermissionResponseMsg represents the user's response to a permission request
 type PermissionResponseMsg struct {
-	// Permission permission.PermissionRequest
-	Action PermissionAction
+	Request  app.PermissionRequest
+	Response app.PermissionResponse
 }
 
-// PermissionDialogComponent interface for permission dialog component
-type PermissionDialogComponent interface {
-	tea.Model
-	tea.ViewModel
-	// SetPermissions(permission permission.PermissionRequest) tea.Cmd
+// PermissionDialog interface for the permission dialog
+type PermissionDialog interface {
+	layout.Modal
+	// Pending reports whether the tool call still waits for an answer
+	Pending() bool
 }
 
 type permissionsMapping struct {
@@ -41,20 +36,19 @@ type permissionsMapping struct {
 	Allow        key.Binding
 	AllowSession key.Binding
 	Deny         key.Binding
-	Tab          key.Binding
 }
 
 var permissionsKeys = permissionsMapping{
 	Left: key.NewBinding(
-		key.WithKeys("left"),
+		key.WithKeys("left", "shift+tab"),
 		key.WithHelp("←", "switch options"),
 	),
 	Right: key.NewBinding(
-		key.WithKeys("right"),
+		key.WithKeys("right", "tab"),
 		key.WithHelp("→", "switch options"),
 	),
 	EnterSpace: key.NewBinding(
-		key.WithKeys("enter", " "),
+		key.WithKeys("enter", "space"),
 		key.WithHelp("enter/space", "confirm"),
 	),
 	Allow: key.NewBinding(
@@ -69,428 +63,145 @@ var permissionsKeys = permissionsMapping{
 		key.WithKeys("d"),
 		key.WithHelp("d", "deny"),
 	),
-	Tab: key.NewBinding(
-		key.WithKeys("tab"),
-		key.WithHelp("tab", "switch options"),
-	),
 }
 
-// permissionDialogComponent is the implementation of PermissionDialog
-type permissionDialogComponent struct {
-	width  int
-	height int
-	// permission      permission.PermissionRequest
-	windowSize      tea.WindowSizeMsg
-	contentViewPort viewport.Model
-	selectedOption  int // 0: Allow, 1: Allow for session, 2: Deny
-
-	diffCache     map[string]string
-	markdownCache map[string]string
-}
-
-func (p *permissionDialogComponent) Init() tea.Cmd {
-	return p.contentViewPort.Init()
-}
-
-func (p *permissionDialogComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
-
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		p.windowSize = msg
-		cmd := p.SetSize()
-		cmds = append(cmds, cmd)
-		p.markdownCache = make(map[string]string)
-		p.diffCache = make(map[string]string)
-		// case tea.KeyMsg:
-		// 	switch {
-		// 	case key.Matches(msg, permissionsKeys.Right) || key.Matches(msg, permissionsKeys.Tab):
-		// 		p.selectedOption = (p.selectedOption + 1) % 3
-		// 		return p, nil
-		// 	case key.Matches(msg, permissionsKeys.Left):
-		// 		p.selectedOption = (p.selectedOption + 2) % 3
-		// 	case key.Matches(msg, permissionsKeys.EnterSpace):
-		// 		return p, p.selectCurrentOption()
-		// 	case key.Matches(msg, permissionsKeys.Allow):
-		// 		return p, util.CmdHandler(PermissionResponseMsg{Action: PermissionAllow, Permission: p.permission})
-		// 	case key.Matches(msg, permissionsKeys.AllowSession):
-		// 		return p, util.CmdHandler(PermissionResponseMsg{Action: PermissionAllowForSession, Permission: p.permission})
-		// 	case key.Matches(msg, permissionsKeys.Deny):
-		// 		return p, util.CmdHandler(PermissionResponseMsg{Action: PermissionDeny, Permission: p.permission})
-		// 	default:
-		// 		// Pass other keys to viewport
-		// 		viewPort, cmd := p.contentViewPort.Update(msg)
-		// 		p.contentViewPort = viewPort
-		// 		cmds = append(cmds, cmd)
-		// 	}
-	}
-
-	return p, tea.Batch(cmds...)
+type permissionOption struct {
+	label    string
+	response app.PermissionResponse
 }
 
-func (p *permissionDialogComponent) selectCurrentOption() tea.Cmd {
-	var action PermissionAction
-
-	switch p.selectedOption {
-	case 0:
-		action = PermissionAllow
-	case 1:
-		action = PermissionAllowForSession
-	case 2:
-		action = PermissionDeny
-	}
-
-	return util.CmdHandler(PermissionResponseMsg{Action: action}) // , Permission: p.permission})
+// permissionOptions are the buttons of the dialog, in order
+var permissionOptions = []permissionOption{
+	{"Allow (a)", app.PermissionOnce},
+	{"Allow for session (s)", app.PermissionAlways},
+	{"Deny (d)", app.PermissionReject},
 }
 
-func (p *permissionDialogComponent) renderButtons() string {
-	t := theme.CurrentTheme()
-	baseStyle := styles.NewStyle().Foreground(t.Text())
-
-	allowStyle := baseStyle
-	allowSessionStyle := baseStyle
-	denyStyle := baseStyle
-	spacerStyle := baseStyle.Background(t.Background())
-
-	// Style the selected button
-	switch p.selectedOption {
-	case 0:
-		allowStyle = allowStyle.Background(t.Primary()).Foreground(t.Background())
-		allowSessionStyle = allowSessionStyle.Background(t.Background()).Foreground(t.Primary())
-		denyStyle = denyStyle.Background(t.Background()).Foreground(t.Primary())
-	case 1:
-		allowStyle = allowStyle.Background(t.Background()).Foreground(t.Primary())
-		allowSessionStyle = allowSessionStyle.Background(t.Primary()).Foreground(t.Background())
-		denyStyle = denyStyle.Background(t.Background()).Foreground(t.Primary())
-	case 2:
-		allowStyle = allowStyle.Background(t.Background()).Foreground(t.Primary())
-		allowSessionStyle = allowSessionStyle.Background(t.Background()).Foreground(t.Primary())
-		denyStyle = denyStyle.Background(t.Primary()).Foreground(t.Background())
+// options are the buttons shown for the request, a restricted workspace
+// can't allow a tool for the whole session
+func (p *permissionDialog) options() []permissionOption {
+	if !p.request.Restricted {
+		return permissionOptions
 	}
-
-	allowButton := allowStyle.Padding(0, 1).Render("Allow (a)")
-	allowSessionButton := allowSessionStyle.Padding(0, 1).Render("Allow for session (s)")
-	denyButton := denyStyle.Padding(0, 1).Render("Deny (d)")
-
-	content := lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		allowButton,
-		spacerStyle.Render("  "),
-		allowSessionButton,
-		spacerStyle.Render("  "),
-		denyButton,
-		spacerStyle.Render("  "),
-	)
-
-	remainingWidth := p.width - lipgloss.Width(content)
-	if remainingWidth > 0 {
-		content = spacerStyle.Render(strings.Repeat(" ", remainingWidth)) + content
-	}
-	return content
+	return slices.DeleteFunc(slices.Clone(permissionOptions), func(option permissionOption) bool {
+		return option.response == app.PermissionAlways
+	})
 }
 
-func (p *permissionDialogComponent) renderHeader() string {
-	return "NOT IMPLEMENTED"
-	// t := theme.CurrentTheme()
-	// baseStyle := styles.BaseStyle()
-	//
-	// toolKey := baseStyle.Foreground(t.TextMuted()).Bold(true).Render("Tool")
-	// toolValue := baseStyle.
-	// 	Foreground(t.Text()).
-	// 	Width(p.width - lipgloss.Width(toolKey)).
-	// 	Render(fmt.Sprintf(": %s", p.permission.ToolName))
-	//
-	// pathKey := baseStyle.Foreground(t.TextMuted()).Bold(true).Render("Path")
-	//
-	// // Get the current working directory to display relative path
-	// relativePath := p.permission.Path
-	// if filepath.IsAbs(relativePath) {
-	// 	if cwd, err := filepath.Rel(config.WorkingDirectory(), relativePath); err == nil {
-	// 		relativePath = cwd
-	// 	}
-	// }
-	//
-	// pathValue := baseStyle.
-	// 	Foreground(t.Text()).
-	// 	Width(p.width - lipgloss.Width(pathKey)).
-	// 	Render(fmt.Sprintf(": %s", relativePath))
-	//
-	// headerParts := []string{
-	// 	lipgloss.JoinHorizontal(
-	// 		lipgloss.Left,
-	// 		toolKey,
-	// 		toolValue,
-	// 	),
-	// 	baseStyle.Render(strings.Repeat(" ", p.width)),
-	// 	lipgloss.JoinHorizontal(
-	// 		lipgloss.Left,
-	// 		pathKey,
-	// 		pathValue,
-	// 	),
-	// 	baseStyle.Render(strings.Repeat(" ", p.width)),
-	// }
-	//
-	// // Add tool-specific header information
-	// switch p.permission.ToolName {
-	// case "bash":
-	// 	headerParts = append(headerParts, baseStyle.Foreground(t.TextMuted()).Width(p.width).Bold(true).Render("Command"))
-	// case "edit":
-	// 	headerParts = append(headerParts, baseStyle.Foreground(t.TextMuted()).Width(p.width).Bold(true).Render("Diff"))
-	// case "write":
-	// 	headerParts = append(headerParts, baseStyle.Foreground(t.TextMuted()).Width(p.width).Bold(true).Render("Diff"))
-	// case "fetch":
-	// 	headerParts = append(headerParts, baseStyle.Foreground(t.TextMuted()).Width(p.width).Bold(true).Render("URL"))
-	// }
-	//
-	// return lipgloss.NewStyle().Background(t.Background()).Render(lipgloss.JoinVertical(lipgloss.Left, headerParts...))
+type permissionDialog struct {
+	request  app.PermissionRequest
+	modal    *modal.Modal
+	selected int
+	answered bool
 }
 
-func (p *permissionDialogComponent) renderBashContent() string {
-	// t := theme.CurrentTheme()
-	// baseStyle := styles.BaseStyle()
-	//
-	// if pr, ok := p.permission.Params.(tools.BashPermissionsParams); ok {
-	// 	content := fmt.Sprintf("```bash\n%s\n```", pr.Command)
-	//
-	// 	// Use the cache for markdown rendering
-	// 	renderedContent := p.GetOrSetMarkdown(p.permission.ID, func() (string, error) {
-	// 		r := styles.GetMarkdownRenderer(p.width - 10)
-	// 		s, err := r.Render(content)
-	//    return s
-	// 	})
-	//
-	// 	finalContent := baseStyle.
-	// 		Width(p.contentViewPort.Width).
-	// 		Render(renderedContent)
-	// 	p.contentViewPort.SetContent(finalContent)
-	// 	return p.styleViewport()
-	// }
-	return ""
-}
-
-func (p *permissionDialogComponent) renderEditContent() string {
-	// if pr, ok := p.permission.Params.(tools.EditPermissionsParams); ok {
-	// 	diff := p.GetOrSetDiff(p.permission.ID, func() (string, error) {
-	// 		return diff.FormatDiff(pr.Diff, diff.WithTotalWidth(p.contentViewPort.Width))
-	// 	})
-	//
-	// 	p.contentViewPort.SetContent(diff)
-	// 	return p.styleViewport()
-	// }
-	return ""
+func (p *permissionDialog) Init() tea.Cmd {
+	return nil
 }
 
-func (p *permissionDialogComponent) renderPatchContent() string {
-	// if pr, ok := p.permission.Params.(tools.EditPermissionsParams); ok {
-	// 	diff := p.GetOrSetDiff(p.permission.ID, func() (string, error) {
-	// 		return diff.FormatDiff(pr.Diff, diff.WithTotalWidth(p.contentViewPort.Width))
-	// 	})
-	//
-	// 	p.contentViewPort.SetContent(diff)
-	// 	return p.styleViewport()
-	// }
-	return ""
+func (p *permissionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		options := p.options()
+		switch {
+		case key.Matches(msg, permissionsKeys.Right):
+			p.selected = (p.selected + 1) % len(options)
+		case key.Matches(msg, permissionsKeys.Left):
+			p.selected = (p.selected + len(options) - 1) % len(options)
+		case key.Matches(msg, permissionsKeys.EnterSpace):
+			return p, p.respond(options[p.selected].response)
+		case key.Matches(msg, permissionsKeys.Allow):
+			return p, p.respond(app.PermissionOnce)
+		case key.Matches(msg, permissionsKeys.AllowSession) && !p.request.Restricted:
+			return p, p.respond(app.PermissionAlways)
+		case key.Matches(msg, permissionsKeys.Deny):
+			return p, p.respond(app.PermissionReject)
+		}
+	}
+	return p, nil
 }
 
-func (p *permissionDialogComponent) renderWriteContent() string {
-	// if pr, ok := p.permission.Params.(tools.WritePermissionsParams); ok {
-	// 	// Use the cache for diff rendering
-	// 	diff := p.GetOrSetDiff(p.permission.ID, func() (string, error) {
-	// 		return diff.FormatDiff(pr.Diff, diff.WithTotalWidth(p.contentViewPort.Width))
-	// 	})
-	//
-	// 	p.contentViewPort.SetContent(diff)
-	// 	return p.styleViewport()
-	// }
-	return ""
+func (p *permissionDialog) respond(response app.PermissionResponse) tea.Cmd {
+	p.answered = true
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(PermissionResponseMsg{Request: p.request, Response: response}),
+	)
 }
 
-func (p *permissionDialogComponent) renderFetchContent() string {
-	// 	t := theme.CurrentTheme()
-	// 	baseStyle := styles.BaseStyle()
-	//
-	// 	if pr, ok := p.permission.Params.(tools.FetchPermissionsParams); ok {
-	// 		content := fmt.Sprintf("```bash\n%s\n```", pr.URL)
-	//
-	// 		// Use the cache for markdown rendering
-	// 		renderedContent := p.GetOrSetMarkdown(p.permission.ID, func() (string, error) {
-	// 			r := styles.GetMarkdownRenderer(p.width - 10)
-	// 			s, err := r.Render(content)
-	//      return s
-	// 		})
-	//
-	// 		finalContent := baseStyle.
-	// 			Width(p.contentViewPort.Width).
-	// 			Render(renderedContent)
-	// 		p.contentViewPort.SetContent(finalContent)
-	// 		return p.styleViewport()
-	// 	}
+// details summarizes the tool call beyond its title, as far as it is known
+func (p *permissionDialog) details() string {
+	lines := func(field string) int {
+		s, _ := p.request.Metadata[field].(string)
+		if s == "" {
+			return 0
+		}
+		return strings.Count(s, "\n") + 1
+	}
+	switch p.request.Tool {
+	case "bash":
+		description, _ := p.request.Metadata["description"].(string)
+		return description
+	case "edit":
+		return fmt.Sprintf("-%d +%d lines", lines("oldString"), lines("newString"))
+	case "write":
+		return fmt.Sprintf("%d lines", lines("content"))
+	}
 	return ""
 }
 
-func (p *permissionDialogComponent) renderDefaultContent() string {
-	// 	t := theme.CurrentTheme()
-	// 	baseStyle := styles.BaseStyle()
-	//
-	// 	content := p.permission.Description
-	//
-	// 	// Use the cache for markdown rendering
-	// 	renderedContent := p.GetOrSetMarkdown(p.permission.ID, func() (string, error) {
-	// 		r := styles.GetMarkdownRenderer(p.width - 10)
-	// 		s, err := r.Render(content)
-	//    return s
-	// 	})
-	//
-	// 	finalContent := baseStyle.
-	// 		Width(p.contentViewPort.Width).
-	// 		Render(renderedContent)
-	// 	p.contentViewPort.SetContent(finalContent)
-	//
-	// 	if renderedContent == "" {
-	// 		return ""
-	// 	}
-	//
-	return p.styleViewport()
-}
-
-func (p *permissionDialogComponent) styleViewport() string {
+func (p *permissionDialog) View() string {
 	t := theme.CurrentTheme()
-	contentStyle := styles.NewStyle().Background(t.Background())
-
-	return contentStyle.Render(p.contentViewPort.View())
-}
-
-func (p *permissionDialogComponent) render() string {
-	return "NOT IMPLEMENTED"
-	// t := theme.CurrentTheme()
-	// baseStyle := styles.BaseStyle()
-	//
-	// title := baseStyle.
-	// 	Bold(true).
-	// 	Width(p.width - 4).
-	// 	Foreground(t.Primary()).
-	// 	Render("Permission Required")
-	// // Render header
-	// headerContent := p.renderHeader()
-	// // Render buttons
-	// buttons := p.renderButtons()
-	//
-	// // Calculate content height dynamically based on window size
-	// p.contentViewPort.Height = p.height - lipgloss.Height(headerContent) - lipgloss.Height(buttons) - 2 - lipgloss.Height(title)
-	// p.contentViewPort.Width = p.width - 4
-	//
-	// // Render content based on tool type
-	// var contentFinal string
-	// switch p.permission.ToolName {
-	// case "bash":
-	// 	contentFinal = p.renderBashContent()
-	// case "edit":
-	// 	contentFinal = p.renderEditContent()
-	// case "patch":
-	// 	contentFinal = p.renderPatchContent()
-	// case "write":
-	// 	contentFinal = p.renderWriteContent()
-	// case "fetch":
-	// 	contentFinal = p.renderFetchContent()
-	// default:
-	// 	contentFinal = p.renderDefaultContent()
-	// }
-	//
-	// content := lipgloss.JoinVertical(
-	// 	lipgloss.Top,
-	// 	title,
-	// 	baseStyle.Render(strings.Repeat(" ", lipgloss.Width(title))),
-	// 	headerContent,
-	// 	contentFinal,
-	// 	buttons,
-	// 	baseStyle.Render(strings.Repeat(" ", p.width-4)),
-	// )
-	//
-	// return baseStyle.
-	// 	Padding(1, 0, 0, 1).
-	// 	Border(lipgloss.RoundedBorder()).
-	// 	BorderBackground(t.Background()).
-	// 	BorderForeground(t.TextMuted()).
-	// 	Width(p.width).
-	// 	Height(p.height).
-	// 	Render(
-	// 		content,
-	// 	)
-}
-
-func (p *permissionDialogComponent) View() string {
-	return p.render()
-}
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	width := layout.Current.Container.Width - 16
 
-func (p *permissionDialogComponent) SetSize() tea.Cmd {
-	// if p.permission.ID == "" {
-	// 	return nil
-	// }
-	// switch p.permission.ToolName {
-	// case "bash":
-	// 	p.width = int(float64(p.windowSize.Width) * 0.4)
-	// 	p.height = int(float64(p.windowSize.Height) * 0.3)
-	// case "edit":
-	// 	p.width = int(float64(p.windowSize.Width) * 0.8)
-	// 	p.height = int(float64(p.windowSize.Height) * 0.8)
-	// case "write":
-	// 	p.width = int(float64(p.windowSize.Width) * 0.8)
-	// 	p.height = int(float64(p.windowSize.Height) * 0.8)
-	// case "fetch":
-	// 	p.width = int(float64(p.windowSize.Width) * 0.4)
-	// 	p.height = int(float64(p.windowSize.Height) * 0.3)
-	// default:
-	// 	p.width = int(float64(p.windowSize.Width) * 0.7)
-	// 	p.height = int(float64(p.windowSize.Height) * 0.5)
-	// }
-	return nil
-}
-
-// func (p *permissionDialogCmp) SetPermissions(permission permission.PermissionRequest) tea.Cmd {
-// 	p.permission = permission
-// 	return p.SetSize()
-// }
-
-// Helper to get or set cached diff content
-func (c *permissionDialogComponent) GetOrSetDiff(key string, generator func() (string, error)) string {
-	if cached, ok := c.diffCache[key]; ok {
-		return cached
+	lines := []string{base.Bold(true).Render(util.TruncateMiddle(p.request.Title, width))}
+	if details := p.details(); details != "" {
+		lines = append(lines, muted.Render(util.TruncateMiddle(details, width)))
 	}
 
-	content, err := generator()
-	if err != nil {
-		return fmt.Sprintf("Error formatting diff: %v", err)
-	}
-
-	c.diffCache[key] = content
-
-	return content
-}
-
-// Helper to get or set cached markdown content
-func (c *permissionDialogComponent) GetOrSetMarkdown(key string, generator func() (string, error)) string {
-	if cached, ok := c.markdownCache[key]; ok {
-		return cached
+	options := p.options()
+	buttons := make([]string, len(options))
+	for i, option := range options {
+		style := base.Foreground(t.Primary()).Padding(0, 1)
+		if i == p.selected {
+			style = style.Background(t.Primary()).Foreground(t.BackgroundElement())
+		}
+		buttons[i] = style.Render(option.label)
 	}
-
-	content, err := generator()
-	if err != nil {
-		return fmt.Sprintf("Error rendering markdown: %v", err)
+	lines = append(lines, "", strings.Join(buttons, base.Render("  ")))
+	if p.request.Restricted {
+		lines = append(lines, muted.PaddingTop(1).Render("restricted workspace: asked for every call, /trust to trust it"))
 	}
+	lines = append(lines, muted.PaddingTop(1).Render("esc deny"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
 
-	c.markdownCache[key] = content
-
-	return content
+func (p *permissionDialog) Render(background string) string {
+	return p.modal.Render(p.View(), background)
 }
 
-func NewPermissionDialogCmp() PermissionDialogComponent {
-	// Create viewport for content
-	contentViewport := viewport.New() // (0, 0)
+func (p *permissionDialog) Pending() bool {
+	return !p.answered
+}
 
-	return &permissionDialogComponent{
-		contentViewPort: contentViewport,
-		selectedOption:  0, // Default to "Allow"
-		diffCache:       make(map[string]string),
-		markdownCache:   make(map[string]string),
+// Close is only reached when the user dismisses the prompt, which denies the
+// call, as other modals opening leave a pending prompt open
+func (p *permissionDialog) Close() tea.Cmd {
+	if p.answered {
+		return nil
+	}
+	p.answered = true
+	return util.CmdHandler(PermissionResponseMsg{Request: p.request, Response: app.PermissionReject})
+}
+
+// NewPermissionDialog creates a dialog asking to approve a tool call
+func NewPermissionDialog(request app.PermissionRequest) PermissionDialog {
+	return &permissionDialog{
+		request: request,
+		modal: modal.New(
+			modal.WithTitle(fmt.Sprintf("Permission Required: %s", request.Tool)),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
 	}
 }
@@ -0,0 +1,137 @@
+package dialog
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// PluginPanelDialog interface for a panel rendered by a plugin
+type PluginPanelDialog interface {
+	layout.Modal
+}
+
+// pluginPanelRefreshMsg asks an open panel to render again
+type pluginPanelRefreshMsg struct {
+	action app.PluginAction
+}
+
+type pluginPanelDialog struct {
+	app      *app.App
+	action   app.PluginAction
+	modal    *modal.Modal
+	viewport viewport.Model
+	width    int
+	height   int
+	loading  bool
+	err      error
+	closed   bool
+}
+
+func (d *pluginPanelDialog) Init() tea.Cmd {
+	return d.refresh()
+}
+
+func (d *pluginPanelDialog) refresh() tea.Cmd {
+	d.loading = true
+	return d.app.RenderPluginPanel(d.action, d.width, d.height)
+}
+
+// sameAction reports whether a message is about the panel of this dialog
+func (d *pluginPanelDialog) sameAction(action app.PluginAction) bool {
+	return action.Plugin == d.action.Plugin && action.Name == d.action.Name
+}
+
+func (d *pluginPanelDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.setSize(msg.Width, msg.Height)
+		return d, nil
+	case app.PluginPanelMsg:
+		if !d.sameAction(msg.Action) {
+			return d, nil
+		}
+		d.loading = false
+		d.err = msg.Err
+		if msg.Err == nil {
+			if msg.Response.Title != "" {
+				d.modal.SetTitle(msg.Response.Title)
+			}
+			d.viewport.SetContent(msg.Response.Content)
+		}
+		if refresh := d.action.Panel.Refresh; refresh > 0 && !d.closed {
+			action := d.action
+			return d, tea.Tick(time.Duration(refresh)*time.Second, func(time.Time) tea.Msg {
+				return pluginPanelRefreshMsg{action: action}
+			})
+		}
+		return d, nil
+	case pluginPanelRefreshMsg:
+		if !d.sameAction(msg.action) || d.closed {
+			return d, nil
+		}
+		return d, d.refresh()
+	case tea.KeyPressMsg:
+		if msg.String() == "r" && !d.loading {
+			return d, d.refresh()
+		}
+	}
+
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+func (d *pluginPanelDialog) setSize(width, height int) {
+	d.width = max(40, width-12)
+	d.height = max(5, height-8)
+	d.viewport.SetWidth(d.width)
+	d.viewport.SetHeight(d.height)
+}
+
+func (d *pluginPanelDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	status := "r refresh · ↑/↓ scroll · from plugin " + d.action.Plugin.Name
+	switch {
+	case d.err != nil:
+		status = styles.NewStyle().Foreground(t.Error()).Background(t.BackgroundElement()).Render(d.err.Error())
+	case d.loading:
+		status = "rendering… · " + status
+	}
+	footer := muted.Width(d.width).Render(status)
+	return lipgloss.JoinVertical(lipgloss.Left, d.viewport.View(), "", footer)
+}
+
+func (d *pluginPanelDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *pluginPanelDialog) Close() tea.Cmd {
+	d.closed = true
+	return nil
+}
+
+// NewPluginPanelDialog creates a dialog showing a panel a plugin renders,
+// rendered again on r and on the refresh interval of the panel
+func NewPluginPanelDialog(app *app.App, action app.PluginAction) PluginPanelDialog {
+	title := action.Panel.Description
+	if title == "" {
+		title = action.Panel.Name
+	}
+	d := &pluginPanelDialog{
+		app:      app,
+		action:   action,
+		viewport: viewport.New(),
+		modal:    modal.New(modal.WithTitle(title)),
+	}
+	d.setSize(layout.Current.Viewport.Width, layout.Current.Viewport.Height)
+	return d
+}
@@ -0,0 +1,146 @@
+package dialog
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// RenameDialog interface for editing the title and description of a session
+type RenameDialog interface {
+	layout.Modal
+}
+
+type renameResultMsg struct {
+	session *opencode.Session
+	err     error
+}
+
+type renameDialog struct {
+	app         *app.App
+	sessionID   string
+	modal       *modal.Modal
+	title       textinput.Model
+	description textinput.Model
+	saving      bool
+}
+
+func (r *renameDialog) Init() tea.Cmd {
+	return r.title.Focus()
+}
+
+func (r *renameDialog) save() tea.Cmd {
+	r.saving = true
+	sessionID := r.sessionID
+	title, description := r.title.Value(), r.description.Value()
+	return func() tea.Msg {
+		session, err := r.app.UpdateSession(context.Background(), sessionID, title, description)
+		return renameResultMsg{session: session, err: err}
+	}
+}
+
+// toggleFocus moves the cursor between the title and the description
+func (r *renameDialog) toggleFocus() tea.Cmd {
+	if r.title.Focused() {
+		r.title.Blur()
+		return r.description.Focus()
+	}
+	r.description.Blur()
+	return r.title.Focus()
+}
+
+func (r *renameDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case renameResultMsg:
+		r.saving = false
+		if msg.err != nil {
+			return r, toast.NewErrorToast(msg.err.Error())
+		}
+		return r, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.SessionRenamedMsg{Session: *msg.session}),
+		)
+	case tea.KeyPressMsg:
+		if r.saving {
+			return r, nil
+		}
+		switch msg.String() {
+		case "enter":
+			return r, r.save()
+		case "tab", "shift+tab", "up", "down":
+			return r, r.toggleFocus()
+		}
+	}
+
+	var cmd tea.Cmd
+	if r.title.Focused() {
+		r.title, cmd = r.title.Update(msg)
+	} else {
+		r.description, cmd = r.description.Update(msg)
+	}
+	return r, cmd
+}
+
+func (r *renameDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if r.saving {
+		return muted.Render("Saving...")
+	}
+	help := muted.PaddingTop(1).Render("enter save · tab switch field · esc cancel")
+	return muted.Render("Title") + "\n" + r.title.View() + "\n\n" +
+		muted.Render("Description") + "\n" + r.description.View() + "\n" + help
+}
+
+func (r *renameDialog) Render(background string) string {
+	return r.modal.Render(r.View(), background)
+}
+
+func (r *renameDialog) Close() tea.Cmd {
+	r.title.Blur()
+	r.description.Blur()
+	return nil
+}
+
+func newRenameInput(placeholder, value string) textinput.Model {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = placeholder
+	input.CharLimit = 200
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred.Text = input.Styles.Focused.Text
+	input.Styles.Blurred.Placeholder = input.Styles.Focused.Placeholder
+	input.Styles.Cursor.Color = t.Primary()
+	input.SetWidth(54)
+	input.SetValue(value)
+	return input
+}
+
+// NewRenameDialog creates a dialog that edits the title and description of
+// a session
+func NewRenameDialog(app *app.App, session *opencode.Session) RenameDialog {
+	return &renameDialog{
+		app:         app,
+		sessionID:   session.ID,
+		title:       newRenameInput("Title", session.Title),
+		description: newRenameInput("Description (optional)", app.SessionDescription(session)),
+		modal: modal.New(
+			modal.WithTitle("Rename Session"),
+			modal.WithMaxWidth(60),
+		),
+	}
+}
@@ -0,0 +1,108 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// resendPreviewLines is how many lines of the resent prompt are shown
+const resendPreviewLines = 6
+
+// ResendDialog interface for confirming a resend, which deletes messages
+type ResendDialog interface {
+	layout.Modal
+}
+
+type resendDialog struct {
+	request app.ResendRequest
+	modal   *modal.Modal
+}
+
+func (d *resendDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *resendDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return d, nil
+	}
+	switch keyMsg.String() {
+	case "enter", "y":
+		return d, tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(app.ResendConfirmedMsg{Request: d.request}),
+		)
+	case "n":
+		return d, util.CmdHandler(modal.CloseModalMsg{})
+	}
+	return d, nil
+}
+
+func (d *resendDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	warning := base.Foreground(t.Warning()).Bold(true)
+	width := min(70, layout.Current.Container.Width-12)
+
+	// the prompt itself is sent again, so only the rest is lost for good
+	later := d.request.Removed - 1
+	var lines []string
+	switch {
+	case d.request.Regenerate && later == 1:
+		lines = append(lines, warning.Render("The last response is deleted")+base.Render(" and the prompt is sent again."))
+	case later > 0:
+		lines = append(lines, warning.Render(fmt.Sprintf("%d later messages are deleted", later))+base.Render(" and the prompt is sent again."))
+	default:
+		lines = append(lines, base.Render("The prompt is sent again."))
+	}
+	if d.request.Model != nil {
+		lines = append(lines, base.Render("Sent to ")+warning.Render(d.request.Model.Name)+base.Render(", which stays selected."))
+	}
+	lines = append(lines, "")
+
+	preview := strings.Split(strings.TrimSpace(d.request.Text), "\n")
+	if len(preview) > resendPreviewLines {
+		hidden := len(preview) - resendPreviewLines
+		preview = append(preview[:resendPreviewLines], fmt.Sprintf("… %d more lines", hidden))
+	}
+	for _, line := range preview {
+		lines = append(lines, muted.Render("│ ")+base.Render(truncate.StringWithTail(line, uint(max(0, width-2)), "…")))
+	}
+	lines = append(lines, "", muted.Render("enter resend · esc cancel"))
+	return strings.Join(lines, "\n")
+}
+
+func (d *resendDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *resendDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewResendDialog creates a dialog that asks before deleting the messages
+// of a resend
+func NewResendDialog(request app.ResendRequest) ResendDialog {
+	title := "Resend Edited Message?"
+	if request.Regenerate {
+		title = "Regenerate Response?"
+	}
+	return &resendDialog{
+		request: request,
+		modal: modal.New(
+			modal.WithTitle(title),
+			modal.WithMaxWidth(74),
+		),
+	}
+}
@@ -0,0 +1,295 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/diff"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// RevertDialog interface for the checkpoint revert dialog
+type RevertDialog interface {
+	layout.Modal
+}
+
+type checkpointItem struct {
+	checkpoint app.Checkpoint
+}
+
+func (c checkpointItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle()
+
+	description := c.checkpoint.Description
+	if description == "" {
+		description = "Checkpoint " + c.checkpoint.ID
+	}
+	text := fmt.Sprintf("%s · %s", description, c.checkpoint.Time().Format("Jan 2 15:04"))
+	if n := len(c.checkpoint.Files); n > 0 {
+		text += fmt.Sprintf(" (%d files)", n)
+	}
+	truncatedStr := truncate.StringWithTail(text, uint(width-1), "...")
+
+	if selected {
+		return baseStyle.
+			Background(t.Primary()).
+			Foreground(t.BackgroundElement()).
+			Width(width).
+			PaddingLeft(1).
+			Render(truncatedStr)
+	}
+	return baseStyle.
+		Foreground(t.Text()).
+		PaddingLeft(1).
+		Render(truncatedStr)
+}
+
+func (c checkpointItem) FilterValue() string {
+	return c.checkpoint.Description
+}
+
+type checkpointsLoadedMsg struct {
+	checkpoints []app.Checkpoint
+	err         error
+}
+
+type checkpointDiffLoadedMsg struct {
+	checkpointID string
+	diffs        []app.CheckpointFileDiff
+	err          error
+}
+
+type revertDialog struct {
+	width, height int
+	app           *app.App
+	modal         *modal.Modal
+	list          list.List[checkpointItem]
+	viewport      viewport.Model
+	loading       bool
+	previewing    *app.Checkpoint
+	diffs         []app.CheckpointFileDiff
+	diffLoading   bool
+	confirmDirty  bool // the dirty tree warning was shown, enter restores
+}
+
+func (r *revertDialog) Init() tea.Cmd {
+	return r.loadCheckpoints()
+}
+
+func (r *revertDialog) loadCheckpoints() tea.Cmd {
+	if r.app.Session == nil || r.app.Session.ID == "" {
+		return nil
+	}
+	r.loading = true
+	sessionID := r.app.Session.ID
+	return func() tea.Msg {
+		checkpoints, err := r.app.Checkpoints.ListCheckpoints(context.Background(), sessionID)
+		return checkpointsLoadedMsg{checkpoints: checkpoints, err: err}
+	}
+}
+
+func (r *revertDialog) loadDiff(checkpoint app.Checkpoint) tea.Cmd {
+	r.previewing = &checkpoint
+	r.diffs = nil
+	r.diffLoading = true
+	r.confirmDirty = false
+	r.modal.SetTitle("Preview Revert")
+	r.viewport.SetContent("")
+	return func() tea.Msg {
+		diffs, err := r.app.Checkpoints.GetCheckpointDiff(context.Background(), checkpoint.ID)
+		return checkpointDiffLoadedMsg{checkpointID: checkpoint.ID, diffs: diffs, err: err}
+	}
+}
+
+func (r *revertDialog) restore(checkpoint app.Checkpoint) tea.Cmd {
+	return func() tea.Msg {
+		if err := r.app.Checkpoints.RestoreCheckpoint(context.Background(), checkpoint.ID); err != nil {
+			return toast.NewErrorToast(err.Error())()
+		}
+		return app.CheckpointRestoredMsg{Checkpoint: checkpoint}
+	}
+}
+
+func (r *revertDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.setSize()
+		r.renderPreview()
+	case checkpointsLoadedMsg:
+		r.loading = false
+		if msg.err != nil {
+			return r, toast.NewErrorToast(msg.err.Error())
+		}
+		items := make([]checkpointItem, 0, len(msg.checkpoints))
+		for _, checkpoint := range msg.checkpoints {
+			items = append(items, checkpointItem{checkpoint: checkpoint})
+		}
+		r.list.SetItems(items)
+		return r, nil
+	case app.CheckpointCreatedMsg:
+		items := r.list.GetItems()
+		for _, item := range items {
+			if item.checkpoint.ID == msg.Checkpoint.ID {
+				return r, nil
+			}
+		}
+		r.list.SetItems(append([]checkpointItem{{checkpoint: msg.Checkpoint}}, items...))
+		return r, nil
+	case checkpointDiffLoadedMsg:
+		if r.previewing == nil || r.previewing.ID != msg.checkpointID {
+			return r, nil
+		}
+		r.diffLoading = false
+		if msg.err != nil {
+			r.previewing = nil
+			r.modal.SetTitle("Revert to Checkpoint")
+			return r, toast.NewErrorToast(msg.err.Error())
+		}
+		r.diffs = msg.diffs
+		r.renderPreview()
+		r.viewport.GotoTop()
+		return r, nil
+	case tea.KeyPressMsg:
+		if r.previewing != nil {
+			switch msg.String() {
+			case "enter":
+				if r.diffLoading {
+					return r, nil
+				}
+				// uncommitted changes may be overwritten, so ask once more
+				if r.app.DirtyWarning() != "" && !r.confirmDirty {
+					r.confirmDirty = true
+					return r, nil
+				}
+				return r, tea.Sequence(
+					util.CmdHandler(modal.CloseModalMsg{}),
+					r.restore(*r.previewing),
+				)
+			case "backspace", "left":
+				r.previewing = nil
+				r.confirmDirty = false
+				r.modal.SetTitle("Revert to Checkpoint")
+				return r, nil
+			}
+			var cmd tea.Cmd
+			r.viewport, cmd = r.viewport.Update(msg)
+			return r, cmd
+		}
+
+		if msg.String() == "enter" {
+			if item, idx := r.list.GetSelectedItem(); idx >= 0 {
+				return r, r.loadDiff(item.checkpoint)
+			}
+			return r, nil
+		}
+	}
+
+	listModel, cmd := r.list.Update(msg)
+	r.list = listModel.(list.List[checkpointItem])
+	return r, cmd
+}
+
+func (r *revertDialog) setSize() {
+	r.width = layout.Current.Container.Width - 12
+	r.height = max(5, layout.Current.Viewport.Height-14)
+	r.list.SetMaxWidth(r.width)
+	r.viewport.SetWidth(r.width)
+	r.viewport.SetHeight(r.height)
+}
+
+// renderPreview renders every changed file of the previewed checkpoint
+func (r *revertDialog) renderPreview() {
+	if r.previewing == nil || r.diffLoading {
+		return
+	}
+	t := theme.CurrentTheme()
+	headerStyle := styles.NewStyle().
+		Foreground(t.Text()).
+		Background(t.BackgroundElement()).
+		Bold(true)
+	addedStyle := styles.NewStyle().Foreground(t.DiffAdded()).Background(t.BackgroundElement())
+	removedStyle := styles.NewStyle().Foreground(t.DiffRemoved()).Background(t.BackgroundElement())
+
+	if len(r.diffs) == 0 {
+		r.viewport.SetContent(styles.NewStyle().
+			Foreground(t.TextMuted()).
+			Render("No file changes, the working tree already matches this checkpoint"))
+		return
+	}
+
+	var sections []string
+	for _, fileDiff := range r.diffs {
+		header := styles.FileLink(fileDiff.Path, headerStyle.Render(relativePath(fileDiff.Path))) +
+			addedStyle.Render(fmt.Sprintf(" +%d", fileDiff.Additions)) +
+			removedStyle.Render(fmt.Sprintf(" -%d", fileDiff.Deletions))
+		body, err := diff.FormatUnifiedDiff(fileDiff.Path, fileDiff.Diff, diff.WithWidth(r.width))
+		if err != nil {
+			body = styles.NewStyle().Foreground(t.TextMuted()).Render("Unable to render diff")
+		}
+		sections = append(sections, header+"\n"+strings.TrimRight(body, "\n"))
+	}
+	r.viewport.SetContent(strings.Join(sections, "\n\n"))
+}
+
+func (r *revertDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if r.previewing != nil {
+		if r.diffLoading {
+			return muted.Render("Loading changes...")
+		}
+		help := muted.PaddingTop(1).Render("enter confirm revert · backspace back · ↑/↓ scroll")
+		if r.confirmDirty {
+			warning := styles.NewStyle().Foreground(t.Warning()).Background(t.BackgroundElement())
+			help = warning.PaddingTop(1).Render(
+				r.app.DirtyWarning() + " that the revert may overwrite, enter to revert anyway",
+			)
+		}
+		return r.viewport.View() + "\n" + help
+	}
+
+	if r.loading {
+		return muted.Render("Loading checkpoints...")
+	}
+	help := muted.PaddingTop(1).Render("enter preview changes · type to filter")
+	return r.list.View() + "\n" + help
+}
+
+func (r *revertDialog) Render(background string) string {
+	return r.modal.Render(r.View(), background)
+}
+
+func (r *revertDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewRevertDialog creates a dialog to preview and restore session checkpoints
+func NewRevertDialog(app *app.App) RevertDialog {
+	checkpoints := list.NewListComponent([]checkpointItem{}, 10, "No checkpoints for this session", true)
+	checkpoints.SetFilterable(true)
+
+	r := &revertDialog{
+		app:  app,
+		list: checkpoints,
+		modal: modal.New(
+			modal.WithTitle("Revert to Checkpoint"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+		viewport: viewport.New(),
+	}
+	r.setSize()
+	return r
+}
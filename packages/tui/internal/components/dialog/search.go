@@ -0,0 +1,223 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/search"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// PromptRecalledMsg is sent when a prompt from the search results should be
+// put back into the editor
+type PromptRecalledMsg struct {
+	Text string
+}
+
+// SearchDialog interface for searching messages, prompts and artifacts
+type SearchDialog interface {
+	layout.Modal
+}
+
+// searchScopes are the kinds tab cycles through, all kinds first
+var searchScopes = []search.Kind{"", search.KindMessage, search.KindPrompt, search.KindArtifact}
+
+// searchLimit is the most results shown at once
+const searchLimit = 50
+
+type searchItem struct {
+	result search.Result
+}
+
+func (s searchItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	textStyle := baseStyle.Foreground(t.Text())
+	kindStyle := baseStyle.Foreground(t.TextMuted())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.Primary())
+		textStyle = baseStyle.Foreground(t.BackgroundElement()).Bold(true)
+		kindStyle = baseStyle.Foreground(t.BackgroundElement())
+	}
+
+	kind := kindStyle.Render(" " + string(s.result.Kind) + " ")
+	text := strings.Join(strings.Fields(s.result.Text), " ")
+	if s.result.Kind == search.KindArtifact {
+		text = relativePath(s.result.Text)
+	}
+	text = truncate.StringWithTail(text, uint(max(0, width-lipgloss.Width(kind)-1)), "…")
+	left := textStyle.Render(" " + text)
+	gap := max(0, width-lipgloss.Width(left)-lipgloss.Width(kind))
+	return left + baseStyle.Render(strings.Repeat(" ", gap)) + kind
+}
+
+type searchDialog struct {
+	app   *app.App
+	modal *modal.Modal
+	input textinput.Model
+	list  list.List[searchItem]
+	scope int
+	query string
+	width int
+}
+
+func (s *searchDialog) Init() tea.Cmd {
+	return s.input.Focus()
+}
+
+// run searches the index again if the query or scope changed
+func (s *searchDialog) run(force bool) {
+	query := strings.TrimSpace(s.input.Value())
+	if query == s.query && !force {
+		return
+	}
+	s.query = query
+	results, err := s.app.Search.Search(search.Query{
+		Text:  query,
+		Kind:  searchScopes[s.scope],
+		Limit: searchLimit,
+	})
+	if err != nil {
+		results = nil
+	}
+	items := make([]searchItem, 0, len(results))
+	for _, result := range results {
+		items = append(items, searchItem{result: result})
+	}
+	s.list.SetItems(items)
+}
+
+// open acts on a result: prompts go back into the editor, messages and
+// artifacts open the session they belong to
+func (s *searchDialog) open(result search.Result) tea.Cmd {
+	if result.Kind == search.KindPrompt {
+		return tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(PromptRecalledMsg{Text: result.Text}),
+		)
+	}
+	sessionID := result.SessionID
+	if s.app.Session != nil && s.app.Session.ID == sessionID {
+		return util.CmdHandler(modal.CloseModalMsg{})
+	}
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		func() tea.Msg {
+			sessions, err := s.app.ListSessions(context.Background())
+			if err != nil {
+				return toast.NewErrorToast("Failed to load sessions")()
+			}
+			for _, session := range sessions {
+				if session.ID == sessionID {
+					return app.SessionSelectedMsg(&session)
+				}
+			}
+			return toast.NewWarningToast("The session of this result no longer exists")()
+		},
+	)
+}
+
+func (s *searchDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.setSize()
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "enter":
+			item, idx := s.list.GetSelectedItem()
+			if idx < 0 {
+				return s, nil
+			}
+			return s, s.open(item.result)
+		case "tab":
+			s.scope = (s.scope + 1) % len(searchScopes)
+			s.run(true)
+			return s, nil
+		case "up", "down", "ctrl+p", "ctrl+n", "pgup", "pgdown":
+			listModel, cmd := s.list.Update(msg)
+			s.list = listModel.(list.List[searchItem])
+			return s, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	s.run(false)
+	return s, cmd
+}
+
+func (s *searchDialog) setSize() {
+	s.width = min(90, layout.Current.Container.Width-12)
+	s.input.SetWidth(s.width - 2)
+	s.list.SetMaxWidth(s.width)
+}
+
+func (s *searchDialog) View() string {
+	t := theme.CurrentTheme()
+	prompt := styles.NewStyle().
+		Foreground(t.Primary()).
+		Background(t.BackgroundElement()).
+		Render("> ")
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	scope := "all"
+	if kind := searchScopes[s.scope]; kind != "" {
+		scope = string(kind) + "s"
+	}
+	stats := s.app.Search.Stats()
+	help := muted.PaddingTop(1).Render(fmt.Sprintf(
+		"searching %s · tab change scope · enter open · %d indexed", scope, stats.Documents,
+	))
+	return prompt + s.input.View() + "\n\n" + s.list.View() + "\n" + help
+}
+
+func (s *searchDialog) Render(background string) string {
+	return s.modal.Render(s.View(), background)
+}
+
+func (s *searchDialog) Close() tea.Cmd {
+	s.input.Blur()
+	return nil
+}
+
+// NewSearchDialog creates a dialog that searches the local index of
+// messages, sent prompts and written files across sessions
+func NewSearchDialog(app *app.App, query string) SearchDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "Search messages, prompts and files"
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred.Text = input.Styles.Focused.Text
+	input.Styles.Blurred.Placeholder = input.Styles.Focused.Placeholder
+	input.Styles.Cursor.Color = t.Primary()
+	input.SetValue(query)
+
+	s := &searchDialog{
+		app:   app,
+		input: input,
+		list:  list.NewListComponent([]searchItem{}, 10, "No results", false),
+		modal: modal.New(
+			modal.WithTitle("Search"),
+			modal.WithMaxWidth(94),
+		),
+	}
+	s.setSize()
+	s.run(true)
+	return s
+}
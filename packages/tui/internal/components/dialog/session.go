@@ -2,11 +2,13 @@ package dialog
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"slices"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/muesli/reflow/truncate"
 	"github.com/sst/opencode-sdk-go"
 	"github.com/sst/dgmo/internal/app"
@@ -26,8 +28,12 @@ type SessionDialog interface {
 
 // sessionItem is a custom list item for sessions that can show delete confirmation
 type sessionItem struct {
+	id                 string
 	title              string
+	tags               []string
 	isDeleteConfirming bool
+	activity           *app.SessionActivity
+	depth              int // how deep in a tree of branches the session is
 }
 
 func (s sessionItem) Render(selected bool, width int) string {
@@ -39,9 +45,35 @@ func (s sessionItem) Render(selected bool, width int) string {
 		text = "Press again to confirm delete"
 	} else {
 		text = s.title
+		if s.depth > 0 {
+			text = strings.Repeat("  ", s.depth-1) + "↳ " + text
+		}
 	}
 
-	truncatedStr := truncate.StringWithTail(text, uint(width-1), "...")
+	var tags string
+	if !s.isDeleteConfirming && len(s.tags) > 0 {
+		tags = " [" + strings.Join(s.tags, ", ") + "]"
+	}
+	// the activity is read on render so the indicator stays live without
+	// rebuilding the list on every streamed part
+	var activity string
+	if preview, generating := s.activity.Generating(s.id); generating && !s.isDeleteConfirming {
+		activity = " ● generating…"
+		if preview != "" {
+			activity += " " + preview
+		}
+		// keep at least some of the title visible
+		activity = truncate.StringWithTail(activity, uint(max(0, width/2)), "…")
+	}
+	var unread string
+	if count := s.activity.Unread(s.id); count > 0 && !s.isDeleteConfirming {
+		unread = fmt.Sprintf(" •%d", count)
+	}
+	truncatedStr := truncate.StringWithTail(
+		text,
+		uint(max(0, width-1-lipgloss.Width(tags)-lipgloss.Width(activity)-lipgloss.Width(unread))),
+		"...",
+	)
 
 	var itemStyle styles.Style
 	if selected {
@@ -72,17 +104,51 @@ func (s sessionItem) Render(selected bool, width int) string {
 		}
 	}
 
+	if tags != "" {
+		tagStyle := baseStyle.Foreground(t.TextMuted())
+		if selected {
+			tagStyle = baseStyle.Background(t.Primary()).Foreground(t.BackgroundElement())
+		}
+		truncatedStr += tagStyle.Render(tags)
+	}
+	if activity != "" {
+		activityStyle := baseStyle.Foreground(t.Accent())
+		if selected {
+			activityStyle = baseStyle.Background(t.Primary()).Foreground(t.BackgroundElement())
+		}
+		truncatedStr += activityStyle.Render(activity)
+	}
+	if unread != "" {
+		unreadStyle := baseStyle.Foreground(t.Warning()).Bold(true)
+		if selected {
+			unreadStyle = baseStyle.Background(t.Primary()).Foreground(t.BackgroundElement()).Bold(true)
+		}
+		truncatedStr += unreadStyle.Render(unread)
+	}
 	return itemStyle.Render(truncatedStr)
 }
 
+func (s sessionItem) FilterValue() string {
+	return s.title + " " + strings.Join(s.tags, " ")
+}
+
+// sessionViews are the sections of the session dialog cycled with tab. The
+// empty view lists all active sessions, the tag views filter them by tag.
+var sessionViews = append(append([]string{""}, app.SessionTags...), "archived")
+
 type sessionDialog struct {
 	width              int
 	height             int
 	modal              *modal.Modal
+	all                []opencode.Session
 	sessions           []opencode.Session
 	list               list.List[sessionItem]
 	app                *app.App
 	deleteConfirmation int // -1 means no confirmation, >= 0 means confirming deletion of session at this index
+	depths             map[string]int
+	view               string
+	tagging            bool
+	deleted            map[string]bool // deleted in this dialog, shown again when undone
 }
 
 func (s *sessionDialog) Init() tea.Cmd {
@@ -95,8 +161,54 @@ func (s *sessionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.width = msg.Width
 		s.height = msg.Height
 		s.list.SetMaxWidth(layout.Current.Container.Width - 12)
+	case app.SessionsUpdatedMsg:
+		for _, session := range msg {
+			if idx := slices.IndexFunc(s.all, func(sess opencode.Session) bool {
+				return sess.ID == session.ID
+			}); idx >= 0 {
+				s.all[idx] = session
+			}
+		}
+		s.refresh()
+		return s, nil
 	case tea.KeyPressMsg:
+		if s.tagging {
+			s.tagging = false
+			if _, idx := s.list.GetSelectedItem(); idx >= 0 && idx < len(s.sessions) {
+				for _, tag := range app.SessionTags {
+					if msg.String() == tag[:1] {
+						s.app.ToggleSessionTag(s.sessions[idx].ID, tag)
+						s.refresh()
+					}
+				}
+			}
+			return s, nil
+		}
 		switch msg.String() {
+		case "tab":
+			idx := slices.Index(sessionViews, s.view)
+			s.view = sessionViews[(idx+1)%len(sessionViews)]
+			s.deleteConfirmation = -1
+			s.refresh()
+			return s, nil
+		case "ctrl+t":
+			if _, idx := s.list.GetSelectedItem(); idx >= 0 && idx < len(s.sessions) {
+				s.tagging = true
+			}
+			return s, nil
+		case "ctrl+a":
+			if _, idx := s.list.GetSelectedItem(); idx >= 0 && idx < len(s.sessions) {
+				session := s.sessions[idx]
+				archived := !s.app.SessionMeta(session.ID).Archived
+				s.app.SetSessionArchived(session.ID, archived)
+				s.deleteConfirmation = -1
+				s.refresh()
+				if archived {
+					return s, toast.NewInfoToast("Archived " + session.Title)
+				}
+				return s, toast.NewInfoToast("Restored " + session.Title)
+			}
+			return s, nil
 		case "enter":
 			if s.deleteConfirmation >= 0 {
 				s.deleteConfirmation = -1
@@ -110,19 +222,20 @@ func (s *sessionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					util.CmdHandler(app.SessionSelectedMsg(&selectedSession)),
 				)
 			}
-		case "x", "delete", "backspace":
+		case "ctrl+d", "delete":
 			if _, idx := s.list.GetSelectedItem(); idx >= 0 && idx < len(s.sessions) {
 				if s.deleteConfirmation == idx {
-					// Second press - actually delete the session
+					// Second press - hide the session, it is deleted once the
+					// undo window closed
 					sessionToDelete := s.sessions[idx]
-					return s, tea.Sequence(
-						func() tea.Msg {
-							s.sessions = slices.Delete(s.sessions, idx, idx+1)
-							s.deleteConfirmation = -1
-							s.updateListItems()
-							return nil
-						},
+					s.deleted[sessionToDelete.ID] = true
+					s.deleteConfirmation = -1
+					s.refresh()
+					return s, s.app.Defer(
+						pendingSessionKey(sessionToDelete.ID),
+						"Deleted "+sessionToDelete.Title,
 						s.deleteSession(sessionToDelete.ID),
+						nil,
 					)
 				} else {
 					// First press - enter delete confirmation mode
@@ -131,6 +244,14 @@ func (s *sessionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return s, nil
 				}
 			}
+		case "ctrl+z":
+			key, label, undo, ok := s.app.Pending.Undo()
+			if !ok {
+				return s, toast.NewInfoToast("Nothing to undo")
+			}
+			delete(s.deleted, strings.TrimPrefix(key, pendingSessionKey("")))
+			s.refresh()
+			return s, tea.Batch(undo, toast.NewSuccessToast("Undone: "+label))
 		case "esc":
 			if s.deleteConfirmation >= 0 {
 				s.deleteConfirmation = -1
@@ -151,8 +272,30 @@ func (s *sessionDialog) Render(background string) string {
 
 	t := theme.CurrentTheme()
 	helpStyle := styles.NewStyle().PaddingLeft(1).PaddingTop(1)
-	helpText := styles.NewStyle().Foreground(t.Text()).Render("x/del")
-	helpText = helpText + styles.NewStyle().Background(t.BackgroundElement()).Foreground(t.TextMuted()).Render(" delete session")
+	keyStyle := styles.NewStyle().Foreground(t.Text())
+	descStyle := styles.NewStyle().Background(t.BackgroundElement()).Foreground(t.TextMuted())
+	var helpText string
+	if s.tagging {
+		for i, tag := range app.SessionTags {
+			if i > 0 {
+				helpText += descStyle.Render(" · ")
+			}
+			helpText += keyStyle.Render(tag[:1]) + descStyle.Render(" "+tag)
+		}
+		helpText += descStyle.Render("  toggle tag")
+	} else {
+		archive := " archive"
+		if s.view == "archived" {
+			archive = " restore"
+		}
+		helpText = keyStyle.Render("tab") + descStyle.Render(" section · ") +
+			keyStyle.Render("ctrl+t") + descStyle.Render(" tag · ") +
+			keyStyle.Render("ctrl+a") + descStyle.Render(archive+" · ") +
+			keyStyle.Render("ctrl+d/del") + descStyle.Render(" delete")
+		if s.undoable() {
+			helpText += descStyle.Render(" · ") + keyStyle.Render("ctrl+z") + descStyle.Render(" undo")
+		}
+	}
 	helpText = helpStyle.Render(helpText)
 
 	content := strings.Join([]string{listView, helpText}, "\n")
@@ -160,19 +303,83 @@ func (s *sessionDialog) Render(background string) string {
 	return s.modal.Render(content, background)
 }
 
+// refresh recomputes the sessions shown in the current view
+func (s *sessionDialog) refresh() {
+	s.sessions = s.sessions[:0]
+	for _, sess := range s.all {
+		if s.deleted[sess.ID] || s.app.Pending.Pending(pendingSessionKey(sess.ID)) {
+			continue
+		}
+		meta := s.app.SessionMeta(sess.ID)
+		switch s.view {
+		case "":
+			if meta.Archived {
+				continue
+			}
+		case "archived":
+			if !meta.Archived {
+				continue
+			}
+		default:
+			if meta.Archived || !slices.Contains(meta.Tags, s.view) {
+				continue
+			}
+		}
+		s.sessions = append(s.sessions, sess)
+	}
+	// branches are listed under the session they were branched from
+	s.sessions, s.depths = app.GroupBranches(s.sessions)
+
+	title := "Switch Session"
+	empty := "No sessions available"
+	switch s.view {
+	case "":
+	case "archived":
+		title = "Archived Sessions"
+		empty = "No archived sessions"
+	default:
+		title += " · " + s.view
+		empty = "No sessions tagged " + s.view
+	}
+	s.modal.SetTitle(title)
+	s.list.SetEmptyMessage(empty)
+	s.updateListItems()
+}
+
 func (s *sessionDialog) updateListItems() {
 	_, currentIdx := s.list.GetSelectedItem()
 
 	var items []sessionItem
 	for i, sess := range s.sessions {
 		item := sessionItem{
+			id:                 sess.ID,
+			activity:           s.app.Activity,
 			title:              sess.Title,
+			tags:               s.app.SessionMeta(sess.ID).Tags,
 			isDeleteConfirming: s.deleteConfirmation == i,
+			depth:              s.depths[sess.ID],
 		}
 		items = append(items, item)
 	}
 	s.list.SetItems(items)
-	s.list.SetSelectedIndex(currentIdx)
+	s.list.SetSelectedIndex(max(0, min(currentIdx, len(items)-1)))
+}
+
+// undoable reports whether a deletion made in this dialog can still be
+// undone
+func (s *sessionDialog) undoable() bool {
+	for id := range s.deleted {
+		if s.app.Pending.Pending(pendingSessionKey(id)) {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingSessionKey is the key a session deletion waits under in the undo
+// queue
+func pendingSessionKey(sessionID string) string {
+	return "session:" + sessionID
 }
 
 func (s *sessionDialog) deleteSession(sessionID string) tea.Cmd {
@@ -194,35 +401,34 @@ func NewSessionDialog(app *app.App) SessionDialog {
 	sessions, _ := app.ListSessions(context.Background())
 
 	var filteredSessions []opencode.Session
-	var items []sessionItem
 	for _, sess := range sessions {
 		if sess.ParentID != "" {
 			continue
 		}
 		filteredSessions = append(filteredSessions, sess)
-		items = append(items, sessionItem{
-			title:              sess.Title,
-			isDeleteConfirming: false,
-		})
 	}
 
 	// Create a generic list component
 	listComponent := list.NewListComponent(
-		items,
+		[]sessionItem{},
 		10, // maxVisibleSessions
 		"No sessions available",
 		true, // useAlphaNumericKeys
 	)
 	listComponent.SetMaxWidth(layout.Current.Container.Width - 12)
+	listComponent.SetFilterable(true)
 
-	return &sessionDialog{
-		sessions:           filteredSessions,
+	s := &sessionDialog{
+		all:                filteredSessions,
 		list:               listComponent,
 		app:                app,
 		deleteConfirmation: -1,
+		deleted:            make(map[string]bool),
 		modal: modal.New(
 			modal.WithTitle("Switch Session"),
 			modal.WithMaxWidth(layout.Current.Container.Width-8),
 		),
 	}
+	s.refresh()
+	return s
 }
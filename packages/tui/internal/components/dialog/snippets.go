@@ -0,0 +1,238 @@
+package dialog
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// SnippetsDialog interface for managing the editor snippets
+type SnippetsDialog interface {
+	layout.Modal
+}
+
+type snippetItem struct {
+	snippet app.Snippet
+}
+
+func (s snippetItem) Render(selected bool, width int) string {
+	th := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(th.BackgroundElement())
+	if selected {
+		baseStyle = styles.NewStyle().Background(th.BackgroundPanel())
+	}
+
+	triggerStyle := baseStyle.Foreground(th.Text())
+	if selected {
+		triggerStyle = triggerStyle.Foreground(th.Primary()).Bold(true)
+	}
+	trigger := triggerStyle.Render(" " + s.snippet.Trigger)
+	preview := strings.Join(strings.Fields(s.snippet.Text), " ")
+	previewWidth := max(0, width-lipgloss.Width(trigger)-2)
+	preview = baseStyle.Foreground(th.TextMuted()).Render("  " + truncate.StringWithTail(preview, uint(previewWidth), "…"))
+	line := trigger + preview
+	gap := max(0, width-lipgloss.Width(line))
+	return line + baseStyle.Render(strings.Repeat(" ", gap))
+}
+
+func (s snippetItem) FilterValue() string {
+	return s.snippet.Trigger + " " + s.snippet.Text
+}
+
+type snippetsDialog struct {
+	app   *app.App
+	modal *modal.Modal
+	list  list.List[snippetItem]
+
+	// edit stage, active while a snippet is added or edited
+	editing  bool
+	previous string // trigger of the edited snippet, empty when adding
+	trigger  textinput.Model
+	text     textinput.Model
+	err      error
+}
+
+func (d *snippetsDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *snippetsDialog) refresh() {
+	snippets := d.app.Snippets.List()
+	items := make([]snippetItem, 0, len(snippets))
+	for _, snippet := range snippets {
+		items = append(items, snippetItem{snippet: snippet})
+	}
+	d.list.SetItems(items)
+}
+
+// edit starts editing a snippet, a new one when snippet is empty
+func (d *snippetsDialog) edit(snippet app.Snippet) tea.Cmd {
+	d.editing = true
+	d.previous = snippet.Trigger
+	d.err = nil
+	d.trigger.SetValue(snippet.Trigger)
+	d.text.SetValue(snippet.Text)
+	d.text.Blur()
+	return d.trigger.Focus()
+}
+
+// save stores the edited snippet and goes back to the list
+func (d *snippetsDialog) save() tea.Cmd {
+	snippet := app.Snippet{Trigger: d.trigger.Value(), Text: d.text.Value()}
+	if err := d.app.Snippets.Set(d.previous, snippet); err != nil {
+		d.err = err
+		return nil
+	}
+	d.editing = false
+	d.trigger.Blur()
+	d.text.Blur()
+	d.refresh()
+	return toast.NewSuccessToast("Saved snippet " + strings.TrimSpace(snippet.Trigger))
+}
+
+func (d *snippetsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.list.SetMaxWidth(layout.Current.Container.Width - 12)
+		d.trigger.SetWidth(layout.Current.Container.Width - 14)
+		d.text.SetWidth(layout.Current.Container.Width - 14)
+	case tea.KeyPressMsg:
+		if d.editing {
+			switch msg.String() {
+			case "enter":
+				return d, d.save()
+			case "tab", "shift+tab":
+				if d.trigger.Focused() {
+					d.trigger.Blur()
+					return d, d.text.Focus()
+				}
+				d.text.Blur()
+				return d, d.trigger.Focus()
+			}
+			var cmd tea.Cmd
+			if d.trigger.Focused() {
+				d.trigger, cmd = d.trigger.Update(msg)
+			} else {
+				d.text, cmd = d.text.Update(msg)
+			}
+			return d, cmd
+		}
+		switch msg.String() {
+		case "enter":
+			if item, idx := d.list.GetSelectedItem(); idx >= 0 {
+				return d, d.edit(item.snippet)
+			}
+			return d, nil
+		case "ctrl+a":
+			return d, d.edit(app.Snippet{})
+		case "ctrl+d", "delete":
+			item, idx := d.list.GetSelectedItem()
+			if idx < 0 {
+				return d, nil
+			}
+			if err := d.app.Snippets.Delete(item.snippet.Trigger); err != nil {
+				return d, toast.NewErrorToast(err.Error())
+			}
+			d.refresh()
+			return d, toast.NewInfoToast("Deleted snippet " + item.snippet.Trigger)
+		}
+	case tea.PasteMsg:
+		if d.editing {
+			var cmd tea.Cmd
+			if d.trigger.Focused() {
+				d.trigger, cmd = d.trigger.Update(msg)
+			} else {
+				d.text, cmd = d.text.Update(msg)
+			}
+			return d, cmd
+		}
+	}
+
+	if d.editing {
+		return d, nil
+	}
+	listModel, cmd := d.list.Update(msg)
+	d.list = listModel.(list.List[snippetItem])
+	return d, cmd
+}
+
+func (d *snippetsDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if d.editing {
+		label := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+		lines := []string{
+			label.Render("Trigger"), d.trigger.View(),
+			label.PaddingTop(1).Render("Expands to"), d.text.View(),
+		}
+		if d.err != nil {
+			lines = append(lines, styles.NewStyle().Foreground(t.Error()).Background(t.BackgroundElement()).PaddingTop(1).Render(d.err.Error()))
+		}
+		lines = append(lines, muted.PaddingTop(1).Render("tab next field · enter save · esc close"))
+		return strings.Join(lines, "\n")
+	}
+
+	help := muted.PaddingTop(1).Width(layout.Current.Container.Width - 12).Render(
+		"enter edit · ctrl+a add · ctrl+d delete · a trigger followed by a space expands · " + d.app.Snippets.Path(),
+	)
+	return d.list.View() + "\n" + help
+}
+
+func (d *snippetsDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *snippetsDialog) Close() tea.Cmd {
+	d.trigger.Blur()
+	d.text.Blur()
+	return nil
+}
+
+// newFieldInput creates a text input styled like the other dialog inputs
+func newFieldInput(placeholder string) textinput.Model {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = placeholder
+	input.SetWidth(layout.Current.Container.Width - 14)
+	input.Styles.Focused.Prompt = styles.NewStyle().Foreground(t.Primary()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred = input.Styles.Focused
+	input.Styles.Cursor.Color = t.Primary()
+	return input
+}
+
+// NewSnippetsDialog creates a dialog to add, edit and delete the snippets
+// the editor expands
+func NewSnippetsDialog(app *app.App) SnippetsDialog {
+	snippets := list.NewListComponent([]snippetItem{}, 10, "No snippets, ctrl+a adds one", false)
+	snippets.SetFilterable(true)
+	snippets.SetMaxWidth(layout.Current.Container.Width - 12)
+
+	d := &snippetsDialog{
+		app:     app,
+		list:    snippets,
+		trigger: newFieldInput(";;rev"),
+		text:    newFieldInput("Please review the following code for bugs:"),
+		modal: modal.New(
+			modal.WithTitle("Snippets"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	d.refresh()
+	return d
+}
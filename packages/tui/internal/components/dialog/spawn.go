@@ -0,0 +1,273 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// SpawnAgentsDialog interface for launching several agents on one goal
+type SpawnAgentsDialog interface {
+	layout.Modal
+}
+
+// the fields before the per agent task and model inputs
+const (
+	spawnFieldGoal = iota
+	spawnFieldCount
+	spawnFieldTools
+	spawnFieldAgents
+)
+
+type spawnAgentsDialog struct {
+	app        *app.App
+	modal      *modal.Modal
+	goal       textinput.Model
+	tasks      []textinput.Model
+	models     []textinput.Model
+	count      int
+	writable   bool
+	focus      int
+	previewing bool
+	prompt     string
+	preview    viewport.Model
+	width      int
+}
+
+func (s *spawnAgentsDialog) Init() tea.Cmd {
+	return s.goal.Focus()
+}
+
+// fields returns how many fields the form cycles through
+func (s *spawnAgentsDialog) fields() int {
+	return spawnFieldAgents + 2*s.count
+}
+
+// input returns the text input of a field, nil for the count and tools
+func (s *spawnAgentsDialog) input(field int) *textinput.Model {
+	switch {
+	case field == spawnFieldGoal:
+		return &s.goal
+	case field < spawnFieldAgents:
+		return nil
+	case (field-spawnFieldAgents)%2 == 0:
+		return &s.tasks[(field-spawnFieldAgents)/2]
+	default:
+		return &s.models[(field-spawnFieldAgents)/2]
+	}
+}
+
+func (s *spawnAgentsDialog) moveFocus(delta int) tea.Cmd {
+	if input := s.input(s.focus); input != nil {
+		input.Blur()
+	}
+	s.focus = (s.focus + delta + s.fields()) % s.fields()
+	if input := s.input(s.focus); input != nil {
+		return input.Focus()
+	}
+	return nil
+}
+
+// setCount grows or shrinks the agents, keeping what was typed for the
+// agents that remain
+func (s *spawnAgentsDialog) setCount(count int) {
+	s.count = max(1, min(app.MaxSpawnAgents, count))
+	for len(s.tasks) < s.count {
+		n := len(s.tasks) + 1
+		s.tasks = append(s.tasks, s.newInput(fmt.Sprintf("Agent %d sub-task (optional)", n)))
+		s.models = append(s.models, s.newInput("Model as provider/model (optional)"))
+	}
+}
+
+func (s *spawnAgentsDialog) specs() []app.AgentSpec {
+	specs := make([]app.AgentSpec, s.count)
+	for i := range specs {
+		specs[i] = app.AgentSpec{Task: s.tasks[i].Value(), Model: s.models[i].Value()}
+	}
+	return specs
+}
+
+// showPreview builds the task tool calls and shows them before launching
+func (s *spawnAgentsDialog) showPreview() tea.Cmd {
+	invocations, err := app.SpawnInvocations(s.goal.Value(), s.specs(), s.writable)
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	prompt, err := app.SpawnPrompt(invocations)
+	if err != nil {
+		return toast.NewErrorToast(err.Error())
+	}
+	s.prompt = prompt
+	s.previewing = true
+	s.preview.SetContent(lipgloss.NewStyle().Width(s.width).Render(prompt))
+	s.preview.GotoTop()
+	return nil
+}
+
+func (s *spawnAgentsDialog) launch() tea.Cmd {
+	if s.app.IsBusy() {
+		return toast.NewInfoToast("Wait for the current response to finish")
+	}
+	// close the dialogs it was opened from too
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseAllModalsMsg{}),
+		util.CmdHandler(app.SendMsg{Text: s.prompt}),
+	)
+}
+
+func (s *spawnAgentsDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.setSize()
+	case tea.KeyPressMsg:
+		if s.previewing {
+			switch msg.String() {
+			case "enter":
+				return s, s.launch()
+			case "backspace":
+				s.previewing = false
+				return s, nil
+			}
+			var cmd tea.Cmd
+			s.preview, cmd = s.preview.Update(msg)
+			return s, cmd
+		}
+
+		switch msg.String() {
+		case "enter":
+			return s, s.showPreview()
+		case "tab", "down":
+			return s, s.moveFocus(1)
+		case "shift+tab", "up":
+			return s, s.moveFocus(-1)
+		}
+		switch s.focus {
+		case spawnFieldCount:
+			switch msg.String() {
+			case "left", "-", "h":
+				s.setCount(s.count - 1)
+			case "right", "+", "l":
+				s.setCount(s.count + 1)
+			}
+			return s, nil
+		case spawnFieldTools:
+			switch msg.String() {
+			case "left", "right", "space", "h", "l":
+				s.writable = !s.writable
+			}
+			return s, nil
+		}
+	}
+
+	if input := s.input(s.focus); input != nil {
+		var cmd tea.Cmd
+		*input, cmd = input.Update(msg)
+		return s, cmd
+	}
+	return s, nil
+}
+
+func (s *spawnAgentsDialog) setSize() {
+	s.width = min(76, layout.Current.Container.Width-12)
+	s.goal.SetWidth(s.width)
+	for i := range s.tasks {
+		s.tasks[i].SetWidth(s.width - 4)
+		s.models[i].SetWidth(s.width - 4)
+	}
+	s.preview.SetWidth(s.width)
+	s.preview.SetHeight(max(6, layout.Current.Viewport.Height-14))
+}
+
+func (s *spawnAgentsDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+
+	if s.previewing {
+		help := muted.PaddingTop(1).Render("enter launch · ↑/↓ scroll · backspace edit")
+		return muted.Render("This message will be sent:") + "\n\n" + s.preview.View() + "\n" + help
+	}
+
+	label := func(field int, text string) string {
+		if field == s.focus {
+			return base.Foreground(t.Primary()).Bold(true).Render(text)
+		}
+		return muted.Render(text)
+	}
+	tools := "read-only"
+	if s.writable {
+		tools = "all tools"
+	}
+
+	lines := []string{
+		label(spawnFieldGoal, "Goal"),
+		s.goal.View(),
+		"",
+		label(spawnFieldCount, "Agents ") + base.Render(fmt.Sprintf("◂ %d ▸", s.count)) +
+			"   " + label(spawnFieldTools, "Tools ") + base.Render("◂ "+tools+" ▸"),
+		"",
+	}
+	for i := range s.count {
+		field := spawnFieldAgents + 2*i
+		lines = append(lines,
+			label(field, fmt.Sprintf("%d. ", i+1))+s.tasks[i].View(),
+			label(field+1, "   ")+s.models[i].View(),
+		)
+	}
+	help := muted.PaddingTop(1).Render("tab next field · ←/→ change · enter preview · esc cancel")
+	return strings.Join(lines, "\n") + "\n" + help
+}
+
+func (s *spawnAgentsDialog) Render(background string) string {
+	return s.modal.Render(s.View(), background)
+}
+
+func (s *spawnAgentsDialog) Close() tea.Cmd {
+	if input := s.input(s.focus); input != nil {
+		input.Blur()
+	}
+	return nil
+}
+
+func (s *spawnAgentsDialog) newInput(placeholder string) textinput.Model {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = placeholder
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred = input.Styles.Focused
+	input.Styles.Cursor.Color = t.Primary()
+	input.SetWidth(s.width - 4)
+	return input
+}
+
+// NewSpawnAgentsDialog creates a dialog that splits a goal across several
+// agents, previews the task tool calls and asks the assistant to make them
+func NewSpawnAgentsDialog(app *app.App) SpawnAgentsDialog {
+	s := &spawnAgentsDialog{
+		app:     app,
+		preview: viewport.New(),
+		modal: modal.New(
+			modal.WithTitle("Spawn Agents"),
+			modal.WithMaxWidth(80),
+		),
+	}
+	s.goal = s.newInput("What should the agents accomplish?")
+	s.setCount(3)
+	s.setSize()
+	return s
+}
@@ -1,11 +1,15 @@
 package dialog
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/v2/spinner"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/muesli/reflow/truncate"
@@ -15,7 +19,9 @@ import (
 	"github.com/sst/dgmo/internal/components/toast"
 	"github.com/sst/dgmo/internal/layout"
 	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/styles/glyphs"
 	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
 )
 
 // SubSessionDialog interface for the sub-session navigation dialog
@@ -25,17 +31,20 @@ type SubSessionDialog interface {
 
 // subSessionItem is a custom list item for sub-sessions
 type subSessionItem struct {
-	sessionID string
-	agentName string
-	task      string
-	status    string
-	createdAt time.Time
-	parentID  string
-	isChild   bool
-	level     int
+	sessionID   string
+	agentName   string
+	agentNumber int
+	task        string
+	status      string
+	progress    string // live progress of the agent, if it is running
+	createdAt   time.Time
+	parentID    string
+	level       int
 	// New fields for better display
-	displayType string // "direct-child", "sibling", "all"
-	note        string
+	displayType string // "direct-child", "descendant" or "sibling"
+	guides      string // tree lines of the ancestors and the branch to the item
+	hasChildren bool
+	collapsed   bool
 }
 
 func (s subSessionItem) Render(selected bool, width int) string {
@@ -43,17 +52,18 @@ func (s subSessionItem) Render(selected bool, width int) string {
 	baseStyle := styles.NewStyle()
 
 	// Format the display text
-	statusIcon := "●"
+	g := glyphs.Current()
+	statusIcon := g.Dot
 	statusColor := t.Secondary()
 	switch s.status {
 	case "running":
-		statusIcon = "▶"
+		statusIcon = g.Running
 		statusColor = t.Primary()
 	case "completed":
-		statusIcon = "✓"
+		statusIcon = g.Done
 		statusColor = t.Success()
 	case "failed":
-		statusIcon = "✗"
+		statusIcon = g.Failed
 		statusColor = t.Error()
 	}
 
@@ -62,16 +72,23 @@ func (s subSessionItem) Render(selected bool, width int) string {
 
 	// Build the display string with context
 	prefix := ""
-	switch s.displayType {
-	case "sibling":
+	if s.displayType == "sibling" {
 		prefix = "[Sibling] "
-	case "all":
-		if s.note != "" {
-			prefix = fmt.Sprintf("[%s] ", s.note)
+	}
+
+	expander := "  "
+	if s.hasChildren {
+		expander = "▾ "
+		if s.collapsed {
+			expander = "▸ "
 		}
 	}
+	detail := timeStr
+	if s.progress != "" {
+		detail = s.progress
+	}
 
-	text := fmt.Sprintf("%s%s %s - %s (%s)", prefix, statusIcon, s.agentName, s.task, timeStr)
+	text := fmt.Sprintf("%s%s%s%s %s - %s (%s)", s.guides, expander, prefix, statusIcon, s.agentName, s.task, detail)
 	truncatedStr := truncate.StringWithTail(text, uint(width-1), "...")
 
 	var itemStyle styles.Style
@@ -91,78 +108,93 @@ func (s subSessionItem) Render(selected bool, width int) string {
 	return itemStyle.Render(truncatedStr)
 }
 
+func (s subSessionItem) FilterValue() string {
+	return s.agentName + " " + s.task
+}
+
 type subSessionDialog struct {
 	width          int
 	height         int
 	modal          *modal.Modal
-	subSessions    []map[string]interface{}
+	subSessions    []app.SubSession
+	displayTypes   map[string]string // how each sub-session relates to the current one
 	list           list.List[subSessionItem]
 	app            *app.App
 	currentSession string
+	spinner        spinner.Model
+	pending        int // strategies still loading
+	generation     int // incremented on refresh to drop stale results
+	ctx            context.Context
+	cancel         context.CancelFunc
+	collapsed      map[string]bool // sessions whose children are hidden
+	depth          map[string]int  // levels below the current session, for descendants
 }
 
+// maxSubSessionDepth is how many levels of descendants are loaded
+const maxSubSessionDepth = 4
+
 func (s *subSessionDialog) Init() tea.Cmd {
-	// Following the sessions dialog pattern - data is loaded in constructor
-	return nil
+	return s.refresh()
 }
 
-func (s *subSessionDialog) loadSubSessions() tea.Msg {
-	// Get current session ID
+// subSessionsStrategyMsg carries the result of one sub-session lookup
+type subSessionsStrategyMsg struct {
+	generation  int
+	displayType string
+	subSessions []app.SubSession
+	err         error
+}
+
+// refresh cancels any in-flight lookups and starts loading sub-sessions again.
+// Each strategy streams its results back independently.
+func (s *subSessionDialog) refresh() tea.Cmd {
+	if s.cancel != nil {
+		s.cancel()
+	}
 	currentSession := s.app.Session
-	if currentSession == nil {
+	if currentSession == nil || currentSession.ID == "" {
 		return toast.NewErrorToast("No active session")
 	}
 
-	ctx := context.Background()
-	var allSubSessions []map[string]interface{}
-
-	// Strategy 1: Get direct children of current session
-	endpoint := fmt.Sprintf("/session/%s/sub-sessions", currentSession.ID)
-	var directChildren []map[string]interface{}
-	err := s.app.Client.Get(ctx, endpoint, nil, &directChildren)
-
-	// Also add a visible indicator in the UI
-	if err != nil {
-		return toast.NewErrorToast(fmt.Sprintf("API Error: %v", err))
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.generation++
+	s.subSessions = nil
+	s.displayTypes = make(map[string]string)
+	s.depth = make(map[string]int)
+	s.currentSession = currentSession.ID
+	s.list.SetItems([]subSessionItem{})
+
+	// Strategy 1: direct children of the current session
+	strategies := []tea.Cmd{
+		s.fetchSubSessions(currentSession.ID, "direct-child", nil),
 	}
 
-	if err == nil && len(directChildren) > 0 {
-		for i := range directChildren {
-			directChildren[i]["_displayType"] = "direct-child"
-		}
-		allSubSessions = append(allSubSessions, directChildren...)
-	}
-
-	// Strategy 2: If current session has a parent, get siblings
+	// Strategy 2: siblings, if the current session has a parent
 	if currentSession.ParentID != "" {
-		parentEndpoint := fmt.Sprintf("/session/%s/sub-sessions", currentSession.ParentID)
-		var siblings []map[string]interface{}
-		err = s.app.Client.Get(ctx, parentEndpoint, nil, &siblings)
-
-		if err == nil && len(siblings) > 0 {
-			// Mark siblings and exclude current session
-			for i := range siblings {
-				if siblings[i]["id"] != currentSession.ID {
-					siblings[i]["_displayType"] = "sibling"
-					siblings[i]["_note"] = "Sibling sub-session"
-					allSubSessions = append(allSubSessions, siblings[i])
-				}
-			}
-		}
+		strategies = append(strategies, s.fetchSubSessions(currentSession.ParentID, "sibling", func(sub app.SubSession) bool {
+			return sub.ID != currentSession.ID
+		}))
 	}
 
-	// Strategy 3: Commented out - we don't want to show ALL sub-sessions
-	// Only show direct children and siblings
-
-	return subSessionsLoadedMsg{
-		subSessions: allSubSessions,
-		currentID:   currentSession.ID,
-	}
+	s.pending = len(strategies)
+	return tea.Batch(append(strategies, s.spinner.Tick)...)
 }
 
-type subSessionsLoadedMsg struct {
-	subSessions []map[string]interface{}
-	currentID   string
+// fetchSubSessions loads the sub-sessions of parentID, keeping those
+// accepted by keep, or all of them when it is nil
+func (s *subSessionDialog) fetchSubSessions(parentID, displayType string, keep func(app.SubSession) bool) tea.Cmd {
+	ctx := s.ctx
+	generation := s.generation
+	return func() tea.Msg {
+		subSessions, err := s.app.SubSessions.List(ctx, parentID)
+		if err != nil {
+			return subSessionsStrategyMsg{generation: generation, err: err}
+		}
+		if keep != nil {
+			subSessions = slices.DeleteFunc(subSessions, func(sub app.SubSession) bool { return !keep(sub) })
+		}
+		return subSessionsStrategyMsg{generation: generation, displayType: displayType, subSessions: subSessions}
+	}
 }
 
 func (s *subSessionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -172,46 +204,84 @@ func (s *subSessionDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.height = msg.Height
 		s.list.SetMaxWidth(layout.Current.Container.Width - 12)
 
-	case subSessionsLoadedMsg:
-		s.subSessions = msg.subSessions
-		s.currentSession = msg.currentID
+	case subSessionsStrategyMsg:
+		if msg.generation != s.generation {
+			return s, nil
+		}
+		s.pending--
+		if msg.err != nil {
+			if errors.Is(msg.err, context.Canceled) {
+				return s, nil
+			}
+			return s, toast.NewErrorToast(fmt.Sprintf("API Error: %v", msg.err))
+		}
+		s.mergeSubSessions(msg.subSessions, msg.displayType)
+		return s, s.fetchDescendants(msg.subSessions, msg.displayType)
+
+	case app.TaskStartedMsg, app.TaskProgressMsg, app.TaskCompletedMsg, app.TaskFailedMsg:
+		// the status and progress of the agents are live
+		s.rebuild()
+		return s, nil
 
-		// Build a tree structure from sub-sessions
-		items := s.buildTreeStructure(s.subSessions, msg.currentID)
-		s.list.SetItems(items)
+	case spinner.TickMsg:
+		if s.pending <= 0 {
+			return s, nil
+		}
+		var cmd tea.Cmd
+		s.spinner, cmd = s.spinner.Update(msg)
+		return s, cmd
 
 	case tea.KeyPressMsg:
 		switch msg.String() {
 		case "enter":
-			if len(s.subSessions) > 0 {
-				_, selected := s.list.GetSelectedItem()
-				if selected >= 0 && selected < len(s.subSessions) {
-					// Switch to the selected sub-session
-					subSession := s.subSessions[selected]
-					sessionID, _ := subSession["id"].(string)
-					return s, s.switchToSession(sessionID)
+			if item, selected := s.list.GetSelectedItem(); selected >= 0 && item.sessionID != "" {
+				// Switch to the selected sub-session
+				return s, s.switchToSession(item.sessionID)
+			}
+
+		case "right":
+			if item, selected := s.list.GetSelectedItem(); selected >= 0 && item.hasChildren && item.collapsed {
+				delete(s.collapsed, item.sessionID)
+				s.rebuild()
+			}
+			return s, nil
+
+		case "left":
+			item, selected := s.list.GetSelectedItem()
+			if selected < 0 {
+				return s, nil
+			}
+			if item.hasChildren && !item.collapsed {
+				s.collapsed[item.sessionID] = true
+				s.rebuild()
+				return s, nil
+			}
+			// a leaf or a collapsed node moves up to its parent
+			for i, parent := range s.list.GetItems() {
+				if parent.sessionID == item.parentID {
+					s.list.SetSelectedIndex(i)
+					break
 				}
 			}
+			return s, nil
 
 		case "ctrl+b":
 			// Return to parent session
-			if s.currentSession != "" {
-				// Find parent of current session
-				for _, sub := range s.subSessions {
-					id, _ := sub["id"].(string)
-					if id == s.currentSession {
-						parentID, _ := sub["parentSessionId"].(string) // Fixed field name
-						return s, s.switchToSession(parentID)
-					}
-				}
+			if s.app.Session != nil && s.app.Session.ParentID != "" {
+				return s, s.switchToSession(s.app.Session.ParentID)
 			}
 
 		case "esc", "ctrl+c":
 			return s, nil
 
-		case "r":
+		case "ctrl+r":
 			// Refresh the list
-			return s, s.loadSubSessions
+			s.app.SubSessions.Invalidate()
+			return s, s.refresh()
+
+		case "ctrl+s":
+			// esc in the spawn dialog comes back to this list
+			return s, util.CmdHandler(modal.PushModalMsg{Modal: NewSpawnAgentsDialog(s.app)})
 		}
 	}
 
@@ -247,16 +317,19 @@ func (s *subSessionDialog) Render(background string) string {
 	content.WriteString(breadcrumbStyle.Render(breadcrumb))
 	content.WriteString("\n")
 
-	if len(s.subSessions) == 0 {
+	if len(s.subSessions) == 0 && s.pending > 0 {
+		loadingStyle := styles.NewStyle().
+			Foreground(t.TextMuted()).
+			MarginTop(1)
+		content.WriteString(loadingStyle.Render(s.spinner.View() + " Loading sub-sessions"))
+	} else if len(s.subSessions) == 0 {
 		emptyStyle := styles.NewStyle().
 			Foreground(t.Secondary()).
 			Align(lipgloss.Center).
 			MarginTop(2)
 		content.WriteString(emptyStyle.Render("No sub-sessions found"))
 		content.WriteString("\n\n")
-		content.WriteString(emptyStyle.Render("💡 Create agents using: 'Create X agents to [task]'"))
-		content.WriteString("\n")
-		content.WriteString(emptyStyle.Render("Example: Create 3 agents to analyze this code"))
+		content.WriteString(emptyStyle.Render("💡 Press ctrl+s to spawn agents on a goal"))
 		content.WriteString("\n\n")
 		hintStyle := styles.NewStyle().
 			Foreground(t.Accent()).
@@ -265,13 +338,19 @@ func (s *subSessionDialog) Render(background string) string {
 	} else {
 		// Show list
 		content.WriteString(s.list.View())
+		if s.pending > 0 {
+			content.WriteString("\n")
+			content.WriteString(styles.NewStyle().
+				Foreground(t.TextMuted()).
+				Render(s.spinner.View() + " Loading more"))
+		}
 
 		// Show help
 		helpStyle := styles.NewStyle().
 			Foreground(t.Secondary()).
 			MarginTop(1)
 
-		helpText := "enter: switch • ctrl+b: parent • r: refresh • esc: close"
+		helpText := "enter: switch • ←/→: collapse/expand • type: filter • ctrl+b: parent • ctrl+r: refresh • ctrl+s: spawn • esc: close"
 		content.WriteString("\n")
 		content.WriteString(helpStyle.Render(helpText))
 	}
@@ -285,86 +364,170 @@ func (s *subSessionDialog) View() string {
 }
 
 func (s *subSessionDialog) Close() tea.Cmd {
+	if s.cancel != nil {
+		s.cancel()
+	}
 	return nil
 }
 
-// buildTreeStructure organizes sub-sessions into a tree hierarchy
-func (s *subSessionDialog) buildTreeStructure(subSessions []map[string]interface{}, currentSessionID string) []subSessionItem {
-	var items []subSessionItem
-
-	// Create a map for quick lookup
-	sessionMap := make(map[string]map[string]interface{})
+// mergeSubSessions adds newly loaded sub-sessions and rebuilds the tree,
+// keeping the current selection
+func (s *subSessionDialog) mergeSubSessions(subSessions []app.SubSession, displayType string) {
 	for _, sub := range subSessions {
-		if id, ok := sub["id"].(string); ok {
-			sessionMap[id] = sub
+		if _, seen := s.displayTypes[sub.ID]; seen {
+			continue
 		}
+		s.displayTypes[sub.ID] = displayType
+		s.subSessions = append(s.subSessions, sub)
 	}
+	s.rebuild()
+}
 
-	// Find root sessions (those whose parent is the current session)
-	var roots []map[string]interface{}
+// fetchDescendants loads the children of sub-sessions below the current
+// session, down to maxSubSessionDepth levels
+func (s *subSessionDialog) fetchDescendants(subSessions []app.SubSession, displayType string) tea.Cmd {
+	if displayType == "sibling" {
+		return nil
+	}
+	var cmds []tea.Cmd
 	for _, sub := range subSessions {
-		parentID, _ := sub["parentSessionId"].(string)
-		if parentID == currentSessionID {
-			roots = append(roots, sub)
+		if _, ok := s.depth[sub.ID]; ok {
+			continue
 		}
+		s.depth[sub.ID] = s.depth[sub.ParentID] + 1
+		if s.depth[sub.ID] >= maxSubSessionDepth {
+			continue
+		}
+		cmds = append(cmds, s.fetchSubSessions(sub.ID, "descendant", nil))
 	}
+	s.pending += len(cmds)
+	return tea.Batch(cmds...)
+}
 
-	// If no roots found, show all sessions flat
-	if len(roots) == 0 {
-		for _, sub := range subSessions {
-			items = append(items, s.createSubSessionItem(sub, false, 0))
+// rebuild lays the tree out again, keeping the current selection
+func (s *subSessionDialog) rebuild() {
+	selected, idx := s.list.GetSelectedItem()
+	items := s.buildTreeStructure(s.subSessions)
+	s.list.SetItems(items)
+	if idx >= 0 {
+		for i, item := range items {
+			if item.sessionID == selected.sessionID {
+				s.list.SetSelectedIndex(i)
+				break
+			}
 		}
-		return items
 	}
+}
 
-	// Build tree recursively
-	for _, root := range roots {
-		items = append(items, s.createSubSessionItem(root, false, 0))
-		items = append(items, s.buildSubTree(sessionMap, root["id"].(string), 1)...)
+// buildTreeStructure organizes sub-sessions into a tree hierarchy. Sessions
+// whose parent was not loaded, the children and the siblings of the current
+// session, are the roots.
+func (s *subSessionDialog) buildTreeStructure(subSessions []app.SubSession) []subSessionItem {
+	children := make(map[string][]app.SubSession)
+	var roots []app.SubSession
+	for _, sub := range subSessions {
+		if _, loaded := s.displayTypes[sub.ParentID]; loaded {
+			children[sub.ParentID] = append(children[sub.ParentID], sub)
+		} else {
+			roots = append(roots, sub)
+		}
 	}
+	// children of the current session before its siblings
+	slices.SortStableFunc(roots, func(a, b app.SubSession) int {
+		return cmp.Compare(s.displayOrder(a), s.displayOrder(b))
+	})
 
-	return items
-}
-
-// buildSubTree recursively builds child sessions
-func (s *subSessionDialog) buildSubTree(sessionMap map[string]map[string]interface{}, parentID string, level int) []subSessionItem {
 	var items []subSessionItem
-
-	for _, sub := range sessionMap {
-		if subParentID, _ := sub["parentSessionId"].(string); subParentID == parentID {
-			items = append(items, s.createSubSessionItem(sub, true, level))
-			// Recursively add children
-			if id, ok := sub["id"].(string); ok {
-				items = append(items, s.buildSubTree(sessionMap, id, level+1)...)
+	var walk func(subs []app.SubSession, level int, ancestors string)
+	walk = func(subs []app.SubSession, level int, ancestors string) {
+		slices.SortStableFunc(subs, func(a, b app.SubSession) int {
+			return cmp.Compare(a.CreatedAt, b.CreatedAt)
+		})
+		for i, sub := range subs {
+			last := i == len(subs)-1
+			item := s.createSubSessionItem(sub, level)
+			branch, guide := "├─ ", "│  "
+			if last {
+				branch, guide = "└─ ", "   "
+			}
+			if level > 0 {
+				item.guides = ancestors + branch
+			}
+			item.hasChildren = len(children[item.sessionID]) > 0
+			item.collapsed = s.collapsed[item.sessionID]
+			items = append(items, item)
+			if item.hasChildren && !item.collapsed {
+				next := ancestors
+				if level > 0 {
+					next += guide
+				}
+				walk(children[item.sessionID], level+1, next)
 			}
 		}
 	}
-
+	walk(roots, 0, "")
 	return items
 }
 
-// createSubSessionItem creates a subSessionItem from raw data
-func (s *subSessionDialog) createSubSessionItem(sub map[string]interface{}, isChild bool, level int) subSessionItem {
-	sessionID, _ := sub["id"].(string)
-	agentName, _ := sub["agentName"].(string)
-	task, _ := sub["taskDescription"].(string)
-	status, _ := sub["status"].(string)
-	createdAt, _ := sub["createdAt"].(float64)
-	parentID, _ := sub["parentSessionId"].(string)
-	displayType, _ := sub["_displayType"].(string)
-	note, _ := sub["_note"].(string)
-
-	return subSessionItem{
-		sessionID:   sessionID,
-		agentName:   agentName,
-		task:        task,
-		status:      status,
-		createdAt:   time.Unix(int64(createdAt)/1000, 0),
-		parentID:    parentID,
-		isChild:     isChild,
+// displayOrder sorts the children of the current session before its
+// siblings
+func (s *subSessionDialog) displayOrder(sub app.SubSession) int {
+	if s.displayTypes[sub.ID] == "sibling" {
+		return 1
+	}
+	return 0
+}
+
+// createSubSessionItem creates a subSessionItem for a sub-session, with the
+// live status of its agent when the task client knows it
+func (s *subSessionDialog) createSubSessionItem(sub app.SubSession, level int) subSessionItem {
+	item := subSessionItem{
+		sessionID:   sub.ID,
+		agentName:   sub.AgentName,
+		agentNumber: sub.AgentNumber,
+		task:        sub.Task,
+		status:      string(sub.Status),
+		createdAt:   sub.Created(),
+		parentID:    sub.ParentID,
 		level:       level,
-		displayType: displayType,
-		note:        note,
+		displayType: s.displayTypes[sub.ID],
+	}
+	if live, ok := s.liveTask(sub.ParentID, sub.AgentNumber); ok {
+		item.status, item.progress = taskStatus(live)
+	}
+	return item
+}
+
+// liveTask returns the task of a sub-session's agent, matched by the parent
+// session and the agent number
+func (s *subSessionDialog) liveTask(parentID string, agentNumber int) (app.TaskInfo, bool) {
+	if s.app.TaskClient == nil || agentNumber == 0 {
+		return app.TaskInfo{}, false
+	}
+	for _, task := range s.app.TaskClient.Tasks() {
+		if task.SessionID == parentID && task.AgentNumber == agentNumber {
+			return task, true
+		}
+	}
+	return app.TaskInfo{}, false
+}
+
+// taskStatus returns the status of a task as the sub-session records name
+// it, and its progress while it runs
+func taskStatus(task app.TaskInfo) (string, string) {
+	switch task.Status {
+	case app.TaskStatusRunning:
+		progress := fmt.Sprintf("%d%%", task.Progress)
+		if task.CurrentTool != "" {
+			progress += " · " + task.CurrentTool
+		}
+		return "running", progress
+	case app.TaskStatusCompleted:
+		return "completed", ""
+	case app.TaskStatusFailed:
+		return "failed", ""
+	default:
+		return "pending", ""
 	}
 }
 
@@ -381,62 +544,22 @@ func NewSubSessionDialog(app *app.App) SubSessionDialog {
 
 	list := list.NewListComponent([]subSessionItem{}, 10, "No sub-sessions", true)
 	list.SetMaxWidth(width - 12)
+	list.SetFilterable(true)
 
+	t := theme.CurrentTheme()
 	dialog := &subSessionDialog{
-		width:  width,
-		height: height,
-		modal:  modal,
-		list:   list,
-		app:    app,
-	}
-
-	// Load sub-sessions immediately following the sessions dialog pattern
-	ctx := context.Background()
-	currentSession := app.Session
-	if currentSession != nil {
-
-		var allSubSessions []map[string]interface{}
-
-		// Strategy 1: Get direct children of current session
-		endpoint := fmt.Sprintf("/session/%s/sub-sessions", currentSession.ID)
-		var directChildren []map[string]interface{}
-		err := app.Client.Get(ctx, endpoint, nil, &directChildren)
-
-		if err == nil && len(directChildren) > 0 {
-
-			for i := range directChildren {
-				directChildren[i]["_displayType"] = "direct-child"
-			}
-			allSubSessions = append(allSubSessions, directChildren...)
-		}
-
-		// Strategy 2: If current session has a parent, get siblings
-		if currentSession.ParentID != "" {
-
-			parentEndpoint := fmt.Sprintf("/session/%s/sub-sessions", currentSession.ParentID)
-			var siblings []map[string]interface{}
-			err = app.Client.Get(ctx, parentEndpoint, nil, &siblings)
-
-			if err == nil && len(siblings) > 0 {
-
-				// Mark siblings and exclude current session
-				for i := range siblings {
-					if siblings[i]["id"] != currentSession.ID {
-						siblings[i]["_displayType"] = "sibling"
-						siblings[i]["_note"] = "Sibling sub-session"
-						allSubSessions = append(allSubSessions, siblings[i])
-					}
-				}
-			}
-		}
-
-		// Build tree structure and set items
-		if len(allSubSessions) > 0 {
-			dialog.subSessions = allSubSessions
-			items := dialog.buildTreeStructure(allSubSessions, currentSession.ID)
-			dialog.list.SetItems(items)
-
-		}
+		width:        width,
+		height:       height,
+		modal:        modal,
+		list:         list,
+		app:          app,
+		collapsed:    make(map[string]bool),
+		depth:        make(map[string]int),
+		displayTypes: make(map[string]string),
+		spinner: spinner.New(
+			spinner.WithSpinner(spinner.Dot),
+			spinner.WithStyle(styles.NewStyle().Foreground(t.Primary()).Lipgloss()),
+		),
 	}
 
 	return dialog
@@ -0,0 +1,349 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/styles/glyphs"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// SwarmDialog interface for the agent swarm dashboard
+type SwarmDialog interface {
+	layout.Modal
+}
+
+const (
+	swarmBarWidth = 12
+	// swarmTailLines is how much of an agent's transcript is shown inline
+	swarmTailLines = 5
+)
+
+// swarmTickMsg carries its dialog so ticks of a closed dialog die out
+type swarmTickMsg struct{ dialog *swarmDialog }
+
+// swarmTranscriptMsg carries the messages of an agent's sub-session
+type swarmTranscriptMsg struct {
+	dialog   *swarmDialog
+	taskID   string
+	messages []opencode.Message
+	err      error
+}
+
+type swarmItem struct {
+	task app.TaskInfo
+	// tail holds the last transcript lines if the item is expanded
+	tail     []string
+	expanded bool
+}
+
+// transcriptTail returns the last n lines of text and tool calls the agent
+// produced
+func transcriptTail(messages []opencode.Message, n int) []string {
+	lines := []string{}
+	for _, message := range messages {
+		if message.Role != opencode.MessageRoleAssistant {
+			continue
+		}
+		for _, part := range message.Parts {
+			switch part := part.AsUnion().(type) {
+			case opencode.TextPart:
+				for line := range strings.SplitSeq(part.Text, "\n") {
+					if line = strings.TrimSpace(line); line != "" {
+						lines = append(lines, line)
+					}
+				}
+			case opencode.ToolInvocationPart:
+				lines = append(lines, "→ "+part.ToolInvocation.ToolName)
+			}
+		}
+	}
+	return lines[max(0, len(lines)-n):]
+}
+
+func (s swarmItem) elapsed() time.Duration {
+	if s.task.Status == app.TaskStatusRunning && !s.task.StartTime.IsZero() {
+		return time.Since(s.task.StartTime)
+	}
+	return s.task.Duration
+}
+
+func (s swarmItem) Render(selected bool, width int) string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.BackgroundElement())
+	if selected {
+		baseStyle = styles.NewStyle().Background(t.BackgroundPanel())
+	}
+
+	g := glyphs.Current()
+	icon, iconColor := g.Running, t.Primary()
+	switch s.task.Status {
+	case app.TaskStatusPending:
+		icon, iconColor = g.Dot, t.TextMuted()
+	case app.TaskStatusCompleted:
+		icon, iconColor = g.Done, t.Success()
+	case app.TaskStatusFailed:
+		icon, iconColor = g.Failed, t.Error()
+	}
+
+	filled := s.task.Progress * swarmBarWidth / 100
+	bar := baseStyle.Foreground(iconColor).Render(strings.Repeat(g.BarFull, filled)) +
+		baseStyle.Foreground(t.BorderSubtle()).Render(strings.Repeat(g.BarEmpty, swarmBarWidth-filled))
+
+	name := s.task.Label()
+	if name == "" {
+		name = s.task.ID
+	}
+	activity := s.task.CurrentTool
+	switch {
+	case s.task.Status == app.TaskStatusFailed:
+		activity = s.task.Error
+	case activity == "":
+		activity = s.task.Message
+	}
+
+	right := fmt.Sprintf(" %3d%% %6s", s.task.Progress, s.elapsed().Round(time.Second))
+	left := baseStyle.Foreground(iconColor).Render(" "+icon+" ") + bar
+	nameWidth := max(10, (width-lipgloss.Width(left)-lipgloss.Width(right))/2)
+	middle := " " + truncate.StringWithTail(name, uint(nameWidth), "…")
+	activityWidth := max(0, width-lipgloss.Width(left)-lipgloss.Width(middle)-lipgloss.Width(right)-2)
+	if activity != "" && activityWidth > 0 {
+		middle += baseStyle.Foreground(t.TextMuted()).Render(" · " + truncate.StringWithTail(activity, uint(activityWidth), "…"))
+	}
+
+	nameStyle := baseStyle.Foreground(t.Text())
+	if selected {
+		nameStyle = nameStyle.Bold(true)
+	}
+	line := left + nameStyle.Render(middle)
+	gap := max(0, width-lipgloss.Width(line)-lipgloss.Width(right))
+	line += baseStyle.Render(strings.Repeat(" ", gap)) + baseStyle.Foreground(t.TextMuted()).Render(right)
+	if !s.expanded {
+		return line
+	}
+
+	tailStyle := styles.NewStyle().Background(t.BackgroundElement()).Foreground(t.TextMuted()).Width(width)
+	tail := s.tail
+	if tail == nil {
+		tail = []string{"loading…"}
+	} else if len(tail) == 0 {
+		tail = []string{"no output yet"}
+	}
+	lines := []string{line}
+	for _, text := range tail {
+		lines = append(lines, tailStyle.Render("     │ "+truncate.StringWithTail(text, uint(max(0, width-8)), "…")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s swarmItem) FilterValue() string {
+	return s.task.AgentName + " " + s.task.Description
+}
+
+type swarmDialog struct {
+	app   *app.App
+	modal *modal.Modal
+	list  list.List[swarmItem]
+	// transcripts caches the sub-session messages of expanded agents
+	transcripts map[string][]opencode.Message
+}
+
+func (s *swarmDialog) Init() tea.Cmd {
+	return s.tick()
+}
+
+func (s *swarmDialog) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return swarmTickMsg{dialog: s}
+	})
+}
+
+// refresh reloads the task snapshot, keeping the selected task selected
+func (s *swarmDialog) refresh() {
+	if s.app.TaskClient == nil {
+		return
+	}
+	selectedID := ""
+	if item, idx := s.list.GetSelectedItem(); idx >= 0 {
+		selectedID = item.task.ID
+	}
+
+	tasks := s.app.TaskClient.Tasks()
+	items := make([]swarmItem, 0, len(tasks))
+	selected := 0
+	for i, task := range tasks {
+		if task.ID == selectedID {
+			selected = i
+		}
+		item := swarmItem{task: task}
+		if messages, ok := s.transcripts[task.ID]; ok {
+			item.expanded = true
+			if messages != nil {
+				item.tail = transcriptTail(messages, swarmTailLines)
+			}
+		}
+		items = append(items, item)
+	}
+	s.list.SetItems(items)
+	s.list.SetSelectedIndex(selected)
+}
+
+func (s *swarmDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case swarmTickMsg:
+		if msg.dialog != s {
+			return s, nil
+		}
+		s.refresh()
+		return s, s.tick()
+	case swarmTranscriptMsg:
+		if msg.dialog != s {
+			return s, nil
+		}
+		if _, expanded := s.transcripts[msg.taskID]; !expanded {
+			return s, nil
+		}
+		if msg.err != nil {
+			slog.Error("Failed to load agent transcript", "taskID", msg.taskID, "error", msg.err)
+			msg.messages = []opencode.Message{}
+		}
+		s.transcripts[msg.taskID] = msg.messages
+		s.refresh()
+		return s, nil
+	case opencode.EventListResponseEventMessageUpdated:
+		sessionID := msg.Properties.Info.Metadata.SessionID
+		messages, expanded := s.transcripts[sessionID]
+		if !expanded || messages == nil {
+			return s, nil
+		}
+		s.transcripts[sessionID] = upsertMessage(messages, msg.Properties.Info)
+		s.refresh()
+		return s, nil
+	case tea.WindowSizeMsg:
+		s.list.SetMaxWidth(layout.Current.Container.Width - 12)
+	case tea.KeyPressMsg:
+		if msg.String() == "tab" {
+			return s, s.toggleTail()
+		}
+		if msg.String() == "enter" {
+			item, idx := s.list.GetSelectedItem()
+			if idx < 0 {
+				return s, nil
+			}
+			return s, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				s.app.SwitchToSession(context.Background(), item.task.ID),
+			)
+		}
+	}
+
+	listModel, cmd := s.list.Update(msg)
+	s.list = listModel.(list.List[swarmItem])
+	return s, cmd
+}
+
+// toggleTail expands or collapses the transcript tail of the selected agent,
+// loading its sub-session the first time it is expanded
+func (s *swarmDialog) toggleTail() tea.Cmd {
+	item, idx := s.list.GetSelectedItem()
+	if idx < 0 {
+		return nil
+	}
+	taskID := item.task.ID
+	if _, expanded := s.transcripts[taskID]; expanded {
+		delete(s.transcripts, taskID)
+		s.refresh()
+		return nil
+	}
+
+	// a nil transcript marks the agent as expanded while it loads
+	s.transcripts[taskID] = nil
+	s.refresh()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		messages, err := s.app.ListMessages(ctx, taskID)
+		return swarmTranscriptMsg{dialog: s, taskID: taskID, messages: messages, err: err}
+	}
+}
+
+// upsertMessage replaces the message with the same ID or appends it
+func upsertMessage(messages []opencode.Message, message opencode.Message) []opencode.Message {
+	for i, existing := range messages {
+		if existing.ID == message.ID {
+			messages[i] = message
+			return messages
+		}
+	}
+	return append(messages, message)
+}
+
+func (s *swarmDialog) summary() string {
+	var running, done, failed int
+	for _, item := range s.list.GetItems() {
+		switch item.task.Status {
+		case app.TaskStatusCompleted:
+			done++
+		case app.TaskStatusFailed:
+			failed++
+		default:
+			running++
+		}
+	}
+	return fmt.Sprintf("%d running · %d done · %d failed", running, done, failed)
+}
+
+func (s *swarmDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if err := s.app.Features.Require(app.FeatureTasks); err != nil {
+		return muted.Render("Agent progress is " + err.Error())
+	}
+	if s.app.TaskClient == nil || !s.app.TaskClient.IsConnected() {
+		return muted.Render("Task event server not connected, agent progress is unavailable")
+	}
+	header := muted.PaddingBottom(1).Render(s.summary())
+	help := muted.PaddingTop(1).Render("enter open session · tab toggle output · type to filter")
+	return header + "\n" + s.list.View() + "\n" + help
+}
+
+func (s *swarmDialog) Render(background string) string {
+	return s.modal.Render(s.View(), background)
+}
+
+func (s *swarmDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewSwarmDialog creates a dashboard of all sub-agents known to the task client
+func NewSwarmDialog(app *app.App) SwarmDialog {
+	agents := list.NewListComponent([]swarmItem{}, 12, "No agents running", true)
+	agents.SetFilterable(true)
+	agents.SetMaxWidth(layout.Current.Container.Width - 12)
+
+	s := &swarmDialog{
+		app:         app,
+		list:        agents,
+		transcripts: make(map[string][]opencode.Message),
+		modal: modal.New(
+			modal.WithTitle("Agents"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	s.refresh()
+	return s
+}
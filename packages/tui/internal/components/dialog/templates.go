@@ -0,0 +1,227 @@
+package dialog
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/textinput"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/list"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// TemplatesDialog interface for the prompt template library
+type TemplatesDialog interface {
+	layout.Modal
+}
+
+// TemplateFilledMsg is sent with the prompt of a template once all of its
+// variables have values
+type TemplateFilledMsg struct {
+	Prompt string
+}
+
+type templateItem struct {
+	template config.PromptTemplate
+}
+
+func (t templateItem) Render(selected bool, width int) string {
+	th := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(th.BackgroundElement())
+	if selected {
+		baseStyle = styles.NewStyle().Background(th.BackgroundPanel())
+	}
+
+	nameStyle := baseStyle.Foreground(th.Text())
+	if selected {
+		nameStyle = nameStyle.Foreground(th.Primary()).Bold(true)
+	}
+	name := nameStyle.Render(" " + t.template.Name)
+	preview := strings.Join(strings.Fields(t.template.Prompt), " ")
+	previewWidth := max(0, width-lipgloss.Width(name)-2)
+	preview = baseStyle.Foreground(th.TextMuted()).Render("  " + truncate.StringWithTail(preview, uint(previewWidth), "…"))
+	line := name + preview
+	gap := max(0, width-lipgloss.Width(line))
+	return line + baseStyle.Render(strings.Repeat(" ", gap))
+}
+
+func (t templateItem) FilterValue() string {
+	return t.template.Name + " " + t.template.Prompt
+}
+
+type templatesDialog struct {
+	app   *app.App
+	modal *modal.Modal
+	list  list.List[templateItem]
+	err   error
+
+	// variable stage, active once a template with placeholders is chosen
+	template  *config.PromptTemplate
+	variables []string
+	values    map[string]string
+	input     textinput.Model
+}
+
+func (d *templatesDialog) Init() tea.Cmd {
+	if d.template != nil {
+		return d.input.Focus()
+	}
+	return nil
+}
+
+// choose fills the template right away, or asks for its variables first
+func (d *templatesDialog) choose(template config.PromptTemplate) tea.Cmd {
+	variables := app.TemplateVariables(template.Prompt)
+	if len(variables) == 0 {
+		return tea.Sequence(
+			util.CmdHandler(modal.CloseModalMsg{}),
+			util.CmdHandler(TemplateFilledMsg{Prompt: template.Prompt}),
+		)
+	}
+	d.template = &template
+	d.variables = variables
+	d.values = make(map[string]string, len(variables))
+	d.modal = modal.New(
+		modal.WithTitle(template.Name),
+		modal.WithMaxWidth(layout.Current.Container.Width-8),
+	)
+	d.resetInput()
+	return d.input.Focus()
+}
+
+func (d *templatesDialog) resetInput() {
+	name := d.variables[len(d.values)]
+	d.input.Reset()
+	d.input.Placeholder = name
+}
+
+func (d *templatesDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.list.SetMaxWidth(layout.Current.Container.Width - 12)
+		d.input.SetWidth(layout.Current.Container.Width - 14)
+	case tea.KeyPressMsg:
+		if d.template != nil {
+			if msg.String() != "enter" {
+				var cmd tea.Cmd
+				d.input, cmd = d.input.Update(msg)
+				return d, cmd
+			}
+			d.values[d.variables[len(d.values)]] = d.input.Value()
+			if len(d.values) < len(d.variables) {
+				d.resetInput()
+				return d, nil
+			}
+			return d, tea.Sequence(
+				util.CmdHandler(modal.CloseModalMsg{}),
+				util.CmdHandler(TemplateFilledMsg{Prompt: app.FillTemplate(d.template.Prompt, d.values)}),
+			)
+		}
+		if msg.String() == "enter" {
+			item, idx := d.list.GetSelectedItem()
+			if idx < 0 {
+				return d, nil
+			}
+			return d, d.choose(item.template)
+		}
+	}
+
+	if d.template != nil {
+		return d, nil
+	}
+	listModel, cmd := d.list.Update(msg)
+	d.list = listModel.(list.List[templateItem])
+	return d, cmd
+}
+
+func (d *templatesDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+
+	if d.template != nil {
+		name := d.variables[len(d.values)]
+		label := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement()).
+			Render(fmt.Sprintf("{{%s}}", name))
+		progress := muted.Render(fmt.Sprintf("  %d/%d", len(d.values)+1, len(d.variables)))
+		preview := muted.PaddingTop(1).Width(layout.Current.Container.Width - 12).Render(
+			app.FillTemplate(d.template.Prompt, d.values),
+		)
+		return label + progress + "\n" + d.input.View() + "\n" + preview
+	}
+
+	if d.err != nil {
+		return muted.Render(d.err.Error())
+	}
+	help := muted.PaddingTop(1).Render("enter insert · type to filter · templates live in " + d.app.TemplatesDir())
+	return d.list.View() + "\n" + help
+}
+
+func (d *templatesDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *templatesDialog) Close() tea.Cmd {
+	d.input.Blur()
+	return nil
+}
+
+func newTemplatesDialog(app *app.App) *templatesDialog {
+	t := theme.CurrentTheme()
+	bgColor := t.BackgroundElement()
+
+	input := textinput.New()
+	input.Prompt = "> "
+	input.SetWidth(layout.Current.Container.Width - 14)
+	input.Styles.Focused.Prompt = styles.NewStyle().Foreground(t.Primary()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Text = styles.NewStyle().Foreground(t.Text()).Background(bgColor).Lipgloss()
+	input.Styles.Focused.Placeholder = styles.NewStyle().Foreground(t.TextMuted()).Background(bgColor).Lipgloss()
+	input.Styles.Blurred = input.Styles.Focused
+	input.Styles.Cursor.Color = t.Primary()
+
+	templates := list.NewListComponent([]templateItem{}, 10, "No templates", false)
+	templates.SetFilterable(true)
+	templates.SetMaxWidth(layout.Current.Container.Width - 12)
+
+	return &templatesDialog{
+		app:   app,
+		input: input,
+		list:  templates,
+		modal: modal.New(
+			modal.WithTitle("Templates"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+}
+
+// NewTemplatesDialog creates a dialog to browse the prompt template library
+func NewTemplatesDialog(app *app.App) TemplatesDialog {
+	d := newTemplatesDialog(app)
+	loaded, err := app.ListTemplates()
+	if err != nil {
+		slog.Error("Failed to load templates", "error", err)
+		d.err = err
+	}
+	items := make([]templateItem, 0, len(loaded))
+	for _, template := range loaded {
+		items = append(items, templateItem{template: template})
+	}
+	d.list.SetItems(items)
+	return d
+}
+
+// NewTemplateVariablesDialog creates a dialog that asks for the variables of
+// a template with placeholders
+func NewTemplateVariablesDialog(app *app.App, template config.PromptTemplate) TemplatesDialog {
+	d := newTemplatesDialog(app)
+	d.choose(template)
+	return d
+}
@@ -106,6 +106,7 @@ func NewThemeDialog() ThemeDialog {
 
 	// Set the max width for the list to match the modal width
 	list.SetMaxWidth(36) // 40 (modal max width) - 4 (modal padding)
+	list.SetFilterable(true)
 
 	return &themeDialog{
 		list:          list,
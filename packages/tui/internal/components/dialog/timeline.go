@@ -0,0 +1,200 @@
+package dialog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// timelineGap is the pause after which the timeline marks the time that
+// passed between two events
+const timelineGap = 10 * time.Minute
+
+// TimelineDialog interface for the session timeline
+type TimelineDialog interface {
+	layout.Modal
+}
+
+type timelineCheckpointsMsg struct {
+	checkpoints []app.Checkpoint
+}
+
+type timelineDialog struct {
+	width, height int
+	app           *app.App
+	modal         *modal.Modal
+	viewport      viewport.Model
+	checkpoints   []app.Checkpoint
+	hideTools     bool
+}
+
+func (d *timelineDialog) Init() tea.Cmd {
+	if d.app.Session == nil || d.app.Session.ID == "" || !d.app.Features.Enabled(app.FeatureCheckpoints) {
+		return nil
+	}
+	sessionID := d.app.Session.ID
+	return func() tea.Msg {
+		checkpoints, err := d.app.Checkpoints.ListCheckpoints(context.Background(), sessionID)
+		if err != nil {
+			// the timeline is still useful without them
+			slog.Warn("Failed to list checkpoints for the timeline", "error", err)
+		}
+		return timelineCheckpointsMsg{checkpoints: checkpoints}
+	}
+}
+
+func (d *timelineDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.setSize()
+		d.render()
+	case timelineCheckpointsMsg:
+		d.checkpoints = msg.checkpoints
+		d.render()
+		return d, nil
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "t":
+			d.hideTools = !d.hideTools
+			d.render()
+			return d, nil
+		case "home", "g":
+			d.viewport.GotoTop()
+			return d, nil
+		case "end", "G":
+			d.viewport.GotoBottom()
+			return d, nil
+		}
+	}
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+func (d *timelineDialog) setSize() {
+	d.width = layout.Current.Container.Width - 12
+	d.height = max(5, layout.Current.Viewport.Height-14)
+	d.viewport.SetWidth(d.width)
+	d.viewport.SetHeight(d.height)
+}
+
+// formatTimelineDuration is empty for events without a duration
+func formatTimelineDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return ""
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// render lays the session out as a vertical timeline, one event per line
+func (d *timelineDialog) render() {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	text := base.Foreground(t.Text())
+	kinds := map[app.TimelineKind]struct {
+		glyph string
+		name  string
+		style styles.Style
+	}{
+		app.TimelinePrompt:     {"●", "you", base.Foreground(t.Secondary())},
+		app.TimelineResponse:   {"●", "assistant", base.Foreground(t.Primary())},
+		app.TimelineTool:       {"├", "", muted},
+		app.TimelineAgent:      {"◈", "agent", base.Foreground(t.Accent())},
+		app.TimelineCheckpoint: {"◆", "checkpoint", base.Foreground(t.Success())},
+	}
+
+	entries := app.BuildTimeline(d.app.Messages, d.checkpoints)
+	var lines []string
+	var previous time.Time
+	for _, entry := range entries {
+		if d.hideTools && entry.Kind == app.TimelineTool {
+			continue
+		}
+		if !previous.IsZero() {
+			if gap := entry.Time.Sub(previous); gap >= timelineGap {
+				lines = append(lines, muted.Render(fmt.Sprintf("%8s  ┆  %s later", "", gap.Round(time.Minute))))
+			}
+			if entry.Time.YearDay() != previous.YearDay() || entry.Time.Year() != previous.Year() {
+				lines = append(lines, muted.Render(entry.Time.Local().Format("Mon Jan 2")))
+			}
+		}
+		previous = entry.Time
+
+		kind := kinds[entry.Kind]
+		line := muted.Render(entry.Time.Local().Format("15:04:05")) + "  " + kind.style.Render(kind.glyph) + " "
+		if kind.name != "" {
+			line += kind.style.Render(fmt.Sprintf("%-10s ", kind.name))
+		} else {
+			line += muted.Render(strings.Repeat(" ", 11))
+		}
+		duration := formatTimelineDuration(entry.Duration)
+		// time, glyph and kind take 23 columns
+		room := d.width - 23 - len(duration) - 2
+		label := truncate.StringWithTail(entry.Label, uint(max(room, 0)), "…")
+		if entry.Kind == app.TimelineTool {
+			line += muted.Render(label)
+		} else {
+			line += text.Render(label)
+		}
+		if duration != "" {
+			line += muted.Render("  " + duration)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, muted.Render("Nothing happened in this session yet"))
+	}
+	d.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func (d *timelineDialog) View() string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement())
+	toggle := "t hide tool calls"
+	if d.hideTools {
+		toggle = "t show tool calls"
+	}
+	help := muted.PaddingTop(1).Render(toggle + " · ↑/↓ scroll · g/G first/last")
+	return d.viewport.View() + "\n" + help
+}
+
+func (d *timelineDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *timelineDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewTimelineDialog creates a dialog showing the current session as a
+// chronological timeline, checkpoints are added once they are loaded
+func NewTimelineDialog(app *app.App) TimelineDialog {
+	d := &timelineDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Session Timeline"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+		viewport: viewport.New(),
+	}
+	d.setSize()
+	d.render()
+	return d
+}
@@ -0,0 +1,145 @@
+package dialog
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/config"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// ShowTrustDialogMsg opens the trust prompt for the workspace
+type ShowTrustDialogMsg struct{}
+
+// WorkspaceTrustSelectedMsg is sent when the user decided whether to trust
+// the workspace
+type WorkspaceTrustSelectedMsg struct {
+	Trust config.WorkspaceTrust
+}
+
+// TrustDialog interface for the workspace trust prompt
+type TrustDialog interface {
+	layout.Modal
+}
+
+var trustKeys = struct {
+	Switch     key.Binding
+	EnterSpace key.Binding
+	Trust      key.Binding
+	Restrict   key.Binding
+}{
+	Switch:     key.NewBinding(key.WithKeys("left", "right", "tab", "shift+tab")),
+	EnterSpace: key.NewBinding(key.WithKeys("enter", "space")),
+	Trust:      key.NewBinding(key.WithKeys("t")),
+	Restrict:   key.NewBinding(key.WithKeys("r")),
+}
+
+// trustOptions are the buttons of the dialog, in order
+var trustOptions = []struct {
+	label string
+	trust config.WorkspaceTrust
+}{
+	{"Trust (t)", config.WorkspaceTrusted},
+	{"Stay restricted (r)", config.WorkspaceRestricted},
+}
+
+type trustDialog struct {
+	app      *app.App
+	modal    *modal.Modal
+	selected int
+}
+
+func (d *trustDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *trustDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		switch {
+		case key.Matches(msg, trustKeys.Switch):
+			d.selected = (d.selected + 1) % len(trustOptions)
+		case key.Matches(msg, trustKeys.EnterSpace):
+			return d, d.decide(trustOptions[d.selected].trust)
+		case key.Matches(msg, trustKeys.Trust):
+			return d, d.decide(config.WorkspaceTrusted)
+		case key.Matches(msg, trustKeys.Restrict):
+			return d, d.decide(config.WorkspaceRestricted)
+		}
+	}
+	return d, nil
+}
+
+func (d *trustDialog) decide(trust config.WorkspaceTrust) tea.Cmd {
+	return tea.Sequence(
+		util.CmdHandler(modal.CloseModalMsg{}),
+		util.CmdHandler(WorkspaceTrustSelectedMsg{Trust: trust}),
+	)
+}
+
+func (d *trustDialog) View() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Foreground(t.Text()).Background(t.BackgroundElement())
+	muted := base.Foreground(t.TextMuted())
+	width := layout.Current.Container.Width - 16
+
+	root := d.app.Info.Path.Root
+	if root == "" {
+		root = d.app.Info.Path.Cwd
+	}
+	lines := []string{
+		base.Bold(true).Render(util.TruncateMiddle(root, width)),
+		"",
+		base.Width(width).Render("Do you trust the authors of the files in this folder?"),
+		"",
+		muted.Width(width).Render("Restricted mode ignores the project config, turns off the config hooks and asks for every bash command, even one allowed for the session."),
+	}
+	if trust := d.app.WorkspaceTrust(); trust != "" {
+		lines = append(lines, "", muted.Render("currently "+string(trust)))
+	}
+
+	buttons := make([]string, len(trustOptions))
+	for i, option := range trustOptions {
+		style := base.Foreground(t.Primary()).Padding(0, 1)
+		if i == d.selected {
+			style = style.Background(t.Primary()).Foreground(t.BackgroundElement())
+		}
+		buttons[i] = style.Render(option.label)
+	}
+	lines = append(lines, "", strings.Join(buttons, base.Render("  ")))
+	hint := "esc keeps the decision"
+	if !d.app.TrustDecided() {
+		hint = "esc stays restricted and asks again next time"
+	}
+	lines = append(lines, muted.PaddingTop(1).Render(hint))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (d *trustDialog) Render(background string) string {
+	return d.modal.Render(d.View(), background)
+}
+
+func (d *trustDialog) Close() tea.Cmd {
+	return nil
+}
+
+// NewTrustDialog creates the prompt asking whether to trust the workspace
+func NewTrustDialog(app *app.App) TrustDialog {
+	d := &trustDialog{
+		app: app,
+		modal: modal.New(
+			modal.WithTitle("Workspace Trust"),
+			modal.WithMaxWidth(layout.Current.Container.Width-8),
+		),
+	}
+	if app.WorkspaceTrust() == config.WorkspaceRestricted {
+		d.selected = 1
+	}
+	return d
+}
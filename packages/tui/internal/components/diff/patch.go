@@ -0,0 +1,216 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is the part of a multi-file patch that changes one file
+type FileDiff struct {
+	// OldFile is empty when the patch creates the file
+	OldFile string
+	// NewFile is empty when the patch deletes the file
+	NewFile string
+	Hunks   []Hunk
+}
+
+// Path returns the file the diff applies to
+func (f FileDiff) Path() string {
+	if f.NewFile != "" {
+		return f.NewFile
+	}
+	return f.OldFile
+}
+
+// patchPath strips the a/ and b/ prefixes git puts on paths, and turns
+// /dev/null into an empty path
+func patchPath(header string) string {
+	path := strings.TrimSpace(header)
+	if tab := strings.IndexByte(path, '\t'); tab >= 0 {
+		path = path[:tab]
+	}
+	if path == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		return path[2:]
+	}
+	return path
+}
+
+// parseRange reads "-12,3" or "+4" into its start and line count
+func parseRange(field string) (int, int, error) {
+	start, count, found := strings.Cut(field[1:], ",")
+	first, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return first, 1, nil
+	}
+	n, err := strconv.Atoi(count)
+	return first, n, err
+}
+
+// ParseMultiFileDiff parses a unified diff that may span several files.
+// Hunk lengths are taken from the hunk headers, so removed lines that look
+// like file headers are read correctly.
+func ParseMultiFileDiff(patch string) ([]FileDiff, error) {
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *Hunk
+	var oldLine, newLine, oldLeft, newLeft int
+
+	flushHunk := func() {
+		if hunk != nil && file != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(patch, "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			dl := DiffLine{}
+			switch {
+			case strings.HasPrefix(line, "\\"):
+				// "\ No newline at end of file"
+				continue
+			case strings.HasPrefix(line, "+"):
+				dl = DiffLine{Kind: LineAdded, NewLineNo: newLine, Content: line[1:]}
+				newLine++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				dl = DiffLine{Kind: LineRemoved, OldLineNo: oldLine, Content: line[1:]}
+				oldLine++
+				oldLeft--
+			default:
+				dl = DiffLine{Kind: LineContext, OldLineNo: oldLine, NewLineNo: newLine, Content: strings.TrimPrefix(line, " ")}
+				oldLine++
+				newLine++
+				oldLeft--
+				newLeft--
+			}
+			hunk.Lines = append(hunk.Lines, dl)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			flushHunk()
+			files = append(files, FileDiff{
+				OldFile: patchPath(line[4:]),
+				NewFile: patchPath(lines[i+1][4:]),
+			})
+			file = &files[len(files)-1]
+			i++
+		case strings.HasPrefix(line, "@@"):
+			if file == nil {
+				return nil, fmt.Errorf("hunk %q comes before any file header", line)
+			}
+			flushHunk()
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+				return nil, fmt.Errorf("invalid hunk header %q", line)
+			}
+			var err error
+			if oldLine, oldLeft, err = parseRange(fields[1]); err != nil {
+				return nil, fmt.Errorf("invalid hunk header %q", line)
+			}
+			if newLine, newLeft, err = parseRange(fields[2]); err != nil {
+				return nil, fmt.Errorf("invalid hunk header %q", line)
+			}
+			hunk = &Hunk{Header: line}
+		}
+		// anything else is a git extended header or commentary
+	}
+	flushHunk()
+
+	// file pointers above are into a slice that grows, so files are only
+	// complete once every hunk was flushed
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file changes found")
+	}
+	return files, nil
+}
+
+// ApplyHunks applies hunks to content in order. A hunk must match the
+// content exactly. It is looked for where its header says first, then at
+// the nearest place it matches, as the lines may have moved.
+func ApplyHunks(content string, hunks []Hunk) (string, error) {
+	lines := strings.Split(content, "\n")
+	trailingNewline := strings.HasSuffix(content, "\n")
+	if trailingNewline {
+		lines = lines[:len(lines)-1]
+	}
+	if content == "" {
+		lines = nil
+	}
+
+	// offset tracks how far earlier hunks moved the lines
+	offset := 0
+	for _, hunk := range hunks {
+		var old, replacement []string
+		start := 0
+		for _, line := range hunk.Lines {
+			if line.Kind != LineAdded {
+				if start == 0 && line.OldLineNo > 0 {
+					start = line.OldLineNo
+				}
+				old = append(old, line.Content)
+			}
+			if line.Kind != LineRemoved {
+				replacement = append(replacement, line.Content)
+			}
+		}
+		if start == 0 {
+			// only additions, the header says where
+			fields := strings.Fields(hunk.Header)
+			if len(fields) > 1 {
+				start, _, _ = parseRange(fields[1])
+				start++
+			}
+		}
+
+		at := findLines(lines, old, start-1+offset)
+		if at < 0 {
+			return "", fmt.Errorf("hunk %s does not match the current file", hunk.Header)
+		}
+		lines = append(lines[:at], append(replacement, lines[at+len(old):]...)...)
+		offset = at - (start - 1) + len(replacement) - len(old)
+	}
+
+	result := strings.Join(lines, "\n")
+	if trailingNewline || (content == "" && len(lines) > 0) {
+		result += "\n"
+	}
+	return result, nil
+}
+
+// findLines returns where want occurs in lines, the closest match to near
+// first, or -1
+func findLines(lines, want []string, near int) int {
+	matches := func(at int) bool {
+		if at < 0 || at+len(want) > len(lines) {
+			return false
+		}
+		for i, line := range want {
+			if lines[at+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+	near = max(0, min(near, len(lines)))
+	for distance := 0; distance <= len(lines); distance++ {
+		if matches(near - distance) {
+			return near - distance
+		}
+		if distance > 0 && matches(near+distance) {
+			return near + distance
+		}
+	}
+	return -1
+}
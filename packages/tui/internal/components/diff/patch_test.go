@@ -0,0 +1,143 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const multiFilePatch = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var a = 1
++var a = 2
+ var b = 3
+@@ -5,2 +5,3 @@
+ func f() {
++	g()
+ }
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,2 @@
++package main
++var c = 4
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package main
+--- not a header
+`
+
+func TestParseMultiFileDiff(t *testing.T) {
+	files, err := ParseMultiFileDiff(multiFilePatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		oldFile, newFile, path string
+		hunks                  int
+	}{
+		{"main.go", "main.go", "main.go", 2},
+		{"", "new.go", "new.go", 1},
+		{"old.go", "", "old.go", 1},
+	}
+	if len(files) != len(tests) {
+		t.Fatalf("got %d files, want %d", len(files), len(tests))
+	}
+	for i, tt := range tests {
+		file := files[i]
+		if file.OldFile != tt.oldFile || file.NewFile != tt.newFile || file.Path() != tt.path {
+			t.Errorf("file %d = %q -> %q (%q), want %q -> %q (%q)",
+				i, file.OldFile, file.NewFile, file.Path(), tt.oldFile, tt.newFile, tt.path)
+		}
+		if len(file.Hunks) != tt.hunks {
+			t.Errorf("file %d has %d hunks, want %d", i, len(file.Hunks), tt.hunks)
+		}
+	}
+
+	// the removed line that looks like a file header belongs to the hunk
+	removed := files[2].Hunks[0].Lines
+	if len(removed) != 2 || removed[1].Content != "-- not a header" {
+		t.Errorf("deleted file lines = %+v", removed)
+	}
+}
+
+func TestParseMultiFileDiffErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":          "",
+		"hunk first":     "@@ -1 +1 @@\n-a\n+b\n",
+		"invalid header": "--- a/x\n+++ b/x\n@@ -a +1 @@\n",
+	}
+	for name, patch := range tests {
+		if _, err := ParseMultiFileDiff(patch); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestApplyHunks(t *testing.T) {
+	files, err := ParseMultiFileDiff(multiFilePatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	main := files[0].Hunks
+	original := "package main\nvar a = 1\nvar b = 3\n\nfunc f() {\n}\n"
+
+	tests := []struct {
+		name    string
+		content string
+		hunks   []Hunk
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "all hunks",
+			content: original,
+			hunks:   main,
+			want:    "package main\nvar a = 2\nvar b = 3\n\nfunc f() {\n\tg()\n}\n",
+		},
+		{
+			name:    "second hunk only",
+			content: original,
+			hunks:   main[1:],
+			want:    "package main\nvar a = 1\nvar b = 3\n\nfunc f() {\n\tg()\n}\n",
+		},
+		{
+			name:    "lines moved down",
+			content: "// header\n\n" + original,
+			hunks:   main,
+			want:    "// header\n\npackage main\nvar a = 2\nvar b = 3\n\nfunc f() {\n\tg()\n}\n",
+		},
+		{
+			name:    "new file",
+			content: "",
+			hunks:   files[1].Hunks,
+			want:    "package main\nvar c = 4\n",
+		},
+		{
+			name:    "file changed since the patch",
+			content: strings.Replace(original, "var a = 1", "var a = 5", 1),
+			hunks:   main,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyHunks(tt.content, tt.hunks)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package flash
+
+import (
+	"image/color"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+const (
+	// frameInterval is the time between two steps of the decay animation
+	frameInterval = 80 * time.Millisecond
+	// frameCount is the number of steps until the border has faded out
+	frameCount = 12
+	// staticDuration is how long the border is shown when motion is reduced
+	staticDuration = 1200 * time.Millisecond
+)
+
+// Severity selects the theme color of a flash
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+// FlashMsg asks for the viewport border to flash
+type FlashMsg struct {
+	Severity Severity
+}
+
+// New returns a command that flashes the viewport border
+func New(severity Severity) tea.Cmd {
+	return func() tea.Msg {
+		return FlashMsg{Severity: severity}
+	}
+}
+
+type frameMsg struct {
+	generation int
+}
+
+// FlashComponent draws a fading border around the main viewport
+type FlashComponent interface {
+	Update(msg tea.Msg) (FlashComponent, tea.Cmd)
+	Render(view string) string
+}
+
+type flashComponent struct {
+	reducedMotion bool
+	severity      Severity
+	frame         int // frames left, 0 when idle
+	generation    int
+}
+
+func (f *flashComponent) Update(msg tea.Msg) (FlashComponent, tea.Cmd) {
+	switch msg := msg.(type) {
+	case FlashMsg:
+		// a running flash is only replaced by one of equal or higher severity
+		if f.frame > 0 && msg.Severity > f.severity {
+			return f, nil
+		}
+		f.severity = msg.Severity
+		f.generation++
+		if f.reducedMotion {
+			f.frame = frameCount
+			generation := f.generation
+			return f, tea.Tick(staticDuration, func(time.Time) tea.Msg {
+				return frameMsg{generation: generation}
+			})
+		}
+		f.frame = frameCount
+		return f, f.tick()
+	case frameMsg:
+		if msg.generation != f.generation || f.frame == 0 {
+			return f, nil
+		}
+		if f.reducedMotion {
+			f.frame = 0
+			return f, nil
+		}
+		f.frame--
+		if f.frame == 0 {
+			return f, nil
+		}
+		return f, f.tick()
+	}
+	return f, nil
+}
+
+func (f *flashComponent) tick() tea.Cmd {
+	generation := f.generation
+	return tea.Tick(frameInterval, func(time.Time) tea.Msg {
+		return frameMsg{generation: generation}
+	})
+}
+
+func (f *flashComponent) color() compat.AdaptiveColor {
+	t := theme.CurrentTheme()
+	base := t.Error()
+	switch f.severity {
+	case SeverityWarning:
+		base = t.Warning()
+	case SeverityInfo:
+		base = t.Info()
+	}
+	if f.reducedMotion {
+		return base
+	}
+	// ease out: fade quickly at first, then linger
+	progress := 1 - float64(f.frame)/frameCount
+	amount := progress * progress
+	background := t.Background()
+	return compat.AdaptiveColor{
+		Light: blend(base.Light, background.Light, amount),
+		Dark:  blend(base.Dark, background.Dark, amount),
+	}
+}
+
+// blend mixes from towards to by amount, leaving colors it can't parse as is
+func blend(from, to color.Color, amount float64) color.Color {
+	a, ok := colorful.MakeColor(from)
+	if !ok {
+		return from
+	}
+	b, ok := colorful.MakeColor(to)
+	if !ok {
+		return from
+	}
+	return a.BlendRgb(b, amount).Clamped()
+}
+
+// Render draws the border over the edges of view while a flash is active
+func (f *flashComponent) Render(view string) string {
+	if f.frame == 0 {
+		return view
+	}
+	width, height := lipgloss.Width(view), lipgloss.Height(view)
+	if width < 2 || height < 2 {
+		return view
+	}
+
+	t := theme.CurrentTheme()
+	style := styles.NewStyle().Foreground(f.color()).Background(t.Background())
+	top := style.Render("┏" + strings.Repeat("━", width-2) + "┓")
+	bottom := style.Render("┗" + strings.Repeat("━", width-2) + "┛")
+	vertical := strings.TrimSuffix(strings.Repeat(style.Render("┃")+"\n", height-2), "\n")
+
+	view = layout.PlaceOverlay(0, 1, vertical, view)
+	view = layout.PlaceOverlay(width-1, 1, vertical, view)
+	view = layout.PlaceOverlay(0, 0, top, view)
+	return layout.PlaceOverlay(0, height-1, bottom, view)
+}
+
+// NewFlashComponent creates an idle flash. With reducedMotion the border is
+// shown at full color for a moment instead of fading out.
+func NewFlashComponent(reducedMotion bool) FlashComponent {
+	return &flashComponent{reducedMotion: reducedMotion}
+}
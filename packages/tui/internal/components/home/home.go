@@ -0,0 +1,419 @@
+package home
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// StartScreenMinimal shows only the logo and version on the start screen
+const StartScreenMinimal = "minimal"
+
+const maxRecentSessions = 5
+
+// TemplateSelectedMsg is sent when a pinned template is chosen from the start screen
+type TemplateSelectedMsg struct {
+	Name   string
+	Prompt string
+}
+
+type sessionsLoadedMsg struct {
+	sessions []opencode.Session
+	err      error
+}
+
+type HomeComponent interface {
+	tea.Model
+	tea.ViewModel
+	SetSize(width, height int) tea.Cmd
+	// Shortcut returns the command bound to a start screen key, or nil
+	Shortcut(key string) tea.Cmd
+}
+
+type homeComponent struct {
+	app             *app.App
+	width, height   int
+	sessions        []opencode.Session
+	loaded          bool
+	serverReachable bool
+	// resume is the last session once it was checked against the server
+	resume *app.SessionIntegrityMsg
+}
+
+func (h *homeComponent) Init() tea.Cmd {
+	return h.loadSessions()
+}
+
+func (h *homeComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case sessionsLoadedMsg:
+		h.loaded = true
+		h.serverReachable = msg.err == nil
+		h.sessions = msg.sessions
+	case app.SessionIntegrityMsg:
+		h.resume = nil
+		if msg.Session != nil {
+			h.resume = &msg
+		}
+	case app.SessionSelectedMsg:
+		// the last session is only offered until another one is opened
+		if h.resume != nil && msg.ID != h.resume.Session.ID {
+			h.resume = nil
+		}
+	case opencode.EventListResponseEventSessionDeleted:
+		if h.resume != nil && msg.Properties.Info.ID == h.resume.Session.ID {
+			h.resume = nil
+		}
+		return h, h.loadSessions()
+	case app.SessionClearedMsg:
+		return h, h.loadSessions()
+	case app.SessionsUpdatedMsg:
+		for _, session := range msg {
+			if session.ParentID != "" {
+				continue
+			}
+			idx := slices.IndexFunc(h.sessions, func(s opencode.Session) bool {
+				return s.ID == session.ID
+			})
+			if idx < 0 {
+				// a session that isn't listed may now be among the most recent
+				return h, h.loadSessions()
+			}
+			h.sessions[idx] = session
+		}
+		slices.SortFunc(h.sessions, func(a, b opencode.Session) int {
+			return int(b.Time.Updated - a.Time.Updated)
+		})
+	}
+	return h, nil
+}
+
+func (h *homeComponent) SetSize(width, height int) tea.Cmd {
+	h.width = width
+	h.height = height
+	return nil
+}
+
+func (h *homeComponent) loadSessions() tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := h.app.ListSessions(context.Background())
+		if err != nil {
+			slog.Error("Failed to load recent sessions", "error", err)
+			return sessionsLoadedMsg{err: err}
+		}
+		sessions = slices.DeleteFunc(sessions, func(s opencode.Session) bool {
+			return s.ParentID != ""
+		})
+		slices.SortFunc(sessions, func(a, b opencode.Session) int {
+			return int(b.Time.Updated - a.Time.Updated)
+		})
+		if len(sessions) > maxRecentSessions {
+			sessions = sessions[:maxRecentSessions]
+		}
+		return sessionsLoadedMsg{sessions: sessions}
+	}
+}
+
+func (h *homeComponent) minimal() bool {
+	return h.app.State.StartScreen == StartScreenMinimal
+}
+
+// shortcutModifier has to be held with a number key for a start screen
+// shortcut, so a prompt can still start with a digit
+const shortcutModifier = "alt+"
+
+type shortcutKind int
+
+const (
+	shortcutNone shortcutKind = iota
+	shortcutResume
+	shortcutSession
+	shortcutTemplate
+)
+
+// resolveShortcut maps alt+0 to the last session and alt+1 to alt+9 to the
+// recent sessions first, then the pinned templates. The index is into the
+// sessions or templates.
+func resolveShortcut(key string, resume bool, sessions, templates int) (shortcutKind, int) {
+	digit, ok := strings.CutPrefix(key, shortcutModifier)
+	if !ok || len(digit) != 1 || digit[0] < '0' || digit[0] > '9' {
+		return shortcutNone, 0
+	}
+	index := int(digit[0] - '0')
+	if index == 0 {
+		if !resume {
+			return shortcutNone, 0
+		}
+		return shortcutResume, 0
+	}
+	index--
+	if index < sessions {
+		return shortcutSession, index
+	}
+	index -= sessions
+	if index < templates {
+		return shortcutTemplate, index
+	}
+	return shortcutNone, 0
+}
+
+// Shortcut returns the command of a start screen shortcut, see resolveShortcut
+func (h *homeComponent) Shortcut(key string) tea.Cmd {
+	if h.minimal() {
+		return nil
+	}
+	kind, index := resolveShortcut(key, h.resume != nil, len(h.sessions), len(h.app.State.Templates))
+	switch kind {
+	case shortcutResume:
+		return util.CmdHandler(app.SessionSelectedMsg(h.resume.Session))
+	case shortcutSession:
+		session := h.sessions[index]
+		return util.CmdHandler(app.SessionSelectedMsg(&session))
+	case shortcutTemplate:
+		template := h.app.State.Templates[index]
+		return util.CmdHandler(TemplateSelectedMsg{Name: template.Name, Prompt: template.Prompt})
+	}
+	return nil
+}
+
+func (h *homeComponent) logo() string {
+	t := theme.CurrentTheme()
+	base := styles.NewStyle().Background(t.Background()).Render
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).Render
+
+	dgm := `
+█▀▀▄ █▀▀▀ █▀▄▀█
+█░░█ █░▀█ █░▀░█
+▀▀▀  ▀▀▀▀ ▀░░░▀`
+	o := `
+  █▀▀█
+▀ █░░█
+  ▀▀▀▀`
+
+	logo := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		muted(dgm),
+		base(o),
+	)
+
+	versionStyle := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.Background()).
+		Width(lipgloss.Width(logo)).
+		Align(lipgloss.Right)
+	version := versionStyle.Render(h.app.Version)
+
+	return strings.Join([]string{logo, version}, "\n")
+}
+
+// section renders a titled block of rows at a fixed width
+func (h *homeComponent) section(title string, rows []string, width int) string {
+	t := theme.CurrentTheme()
+	titleStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).Bold(true)
+	lines := []string{titleStyle.Width(width).Render(title)}
+	lines = append(lines, rows...)
+	return strings.Join(lines, "\n")
+}
+
+func (h *homeComponent) row(key, label, hint string, width int) string {
+	t := theme.CurrentTheme()
+	keyStyle := styles.NewStyle().Foreground(t.Primary()).Background(t.Background()).Bold(true)
+	labelStyle := styles.NewStyle().Foreground(t.Text()).Background(t.Background())
+	hintStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background())
+
+	keyText := keyStyle.Render(fmt.Sprintf("%-6s", key))
+	hintText := ""
+	if hint != "" {
+		hintText = hintStyle.Render(" " + hint)
+	}
+	labelWidth := max(0, width-lipgloss.Width(keyText)-lipgloss.Width(hintText))
+	label = truncate.StringWithTail(label, uint(labelWidth), "...")
+	return keyText + labelStyle.Width(labelWidth).Render(label) + hintText
+}
+
+func (h *homeComponent) recentSessions(width int) string {
+	t := theme.CurrentTheme()
+	var rows []string
+	for i, session := range h.sessions {
+		updated := timeAgo(time.UnixMilli(int64(session.Time.Updated)))
+		if _, generating := h.app.Activity.Generating(session.ID); generating {
+			updated = "generating…"
+		} else if unread := h.app.Activity.Unread(session.ID); unread > 0 {
+			updated = fmt.Sprintf("%d unread", unread)
+		}
+		rows = append(rows, h.row(shortcutModifier+strconv.Itoa(i+1), session.Title, updated, width))
+	}
+	if len(rows) == 0 {
+		empty := "No sessions yet"
+		if !h.loaded {
+			empty = "Loading..."
+		}
+		rows = append(rows, styles.NewStyle().
+			Foreground(t.TextMuted()).
+			Background(t.Background()).
+			Width(width).
+			Render(empty))
+	}
+	return h.section("Recent sessions", rows, width)
+}
+
+// lastSession offers the session open on the last exit, with what changed
+// on the server since
+func (h *homeComponent) lastSession(width int) string {
+	if h.resume == nil {
+		return ""
+	}
+	hint := "unchanged"
+	switch h.resume.Integrity {
+	case app.SessionAdvanced:
+		hint = "new messages"
+	case app.SessionTruncated:
+		hint = "compacted elsewhere"
+	}
+	return h.section("Continue", []string{h.row(shortcutModifier+"0", h.resume.Session.Title, hint, width)}, width)
+}
+
+func (h *homeComponent) templates(width int) string {
+	var rows []string
+	offset := len(h.sessions)
+	for i, template := range h.app.State.Templates {
+		if offset+i >= 9 {
+			break
+		}
+		rows = append(rows, h.row(shortcutModifier+strconv.Itoa(offset+i+1), template.Name, "", width))
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	return h.section("Templates", rows, width)
+}
+
+func (h *homeComponent) quickActions(width int) string {
+	actions := []commands.CommandName{
+		commands.SessionNewCommand,
+		commands.SessionListCommand,
+		commands.ProjectOpenCommand,
+		commands.ProjectInitCommand,
+		commands.ModelListCommand,
+		commands.AppHelpCommand,
+	}
+	var rows []string
+	for _, name := range actions {
+		command, ok := h.app.Commands[name]
+		if !ok {
+			continue
+		}
+		// commands without a key are run by their trigger
+		var keybind string
+		switch {
+		case len(command.Keybindings) > 0:
+			binding := command.Keybindings[0]
+			keybind = binding.Key
+			if binding.RequiresLeader {
+				keybind = h.app.Config.Keybinds.Leader + " " + binding.Key
+			}
+		case command.Trigger != "":
+			keybind = "/" + command.Trigger
+		default:
+			continue
+		}
+		rows = append(rows, h.row("", command.Description, keybind, width))
+	}
+	return h.section("Quick actions", rows, width)
+}
+
+func (h *homeComponent) connection(width int) string {
+	t := theme.CurrentTheme()
+	status := func(label string, ok bool) string {
+		color := t.Error()
+		state := "offline"
+		if ok {
+			color = t.Success()
+			state = "connected"
+		}
+		dot := styles.NewStyle().Foreground(color).Background(t.Background()).Render("●")
+		text := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).Render(" " + label + " " + state)
+		return dot + text
+	}
+	server := status("server", !h.loaded || h.serverReachable)
+	tasks := status("task events", h.app.TaskClient != nil && h.app.TaskClient.IsConnected())
+	spacer := styles.NewStyle().Background(t.Background()).Render("   ")
+	return styles.NewStyle().Background(t.Background()).Width(width).Render(server + spacer + tasks)
+}
+
+func (h *homeComponent) View() string {
+	t := theme.CurrentTheme()
+	baseStyle := styles.NewStyle().Background(t.Background())
+
+	logo := lipgloss.PlaceHorizontal(
+		h.width,
+		lipgloss.Center,
+		h.logo(),
+		styles.WhitespaceStyle(t.Background()),
+	)
+
+	lines := []string{logo}
+	if !h.minimal() {
+		width := min(h.width-4, 60)
+		var blocks []string
+		if last := h.lastSession(width); last != "" {
+			blocks = append(blocks, last)
+		}
+		blocks = append(blocks, h.recentSessions(width))
+		if templates := h.templates(width); templates != "" {
+			blocks = append(blocks, templates)
+		}
+		blocks = append(blocks, h.quickActions(width), h.connection(width))
+		for _, block := range blocks {
+			lines = append(lines, "", lipgloss.PlaceHorizontal(
+				h.width,
+				lipgloss.Center,
+				block,
+				styles.WhitespaceStyle(t.Background()),
+			))
+		}
+	}
+
+	return lipgloss.Place(
+		h.width,
+		h.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		baseStyle.Render(strings.Join(lines, "\n")),
+		styles.WhitespaceStyle(t.Background()),
+	)
+}
+
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+func NewHomeComponent(app *app.App) HomeComponent {
+	return &homeComponent{
+		app: app,
+	}
+}
@@ -0,0 +1,35 @@
+package home
+
+import "testing"
+
+func TestResolveShortcut(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		resume    bool
+		sessions  int
+		templates int
+		kind      shortcutKind
+		index     int
+	}{
+		{"plain digit types", "1", true, 3, 2, shortcutNone, 0},
+		{"other modifier", "ctrl+1", true, 3, 2, shortcutNone, 0},
+		{"not a digit", "alt+a", true, 3, 2, shortcutNone, 0},
+		{"resume", "alt+0", true, 3, 2, shortcutResume, 0},
+		{"nothing to resume", "alt+0", false, 3, 2, shortcutNone, 0},
+		{"first session", "alt+1", false, 3, 2, shortcutSession, 0},
+		{"last session", "alt+3", false, 3, 2, shortcutSession, 2},
+		{"first template after sessions", "alt+4", false, 3, 2, shortcutTemplate, 0},
+		{"last template", "alt+5", false, 3, 2, shortcutTemplate, 1},
+		{"past the templates", "alt+6", false, 3, 2, shortcutNone, 0},
+		{"templates without sessions", "alt+1", false, 0, 2, shortcutTemplate, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, index := resolveShortcut(tt.key, tt.resume, tt.sessions, tt.templates)
+			if kind != tt.kind || index != tt.index {
+				t.Errorf("resolveShortcut(%q) = %d, %d, want %d, %d", tt.key, kind, index, tt.kind, tt.index)
+			}
+		})
+	}
+}
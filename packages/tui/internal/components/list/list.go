@@ -1,6 +1,7 @@
 package list
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/v2/key"
@@ -14,6 +15,12 @@ type ListItem interface {
 	Render(selected bool, width int) string
 }
 
+// FilterableItem is implemented by list items that can be matched by
+// type-to-filter. Items that don't implement it never match a filter.
+type FilterableItem interface {
+	FilterValue() string
+}
+
 type List[T ListItem] interface {
 	tea.Model
 	tea.ViewModel
@@ -24,33 +31,48 @@ type List[T ListItem] interface {
 	SetSelectedIndex(idx int)
 	SetEmptyMessage(msg string)
 	IsEmpty() bool
+	// SetFilterable enables type-to-filter. Printable keys are then used
+	// for the filter query instead of j/k navigation.
+	SetFilterable(filterable bool)
+	FilterQuery() string
 }
 
 type listComponent[T ListItem] struct {
 	fallbackMsg         string
 	items               []T
-	selectedIdx         int
+	visible             []int // indices into items that match the filter
+	selectedIdx         int   // position in visible
 	maxWidth            int
 	maxVisibleItems     int
 	useAlphaNumericKeys bool
+	filterable          bool
+	query               string
 	width               int
 	height              int
 }
 
 type listKeyMap struct {
-	Up        key.Binding
-	Down      key.Binding
-	UpAlpha   key.Binding
-	DownAlpha key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	UpAlpha     key.Binding
+	DownAlpha   key.Binding
+	Home        key.Binding
+	End         key.Binding
+	HomeAlpha   key.Binding
+	EndAlpha    key.Binding
+	PageUp      key.Binding
+	PageDown    key.Binding
+	FilterDel   key.Binding
+	FilterClear key.Binding
 }
 
 var simpleListKeys = listKeyMap{
 	Up: key.NewBinding(
-		key.WithKeys("up"),
+		key.WithKeys("up", "ctrl+p"),
 		key.WithHelp("↑", "previous list item"),
 	),
 	Down: key.NewBinding(
-		key.WithKeys("down"),
+		key.WithKeys("down", "ctrl+n"),
 		key.WithHelp("↓", "next list item"),
 	),
 	UpAlpha: key.NewBinding(
@@ -61,6 +83,38 @@ var simpleListKeys = listKeyMap{
 		key.WithKeys("j"),
 		key.WithHelp("j", "next list item"),
 	),
+	Home: key.NewBinding(
+		key.WithKeys("home"),
+		key.WithHelp("home", "first list item"),
+	),
+	End: key.NewBinding(
+		key.WithKeys("end"),
+		key.WithHelp("end", "last list item"),
+	),
+	HomeAlpha: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "first list item"),
+	),
+	EndAlpha: key.NewBinding(
+		key.WithKeys("G"),
+		key.WithHelp("G", "last list item"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "previous page"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdown", "next page"),
+	),
+	FilterDel: key.NewBinding(
+		key.WithKeys("backspace"),
+		key.WithHelp("backspace", "delete filter character"),
+	),
+	FilterClear: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "clear filter"),
+	),
 }
 
 func (c *listComponent[T]) Init() tea.Cmd {
@@ -69,27 +123,98 @@ func (c *listComponent[T]) Init() tea.Cmd {
 
 func (c *listComponent[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
+	case tea.KeyPressMsg:
+		alpha := c.useAlphaNumericKeys && !c.filterable
 		switch {
-		case key.Matches(msg, simpleListKeys.Up) || (c.useAlphaNumericKeys && key.Matches(msg, simpleListKeys.UpAlpha)):
-			if c.selectedIdx > 0 {
-				c.selectedIdx--
-			}
+		case key.Matches(msg, simpleListKeys.Up) || (alpha && key.Matches(msg, simpleListKeys.UpAlpha)):
+			c.move(-1, true)
 			return c, nil
-		case key.Matches(msg, simpleListKeys.Down) || (c.useAlphaNumericKeys && key.Matches(msg, simpleListKeys.DownAlpha)):
-			if c.selectedIdx < len(c.items)-1 {
-				c.selectedIdx++
-			}
+		case key.Matches(msg, simpleListKeys.Down) || (alpha && key.Matches(msg, simpleListKeys.DownAlpha)):
+			c.move(1, true)
+			return c, nil
+		case key.Matches(msg, simpleListKeys.Home) || (alpha && key.Matches(msg, simpleListKeys.HomeAlpha)):
+			c.selectedIdx = 0
+			return c, nil
+		case key.Matches(msg, simpleListKeys.End) || (alpha && key.Matches(msg, simpleListKeys.EndAlpha)):
+			c.selectedIdx = max(0, len(c.visible)-1)
 			return c, nil
+		case key.Matches(msg, simpleListKeys.PageUp):
+			c.move(-c.pageSize(), false)
+			return c, nil
+		case key.Matches(msg, simpleListKeys.PageDown):
+			c.move(c.pageSize(), false)
+			return c, nil
+		}
+
+		if !c.filterable {
+			return c, nil
+		}
+		switch {
+		case key.Matches(msg, simpleListKeys.FilterDel):
+			if c.query != "" {
+				runes := []rune(c.query)
+				c.setQuery(string(runes[:len(runes)-1]))
+			}
+		case key.Matches(msg, simpleListKeys.FilterClear):
+			c.setQuery("")
+		case msg.Text != "":
+			c.setQuery(c.query + msg.Text)
 		}
 	}
 
 	return c, nil
 }
 
+// move shifts the selection by delta, wrapping around the ends when wrap is set
+// and clamping otherwise
+func (c *listComponent[T]) move(delta int, wrap bool) {
+	n := len(c.visible)
+	if n == 0 {
+		return
+	}
+	next := c.selectedIdx + delta
+	if wrap {
+		c.selectedIdx = (next%n + n) % n
+		return
+	}
+	c.selectedIdx = max(0, min(next, n-1))
+}
+
+func (c *listComponent[T]) pageSize() int {
+	return max(1, c.maxVisibleItems)
+}
+
+func (c *listComponent[T]) setQuery(query string) {
+	_, current := c.GetSelectedItem()
+	c.query = query
+	c.applyFilter()
+	c.selectedIdx = 0
+	c.SetSelectedIndex(current)
+}
+
+// applyFilter rebuilds the visible indices from the current query
+func (c *listComponent[T]) applyFilter() {
+	c.visible = make([]int, 0, len(c.items))
+	query := strings.ToLower(c.query)
+	for i, item := range c.items {
+		if query == "" {
+			c.visible = append(c.visible, i)
+			continue
+		}
+		if f, ok := any(item).(FilterableItem); ok &&
+			strings.Contains(strings.ToLower(f.FilterValue()), query) {
+			c.visible = append(c.visible, i)
+		}
+	}
+	c.selectedIdx = max(0, min(c.selectedIdx, len(c.visible)-1))
+}
+
+// GetSelectedItem returns the selected item and its index in the full item
+// list, regardless of any active filter
 func (c *listComponent[T]) GetSelectedItem() (T, int) {
-	if len(c.items) > 0 {
-		return c.items[c.selectedIdx], c.selectedIdx
+	if len(c.visible) > 0 {
+		idx := c.visible[c.selectedIdx]
+		return c.items[idx], idx
 	}
 
 	var zero T
@@ -99,6 +224,7 @@ func (c *listComponent[T]) GetSelectedItem() (T, int) {
 func (c *listComponent[T]) SetItems(items []T) {
 	c.selectedIdx = 0
 	c.items = items
+	c.applyFilter()
 }
 
 func (c *listComponent[T]) GetItems() []T {
@@ -110,48 +236,86 @@ func (c *listComponent[T]) SetEmptyMessage(msg string) {
 }
 
 func (c *listComponent[T]) IsEmpty() bool {
-	return len(c.items) == 0
+	return len(c.visible) == 0
 }
 
 func (c *listComponent[T]) SetMaxWidth(width int) {
 	c.maxWidth = width
 }
 
+func (c *listComponent[T]) SetFilterable(filterable bool) {
+	c.filterable = filterable
+	if !filterable {
+		c.setQuery("")
+	}
+}
+
+func (c *listComponent[T]) FilterQuery() string {
+	return c.query
+}
+
+// SetSelectedIndex selects the item at idx in the full item list, if it is
+// currently visible
 func (c *listComponent[T]) SetSelectedIndex(idx int) {
-	if idx >= 0 && idx < len(c.items) {
-		c.selectedIdx = idx
+	for pos, i := range c.visible {
+		if i == idx {
+			c.selectedIdx = pos
+			return
+		}
 	}
 }
 
+func (c *listComponent[T]) emptyView() string {
+	t := theme.CurrentTheme()
+	msg := c.fallbackMsg
+	if c.query != "" && len(c.items) > 0 {
+		msg = fmt.Sprintf("No matches for %q", c.query)
+	}
+	return styles.NewStyle().Foreground(t.TextMuted()).Render(msg)
+}
+
+func (c *listComponent[T]) filterView(width int) string {
+	t := theme.CurrentTheme()
+	prompt := styles.NewStyle().Foreground(t.Primary()).PaddingLeft(1).Render("/")
+	query := styles.NewStyle().Foreground(t.Text()).Render(" " + c.query)
+	return truncate.StringWithTail(prompt+query, uint(width), "...")
+}
+
 func (c *listComponent[T]) View() string {
-	items := c.items
 	maxWidth := c.maxWidth
 	if maxWidth == 0 {
 		maxWidth = 80 // Default width if not set
 	}
-	maxVisibleItems := min(c.maxVisibleItems, len(items))
-	startIdx := 0
 
-	if len(items) <= 0 {
-		return c.fallbackMsg
+	var header []string
+	if c.filterable && c.query != "" {
+		header = append(header, c.filterView(maxWidth))
+	}
+
+	if len(c.visible) <= 0 {
+		return strings.Join(append(header, c.emptyView()), "\n")
 	}
 
-	if len(items) > maxVisibleItems {
+	maxVisibleItems := min(c.maxVisibleItems, len(c.visible))
+	startIdx := 0
+
+	if len(c.visible) > maxVisibleItems {
 		halfVisible := maxVisibleItems / 2
-		if c.selectedIdx >= halfVisible && c.selectedIdx < len(items)-halfVisible {
+		if c.selectedIdx >= halfVisible && c.selectedIdx < len(c.visible)-halfVisible {
 			startIdx = c.selectedIdx - halfVisible
-		} else if c.selectedIdx >= len(items)-halfVisible {
-			startIdx = len(items) - maxVisibleItems
+		} else if c.selectedIdx >= len(c.visible)-halfVisible {
+			startIdx = len(c.visible) - maxVisibleItems
 		}
 	}
 
-	endIdx := min(startIdx+maxVisibleItems, len(items))
+	endIdx := min(startIdx+maxVisibleItems, len(c.visible))
 
-	listItems := make([]string, 0, maxVisibleItems)
+	listItems := make([]string, 0, maxVisibleItems+len(header))
+	listItems = append(listItems, header...)
 
-	for i := startIdx; i < endIdx; i++ {
-		item := items[i]
-		title := item.Render(i == c.selectedIdx, maxWidth)
+	for pos := startIdx; pos < endIdx; pos++ {
+		item := c.items[c.visible[pos]]
+		title := item.Render(pos == c.selectedIdx, maxWidth)
 		listItems = append(listItems, title)
 	}
 
@@ -159,13 +323,15 @@ func (c *listComponent[T]) View() string {
 }
 
 func NewListComponent[T ListItem](items []T, maxVisibleItems int, fallbackMsg string, useAlphaNumericKeys bool) List[T] {
-	return &listComponent[T]{
+	c := &listComponent[T]{
 		fallbackMsg:         fallbackMsg,
 		items:               items,
 		maxVisibleItems:     maxVisibleItems,
 		useAlphaNumericKeys: useAlphaNumericKeys,
 		selectedIdx:         0,
 	}
+	c.applyFilter()
+	return c
 }
 
 // StringItem is a simple implementation of ListItem for string values
@@ -193,6 +359,10 @@ func (s StringItem) Render(selected bool, width int) string {
 	return itemStyle.Render(truncatedStr)
 }
 
+func (s StringItem) FilterValue() string {
+	return string(s)
+}
+
 // NewStringList creates a new list component with string items
 func NewStringList(items []string, maxVisibleItems int, fallbackMsg string, useAlphaNumericKeys bool) List[StringItem] {
 	stringItems := make([]StringItem, len(items))
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/sst/dgmo/internal/layout"
 	"github.com/sst/dgmo/internal/styles"
 	"github.com/sst/dgmo/internal/theme"
@@ -12,6 +13,16 @@ import (
 // CloseModalMsg is a message to signal that the active modal should be closed.
 type CloseModalMsg struct{}
 
+// CloseAllModalsMsg closes the active modal and every modal below it, for
+// nested flows that are done once their last step is
+type CloseAllModalsMsg struct{}
+
+// PushModalMsg opens a modal on top of the active one, which gets the focus
+// back when the new modal is closed
+type PushModalMsg struct {
+	Modal layout.Modal
+}
+
 // Modal is a reusable modal component that handles frame rendering and overlay placement
 type Modal struct {
 	width      int
@@ -72,6 +83,17 @@ func (m *Modal) SetTitle(title string) {
 	m.title = title
 }
 
+// Dim fades a rendered screen so it reads as inactive behind a modal
+func Dim(background string) string {
+	t := theme.CurrentTheme()
+	style := styles.NewStyle().Foreground(t.BorderSubtle()).Background(t.Background())
+	lines := strings.Split(background, "\n")
+	for i, line := range lines {
+		lines[i] = style.Render(ansi.Strip(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // Render renders the modal centered on the screen
 func (m *Modal) Render(contentView string, background string) string {
 	t := theme.CurrentTheme()
@@ -0,0 +1,104 @@
+package status
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// hintInterval is how long a set of hints shows before the next one when
+// not all of them fit
+const hintInterval = 6 * time.Second
+
+type hintTickMsg struct{}
+
+type hint struct {
+	command commands.CommandName
+	label   string
+}
+
+func hintTick() tea.Cmd {
+	return tea.Tick(hintInterval, func(time.Time) tea.Msg {
+		return hintTickMsg{}
+	})
+}
+
+// hints returns the keys most worth knowing right now, most relevant first
+func (m statusComponent) hints() []hint {
+	var hints []hint
+	if m.app.ToolTimers.Stats(m.app.ToolStuckAfter()).Stuck > 0 {
+		hints = append(hints, hint{commands.SessionInterruptCommand, "interrupt stuck tool"})
+	} else if m.app.IsBusy() {
+		hints = append(hints, hint{commands.SessionInterruptCommand, "interrupt"})
+	}
+	if m.app.IsBusy() {
+		hints = append(hints, hint{commands.ToolDetailsCommand, "tool details"})
+	}
+	if m.app.RunningTasks() > 0 {
+		hints = append(hints,
+			hint{commands.SwarmDashboardCommand, "agents"},
+			hint{commands.SubSessionCommand, "sub-sessions"},
+		)
+	}
+	if m.app.Features.Enabled(app.FeatureMCP) && len(m.app.MCPStats.Degraded()) > 0 {
+		hints = append(hints, hint{commands.MCPServersCommand, "MCP panel"})
+	}
+	if !m.following {
+		hints = append(hints,
+			hint{commands.MessagesLastCommand, "bottom"},
+			hint{commands.SearchCommand, "search"},
+		)
+	}
+	if len(hints) == 0 {
+		hints = append(hints,
+			hint{commands.CommandPaletteCommand, "commands"},
+			hint{commands.SessionListCommand, "sessions"},
+			hint{commands.ModelListCommand, "models"},
+			hint{commands.AppHelpCommand, "help"},
+		)
+	}
+	return hints
+}
+
+// hintStrip renders as many hints as fit. When some are left over, the
+// strip rotates through them on every tick.
+func (m statusComponent) hintStrip() string {
+	t := theme.CurrentTheme()
+	keyStyle := styles.NewStyle().Foreground(t.Text()).Background(t.Background())
+	labelStyle := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background())
+	separator := labelStyle.Render(" • ")
+
+	var rendered []string
+	for _, hint := range m.hints() {
+		if key := m.app.CommandKey(hint.command); key != "" {
+			rendered = append(rendered, keyStyle.Render(key)+labelStyle.Render(" "+hint.label))
+		}
+	}
+	if len(rendered) == 0 {
+		return ""
+	}
+
+	width := m.width - 2
+	start := m.rotation % len(rendered)
+	var shown []string
+	used := 0
+	for i := range rendered {
+		item := rendered[(start+i)%len(rendered)]
+		extra := lipgloss.Width(item)
+		if len(shown) > 0 {
+			extra += lipgloss.Width(separator)
+		}
+		if used+extra > width {
+			break
+		}
+		shown = append(shown, item)
+		used += extra
+	}
+	return strings.Join(shown, separator)
+}
@@ -2,27 +2,37 @@ package status
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/sst/dgmo/internal/app"
 	"github.com/sst/dgmo/internal/styles"
 	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
 )
 
 type StatusComponent interface {
 	tea.Model
 	tea.ViewModel
+	// ModelAt reports whether column x of the status line shows the model
+	ModelAt(x int) bool
+	// SetFollowing tells the hints whether the messages stick to the newest
+	// one or were scrolled up
+	SetFollowing(following bool) StatusComponent
 }
 
 type statusComponent struct {
-	app   *app.App
-	width int
+	app       *app.App
+	width     int
+	following bool
+	rotation  int
 }
 
 func (m statusComponent) Init() tea.Cmd {
-	return nil
+	return hintTick()
 }
 
 func (m statusComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -30,10 +40,18 @@ func (m statusComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		return m, nil
+	case hintTickMsg:
+		m.rotation++
+		return m, hintTick()
 	}
 	return m, nil
 }
 
+func (m statusComponent) SetFollowing(following bool) StatusComponent {
+	m.following = following
+	return m
+}
+
 func (m statusComponent) logo() string {
 	t := theme.CurrentTheme()
 	base := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundElement()).Render
@@ -49,25 +67,83 @@ func (m statusComponent) logo() string {
 		Render(dgm + hyphen + o + version)
 }
 
-func formatTokensAndCost(tokens float64, contextWindow float64, cost float64) string {
-	// Format tokens in human-readable format (e.g., 110K, 1.2M)
-	var formattedTokens string
-	switch {
-	case tokens >= 1_000_000:
-		formattedTokens = fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
-	case tokens >= 1_000:
-		formattedTokens = fmt.Sprintf("%.1fK", float64(tokens)/1_000)
-	default:
-		formattedTokens = fmt.Sprintf("%d", int(tokens))
+// model shows the current model, clicking it opens the model dialog
+func (m statusComponent) model() string {
+	if m.app.Session == nil || m.app.Session.ID == "" || m.app.Model == nil {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	return styles.NewStyle().
+		Foreground(t.Text()).
+		Background(t.BackgroundPanel()).
+		Padding(0, 1).
+		Render(m.app.Model.Name)
+}
+
+// sessionInfo shows the context used and the cost of the session
+func (m statusComponent) sessionInfo() string {
+	if m.app.Session == nil || m.app.Session.ID == "" {
+		return ""
+	}
+	t := theme.CurrentTheme()
+	tokens := app.ContextTokens(m.app.Messages)
+	cost := float64(0)
+	contextWindow := m.app.Model.Limit.Context
+
+	for _, message := range m.app.Messages {
+		cost += message.Metadata.Assistant.Cost
 	}
 
-	// Remove .0 suffix if present
-	if strings.HasSuffix(formattedTokens, ".0K") {
-		formattedTokens = strings.Replace(formattedTokens, ".0K", "K", 1)
+	return styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundElement()).
+		Padding(0, 1).
+		Render(formatTokensAndCost(tokens, contextWindow, cost))
+}
+
+// taskLatency shows the round trip of the task event connection, highlighted
+// when it lags
+func (m statusComponent) taskLatency() string {
+	if m.app.TaskClient == nil {
+		return ""
 	}
-	if strings.HasSuffix(formattedTokens, ".0M") {
-		formattedTokens = strings.Replace(formattedTokens, ".0M", "M", 1)
+	stats := m.app.TaskClient.GetConnectionStats()
+	if !stats.Connected || stats.LastPing.IsZero() {
+		return ""
 	}
+	t := theme.CurrentTheme()
+	style := styles.NewStyle().Foreground(t.TextMuted()).Background(t.BackgroundPanel()).Padding(0, 1)
+	text := "tasks " + formatLatency(stats.Latency)
+	if stats.Lagging() {
+		style = style.Foreground(t.BackgroundPanel()).Background(t.Warning())
+		text = "tasks lagging " + formatLatency(stats.Latency)
+	}
+	return style.Render(text)
+}
+
+func formatLatency(latency time.Duration) string {
+	if latency < time.Second {
+		return fmt.Sprintf("%dms", latency.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", latency.Seconds())
+}
+
+// formatElapsed shows a duration in whole seconds, as 2m10s
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+func (m statusComponent) ModelAt(x int) bool {
+	model := m.model()
+	if model == "" {
+		return false
+	}
+	end := m.width - lipgloss.Width(m.sessionInfo())
+	return x >= end-lipgloss.Width(model) && x < end
+}
+
+func formatTokensAndCost(tokens float64, contextWindow float64, cost float64) string {
+	formattedTokens := util.FormatTokens(tokens)
 
 	// Format cost with $ symbol and 2 decimal places
 	formattedCost := fmt.Sprintf("$%.2f", cost)
@@ -94,52 +170,93 @@ func (m statusComponent) View() string {
 		Padding(0, 1).
 		Render(m.app.Info.Path.Cwd)
 
-	sessionInfo := ""
-	if m.app.Session.ID != "" {
-		tokens := float64(0)
-		cost := float64(0)
-		contextWindow := m.app.Model.Limit.Context
-
-		for _, message := range m.app.Messages {
-			cost += message.Metadata.Assistant.Cost
-			usage := message.Metadata.Assistant.Tokens
-			if usage.Output > 0 {
-				if message.Metadata.Assistant.Summary {
-					tokens = usage.Output
-					continue
-				}
-				tokens = (usage.Input +
-					usage.Cache.Write +
-					usage.Cache.Read +
-					usage.Output +
-					usage.Reasoning)
-			}
-		}
+	model := m.model()
+	sessionInfo := m.sessionInfo()
 
-		sessionInfo = styles.NewStyle().
+	if m.app.Bundle != nil {
+		cwd = styles.NewStyle().
 			Foreground(t.TextMuted()).
-			Background(t.BackgroundElement()).
+			Background(t.BackgroundPanel()).
+			Padding(0, 1).
+			Render("read-only bundle " + filepath.Base(m.app.Bundle.Path))
+	}
+
+	branch := ""
+	if m.app.Git != nil {
+		color := t.TextMuted()
+		if !m.app.Git.Clean() {
+			color = t.Warning()
+		}
+		branch = styles.NewStyle().
+			Foreground(color).
+			Background(t.BackgroundPanel()).
 			Padding(0, 1).
-			Render(formatTokensAndCost(tokens, contextWindow, cost))
+			Render(m.app.Git.Summary())
+	}
+
+	restricted := ""
+	if m.app.Restricted() {
+		restricted = styles.NewStyle().
+			Foreground(t.Warning()).
+			Background(t.BackgroundPanel()).
+			Padding(0, 1).
+			Render("restricted")
+	}
+
+	tasks := m.taskLatency()
+	instructions := ""
+	if m.app.SessionInstructions(m.app.Session) != "" {
+		instructions = styles.NewStyle().
+			Foreground(t.Accent()).
+			Background(t.BackgroundPanel()).
+			Padding(0, 1).
+			Render("custom instructions")
+	}
+	mcp := ""
+	if degraded := m.app.MCPStats.Degraded(); len(degraded) > 0 {
+		mcp = styles.NewStyle().
+			Foreground(t.BackgroundPanel()).
+			Background(t.Warning()).
+			Padding(0, 1).
+			Render("mcp slow: " + strings.Join(degraded, ", "))
+	}
+
+	stuck := ""
+	if stats := m.app.ToolTimers.Stats(m.app.ToolStuckAfter()); stats.Stuck > 0 {
+		text := fmt.Sprintf("%s stuck %s", stats.Longest.Name, formatElapsed(stats.Longest.Elapsed()))
+		if stats.Stuck > 1 {
+			text = fmt.Sprintf("%d tools stuck", stats.Stuck)
+		}
+		stuck = styles.NewStyle().
+			Foreground(t.BackgroundPanel()).
+			Background(t.Warning()).
+			Padding(0, 1).
+			Render(text)
 	}
 
 	// diagnostics := styles.Padded().Background(t.BackgroundElement()).Render(m.projectDiagnostics())
 
 	space := max(
 		0,
-		m.width-lipgloss.Width(logo)-lipgloss.Width(cwd)-lipgloss.Width(sessionInfo),
+		m.width-lipgloss.Width(logo)-lipgloss.Width(cwd)-lipgloss.Width(branch)-lipgloss.Width(restricted)-
+			lipgloss.Width(instructions)-lipgloss.Width(tasks)-lipgloss.Width(mcp)-lipgloss.Width(stuck)-lipgloss.Width(model)-lipgloss.Width(sessionInfo),
 	)
 	spacer := styles.NewStyle().Background(t.BackgroundPanel()).Width(space).Render("")
 
-	status := logo + cwd + spacer + sessionInfo
+	status := logo + cwd + branch + restricted + spacer + instructions + tasks + mcp + stuck + model + sessionInfo
 
-	blank := styles.NewStyle().Background(t.Background()).Width(m.width).Render("")
-	return blank + "\n" + status
+	hints := styles.NewStyle().
+		Background(t.Background()).
+		Width(m.width).
+		Padding(0, 1).
+		Render(m.hintStrip())
+	return hints + "\n" + status
 }
 
 func NewStatusCmp(app *app.App) StatusComponent {
 	statusComponent := &statusComponent{
-		app: app,
+		app:       app,
+		following: true,
 	}
 
 	return statusComponent
@@ -640,6 +640,26 @@ func (m *Model) SetCursorColumn(col int) {
 	m.lastCharOffset = 0
 }
 
+// WordBeforeCursor returns the text between the cursor and the whitespace
+// before it on the cursor's line.
+func (m Model) WordBeforeCursor() string {
+	line := m.value[m.row]
+	col := clamp(m.col, 0, len(line))
+	start := col
+	for start > 0 && !unicode.IsSpace(line[start-1]) {
+		start--
+	}
+	return string(line[start:col])
+}
+
+// DeleteBeforeCursor removes up to n runes before the cursor on its line.
+func (m *Model) DeleteBeforeCursor(n int) {
+	col := clamp(m.col, 0, len(m.value[m.row]))
+	n = clamp(n, 0, col)
+	m.value[m.row] = append(m.value[m.row][:col-n], m.value[m.row][col:]...)
+	m.SetCursorColumn(col - n)
+}
+
 // CursorStart moves the cursor to the start of the input field.
 func (m *Model) CursorStart() {
 	m.SetCursorColumn(0)
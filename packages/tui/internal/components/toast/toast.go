@@ -19,6 +19,8 @@ type ShowToastMsg struct {
 	Title    *string
 	Color    compat.AdaptiveColor
 	Duration time.Duration
+	// Label names the kind of toast, which is otherwise only its color
+	Label string
 }
 
 // DismissToastMsg is a message to dismiss a specific toast
@@ -34,6 +36,7 @@ type Toast struct {
 	Color     compat.AdaptiveColor
 	CreatedAt time.Time
 	Duration  time.Duration
+	Label     string
 }
 
 // ToastManager manages multiple toast notifications
@@ -64,6 +67,7 @@ func (tm *ToastManager) Update(msg tea.Msg) (*ToastManager, tea.Cmd) {
 			Color:     msg.Color,
 			CreatedAt: time.Now(),
 			Duration:  msg.Duration,
+			Label:     msg.Label,
 		}
 
 		tm.toasts = append(tm.toasts, toast)
@@ -93,10 +97,10 @@ func (tm *ToastManager) renderSingleToast(toast Toast) string {
 	baseStyle := styles.NewStyle().
 		Foreground(t.Text()).
 		Background(t.BackgroundElement()).
-		Padding(1, 2)
+		Padding(layout.Scale(1), layout.Scale(2))
 
-	maxWidth := max(40, layout.Current.Viewport.Width/3)
-	contentMaxWidth := max(maxWidth-6, 20)
+	maxWidth := max(layout.Scale(40), layout.Current.Viewport.Width/3)
+	contentMaxWidth := max(maxWidth-2*layout.Scale(2)-2, 20)
 
 	// Build content with wrapping
 	var content strings.Builder
@@ -108,12 +112,16 @@ func (tm *ToastManager) renderSingleToast(toast Toast) string {
 	}
 
 	// Wrap message text
+	message := toast.Message
+	if styles.Accessible() && toast.Label != "" {
+		message = toast.Label + " " + message
+	}
 	messageStyle := styles.NewStyle()
-	contentWidth := lipgloss.Width(toast.Message)
+	contentWidth := lipgloss.Width(message)
 	if contentWidth > contentMaxWidth {
 		messageStyle = messageStyle.Width(contentMaxWidth)
 	}
-	content.WriteString(messageStyle.Render(toast.Message))
+	content.WriteString(messageStyle.Render(message))
 
 	// Render toast with max width
 	return baseStyle.MaxWidth(maxWidth).Render(content.String())
@@ -163,15 +171,15 @@ func (tm *ToastManager) RenderOverlay(background string) string {
 			break
 		}
 
-		// Place this toast
-		result = layout.PlaceOverlay(
-			x,
-			currentY,
-			toastView,
-			result,
+		// Place this toast, without a border in accessible mode
+		options := []layout.OverlayOption{
 			layout.WithOverlayBorder(),
 			layout.WithOverlayBorderColor(toast.Color),
-		)
+		}
+		if styles.Accessible() {
+			options = nil
+		}
+		result = layout.PlaceOverlay(x, currentY, toastView, result, options...)
 
 		// Move down for next toast (add 1 for spacing between toasts)
 		currentY += toastHeight + 1
@@ -189,6 +197,7 @@ type toastOptions struct {
 	title    *string
 	duration *time.Duration
 	color    *compat.AdaptiveColor
+	label    string
 }
 
 type ToastOption func(*toastOptions)
@@ -210,6 +219,13 @@ func WithColor(color compat.AdaptiveColor) ToastOption {
 	}
 }
 
+// withLabel sets the status label shown in accessible mode
+func withLabel(label string) ToastOption {
+	return func(t *toastOptions) {
+		t.label = label
+	}
+}
+
 func NewToast(message string, options ...ToastOption) tea.Cmd {
 	t := theme.CurrentTheme()
 	duration := 5 * time.Second
@@ -229,12 +245,13 @@ func NewToast(message string, options ...ToastOption) tea.Cmd {
 			Title:    opts.title,
 			Duration: *opts.duration,
 			Color:    *opts.color,
+			Label:    opts.label,
 		}
 	}
 }
 
 func NewInfoToast(message string, options ...ToastOption) tea.Cmd {
-	options = append(options, WithColor(theme.CurrentTheme().Info()))
+	options = append(options, WithColor(theme.CurrentTheme().Info()), withLabel(styles.StatusInfo))
 	return NewToast(
 		message,
 		options...,
@@ -242,7 +259,7 @@ func NewInfoToast(message string, options ...ToastOption) tea.Cmd {
 }
 
 func NewSuccessToast(message string, options ...ToastOption) tea.Cmd {
-	options = append(options, WithColor(theme.CurrentTheme().Success()))
+	options = append(options, WithColor(theme.CurrentTheme().Success()), withLabel(styles.StatusSuccess))
 	return NewToast(
 		message,
 		options...,
@@ -250,7 +267,7 @@ func NewSuccessToast(message string, options ...ToastOption) tea.Cmd {
 }
 
 func NewWarningToast(message string, options ...ToastOption) tea.Cmd {
-	options = append(options, WithColor(theme.CurrentTheme().Warning()))
+	options = append(options, WithColor(theme.CurrentTheme().Warning()), withLabel(styles.StatusWarning))
 	return NewToast(
 		message,
 		options...,
@@ -258,7 +275,7 @@ func NewWarningToast(message string, options ...ToastOption) tea.Cmd {
 }
 
 func NewErrorToast(message string, options ...ToastOption) tea.Cmd {
-	options = append(options, WithColor(theme.CurrentTheme().Error()))
+	options = append(options, WithColor(theme.CurrentTheme().Error()), withLabel(styles.StatusError))
 	return NewToast(
 		message,
 		options...,
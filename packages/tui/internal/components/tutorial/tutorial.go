@@ -0,0 +1,228 @@
+package tutorial
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// Step is one core flow of the walkthrough
+type Step struct {
+	ID    string
+	Title string
+	// Hint tells how to do the step, %s is replaced with how to run Command
+	Hint    string
+	Command commands.CommandName
+	// Done reports whether msg completes the step
+	Done func(msg tea.Msg) bool
+}
+
+func executed(name commands.CommandName) func(tea.Msg) bool {
+	return func(msg tea.Msg) bool {
+		executed, ok := msg.(commands.CommandExecutedMsg)
+		return ok && executed.Name == name
+	}
+}
+
+// Steps are the flows the tutorial walks through, in order
+var Steps = []Step{
+	{
+		ID:    "send",
+		Title: "Send a message",
+		Hint:  "Type a question in the editor below and press enter.",
+		Done: func(msg tea.Msg) bool {
+			_, ok := msg.(app.SendMsg)
+			return ok
+		},
+	},
+	{
+		ID:      "sessions",
+		Title:   "Open the session list",
+		Hint:    "%s to see your sessions, then esc to close it.",
+		Command: commands.SessionListCommand,
+		Done:    executed(commands.SessionListCommand),
+	},
+	{
+		ID:      "agent",
+		Title:   "Spawn an agent",
+		Hint:    "%s, give it a goal and wait for the agent to start.",
+		Command: commands.SpawnAgentsCommand,
+		Done: func(msg tea.Msg) bool {
+			_, ok := msg.(app.TaskStartedMsg)
+			return ok
+		},
+	},
+	{
+		ID:      "diff",
+		Title:   "View a diff",
+		Hint:    "%s to see the files that were changed.",
+		Command: commands.DiffViewCommand,
+		Done:    executed(commands.DiffViewCommand),
+	},
+	{
+		ID:      "revert",
+		Title:   "Revert a checkpoint",
+		Hint:    "%s and pick a checkpoint to roll the files back.",
+		Command: commands.SessionRevertCommand,
+		Done: func(msg tea.Msg) bool {
+			_, ok := msg.(app.CheckpointRestoredMsg)
+			return ok
+		},
+	},
+}
+
+// NextStep returns the index of the first step not in done, len(Steps) when
+// all of them are
+func NextStep(done []string) int {
+	for i, step := range Steps {
+		if !slices.Contains(done, step.ID) {
+			return i
+		}
+	}
+	return len(Steps)
+}
+
+// StartMsg shows the tutorial at the first step not done yet, from the
+// beginning if all of them are or Restart is set
+type StartMsg struct {
+	Restart bool
+}
+
+// SkipMsg marks the current step as done without doing it
+type SkipMsg struct{}
+
+// StopMsg hides the tutorial, the progress is kept
+type StopMsg struct{}
+
+// TutorialComponent shows the current step of the walkthrough over the chat
+// and advances when the step is done. Progress is saved in the state.
+type TutorialComponent interface {
+	Update(msg tea.Msg) (TutorialComponent, tea.Cmd)
+	Render(view string) string
+	Active() bool
+}
+
+type tutorialComponent struct {
+	app    *app.App
+	active bool
+}
+
+func (t *tutorialComponent) Active() bool {
+	return t.active
+}
+
+func (t *tutorialComponent) Update(msg tea.Msg) (TutorialComponent, tea.Cmd) {
+	switch msg := msg.(type) {
+	case StartMsg:
+		if msg.Restart || NextStep(t.app.State.Tutorial) == len(Steps) {
+			t.app.State.Tutorial = nil
+			t.app.SaveState()
+		}
+		t.active = true
+		return t, nil
+	case StopMsg:
+		t.active = false
+		return t, nil
+	case SkipMsg:
+		if !t.active {
+			return t, nil
+		}
+		return t, t.complete()
+	}
+	if !t.active {
+		return t, nil
+	}
+	if step := NextStep(t.app.State.Tutorial); step < len(Steps) && Steps[step].Done(msg) {
+		return t, t.complete()
+	}
+	return t, nil
+}
+
+// complete marks the current step as done and ends the tutorial after the
+// last one
+func (t *tutorialComponent) complete() tea.Cmd {
+	step := NextStep(t.app.State.Tutorial)
+	if step == len(Steps) {
+		t.active = false
+		return nil
+	}
+	t.app.State.Tutorial = append(t.app.State.Tutorial, Steps[step].ID)
+	t.app.SaveState()
+	if step+1 < len(Steps) {
+		return nil
+	}
+	t.active = false
+	return toast.NewSuccessToast("You have seen the core flows, /help lists the rest", toast.WithTitle("Tutorial complete"))
+}
+
+// howTo tells how to run a command: its first keybinding and its slash
+// command, as configured
+func (t *tutorialComponent) howTo(name commands.CommandName) string {
+	command, ok := t.app.Commands[name]
+	if !ok {
+		return "Run " + string(name)
+	}
+	var ways []string
+	if len(command.Keybindings) > 0 {
+		ways = append(ways, "press "+command.Keybindings[0].String())
+	}
+	if command.Trigger != "" {
+		ways = append(ways, "type /"+command.Trigger)
+	}
+	if len(ways) == 0 {
+		return "Run " + command.Description
+	}
+	how := strings.Join(ways, " or ")
+	return strings.ToUpper(how[:1]) + how[1:]
+}
+
+// Render draws the current step in the top left corner of view
+func (t *tutorialComponent) Render(view string) string {
+	step := NextStep(t.app.State.Tutorial)
+	if !t.active || step == len(Steps) {
+		return view
+	}
+	current := Steps[step]
+	hint := current.Hint
+	if current.Command != "" {
+		hint = fmt.Sprintf(hint, t.howTo(current.Command))
+	}
+
+	th := theme.CurrentTheme()
+	width := min(50, lipgloss.Width(view)-8)
+	if width < 20 {
+		return view
+	}
+	base := styles.NewStyle().Background(th.BackgroundPanel()).Width(width)
+	title := base.Foreground(th.Primary()).Bold(true).
+		Render(fmt.Sprintf("Tutorial %d/%d · %s", step+1, len(Steps), current.Title))
+	body := base.Foreground(th.Text()).Render(hint)
+	footer := base.Foreground(th.TextMuted()).Render("/tutorial skip · /tutorial stop")
+	box := styles.NewStyle().
+		Background(th.BackgroundPanel()).
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", body, "", footer))
+
+	return layout.PlaceOverlay(
+		2,
+		1,
+		box,
+		view,
+		layout.WithOverlayBorder(),
+		layout.WithOverlayBorderColor(th.Primary()),
+	)
+}
+
+// NewTutorialComponent creates a hidden tutorial, StartMsg shows it
+func NewTutorialComponent(app *app.App) TutorialComponent {
+	return &tutorialComponent{app: app}
+}
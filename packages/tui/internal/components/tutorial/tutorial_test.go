@@ -0,0 +1,47 @@
+package tutorial
+
+import (
+	"testing"
+
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+)
+
+func TestNextStep(t *testing.T) {
+	tests := []struct {
+		name string
+		done []string
+		want int
+	}{
+		{"fresh", nil, 0},
+		{"first done", []string{"send"}, 1},
+		{"skipped ahead", []string{"send", "sessions", "diff"}, 2},
+		{"unknown steps are ignored", []string{"removed"}, 0},
+		{"all done", []string{"send", "sessions", "agent", "diff", "revert"}, len(Steps)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextStep(tt.done); got != tt.want {
+				t.Errorf("NextStep(%v) = %d, want %d", tt.done, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStepsDone(t *testing.T) {
+	sessions := Steps[NextStep([]string{"send"})]
+	if !sessions.Done(commands.CommandExecutedMsg{Name: commands.SessionListCommand}) {
+		t.Error("opening the session list does not complete the sessions step")
+	}
+	if sessions.Done(commands.CommandExecutedMsg{Name: commands.DiffViewCommand}) {
+		t.Error("opening the diff view completes the sessions step")
+	}
+	if !Steps[0].Done(app.SendMsg{Text: "hi"}) {
+		t.Error("sending a message does not complete the first step")
+	}
+	for _, step := range Steps {
+		if step.Done == nil {
+			t.Errorf("step %s can't be completed", step.ID)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -16,17 +17,239 @@ type ModelUsage struct {
 	LastUsed   time.Time `toml:"last_used"`
 }
 
+// ModelRef identifies a model of a provider
+type ModelRef struct {
+	ProviderID string `toml:"provider_id"`
+	ModelID    string `toml:"model_id"`
+}
+
+// PromptTemplate is a pinned prompt shown on the start screen
+type PromptTemplate struct {
+	Name   string `toml:"name"`
+	Prompt string `toml:"prompt"`
+}
+
 type State struct {
-	Theme              string       `toml:"theme"`
-	Provider           string       `toml:"provider"`
-	Model              string       `toml:"model"`
-	RecentlyUsedModels []ModelUsage `toml:"recently_used_models"`
+	Theme              string           `toml:"theme"`
+	Provider           string           `toml:"provider"`
+	Model              string           `toml:"model"`
+	RecentlyUsedModels []ModelUsage     `toml:"recently_used_models"`
+	FavoriteModels     []ModelRef       `toml:"favorite_models"`
+	Notifications      bool             `toml:"notifications"`
+	StartScreen        string           `toml:"start_screen"`
+	Templates          []PromptTemplate `toml:"templates"`
+	ScrollStep         int              `toml:"scroll_step"`
+	ScrollMomentum     bool             `toml:"scroll_momentum"`
+	// ReducedMotion replaces animations with static feedback
+	ReducedMotion bool `toml:"reduced_motion"`
+	// Accessible renders plain text for screen readers, see --accessible
+	Accessible bool `toml:"accessible"`
+	// AgentMode is the autonomy profile of sub-agents
+	AgentMode string `toml:"agent_mode"`
+	// PinnedContext lists files that are included with every prompt
+	PinnedContext []string `toml:"pinned_context"`
+	// Features turns optional subsystems off even if the server supports them
+	Features map[string]bool `toml:"features"`
+	// Sessions holds local tags and archive flags, keyed by session ID
+	Sessions map[string]SessionMeta `toml:"sessions"`
+	// Draft is the unsent prompt saved on exit, restored on the next start
+	Draft string `toml:"draft"`
+	// ImagePreviews renders image links in assistant messages inline
+	ImagePreviews bool `toml:"image_previews"`
+	// ImagePreviewDomains lists the hosts image previews are fetched from,
+	// subdomains included
+	ImagePreviewDomains []string `toml:"image_preview_domains"`
+	// LastSession is the session open on exit, checked against the server
+	// before it is offered for resuming
+	LastSession *SessionSnapshot `toml:"last_session"`
+	// AgentPresets are agent profiles selectable in the agent mode dialog
+	AgentPresets []AgentPreset `toml:"agent_presets"`
+	// ActivePreset names the selected agent preset, empty for none
+	ActivePreset string `toml:"active_preset"`
+	// QuietHours hold back notifications except critical failures
+	QuietHours []QuietHoursRule `toml:"quiet_hours"`
+	// ToolStuckAfter is how many seconds a tool call runs before it is
+	// flagged as possibly stuck, 0 for the default
+	ToolStuckAfter int `toml:"tool_stuck_after"`
+	// IdleReminderAfter is how many minutes a session waits for a reply
+	// after the assistant finished before a reminder is sent, 0 for the
+	// default of 10 and -1 for no reminders
+	IdleReminderAfter int `toml:"idle_reminder_after"`
+	// TaskToasts picks the agent task events that show a toast: "all" for
+	// start, completion and failure, "failures", or "off". Progress always
+	// shows in the task box only.
+	TaskToasts string `toml:"task_toasts"`
+	// FoldAgentSummaries shows the answer and changed files of a finished
+	// sub-agent below its task in the parent transcript
+	FoldAgentSummaries bool `toml:"fold_agent_summaries"`
+	// MaxWidth is how many columns the transcript and editor use at most,
+	// 0 for the default of 80 and -1 for the whole terminal
+	MaxWidth int `toml:"max_width"`
+	// Density is "comfortable" or "compact", which trims the padding and
+	// the blank lines around transcript blocks
+	Density string `toml:"density"`
+	// Zoom scales paddings, box widths and truncation limits in percent,
+	// for terminals with a larger or smaller font. 0 for 100.
+	Zoom int `toml:"zoom"`
+	// Markdown holds the markdown rendering choices
+	Markdown MarkdownConfig `toml:"markdown"`
+	// Hyperlinks makes file paths clickable with OSC 8 links: "auto" on
+	// terminals known to support them, "on" or "off"
+	Hyperlinks string `toml:"hyperlinks"`
+	// HyperlinkScheme is "file" to link file:// URLs, or an editor URL
+	// scheme such as "vscode" to open the files in the editor
+	HyperlinkScheme string `toml:"hyperlink_scheme"`
+	// Glyphs picks the characters of spinners, boxes and icons: "auto"
+	// detects the terminal, "unicode", "ascii" or "nerd" for Nerd Font icons
+	Glyphs string `toml:"glyphs"`
+	// LargePrompt holds the sizes above which a prompt is confirmed before
+	// it is sent
+	LargePrompt LargePromptConfig `toml:"large_prompt"`
+	// Paste holds how pasted text is cleaned up before it is inserted
+	Paste PasteConfig `toml:"paste"`
+	// Tutorial lists the tutorial steps that are done
+	Tutorial []string `toml:"tutorial"`
+	// Bell holds the audible alerts for finished work
+	Bell BellConfig `toml:"bell"`
+	// Broadcasts are the latest prompts sent to several sessions at once,
+	// oldest first, kept to compare and rate the responses
+	Broadcasts []BroadcastRecord `toml:"broadcasts"`
+	// Workspaces holds the trust decision of each workspace, keyed by its
+	// root directory. A workspace without one is restricted until the
+	// user decides.
+	Workspaces map[string]WorkspaceTrust `toml:"workspaces"`
+}
+
+// WorkspaceTrust is the trust decision for a workspace
+type WorkspaceTrust string
+
+const (
+	// WorkspaceTrusted runs the workspace with its project config and
+	// hooks, and lets bash be allowed for a whole session
+	WorkspaceTrusted WorkspaceTrust = "trusted"
+	// WorkspaceRestricted keeps the workspace restricted without asking
+	// again
+	WorkspaceRestricted WorkspaceTrust = "restricted"
+)
+
+// BroadcastRecord is a prompt that was sent to several sessions at once
+type BroadcastRecord struct {
+	ID     string           `toml:"id"`
+	Prompt string           `toml:"prompt"`
+	Time   time.Time        `toml:"time"`
+	Runs   []BroadcastEntry `toml:"runs"`
+}
+
+// BroadcastEntry is a session that answered a broadcast
+type BroadcastEntry struct {
+	SessionID  string `toml:"session_id"`
+	Title      string `toml:"title"`
+	ProviderID string `toml:"provider_id"`
+	ModelID    string `toml:"model_id"`
+	// Rating is the score given to the response, 0 if unrated
+	Rating int `toml:"rating"`
+}
+
+// BellConfig holds when the terminal sounds an alert. Alerts only sound
+// after the user was away from the keyboard for a while.
+type BellConfig struct {
+	// Response sounds when an assistant response completes
+	Response bool `toml:"response"`
+	// Task sounds when an agent task completes or fails
+	Task bool `toml:"task"`
+	// Muted silences the alerts without changing which events sound
+	Muted bool `toml:"muted"`
+	// IdleAfter is how many seconds without input pass before an alert
+	// sounds, 0 for the default of 30
+	IdleAfter int `toml:"idle_after"`
+	// Sound is "bell" for the terminal bell or "tone" for a short tone on
+	// terminals that play DECPS sounds
+	Sound string `toml:"sound"`
+}
+
+// MarkdownConfig holds how assistant messages are rendered
+type MarkdownConfig struct {
+	// Emoji turns :shortcodes: into emoji
+	Emoji bool `toml:"emoji"`
+	// LineNumbers numbers the lines of code blocks
+	LineNumbers bool `toml:"line_numbers"`
+	// Headings is "hashes" to keep the # markers, "plain" or "underline"
+	Headings string `toml:"headings"`
+	// Links is "full" to show the target after the link text, or "text"
+	Links string `toml:"links"`
+}
+
+// LargePromptConfig holds when a prompt is confirmed before sending. A
+// threshold of 0 takes the default, -1 turns the check off.
+type LargePromptConfig struct {
+	// Tokens is the estimated size of the prompt and the pinned context
+	Tokens int `toml:"tokens"`
+	// Bytes is the size of the prompt with its images and pinned context
+	Bytes int `toml:"bytes"`
+}
+
+// PasteConfig holds how pasted text is cleaned up. Line endings are always
+// normalized and terminal escape sequences dropped.
+type PasteConfig struct {
+	// TrimTrailingSpace removes the whitespace at the end of every line
+	TrimTrailingSpace bool `toml:"trim_trailing_space"`
+	// TabWidth expands tabs to spaces up to the next multiple of the width,
+	// 0 keeps the tabs
+	TabWidth int `toml:"tab_width"`
+}
+
+// QuietHoursRule is a daily span like 22:00 to 08:00, on the given weekdays
+// (mon, tue, ...) or every day
+type QuietHoursRule struct {
+	Days  []string `toml:"days"`
+	Start string   `toml:"start"`
+	End   string   `toml:"end"`
+}
+
+// AgentPreset is an agent profile sent with every message, sub-agents
+// inherit its restrictions
+type AgentPreset struct {
+	Name string `toml:"name"`
+	// Model is given as provider/model, empty keeps the selected model
+	Model string `toml:"model"`
+	// Tools restricts the agent to these tools, empty allows all the tools
+	// of its mode
+	Tools       []string `toml:"tools"`
+	Temperature *float64 `toml:"temperature"`
+}
+
+// SessionSnapshot is what the client last knew of a session
+type SessionSnapshot struct {
+	ID            string `toml:"id"`
+	Title         string `toml:"title"`
+	Messages      int    `toml:"messages"`
+	LastMessageID string `toml:"last_message_id"`
+}
+
+// SessionMeta is session metadata the server does not store
+type SessionMeta struct {
+	Tags     []string `toml:"tags"`
+	Archived bool     `toml:"archived"`
+	// Bookmarks are named spots in the transcript, oldest first
+	Bookmarks []Bookmark `toml:"bookmarks"`
+}
+
+// Bookmark is a named scroll position in the transcript of a session
+type Bookmark struct {
+	Name string `toml:"name"`
+	// MessageID is the message at the top of the view, Offset how many of
+	// its lines were scrolled past
+	MessageID string `toml:"message_id"`
+	Offset    int    `toml:"offset"`
 }
 
 func NewState() *State {
 	return &State{
 		Theme:              "dgmo",
 		RecentlyUsedModels: make([]ModelUsage, 0),
+		Notifications:      true,
+		ScrollStep:         3,
+		ScrollMomentum:     true,
 	}
 }
 
@@ -58,6 +281,23 @@ func (s *State) UpdateModelUsage(providerID, modelID string) {
 	}
 }
 
+// IsFavoriteModel reports whether the model is in the favorites list
+func (s *State) IsFavoriteModel(providerID, modelID string) bool {
+	return slices.Contains(s.FavoriteModels, ModelRef{ProviderID: providerID, ModelID: modelID})
+}
+
+// ToggleFavoriteModel adds the model to the favorites list, or removes it if
+// it is already there, and reports whether it is now a favorite
+func (s *State) ToggleFavoriteModel(providerID, modelID string) bool {
+	ref := ModelRef{ProviderID: providerID, ModelID: modelID}
+	if idx := slices.Index(s.FavoriteModels, ref); idx >= 0 {
+		s.FavoriteModels = slices.Delete(s.FavoriteModels, idx, idx+1)
+		return false
+	}
+	s.FavoriteModels = append(s.FavoriteModels, ref)
+	return true
+}
+
 // SaveState writes the provided Config struct to the specified TOML file.
 // It will create the file if it doesn't exist, or overwrite it if it does.
 func SaveState(filePath string, state *State) error {
@@ -82,13 +322,14 @@ func SaveState(filePath string, state *State) error {
 
 // LoadState loads the state from the specified TOML file.
 // It returns a pointer to the State struct and an error if any issues occur.
+// Fields missing from the file keep their NewState defaults.
 func LoadState(filePath string) (*State, error) {
-	var state State
-	if _, err := toml.DecodeFile(filePath, &state); err != nil {
+	state := NewState()
+	if _, err := toml.DecodeFile(filePath, state); err != nil {
 		if _, statErr := os.Stat(filePath); os.IsNotExist(statErr) {
 			return nil, fmt.Errorf("state file not found at %s: %w", filePath, statErr)
 		}
 		return nil, fmt.Errorf("failed to decode TOML from file %s: %w", filePath, err)
 	}
-	return &state, nil
+	return state, nil
 }
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFile is the per-project overlay, relative to the project root
+const ProjectConfigFile = ".dgmo/config.json"
+
+// ProjectConfig overlays the user settings for a single project. Empty
+// fields leave the user setting untouched.
+type ProjectConfig struct {
+	Theme string `json:"theme,omitempty"`
+	// Keybinds override command keybindings by command name, plus "leader"
+	Keybinds map[string]string `json:"keybinds,omitempty"`
+	// Autonomy is the agent mode profile, "read-only" or "all-tools"
+	Autonomy string `json:"autonomy,omitempty"`
+	// Context lists files, relative to the project root, that are pinned to
+	// every prompt in addition to the user's pinned context
+	Context []string `json:"context,omitempty"`
+}
+
+// LoadProjectConfig reads the overlay at path. A missing file is not an
+// error and returns nil.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config %s: %w", path, err)
+	}
+	var project ProjectConfig
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse project config %s: %w", path, err)
+	}
+	switch project.Autonomy {
+	case "", "read-only", "all-tools":
+	default:
+		return nil, fmt.Errorf("invalid autonomy %q in %s, expected read-only or all-tools", project.Autonomy, path)
+	}
+	return &project, nil
+}
+
+// SaveProjectKeybinds replaces the keybinds of the overlay at path, keeping
+// its other settings as they are. The file is created if it doesn't exist.
+func SaveProjectKeybinds(path string, keybinds map[string]string) error {
+	fields := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read project config %s: %w", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("failed to parse project config %s: %w", path, err)
+		}
+	}
+	if len(keybinds) == 0 {
+		delete(fields, "keybinds")
+	} else {
+		encoded, err := json.Marshal(keybinds)
+		if err != nil {
+			return err
+		}
+		fields["keybinds"] = encoded
+	}
+
+	data, err = json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write project config %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Package git reads the branch and working tree state and the refs of a
+// repository
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrNotRepository is returned when the directory is not inside a git repository
+var ErrNotRepository = errors.New("not a git repository")
+
+// Status is the state of a repository's working tree
+type Status struct {
+	// Branch is the checked out branch, empty when HEAD is detached
+	Branch string
+	// Commit is the abbreviated hash of HEAD, empty before the first commit
+	Commit string
+	// Dirty counts the changed, staged and untracked files
+	Dirty int
+	// Upstream is the remote branch the branch tracks, if any
+	Upstream string
+	Ahead    int
+	Behind   int
+}
+
+// Clean reports whether the working tree has no uncommitted changes
+func (s *Status) Clean() bool {
+	return s.Dirty == 0
+}
+
+// Summary describes the status in a few characters, like "main *3 ↑1"
+func (s *Status) Summary() string {
+	var b strings.Builder
+	switch {
+	case s.Branch != "":
+		b.WriteString(s.Branch)
+	case s.Commit != "":
+		b.WriteString(s.Commit)
+	default:
+		b.WriteString("(no commits)")
+	}
+	if s.Dirty > 0 {
+		fmt.Fprintf(&b, " *%d", s.Dirty)
+	}
+	if s.Ahead > 0 {
+		fmt.Fprintf(&b, " ↑%d", s.Ahead)
+	}
+	if s.Behind > 0 {
+		fmt.Fprintf(&b, " ↓%d", s.Behind)
+	}
+	return b.String()
+}
+
+// Read returns the status of the repository containing dir
+func Read(ctx context.Context, dir string) (*Status, error) {
+	out, err := run(ctx, dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return nil, err
+	}
+	return parse(out), nil
+}
+
+// parse reads the output of git status --porcelain=v2 --branch
+func parse(out []byte) *Status {
+	status := &Status{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		header, ok := strings.CutPrefix(line, "# ")
+		if !ok {
+			if line != "" {
+				status.Dirty++
+			}
+			continue
+		}
+		key, value, _ := strings.Cut(header, " ")
+		switch key {
+		case "branch.oid":
+			if value != "(initial)" && len(value) >= 7 {
+				status.Commit = value[:7]
+			}
+		case "branch.head":
+			if value != "(detached)" {
+				status.Branch = value
+			}
+		case "branch.upstream":
+			status.Upstream = value
+		case "branch.ab":
+			ahead, behind, _ := strings.Cut(value, " ")
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+		}
+	}
+	return status
+}
+
+// Ref is a branch, tag or commit a prompt can refer to
+type Ref struct {
+	// Name is the short name of a branch or tag, or the abbreviated hash
+	// of a commit
+	Name string
+	// Kind is branch, remote, tag or commit
+	Kind string
+	// Subject is the subject line of the commit the ref points at
+	Subject string
+}
+
+// Refs returns the branches and tags of the repository containing dir, the
+// most recently committed first, followed by the last commits of HEAD
+func Refs(ctx context.Context, dir string, commits int) ([]Ref, error) {
+	out, err := run(ctx, dir, "for-each-ref", "--sort=-committerdate",
+		"--format=%(refname)%09%(refname:short)%09%(subject)",
+		"refs/heads", "refs/remotes", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	refs := parseRefs(out)
+	if commits > 0 {
+		out, err = run(ctx, dir, "log", "-n", strconv.Itoa(commits), "--format=%h%x09%s")
+		// a repository without commits has no log
+		if err == nil {
+			refs = append(refs, parseCommits(out)...)
+		}
+	}
+	return refs, nil
+}
+
+func run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "not a git repository") {
+			return nil, ErrNotRepository
+		}
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseRefs reads the output of git for-each-ref with the full name, the
+// short name and the subject separated by tabs
+func parseRefs(out []byte) []Ref {
+	var refs []Ref
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) < 2 || strings.HasSuffix(fields[0], "/HEAD") {
+			continue
+		}
+		ref := Ref{Name: fields[1]}
+		switch {
+		case strings.HasPrefix(fields[0], "refs/heads/"):
+			ref.Kind = "branch"
+		case strings.HasPrefix(fields[0], "refs/remotes/"):
+			ref.Kind = "remote"
+		default:
+			ref.Kind = "tag"
+		}
+		if len(fields) == 3 {
+			ref.Subject = fields[2]
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// parseCommits reads the output of git log with the abbreviated hash and
+// the subject separated by a tab
+func parseCommits(out []byte) []Ref {
+	var refs []Ref
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		hash, subject, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		refs = append(refs, Ref{Name: hash, Kind: "commit", Subject: subject})
+	}
+	return refs
+}
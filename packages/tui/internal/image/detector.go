@@ -4,6 +4,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/sst/dgmo/internal/paths"
 )
 
 // IsImageFile checks if a file has an image extension
@@ -17,26 +19,9 @@ func IsImageFile(filename string) bool {
 	}
 }
 
-// ConvertWindowsPath converts Windows paths to WSL format
-func ConvertWindowsPath(path string) string {
-	// Check if it's a Windows path (e.g., C:\path\to\file)
-	windowsPathRegex := regexp.MustCompile(`^([A-Za-z]):\\(.*)`)
-	matches := windowsPathRegex.FindStringSubmatch(path)
-
-	if len(matches) == 3 {
-		// Convert to WSL format: /mnt/c/path/to/file
-		drive := strings.ToLower(matches[1])
-		pathPart := strings.ReplaceAll(matches[2], "\\", "/")
-		return "/mnt/" + drive + "/" + pathPart
-	}
-
-	// If not a Windows path, return as-is
-	return path
-}
-
 // ExtractImagePaths extracts image file paths from text
 func ExtractImagePaths(text string) []string {
-	var paths []string
+	var found []string
 
 	// Regex patterns for different path formats
 	patterns := []string{
@@ -44,8 +29,8 @@ func ExtractImagePaths(text string) []string {
 		`["']([^"']*\.(?:png|jpg|jpeg|gif|webp|bmp))["']`,
 		// Backtick paths
 		"`([^`]*\\.(?:png|jpg|jpeg|gif|webp|bmp))`",
-		// Unquoted paths with full path
-		`(?:^|\s)((?:[A-Za-z]:[\\\/]|\/)[^\s]*\.(?:png|jpg|jpeg|gif|webp|bmp))(?:\s|$)`,
+		// Unquoted paths with full path, including UNC paths
+		`(?:^|\s)((?:[A-Za-z]:[\\\/]|\/|\\\\)[^\s]*\.(?:png|jpg|jpeg|gif|webp|bmp))(?:\s|$)`,
 		// Simple filenames
 		`(?:^|\s)([^\s]+\.(?:png|jpg|jpeg|gif|webp|bmp))(?:\s|$)`,
 	}
@@ -56,9 +41,9 @@ func ExtractImagePaths(text string) []string {
 		for _, match := range matches {
 			if len(match) > 1 {
 				path := strings.TrimSpace(match[1])
-				// Convert Windows paths to WSL format
-				path = ConvertWindowsPath(path)
-				paths = append(paths, path)
+				// Windows paths are pasted into WSL terminals too
+				path = paths.Local(path)
+				found = append(found, path)
 			}
 		}
 	}
@@ -66,7 +51,7 @@ func ExtractImagePaths(text string) []string {
 	// Remove duplicates
 	seen := make(map[string]bool)
 	var uniquePaths []string
-	for _, path := range paths {
+	for _, path := range found {
 		if !seen[path] {
 			seen[path] = true
 			uniquePaths = append(uniquePaths, path)
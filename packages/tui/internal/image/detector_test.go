@@ -0,0 +1,23 @@
+package image
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sst/dgmo/internal/paths"
+)
+
+func TestExtractImagePathsMixedSeparators(t *testing.T) {
+	text := `compare C:\Users\dev/shots\before.png with "D:/shots\after.jpg" and \\wsl$\Ubuntu\tmp\diff.gif`
+	got := ExtractImagePaths(text)
+	want := []string{
+		paths.Local(`C:\Users\dev/shots\before.png`),
+		paths.Local(`D:/shots\after.jpg`),
+		paths.Local(`\\wsl$\Ubuntu\tmp\diff.gif`),
+	}
+	for _, path := range want {
+		if !slices.Contains(got, path) {
+			t.Errorf("ExtractImagePaths missed %q, got %q", path, got)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/sst/dgmo/internal/paths"
 )
 
 const MaxImageSize = 5 * 1024 * 1024 // 5MB
@@ -32,7 +34,7 @@ func GetMimeType(filename string) string {
 // ReadImageAsBase64 reads an image file and returns it as a base64 data URL
 func ReadImageAsBase64(filePath string) (string, error) {
 	// Convert Windows path if needed
-	normalizedPath := ConvertWindowsPath(filePath)
+	normalizedPath := paths.Local(filePath)
 
 	// Check if file exists
 	info, err := os.Stat(normalizedPath)
@@ -0,0 +1,74 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// imageURLPattern matches http and https links whose path ends in an image
+// extension, optionally followed by a query
+var imageURLPattern = regexp.MustCompile(`(?i)https?://[^\s<>"'()\[\]` + "`" + `]+\.(?:png|jpe?g|gif|webp|bmp)(?:\?[^\s<>"'()\[\]` + "`" + `]*)?`)
+
+// ExtractImageURLs returns the image links in text, without duplicates
+func ExtractImageURLs(text string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, url := range imageURLPattern.FindAllString(text, -1) {
+		if !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// RemotePreview downloads the image at url and renders it at most width
+// cells wide and rows lines tall. Images larger than maxBytes are refused
+// without reading them whole.
+func RemotePreview(ctx context.Context, client *http.Client, url string, maxBytes int64, width, rows int) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("fetching %s: not an image (%s)", url, contentType)
+	}
+	if resp.ContentLength > maxBytes {
+		return "", fmt.Errorf("fetching %s: image is larger than %d bytes", url, maxBytes)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > maxBytes {
+		return "", fmt.Errorf("fetching %s: image is larger than %d bytes", url, maxBytes)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", url, err)
+	}
+	// each line holds two pixel rows, narrow the image until it fits
+	bounds := img.Bounds()
+	if bounds.Dx() > 0 && bounds.Dy() > 0 {
+		width = min(width, min(bounds.Dx(), rows*2*bounds.Dx()/bounds.Dy()))
+	}
+	return strings.TrimRight(ToString(max(1, width), img), "\n"), nil
+}
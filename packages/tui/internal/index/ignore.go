@@ -0,0 +1,104 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+)
+
+// rule is a pattern of a .gitignore file
+type rule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+	// anchored patterns contain a slash and match the path from the
+	// directory of their file, the others match the name at any depth
+	anchored bool
+}
+
+// ignoreFile holds the rules of the .gitignore file of dir, a path relative
+// to the root of the index
+type ignoreFile struct {
+	dir   string
+	rules []rule
+}
+
+// parseIgnore reads the rules of a .gitignore file
+func parseIgnore(dir string, data []byte) *ignoreFile {
+	f := &ignoreFile{dir: dir}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r rule
+		if strings.HasPrefix(line, "!") {
+			r.negate, line = true, line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly, line = true, strings.TrimRight(line, "/")
+		}
+		r.anchored = strings.Contains(line, "/")
+		r.pattern = strings.TrimPrefix(line, "/")
+		if r.pattern != "" {
+			f.rules = append(f.rules, r)
+		}
+	}
+	return f
+}
+
+// match reports whether a rule of the file matches rel, and whether the
+// last matching rule ignores it
+func (f *ignoreFile) match(rel string, isDir bool) (matched, ignored bool) {
+	if f.dir != "" {
+		var ok bool
+		if rel, ok = strings.CutPrefix(rel, f.dir+"/"); !ok {
+			return false, false
+		}
+	}
+	for _, r := range f.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if r.anchored {
+			ok = matchPath(r.pattern, rel)
+		} else {
+			ok, _ = path.Match(r.pattern, path.Base(rel))
+		}
+		if ok {
+			matched, ignored = true, !r.negate
+		}
+	}
+	return matched, ignored
+}
+
+// matchPath matches a slash separated pattern in which ** stands for any
+// number of directories
+func matchPath(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
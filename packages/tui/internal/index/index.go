@@ -0,0 +1,392 @@
+// Package index keeps the list of the files of the workspace that file
+// completions and the file picker search. The workspace is walked once,
+// honouring its .gitignore files, and the list is then kept up to date from
+// file system events instead of walking it again.
+package index
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// MaxFiles bounds the index of a huge workspace
+const MaxFiles = 20000
+
+// skippedDirs are never indexed, besides hidden directories, even when no
+// .gitignore lists them
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+}
+
+// Index is the file list of a workspace
+type Index struct {
+	root string
+	// watcher is nil when the workspace cannot be watched, the index is
+	// then built once and not updated
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+
+	mu      sync.Mutex
+	files   map[string]struct{}
+	sorted  []string // files in search order, nil after a change
+	ignores map[string]*ignoreFile
+	dirs    map[string]bool // watched directories
+	ready   bool
+	full    bool
+	closed  bool
+}
+
+// Open indexes root in the background and watches it for changes
+func Open(root string) *Index {
+	x := &Index{
+		root:    root,
+		done:    make(chan struct{}),
+		files:   make(map[string]struct{}),
+		ignores: make(map[string]*ignoreFile),
+		dirs:    make(map[string]bool),
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("Failed to watch the workspace, the file index will not update", "error", err)
+	} else {
+		x.watcher = watcher
+	}
+	go x.run()
+	return x
+}
+
+func (x *Index) run() {
+	defer close(x.done)
+	x.walk("")
+	x.mu.Lock()
+	x.ready = true
+	x.mu.Unlock()
+	slog.Debug("Indexed workspace", "root", x.root, "files", len(x.files))
+
+	if x.watcher == nil {
+		return
+	}
+	for {
+		select {
+		case event, ok := <-x.watcher.Events:
+			if !ok {
+				return
+			}
+			x.apply(event)
+		case err, ok := <-x.watcher.Errors:
+			if !ok {
+				return
+			}
+			// events were dropped, only walking again catches up with them
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				x.refresh("")
+				continue
+			}
+			slog.Warn("Failed to watch the workspace", "error", err)
+		}
+	}
+}
+
+// Ready reports whether the first walk of the workspace finished. Until then
+// the index holds part of the files.
+func (x *Index) Ready() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.ready
+}
+
+// Files returns the indexed files relative to the root, shallow files first.
+// The slice is shared and must not be modified.
+func (x *Index) Files() []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.sorted == nil {
+		sorted := make([]string, 0, len(x.files))
+		for file := range x.files {
+			sorted = append(sorted, file)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			di, dj := strings.Count(sorted[i], "/"), strings.Count(sorted[j], "/")
+			if di != dj {
+				return di < dj
+			}
+			return sorted[i] < sorted[j]
+		})
+		x.sorted = sorted
+	}
+	return x.sorted
+}
+
+// Search returns up to limit files matching a fuzzy query, all files for an
+// empty one
+func (x *Index) Search(query string, limit int) []string {
+	files := x.Files()
+	if query != "" {
+		files = rank(query, files)
+	}
+	return slices.Clone(files[:min(limit, len(files))])
+}
+
+// Close stops watching the workspace
+func (x *Index) Close() {
+	x.mu.Lock()
+	if x.closed {
+		x.mu.Unlock()
+		return
+	}
+	x.closed = true
+	x.mu.Unlock()
+	if x.watcher != nil {
+		x.watcher.Close()
+	}
+	<-x.done
+}
+
+// apply updates the index for a change in a watched directory
+func (x *Index) apply(event fsnotify.Event) {
+	rel := x.rel(event.Name)
+	if rel == "" {
+		return
+	}
+	if path.Base(rel) == ".gitignore" {
+		if !event.Has(fsnotify.Chmod) {
+			x.refresh(parent(rel))
+		}
+		return
+	}
+	switch {
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		// a renamed file is created again under its new name
+		x.remove(rel)
+	case event.Has(fsnotify.Create):
+		info, err := os.Lstat(event.Name)
+		if err != nil || x.skipped(rel, info.IsDir()) {
+			return
+		}
+		if info.IsDir() {
+			x.walk(rel)
+		} else {
+			x.add(rel)
+		}
+	}
+}
+
+// refresh indexes dir again after its ignore rules changed
+func (x *Index) refresh(dir string) {
+	x.remove(dir)
+	x.walk(dir)
+}
+
+// walk adds the files under dir, a path relative to the root, and watches
+// its directories
+func (x *Index) walk(dir string) {
+	start := filepath.Join(x.root, filepath.FromSlash(dir))
+	err := filepath.WalkDir(start, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// an unreadable directory is left out of the index
+			if entry != nil && entry.IsDir() && p != start {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if x.isClosed() {
+			return fs.SkipAll
+		}
+		rel := x.rel(p)
+		if entry.IsDir() {
+			if rel != "" && x.skipped(rel, true) {
+				return fs.SkipDir
+			}
+			x.loadIgnore(rel)
+			x.watch(rel)
+			return nil
+		}
+		if x.skipped(rel, false) {
+			return nil
+		}
+		if !x.add(rel) {
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("Failed to index workspace", "dir", start, "error", err)
+	}
+}
+
+// add adds a file, it returns false once the index is full
+func (x *Index) add(rel string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.files[rel]; ok {
+		return true
+	}
+	if len(x.files) >= MaxFiles {
+		if !x.full {
+			x.full = true
+			slog.Warn("Workspace has too many files, the file index is incomplete", "root", x.root, "limit", MaxFiles)
+		}
+		return false
+	}
+	x.files[rel] = struct{}{}
+	x.sorted = nil
+	return true
+}
+
+// remove drops a file, or a directory with everything under it
+func (x *Index) remove(rel string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	under := func(p string) bool {
+		return rel == "" || p == rel || strings.HasPrefix(p, rel+"/")
+	}
+	for file := range x.files {
+		if under(file) {
+			delete(x.files, file)
+			x.sorted = nil
+		}
+	}
+	for dir := range x.ignores {
+		if under(dir) {
+			delete(x.ignores, dir)
+		}
+	}
+	for dir := range x.dirs {
+		if under(dir) {
+			// a renamed directory would keep reporting its old name
+			if x.watcher != nil {
+				x.watcher.Remove(filepath.Join(x.root, filepath.FromSlash(dir)))
+			}
+			delete(x.dirs, dir)
+		}
+	}
+	x.full = len(x.files) >= MaxFiles
+}
+
+func (x *Index) watch(dir string) {
+	if x.watcher == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.dirs[dir] {
+		return
+	}
+	if err := x.watcher.Add(filepath.Join(x.root, filepath.FromSlash(dir))); err != nil {
+		slog.Debug("Failed to watch directory", "dir", dir, "error", err)
+		return
+	}
+	x.dirs[dir] = true
+}
+
+// loadIgnore reads the .gitignore file of dir, and the excludes of the
+// repository for the root
+func (x *Index) loadIgnore(dir string) {
+	base := filepath.Join(x.root, filepath.FromSlash(dir))
+	data, _ := os.ReadFile(filepath.Join(base, ".gitignore"))
+	if dir == "" {
+		exclude, _ := os.ReadFile(filepath.Join(base, ".git", "info", "exclude"))
+		data = append(exclude, append([]byte("\n"), data...)...)
+	}
+	ignore := parseIgnore(dir, data)
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if len(ignore.rules) == 0 {
+		delete(x.ignores, dir)
+		return
+	}
+	x.ignores[dir] = ignore
+}
+
+// skipped reports whether a path is left out of the index: hidden, a
+// dependency directory or ignored
+func (x *Index) skipped(rel string, isDir bool) bool {
+	name := path.Base(rel)
+	if strings.HasPrefix(name, ".") || isDir && skippedDirs[name] {
+		return true
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	// the rules of deeper files override those of their parents
+	ignored := false
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		ignore := x.ignores[strings.Join(parts[:i], "/")]
+		if ignore == nil {
+			continue
+		}
+		if matched, ok := ignore.match(rel, isDir); matched {
+			ignored = ok
+		}
+	}
+	return ignored
+}
+
+func (x *Index) isClosed() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.closed
+}
+
+// rel returns a path relative to the root with forward slashes, empty for
+// the root itself
+func (x *Index) rel(p string) string {
+	rel, err := filepath.Rel(x.root, p)
+	if err != nil || rel == "." {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+func parent(rel string) string {
+	if dir := path.Dir(rel); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+// rank returns the files matching a fuzzy query, those whose name contains
+// the query first, then those whose path contains it, then the closest
+// fuzzy matches
+func rank(query string, files []string) []string {
+	query = strings.ToLower(query)
+	tier := func(file string) int {
+		file = strings.ToLower(file)
+		switch {
+		case strings.Contains(path.Base(file), query):
+			return 0
+		case strings.Contains(file, query):
+			return 1
+		}
+		return 2
+	}
+	ranks := fuzzy.RankFindFold(query, files)
+	sort.SliceStable(ranks, func(i, j int) bool {
+		ti, tj := tier(ranks[i].Target), tier(ranks[j].Target)
+		if ti != tj {
+			return ti < tj
+		}
+		return ranks[i].Distance < ranks[j].Distance
+	})
+	matches := make([]string, 0, len(ranks))
+	for _, rank := range ranks {
+		matches = append(matches, rank.Target)
+	}
+	return matches
+}
@@ -0,0 +1,116 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for file, content := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func openReady(t *testing.T, root string) *Index {
+	t.Helper()
+	x := Open(root)
+	t.Cleanup(x.Close)
+	waitFor(t, "the first walk", x.Ready)
+	return x
+}
+
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":                     "*.log\n!keep.log\n/out/\n",
+		"main.go":                        "",
+		"debug.log":                      "",
+		"keep.log":                       "",
+		"out/main":                       "",
+		"internal/out/report.txt":        "",
+		"internal/app/app.go":            "",
+		"internal/.gitignore":            "generated/\n**/fixtures/*.json\n",
+		"internal/generated/api.go":      "",
+		"internal/app/fixtures/a.json":   "",
+		"internal/app/fixtures/README":   "",
+		".env":                           "",
+		".git/HEAD":                      "",
+		"node_modules/left-pad/index.js": "",
+	})
+
+	x := openReady(t, root)
+	want := []string{
+		"keep.log",
+		"main.go",
+		"internal/app/app.go",
+		"internal/out/report.txt",
+		"internal/app/fixtures/README",
+	}
+	if files := x.Files(); !reflect.DeepEqual(files, want) {
+		t.Errorf("Files = %v, want %v", files, want)
+	}
+}
+
+func TestIndexUpdates(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"main.go": "", "internal/app.go": ""})
+	x := openReady(t, root)
+	has := func(file string) func() bool {
+		return func() bool { return slices.Contains(x.Files(), file) }
+	}
+
+	writeFiles(t, root, map[string]string{"internal/tui/tui.go": "", "notes.tmp": ""})
+	waitFor(t, "a file in a new directory", has("internal/tui/tui.go"))
+	waitFor(t, "a new file", has("notes.tmp"))
+
+	if err := os.RemoveAll(filepath.Join(root, "internal")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "a removed directory", func() bool { return !has("internal/app.go")() })
+
+	writeFiles(t, root, map[string]string{".gitignore": "*.tmp\n"})
+	waitFor(t, "a newly ignored file", func() bool { return !has("notes.tmp")() })
+	if files := x.Files(); !reflect.DeepEqual(files, []string{"main.go"}) {
+		t.Errorf("Files = %v, want [main.go]", files)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	files := []string{"packages/tui/internal/tui/modals.go", "internal/components/chat/partial.go", "docs/partial.md"}
+	want := []string{"internal/components/chat/partial.go", "packages/tui/internal/tui/modals.go"}
+	if got := rank("partial.go", files); !reflect.DeepEqual(got, want) {
+		t.Errorf("rank = %v, want %v", got, want)
+	}
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"a.go": "", "b.go": "", "c.md": ""})
+	x := openReady(t, root)
+	if got := x.Search("", 2); !reflect.DeepEqual(got, []string{"a.go", "b.go"}) {
+		t.Errorf("Search with a limit = %v", got)
+	}
+	if got := x.Search(".md", 10); !reflect.DeepEqual(got, []string{"c.md"}) {
+		t.Errorf("Search(.md) = %v", got)
+	}
+}
@@ -1,6 +1,8 @@
 package layout
 
 import (
+	"math"
+
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
@@ -10,6 +12,8 @@ func init() {
 	Current = &LayoutInfo{
 		Viewport:  Dimensions{Width: 80, Height: 25},
 		Container: Dimensions{Width: 80, Height: 25},
+		Density:   DensityComfortable,
+		Zoom:      100,
 	}
 }
 
@@ -20,9 +24,30 @@ type Dimensions struct {
 	Height int
 }
 
+// Density is how much room the transcript leaves around its blocks
+type Density string
+
+const (
+	DensityComfortable Density = "comfortable"
+	DensityCompact     Density = "compact"
+)
+
 type LayoutInfo struct {
 	Viewport  Dimensions
 	Container Dimensions
+	Density   Density
+	// Zoom scales paddings, box widths and truncation limits, in percent
+	Zoom int
+}
+
+// Scale sizes n for the current zoom. Sizes that are not zero stay at
+// least 1, so zooming out never drops a padding or a box entirely.
+func Scale(n int) int {
+	zoom := Current.Zoom
+	if zoom <= 0 || n == 0 {
+		return n
+	}
+	return max(1, int(math.Round(float64(n*zoom)/100)))
 }
 
 type Modal interface {
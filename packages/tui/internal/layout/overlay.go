@@ -12,6 +12,7 @@ import (
 	"github.com/muesli/ansi"
 	"github.com/muesli/reflow/truncate"
 	"github.com/muesli/termenv"
+	"github.com/sst/dgmo/internal/styles/glyphs"
 	"github.com/sst/dgmo/internal/util"
 )
 
@@ -125,7 +126,7 @@ func PlaceOverlay(
 			if leftSeq != "" {
 				b.WriteString(leftSeq)
 			}
-			b.WriteString("┃")
+			b.WriteString(glyphs.Current().Bar)
 			if leftSeq != "" {
 				b.WriteString("\x1b[0m") // Reset all styles only if we applied any
 			}
@@ -140,7 +141,7 @@ func PlaceOverlay(
 			if rightSeq != "" {
 				b.WriteString(rightSeq)
 			}
-			b.WriteString("┃")
+			b.WriteString(glyphs.Current().Bar)
 			if rightSeq != "" {
 				b.WriteString("\x1b[0m") // Reset all styles only if we applied any
 			}
@@ -0,0 +1,163 @@
+// Package logging sets up the TUI log file with size and age based rotation.
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelEnv is the environment variable that sets the minimum log level
+const LevelEnv = "DGMO_LOG_LEVEL"
+
+// Path returns the location of the TUI log inside the app data directory
+func Path(dataDir string) string {
+	return filepath.Join(dataDir, "log", "tui.log")
+}
+
+// ParseLevel converts a level name such as "debug" or "WARN" to a slog level.
+// Unknown or empty names fall back to info.
+func ParseLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// RotatingFile is an io.Writer that moves the log aside once it grows past
+// MaxSize or gets older than MaxAge, keeping at most MaxBackups old files
+type RotatingFile struct {
+	MaxSize    int64
+	MaxAge     time.Duration
+	MaxBackups int
+
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// Option configures a RotatingFile
+type Option func(*RotatingFile)
+
+// WithMaxSize sets the size in bytes after which the log is rotated
+func WithMaxSize(size int64) Option {
+	return func(r *RotatingFile) { r.MaxSize = size }
+}
+
+// WithMaxAge sets how long a log file is written to before it is rotated
+func WithMaxAge(age time.Duration) Option {
+	return func(r *RotatingFile) { r.MaxAge = age }
+}
+
+// WithMaxBackups sets how many rotated files are kept
+func WithMaxBackups(n int) Option {
+	return func(r *RotatingFile) { r.MaxBackups = n }
+}
+
+// NewRotatingFile opens path for appending, creating its directory if needed
+func NewRotatingFile(path string, opts ...Option) (*RotatingFile, error) {
+	r := &RotatingFile{
+		MaxSize:    10 << 20,
+		MaxAge:     24 * time.Hour,
+		MaxBackups: 5,
+		path:       path,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	r.opened = info.ModTime()
+	if r.size == 0 {
+		r.opened = time.Now()
+	}
+	return nil
+}
+
+// Write appends p to the log, rotating first if it is due
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.due(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) due(incoming int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.MaxSize > 0 && r.size+incoming > r.MaxSize {
+		return true
+	}
+	return r.MaxAge > 0 && time.Since(r.opened) > r.MaxAge
+}
+
+// rotate renames the current log with a timestamp suffix and starts a new one
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	backup := fmt.Sprintf("%s.%s", r.path, time.Now().Format("20060102-150405.000"))
+	if err := os.Rename(r.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	r.prune()
+	return r.open()
+}
+
+// prune removes rotated files beyond MaxBackups
+func (r *RotatingFile) prune() {
+	if r.MaxBackups <= 0 {
+		return
+	}
+	backups, _ := filepath.Glob(r.path + ".*")
+	if len(backups) <= r.MaxBackups {
+		return
+	}
+	// timestamp suffixes sort chronologically
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-r.MaxBackups] {
+		os.Remove(backup)
+	}
+}
+
+// Close closes the underlying file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,120 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceEnv is the environment variable that turns message tracing on from
+// the start, before a command can
+const TraceEnv = "DGMO_TRACE_MESSAGES"
+
+const (
+	// traceLimit is how many messages of one type are logged per window,
+	// so spinner ticks and streaming updates do not flood the log
+	traceLimit  = 10
+	traceWindow = time.Second
+)
+
+// traceCount is how many messages of a type were logged and dropped in the
+// current window
+type traceCount struct {
+	start      time.Time
+	logged     int
+	suppressed int
+}
+
+// Tracer logs the messages going through an update loop with how long they
+// took and which components consumed them. Each message type is rate
+// limited, a logged line counts the ones dropped before it.
+type Tracer struct {
+	mu        sync.Mutex
+	enabled   bool
+	now       func() time.Time
+	counts    map[string]*traceCount
+	consumers []string
+}
+
+// NewTracer creates a tracer, logging from the start when enabled
+func NewTracer(enabled bool) *Tracer {
+	return &Tracer{
+		enabled: enabled,
+		now:     time.Now,
+		counts:  make(map[string]*traceCount),
+	}
+}
+
+// Enabled reports whether messages are traced
+func (t *Tracer) Enabled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.enabled
+}
+
+// SetEnabled turns tracing on or off
+func (t *Tracer) SetEnabled(enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.enabled = enabled
+	clear(t.counts)
+	t.consumers = nil
+}
+
+// Consume records a component that consumed the message being traced
+func (t *Tracer) Consume(component string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.enabled {
+		t.consumers = append(t.consumers, component)
+	}
+}
+
+// Trace logs a message once it went through the update loop, with the
+// components recorded by Consume since the last message. A message nothing
+// claimed is reported as handled by fallback.
+func (t *Tracer) Trace(msg any, elapsed time.Duration, fallback string) {
+	t.mu.Lock()
+	consumers := t.consumers
+	t.consumers = nil
+	if !t.enabled {
+		t.mu.Unlock()
+		return
+	}
+	msgType := fmt.Sprintf("%T", msg)
+	now := t.now()
+	count, ok := t.counts[msgType]
+	if !ok || now.Sub(count.start) >= traceWindow {
+		suppressed := 0
+		if ok {
+			suppressed = count.suppressed
+		}
+		count = &traceCount{start: now, suppressed: suppressed}
+		t.counts[msgType] = count
+	}
+	if count.logged >= traceLimit {
+		count.suppressed++
+		t.mu.Unlock()
+		return
+	}
+	count.logged++
+	suppressed := count.suppressed
+	count.suppressed = 0
+	t.mu.Unlock()
+
+	if len(consumers) == 0 {
+		consumers = []string{fallback}
+	}
+	attrs := []any{
+		"type", msgType,
+		"elapsed", elapsed,
+		"consumer", strings.Join(consumers, ","),
+	}
+	if suppressed > 0 {
+		attrs = append(attrs, "suppressed", suppressed)
+	}
+	// logged at info, tracing is asked for explicitly whatever the log level
+	slog.Info("Update trace", attrs...)
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tickMsg struct{}
+
+func TestTracer(t *testing.T) {
+	var out bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&out, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	tracer := NewTracer(false)
+	tracer.Consume("editor")
+	tracer.Trace(tickMsg{}, time.Millisecond, "app")
+	if out.Len() != 0 {
+		t.Fatalf("traced while off: %s", out.String())
+	}
+
+	now := time.Now()
+	tracer.SetEnabled(true)
+	tracer.now = func() time.Time { return now }
+	tracer.Consume("modal")
+	tracer.Consume("components")
+	tracer.Trace(tickMsg{}, time.Millisecond, "app")
+	if line := out.String(); !strings.Contains(line, "type=logging.tickMsg") || !strings.Contains(line, "consumer=modal,components") {
+		t.Errorf("trace line = %s", line)
+	}
+
+	for range traceLimit + 4 {
+		tracer.Trace(tickMsg{}, time.Millisecond, "app")
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != traceLimit {
+		t.Errorf("%d lines logged in one window, want %d", lines, traceLimit)
+	}
+	now = now.Add(traceWindow)
+	out.Reset()
+	tracer.Trace(tickMsg{}, time.Millisecond, "app")
+	if line := out.String(); !strings.Contains(line, "consumer=app") || !strings.Contains(line, "suppressed=5") {
+		t.Errorf("first line of the next window = %s", line)
+	}
+}
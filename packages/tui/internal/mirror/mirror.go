@@ -0,0 +1,168 @@
+// Package mirror keeps an append-only copy of every message the client
+// receives, one JSONL file per session, so a transcript can be rebuilt when
+// the server loses it or the client crashes mid-stream.
+package mirror
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNoMirror is returned by Load for sessions that were never mirrored
+var ErrNoMirror = errors.New("no local copy of this session")
+
+// queueSize is how many messages wait for the writer before Append blocks
+const queueSize = 256
+
+type record struct {
+	ID      string          `json:"id"`
+	Message json.RawMessage `json:"message"`
+}
+
+type entry struct {
+	sessionID string
+	record    record
+}
+
+// Mirror appends messages to the session files from a goroutine of its own,
+// so the update loop does not wait for the disk
+type Mirror struct {
+	dir     string
+	entries chan entry
+	pending sync.WaitGroup
+	done    chan struct{}
+
+	mu     sync.Mutex
+	hashes map[string]uint64 // of the last copy written per message ID
+	closed bool
+}
+
+// Open starts mirroring to dir, which is created when needed
+func Open(dir string) *Mirror {
+	m := &Mirror{
+		dir:     dir,
+		entries: make(chan entry, queueSize),
+		done:    make(chan struct{}),
+		hashes:  make(map[string]uint64),
+	}
+	go m.write()
+	return m
+}
+
+func (m *Mirror) path(sessionID string) string {
+	return filepath.Join(m.dir, sessionID+".jsonl")
+}
+
+// Append queues a copy of a message. Copies identical to the last one of
+// the same message are skipped, streaming repeats most of them.
+func (m *Mirror) Append(sessionID, messageID string, message json.RawMessage) {
+	h := fnv.New64a()
+	h.Write(message)
+	sum := h.Sum64()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed || m.hashes[messageID] == sum {
+		return
+	}
+	m.hashes[messageID] = sum
+	m.pending.Add(1)
+	m.entries <- entry{sessionID: sessionID, record: record{ID: messageID, Message: message}}
+}
+
+// write appends the queued messages, keeping the file of the last session
+// open since consecutive messages mostly belong to the same one
+func (m *Mirror) write() {
+	defer close(m.done)
+	var file *os.File
+	var encoder *json.Encoder
+	openSession := ""
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	for e := range m.entries {
+		if e.sessionID != openSession {
+			if file != nil {
+				file.Close()
+				file, encoder, openSession = nil, nil, ""
+			}
+			if err := os.MkdirAll(m.dir, 0o755); err != nil {
+				slog.Error("Failed to create the transcript mirror", "error", err)
+				m.pending.Done()
+				continue
+			}
+			f, err := os.OpenFile(m.path(e.sessionID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				slog.Error("Failed to open the transcript mirror", "session", e.sessionID, "error", err)
+				m.pending.Done()
+				continue
+			}
+			file, encoder, openSession = f, json.NewEncoder(f), e.sessionID
+		}
+		if err := encoder.Encode(e.record); err != nil {
+			slog.Error("Failed to mirror message", "message", e.record.ID, "error", err)
+		}
+		m.pending.Done()
+	}
+}
+
+// Load returns the latest copy of every mirrored message of a session, in
+// the order they were first received. A line cut off by a crash is skipped.
+func (m *Mirror) Load(sessionID string) ([]json.RawMessage, error) {
+	m.pending.Wait()
+
+	file, err := os.Open(m.path(sessionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoMirror
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var messages []json.RawMessage
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.ID == "" {
+			continue
+		}
+		if i, ok := index[r.ID]; ok {
+			messages[i] = r.Message
+			continue
+		}
+		index[r.ID] = len(messages)
+		messages = append(messages, r.Message)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, ErrNoMirror
+	}
+	return messages, nil
+}
+
+// Close writes the queued messages and stops mirroring
+func (m *Mirror) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.entries)
+	m.mu.Unlock()
+	<-m.done
+}
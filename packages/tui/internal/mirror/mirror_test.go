@@ -0,0 +1,65 @@
+package mirror
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMirrorKeepsLatestCopy(t *testing.T) {
+	dir := t.TempDir()
+	m := Open(dir)
+	defer m.Close()
+
+	m.Append("ses_1", "msg_1", []byte(`{"id":"msg_1","text":"hel"}`))
+	m.Append("ses_2", "msg_9", []byte(`{"id":"msg_9"}`))
+	m.Append("ses_1", "msg_2", []byte(`{"id":"msg_2"}`))
+	m.Append("ses_1", "msg_1", []byte(`{"id":"msg_1","text":"hello"}`))
+	// identical copies are not written again
+	m.Append("ses_1", "msg_1", []byte(`{"id":"msg_1","text":"hello"}`))
+
+	messages, err := m.Load("ses_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`{"id":"msg_1","text":"hello"}`, `{"id":"msg_2"}`}
+	if len(messages) != len(want) {
+		t.Fatalf("loaded %d messages, want %d", len(messages), len(want))
+	}
+	for i := range want {
+		if string(messages[i]) != want[i] {
+			t.Errorf("message %d = %s, want %s", i, messages[i], want[i])
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "ses_1.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("the mirror has %d lines, want 3", lines)
+	}
+}
+
+func TestMirrorSkipsTruncatedLine(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"id":"msg_1","message":{"id":"msg_1"}}` + "\n" + `{"id":"msg_2","mess`
+	if err := os.WriteFile(filepath.Join(dir, "ses_1.jsonl"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := Open(dir)
+	defer m.Close()
+
+	messages, err := m.Load("ses_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || string(messages[0]) != `{"id":"msg_1"}` {
+		t.Errorf("loaded %s", messages)
+	}
+	if _, err := m.Load("ses_unknown"); !errors.Is(err, ErrNoMirror) {
+		t.Errorf("Load of an unknown session = %v, want ErrNoMirror", err)
+	}
+}
@@ -0,0 +1,42 @@
+// Package notify delivers native desktop notifications, and provides the
+// terminal escape sequence to use when no platform backend is available.
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notification is a single desktop notification
+type Notification struct {
+	Title string
+	Body  string
+}
+
+// Send delivers the notification through the platform backend. When it
+// fails, Sequence is the fallback.
+func Send(n Notification) error {
+	return send(n)
+}
+
+// Sequence returns an OSC 777 notification followed by a bell. Terminals
+// that don't understand OSC 777 ignore it and still ring the bell. It has to
+// go through the program's output, as writing it while a frame is drawn
+// corrupts the screen.
+func Sequence(n Notification) string {
+	return fmt.Sprintf("\x1b]777;notify;%s;%s\x1b\\\a", sanitize(n.Title), sanitize(n.Body))
+}
+
+// sanitize strips characters that would terminate or corrupt an escape
+// sequence or a shell-quoted argument
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ';' || r == '"' || r == '\\':
+			return ' '
+		case r < 0x20 || r == 0x7f:
+			return ' '
+		}
+		return r
+	}, s)
+}
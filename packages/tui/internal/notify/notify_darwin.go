@@ -0,0 +1,13 @@
+//go:build darwin
+
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func send(n Notification) error {
+	script := fmt.Sprintf("display notification %q with title %q", sanitize(n.Body), sanitize(n.Title))
+	return exec.Command("osascript", "-e", script).Run() //nolint:gosec
+}
@@ -0,0 +1,15 @@
+//go:build linux
+
+package notify
+
+import (
+	"os/exec"
+)
+
+func send(n Notification) error {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return err
+	}
+	return exec.Command(path, "--app-name=dgmo", sanitize(n.Title), sanitize(n.Body)).Run() //nolint:gosec
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package notify
+
+import "errors"
+
+func send(n Notification) error {
+	return errors.New("no native notification backend")
+}
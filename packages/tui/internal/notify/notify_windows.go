@@ -0,0 +1,25 @@
+//go:build windows
+
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode('%s')) > $null
+$text.Item(1).AppendChild($template.CreateTextNode('%s')) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('dgmo').Show($toast)`
+
+func send(n Notification) error {
+	quote := func(s string) string {
+		return strings.ReplaceAll(sanitize(s), "'", "''")
+	}
+	script := fmt.Sprintf(toastScript, quote(n.Title), quote(n.Body))
+	return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Run() //nolint:gosec
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// QuietHours is a daily span during which notifications are held back. A
+// span that ends before it starts runs past midnight and belongs to the day
+// it starts on.
+type QuietHours struct {
+	// Days the span starts on, every day when empty
+	Days  []time.Weekday
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseQuietHours reads a span given as HH:MM times and three letter day
+// names
+func ParseQuietHours(days []string, start, end string) (QuietHours, error) {
+	var q QuietHours
+	var err error
+	if q.Start, err = parseClock(start); err != nil {
+		return q, err
+	}
+	if q.End, err = parseClock(end); err != nil {
+		return q, err
+	}
+	for _, day := range days {
+		// accept full names too
+		name := strings.ToLower(strings.TrimSpace(day))
+		if len(name) > 3 {
+			name = name[:3]
+		}
+		weekday, ok := weekdays[name]
+		if !ok {
+			return q, fmt.Errorf("unknown weekday %q", day)
+		}
+		q.Days = append(q.Days, weekday)
+	}
+	return q, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (q QuietHours) startsOn(day time.Weekday) bool {
+	return len(q.Days) == 0 || slices.Contains(q.Days, day)
+}
+
+// Active reports whether t falls into the span
+func (q QuietHours) Active(t time.Time) bool {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	switch {
+	case q.Start == q.End:
+		return false
+	case q.Start < q.End:
+		return q.startsOn(t.Weekday()) && clock >= q.Start && clock < q.End
+	}
+	// past midnight, the early hours belong to the span of the day before
+	if clock >= q.Start {
+		return q.startsOn(t.Weekday())
+	}
+	yesterday := (t.Weekday() + 6) % 7
+	return clock < q.End && q.startsOn(yesterday)
+}
@@ -0,0 +1,130 @@
+// Package paths handles file paths that may come from another platform, such
+// as Windows paths pasted into a WSL terminal or UNC paths in tool output
+package paths
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+var (
+	drivePrefix = regexp.MustCompile(`^([A-Za-z]):(/|$)`)
+	// wslShare matches the UNC shares Windows exposes WSL distributions under
+	wslShare = regexp.MustCompile(`(?i)^//wsl(?:\$|\.localhost)/[^/]+(/.*)?$`)
+)
+
+// Normalize converts a path to forward slashes and cleans it. Drive letters
+// are upper cased and the leading double slash of UNC paths is kept.
+func Normalize(p string) string {
+	if p == "" {
+		return ""
+	}
+	p = strings.ReplaceAll(p, "\\", "/")
+	if IsUNC(p) {
+		return "/" + path.Clean(p[1:])
+	}
+	if m := drivePrefix.FindStringSubmatch(p); m != nil {
+		return strings.ToUpper(m[1]) + ":" + path.Clean("/"+p[2:])
+	}
+	return path.Clean(p)
+}
+
+// IsUNC reports whether a path is a UNC path like \\server\share
+func IsUNC(p string) bool {
+	return len(p) > 2 && isSeparator(p[0]) && isSeparator(p[1]) && !isSeparator(p[2])
+}
+
+// IsAbs reports whether a path is absolute on any platform
+func IsAbs(p string) bool {
+	p = strings.ReplaceAll(p, "\\", "/")
+	return strings.HasPrefix(p, "/") || drivePrefix.MatchString(p)
+}
+
+// caseInsensitive reports whether a normalized path lives on a Windows
+// filesystem, which ignores case
+func caseInsensitive(p string) bool {
+	return IsUNC(p) || drivePrefix.MatchString(p)
+}
+
+// Rel returns target relative to base with forward slashes. It reports false
+// if target is not inside base.
+func Rel(base, target string) (string, bool) {
+	if base == "" || target == "" {
+		return "", false
+	}
+	base, target = Normalize(base), Normalize(target)
+	prefix := strings.TrimSuffix(base, "/") + "/"
+	equal := strings.EqualFold
+	if !caseInsensitive(base) {
+		equal = func(a, b string) bool { return a == b }
+	}
+	switch {
+	case equal(target, base):
+		return ".", true
+	case len(target) > len(prefix) && equal(target[:len(prefix)], prefix):
+		return target[len(prefix):], true
+	}
+	return "", false
+}
+
+// Relative shortens a path for display. It is made relative to the first of
+// bases that contains it, and is only normalized otherwise.
+func Relative(p string, bases ...string) string {
+	for _, base := range bases {
+		if rel, ok := Rel(base, p); ok {
+			return rel
+		}
+	}
+	return Normalize(p)
+}
+
+// Local converts a path from any platform into one that can be opened here.
+// Outside Windows, drive paths map to their WSL mount under /mnt and WSL
+// shares map to the path inside the distribution.
+func Local(p string) string {
+	return local(p, runtime.GOOS)
+}
+
+func local(p, goos string) string {
+	if p == "" {
+		return ""
+	}
+	n := Normalize(p)
+	if goos == "windows" {
+		if caseInsensitive(n) {
+			return strings.ReplaceAll(n, "/", `\`)
+		}
+		return p
+	}
+	if m := wslShare.FindStringSubmatch(n); m != nil {
+		if m[1] == "" {
+			return "/"
+		}
+		return m[1]
+	}
+	if m := drivePrefix.FindStringSubmatch(n); m != nil {
+		return strings.TrimSuffix("/mnt/"+strings.ToLower(m[1])+n[2:], "/")
+	}
+	return p
+}
+
+// ExpandHome replaces a leading ~/ with the home directory, as a shell does
+// for a path typed as a command argument
+func ExpandHome(p string) string {
+	if !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[2:])
+}
+
+func isSeparator(c byte) bool {
+	return c == '/' || c == '\\'
+}
@@ -0,0 +1,139 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"/home/user/project", "/home/user/project"},
+		{"/home/user//project/", "/home/user/project"},
+		{`src\components\app.go`, "src/components/app.go"},
+		{`src/components\app.go`, "src/components/app.go"},
+		{`c:\Users\dev\project`, "C:/Users/dev/project"},
+		{`C:/Users\dev/./project\..\other`, "C:/Users/dev/other"},
+		{`C:\`, "C:/"},
+		{`\\server\share\docs\file.txt`, "//server/share/docs/file.txt"},
+		{`//server/share\docs//file.txt`, "//server/share/docs/file.txt"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIsAbs(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"/home/user", true},
+		{`C:\Users`, true},
+		{"c:/Users", true},
+		{`\\server\share`, true},
+		{`src\app.go`, false},
+		{"src/app.go", false},
+		{"C:relative", false},
+	}
+	for _, tt := range tests {
+		if got := IsAbs(tt.input); got != tt.want {
+			t.Errorf("IsAbs(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRel(t *testing.T) {
+	tests := []struct {
+		base   string
+		target string
+		want   string
+		ok     bool
+	}{
+		{"/home/user/project", "/home/user/project/src/app.go", "src/app.go", true},
+		{"/home/user/project/", "/home/user/project", ".", true},
+		{"/home/user/project", "/home/user/project-other/app.go", "", false},
+		{"/home/user/project", "/home/user/Project/app.go", "", false},
+		{`C:\Users\dev\project`, `C:/Users/dev/project\src\app.go`, "src/app.go", true},
+		{`C:\Users\dev\project`, `c:\users\DEV\project\src\app.go`, "src/app.go", true},
+		{`C:\Users\dev\project`, `D:\Users\dev\project\app.go`, "", false},
+		{`\\server\share\repo`, `//server/share/repo\pkg/main.go`, "pkg/main.go", true},
+		{"", "/home/user/app.go", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Rel(tt.base, tt.target)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Rel(%q, %q) = %q, %v, want %q, %v", tt.base, tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRelative(t *testing.T) {
+	cwd := `C:\Users\dev\project\pkg`
+	root := `C:\Users\dev\project`
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`C:\Users\dev\project\pkg\main.go`, "main.go"},
+		{`C:/Users/dev/project\README.md`, "README.md"},
+		{`C:\Windows\system32\drivers`, "C:/Windows/system32/drivers"},
+	}
+	for _, tt := range tests {
+		if got := Relative(tt.input, cwd, root); got != tt.want {
+			t.Errorf("Relative(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	tests := []struct {
+		input   string
+		linux   string
+		windows string
+	}{
+		{"/home/user/pic.png", "/home/user/pic.png", "/home/user/pic.png"},
+		{`C:\Users\dev\Pictures\pic.png`, "/mnt/c/Users/dev/Pictures/pic.png", `C:\Users\dev\Pictures\pic.png`},
+		{`d:/data\shots/pic.png`, "/mnt/d/data/shots/pic.png", `D:\data\shots\pic.png`},
+		{`\\wsl$\Ubuntu\home\user\pic.png`, "/home/user/pic.png", `\\wsl$\Ubuntu\home\user\pic.png`},
+		{`\\wsl.localhost\Ubuntu`, "/", `\\wsl.localhost\Ubuntu`},
+		{`\\server\share\pic.png`, `\\server\share\pic.png`, `\\server\share\pic.png`},
+		{`screenshots\pic.png`, `screenshots\pic.png`, `screenshots\pic.png`},
+	}
+	for _, tt := range tests {
+		if got := local(tt.input, "linux"); got != tt.linux {
+			t.Errorf("local(%q, linux) = %q, want %q", tt.input, got, tt.linux)
+		}
+		if got := local(tt.input, "windows"); got != tt.windows {
+			t.Errorf("local(%q, windows) = %q, want %q", tt.input, got, tt.windows)
+		}
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"~/.ssh/id_ed25519.pub", filepath.Join(home, ".ssh/id_ed25519.pub")},
+		{"~", "~"},
+		{"~other/key.pub", "~other/key.pub"},
+		{"/etc/key.pub", "/etc/key.pub"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ExpandHome(tt.input); got != tt.want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
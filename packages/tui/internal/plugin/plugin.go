@@ -0,0 +1,236 @@
+// Package plugin runs the executables in the plugins directory that extend
+// the TUI with commands, panels and completion providers.
+//
+// A plugin is any executable file. It is run once per call with the call as
+// its only argument, reads a JSON Request on stdin and writes a JSON
+// Response on stdout:
+//
+//	describe   lists what the plugin provides, see Manifest
+//	command    runs one of its commands
+//	panel      renders one of its panels as text
+//	complete   returns completion items for a query
+//
+// A plugin reports failure with the error field of its response, or by
+// exiting with a non-zero status and a message on stderr.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Version is the protocol version sent with every request
+const Version = 1
+
+const (
+	// describeTimeout bounds the startup cost of a plugin
+	describeTimeout = 3 * time.Second
+	// callTimeout bounds every other call
+	callTimeout = 15 * time.Second
+	// maxOutput caps what is read of a response
+	maxOutput = 1 << 20
+)
+
+// Calls a plugin answers
+const (
+	CallDescribe = "describe"
+	CallCommand  = "command"
+	CallPanel    = "panel"
+	CallComplete = "complete"
+)
+
+// Request is what a plugin reads on stdin
+type Request struct {
+	Version int    `json:"version"`
+	Call    string `json:"call"`
+	// Name is the command or panel called
+	Name string   `json:"name,omitempty"`
+	Args []string `json:"args,omitempty"`
+	// Query is the text typed after the completion trigger
+	Query string `json:"query,omitempty"`
+	// Width and Height are the space a panel is shown in
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Cwd       string `json:"cwd"`
+}
+
+// Response is what a plugin writes on stdout
+type Response struct {
+	Error string `json:"error,omitempty"`
+	// Message is shown in a toast after a command
+	Message string `json:"message,omitempty"`
+	// Prompt replaces the text in the editor after a command
+	Prompt string `json:"prompt,omitempty"`
+	// Title and Content are a rendered panel
+	Title   string `json:"title,omitempty"`
+	Content string `json:"content,omitempty"`
+	// Items are the completions of a query
+	Items []Item `json:"items,omitempty"`
+	// Manifest is the answer to describe
+	Manifest
+}
+
+// Manifest lists what a plugin provides
+type Manifest struct {
+	Commands    []Command    `json:"commands,omitempty"`
+	Panels      []Panel      `json:"panels,omitempty"`
+	Completions []Completion `json:"completions,omitempty"`
+}
+
+// Command is a slash command a plugin runs
+type Command struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Args name the arguments the command takes, all optional
+	Args []string `json:"args,omitempty"`
+	// Keybind binds the command like a keybind in the config
+	Keybind string `json:"keybind,omitempty"`
+}
+
+// Panel is text a plugin renders, shown in a dialog opened by a command of
+// the same name
+type Panel struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Refresh is how often the open panel is rendered again in seconds, 0
+	// renders it once
+	Refresh int `json:"refresh,omitempty"`
+}
+
+// Completion is a completion provider, opened when a word starts with its
+// trigger, a single character other than /, @, # and $
+type Completion struct {
+	Trigger     string `json:"trigger"`
+	Description string `json:"description"`
+}
+
+// Item is one completion
+type Item struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Plugin is an executable that described itself
+type Plugin struct {
+	Name     string
+	Path     string
+	Manifest Manifest
+}
+
+// Load describes every plugin in dir. Plugins that fail to describe
+// themselves are skipped and reported in the error. A missing directory
+// has no plugins.
+func Load(ctx context.Context, dir, cwd string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+	var plugins []*Plugin
+	var errs []error
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !executable(path) {
+			continue
+		}
+		plugin := &Plugin{
+			Name: strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			Path: path,
+		}
+		describeCtx, cancel := context.WithTimeout(ctx, describeTimeout)
+		response, err := plugin.call(describeCtx, Request{Call: CallDescribe, Cwd: cwd})
+		cancel()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plugin.Manifest = response.Manifest
+		plugins = append(plugins, plugin)
+	}
+	return plugins, errors.Join(errs...)
+}
+
+// executable reports whether path is a file the plugin loader runs
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return slices.Contains([]string{".exe", ".bat", ".cmd"}, strings.ToLower(filepath.Ext(path)))
+	}
+	return info.Mode()&0o111 != 0
+}
+
+// Call sends a request to the plugin and returns its response
+func (p *Plugin) Call(ctx context.Context, request Request) (Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	return p.call(ctx, request)
+}
+
+func (p *Plugin) call(ctx context.Context, request Request) (Response, error) {
+	request.Version = Version
+	input, err := json.Marshal(request)
+	if err != nil {
+		return Response{}, err
+	}
+
+	cmd := exec.CommandContext(ctx, p.Path, request.Call)
+	cmd.Dir = request.Cwd
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedWriter{buffer: &stdout, limit: maxOutput}
+	cmd.Stderr = &limitedWriter{buffer: &stderr, limit: maxOutput}
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return Response{}, fmt.Errorf("plugin %s timed out on %s", p.Name, request.Call)
+	}
+	if err != nil {
+		if message := firstLine(stderr.String()); message != "" {
+			return Response{}, fmt.Errorf("plugin %s: %s", p.Name, message)
+		}
+		return Response{}, fmt.Errorf("plugin %s failed on %s: %w", p.Name, request.Call, err)
+	}
+
+	var response Response
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return Response{}, fmt.Errorf("plugin %s sent an invalid response to %s: %w", p.Name, request.Call, err)
+	}
+	if response.Error != "" {
+		return response, fmt.Errorf("plugin %s: %s", p.Name, response.Error)
+	}
+	return response, nil
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+// limitedWriter drops what is written beyond its limit, so a runaway
+// plugin cannot fill the memory
+type limitedWriter struct {
+	buffer *bytes.Buffer
+	limit  int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if room := w.limit - w.buffer.Len(); room > 0 {
+		w.buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writePlugin writes a shell script plugin
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "jira", `case "$1" in
+describe) echo '{"commands":[{"name":"issue","description":"open an issue","args":["key"]}],"completions":[{"trigger":"!","description":"issues"}]}' ;;
+command) cat > request.json; echo '{"message":"opened"}' ;;
+*) echo "unknown call $1" >&2; exit 1 ;;
+esac`)
+	writePlugin(t, dir, "broken", `echo 'not json'`)
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := Load(context.Background(), dir, dir)
+	if err == nil || !strings.Contains(err.Error(), "plugin broken sent an invalid response") {
+		t.Errorf("Load error = %v, want the broken plugin reported", err)
+	}
+	if len(plugins) != 1 || plugins[0].Name != "jira" {
+		t.Fatalf("Load = %+v, want only jira", plugins)
+	}
+	jira := plugins[0]
+	if len(jira.Manifest.Commands) != 1 || jira.Manifest.Commands[0].Args[0] != "key" {
+		t.Errorf("commands = %+v", jira.Manifest.Commands)
+	}
+	if len(jira.Manifest.Completions) != 1 || jira.Manifest.Completions[0].Trigger != "!" {
+		t.Errorf("completions = %+v", jira.Manifest.Completions)
+	}
+
+	response, err := jira.Call(context.Background(), Request{Call: CallCommand, Name: "issue", Args: []string{"ABC-1"}, Cwd: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Message != "opened" {
+		t.Errorf("message = %q, want opened", response.Message)
+	}
+	request, err := os.ReadFile(filepath.Join(dir, "request.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(request), `"args":["ABC-1"]`) || !strings.Contains(string(request), `"version":1`) {
+		t.Errorf("request = %s, want the args and the protocol version", request)
+	}
+
+	_, err = jira.Call(context.Background(), Request{Call: CallPanel, Name: "board", Cwd: dir})
+	if err == nil || err.Error() != "plugin jira: unknown call panel" {
+		t.Errorf("panel error = %v, want the stderr of the plugin", err)
+	}
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	plugins, err := Load(context.Background(), filepath.Join(t.TempDir(), "plugins"), "")
+	if err != nil || plugins != nil {
+		t.Errorf("Load = %v, %v, want no plugins", plugins, err)
+	}
+}
@@ -0,0 +1,327 @@
+// Package recorder captures rendered TUI frames and writes them out as
+// asciinema (asciicast v2) recordings.
+package recorder
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoRecording is returned when exporting before anything was recorded
+var ErrNoRecording = errors.New("nothing recorded yet")
+
+// frame is a full redraw of the screen, spooled to disk as a JSON line
+type frame struct {
+	At   time.Duration `json:"at"`
+	Data string        `json:"data"`
+}
+
+// Range selects part of a recording. A zero To runs to the end.
+type Range struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// contains reports whether a frame at the given time falls into the range
+func (r Range) contains(at time.Duration) bool {
+	return at >= r.From && (r.To == 0 || at <= r.To)
+}
+
+// ParseRange reads a range given as "start-end" with times as seconds,
+// m:ss or h:mm:ss. Either side may be left out, "1:30-" runs to the end
+// and "-45" starts at the beginning.
+func ParseRange(s string) (Range, error) {
+	start, end, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return Range{}, fmt.Errorf("invalid range %q, expected start-end such as 0:30-2:00", s)
+	}
+	var r Range
+	var err error
+	if start != "" {
+		if r.From, err = parseTime(start); err != nil {
+			return Range{}, err
+		}
+	}
+	if end != "" {
+		if r.To, err = parseTime(end); err != nil {
+			return Range{}, err
+		}
+		if r.To <= r.From {
+			return Range{}, fmt.Errorf("invalid range %q, it ends before it starts", s)
+		}
+	}
+	return r, nil
+}
+
+func parseTime(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	var seconds int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// Recorder spools frames to a file between Start and Stop, so a long
+// recording does not grow memory, and exports any range of the last one
+type Recorder struct {
+	mu        sync.Mutex
+	recording bool
+	started   time.Time
+	width     int
+	height    int
+	last      string
+	elapsed   time.Duration
+	// spool holds the frames of the last recording
+	spool  string
+	frames chan frame
+	done   chan error
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// New creates an idle recorder
+func New() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+// Start begins a new recording, spooled to a file in dir. The previous
+// recording is discarded.
+func (r *Recorder) Start(dir string, width, height int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recording {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create recordings directory %s: %w", dir, err)
+	}
+	file, err := os.CreateTemp(dir, ".recording-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("failed to create recording spool: %w", err)
+	}
+	r.discard()
+
+	r.recording = true
+	r.started = r.now()
+	r.width = width
+	r.height = height
+	r.last = ""
+	r.elapsed = 0
+	r.spool = file.Name()
+	r.frames = make(chan frame, 64)
+	r.done = make(chan error, 1)
+	go spool(file, r.frames, r.done)
+	return nil
+}
+
+// spool writes frames until the channel is closed, off the render loop
+func spool(file *os.File, frames <-chan frame, done chan<- error) {
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	var err error
+	for f := range frames {
+		if err == nil {
+			err = encoder.Encode(f)
+		}
+	}
+	if flushErr := writer.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	done <- err
+}
+
+// discard removes the spool of the previous recording
+func (r *Recorder) discard() {
+	if r.spool != "" {
+		os.Remove(r.spool)
+		r.spool = ""
+	}
+}
+
+// Stop ends the recording and returns how long it ran
+func (r *Recorder) Stop() (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.recording {
+		return r.elapsed, nil
+	}
+	r.recording = false
+	r.elapsed = r.now().Sub(r.started)
+	close(r.frames)
+	if err := <-r.done; err != nil {
+		return r.elapsed, fmt.Errorf("failed to write recording: %w", err)
+	}
+	return r.elapsed, nil
+}
+
+// Close stops recording and removes the spooled frames
+func (r *Recorder) Close() {
+	if _, err := r.Stop(); err != nil {
+		slog.Error("Failed to stop recording", "error", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.discard()
+}
+
+// Recording reports whether frames are being captured
+func (r *Recorder) Recording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recording
+}
+
+// Capture records a rendered frame if it differs from the previous one
+func (r *Recorder) Capture(view string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.recording || view == r.last {
+		return
+	}
+	r.last = view
+	r.frames <- frame{At: r.now().Sub(r.started), Data: view}
+}
+
+// Resize updates the recorded terminal size if it grows during recording
+func (r *Recorder) Resize(width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recording {
+		r.width = max(r.width, width)
+		r.height = max(r.height, height)
+	}
+}
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// WriteCast writes the frames of the last recording that fall into the
+// range as an asciicast v2 stream, starting at zero. Each frame clears the
+// screen and redraws, so the screen shown when the range starts is taken
+// from the frame before it.
+func (r *Recorder) WriteCast(w io.Writer, title string, rng Range) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recording {
+		return errors.New("recording is still running")
+	}
+	if r.spool == "" {
+		return ErrNoRecording
+	}
+
+	file, err := os.Open(r.spool)
+	if err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+	defer file.Close()
+
+	header, err := json.Marshal(castHeader{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.started.Add(rng.From).Unix(),
+		Title:     title,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", header); err != nil {
+		return err
+	}
+
+	write := func(at time.Duration, data string) error {
+		data = "\x1b[H\x1b[2J" + strings.ReplaceAll(data, "\n", "\r\n")
+		event, err := json.Marshal([]any{at.Seconds(), "o", data})
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", event)
+		return err
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	var before *frame
+	for {
+		var f frame
+		if err := decoder.Decode(&f); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read recording: %w", err)
+		}
+		if f.At < rng.From {
+			before = &f
+			continue
+		}
+		if !rng.contains(f.At) {
+			break
+		}
+		if before != nil && f.At > rng.From {
+			if err := write(0, before.Data); err != nil {
+				return err
+			}
+		}
+		before = nil
+		if err := write(f.At-rng.From, f.Data); err != nil {
+			return err
+		}
+	}
+	// nothing changed on screen during the range
+	if before != nil {
+		return write(0, before.Data)
+	}
+	return nil
+}
+
+// Save writes the range of the last recording to a new .cast file in dir
+// and returns its path
+func (r *Recorder) Save(dir, title string, rng Range) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create recordings directory %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("dgmo-%s.cast", time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create recording %s: %w", path, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := r.WriteCast(writer, title, rng); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to write recording %s: %w", path, err)
+	}
+	if err := writer.Flush(); err != nil {
+		return "", fmt.Errorf("failed to flush recording %s: %w", path, err)
+	}
+	return path, nil
+}
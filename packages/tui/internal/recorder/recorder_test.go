@@ -0,0 +1,159 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Range
+		wantErr bool
+	}{
+		{"0:30-2:00", Range{From: 30 * time.Second, To: 2 * time.Minute}, false},
+		{"10-20", Range{From: 10 * time.Second, To: 20 * time.Second}, false},
+		{"1:30-", Range{From: 90 * time.Second}, false},
+		{"-45", Range{To: 45 * time.Second}, false},
+		{"1:00:00-1:00:05", Range{From: time.Hour, To: time.Hour + 5*time.Second}, false},
+		{"30", Range{}, true},
+		{"2:00-1:00", Range{}, true},
+		{"a-b", Range{}, true},
+		{"1:2:3:4-", Range{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRange(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// record captures a frame at each of the given seconds
+func record(t *testing.T, frames map[int]string, seconds ...int) *Recorder {
+	t.Helper()
+	start := time.Unix(1700000000, 0)
+	now := start
+	r := New()
+	r.now = func() time.Time { return now }
+	if err := r.Start(t.TempDir(), 80, 24); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range seconds {
+		now = start.Add(time.Duration(s) * time.Second)
+		r.Capture(frames[s])
+	}
+	now = start.Add(time.Minute)
+	if _, err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(r.Close)
+	return r
+}
+
+type event struct {
+	at   float64
+	data string
+}
+
+// readCast checks the header and returns the events of a cast
+func readCast(t *testing.T, cast string) []event {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(cast, "\n"), "\n")
+	var header castHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("invalid header %q: %v", lines[0], err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 {
+		t.Errorf("header = %+v", header)
+	}
+	var events []event
+	for _, line := range lines[1:] {
+		var raw []any
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		data := strings.TrimPrefix(raw[2].(string), "\x1b[H\x1b[2J")
+		events = append(events, event{at: raw[0].(float64), data: data})
+	}
+	return events
+}
+
+func TestWriteCast(t *testing.T) {
+	frames := map[int]string{0: "a", 5: "b\nc", 10: "d", 20: "e"}
+	r := record(t, frames, 0, 5, 5, 10, 20)
+
+	tests := []struct {
+		name string
+		rng  Range
+		want []event
+	}{
+		{
+			name: "whole recording",
+			want: []event{{0, "a"}, {5, "b\r\nc"}, {10, "d"}, {20, "e"}},
+		},
+		{
+			name: "starts between frames",
+			rng:  Range{From: 7 * time.Second, To: 15 * time.Second},
+			want: []event{{0, "b\r\nc"}, {3, "d"}},
+		},
+		{
+			name: "starts on a frame",
+			rng:  Range{From: 10 * time.Second},
+			want: []event{{0, "d"}, {10, "e"}},
+		},
+		{
+			name: "no change in range",
+			rng:  Range{From: 12 * time.Second, To: 18 * time.Second},
+			want: []event{{0, "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := r.WriteCast(&buf, "test", tt.rng); err != nil {
+				t.Fatal(err)
+			}
+			got := readCast(t, buf.String())
+			if len(got) != len(tt.want) {
+				t.Fatalf("got events %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCastWithoutRecording(t *testing.T) {
+	if err := New().WriteCast(&bytes.Buffer{}, "test", Range{}); err != ErrNoRecording {
+		t.Errorf("got %v, want %v", err, ErrNoRecording)
+	}
+}
+
+func TestSave(t *testing.T) {
+	r := record(t, map[int]string{1: "a"}, 1)
+	path, err := r.Save(t.TempDir(), "test", Range{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, ".cast") {
+		t.Errorf("path = %q, want a .cast file", path)
+	}
+}
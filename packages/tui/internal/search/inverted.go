@@ -0,0 +1,229 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// invertedIndex keeps documents and their postings in memory and writes the
+// documents to disk on compaction. Postings are rebuilt when it is opened.
+type invertedIndex struct {
+	opts Options
+
+	mu       sync.RWMutex
+	docs     map[string]Document
+	postings map[string]map[string]int // term -> document ID -> frequency
+	bytes    int64
+	dirty    bool
+}
+
+func openInverted(opts Options) (Index, error) {
+	idx := &invertedIndex{
+		opts:     opts,
+		docs:     make(map[string]Document),
+		postings: make(map[string]map[string]int),
+	}
+	if opts.Path == "" {
+		return idx, nil
+	}
+	file, err := os.Open(opts.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open search index: %w", err)
+	}
+	defer file.Close()
+	var docs []Document
+	if err := gob.NewDecoder(file).Decode(&docs); err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	for _, doc := range docs {
+		idx.add(doc)
+	}
+	return idx, nil
+}
+
+func (idx *invertedIndex) add(doc Document) {
+	idx.remove(doc.ID)
+	idx.docs[doc.ID] = doc
+	idx.bytes += doc.size()
+	for _, term := range tokenize(doc.Title + " " + doc.Text) {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]int)
+		}
+		idx.postings[term][doc.ID]++
+	}
+}
+
+func (idx *invertedIndex) remove(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	delete(idx.docs, id)
+	idx.bytes -= doc.size()
+	for _, term := range tokenize(doc.Title + " " + doc.Text) {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+}
+
+func (idx *invertedIndex) Put(docs ...Document) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return errors.New("search document has no ID")
+		}
+		idx.add(doc)
+	}
+	idx.dirty = true
+	return nil
+}
+
+func (idx *invertedIndex) Delete(ids ...string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, id := range ids {
+		idx.remove(id)
+	}
+	idx.dirty = true
+	return nil
+}
+
+// matches returns the documents containing a term and how often, the last
+// term of a query also matches as a prefix
+func (idx *invertedIndex) matches(term string, prefix bool) map[string]int {
+	if !prefix {
+		return idx.postings[term]
+	}
+	found := make(map[string]int)
+	for candidate, postings := range idx.postings {
+		if !strings.HasPrefix(candidate, term) {
+			continue
+		}
+		for id, freq := range postings {
+			found[id] += freq
+		}
+	}
+	return found
+}
+
+func (idx *invertedIndex) Search(query Query) ([]Result, error) {
+	terms := tokenize(query.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[string]float64)
+	for i, term := range terms {
+		postings := idx.matches(term, i == len(terms)-1)
+		if len(postings) == 0 {
+			return nil, nil
+		}
+		idf := math.Log(1 + float64(len(idx.docs))/float64(len(postings)))
+		next := make(map[string]float64, len(postings))
+		for id, freq := range postings {
+			if i > 0 {
+				if _, ok := scores[id]; !ok {
+					continue
+				}
+			}
+			next[id] = scores[id] + (1+math.Log(float64(freq)))*idf
+		}
+		scores = next
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		doc := idx.docs[id]
+		if query.Kind != "" && doc.Kind != query.Kind {
+			continue
+		}
+		results = append(results, Result{Document: doc, Score: score})
+	}
+	sortResults(results)
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+func (idx *invertedIndex) Stats() Stats {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return Stats{Documents: len(idx.docs), Bytes: idx.bytes}
+}
+
+// evict drops the oldest documents until the index fits its caps
+func (idx *invertedIndex) evict() {
+	over := func() bool {
+		return (idx.opts.MaxDocuments > 0 && len(idx.docs) > idx.opts.MaxDocuments) ||
+			(idx.opts.MaxBytes > 0 && idx.bytes > idx.opts.MaxBytes)
+	}
+	if !over() {
+		return
+	}
+	docs := make([]Document, 0, len(idx.docs))
+	for _, doc := range idx.docs {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Time.Before(docs[j].Time) })
+	for _, doc := range docs {
+		if !over() {
+			break
+		}
+		idx.remove(doc.ID)
+	}
+	idx.dirty = true
+}
+
+func (idx *invertedIndex) Compact() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.evict()
+	if !idx.dirty || idx.opts.Path == "" {
+		return nil
+	}
+	docs := make([]Document, 0, len(idx.docs))
+	for _, doc := range idx.docs {
+		docs = append(docs, doc)
+	}
+	if err := os.MkdirAll(filepath.Dir(idx.opts.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create search index directory: %w", err)
+	}
+	// write to a temporary file first so a crash never leaves half an index
+	tmp := idx.opts.Path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := gob.NewEncoder(file).Encode(docs); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Rename(tmp, idx.opts.Path); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	idx.dirty = false
+	return nil
+}
+
+func (idx *invertedIndex) Close() error {
+	return idx.Compact()
+}
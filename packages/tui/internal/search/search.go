@@ -0,0 +1,151 @@
+// Package search indexes the local caches of the tui, such as messages,
+// prompt history and artifacts, for full text search. Backends are
+// pluggable; the built-in one is an inverted index kept in a single file.
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Kind is the cache a document comes from
+type Kind string
+
+const (
+	KindMessage  Kind = "message"
+	KindPrompt   Kind = "prompt"
+	KindArtifact Kind = "artifact"
+)
+
+// Document is a searchable entry. Putting a document with an existing ID
+// replaces it.
+type Document struct {
+	ID        string
+	Kind      Kind
+	SessionID string
+	Title     string
+	Text      string
+	Time      time.Time
+}
+
+// size approximates the bytes a document takes in the index
+func (d Document) size() int64 {
+	return int64(len(d.ID) + len(d.SessionID) + len(d.Title) + len(d.Text))
+}
+
+// Query selects documents. All terms must match, the last one as a prefix
+// so results show up while typing.
+type Query struct {
+	Text string
+	// Kind restricts results to one cache, all caches when empty
+	Kind  Kind
+	Limit int
+}
+
+// Result is a matching document, best first
+type Result struct {
+	Document
+	Score float64
+}
+
+// Stats describes the size of an index
+type Stats struct {
+	Documents int
+	Bytes     int64
+}
+
+// Index is a search backend
+type Index interface {
+	Put(docs ...Document) error
+	Delete(ids ...string) error
+	Search(query Query) ([]Result, error)
+	Stats() Stats
+	// Compact drops the oldest documents beyond the size caps and persists
+	// the index
+	Compact() error
+	Close() error
+}
+
+// Options configures an index
+type Options struct {
+	// Path is where the index is stored, it is kept in memory if empty
+	Path string
+	// MaxDocuments and MaxBytes cap the index, unlimited when zero
+	MaxDocuments int
+	MaxBytes     int64
+}
+
+// Backend opens an index
+type Backend func(Options) (Index, error)
+
+// DefaultBackend is the backend used when none is configured
+const DefaultBackend = "inverted"
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		DefaultBackend: openInverted,
+	}
+)
+
+// Register makes a backend available under a name, replacing any backend
+// registered before under the same name
+func Register(name string, backend Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[name] = backend
+}
+
+// Open opens an index with the named backend
+func Open(name string, opts Options) (Index, error) {
+	if name == "" {
+		name = DefaultBackend
+	}
+	backendsMu.RLock()
+	backend, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown search backend %q", name)
+	}
+	return backend(opts)
+}
+
+// Maintain compacts the index every interval until ctx is done, so writes
+// stay cheap and caps are enforced in the background
+func Maintain(ctx context.Context, index Index, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := index.Compact(); err != nil {
+				slog.Error("Failed to compact search index", "error", err)
+			}
+		}
+	}
+}
+
+// tokenize splits text into lower case words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// sortResults orders results by score, then by recency
+func sortResults(results []Result) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Time.After(results[j].Time)
+	})
+}
@@ -0,0 +1,55 @@
+package styles
+
+import (
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/styles/glyphs"
+)
+
+// accessible is set once at startup, before anything renders
+var accessible bool
+
+// Status labels that replace spinners, symbols and colors in accessible mode
+const (
+	StatusRunning  = "[RUNNING]"
+	StatusDone     = "[DONE]"
+	StatusFailed   = "[FAILED]"
+	StatusPending  = "[PENDING]"
+	StatusDegraded = "[DEGRADED]"
+	StatusInfo     = "[INFO]"
+	StatusSuccess  = "[SUCCESS]"
+	StatusWarning  = "[WARNING]"
+	StatusError    = "[ERROR]"
+)
+
+// SetAccessible turns the screen reader friendly mode on or off. It drops
+// spinners, emoji and box drawing, and spells out states that are
+// otherwise shown by color alone.
+func SetAccessible(on bool) {
+	accessible = on
+}
+
+// Accessible reports whether the screen reader friendly mode is on
+func Accessible() bool {
+	return accessible
+}
+
+// StatusMark returns symbol, or the status label in accessible mode
+func StatusMark(symbol, label string) string {
+	if accessible {
+		return label
+	}
+	return symbol
+}
+
+// BlockBorder is the bar beside transcript blocks and the editor. It is
+// blank in accessible mode, which keeps the layout but reads as nothing,
+// and plain ASCII on terminals without UTF-8.
+func BlockBorder() lipgloss.Border {
+	if accessible {
+		return lipgloss.HiddenBorder()
+	}
+	if glyphs.CurrentSupport() == glyphs.ASCII {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.ThickBorder()
+}
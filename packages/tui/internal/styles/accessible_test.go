@@ -0,0 +1,18 @@
+package styles
+
+import "testing"
+
+func TestStatusMark(t *testing.T) {
+	defer SetAccessible(false)
+
+	if got := StatusMark("✓", StatusDone); got != "✓" {
+		t.Errorf("StatusMark = %q, want the symbol", got)
+	}
+	SetAccessible(true)
+	if got := StatusMark("✓", StatusDone); got != "[DONE]" {
+		t.Errorf("accessible StatusMark = %q, want [DONE]", got)
+	}
+	if border := BlockBorder(); border.Left != " " {
+		t.Errorf("accessible BlockBorder left = %q, want blank", border.Left)
+	}
+}
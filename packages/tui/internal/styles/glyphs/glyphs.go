@@ -0,0 +1,236 @@
+// Package glyphs holds the characters the renderers draw spinners, boxes
+// and icons with. Terminals without UTF-8 get ASCII, and terminals with a
+// Nerd Font get its icons instead of emoji.
+package glyphs
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// Support is how much of Unicode a terminal can show
+type Support int
+
+const (
+	// ASCII is for terminals without UTF-8, such as the Windows console host
+	ASCII Support = iota
+	// Unicode is for UTF-8 terminals, with emoji as icons
+	Unicode
+	// NerdFont is for UTF-8 terminals whose font has the Nerd Font icons
+	NerdFont
+)
+
+// Set is the characters drawn for one level of support
+type Set struct {
+	// Spinner are the frames of the spinner beside running tools
+	Spinner []string
+	// Spinners grow more elaborate the longer a task runs, one per stage
+	Spinners [][]string
+
+	TopLeft     string
+	TopRight    string
+	BottomLeft  string
+	BottomRight string
+	Horizontal  string
+	Vertical    string
+	// Bar is the heavy line beside transcript blocks and overlays
+	Bar string
+
+	Running string
+	// Dot marks something waiting or active, where no state applies
+	Dot     string
+	Done    string
+	Failed  string
+	Pending string
+	Timer   string
+
+	BarFull  string
+	BarEmpty string
+	// Branch leads the title of a tool call shown in a text block
+	Branch string
+
+	// Tools are the icons of tool calls by tool name, Tool for the rest
+	Tools map[string]string
+	Tool  string
+}
+
+// Icon returns the icon of a tool call
+func (s Set) Icon(tool string) string {
+	if icon, ok := s.Tools[tool]; ok {
+		return icon
+	}
+	return s.Tool
+}
+
+var unicodeSet = Set{
+	Spinner: []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+	Spinners: [][]string{
+		{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}, // Dots
+		{"◐", "◓", "◑", "◒"}, // Circle quarters
+		{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█", "▇", "▆", "▅", "▄", "▃", "▂"}, // Blocks
+		{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"},                               // Complex dots
+		{"◴", "◷", "◶", "◵"}, // Diamonds
+		{"⊙", "⊗", "⊕", "⊗"}, // Pulsing
+	},
+	TopLeft:     "╭",
+	TopRight:    "╮",
+	BottomLeft:  "╰",
+	BottomRight: "╯",
+	Horizontal:  "─",
+	Vertical:    "│",
+	Bar:         "┃",
+	Running:     "▶",
+	Dot:         "●",
+	Done:        "✓",
+	Failed:      "✗",
+	Pending:     "○",
+	Timer:       "⏱ ",
+	BarFull:     "█",
+	BarEmpty:    "░",
+	Branch:      "∟ ",
+	Tools: map[string]string{
+		"task":      "🚀",
+		"bash":      "⚡",
+		"edit":      "✏️",
+		"webfetch":  "🌐",
+		"glob":      "🔎",
+		"grep":      "🔍",
+		"list":      "📁",
+		"read":      "📖",
+		"write":     "💾",
+		"todowrite": "📋",
+		"todoread":  "📋",
+		"patch":     "🔧",
+	},
+	Tool: "⚙️",
+}
+
+var asciiSet = Set{
+	Spinner: []string{"|", "/", "-", "\\"},
+	Spinners: [][]string{
+		{"|", "/", "-", "\\"},
+		{".  ", ".. ", "...", " ..", "  .", "   "},
+		{"[=  ]", "[ = ]", "[  =]", "[ = ]"},
+	},
+	TopLeft:     "+",
+	TopRight:    "+",
+	BottomLeft:  "+",
+	BottomRight: "+",
+	Horizontal:  "-",
+	Vertical:    "|",
+	Bar:         "|",
+	Running:     ">",
+	Dot:         "*",
+	Done:        "+",
+	Failed:      "x",
+	Pending:     "o",
+	Timer:       "",
+	BarFull:     "#",
+	BarEmpty:    ".",
+	Branch:      "- ",
+	Tools:       map[string]string{},
+	Tool:        "*",
+}
+
+// nerdFontSet keeps the Unicode frames and boxes, only the icons change
+var nerdFontSet = func() Set {
+	set := unicodeSet
+	set.Timer = "\U000f051f " // nf-md-timer_outline
+	set.Tools = map[string]string{
+		"task":      "\U000f0463", // nf-md-rocket
+		"bash":      "\uf489",     // nf-oct-terminal
+		"edit":      "\uf044",     // nf-fa-edit
+		"webfetch":  "\uf0ac",     // nf-fa-globe
+		"glob":      "\uf002",     // nf-fa-search
+		"grep":      "\uf002",     // nf-fa-search
+		"list":      "\uf07b",     // nf-fa-folder
+		"read":      "\uf02d",     // nf-fa-book
+		"write":     "\uf0c7",     // nf-fa-save
+		"todowrite": "\uf0ae",     // nf-fa-tasks
+		"todoread":  "\uf0ae",     // nf-fa-tasks
+		"patch":     "\uf0ad",     // nf-fa-wrench
+	}
+	set.Tool = "\uf013" // nf-fa-cog
+	return set
+}()
+
+var current atomic.Int32
+
+func init() {
+	current.Store(int32(Unicode))
+}
+
+// SetSupport picks the glyphs for a level of support
+func SetSupport(support Support) {
+	current.Store(int32(support))
+}
+
+// CurrentSupport returns the configured level of support
+func CurrentSupport() Support {
+	return Support(current.Load())
+}
+
+// Current returns the glyphs of the configured level of support
+func Current() Set {
+	switch CurrentSupport() {
+	case ASCII:
+		return asciiSet
+	case NerdFont:
+		return nerdFontSet
+	}
+	return unicodeSet
+}
+
+// ParseSupport reads a setting of "ascii", "unicode" or "nerd". Anything
+// else, "auto" included, is detected from the environment.
+func ParseSupport(setting string) Support {
+	switch setting {
+	case "ascii":
+		return ASCII
+	case "unicode":
+		return Unicode
+	case "nerd":
+		return NerdFont
+	}
+	return Detect()
+}
+
+// Detect guesses the support of the terminal from the environment. Nerd
+// Fonts cannot be detected, NERD_FONT=1 tells about one.
+func Detect() Support {
+	return detect(os.Getenv, runtime.GOOS)
+}
+
+func detect(getenv func(string) string, goos string) Support {
+	support := Unicode
+	switch {
+	case goos == "windows":
+		// the console host garbles anything outside its code page, Windows
+		// Terminal and the editors' terminals do not
+		if getenv("WT_SESSION") == "" && getenv("TERM_PROGRAM") == "" {
+			support = ASCII
+		}
+	case getenv("TERM") == "linux" || getenv("TERM") == "dumb":
+		support = ASCII
+	case !utf8Locale(getenv):
+		support = ASCII
+	}
+	if support == Unicode && getenv("NERD_FONT") == "1" {
+		support = NerdFont
+	}
+	return support
+}
+
+// utf8Locale reports whether the locale the terminal runs with is UTF-8.
+// No locale at all is taken as UTF-8, which most terminals default to.
+func utf8Locale(getenv func(string) string) bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return true
+}
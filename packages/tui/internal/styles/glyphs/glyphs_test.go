@@ -0,0 +1,46 @@
+package glyphs
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		goos string
+		want Support
+	}{
+		{"utf-8 locale", map[string]string{"LANG": "en_US.UTF-8"}, "linux", Unicode},
+		{"no locale", map[string]string{}, "darwin", Unicode},
+		{"latin-1 locale", map[string]string{"LANG": "de_DE.ISO-8859-1"}, "linux", ASCII},
+		{"LC_ALL wins", map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, "linux", ASCII},
+		{"linux console", map[string]string{"TERM": "linux", "LANG": "en_US.UTF-8"}, "linux", ASCII},
+		{"console host", map[string]string{}, "windows", ASCII},
+		{"windows terminal", map[string]string{"WT_SESSION": "1"}, "windows", Unicode},
+		{"nerd font", map[string]string{"NERD_FONT": "1"}, "linux", NerdFont},
+		{"nerd font without utf-8", map[string]string{"NERD_FONT": "1", "LANG": "C"}, "linux", ASCII},
+	}
+	for _, test := range tests {
+		getenv := func(name string) string { return test.env[name] }
+		if got := detect(getenv, test.goos); got != test.want {
+			t.Errorf("%s: detect = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestASCIISet(t *testing.T) {
+	defer SetSupport(Unicode)
+	SetSupport(ASCII)
+	set := Current()
+	for _, frames := range append([][]string{set.Spinner}, set.Spinners...) {
+		for _, frame := range frames {
+			for _, r := range frame {
+				if r > 127 {
+					t.Errorf("spinner frame %q is not ASCII", frame)
+				}
+			}
+		}
+	}
+	if set.Icon("bash") != set.Tool {
+		t.Errorf("tool icon = %q, want the fallback %q", set.Icon("bash"), set.Tool)
+	}
+}
@@ -0,0 +1,108 @@
+package styles
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// HyperlinkOptions control how file paths are linked
+type HyperlinkOptions struct {
+	// Enabled emits OSC 8 hyperlinks, text is left plain otherwise
+	Enabled bool
+	// Scheme is "file" for file:// URLs or an editor scheme such as
+	// "vscode" or "cursor", which open the file in the editor
+	Scheme string
+	// Root resolves relative paths
+	Root string
+}
+
+var (
+	hyperlinks   = HyperlinkOptions{Scheme: "file"}
+	hyperlinksMu sync.RWMutex
+)
+
+// SetHyperlinks changes how file paths are linked
+func SetHyperlinks(options HyperlinkOptions) {
+	if options.Scheme == "" {
+		options.Scheme = "file"
+	}
+	hyperlinksMu.Lock()
+	hyperlinks = options
+	hyperlinksMu.Unlock()
+}
+
+// HyperlinksEnabled reports whether file paths are linked
+func HyperlinksEnabled() bool {
+	hyperlinksMu.RLock()
+	defer hyperlinksMu.RUnlock()
+	return hyperlinks.Enabled
+}
+
+// DetectHyperlinks reports whether the terminal is known to support OSC 8
+// hyperlinks. Multiplexers are left out, not all of them pass links on.
+func DetectHyperlinks() bool {
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return false
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "rio":
+		return true
+	}
+	switch os.Getenv("TERM") {
+	case "xterm-kitty", "xterm-ghostty", "foot", "alacritty", "wezterm":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	// VTE based terminals support links since 0.50
+	if version, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && version >= 5000 {
+		return true
+	}
+	return false
+}
+
+// FileURL returns the URL a file path is linked to, with the line when it
+// is greater than 0 and the scheme is an editor's
+func FileURL(path string, line int) string {
+	hyperlinksMu.RLock()
+	options := hyperlinks
+	hyperlinksMu.RUnlock()
+
+	if !filepath.IsAbs(path) && options.Root != "" {
+		path = filepath.Join(options.Root, path)
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// windows drive paths
+		path = "/" + path
+	}
+	if options.Scheme == "file" {
+		return (&url.URL{Scheme: "file", Path: path}).String()
+	}
+	link := options.Scheme + "://file" + (&url.URL{Path: path}).EscapedPath()
+	if line > 0 {
+		link += ":" + strconv.Itoa(line)
+	}
+	return link
+}
+
+// FileLink makes text a hyperlink to a file when hyperlinks are enabled
+func FileLink(path, text string) string {
+	return FileLineLink(path, 0, text)
+}
+
+// FileLineLink makes text a hyperlink to a line of a file when hyperlinks
+// are enabled
+func FileLineLink(path string, line int, text string) string {
+	if path == "" || !HyperlinksEnabled() {
+		return text
+	}
+	return ansi.SetHyperlink(FileURL(path, line)) + text + ansi.ResetHyperlink()
+}
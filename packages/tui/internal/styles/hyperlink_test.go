@@ -0,0 +1,30 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFileLink(t *testing.T) {
+	defer SetHyperlinks(HyperlinkOptions{})
+
+	SetHyperlinks(HyperlinkOptions{Root: "/repo"})
+	if got := FileLink("main.go", "main.go"); got != "main.go" {
+		t.Errorf("disabled link = %q, want plain text", got)
+	}
+
+	SetHyperlinks(HyperlinkOptions{Enabled: true, Root: "/repo"})
+	want := ansi.SetHyperlink("file:///repo/src/a%20b.go") + "a b.go" + ansi.ResetHyperlink()
+	if got := FileLink("src/a b.go", "a b.go"); got != want {
+		t.Errorf("file link = %q, want %q", got, want)
+	}
+	if got := ansi.Strip(FileLink("/tmp/x.go", "x.go")); got != "x.go" {
+		t.Errorf("link text = %q, want x.go", got)
+	}
+
+	SetHyperlinks(HyperlinkOptions{Enabled: true, Scheme: "vscode", Root: "/repo"})
+	if got := FileURL("/repo/main.go", 12); got != "vscode://file/repo/main.go:12" {
+		t.Errorf("editor URL = %q", got)
+	}
+}
@@ -1,6 +1,9 @@
 package styles
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/ansi"
 	"github.com/charmbracelet/lipgloss/v2"
@@ -16,13 +19,46 @@ func boolPtr(b bool) *bool       { return &b }
 func stringPtr(s string) *string { return &s }
 func uintPtr(u uint) *uint       { return &u }
 
-// returns a glamour TermRenderer configured with the current theme
+// markdownStyles holds the style config derived from each theme and
+// background, keyed by theme instance so a reloaded theme derives anew
+var (
+	markdownStyles   = make(map[string]ansi.StyleConfig)
+	markdownStylesMu sync.Mutex
+)
+
+// markdownStyleConfig returns the style config for the current theme,
+// deriving it once per theme, background and markdown options
+func markdownStyleConfig(backgroundColor compat.AdaptiveColor) ansi.StyleConfig {
+	background := "none"
+	if color := AdaptiveColorToString(backgroundColor); color != nil {
+		background = *color
+	}
+	options := GetMarkdownOptions()
+	key := fmt.Sprintf("%p:%s:%s", theme.CurrentTheme(), background, options.key())
+
+	markdownStylesMu.Lock()
+	defer markdownStylesMu.Unlock()
+	if config, ok := markdownStyles[key]; ok {
+		return config
+	}
+	config := generateMarkdownStyleConfig(backgroundColor)
+	applyMarkdownOptions(&config, options)
+	markdownStyles[key] = config
+	return config
+}
+
+// returns a glamour TermRenderer configured with the current theme and
+// markdown options
 func GetMarkdownRenderer(width int, backgroundColor compat.AdaptiveColor) *glamour.TermRenderer {
-	r, _ := glamour.NewTermRenderer(
-		glamour.WithStyles(generateMarkdownStyleConfig(backgroundColor)),
+	options := []glamour.TermRendererOption{
+		glamour.WithStyles(markdownStyleConfig(backgroundColor)),
 		glamour.WithWordWrap(width),
 		glamour.WithChromaFormatter("terminal16m"),
-	)
+	}
+	if GetMarkdownOptions().Emoji {
+		options = append(options, glamour.WithEmoji())
+	}
+	r, _ := glamour.NewTermRenderer(options...)
 	return r
 }
 
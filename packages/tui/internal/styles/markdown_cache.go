@@ -0,0 +1,145 @@
+package styles
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+const markdownCacheSize = 512
+
+// MarkdownCacheStats reports markdown render cache usage
+type MarkdownCacheStats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+	Size      int
+}
+
+func (s MarkdownCacheStats) String() string {
+	total := s.Hits + s.Misses
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(s.Hits) / float64(total) * 100
+	}
+	return fmt.Sprintf("hits=%d misses=%d evictions=%d size=%d hit_ratio=%.1f%%",
+		s.Hits, s.Misses, s.Evictions, s.Size, ratio)
+}
+
+type markdownCacheEntry struct {
+	key   string
+	value string
+}
+
+// markdownCache is an LRU cache of rendered markdown keyed by content hash,
+// width, theme and background color
+type markdownCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	stats    MarkdownCacheStats
+}
+
+var renderedMarkdown = newMarkdownCache(markdownCacheSize)
+
+func newMarkdownCache(capacity int) *markdownCache {
+	return &markdownCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *markdownCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.stats.Hits++
+		return element.Value.(*markdownCacheEntry).value, true
+	}
+	c.stats.Misses++
+	return "", false
+}
+
+func (c *markdownCache) set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*markdownCacheEntry).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&markdownCacheEntry{key: key, value: value})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*markdownCacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+func (c *markdownCache) snapshot() MarkdownCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = c.order.Len()
+	return stats
+}
+
+func markdownCacheKey(content string, width int, backgroundColor compat.AdaptiveColor) string {
+	hash := sha256.Sum256([]byte(content))
+	background := "none"
+	if color := AdaptiveColorToString(backgroundColor); color != nil {
+		background = *color
+	}
+	return fmt.Sprintf("%s:%d:%s:%s:%s",
+		hex.EncodeToString(hash[:]),
+		width,
+		theme.CurrentThemeName(),
+		background,
+		GetMarkdownOptions().key(),
+	)
+}
+
+// RenderMarkdown renders markdown with the themed glamour renderer, reusing
+// previous output for identical content, width, theme, background and
+// markdown options
+func RenderMarkdown(content string, width int, backgroundColor compat.AdaptiveColor) (string, error) {
+	key := markdownCacheKey(content, width, backgroundColor)
+	if rendered, ok := renderedMarkdown.get(key); ok {
+		return rendered, nil
+	}
+	options := GetMarkdownOptions()
+	var rendered string
+	var err error
+	if options.LineNumbers || options.Links == LinksText {
+		segments := splitCodeBlocks(content)
+		if options.Links == LinksText {
+			hideLinkTargets(segments)
+		}
+		if options.LineNumbers {
+			rendered, err = renderNumbered(segments, width, backgroundColor)
+		} else {
+			rendered, err = GetMarkdownRenderer(width, backgroundColor).Render(joinSegments(segments))
+		}
+	} else {
+		rendered, err = GetMarkdownRenderer(width, backgroundColor).Render(content)
+	}
+	if err != nil {
+		return rendered, err
+	}
+	renderedMarkdown.set(key, rendered)
+	return rendered, nil
+}
+
+// GetMarkdownCacheStats returns the markdown render cache metrics
+func GetMarkdownCacheStats() MarkdownCacheStats {
+	return renderedMarkdown.snapshot()
+}
@@ -0,0 +1,195 @@
+package styles
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/lipgloss/v2/compat"
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// Heading styles
+const (
+	HeadingsHashes    = "hashes"
+	HeadingsPlain     = "plain"
+	HeadingsUnderline = "underline"
+)
+
+// Link styles
+const (
+	LinksFull = "full"
+	LinksText = "text"
+)
+
+// MarkdownOptions are the user's choices for rendering markdown
+type MarkdownOptions struct {
+	// Emoji turns :shortcodes: into emoji
+	Emoji bool
+	// LineNumbers numbers the lines of code blocks
+	LineNumbers bool
+	// Headings is HeadingsHashes, HeadingsPlain or HeadingsUnderline
+	Headings string
+	// Links is LinksFull to show the target after the link text, or
+	// LinksText to show the text only
+	Links string
+}
+
+var (
+	markdownOptions   = MarkdownOptions{Headings: HeadingsHashes, Links: LinksFull}
+	markdownOptionsMu sync.RWMutex
+)
+
+// SetMarkdownOptions changes how markdown is rendered. Unknown heading and
+// link styles fall back to the defaults.
+func SetMarkdownOptions(options MarkdownOptions) {
+	switch options.Headings {
+	case HeadingsPlain, HeadingsUnderline:
+	default:
+		options.Headings = HeadingsHashes
+	}
+	if options.Links != LinksText {
+		options.Links = LinksFull
+	}
+	markdownOptionsMu.Lock()
+	markdownOptions = options
+	markdownOptionsMu.Unlock()
+}
+
+// GetMarkdownOptions returns the current markdown options
+func GetMarkdownOptions() MarkdownOptions {
+	markdownOptionsMu.RLock()
+	defer markdownOptionsMu.RUnlock()
+	return markdownOptions
+}
+
+// key identifies the options in cache keys
+func (o MarkdownOptions) key() string {
+	return fmt.Sprintf("%t:%t:%s:%s", o.Emoji, o.LineNumbers, o.Headings, o.Links)
+}
+
+// applyMarkdownOptions adjusts a themed style config to the heading style
+func applyMarkdownOptions(config *ansi.StyleConfig, options MarkdownOptions) {
+	if options.Headings == HeadingsHashes {
+		return
+	}
+	for _, heading := range []*ansi.StyleBlock{&config.H1, &config.H2, &config.H3, &config.H4, &config.H5, &config.H6} {
+		heading.Prefix = ""
+		if options.Headings == HeadingsUnderline {
+			heading.Underline = boolPtr(true)
+		}
+	}
+}
+
+// markdownSegment is either prose or a fenced code block
+type markdownSegment struct {
+	text   string
+	code   bool
+	closed bool // code block with a closing fence
+}
+
+// splitCodeBlocks cuts markdown into prose and fenced code blocks, an
+// unclosed fence runs to the end
+func splitCodeBlocks(content string) []markdownSegment {
+	var segments []markdownSegment
+	var current []string
+	fence := ""
+	flush := func(code, closed bool) {
+		if len(current) > 0 {
+			segments = append(segments, markdownSegment{text: strings.Join(current, "\n"), code: code, closed: closed})
+			current = nil
+		}
+	}
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			flush(false, false)
+			fence = trimmed[:3]
+			current = append(current, line)
+		case fence != "" && strings.HasPrefix(trimmed, fence) && strings.TrimSpace(strings.Trim(trimmed, fence[:1])) == "":
+			current = append(current, line)
+			flush(true, true)
+			fence = ""
+		default:
+			current = append(current, line)
+		}
+	}
+	flush(fence != "", false)
+	return segments
+}
+
+var markdownLink = regexp.MustCompile(`(^|[^!\\])\[([^\]\n]+)\]\([^)\s]+(?:\s+"[^"\n]*")?\)`)
+
+// hideLinkTargets points inline links at an empty anchor, which glamour
+// renders as the link text alone
+func hideLinkTargets(segments []markdownSegment) {
+	for i, segment := range segments {
+		if !segment.code {
+			segments[i].text = markdownLink.ReplaceAllString(segment.text, "$1[$2](#)")
+		}
+	}
+}
+
+func joinSegments(segments []markdownSegment) string {
+	texts := make([]string, len(segments))
+	for i, segment := range segments {
+		texts[i] = segment.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// trimBlankLines drops the blank lines glamour puts around a document
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(xansi.Strip(lines[0])) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(xansi.Strip(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// renderNumbered renders the segments one by one and puts a line number
+// gutter in front of the code blocks. A block whose lines wrapped is left
+// without numbers, they would not match the source.
+func renderNumbered(segments []markdownSegment, width int, backgroundColor compat.AdaptiveColor) (string, error) {
+	t := theme.CurrentTheme()
+	gutter := NewStyle().Foreground(t.TextMuted()).Background(backgroundColor)
+
+	var blocks []string
+	for _, segment := range segments {
+		if !segment.code {
+			rendered, err := GetMarkdownRenderer(width, backgroundColor).Render(segment.text)
+			if err != nil {
+				return "", err
+			}
+			if lines := trimBlankLines(strings.Split(rendered, "\n")); len(lines) > 0 {
+				blocks = append(blocks, strings.Join(lines, "\n"))
+			}
+			continue
+		}
+
+		// the fences are not rendered
+		count := strings.Count(segment.text, "\n")
+		if segment.closed {
+			count--
+		}
+		digits := len(fmt.Sprint(max(count, 1)))
+		rendered, err := GetMarkdownRenderer(width-digits-1, backgroundColor).Render(segment.text)
+		if err != nil {
+			return "", err
+		}
+		lines := trimBlankLines(strings.Split(rendered, "\n"))
+		if len(lines) == count {
+			for i := range lines {
+				lines[i] = gutter.Render(fmt.Sprintf("%*d ", digits, i+1)) + lines[i]
+			}
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return "\n" + strings.Join(blocks, "\n\n") + "\n", nil
+}
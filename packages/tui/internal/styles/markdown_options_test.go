@@ -0,0 +1,41 @@
+package styles
+
+import (
+	"testing"
+)
+
+func TestSplitCodeBlocks(t *testing.T) {
+	content := "intro\n```go\nfunc main() {}\n```\nmiddle\n~~~\nopen"
+	segments := splitCodeBlocks(content)
+	want := []markdownSegment{
+		{text: "intro"},
+		{text: "```go\nfunc main() {}\n```", code: true, closed: true},
+		{text: "middle"},
+		{text: "~~~\nopen", code: true},
+	}
+	if len(segments) != len(want) {
+		t.Fatalf("got %d segments %+v, want %d", len(segments), segments, len(want))
+	}
+	for i := range want {
+		if segments[i] != want[i] {
+			t.Errorf("segment %d = %+v, want %+v", i, segments[i], want[i])
+		}
+	}
+	if joined := joinSegments(segments); joined != content {
+		t.Errorf("joined segments = %q, want %q", joined, content)
+	}
+}
+
+func TestHideLinkTargets(t *testing.T) {
+	segments := []markdownSegment{
+		{text: `see [the docs](https://example.com "Docs") and ![logo](logo.png)`},
+		{text: "```\n[kept](https://example.com)\n```", code: true, closed: true},
+	}
+	hideLinkTargets(segments)
+	if want := `see [the docs](#) and ![logo](logo.png)`; segments[0].text != want {
+		t.Errorf("prose = %q, want %q", segments[0].text, want)
+	}
+	if want := "```\n[kept](https://example.com)\n```"; segments[1].text != want {
+		t.Errorf("code = %q, want %q", segments[1].text, want)
+	}
+}
@@ -47,21 +47,21 @@ func LoadThemesFromJSON() error {
 			continue
 		}
 		themeName := strings.TrimSuffix(entry.Name(), ".json")
-		data, err := themesFS.ReadFile(path.Join("themes", entry.Name()))
-		if err != nil {
-			return fmt.Errorf("failed to read theme file %s: %w", entry.Name(), err)
-		}
-		theme, err := parseJSONTheme(themeName, data)
-		if err != nil {
-			return fmt.Errorf("failed to parse theme %s: %w", themeName, err)
-		}
-		RegisterTheme(themeName, theme)
+		file := path.Join("themes", entry.Name())
+		RegisterThemeLoader(themeName, func() (Theme, error) {
+			data, err := themesFS.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read theme file %s: %w", file, err)
+			}
+			return parseJSONTheme(themeName, data)
+		})
 	}
 
 	return nil
 }
 
-// LoadThemesFromDirectories loads themes from user directories in the correct override order.
+// LoadThemesFromDirectories registers themes from user directories in the correct override order.
+// Only the directories are read here, each theme file is parsed the first time it is used.
 // The hierarchy is (from lowest to highest priority):
 // 1. Built-in themes (embedded)
 // 2. USER_CONFIG/dgmo/themes/*.json
@@ -107,19 +107,14 @@ func loadThemesFromDirectory(dir string) error {
 		themeName := strings.TrimSuffix(entry.Name(), ".json")
 		filePath := filepath.Join(dir, entry.Name())
 
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			// Skip files that can't be read
-			continue
-		}
-
-		theme, err := parseJSONTheme(themeName, data)
-		if err != nil {
-			// Skip themes that can't be parsed
-			continue
-		}
-
-		RegisterTheme(themeName, theme)
+		// the file is read and parsed the first time the theme is used
+		RegisterThemeLoader(themeName, func() (Theme, error) {
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read theme file %s: %w", filePath, err)
+			}
+			return parseJSONTheme(themeName, data)
+		})
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package theme
 import (
 	"fmt"
 	"image/color"
+	"log/slog"
 	"slices"
 	"strconv"
 	"strings"
@@ -14,10 +15,16 @@ import (
 	"github.com/charmbracelet/x/ansi"
 )
 
+// Loader parses a theme the first time it is used
+type Loader func() (Theme, error)
+
 // Manager handles theme registration, selection, and retrieval.
 // It maintains a registry of available themes and tracks the currently active theme.
+// Themes registered with a loader are parsed on first use.
 type Manager struct {
 	themes               map[string]Theme
+	loaders              map[string]Loader
+	usesAnsi             map[string]bool // whether each parsed theme uses ANSI colors
 	currentName          string
 	currentUsesAnsiCache bool // Cache whether current theme uses ANSI colors
 	mu                   sync.RWMutex
@@ -26,6 +33,8 @@ type Manager struct {
 // Global instance of the theme manager
 var globalManager = &Manager{
 	themes:      make(map[string]Theme),
+	loaders:     make(map[string]Loader),
+	usesAnsi:    make(map[string]bool),
 	currentName: "",
 }
 
@@ -35,12 +44,87 @@ func RegisterTheme(name string, theme Theme) {
 	globalManager.mu.Lock()
 	defer globalManager.mu.Unlock()
 
-	globalManager.themes[name] = theme
+	delete(globalManager.loaders, name)
+	globalManager.store(name, theme)
 
 	// If this is the first theme, make it the default
 	if globalManager.currentName == "" {
 		globalManager.currentName = name
-		globalManager.currentUsesAnsiCache = themeUsesAnsiColors(theme)
+		globalManager.currentUsesAnsiCache = globalManager.usesAnsi[name]
+	}
+}
+
+// RegisterThemeLoader adds a theme that is parsed the first time it is
+// used, replacing any theme registered before under the same name.
+// If this is the first theme registered, it becomes the default.
+func RegisterThemeLoader(name string, load Loader) {
+	globalManager.mu.Lock()
+	defer globalManager.mu.Unlock()
+
+	delete(globalManager.themes, name)
+	delete(globalManager.usesAnsi, name)
+	globalManager.loaders[name] = load
+
+	if globalManager.currentName == "" {
+		globalManager.currentName = name
+	}
+}
+
+// store keeps a parsed theme along with the styles derived from it. The
+// caller holds the lock.
+func (m *Manager) store(name string, theme Theme) {
+	m.themes[name] = theme
+	m.usesAnsi[name] = themeUsesAnsiColors(theme)
+}
+
+// resolve returns a theme, parsing it if it was registered with a loader.
+// The caller holds the write lock.
+func (m *Manager) resolve(name string) Theme {
+	if theme, ok := m.themes[name]; ok {
+		return theme
+	}
+	load, ok := m.loaders[name]
+	if !ok {
+		return nil
+	}
+	delete(m.loaders, name)
+	theme, err := load()
+	if err != nil {
+		slog.Warn("Failed to load theme", "theme", name, "error", err)
+		return nil
+	}
+	m.store(name, theme)
+	if name == m.currentName {
+		m.currentUsesAnsiCache = m.usesAnsi[name]
+	}
+	return theme
+}
+
+// get returns a theme, taking the write lock only when it must be parsed
+func (m *Manager) get(name string) Theme {
+	m.mu.RLock()
+	theme, ok := m.themes[name]
+	m.mu.RUnlock()
+	if ok {
+		return theme
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resolve(name)
+}
+
+// Preload parses every theme that has not been used yet, so previewing
+// themes never waits on parsing. It is safe to call in the background.
+func Preload() {
+	globalManager.mu.RLock()
+	names := make([]string, 0, len(globalManager.loaders))
+	for name := range globalManager.loaders {
+		names = append(names, name)
+	}
+	globalManager.mu.RUnlock()
+
+	for _, name := range names {
+		globalManager.get(name)
 	}
 }
 
@@ -49,15 +133,15 @@ func RegisterTheme(name string, theme Theme) {
 func SetTheme(name string) error {
 	globalManager.mu.Lock()
 	defer globalManager.mu.Unlock()
-	delete(styles.Registry, "charm")
 
-	theme, exists := globalManager.themes[name]
-	if !exists {
+	theme := globalManager.resolve(name)
+	if theme == nil {
 		return fmt.Errorf("theme '%s' not found", name)
 	}
+	delete(styles.Registry, "charm")
 
 	globalManager.currentName = name
-	globalManager.currentUsesAnsiCache = themeUsesAnsiColors(theme)
+	globalManager.currentUsesAnsiCache = globalManager.usesAnsi[name]
 
 	return nil
 }
@@ -66,13 +150,14 @@ func SetTheme(name string) error {
 // If no theme is set, it returns nil.
 func CurrentTheme() Theme {
 	globalManager.mu.RLock()
-	defer globalManager.mu.RUnlock()
+	name := globalManager.currentName
+	globalManager.mu.RUnlock()
 
-	if globalManager.currentName == "" {
+	if name == "" {
 		return nil
 	}
 
-	return globalManager.themes[globalManager.currentName]
+	return globalManager.get(name)
 }
 
 // CurrentThemeName returns the name of the currently active theme.
@@ -88,10 +173,13 @@ func AvailableThemes() []string {
 	globalManager.mu.RLock()
 	defer globalManager.mu.RUnlock()
 
-	names := make([]string, 0, len(globalManager.themes))
+	names := make([]string, 0, len(globalManager.themes)+len(globalManager.loaders))
 	for name := range globalManager.themes {
 		names = append(names, name)
 	}
+	for name := range globalManager.loaders {
+		names = append(names, name)
+	}
 	slices.SortFunc(names, func(a, b string) int {
 		if a == "dgmo" {
 			return -1
@@ -111,10 +199,7 @@ func AvailableThemes() []string {
 // GetTheme returns a specific theme by name.
 // Returns nil if the theme doesn't exist.
 func GetTheme(name string) Theme {
-	globalManager.mu.RLock()
-	defer globalManager.mu.RUnlock()
-
-	return globalManager.themes[name]
+	return globalManager.get(name)
 }
 
 // UpdateSystemTheme updates the system theme with terminal background info
@@ -123,9 +208,10 @@ func UpdateSystemTheme(terminalBg color.Color, isDark bool) {
 	defer globalManager.mu.Unlock()
 
 	dynamicTheme := NewSystemTheme(terminalBg, isDark)
-	globalManager.themes["system"] = dynamicTheme
+	globalManager.store("system", dynamicTheme)
 	if globalManager.currentName == "system" {
-		globalManager.currentUsesAnsiCache = themeUsesAnsiColors(dynamicTheme)
+		delete(styles.Registry, "charm")
+		globalManager.currentUsesAnsiCache = globalManager.usesAnsi["system"]
 	}
 }
 
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+)
+
+// renderPerfHUD draws render timings and cache metrics in the bottom right
+// corner of view, above the editor
+func (a *appModel) renderPerfHUD(view string) string {
+	if !a.showPerfHUD {
+		return view
+	}
+	stats := a.messages.RenderStats()
+	markdown := styles.GetMarkdownCacheStats()
+
+	lines := []string{
+		fmt.Sprintf("render    %dms, %d messages", stats.Duration.Milliseconds(), stats.Rendered),
+		"messages  " + stats.Cache.String(),
+	}
+	widths := make([]int, 0, len(stats.Cache.Widths))
+	for width := range stats.Cache.Widths {
+		widths = append(widths, width)
+	}
+	slices.Sort(widths)
+	for _, width := range widths {
+		lines = append(lines, fmt.Sprintf("  width %-4d%d/%d", width, stats.Cache.Widths[width], stats.Cache.Capacity))
+	}
+	lines = append(lines, "markdown  "+markdown.String())
+
+	t := theme.CurrentTheme()
+	hud := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundPanel()).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+	x := max(0, lipgloss.Width(view)-lipgloss.Width(hud)-2)
+	y := max(0, a.height-5-lipgloss.Height(hud)-1)
+	return layout.PlaceOverlay(x, y, hud, view)
+}
@@ -0,0 +1,336 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/components/chat"
+	"github.com/sst/dgmo/internal/components/dialog"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// keyRoute is a scope of key handling. handle reports whether it took the
+// key, the scopes after it never see a key that was taken.
+type keyRoute struct {
+	scope  string
+	handle func(a *appModel, msg tea.KeyPressMsg) (tea.Cmd, bool)
+}
+
+// keyRoutes are the scopes a key press goes through, in order
+var keyRoutes = []keyRoute{
+	// only the top modal gets the keys, esc closes it
+	{"modal", (*appModel).routeModal},
+	// the read-only viewer has no editor to type into
+	{"viewer", (*appModel).routeViewer},
+	{"paste", (*appModel).routePasteBurst},
+	{"alt screen", (*appModel).routeAltScreen},
+	// the key after the leader, and the key after a chord prefix
+	{"leader command", (*appModel).routeLeaderSequence},
+	{"chord", (*appModel).routeChord},
+	{"completions", (*appModel).routeCompletions},
+	{"slash arguments", (*appModel).routeSlashArgs},
+	// start screen shortcuts and tab switching with ctrl+1..9
+	{"shortcuts", (*appModel).routeShortcuts},
+	// printable characters go straight to the editor, for responsiveness
+	{"editor", (*appModel).routeEditorText},
+	{"leader", (*appModel).routeLeader},
+	{"interrupt", (*appModel).routeInterrupt},
+	{"command", (*appModel).routeCommands},
+	{"chord", (*appModel).routeChordPrefix},
+	// backspace, arrows and the other keys without text
+	{"editor", (*appModel).routeEditor},
+}
+
+// routeKey hands a key press to the first scope that takes it
+func (a *appModel) routeKey(msg tea.KeyPressMsg) tea.Cmd {
+	for _, route := range keyRoutes {
+		if cmd, ok := route.handle(a, msg); ok {
+			a.tracer.Consume(route.scope)
+			return cmd
+		}
+	}
+	return nil
+}
+
+// keyChord is a prefix key that waits for a second key. The prefix is a
+// chord while when holds, start runs when it is pressed and reports whether
+// to wait; any key not in keys cancels the chord.
+type keyChord struct {
+	prefix string
+	when   func(a *appModel) bool
+	start  func(a *appModel) (tea.Cmd, bool)
+	keys   map[string]func(a *appModel) tea.Cmd
+}
+
+// keyChords are the chords of the editor screen
+var keyChords = []keyChord{
+	{
+		// ctrl+b returns to the parent session or the last viewed sub-session,
+		// and otherwise waits for . or , to move between sibling sub-sessions
+		prefix: "ctrl+b",
+		when:   func(a *appModel) bool { return a.app.Session != nil },
+		start:  (*appModel).startSubSessionChord,
+		keys: map[string]func(a *appModel) tea.Cmd{
+			".": func(a *appModel) tea.Cmd { return a.navigateToSibling(context.Background(), "next") },
+			",": func(a *appModel) tea.Cmd { return a.navigateToSibling(context.Background(), "prev") },
+		},
+	},
+}
+
+func (a *appModel) routeModal(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if len(a.modals) == 0 {
+		return nil, false
+	}
+	switch msg.String() {
+	// Escape closes the top modal, revealing the one below
+	case "esc", "ctrl+c":
+		return a.closeModal(), true
+	}
+	return a.updateModals(msg), true
+}
+
+func (a *appModel) routeViewer(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if !a.app.ViewOnly() {
+		return nil, false
+	}
+	model, cmd := a.updateViewer(msg)
+	*a = model.(appModel)
+	return cmd, true
+}
+
+// routePasteBurst takes keys arriving faster than anyone types, a paste in
+// a terminal without bracketed paste, as text and never as commands
+func (a *appModel) routePasteBurst(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	burst := time.Since(a.lastKeyPress) < pasteBurstInterval
+	a.lastKeyPress = time.Now()
+	if !burst || a.showCompletionDialog {
+		return nil, false
+	}
+	return a.pasteKey(msg), true
+}
+
+func (a *appModel) routeAltScreen(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if msg.String() != "shift+tab" {
+		return nil, false
+	}
+	a.isAltScreen = !a.isAltScreen
+	cmd, toastMsg := tea.EnterAltScreen, "Fullscreen mode enabled"
+	if !a.isAltScreen {
+		cmd, toastMsg = tea.ExitAltScreen, "Fullscreen mode disabled"
+	}
+	// the other screen may hold stale output and a different size, so clear
+	// it and lay everything out again once the size is in
+	return tea.Batch(
+		tea.Sequence(cmd, tea.ClearScreen, tea.RequestWindowSize),
+		toast.NewInfoToast(toastMsg),
+	), true
+}
+
+// routeLeaderSequence runs the command bound to the key after the leader.
+// Digits without a binding of their own are a count, which a command with
+// a count argument gets, e.g. <leader>2` copies the second code block. A
+// key without a command ends the sequence and goes on to the other scopes.
+func (a *appModel) routeLeaderSequence(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if !a.isLeaderSequence {
+		return nil, false
+	}
+	a.isLeaderSequence = false
+	count := a.leaderCount
+	a.leaderCount = 0
+	matches := a.app.Commands.Matches(msg, true)
+	if len(matches) == 0 {
+		if digit, err := strconv.Atoi(msg.String()); err == nil && digit >= 0 && digit <= 9 && count+digit > 0 {
+			a.leaderCount = count*10 + digit
+			a.isLeaderSequence = true
+			return nil, true
+		}
+		return nil, false
+	}
+	if count > 0 {
+		for _, command := range matches {
+			if len(command.Args) > 0 && command.Args[0].Count {
+				return util.CmdHandler(commands.SlashCommandMsg{Command: command, Args: []string{strconv.Itoa(count)}}), true
+			}
+		}
+	}
+	return util.CmdHandler(commands.ExecuteCommandsMsg(matches)), true
+}
+
+// routeChord finishes a pending chord, any key other than its own cancels it
+func (a *appModel) routeChord(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if a.chord == nil {
+		return nil, false
+	}
+	chord := a.chord
+	a.chord = nil
+	if run, ok := chord.keys[msg.String()]; ok {
+		return run(a), true
+	}
+	return nil, true
+}
+
+func (a *appModel) routeChordPrefix(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	for i, chord := range keyChords {
+		if chord.prefix != msg.String() || !chord.when(a) {
+			continue
+		}
+		cmd, wait := chord.start(a)
+		if wait {
+			a.chord = &keyChords[i]
+		}
+		return cmd, true
+	}
+	return nil, false
+}
+
+// startSubSessionChord jumps between a session and its sub-sessions right
+// away when it can, or waits for the sibling to move to
+func (a *appModel) startSubSessionChord() (tea.Cmd, bool) {
+	if a.app.CurrentSessionType == "sub" && a.app.Session.ParentID != "" {
+		return a.app.SwitchToSession(context.Background(), a.app.Session.ParentID), false
+	}
+	if a.app.CurrentSessionType == "main" && a.app.LastViewedSubSession != "" {
+		return a.app.SwitchToSession(context.Background(), a.app.LastViewedSubSession), false
+	}
+	return toast.NewInfoToast("Press . for next or , for previous sibling"), true
+}
+
+// routeCompletions opens the completion dialog on / or the trigger of a
+// completion provider, and feeds it the keys while it is open
+func (a *appModel) routeCompletions(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	keyString := msg.String()
+	var cmds []tea.Cmd
+	if (keyString == "/" || a.completionTrigger(msg)) && !a.showCompletionDialog {
+		a.showCompletionDialog = true
+
+		// without a space before the cursor the word typed so far is part of
+		// the completion (ie, `packages/`)
+		initialValue := keyString
+		currentInput := a.editor.Value()
+		if keyString == "/" && !strings.HasSuffix(currentInput, " ") {
+			words := strings.Split(currentInput, " ")
+			initialValue = strings.TrimSpace(words[len(words)-1]) + "/"
+		}
+
+		updated, cmd := a.completions.Update(app.CompletionDialogTriggeredMsg{InitialValue: initialValue})
+		a.completions = updated.(dialog.CompletionDialog)
+		cmds = append(cmds, cmd)
+
+		updated, cmd = a.editor.Update(msg)
+		a.editor = updated.(chat.EditorComponent)
+		cmds = append(cmds, cmd)
+
+		updated, cmd = a.updateCompletions(msg)
+		a.completions = updated.(dialog.CompletionDialog)
+		cmds = append(cmds, cmd)
+		return tea.Sequence(cmds...), true
+	}
+	if !a.showCompletionDialog {
+		return nil, false
+	}
+
+	switch keyString {
+	case "tab", "enter", "esc", "ctrl+c":
+		updated, cmd := a.updateCompletions(msg)
+		a.completions = updated.(dialog.CompletionDialog)
+		return cmd, true
+	}
+	updated, cmd := a.editor.Update(msg)
+	a.editor = updated.(chat.EditorComponent)
+	cmds = append(cmds, cmd)
+
+	updated, cmd = a.updateCompletions(msg)
+	a.completions = updated.(dialog.CompletionDialog)
+	cmds = append(cmds, cmd)
+	return tea.Batch(cmds...), true
+}
+
+// completionTrigger reports whether a key starts a word with the trigger of
+// a completion provider, @ for files, # for git refs, $ for variables or
+// the trigger of a plugin
+func (a *appModel) completionTrigger(msg tea.KeyPressMsg) bool {
+	if msg.Text == "" {
+		return false
+	}
+	input := a.editor.Value()
+	if input != "" && !strings.HasSuffix(input, " ") && !strings.HasSuffix(input, "\n") {
+		return false
+	}
+	return a.completionManager.IsTrigger(msg.Text)
+}
+
+func (a *appModel) routeSlashArgs(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if msg.String() != "tab" || !strings.HasPrefix(a.editor.Value(), "/") {
+		return nil, false
+	}
+	return a.completeSlashArgs(a.editor.Value()), true
+}
+
+func (a *appModel) routeShortcuts(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if a.app.Session == nil || a.app.Session.ID == "" {
+		if cmd := a.home.Shortcut(msg.String()); cmd != nil {
+			return cmd, true
+		}
+	}
+	if i, ok := tabShortcut(msg.String()); ok {
+		return a.switchTab(i), true
+	}
+	return nil, false
+}
+
+func (a *appModel) routeEditorText(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if msg.Text == "" {
+		return nil, false
+	}
+	return a.routeEditor(msg)
+}
+
+func (a *appModel) routeLeader(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if a.leaderBinding == nil || !key.Matches(msg, *a.leaderBinding) {
+		return nil, false
+	}
+	a.isLeaderSequence = true
+	a.leaderCount = 0
+	return nil, true
+}
+
+// routeInterrupt takes the interrupt key while the assistant is busy. The
+// first press arms it and only a second one within the debounce timeout
+// interrupts, so a stray press does not throw a response away.
+func (a *appModel) routeInterrupt(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	interruptCommand := a.app.Commands[commands.SessionInterruptCommand]
+	if !a.app.IsBusy() || !interruptCommand.Matches(msg, false) {
+		return nil, false
+	}
+	if a.interruptKeyState == InterruptKeyFirstPress {
+		a.interruptKeyState = InterruptKeyIdle
+		a.editor.SetInterruptKeyInDebounce(false)
+		return util.CmdHandler(commands.ExecuteCommandMsg(interruptCommand)), true
+	}
+	a.interruptKeyState = InterruptKeyFirstPress
+	a.editor.SetInterruptKeyInDebounce(true)
+	return tea.Tick(interruptDebounceTimeout, func(t time.Time) tea.Msg {
+		return InterruptDebounceTimeoutMsg{}
+	}), true
+}
+
+func (a *appModel) routeCommands(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	matches := a.app.Commands.Matches(msg, false)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return util.CmdHandler(commands.ExecuteCommandsMsg(matches)), true
+}
+
+func (a *appModel) routeEditor(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	updated, cmd := a.editor.Update(msg)
+	a.editor = updated.(chat.EditorComponent)
+	return cmd, true
+}
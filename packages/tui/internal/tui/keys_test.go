@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/completions"
+	"github.com/sst/dgmo/internal/components/chat"
+	"github.com/sst/dgmo/internal/logging"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/opencode-sdk-go"
+)
+
+// fakeEditor records the keys that reach the editor
+type fakeEditor struct {
+	chat.EditorComponent
+	keys []string
+}
+
+func (e *fakeEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		e.keys = append(e.keys, msg.String())
+	}
+	return e, nil
+}
+
+func (e *fakeEditor) Value() string                    { return "" }
+func (e *fakeEditor) SetInterruptKeyInDebounce(_ bool) {}
+
+// fakeModal records the keys that reach a modal
+type fakeModal struct {
+	keys   []string
+	closed bool
+}
+
+func (m *fakeModal) Init() tea.Cmd { return nil }
+
+func (m *fakeModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		m.keys = append(m.keys, msg.String())
+	}
+	return m, nil
+}
+
+func (m *fakeModal) View() string                    { return "" }
+func (m *fakeModal) Render(background string) string { return background }
+
+func (m *fakeModal) Close() tea.Cmd {
+	m.closed = true
+	return nil
+}
+
+func newKeyTestModel() *appModel {
+	a := &app.App{
+		Commands: commands.LoadFromConfig(&opencode.Config{}),
+		Session:  &opencode.Session{ID: "ses_1"},
+	}
+	return &appModel{
+		app:               a,
+		editor:            &fakeEditor{},
+		completionManager: completions.NewCompletionManager(a),
+		leaderBinding:     newLeaderBinding("ctrl+x"),
+		tracer:            logging.NewTracer(false),
+	}
+}
+
+// routeTo runs a key through the scopes like routeKey and returns the scope
+// that took it with its command
+func routeTo(a *appModel, msg tea.KeyPressMsg) (string, tea.Cmd) {
+	// keys following each other this fast would be taken for a paste
+	a.lastKeyPress = a.lastKeyPress.AddDate(-1, 0, 0)
+	for _, route := range keyRoutes {
+		if cmd, ok := route.handle(a, msg); ok {
+			return route.scope, cmd
+		}
+	}
+	return "", nil
+}
+
+// describe names the commands the message of cmd runs
+func describe(cmd tea.Cmd) string {
+	if cmd == nil {
+		return ""
+	}
+	msg := cmd()
+	names := func(cmds []commands.Command) string {
+		var names []string
+		for _, command := range cmds {
+			names = append(names, string(command.Name))
+		}
+		return strings.Join(names, ",")
+	}
+	switch msg := msg.(type) {
+	case commands.ExecuteCommandsMsg:
+		return names(msg)
+	case commands.ExecuteCommandMsg:
+		return string(msg.Name)
+	case commands.SlashCommandMsg:
+		return fmt.Sprintf("%s %v", msg.Command.Name, msg.Args)
+	}
+	return fmt.Sprintf("%T", msg)
+}
+
+func keyPress(s string) tea.KeyPressMsg {
+	switch s {
+	case "enter":
+		return tea.KeyPressMsg{Code: tea.KeyEnter}
+	case "esc":
+		return tea.KeyPressMsg{Code: tea.KeyEscape}
+	case "shift+tab":
+		return tea.KeyPressMsg{Code: tea.KeyTab, Mod: tea.ModShift}
+	}
+	if ctrl, ok := strings.CutPrefix(s, "ctrl+"); ok {
+		return tea.KeyPressMsg{Code: rune(ctrl[0]), Mod: tea.ModCtrl}
+	}
+	return tea.KeyPressMsg{Code: rune(s[0]), Text: s}
+}
+
+func TestRouteKey(t *testing.T) {
+	type step struct {
+		key, scope, result string
+	}
+	tests := []struct {
+		name  string
+		setup func(a *appModel)
+		steps []step
+		// editor and modal are the keys that should reach them, a result of
+		// * is not checked
+		editor []string
+		modal  []string
+	}{
+		{
+			name: "leader sequence",
+			steps: []step{
+				{"ctrl+x", "leader", ""},
+				{"n", "leader command", "session_new"},
+			},
+		},
+		{
+			name: "a leader count goes to a command with a count",
+			steps: []step{
+				{"ctrl+x", "leader", ""},
+				{"1", "leader command", ""},
+				{"2", "leader command", ""},
+				{"`", "leader command", "messages_copy_code [12]"},
+			},
+		},
+		{
+			name: "a leading zero is no count",
+			steps: []step{
+				{"ctrl+x", "leader", ""},
+				{"0", "editor", ""},
+			},
+			editor: []string{"0"},
+		},
+		{
+			name: "a key without a leader command ends the sequence",
+			steps: []step{
+				{"ctrl+x", "leader", ""},
+				{"!", "editor", ""},
+				{"n", "editor", ""},
+			},
+			editor: []string{"!", "n"},
+		},
+		{
+			name: "text goes to the editor before key commands",
+			steps: []step{
+				{"n", "editor", ""},
+				{"enter", "command", "input_submit"},
+				{"ctrl+p", "command", "command_palette"},
+			},
+			editor: []string{"n"},
+		},
+		{
+			name:  "a modal takes every key before the leader and the editor",
+			setup: func(a *appModel) { a.pushModal(&fakeModal{}) },
+			steps: []step{
+				{"ctrl+x", "modal", ""},
+				{"n", "modal", ""},
+				{"enter", "modal", ""},
+				{"esc", "modal", ""},
+				{"ctrl+x", "leader", ""},
+			},
+			modal: []string{"ctrl+x", "n", "enter"},
+		},
+		{
+			name: "a modal takes the key after the leader",
+			setup: func(a *appModel) {
+				a.isLeaderSequence = true
+				a.pushModal(&fakeModal{})
+			},
+			steps: []step{
+				{"n", "modal", ""},
+				{"esc", "modal", ""},
+			},
+			modal: []string{"n"},
+		},
+		{
+			name:  "esc arms the interrupt while busy, a second one interrupts",
+			setup: func(a *appModel) { a.app.Messages = []opencode.Message{{}} },
+			steps: []step{
+				// a tick that ends the debounce
+				{"esc", "interrupt", "*"},
+				{"esc", "interrupt", "session_interrupt"},
+			},
+		},
+		{
+			name: "the chord prefix waits for its key",
+			setup: func(a *appModel) {
+				a.app.CurrentSessionType = "main"
+			},
+			steps: []step{
+				{"ctrl+b", "chord", "toast.ShowToastMsg"},
+				{"z", "chord", ""},
+				{"z", "editor", ""},
+			},
+			editor: []string{"z"},
+		},
+		{
+			name: "shift+tab switches the screen before the editor sees it",
+			steps: []step{
+				{"shift+tab", "alt screen", "tea.BatchMsg"},
+			},
+		},
+	}
+	if err := theme.LoadThemesFromJSON(); err != nil {
+		t.Fatal(err)
+	}
+	if err := theme.SetTheme("ayu"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newKeyTestModel()
+			var modal *fakeModal
+			if tc.setup != nil {
+				tc.setup(a)
+				if top, ok := a.topModal().(*fakeModal); ok {
+					modal = top
+				}
+			}
+			for i, s := range tc.steps {
+				scope, cmd := routeTo(a, keyPress(s.key))
+				result := "*"
+				if s.result != "*" {
+					result = describe(cmd)
+				}
+				if scope != s.scope || result != s.result {
+					t.Errorf("key %d %q went to %q with %q, want %q with %q", i, s.key, scope, result, s.scope, s.result)
+				}
+			}
+			if keys := a.editor.(*fakeEditor).keys; strings.Join(keys, " ") != strings.Join(tc.editor, " ") {
+				t.Errorf("editor got %v, want %v", keys, tc.editor)
+			}
+			if modal != nil {
+				if strings.Join(modal.keys, " ") != strings.Join(tc.modal, " ") || !modal.closed {
+					t.Errorf("modal got %v, closed %v, want %v and closed", modal.keys, modal.closed, tc.modal)
+				}
+			}
+		})
+	}
+}
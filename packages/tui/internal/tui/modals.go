@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/sst/dgmo/internal/components/dialog"
+	"github.com/sst/dgmo/internal/components/modal"
+	"github.com/sst/dgmo/internal/layout"
+)
+
+// topModal returns the modal that has focus, nil when none is open
+func (a *appModel) topModal() layout.Modal {
+	if len(a.modals) == 0 {
+		return nil
+	}
+	return a.modals[len(a.modals)-1]
+}
+
+// openModal replaces the open modals with m. Permission prompts waiting for
+// an answer stay on top of it.
+func (a *appModel) openModal(m layout.Modal) tea.Cmd {
+	cmd := a.closeModals()
+	a.modals = append([]layout.Modal{m}, a.modals...)
+	return cmd
+}
+
+// pushModal opens m on top of the open modals, which keep their state and
+// get the focus back when m is closed
+func (a *appModel) pushModal(m layout.Modal) {
+	a.modals = append(a.modals, m)
+}
+
+// closeModal closes the modal that has focus
+func (a *appModel) closeModal() tea.Cmd {
+	top := a.topModal()
+	if top == nil {
+		return nil
+	}
+	a.modals = a.modals[:len(a.modals)-1]
+	return top.Close()
+}
+
+// closeModals closes every open modal, top first, except the permission
+// prompts waiting for an answer. Closing one would deny its tool call.
+func (a *appModel) closeModals() tea.Cmd {
+	var cmds []tea.Cmd
+	var pending []layout.Modal
+	for len(a.modals) > 0 {
+		if prompt, ok := a.topModal().(dialog.PermissionDialog); ok && prompt.Pending() {
+			pending = append(pending, prompt)
+			a.modals = a.modals[:len(a.modals)-1]
+			continue
+		}
+		cmds = append(cmds, a.closeModal())
+	}
+	slices.Reverse(pending)
+	a.modals = pending
+	return tea.Batch(cmds...)
+}
+
+// updateModals delivers msg to the open modals. Input only reaches the top
+// one, everything else reaches all of them so the results of work started
+// by a covered modal are not lost.
+func (a *appModel) updateModals(msg tea.Msg) tea.Cmd {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.PasteMsg, tea.MouseMsg:
+		top := a.topModal()
+		if top == nil {
+			return nil
+		}
+		a.tracer.Consume(fmt.Sprintf("%T", top))
+		updated, cmd := top.Update(msg)
+		a.modals[len(a.modals)-1] = updated.(layout.Modal)
+		return cmd
+	}
+
+	var cmds []tea.Cmd
+	for i, m := range a.modals {
+		updated, cmd := m.Update(msg)
+		a.modals[i] = updated.(layout.Modal)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// renderModals draws the open modals bottom to top, dimming what is below
+// each modal that is stacked on another
+func (a *appModel) renderModals(background string) string {
+	for i, m := range a.modals {
+		if i > 0 {
+			background = modal.Dim(background)
+		}
+		background = m.Render(background)
+	}
+	return background
+}
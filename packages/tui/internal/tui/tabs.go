@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/components/chat"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/notify"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+	"github.com/sst/opencode-sdk-go"
+)
+
+const (
+	// maxTabs is how many sessions can be open at once, ctrl+1..9 reach
+	// every one of them
+	maxTabs = 9
+	// maxTabTitle is the width of a tab title in the tab bar
+	maxTabTitle = 24
+)
+
+// sessionTab is a session kept open in a tab. The state of the active tab
+// lives in the app and the model, the fields hold it while the tab is in
+// the background.
+type sessionTab struct {
+	session  *opencode.Session
+	messages []opencode.Message
+	bundle   *app.SessionBundle
+	draft    string
+	// view keeps the renders and the scroll position of the transcript, so
+	// switching back neither fetches nor renders everything again
+	view chat.MessagesComponent
+}
+
+// title names the session of a tab in the tab bar
+func (t *sessionTab) title() string {
+	if t.session == nil || t.session.ID == "" {
+		return "new session"
+	}
+	if t.session.Title == "" {
+		return t.session.ID
+	}
+	return t.session.Title
+}
+
+// tabShortcut returns the tab ctrl+1..9 selects
+func tabShortcut(keyString string) (int, bool) {
+	digit, ok := strings.CutPrefix(keyString, "ctrl+")
+	if !ok || len(digit) != 1 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digit)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+// storeTab moves the state of the active tab out of the app into its tab
+func (a *appModel) storeTab() {
+	tab := a.tabs[a.activeTab]
+	tab.session = a.app.Session
+	tab.messages = a.app.Messages
+	tab.bundle = a.app.Bundle
+	tab.draft = a.editor.Value()
+	tab.view = a.messages
+}
+
+// loadTab makes tab i the active one
+func (a *appModel) loadTab(i int) tea.Cmd {
+	tab := a.tabs[i]
+	a.activeTab = i
+	a.app.Session = tab.session
+	if a.app.Session == nil {
+		a.app.Session = &opencode.Session{}
+	}
+	a.app.Messages = tab.messages
+	a.app.Bundle = tab.bundle
+	a.messages = tab.view
+	a.editor.SetValue(tab.draft)
+	a.applyLayout()
+
+	cmds := []tea.Cmd{
+		util.CmdHandler(chat.TranscriptShownMsg{}),
+		util.CmdHandler(windowTitleMsg{}),
+	}
+	if a.app.Session.ID != "" {
+		a.app.Activity.MarkRead(a.app.Session.ID)
+	}
+	return tea.Batch(cmds...)
+}
+
+// switchTab activates tab i
+func (a *appModel) switchTab(i int) tea.Cmd {
+	if i < 0 || i >= len(a.tabs) {
+		return toast.NewInfoToast(fmt.Sprintf("There is no tab %d", i+1))
+	}
+	if i == a.activeTab {
+		return nil
+	}
+	a.storeTab()
+	return a.loadTab(i)
+}
+
+// openTab opens a tab on the start screen and activates it
+func (a *appModel) openTab() tea.Cmd {
+	if len(a.tabs) >= maxTabs {
+		return toast.NewWarningToast(fmt.Sprintf("At most %d tabs can be open", maxTabs))
+	}
+	a.storeTab()
+	a.tabs = append(a.tabs, &sessionTab{
+		session: &opencode.Session{},
+		view:    chat.NewMessagesComponent(a.app),
+	})
+	return tea.Batch(a.loadTab(len(a.tabs)-1), util.CmdHandler(app.SessionClearedMsg{}))
+}
+
+// closeTab closes the active tab and activates its left neighbour
+func (a *appModel) closeTab() tea.Cmd {
+	if len(a.tabs) == 1 {
+		return toast.NewInfoToast("The last tab stays open")
+	}
+	a.tabs = append(a.tabs[:a.activeTab], a.tabs[a.activeTab+1:]...)
+	return a.loadTab(max(0, a.activeTab-1))
+}
+
+// tabOf returns the tab a session is open in, or -1
+func (a *appModel) tabOf(sessionID string) int {
+	for i, tab := range a.tabs {
+		session := tab.session
+		if i == a.activeTab {
+			session = a.app.Session
+		}
+		if session != nil && session.ID == sessionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateBackgroundTabs keeps the sessions of background tabs current, so
+// they show the latest messages when switched to
+func (a *appModel) updateBackgroundTabs(msg tea.Msg) {
+	for i, tab := range a.tabs {
+		if i == a.activeTab || tab.session == nil || tab.session.ID == "" {
+			continue
+		}
+		switch msg := msg.(type) {
+		case opencode.EventListResponseEventMessageUpdated:
+			if msg.Properties.Info.Metadata.SessionID != tab.session.ID {
+				continue
+			}
+			replaced := false
+			for j, message := range tab.messages {
+				if message.ID == msg.Properties.Info.ID {
+					tab.messages[j] = msg.Properties.Info
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				tab.messages = append(tab.messages, msg.Properties.Info)
+			}
+		case opencode.EventListResponseEventSessionUpdated:
+			if msg.Properties.Info.ID == tab.session.ID {
+				session := msg.Properties.Info
+				tab.session = &session
+			}
+		case opencode.EventListResponseEventSessionDeleted:
+			if msg.Properties.Info.ID == tab.session.ID {
+				tab.session = &opencode.Session{}
+				tab.messages = nil
+			}
+		}
+	}
+}
+
+// tabBarHeight is the height the tab bar takes, which is only shown with
+// more than one tab open
+func (a appModel) tabBarHeight() int {
+	if len(a.tabs) > 1 {
+		return 1
+	}
+	return 0
+}
+
+// renderTabBar draws a line with the open tabs, the active one highlighted
+func (a appModel) renderTabBar() string {
+	t := theme.CurrentTheme()
+	inactive := styles.NewStyle().
+		Foreground(t.TextMuted()).
+		Background(t.BackgroundPanel()).
+		Padding(0, 1)
+	active := inactive.Foreground(t.Text()).Background(t.BackgroundElement()).Bold(true)
+
+	var tabs []string
+	for i, tab := range a.tabs {
+		session := tab.session
+		if i == a.activeTab {
+			session = a.app.Session
+		}
+		title := (&sessionTab{session: session}).title()
+		style := inactive
+		if i == a.activeTab {
+			style = active
+		} else if session != nil && a.app.Activity.Unread(session.ID) > 0 {
+			title += " •"
+		}
+		title = truncate.StringWithTail(title, maxTabTitle, "…")
+		if idle := a.idleFor(session); idle > 0 {
+			title += " idle " + formatIdle(idle)
+		}
+		tabs = append(tabs, style.Render(fmt.Sprintf("%d %s", i+1, title)))
+	}
+	bar := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+	return lipgloss.PlaceHorizontal(a.width, lipgloss.Left, bar, styles.WhitespaceStyle(t.Background()))
+}
+
+// idleFor returns how long a session has been waiting for a reply, once that
+// is longer than the reminder threshold, and 0 otherwise
+func (a appModel) idleFor(session *opencode.Session) time.Duration {
+	after := a.app.IdleReminderAfter()
+	if session == nil || session.ID == "" || after <= 0 {
+		return 0
+	}
+	since, ok := a.app.Activity.IdleSince(session.ID)
+	if !ok || time.Since(since) < after {
+		return 0
+	}
+	return time.Since(since)
+}
+
+// formatIdle shows a wait as 12m or 3h
+func formatIdle(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
+
+// remindIdle sends a reminder for the sessions that have been waiting for a
+// reply too long. The open session is only reminded of while the terminal
+// is not focused.
+func (a appModel) remindIdle() tea.Cmd {
+	after := a.app.IdleReminderAfter()
+	if after <= 0 {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, id := range a.app.Activity.DueReminders(after, time.Now()) {
+		title := id
+		if a.app.Session != nil && a.app.Session.ID == id {
+			if a.isFocused {
+				continue
+			}
+			title = (&sessionTab{session: a.app.Session}).title()
+		} else if i := a.tabOf(id); i >= 0 {
+			title = a.tabs[i].title()
+		}
+		cmds = append(cmds, a.app.Notify(notify.Notification{
+			Title: "Waiting for your reply",
+			Body:  fmt.Sprintf("%s has been idle for %s", title, formatIdle(after)),
+		}, app.NotificationNormal))
+	}
+	return tea.Batch(cmds...)
+}
@@ -2,27 +2,40 @@ package tui
 
 import (
 	"context"
+	"crypto"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 
 	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/bugreport"
 	"github.com/sst/dgmo/internal/commands"
 	"github.com/sst/dgmo/internal/completions"
 	"github.com/sst/dgmo/internal/components/chat"
-	cmdcomp "github.com/sst/dgmo/internal/components/commands"
 	"github.com/sst/dgmo/internal/components/dialog"
+	"github.com/sst/dgmo/internal/components/flash"
+	"github.com/sst/dgmo/internal/components/home"
 	"github.com/sst/dgmo/internal/components/modal"
 	"github.com/sst/dgmo/internal/components/status"
 	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/components/tutorial"
+	"github.com/sst/dgmo/internal/config"
 	"github.com/sst/dgmo/internal/layout"
+	"github.com/sst/dgmo/internal/logging"
+	"github.com/sst/dgmo/internal/notify"
+	"github.com/sst/dgmo/internal/paths"
+	"github.com/sst/dgmo/internal/recorder"
 	"github.com/sst/dgmo/internal/styles"
 	"github.com/sst/dgmo/internal/theme"
 	"github.com/sst/dgmo/internal/util"
@@ -32,6 +45,14 @@ import (
 // InterruptDebounceTimeoutMsg is sent when the interrupt key debounce timeout expires
 type InterruptDebounceTimeoutMsg struct{}
 
+// slashArgsCompletedMsg carries the result of completing a slash command
+// argument, it is dropped if the input changed in the meantime
+type slashArgsCompletedMsg struct {
+	input     string
+	completed string
+	matches   []string
+}
+
 // InterruptKeyState tracks the state of interrupt key presses for debouncing
 type InterruptKeyState int
 
@@ -42,23 +63,63 @@ const (
 
 const interruptDebounceTimeout = 1 * time.Second
 
+// sessionFlushMsg delivers the session updates collected since the last flush
+type sessionFlushMsg struct{}
+
+// draftRestoredMsg puts a cleared input back when its clearing is undone
+type draftRestoredMsg struct {
+	text string
+}
+
+// windowTitleMsg brings the terminal title up to date
+type windowTitleMsg struct{}
+
+// windowTitleInterval is how often the terminal title is refreshed
+const windowTitleInterval = time.Second
+
+// pasteBurstInterval is the gap between key presses below which they are
+// taken for pasted text. Typing and key repeat are several times slower.
+const pasteBurstInterval = 5 * time.Millisecond
+
+// dirtyConfirmTimeout is how long a warned action can be confirmed by
+// running it again
+const dirtyConfirmTimeout = 10 * time.Second
+
 type appModel struct {
 	width, height        int
 	app                  *app.App
-	modal                layout.Modal
+	modals               []layout.Modal // open modals, the last one has focus
 	status               status.StatusComponent
 	editor               chat.EditorComponent
 	messages             chat.MessagesComponent
+	home                 home.HomeComponent
 	completions          dialog.CompletionDialog
 	completionManager    *completions.CompletionManager
 	showCompletionDialog bool
+	showPerfHUD          bool
 	leaderBinding        *key.Binding
 	isLeaderSequence     bool
+	leaderCount          int // typed after the leader, see routeLeaderSequence
 	toastManager         *toast.ToastManager
+	flash                flash.FlashComponent
+	tutorial             tutorial.TutorialComponent
 	interruptKeyState    InterruptKeyState
 	lastScroll           time.Time
-	isCtrlBSequence      bool // Track if Ctrl+B was pressed for multi-key sequences
-	isAltScreen          bool // Track alternate screen state - starts false
+	lastInput            time.Time // last key press, alerts only sound once it is a while ago
+	lastKeyPress         time.Time // detects pastes in terminals without bracketed paste
+	chord                *keyChord // the chord waiting for its second key
+	isAltScreen          bool      // Track alternate screen state - starts false
+	isFocused            bool      // Track terminal focus for desktop notifications
+	recorder             *recorder.Recorder
+	tracer               *logging.Tracer
+	pendingSessions      map[string]opencode.Session // session updates waiting for the next flush
+	unreachable          *opencode.Session           // the last session whose messages failed to load
+	continuation         chan tea.Msg                // progress of the running /continue request
+	cancelContinuation   context.CancelFunc
+	windowTitle          string        // last title set on the terminal
+	confirmInitUntil     time.Time     // init runs despite a dirty tree until then
+	tabs                 []*sessionTab // open sessions, the active one's state lives in the app
+	activeTab            int
 }
 
 func (a appModel) Init() tea.Cmd {
@@ -69,11 +130,26 @@ func (a appModel) Init() tea.Cmd {
 		cmds = append(cmds, tea.RequestBackgroundColor)
 	}
 	cmds = append(cmds, a.app.InitializeProvider())
+	cmds = append(cmds, a.app.WatchProjectConfig())
 	cmds = append(cmds, a.editor.Init())
 	cmds = append(cmds, a.messages.Init())
+	cmds = append(cmds, a.home.Init())
 	cmds = append(cmds, a.status.Init())
 	cmds = append(cmds, a.completions.Init())
 	cmds = append(cmds, a.toastManager.Init())
+	cmds = append(cmds, util.CmdHandler(windowTitleMsg{}))
+	cmds = append(cmds, a.app.RefreshGit())
+	cmds = append(cmds, a.app.TickToolTimers())
+	if a.app.ViewOnly() {
+		cmds = append(cmds, a.app.OpenView(context.Background()))
+		return tea.Batch(cmds...)
+	}
+	cmds = append(cmds, a.app.CheckLastSession())
+	cmds = append(cmds, a.app.LoadPlugins())
+	cmds = append(cmds, a.app.SyncWorkspaceTrust())
+	if !a.app.TrustDecided() {
+		cmds = append(cmds, util.CmdHandler(dialog.ShowTrustDialogMsg{}))
+	}
 
 	// Check if we should show the init dialog
 	cmds = append(cmds, func() tea.Msg {
@@ -102,212 +178,82 @@ var BUGGED_SCROLL_KEYS = map[string]bool{
 }
 
 func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !a.tracer.Enabled() {
+		return a.update(msg)
+	}
+	start := time.Now()
+	model, cmd := a.update(msg)
+	a.tracer.Trace(msg, time.Since(start), "app")
+	return model, cmd
+}
+
+// update handles a message. The components that consume it are recorded
+// for the message trace.
+func (a appModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// before anything else, so open lists render with the latest activity
+	currentSessionID := ""
+	if a.app.Session != nil {
+		currentSessionID = a.app.Session.ID
+	}
+	a.app.Activity.Observe(msg, currentSessionID)
+	if len(a.app.Activity.TakeFinished()) > 0 {
+		cmds = append(cmds, a.app.Ring(app.BellResponse, time.Since(a.lastInput)))
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyPressMsg:
-		keyString := msg.String()
-		if time.Since(a.lastScroll) < time.Millisecond*100 && BUGGED_SCROLL_KEYS[keyString] {
+		if time.Since(a.lastScroll) < time.Millisecond*100 && BUGGED_SCROLL_KEYS[msg.String()] {
 			return a, nil
 		}
-
-		// 1. Handle active modal
-		if a.modal != nil {
-			switch keyString {
-			// Escape always closes current modal
-			case "esc", "ctrl+c":
-				cmd := a.modal.Close()
-				a.modal = nil
-				return a, cmd
-			}
-
-			// Pass all other key presses to the modal
-			updatedModal, cmd := a.modal.Update(msg)
-			a.modal = updatedModal.(layout.Modal)
-			return a, cmd
+		a.lastInput = time.Now()
+		return a, a.routeKey(msg)
+	case tea.PasteMsg:
+		// a bracketed paste is text for whatever has focus, so it never
+		// reaches the command keybindings
+		if len(a.modals) > 0 {
+			return a, a.updateModals(msg)
 		}
-
-		// 2. Handle alternate screen toggle (Shift+Tab)
-		if keyString == "shift+tab" {
-			a.isAltScreen = !a.isAltScreen
-			var cmd tea.Cmd
-			if a.isAltScreen {
-				cmd = tea.EnterAltScreen
-			} else {
-				cmd = tea.ExitAltScreen
-			}
-			// Show toast notification for user feedback
-			toastMsg := "Fullscreen mode enabled"
-			if !a.isAltScreen {
-				toastMsg = "Fullscreen mode disabled"
-			}
-			return a, tea.Batch(cmd, toast.NewInfoToast(toastMsg))
-		}
-
-		// 3. Check for commands that require leader
-		if a.isLeaderSequence {
-			matches := a.app.Commands.Matches(msg, a.isLeaderSequence)
-			a.isLeaderSequence = false
-			if len(matches) > 0 {
-				return a, util.CmdHandler(commands.ExecuteCommandsMsg(matches))
-			}
-		}
-
-		// 4. Handle completions trigger
-		if keyString == "/" && !a.showCompletionDialog {
-			a.showCompletionDialog = true
-
-			initialValue := "/"
-			currentInput := a.editor.Value()
-
-			// if the input doesn't end with a space,
-			// then we want to include the last word
-			// (ie, `packages/`)
-			if !strings.HasSuffix(currentInput, " ") {
-				words := strings.Split(a.editor.Value(), " ")
-				if len(words) > 0 {
-					lastWord := words[len(words)-1]
-					lastWord = strings.TrimSpace(lastWord)
-					initialValue = lastWord + "/"
-				}
-			}
-
-			updated, cmd := a.completions.Update(
-				app.CompletionDialogTriggeredMsg{
-					InitialValue: initialValue,
-				},
-			)
-			a.completions = updated.(dialog.CompletionDialog)
-			cmds = append(cmds, cmd)
-
-			updated, cmd = a.editor.Update(msg)
-			a.editor = updated.(chat.EditorComponent)
-			cmds = append(cmds, cmd)
-
-			updated, cmd = a.updateCompletions(msg)
-			a.completions = updated.(dialog.CompletionDialog)
-			cmds = append(cmds, cmd)
-
-			return a, tea.Sequence(cmds...)
-		}
-
-		if a.showCompletionDialog {
-			switch keyString {
-			case "tab", "enter", "esc", "ctrl+c":
-				updated, cmd := a.updateCompletions(msg)
-				a.completions = updated.(dialog.CompletionDialog)
-				cmds = append(cmds, cmd)
-				return a, tea.Batch(cmds...)
-			}
-
-			updated, cmd := a.editor.Update(msg)
-			a.editor = updated.(chat.EditorComponent)
-			cmds = append(cmds, cmd)
-
-			updated, cmd = a.updateCompletions(msg)
-			a.completions = updated.(dialog.CompletionDialog)
-			cmds = append(cmds, cmd)
-
-			return a, tea.Batch(cmds...)
-		}
-
-		// 5. Maximize editor responsiveness for printable characters
-		if msg.Text != "" {
-			updated, cmd := a.editor.Update(msg)
-			a.editor = updated.(chat.EditorComponent)
-			cmds = append(cmds, cmd)
-			return a, tea.Batch(cmds...)
-		}
-
-		// 6. Check for leader key activation
-		if a.leaderBinding != nil &&
-			!a.isLeaderSequence &&
-			key.Matches(msg, *a.leaderBinding) {
-			a.isLeaderSequence = true
+		if a.app.ViewOnly() {
 			return a, nil
 		}
-
-		// 6. Handle interrupt key debounce for session interrupt
-		interruptCommand := a.app.Commands[commands.SessionInterruptCommand]
-		if interruptCommand.Matches(msg, a.isLeaderSequence) && a.app.IsBusy() {
-			switch a.interruptKeyState {
-			case InterruptKeyIdle:
-				// First interrupt key press - start debounce timer
-				a.interruptKeyState = InterruptKeyFirstPress
-				a.editor.SetInterruptKeyInDebounce(true)
-				return a, tea.Tick(interruptDebounceTimeout, func(t time.Time) tea.Msg {
-					return InterruptDebounceTimeoutMsg{}
-				})
-			case InterruptKeyFirstPress:
-				// Second interrupt key press within timeout - actually interrupt
-				a.interruptKeyState = InterruptKeyIdle
-				a.editor.SetInterruptKeyInDebounce(false)
-				return a, util.CmdHandler(commands.ExecuteCommandMsg(interruptCommand))
-			}
-		}
-
-		// 8. Check again for commands that don't require leader (excluding interrupt when busy)
-		matches := a.app.Commands.Matches(msg, a.isLeaderSequence)
-		if len(matches) > 0 {
-			// Skip interrupt key if we're in debounce mode and app is busy
-			if interruptCommand.Matches(msg, a.isLeaderSequence) && a.app.IsBusy() && a.interruptKeyState != InterruptKeyIdle {
-				return a, nil
-			}
-			return a, util.CmdHandler(commands.ExecuteCommandsMsg(matches))
-		}
-
-		// 9. Handle Ctrl+B sequences
-		if a.isCtrlBSequence {
-			a.isCtrlBSequence = false
-			switch keyString {
-			case ".":
-				// Navigate to next sibling sub-session
-				return a, a.navigateToSibling(context.Background(), "next")
-			case ",":
-				// Navigate to previous sibling sub-session
-				return a, a.navigateToSibling(context.Background(), "prev")
-			default:
-				// Any other key cancels the sequence
-				return a, nil
-			}
-		}
-
-		if keyString == "ctrl+b" && a.app.Session != nil {
-
-			// Set flag for multi-key sequence
-			a.isCtrlBSequence = true
-			// Also handle immediate navigation
-			// If in sub-session, return to parent
-			if a.app.CurrentSessionType == "sub" && a.app.Session.ParentID != "" {
-				a.isCtrlBSequence = false // Cancel sequence since we're navigating
-
-				return a, a.app.SwitchToSession(context.Background(), a.app.Session.ParentID)
-			}
-			// If in main session and has viewed sub-sessions, go to last viewed
-			if a.app.CurrentSessionType == "main" && a.app.LastViewedSubSession != "" {
-				a.isCtrlBSequence = false // Cancel sequence since we're navigating
-
-				return a, a.app.SwitchToSession(context.Background(), a.app.LastViewedSubSession)
-			}
-			// Otherwise wait for next key (. or ,)
-
-			return a, toast.NewInfoToast("Press . for next or , for previous sibling")
-		}
-
-		// 10. Fallback to editor. This is for other characters
-		// like backspace, tab, etc.
-		updatedEditor, cmd := a.editor.Update(msg)
-		a.editor = updatedEditor.(chat.EditorComponent)
+		a.tracer.Consume("editor")
+		updated, cmd := a.editor.PasteText(string(msg))
+		a.editor = updated.(chat.EditorComponent)
 		return a, cmd
 	case tea.MouseWheelMsg:
 		a.lastScroll = time.Now()
-		if a.modal != nil {
+		if len(a.modals) > 0 {
 			return a, nil
 		}
 		updated, cmd := a.messages.Update(msg)
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
 		return a, tea.Batch(cmds...)
+	case tea.MouseClickMsg:
+		if len(a.modals) > 0 {
+			return a, nil
+		}
+		// the status line is the last row, below a blank row
+		if msg.Y == a.height+1 {
+			if a.status.ModelAt(msg.X) {
+				return a.executeCommand(a.app.Commands[commands.ModelListCommand])
+			}
+			return a, nil
+		}
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, nil
+		}
+		updated, cmd := a.messages.Update(msg)
+		a.messages = updated.(chat.MessagesComponent)
+		return a, cmd
+	case chat.EditClickedMsg:
+		return a, a.openModal(dialog.NewDiffDialog(a.app, msg.MessageID, msg.ToolCallID))
+	case tea.FocusMsg:
+		a.isFocused = true
+	case tea.BlurMsg:
+		a.isFocused = false
 	case tea.BackgroundColorMsg:
 		styles.Terminal = &styles.TerminalInfo{
 			Background:       msg.Color,
@@ -324,15 +270,27 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case modal.CloseModalMsg:
-		var cmd tea.Cmd
-		if a.modal != nil {
-			cmd = a.modal.Close()
-		}
-		a.modal = nil
-		return a, cmd
+		return a, a.closeModal()
+	case modal.CloseAllModalsMsg:
+		return a, a.closeModals()
+	case modal.PushModalMsg:
+		a.pushModal(msg.Modal)
+		return a, msg.Modal.Init()
 	case commands.ExecuteCommandMsg:
 		updated, cmd := a.executeCommand(commands.Command(msg))
 		return updated, cmd
+	case commands.SlashCommandMsg:
+		updated, cmd := a.executeSlashCommand(msg)
+		return updated, cmd
+	case slashArgsCompletedMsg:
+		if a.editor.Value() != msg.input {
+			return a, nil
+		}
+		a.editor.SetValue(msg.completed)
+		if len(msg.matches) > 1 {
+			return a, toast.NewInfoToast(strings.Join(msg.matches[:min(8, len(msg.matches))], "  "))
+		}
+		return a, nil
 	case commands.ExecuteCommandsMsg:
 		for _, command := range msg {
 			updated, cmd := a.executeCommand(command)
@@ -342,28 +300,295 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case error:
 		return a, toast.NewErrorToast(msg.Error())
+	case app.SessionRenamedMsg:
+		if a.app.Session != nil && a.app.Session.ID == msg.Session.ID {
+			a.app.Session = &msg.Session
+		}
+		return a, toast.NewSuccessToast("Session renamed to " + msg.Session.Title)
+	case app.SessionEnvChangedMsg:
+		if a.app.Session != nil && a.app.Session.ID == msg.Session.ID {
+			a.app.Session = &msg.Session
+		}
+		return a, toast.NewSuccessToast("Session environment saved, it applies from the next bash command")
+	case app.SessionBranchedMsg:
+		// a prompt the branch starts before goes back to the editor
+		if msg.Prompt != "" {
+			a.editor.SetValue(msg.Prompt)
+		}
+		session := msg.Session
+		return a, tea.Batch(
+			util.CmdHandler(app.SessionSelectedMsg(&session)),
+			toast.NewSuccessToast("Switched to "+session.Title),
+		)
+	case app.PendingOpDueMsg:
+		return a, a.app.Pending.Due(msg.ID)
+	case draftRestoredMsg:
+		// what was typed since the clear is kept after the restored text
+		text := msg.text
+		if current := a.editor.Value(); strings.TrimSpace(current) != "" {
+			text += "\n" + current
+		}
+		a.editor.SetValue(text)
+		return a, nil
+	case app.ProjectAnalyzedMsg:
+		analysisDialog := dialog.NewInitAnalysisDialog(msg.Analysis)
+		return a, tea.Batch(a.openModal(analysisDialog), analysisDialog.Init())
+	case app.BroadcastConfirmedMsg:
+		send, err := a.app.StartBroadcast(context.Background(), msg.Prompt, msg.Targets)
+		if err != nil {
+			return a, toast.NewErrorToast(err.Error())
+		}
+		compareDialog := dialog.NewBroadcastCompareDialog(a.app)
+		return a, tea.Batch(send, a.openModal(compareDialog), compareDialog.Init())
+	case app.BroadcastRunMsg:
+		return a, a.app.BroadcastRunUpdate(context.Background(), msg)
+	case app.BroadcastLoadedMsg:
+		a.app.Broadcast = msg.Broadcast
+	case dialog.ShowTrustDialogMsg:
+		a.pushModal(dialog.NewTrustDialog(a.app))
+		return a, nil
+	case app.BugReportBuiltMsg:
+		if msg.Err != nil {
+			slog.Error("Failed to build bug report", "error", msg.Err)
+			return a, toast.NewErrorToast(msg.Err.Error())
+		}
+		return a, a.openModal(dialog.NewBugReportDialog(msg.Report, msg.PublicKey, msg.KeyPath))
+	case dialog.BugReportConfirmedMsg:
+		return a, a.writeBugReport(msg.Report, msg.PublicKey)
+	case dialog.WorkspaceTrustSelectedMsg:
+		cmds = append(cmds, a.app.SetWorkspaceTrust(msg.Trust))
+		if msg.Trust == config.WorkspaceTrusted {
+			cmds = append(cmds, toast.NewSuccessToast("Workspace trusted"))
+		} else {
+			cmds = append(cmds, toast.NewInfoToast("Workspace restricted, /trust to change it"))
+		}
+		return a, tea.Batch(cmds...)
+	case app.PluginsLoadedMsg:
+		a.app.SetPlugins(msg.Plugins)
+		if msg.Err != nil {
+			return a, toast.NewWarningToast("Some plugins failed to load, see the logs")
+		}
+	case app.PluginCommandResultMsg:
+		if msg.Err != nil {
+			return a, toast.NewErrorToast(msg.Err.Error())
+		}
+		if msg.Response.Prompt != "" {
+			a.editor.SetValue(msg.Response.Prompt)
+		}
+		if msg.Response.Message != "" {
+			return a, toast.NewInfoToast(msg.Response.Message)
+		}
+	case app.ProjectInitConfirmedMsg:
+		return a, a.app.InitializeProject(context.Background(), msg.Analysis)
+	case app.ResendRequestedMsg:
+		return a, a.openModal(dialog.NewResendDialog(msg.Request))
+	case app.ResendConfirmedMsg:
+		return a, a.resend(msg.Request)
+	case app.MessagesTruncatedMsg:
+		// the session was left while the server removed the messages
+		if a.app.Session == nil || a.app.Session.ID != msg.Request.SessionID {
+			return a, nil
+		}
+		a.app.Messages = app.TruncateMessages(a.app.Messages, msg.Request.MessageID)
+		return a, util.CmdHandler(app.SendMsg{Text: msg.Request.Text})
+	case app.SessionInstructionsChangedMsg:
+		if a.app.Session != nil && a.app.Session.ID == msg.Session.ID {
+			a.app.Session = &msg.Session
+		}
+		if a.app.SessionInstructions(&msg.Session) == "" {
+			return a, toast.NewSuccessToast("Session uses the project instructions again")
+		}
+		return a, toast.NewSuccessToast("Session instructions saved, they apply from the next message")
+	case app.PatchAppliedMsg:
+		title, _, _ := app.ParsePatchSummary(msg.Summary)
+		return a, tea.Batch(toast.NewSuccessToast(title), a.app.RefreshGit())
+	case app.SessionCompactedMsg:
+		a.pushModal(dialog.NewCompactionDialog(msg.Report))
+		return a, toast.NewSuccessToast(fmt.Sprintf(
+			"Compacted %d messages, %s tokens saved",
+			len(msg.Report.Compacted),
+			util.FormatTokens(msg.Report.Saved()),
+		))
 	case app.SendMsg:
 		a.showCompletionDialog = false
-		cmd := a.app.SendChatMessage(context.Background(), msg.Text, msg.Attachments)
+		if a.app.Bundle != nil {
+			return a, toast.NewWarningToast(app.ErrBundleReadOnly.Error() + ", start a new session to continue")
+		}
+		if a.app.ViewOnly() {
+			return a, toast.NewWarningToast(app.ErrViewOnly.Error())
+		}
+		if !msg.Confirmed {
+			size := a.app.MeasurePrompt(msg.Text, msg.CompactPinned)
+			if reason := a.app.LargePrompt(size); reason != "" {
+				return a, a.openModal(dialog.NewLargePromptDialog(msg, size, reason))
+			}
+		}
+		a.app.IndexPrompt(a.app.Session.ID, msg.Text)
+		if err := a.app.History.Add(a.app.Session.ID, msg.Text); err != nil {
+			slog.Error("Failed to save prompt history", "error", err)
+		}
+		cmd := a.app.SendChatMessage(context.Background(), msg.Text, msg.Attachments, msg.CompactPinned)
 		cmds = append(cmds, cmd)
+	case dialog.LargePromptCancelledMsg:
+		a.editor.SetValue(msg.Text)
+	case dialog.HistorySelectedMsg:
+		a.editor.SetValue(msg.Text)
+	case app.BundleImportedMsg:
+		a.app.OpenBundle(msg.Bundle)
+		manifest := msg.Bundle.Manifest
+		cmds = append(cmds, toast.NewSuccessToast(
+			fmt.Sprintf("%d messages, %d checkpoints, %d artifacts, %d handoffs (read-only)",
+				len(msg.Bundle.Messages), len(manifest.Checkpoints), len(manifest.Artifacts), len(manifest.Handoffs)),
+			toast.WithTitle("Imported "+manifest.Title),
+		))
+	case app.CheckpointCreatedMsg:
+		label := msg.Checkpoint.Description
+		if label == "" {
+			label = msg.Checkpoint.ID
+		}
+		cmds = append(cmds, toast.NewSuccessToast("Checkpoint created: "+label))
+	case app.CheckpointRestoredMsg:
+		cmds = append(cmds, toast.NewSuccessToast("Reverted to checkpoint"))
+		if a.app.Session != nil && a.app.Session.ID != "" {
+			cmds = append(cmds, util.CmdHandler(app.SessionSelectedMsg(a.app.Session)))
+		}
 	case dialog.CompletionDialogCloseMsg:
 		a.showCompletionDialog = false
+	case home.TemplateSelectedMsg:
+		updated, cmd := a.insertTemplate(config.PromptTemplate{Name: msg.Name, Prompt: msg.Prompt})
+		return updated, cmd
+	case dialog.TemplateFilledMsg:
+		a.editor.SetValue(msg.Prompt)
+	case app.ContinuationProgressMsg:
+		switch msg.Progress.Stage {
+		case app.ContinuationRequesting:
+			cmds = append(cmds, toast.NewInfoToast("Generating continuation prompt…"))
+		case app.ContinuationWaiting:
+			cmds = append(cmds, toast.NewInfoToast(fmt.Sprintf(
+				"Still generating continuation prompt (%s)", msg.Progress.Elapsed.Round(time.Second),
+			)))
+		}
+		cmds = append(cmds, waitForContinuation(a.continuation))
+	case app.ContinuationGeneratedMsg:
+		a.continuation = nil
+		a.cancelContinuation = nil
+		switch {
+		case errors.Is(msg.Err, context.Canceled):
+			return a, toast.NewInfoToast("Continuation cancelled")
+		case msg.Err != nil:
+			return a, tea.Batch(
+				toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Continuation")),
+				a.notifyUnfocused("Continuation failed", msg.Err.Error(), app.NotificationCritical),
+			)
+		}
+		continuationDialog := dialog.NewContinuationDialog(msg.Response)
+		return a, tea.Batch(
+			a.openModal(continuationDialog),
+			continuationDialog.Init(),
+			a.notifyUnfocused("Continuation ready", "The handoff prompt is waiting for review", app.NotificationNormal),
+		)
+	case dialog.ContinuationAcceptedMsg:
+		// hand off to a fresh session, the current one stays as it is
+		a.app.Bundle = nil
+		a.app.Session = &opencode.Session{}
+		a.app.Messages = []opencode.Message{}
+		return a, tea.Sequence(
+			util.CmdHandler(app.SessionClearedMsg{}),
+			util.CmdHandler(app.SendMsg{Text: msg.Prompt}),
+		)
+	case opencode.EventListResponseEventPermissionUpdated:
+		request, prompt := a.app.Permissions.Enqueue(msg)
+		if a.app.Permissions.AlwaysAllowed(request) {
+			return a, a.respondPermission(request, app.PermissionAlways)
+		}
+		if prompt {
+			return a, a.promptPermission(request)
+		}
+	case dialog.PromptRecalledMsg:
+		a.editor.SetValue(msg.Text)
+		return a, nil
+	case dialog.PermissionResponseMsg:
+		return a, a.respondPermission(msg.Request, msg.Response)
+	case app.PermissionRespondedMsg:
+		if msg.Err != nil {
+			slog.Error("Failed to respond to permission request", "error", msg.Err)
+			cmds = append(cmds, toast.NewErrorToast("Failed to respond to permission request"))
+		}
+		if next, ok := a.app.Permissions.Next(); ok {
+			// calls of a tool that was just allowed for the session queue up
+			// behind the first one
+			if a.app.Permissions.AlwaysAllowed(next) {
+				cmds = append(cmds, a.respondPermission(next, app.PermissionAlways))
+			} else {
+				cmds = append(cmds, a.promptPermission(next))
+			}
+		}
+		return a, tea.Batch(cmds...)
 	case opencode.EventListResponseEventInstallationUpdated:
 		return a, toast.NewSuccessToast(
 			"DGMO updated to "+msg.Properties.Version+", restart to apply.",
 			toast.WithTitle("New version installed"),
 		)
 	case opencode.EventListResponseEventSessionDeleted:
+		a.updateBackgroundTabs(msg)
 		if a.app.Session != nil && msg.Properties.Info.ID == a.app.Session.ID {
 			a.app.Session = &opencode.Session{}
 			a.app.Messages = []opencode.Message{}
 		}
 		return a, toast.NewSuccessToast("Session deleted successfully")
 	case opencode.EventListResponseEventSessionUpdated:
+		a.updateBackgroundTabs(msg)
 		if msg.Properties.Info.ID == a.app.Session.ID {
 			a.app.Session = &msg.Properties.Info
 		}
+		// bulk operations update many sessions at once, so open lists are
+		// refreshed with a batch instead of on every event
+		if len(a.pendingSessions) == 0 {
+			cmds = append(cmds, tea.Tick(app.SessionRefreshInterval, func(time.Time) tea.Msg {
+				return sessionFlushMsg{}
+			}))
+		}
+		a.pendingSessions[msg.Properties.Info.ID] = msg.Properties.Info
+	case windowTitleMsg:
+		if title := a.currentWindowTitle(); title != a.windowTitle {
+			a.windowTitle = title
+			cmds = append(cmds, tea.SetWindowTitle(title))
+		}
+		cmds = append(cmds, tea.Tick(windowTitleInterval, func(time.Time) tea.Msg {
+			return windowTitleMsg{}
+		}))
+		return a, tea.Batch(cmds...)
+	case app.GitStatusMsg:
+		a.app.Git = msg.Status
+		return a, tea.Tick(app.GitRefreshInterval, func(time.Time) tea.Msg {
+			return a.app.RefreshGit()()
+		})
+	case app.ShutdownMsg:
+		slog.Info("Received signal, exiting", "signal", msg.Signal)
+		return a, a.exit()
+	case sessionFlushMsg:
+		sessions := make(app.SessionsUpdatedMsg, 0, len(a.pendingSessions))
+		for _, session := range a.pendingSessions {
+			sessions = append(sessions, session)
+		}
+		clear(a.pendingSessions)
+		return a, util.CmdHandler(sessions)
 	case opencode.EventListResponseEventMessageUpdated:
+		a.updateBackgroundTabs(msg)
+		if a.app.Broadcast != nil {
+			a.app.Broadcast.Observe(msg.Properties.Info)
+		}
+		a.app.MirrorMessage(msg.Properties.Info)
+		a.app.IndexMessage(msg.Properties.Info)
+		for _, alert := range a.app.MCPStats.Observe(msg.Properties.Info) {
+			if alert.Degraded {
+				cmds = append(cmds, toast.NewWarningToast(
+					fmt.Sprintf("MCP server %s is degraded: %s", alert.Server, alert.Reason),
+				))
+			} else {
+				cmds = append(cmds, toast.NewInfoToast("MCP server "+alert.Server+" recovered"))
+			}
+		}
 		if msg.Properties.Info.Metadata.SessionID == a.app.Session.ID {
 			exists := false
 			optimisticReplaced := false
@@ -402,35 +627,38 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case nil:
 		case opencode.ProviderAuthError:
 			slog.Error("Failed to authenticate with provider", "error", err.Data.Message)
-			return a, toast.NewErrorToast("Provider error: " + err.Data.Message)
+			return a, tea.Batch(
+				toast.NewErrorToast("Provider error: "+err.Data.Message),
+				flash.New(flash.SeverityError),
+			)
 		case opencode.UnknownError:
 			slog.Error("Server error", "name", err.Name, "message", err.Data.Message)
-			return a, toast.NewErrorToast(err.Data.Message, toast.WithTitle(string(err.Name)))
+			return a, tea.Batch(
+				toast.NewErrorToast(err.Data.Message, toast.WithTitle(string(err.Name))),
+				flash.New(flash.SeverityError),
+			)
 		}
 	case tea.WindowSizeMsg:
+		a.recorder.Resize(msg.Width, msg.Height)
 		msg.Height -= 2 // Make space for the status bar
 		a.width, a.height = msg.Width, msg.Height
-		layout.Current = &layout.LayoutInfo{
-			Viewport: layout.Dimensions{
-				Width:  a.width,
-				Height: a.height,
-			},
-			Container: layout.Dimensions{
-				Width: min(a.width, 80),
-			},
-		}
-		// Update child component sizes
-		messagesHeight := a.height - 6 // Leave room for editor and status bar
-		a.messages.SetSize(a.width, messagesHeight)
-		a.editor.SetSize(min(a.width, 80), 5)
+		a.applyLayout()
 	case app.SessionSelectedMsg:
+		// a session open in another tab is shown there
+		if i := a.tabOf(msg.ID); i >= 0 && i != a.activeTab {
+			return a, a.switchTab(i)
+		}
 		messages, err := a.app.ListMessages(context.Background(), msg.ID)
 		if err != nil {
 			slog.Error("Failed to list messages", "error", err)
-			return a, toast.NewErrorToast("Failed to open session")
+			a.unreachable = msg
+			return a, toast.NewErrorToast("Failed to open session, /recover rebuilds it from the local copy")
 		}
+		a.unreachable = nil
+		a.app.Bundle = nil
 		a.app.Session = msg
 		a.app.Messages = messages
+		a.app.Activity.MarkRead(msg.ID)
 
 		// Update session type when selecting from dialog
 		if msg.ParentID != "" {
@@ -440,17 +668,33 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.app.CurrentSessionType = "main"
 		}
 
+	case app.ToolTimerTickMsg:
+		cmds = append(cmds, a.app.TickToolTimers())
+		cmds = append(cmds, a.remindIdle())
+	case app.SessionIntegrityMsg:
+		switch msg.Integrity {
+		case app.SessionDeleted:
+			a.app.ForgetSession(msg.Snapshot.ID)
+			cmds = append(cmds, toast.NewWarningToast(msg.Notice))
+		case app.SessionTruncated:
+			cmds = append(cmds, toast.NewWarningToast(msg.Notice))
+		}
+	case app.TranscriptRecoveredMsg:
+		a.unreachable = nil
+		a.app.Bundle = nil
+		a.app.Session = msg.Session
+		a.app.Messages = msg.Messages
+		cmds = append(cmds, toast.NewSuccessToast(
+			fmt.Sprintf("Recovered %d messages from the local copy", len(msg.Messages)),
+		))
 	case app.SessionSwitchedMsg:
 
 		// Handle session switching from navigation
 		a.app.Session = msg.Session
 		a.app.Messages = msg.Messages
-		// Close any open modal
-		if a.modal != nil {
-			cmd := a.modal.Close()
-			a.modal = nil
-			cmds = append(cmds, cmd)
-		}
+		a.app.Activity.MarkRead(msg.Session.ID)
+		// Close any open modals
+		cmds = append(cmds, a.closeModals())
 		// Show success toast
 		cmds = append(cmds, toast.NewSuccessToast(fmt.Sprintf("Switched to session: %s", msg.Session.Title)))
 		// Messages will be updated automatically via a.app.Messages
@@ -462,8 +706,23 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.app.State.UpdateModelUsage(msg.Provider.ID, msg.Model.ID)
 		a.app.SaveState()
 	case dialog.ThemeSelectedMsg:
-		a.app.State.Theme = msg.ThemeName
-		a.app.SaveState()
+		// a project theme applies to this project only
+		if !a.app.ProjectOverrides("theme") {
+			a.app.State.Theme = msg.ThemeName
+			a.app.SaveState()
+		}
+	case app.ProjectConfigCheckMsg:
+		cmds = append(cmds, a.app.CheckProjectConfig(), a.app.WatchProjectConfig())
+	case app.ProjectConfigReloadedMsg:
+		if msg.Err != nil {
+			cmds = append(cmds, toast.NewErrorToast(msg.Err.Error(), toast.WithTitle("Project config")))
+			break
+		}
+		a.leaderBinding = newLeaderBinding(a.app.Config.Keybinds.Leader)
+		cmds = append(cmds,
+			toast.NewInfoToast("Project config reloaded"),
+			util.CmdHandler(dialog.ThemeSelectedMsg{ThemeName: theme.CurrentThemeName()}),
+		)
 	case toast.ShowToastMsg:
 		tm, cmd := a.toastManager.Update(msg)
 		a.toastManager = tm
@@ -479,18 +738,54 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case app.TaskStartedMsg:
 		// Task started - update progress to 0
 		chat.UpdateTaskProgress(msg.Task.ID, 0)
+		chat.UpdateTaskAgent(msg.Task.ID, msg.Task.AgentNumber)
+		chat.UpdateTaskStart(msg.Task.ID, msg.Task.StartTime)
+		if a.app.TaskToastsFor(app.TaskToastStarted) {
+			cmds = append(cmds, toast.NewInfoToast(taskToastText(msg.Task, "started")))
+		}
 	case app.TaskProgressMsg:
-		// Update task progress
+		// progress goes to the task box only, a toast per update floods
+		// the screen
 		chat.UpdateTaskProgress(msg.TaskID, msg.Progress)
+		chat.UpdateTaskMessage(msg.TaskID, msg.Message)
+		if a.app.TaskClient != nil {
+			if task, ok := a.app.TaskClient.GetTask(msg.TaskID); ok {
+				chat.UpdateTaskTool(msg.TaskID, task.CurrentTool)
+				chat.UpdateTaskStart(msg.TaskID, task.StartTime)
+			}
+		}
 	case app.TaskCompletedMsg:
 		// Task completed - set progress to 100
 		chat.UpdateTaskProgress(msg.TaskID, 100)
+		chat.UpdateTaskDuration(msg.TaskID, msg.Duration)
+		cmds = append(cmds, a.notifyTask(msg.TaskID, "Task completed", msg.Summary, app.NotificationNormal))
+		cmds = append(cmds, a.app.Ring(app.BellTask, time.Since(a.lastInput)))
+		if a.app.TaskToastsFor(app.TaskToastCompleted) {
+			cmds = append(cmds, toast.NewSuccessToast(a.taskToast(msg.TaskID, "completed")))
+		}
 	case app.TaskFailedMsg:
-		// Task failed - could show error state
-		// For now, just log it
 		slog.Warn("Task failed", "taskID", msg.TaskID, "error", msg.Error)
+		cmds = append(cmds, a.notifyTask(msg.TaskID, "Task failed", msg.Error, app.NotificationCritical))
+		cmds = append(cmds, a.app.Ring(app.BellTask, time.Since(a.lastInput)))
+		if a.app.TaskToastsFor(app.TaskToastFailed) {
+			cmds = append(cmds, toast.NewErrorToast(a.taskToast(msg.TaskID, "failed: "+msg.Error)))
+		}
+		cmds = append(cmds, flash.New(flash.SeverityError))
 	}
 
+	// every component sees the messages that get this far
+	a.tracer.Consume("components")
+
+	// update border flash
+	f, cmd := a.flash.Update(msg)
+	a.flash = f
+	cmds = append(cmds, cmd)
+
+	// advance the tutorial when its current step is done
+	t, cmd := a.tutorial.Update(msg)
+	a.tutorial = t
+	cmds = append(cmds, cmd)
+
 	// update status bar
 	s, cmd := a.status.Update(msg)
 	cmds = append(cmds, cmd)
@@ -506,12 +801,13 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	a.messages = u.(chat.MessagesComponent)
 	cmds = append(cmds, cmd)
 
-	// update modal
-	if a.modal != nil {
-		u, cmd := a.modal.Update(msg)
-		a.modal = u.(layout.Modal)
-		cmds = append(cmds, cmd)
-	}
+	// update start screen
+	u, cmd = a.home.Update(msg)
+	a.home = u.(home.HomeComponent)
+	cmds = append(cmds, cmd)
+
+	// update modals
+	cmds = append(cmds, a.updateModals(msg))
 
 	if a.showCompletionDialog {
 		u, cmd := a.completions.Update(msg)
@@ -524,22 +820,29 @@ func (a appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (a appModel) View() string {
 	mainLayout := a.chat(layout.Current.Container.Width, lipgloss.Center)
-	if a.modal != nil {
-		mainLayout = a.modal.Render(mainLayout)
-	}
+	mainLayout = a.flash.Render(mainLayout)
+	mainLayout = a.tutorial.Render(mainLayout)
+	mainLayout = a.renderPerfHUD(mainLayout)
+	mainLayout = a.renderModals(mainLayout)
 	mainLayout = a.toastManager.RenderOverlay(mainLayout)
 	if theme.CurrentThemeUsesAnsiColors() {
 		mainLayout = util.ConvertRGBToAnsi16Colors(mainLayout)
 	}
-	return mainLayout + "\n" + a.status.View()
+	// scrolling by key skips the update loop, so read the scroll state here
+	view := mainLayout + "\n" + a.status.SetFollowing(a.messages.Following()).View()
+	a.recorder.Capture(view)
+	return view
 }
 
 func (a appModel) chat(width int, align lipgloss.Position) string {
 	editorView := a.editor.View(width, align)
 	lines := a.editor.Lines()
+	if a.app.ViewOnly() {
+		editorView, lines = a.viewerFooter(width, align), 1
+	}
 	messagesView := a.messages.View()
 	if a.app.Session == nil || a.app.Session.ID == "" {
-		messagesView = a.home()
+		messagesView = a.home.View()
 	}
 	editorHeight := max(lines, 5)
 
@@ -551,12 +854,14 @@ func (a appModel) chat(width int, align lipgloss.Position) string {
 		styles.WhitespaceStyle(t.Background()),
 	)
 
-	mainLayout := layout.Render(
-		layout.FlexOptions{
-			Direction: layout.Column,
-			Width:     a.width,
-			Height:    a.height,
-		},
+	var items []layout.FlexItem
+	if a.tabBarHeight() > 0 {
+		items = append(items, layout.FlexItem{
+			View:      a.renderTabBar(),
+			FixedSize: a.tabBarHeight(),
+		})
+	}
+	items = append(items,
 		layout.FlexItem{
 			View: messagesView,
 			Grow: true,
@@ -566,9 +871,17 @@ func (a appModel) chat(width int, align lipgloss.Position) string {
 			FixedSize: 5,
 		},
 	)
+	mainLayout := layout.Render(
+		layout.FlexOptions{
+			Direction: layout.Column,
+			Width:     a.width,
+			Height:    a.height,
+		},
+		items...,
+	)
 
 	if lines > 1 {
-		editorWidth := min(a.width, 80)
+		editorWidth := layout.Current.Container.Width
 		editorX := (a.width - editorWidth) / 2
 		editorY := a.height - editorHeight
 		mainLayout = layout.PlaceOverlay(
@@ -580,7 +893,7 @@ func (a appModel) chat(width int, align lipgloss.Position) string {
 	}
 
 	if a.showCompletionDialog {
-		editorWidth := min(a.width, 80)
+		editorWidth := layout.Current.Container.Width
 		editorX := (a.width - editorWidth) / 2
 		a.completions.SetWidth(editorWidth)
 		overlay := a.completions.View()
@@ -598,82 +911,437 @@ func (a appModel) chat(width int, align lipgloss.Position) string {
 	return mainLayout
 }
 
-func (a appModel) home() string {
-	t := theme.CurrentTheme()
-	baseStyle := styles.NewStyle().Background(t.Background())
-	base := baseStyle.Render
-	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background()).Render
-
-	dgm := `
-█▀▀▄ █▀▀▀ █▀▄▀█
-█░░█ █░▀█ █░▀░█
-▀▀▀  ▀▀▀▀ ▀░░░▀`
-	o := `
-  █▀▀█
-▀ █░░█
-  ▀▀▀▀`
-
-	logo := lipgloss.JoinHorizontal(
-		lipgloss.Top,
-		muted(dgm),
-		base(o),
-	)
-	// cwd := app.Info.Path.Cwd
-	// config := app.Info.Path.Config
-
-	versionStyle := styles.NewStyle().
-		Foreground(t.TextMuted()).
-		Background(t.Background()).
-		Width(lipgloss.Width(logo)).
-		Align(lipgloss.Right)
-	version := versionStyle.Render(a.app.Version)
-
-	logoAndVersion := strings.Join([]string{logo, version}, "\n")
-	logoAndVersion = lipgloss.PlaceHorizontal(
-		a.width,
-		lipgloss.Center,
-		logoAndVersion,
-		styles.WhitespaceStyle(t.Background()),
-	)
-	commandsView := cmdcomp.New(
-		a.app,
-		cmdcomp.WithBackground(t.Background()),
-		cmdcomp.WithLimit(6),
-	)
-	cmds := lipgloss.PlaceHorizontal(
-		a.width,
-		lipgloss.Center,
-		commandsView.View(),
-		styles.WhitespaceStyle(t.Background()),
-	)
+// completeSlashArgs completes the argument under the cursor off the update
+// loop, since model choices come from the server
+func (a appModel) completeSlashArgs(input string) tea.Cmd {
+	return func() tea.Msg {
+		completed, matches := completions.CompleteArgument(a.app, input)
+		return slashArgsCompletedMsg{input: input, completed: completed, matches: matches}
+	}
+}
 
-	lines := []string{}
-	lines = append(lines, logoAndVersion)
-	lines = append(lines, "")
-	lines = append(lines, "")
-	// lines = append(lines, base("cwd ")+muted(cwd))
-	// lines = append(lines, base("config ")+muted(config))
-	// lines = append(lines, "")
-	lines = append(lines, cmds)
+// pasteKey inserts a key of a paste burst into the editor as text: enter
+// breaks the line and keys without text are dropped
+func (a *appModel) pasteKey(msg tea.KeyPressMsg) tea.Cmd {
+	var updated tea.Model
+	var cmd tea.Cmd
+	switch {
+	case msg.String() == "enter":
+		updated, cmd = a.editor.PasteText("\n")
+	case msg.String() == "tab":
+		updated, cmd = a.editor.PasteText("\t")
+	case msg.Text != "":
+		updated, cmd = a.editor.PasteText(msg.Text)
+	default:
+		return nil
+	}
+	a.editor = updated.(chat.EditorComponent)
+	return cmd
+}
 
-	return lipgloss.Place(
-		a.width,
-		a.height-5,
-		lipgloss.Center,
-		lipgloss.Center,
-		baseStyle.Render(strings.Join(lines, "\n")),
-		styles.WhitespaceStyle(t.Background()),
+// executeSlashCommand runs a command typed in the editor. Without arguments
+// it behaves like the keybinding of the command.
+func (a appModel) executeSlashCommand(msg commands.SlashCommandMsg) (tea.Model, tea.Cmd) {
+	if len(msg.Args) == 0 {
+		return a.executeCommand(msg.Command)
+	}
+
+	executed := util.CmdHandler(commands.CommandExecutedMsg(msg.Command))
+	switch msg.Command.Name {
+	case commands.ModelListCommand, commands.SessionRegenerateCommand:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		providers, err := a.app.ListProviders(ctx)
+		if err != nil {
+			slog.Error("Failed to list providers", "error", err)
+			return a, toast.NewErrorToast("Failed to list models")
+		}
+		provider, model, ok := findModel(providers, msg.Args[0])
+		if !ok {
+			return a, toast.NewErrorToast(fmt.Sprintf("Unknown model %q", msg.Args[0]))
+		}
+		if msg.Command.Name == commands.SessionRegenerateCommand {
+			return a, tea.Batch(executed, a.requestRegenerate(&provider, &model))
+		}
+		return a, tea.Batch(
+			executed,
+			util.CmdHandler(app.ModelSelectedMsg{Provider: provider, Model: model}),
+			toast.NewInfoToast("Switched to "+model.Name),
+		)
+	case commands.ThemeListCommand:
+		if err := theme.SetTheme(msg.Args[0]); err != nil {
+			return a, toast.NewErrorToast(fmt.Sprintf("Unknown theme %q", msg.Args[0]))
+		}
+		return a, tea.Batch(
+			executed,
+			util.CmdHandler(dialog.ThemeSelectedMsg{ThemeName: msg.Args[0]}),
+		)
+	case commands.SessionExportCommand:
+		return a, tea.Batch(executed, a.exportSession(app.ExportFormat(msg.Args[0])))
+	case commands.SessionPRCommand:
+		toFile := len(msg.Args) > 1 && msg.Args[1] == "file"
+		return a, tea.Batch(executed, a.describePR(app.PRFlavor(msg.Args[0]), toFile))
+	case commands.SessionContinueCommand:
+		updated, cmd := a.continueSession(strings.Join(msg.Args, " "))
+		return updated, tea.Batch(executed, cmd)
+	case commands.SessionImportCommand:
+		return a, tea.Batch(executed, importBundle(msg.Args[0]))
+	case commands.BugReportCommand:
+		return a, tea.Batch(executed, a.app.BuildBugReport(msg.Args[0]))
+	case commands.SessionRecordCommand:
+		rng, err := recorder.ParseRange(msg.Args[0])
+		if err != nil {
+			return a, toast.NewErrorToast(err.Error())
+		}
+		return a, tea.Batch(executed, a.saveRecording(rng))
+	case commands.SessionRenameCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewWarningToast("Start a session before renaming it")
+		}
+		session := a.app.Session
+		title := strings.Join(msg.Args, " ")
+		return a, tea.Batch(executed, func() tea.Msg {
+			renamed, err := a.app.UpdateSession(context.Background(), session.ID, title, a.app.SessionDescription(session))
+			if err != nil {
+				return err
+			}
+			return app.SessionRenamedMsg{Session: *renamed}
+		})
+	case commands.MessagesBookmarkCommand:
+		return a, tea.Batch(executed, a.bookmark(strings.Join(msg.Args, " ")))
+	case commands.MessagesCopyCodeCommand:
+		n, err := strconv.Atoi(msg.Args[0])
+		if err != nil || n < 1 {
+			return a, toast.NewErrorToast(fmt.Sprintf("Invalid block number %q, usage: %s", msg.Args[0], msg.Command.Usage()))
+		}
+		return a, tea.Batch(executed, a.copyCodeBlock(n))
+	case commands.SearchCommand:
+		searchDialog := dialog.NewSearchDialog(a.app, strings.Join(msg.Args, " "))
+		return a, tea.Batch(executed, a.openModal(searchDialog), searchDialog.Init())
+	case commands.TemplateListCommand:
+		template, ok := a.app.FindTemplate(msg.Args[0])
+		if !ok {
+			return a, toast.NewErrorToast(fmt.Sprintf("Unknown template %q", msg.Args[0]))
+		}
+		updated, cmd := a.insertTemplate(template)
+		return updated, tea.Batch(executed, cmd)
+	case commands.SubSessionCommand:
+		return a, tea.Batch(executed, a.app.SwitchToSession(context.Background(), msg.Args[0]))
+	case commands.LayoutWidthCommand:
+		width, err := app.ParseMaxWidth(msg.Args[0])
+		if err != nil {
+			return a, toast.NewErrorToast(err.Error())
+		}
+		return a, tea.Batch(executed, a.setLayout(width, a.app.Density()))
+	case commands.LayoutDensityCommand:
+		return a, tea.Batch(executed, a.setLayout(a.app.State.MaxWidth, layout.Density(msg.Args[0])))
+	case commands.LayoutZoomCommand:
+		zoom, err := app.ParseZoom(msg.Args[0], a.app.Zoom())
+		if err != nil {
+			return a, toast.NewErrorToast(err.Error())
+		}
+		return a, tea.Batch(executed, a.setZoom(zoom))
+	case commands.BellToggleCommand:
+		event := app.BellEvent(msg.Args[0])
+		if event != app.BellResponse && event != app.BellTask {
+			return a, toast.NewErrorToast(fmt.Sprintf("Unknown alert %q, use response or task", msg.Args[0]))
+		}
+		what := "a response completes"
+		if event == app.BellTask {
+			what = "an agent task finishes"
+		}
+		if a.app.ToggleBellEvent(event) {
+			return a, tea.Batch(executed, toast.NewInfoToast("Alert when "+what+" while you are away"))
+		}
+		return a, tea.Batch(executed, toast.NewInfoToast("No alert when "+what))
+	case commands.TutorialCommand:
+		switch msg.Args[0] {
+		case "skip":
+			return a, tea.Batch(executed, util.CmdHandler(tutorial.SkipMsg{}))
+		case "stop":
+			return a, tea.Batch(executed, util.CmdHandler(tutorial.StopMsg{}))
+		case "restart":
+			return a, tea.Batch(executed, util.CmdHandler(tutorial.StartMsg{Restart: true}))
+		}
+		return a, toast.NewErrorToast(fmt.Sprintf("Unknown tutorial action %q", msg.Args[0]))
+	}
+	if action, ok := a.app.PluginAction(msg.Command.Name); ok {
+		return a, tea.Batch(executed, a.runPluginAction(action, msg.Args))
+	}
+	return a, toast.NewErrorToast(fmt.Sprintf("/%s takes no arguments", msg.Command.Trigger))
+}
+
+// insertTemplate puts the template into the editor, asking for the values
+// of its variables first
+func (a appModel) insertTemplate(template config.PromptTemplate) (tea.Model, tea.Cmd) {
+	if len(app.TemplateVariables(template.Prompt)) == 0 {
+		a.editor.SetValue(template.Prompt)
+		return a, nil
+	}
+	templateDialog := dialog.NewTemplateVariablesDialog(a.app, template)
+	return a, tea.Batch(a.openModal(templateDialog), templateDialog.Init())
+}
+
+// findModel looks up a model by "provider/model" or by a model id or name
+func findModel(providers []opencode.Provider, query string) (opencode.Provider, opencode.Model, bool) {
+	providerID, modelID, qualified := strings.Cut(query, "/")
+	for _, provider := range providers {
+		if qualified {
+			if provider.ID != providerID {
+				continue
+			}
+			if model, ok := provider.Models[modelID]; ok {
+				return provider, model, true
+			}
+			continue
+		}
+		for id, model := range provider.Models {
+			if id == query || strings.EqualFold(model.Name, query) {
+				return provider, model, true
+			}
+		}
+	}
+	return opencode.Provider{}, opencode.Model{}, false
+}
+
+// continueSession asks the server for a continuation prompt of the current
+// session. Running it again while a request is in flight cancels it.
+func (a appModel) continueSession(goal string) (appModel, tea.Cmd) {
+	if a.cancelContinuation != nil {
+		a.cancelContinuation()
+		return a, nil
+	}
+	if a.app.Session == nil || a.app.Session.ID == "" {
+		return a, toast.NewErrorToast("No session to continue")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan tea.Msg)
+	send := func(msg tea.Msg) {
+		select {
+		case updates <- msg:
+		case <-ctx.Done():
+		}
+	}
+	request := app.ContinuationRequest{
+		SessionID:        a.app.Session.ID,
+		Goal:             goal,
+		WorkingDirectory: a.app.Info.Path.Cwd,
+	}
+	go func() {
+		response, err := a.app.Continuation.Generate(ctx, request, func(p app.ContinuationProgress) {
+			send(app.ContinuationProgressMsg{Progress: p})
+		})
+		// the result must arrive even after cancellation
+		updates <- app.ContinuationGeneratedMsg{Response: response, Err: err}
+		cancel()
+	}()
+
+	a.continuation = updates
+	a.cancelContinuation = cancel
+	return a, waitForContinuation(updates)
+}
+
+// resendBlocked returns a warning when the prompts of the session cannot be
+// resent now, nil when they can
+func (a appModel) resendBlocked() tea.Cmd {
+	switch {
+	case a.app.Bundle != nil:
+		return toast.NewWarningToast(app.ErrBundleReadOnly.Error())
+	case a.app.ViewOnly():
+		return toast.NewWarningToast(app.ErrViewOnly.Error())
+	case a.app.Session == nil || a.app.Session.ID == "":
+		return toast.NewWarningToast("No messages sent yet")
+	case a.app.IsBusy():
+		return toast.NewWarningToast("Wait for the response to finish, or interrupt it first")
+	}
+	if err := a.app.Features.Require(app.FeatureTruncate); err != nil {
+		return toast.NewInfoToast("Resending messages: " + err.Error())
+	}
+	return nil
+}
+
+// requestRegenerate asks to resend the last prompt, to the given model
+// when it is not nil
+func (a appModel) requestRegenerate(provider *opencode.Provider, model *opencode.Model) tea.Cmd {
+	if cmd := a.resendBlocked(); cmd != nil {
+		return cmd
+	}
+	request, err := app.RegenerateRequest(a.app.Messages)
+	if err != nil {
+		return toast.NewWarningToast(err.Error())
+	}
+	request.Provider, request.Model = provider, model
+	return util.CmdHandler(app.ResendRequestedMsg{Request: request})
+}
+
+// resend has the server remove the resent prompt and everything after it,
+// the prompt is sent again once they are gone. A model of the request is
+// selected first.
+func (a appModel) resend(request app.ResendRequest) tea.Cmd {
+	if cmd := a.resendBlocked(); cmd != nil {
+		return cmd
+	}
+	truncate := func() tea.Msg {
+		if err := a.app.TruncateSession(context.Background(), request.SessionID, request.MessageID); err != nil {
+			return toast.NewErrorToast(err.Error())()
+		}
+		return app.MessagesTruncatedMsg{Request: request}
+	}
+	if request.Provider == nil || request.Model == nil {
+		return truncate
+	}
+	return tea.Sequence(
+		util.CmdHandler(app.ModelSelectedMsg{Provider: *request.Provider, Model: *request.Model}),
+		truncate,
 	)
 }
 
+// importBundle reads a session bundle off the update loop
+func importBundle(path string) tea.Cmd {
+	return func() tea.Msg {
+		path = paths.ExpandHome(path)
+		bundle, err := app.ImportBundle(path)
+		if err != nil {
+			slog.Error("Failed to import bundle", "path", path, "error", err)
+			return toast.NewErrorToast(err.Error(), toast.WithTitle("Import"))()
+		}
+		return app.BundleImportedMsg{Bundle: bundle}
+	}
+}
+
+// waitForContinuation delivers the next update of a continuation request
+func waitForContinuation(updates chan tea.Msg) tea.Cmd {
+	if updates == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// recordingsDir holds saved recordings and the frames of the current one
+func (a appModel) recordingsDir() string {
+	return filepath.Join(a.app.Info.Path.Data, "recordings")
+}
+
+// saveRecording stops a running recording and exports the range of the last
+// one to a .cast file
+func (a appModel) saveRecording(rng recorder.Range) tea.Cmd {
+	duration, err := a.recorder.Stop()
+	if err != nil {
+		slog.Error("Failed to stop recording", "error", err)
+		return toast.NewErrorToast("Failed to save recording")
+	}
+	title := "dgmo"
+	if a.app.Session != nil && a.app.Session.Title != "" {
+		title = a.app.Session.Title
+	}
+	if rng.To == 0 || rng.To > duration {
+		rng.To = duration
+	}
+	if rng.From >= rng.To {
+		return toast.NewErrorToast(fmt.Sprintf("The recording is only %s long", duration.Round(time.Second)))
+	}
+	dir := a.recordingsDir()
+	return func() tea.Msg {
+		path, err := a.recorder.Save(dir, title, rng)
+		if errors.Is(err, recorder.ErrNoRecording) {
+			return toast.NewWarningToast("Nothing recorded yet, run /record to start")()
+		}
+		if err != nil {
+			slog.Error("Failed to save recording", "error", err)
+			return toast.NewErrorToast("Failed to save recording")()
+		}
+		return tea.Batch(
+			tea.SetClipboard(path),
+			toast.NewSuccessToast(
+				fmt.Sprintf("Saved %s recording, path copied to clipboard", (rng.To-rng.From).Round(time.Second)),
+				toast.WithTitle(filepath.Base(path)),
+			),
+		)()
+	}
+}
+
+// exportSession writes the transcript to the exports directory off the
+// update loop and copies the path of the file to the clipboard
+func (a appModel) exportSession(format app.ExportFormat) tea.Cmd {
+	dir := filepath.Join(a.app.Info.Path.Data, "exports")
+	return func() tea.Msg {
+		var path string
+		var err error
+		if format == app.ExportBundle {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			path, err = a.app.ExportBundle(ctx, dir)
+		} else {
+			path, err = a.app.ExportSession(dir, format)
+		}
+		if err != nil {
+			slog.Error("Failed to export session", "error", err)
+			return toast.NewErrorToast("Failed to export session")()
+		}
+		return tea.Batch(
+			tea.SetClipboard(path),
+			toast.NewSuccessToast("Session exported, path copied to clipboard", toast.WithTitle(filepath.Base(path))),
+		)()
+	}
+}
+
+// writeBugReport writes a reviewed bug report to the exports directory off
+// the update loop and copies the path of the file to the clipboard
+func (a appModel) writeBugReport(report *bugreport.Report, publicKey crypto.PublicKey) tea.Cmd {
+	return func() tea.Msg {
+		path, err := a.app.WriteBugReport(report, publicKey)
+		if err != nil {
+			slog.Error("Failed to write bug report", "error", err)
+			return toast.NewErrorToast("Failed to write bug report")()
+		}
+		return tea.Batch(
+			tea.SetClipboard(path),
+			toast.NewSuccessToast("Bug report written, path copied to clipboard", toast.WithTitle(filepath.Base(path))),
+		)()
+	}
+}
+
+// describePR generates a pull request description of the session and copies
+// it to the clipboard, or writes it to a file and copies the path
+func (a appModel) describePR(flavor app.PRFlavor, toFile bool) tea.Cmd {
+	if a.app.Session == nil || a.app.Session.ID == "" {
+		return toast.NewWarningToast("Start a session before describing it")
+	}
+	description := app.PRDescription(a.app.Session, a.app.Messages, a.app.Info.Path.Root, flavor)
+	if !toFile {
+		return tea.Batch(
+			tea.SetClipboard(description),
+			toast.NewSuccessToast("PR description copied to clipboard"),
+		)
+	}
+	dir := filepath.Join(a.app.Info.Path.Data, "exports")
+	return func() tea.Msg {
+		path, err := a.app.WritePRDescription(dir, description)
+		if err != nil {
+			slog.Error("Failed to write PR description", "error", err)
+			return toast.NewErrorToast("Failed to write the PR description")()
+		}
+		return tea.Batch(
+			tea.SetClipboard(path),
+			toast.NewSuccessToast("PR description written, path copied to clipboard", toast.WithTitle(filepath.Base(path))),
+		)()
+	}
+}
+
 func (a appModel) executeCommand(command commands.Command) (tea.Model, tea.Cmd) {
+	if cmd := a.viewerBlocked(command); cmd != nil {
+		return a, cmd
+	}
 	cmds := []tea.Cmd{
 		util.CmdHandler(commands.CommandExecutedMsg(command)),
 	}
 	switch command.Name {
 	case commands.AppHelpCommand:
 		helpDialog := dialog.NewHelpDialog(a.app)
-		a.modal = helpDialog
+		cmds = append(cmds, a.openModal(helpDialog))
 	case commands.EditorOpenCommand:
 		if a.app.IsBusy() {
 			// status.Warn("Agent is working, please wait...")
@@ -727,15 +1395,263 @@ func (a appModel) executeCommand(command commands.Command) (tea.Model, tea.Cmd)
 		if a.app.Session == nil || a.app.Session.ID == "" {
 			return a, nil
 		}
+		a.app.Bundle = nil
 		a.app.Session = &opencode.Session{}
 		a.app.Messages = []opencode.Message{}
 		cmds = append(cmds, util.CmdHandler(app.SessionClearedMsg{}))
+	case commands.SessionTabNewCommand:
+		cmds = append(cmds, a.openTab())
+	case commands.SessionTabNextCommand:
+		if len(a.tabs) == 1 {
+			return a, toast.NewInfoToast("No other tab is open, /tab opens one")
+		}
+		cmds = append(cmds, a.switchTab((a.activeTab+1)%len(a.tabs)))
+	case commands.SessionTabCloseCommand:
+		cmds = append(cmds, a.closeTab())
 	case commands.SessionListCommand:
 		sessionDialog := dialog.NewSessionDialog(a.app)
-		a.modal = sessionDialog
+		cmds = append(cmds, a.openModal(sessionDialog))
+	case commands.SessionLatestCommand:
+		currentSessionID := ""
+		if a.app.Session != nil {
+			currentSessionID = a.app.Session.ID
+		}
+		sessionID, ok := a.app.Activity.MostRecent(currentSessionID)
+		if !ok {
+			return a, toast.NewInfoToast("No activity in other sessions")
+		}
+		cmds = append(cmds, a.app.SwitchToSession(context.Background(), sessionID))
+	case commands.SessionRevertCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, nil
+		}
+		if err := a.app.Features.Require(app.FeatureCheckpoints); err != nil {
+			return a, toast.NewInfoToast("Checkpoints: " + err.Error())
+		}
+		revertDialog := dialog.NewRevertDialog(a.app)
+		cmds = append(cmds, a.openModal(revertDialog))
+		cmds = append(cmds, revertDialog.Init())
+	case commands.SessionCheckpointCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewInfoToast("Start a session before creating a checkpoint")
+		}
+		if err := a.app.Features.Require(app.FeatureCheckpoints); err != nil {
+			return a, toast.NewInfoToast("Checkpoints: " + err.Error())
+		}
+		checkpointDialog := dialog.NewCheckpointDialog(a.app)
+		cmds = append(cmds, a.openModal(checkpointDialog))
+		cmds = append(cmds, checkpointDialog.Init())
+	case commands.SwarmDashboardCommand:
+		swarmDialog := dialog.NewSwarmDialog(a.app)
+		cmds = append(cmds, a.openModal(swarmDialog))
+		cmds = append(cmds, swarmDialog.Init())
+	case commands.CommandPaletteCommand:
+		paletteDialog := dialog.NewCommandPaletteDialog(a.app)
+		cmds = append(cmds, a.openModal(paletteDialog))
+		cmds = append(cmds, paletteDialog.Init())
+	case commands.TemplateListCommand:
+		templatesDialog := dialog.NewTemplatesDialog(a.app)
+		cmds = append(cmds, a.openModal(templatesDialog))
+		cmds = append(cmds, templatesDialog.Init())
+	case commands.HistorySearchCommand:
+		historyDialog := dialog.NewHistorySearchDialog(a.app)
+		cmds = append(cmds, a.openModal(historyDialog))
+		cmds = append(cmds, historyDialog.Init())
+	case commands.SnippetsCommand:
+		snippetsDialog := dialog.NewSnippetsDialog(a.app)
+		cmds = append(cmds, a.openModal(snippetsDialog))
+		cmds = append(cmds, snippetsDialog.Init())
+	case commands.PromptBuilderCommand:
+		builderDialog := dialog.NewPromptBuilderDialog(a.app)
+		cmds = append(cmds, a.openModal(builderDialog))
+		cmds = append(cmds, builderDialog.Init())
+	case commands.SearchCommand:
+		searchDialog := dialog.NewSearchDialog(a.app, "")
+		cmds = append(cmds, a.openModal(searchDialog))
+		cmds = append(cmds, searchDialog.Init())
+	case commands.SessionRecoverCommand:
+		session := a.unreachable
+		if session == nil {
+			session = a.app.Session
+		}
+		if session == nil || session.ID == "" {
+			return a, toast.NewInfoToast("Open a session to recover it")
+		}
+		cmds = append(cmds, func() tea.Msg {
+			messages, err := a.app.RecoverMessages(session.ID)
+			if err != nil {
+				return toast.NewErrorToast("Can't recover the session: " + err.Error())()
+			}
+			return app.TranscriptRecoveredMsg{Session: session, Messages: messages}
+		})
+	case commands.SessionTimelineCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewInfoToast("Start a session to see its timeline")
+		}
+		timelineDialog := dialog.NewTimelineDialog(a.app)
+		cmds = append(cmds, a.openModal(timelineDialog))
+		cmds = append(cmds, timelineDialog.Init())
+	case commands.PerfHUDCommand:
+		a.showPerfHUD = !a.showPerfHUD
+	case commands.TraceCommand:
+		enabled := !a.tracer.Enabled()
+		a.tracer.SetEnabled(enabled)
+		if enabled {
+			cmds = append(cmds, toast.NewInfoToast("Tracing messages to the log, see /logs"))
+		} else {
+			cmds = append(cmds, toast.NewInfoToast("Message tracing off"))
+		}
+	case commands.LayoutWidthCommand:
+		cmds = append(cmds, a.setLayout(a.app.NextMaxWidth(), a.app.Density()))
+	case commands.LayoutDensityCommand:
+		density := layout.DensityCompact
+		if a.app.Density() == layout.DensityCompact {
+			density = layout.DensityComfortable
+		}
+		cmds = append(cmds, a.setLayout(a.app.State.MaxWidth, density))
+	case commands.LayoutZoomCommand:
+		cmds = append(cmds, a.setZoom(app.DefaultZoom))
+	case commands.LayoutZoomInCommand:
+		cmds = append(cmds, a.setZoom(app.StepZoom(a.app.Zoom(), 1)))
+	case commands.LayoutZoomOutCommand:
+		cmds = append(cmds, a.setZoom(app.StepZoom(a.app.Zoom(), -1)))
+	case commands.WorkspaceTrustCommand:
+		cmds = append(cmds, a.openModal(dialog.NewTrustDialog(a.app)))
+	case commands.BugReportCommand:
+		cmds = append(cmds, a.app.BuildBugReport(""))
+	case commands.BellToggleCommand:
+		bell := &a.app.State.Bell
+		if !bell.Response && !bell.Task {
+			return a, toast.NewInfoToast("No alerts are on, /bell response or /bell task turns one on")
+		}
+		bell.Muted = !bell.Muted
+		a.app.SaveState()
+		if bell.Muted {
+			cmds = append(cmds, toast.NewInfoToast("Alerts muted"))
+		} else {
+			cmds = append(cmds, toast.NewInfoToast("Alerts unmuted"))
+		}
+	case commands.TutorialCommand:
+		if a.tutorial.Active() {
+			cmds = append(cmds, util.CmdHandler(tutorial.StopMsg{}))
+		} else {
+			cmds = append(cmds, util.CmdHandler(tutorial.StartMsg{}))
+		}
+	case commands.KeybindsCommand:
+		keybindsDialog := dialog.NewKeybindsDialog(a.app)
+		cmds = append(cmds, a.openModal(keybindsDialog))
+		cmds = append(cmds, keybindsDialog.Init())
+	case commands.SessionRenameCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewWarningToast("Start a session before renaming it")
+		}
+		renameDialog := dialog.NewRenameDialog(a.app, a.app.Session)
+		cmds = append(cmds, a.openModal(renameDialog))
+		cmds = append(cmds, renameDialog.Init())
+	case commands.SessionInstructionsCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewWarningToast("Start a session before setting its instructions")
+		}
+		if err := a.app.Features.Require(app.FeatureInstructions); err != nil {
+			return a, toast.NewInfoToast("Session instructions: " + err.Error())
+		}
+		instructionsDialog := dialog.NewInstructionsDialog(a.app, a.app.Session)
+		cmds = append(cmds, a.openModal(instructionsDialog))
+		cmds = append(cmds, instructionsDialog.Init())
+	case commands.SessionEnvCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewWarningToast("Start a session before setting its environment")
+		}
+		if err := a.app.Features.Require(app.FeatureEnv); err != nil {
+			return a, toast.NewInfoToast("Session environment: " + err.Error())
+		}
+		cmds = append(cmds, a.openModal(dialog.NewEnvDialog(a.app, a.app.Session)))
+	case commands.SessionRegenerateCommand:
+		cmds = append(cmds, a.requestRegenerate(nil, nil))
+	case commands.SessionBranchCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewWarningToast("No messages to branch from yet")
+		}
+		if err := a.app.Features.Require(app.FeatureBranch); err != nil {
+			return a, toast.NewInfoToast("Branching: " + err.Error())
+		}
+		messageID, prompt, err := app.BranchPoint(a.app.Messages, a.messages.SelectedMessage())
+		if err != nil {
+			return a, toast.NewWarningToast(err.Error())
+		}
+		sessionID := a.app.Session.ID
+		cmds = append(cmds, func() tea.Msg {
+			session, err := a.app.BranchSession(context.Background(), sessionID, messageID)
+			if err != nil {
+				return toast.NewErrorToast(err.Error())()
+			}
+			return app.SessionBranchedMsg{Session: *session, Prompt: prompt}
+		})
+	case commands.MessageEditCommand:
+		if cmd := a.resendBlocked(); cmd != nil {
+			return a, cmd
+		}
+		cmds = append(cmds, a.openModal(dialog.NewEditMessageDialog(a.app)))
+	case commands.MCPServersCommand:
+		if err := a.app.Features.Require(app.FeatureMCP); err != nil {
+			return a, toast.NewInfoToast("MCP servers: " + err.Error())
+		}
+		mcpDialog := dialog.NewMCPDialog(a.app)
+		cmds = append(cmds, a.openModal(mcpDialog))
+		cmds = append(cmds, mcpDialog.Init())
+	case commands.AppConfigCommand:
+		configDialog := dialog.NewConfigDialog(a.app)
+		cmds = append(cmds, a.openModal(configDialog))
+		cmds = append(cmds, configDialog.Init())
+	case commands.AppLogsCommand:
+		logsDialog := dialog.NewLogsDialog(a.app)
+		cmds = append(cmds, a.openModal(logsDialog))
+		cmds = append(cmds, logsDialog.Init())
+	case commands.MessageInspectCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, nil
+		}
+		cmds = append(cmds, a.openModal(dialog.NewInspectDialog(a.app, a.messages.SelectedMessage())))
+	case commands.DiffViewCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, nil
+		}
+		cmds = append(cmds, a.openModal(dialog.NewDiffDialog(a.app, a.messages.SelectedMessage(), "")))
+	case commands.DiagnosticsCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, nil
+		}
+		cmds = append(cmds, a.openModal(dialog.NewDiagnosticsDialog(a.app)))
+	case commands.PatchReviewCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, nil
+		}
+		if err := a.app.Features.Require(app.FeaturePatch); err != nil {
+			return a, toast.NewInfoToast("Patch review: " + err.Error())
+		}
+		cmds = append(cmds, a.openModal(dialog.NewPatchDialog(a.app)))
 	case commands.SubSessionCommand:
 		subSessionDialog := dialog.NewSubSessionDialog(a.app)
-		a.modal = subSessionDialog
+		cmds = append(cmds, a.openModal(subSessionDialog))
+		cmds = append(cmds, subSessionDialog.Init())
+	case commands.SpawnAgentsCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewInfoToast("Start a session before spawning agents")
+		}
+		spawnDialog := dialog.NewSpawnAgentsDialog(a.app)
+		cmds = append(cmds, a.openModal(spawnDialog))
+		cmds = append(cmds, spawnDialog.Init())
+	case commands.SessionBroadcastCommand:
+		broadcastDialog := dialog.NewBroadcastDialog(a.app, a.editor.Value())
+		cmds = append(cmds, a.openModal(broadcastDialog))
+		cmds = append(cmds, broadcastDialog.Init())
+	case commands.SessionCompareCommand:
+		if a.app.Broadcast == nil {
+			cmds = append(cmds, a.app.LoadLastBroadcast(context.Background()))
+		}
+		compareDialog := dialog.NewBroadcastCompareDialog(a.app)
+		cmds = append(cmds, a.openModal(compareDialog))
+		cmds = append(cmds, compareDialog.Init())
 	case commands.SessionShareCommand:
 		if a.app.Session == nil || a.app.Session.ID == "" {
 			return a, nil
@@ -758,8 +1674,8 @@ func (a appModel) executeCommand(command commands.Command) (tea.Model, tea.Cmd)
 		if a.app.Session == nil || a.app.Session.ID == "" {
 			return a, nil
 		}
-		// TODO: block until compaction is complete
-		a.app.CompactSession(context.Background())
+		cmds = append(cmds, toast.NewInfoToast("Compacting the session…"))
+		cmds = append(cmds, a.app.CompactSession(context.Background()))
 	case commands.ToolDetailsCommand:
 		message := "Tool details are now visible"
 		if a.messages.ToolDetailsVisible() {
@@ -767,28 +1683,123 @@ func (a appModel) executeCommand(command commands.Command) (tea.Model, tea.Cmd)
 		}
 		cmds = append(cmds, util.CmdHandler(chat.ToggleToolDetailsMsg{}))
 		cmds = append(cmds, toast.NewInfoToast(message))
+	case commands.MessagesRawCommand:
+		cmds = append(cmds, util.CmdHandler(chat.ToggleRawMarkdownMsg{}))
 	case commands.ModelListCommand:
 		modelDialog := dialog.NewModelDialog(a.app)
-		a.modal = modelDialog
+		cmds = append(cmds, a.openModal(modelDialog))
+	case commands.ModelCycleCommand:
+		// listing the providers goes to the server, so it runs off the update loop
+		cmds = append(cmds, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			provider, model, err := a.app.NextFavoriteModel(ctx)
+			if err != nil {
+				return toast.NewErrorToast(err.Error())()
+			}
+			return tea.Batch(
+				util.CmdHandler(app.ModelSelectedMsg{Provider: provider, Model: model}),
+				toast.NewInfoToast(fmt.Sprintf("Switched to %s (%s)", model.Name, provider.Name)),
+			)()
+		})
 	case commands.AgentModeCommand:
 		agentDialog := dialog.NewAgentDialog(a.app)
-		a.modal = agentDialog
+		cmds = append(cmds, a.openModal(agentDialog))
 	case commands.ThemeListCommand:
 		themeDialog := dialog.NewThemeDialog()
-		a.modal = themeDialog
+		cmds = append(cmds, a.openModal(themeDialog))
+	case commands.SessionRecordCommand:
+		if !a.recorder.Recording() {
+			if err := a.recorder.Start(a.recordingsDir(), a.width, a.height+2); err != nil {
+				slog.Error("Failed to start recording", "error", err)
+				return a, toast.NewErrorToast("Failed to start recording")
+			}
+			cmds = append(cmds, toast.NewInfoToast("Recording started, run /record again to stop"))
+			break
+		}
+		cmds = append(cmds, a.saveRecording(recorder.Range{}))
+	case commands.SessionExportCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, nil
+		}
+		cmds = append(cmds, a.exportSession(app.ExportMarkdown))
+	case commands.SessionPRCommand:
+		cmds = append(cmds, a.describePR(app.PRGitHub, false))
+	case commands.SessionContinueCommand:
+		var cmd tea.Cmd
+		a, cmd = a.continueSession("")
+		cmds = append(cmds, cmd)
+	case commands.NotificationsToggleCommand:
+		a.app.State.Notifications = !a.app.State.Notifications
+		a.app.SaveState()
+		message := "Desktop notifications disabled"
+		if a.app.State.Notifications {
+			message = "Desktop notifications enabled"
+		}
+		cmds = append(cmds, toast.NewInfoToast(message))
+	case commands.ProjectOpenCommand:
+		editor := os.Getenv("EDITOR")
+		if editor == "" {
+			return a, toast.NewErrorToast("No EDITOR set, can't open the project")
+		}
+		c := exec.Command(editor, a.app.Info.Path.Root) //nolint:gosec
+		c.Dir = a.app.Info.Path.Root
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		cmds = append(cmds, tea.ExecProcess(c, func(err error) tea.Msg {
+			if err != nil {
+				slog.Error("Failed to open editor", "error", err)
+			}
+			return nil
+		}))
 	case commands.ProjectInitCommand:
-		cmds = append(cmds, a.app.InitializeProject(context.Background()))
+		// init writes files into the project, so a dirty tree is confirmed
+		// by running it again
+		if warning := a.app.DirtyWarning(); warning != "" && time.Now().After(a.confirmInitUntil) {
+			a.confirmInitUntil = time.Now().Add(dirtyConfirmTimeout)
+			cmds = append(cmds, toast.NewWarningToast(warning+", run init again to continue"))
+			break
+		}
+		a.confirmInitUntil = time.Time{}
+		// servers that take no analysis initialize right away
+		if !a.app.Features.Enabled(app.FeatureAnalysis) {
+			cmds = append(cmds, a.app.InitializeProject(context.Background(), ""))
+			break
+		}
+		root := a.app.Info.Path.Root
+		cmds = append(cmds, func() tea.Msg {
+			analysis, err := app.AnalyzeProject(root)
+			if err != nil {
+				slog.Warn("Project analysis failed", "error", err)
+			}
+			return app.ProjectAnalyzedMsg{Analysis: analysis}
+		})
 	case commands.InputClearCommand:
-		if a.editor.Value() == "" {
+		text := a.editor.Value()
+		if text == "" {
 			return a, nil
 		}
 		updated, cmd := a.editor.Clear()
 		a.editor = updated.(chat.EditorComponent)
 		cmds = append(cmds, cmd)
+		if strings.TrimSpace(text) != "" {
+			cmds = append(cmds, a.app.Defer("draft", "Input cleared", nil, util.CmdHandler(draftRestoredMsg{text: text})))
+		}
+	case commands.UndoCommand:
+		_, label, undo, ok := a.app.Pending.Undo()
+		if !ok {
+			return a, toast.NewInfoToast("Nothing to undo")
+		}
+		cmds = append(cmds, undo, toast.NewSuccessToast("Undone: "+label))
 	case commands.InputPasteCommand:
 		updated, cmd := a.editor.Paste()
 		a.editor = updated.(chat.EditorComponent)
 		cmds = append(cmds, cmd)
+	case commands.InputPasteCodeCommand:
+		updated, cmd := a.editor.PasteCode()
+		a.editor = updated.(chat.EditorComponent)
+		cmds = append(cmds, cmd)
 	case commands.InputSubmitCommand:
 		updated, cmd := a.editor.Submit()
 		a.editor = updated.(chat.EditorComponent)
@@ -819,6 +1830,15 @@ func (a appModel) executeCommand(command commands.Command) (tea.Model, tea.Cmd)
 		updated, cmd := a.messages.Last()
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
+	case commands.MessagesBookmarkCommand:
+		cmds = append(cmds, a.bookmark(""))
+	case commands.MessagesCopyCodeCommand:
+		cmds = append(cmds, a.copyCodeBlock(0))
+	case commands.MessagesBookmarksCommand:
+		if a.app.Session == nil || a.app.Session.ID == "" {
+			return a, toast.NewWarningToast("No bookmarks before the session starts")
+		}
+		cmds = append(cmds, a.openModal(dialog.NewBookmarksDialog(a.app, a.app.Session.ID)))
 	case commands.MessagesPageUpCommand:
 		updated, cmd := a.messages.PageUp()
 		a.messages = updated.(chat.MessagesComponent)
@@ -836,11 +1856,24 @@ func (a appModel) executeCommand(command commands.Command) (tea.Model, tea.Cmd)
 		a.messages = updated.(chat.MessagesComponent)
 		cmds = append(cmds, cmd)
 	case commands.AppExitCommand:
-		return a, tea.Quit
+		return a, a.exit()
+	default:
+		if action, ok := a.app.PluginAction(command.Name); ok {
+			cmds = append(cmds, a.runPluginAction(action, nil))
+		}
 	}
 	return a, tea.Batch(cmds...)
 }
 
+// runPluginAction runs a plugin command, or opens the panel of a plugin
+func (a *appModel) runPluginAction(action app.PluginAction, args []string) tea.Cmd {
+	if action.Panel != nil {
+		panel := dialog.NewPluginPanelDialog(a.app, action)
+		return tea.Batch(a.openModal(panel), panel.Init())
+	}
+	return a.app.RunPluginCommand(action, args)
+}
+
 func (a appModel) updateCompletions(msg tea.Msg) (tea.Model, tea.Cmd) {
 	currentInput := a.editor.Value()
 	if currentInput != "" {
@@ -850,6 +1883,14 @@ func (a appModel) updateCompletions(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a.completions.Update(msg)
 }
 
+func newLeaderBinding(leader string) *key.Binding {
+	if leader == "" {
+		return nil
+	}
+	binding := key.NewBinding(key.WithKeys(leader))
+	return &binding
+}
+
 func NewModel(app *app.App) tea.Model {
 	completionManager := completions.NewCompletionManager(app)
 	initialProvider := completionManager.DefaultProvider()
@@ -857,80 +1898,266 @@ func NewModel(app *app.App) tea.Model {
 	messages := chat.NewMessagesComponent(app)
 	editor := chat.NewEditorComponent(app)
 	completions := dialog.NewCompletionDialogComponent(initialProvider)
-
-	var leaderBinding *key.Binding
-	if app.Config.Keybinds.Leader != "" {
-		binding := key.NewBinding(key.WithKeys(app.Config.Keybinds.Leader))
-		leaderBinding = &binding
-	}
+	leaderBinding := newLeaderBinding(app.Config.Keybinds.Leader)
 
 	model := &appModel{
 		status:               status.NewStatusCmp(app),
 		app:                  app,
 		editor:               editor,
 		messages:             messages,
+		home:                 home.NewHomeComponent(app),
 		completions:          completions,
 		completionManager:    completionManager,
 		leaderBinding:        leaderBinding,
 		isLeaderSequence:     false,
 		showCompletionDialog: false,
 		toastManager:         toast.NewToastManager(),
+		flash:                flash.NewFlashComponent(app.State.ReducedMotion || styles.Accessible()),
+		tutorial:             tutorial.NewTutorialComponent(app),
 		interruptKeyState:    InterruptKeyIdle,
 		isAltScreen:          false, // Start with alt screen disabled (normal terminal mode)
+		isFocused:            true,
+		recorder:             recorder.New(),
+		tracer:               logging.NewTracer(tracingFromEnv()),
+		pendingSessions:      make(map[string]opencode.Session),
+		tabs:                 []*sessionTab{{view: messages}},
+	}
+	if app.State.Draft != "" {
+		editor.SetValue(app.State.Draft)
 	}
 
 	return model
 }
 
-// navigateToSibling navigates to the next or previous sibling sub-session
-func (a *appModel) navigateToSibling(ctx context.Context, direction string) tea.Cmd {
-	return func() tea.Msg {
-		// Only works if we're in a sub-session
-		if a.app.Session == nil || a.app.Session.ParentID == "" {
-			return toast.NewInfoToast("Not in a sub-session")
-		}
+// tracingFromEnv reports whether message tracing is turned on from the start
+func tracingFromEnv() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(logging.TraceEnv))
+	return enabled
+}
 
-		// Get all siblings
-		endpoint := fmt.Sprintf("/session/%s/sub-sessions", a.app.Session.ParentID)
-		var siblings []map[string]interface{}
-		err := a.app.Client.Get(ctx, endpoint, nil, &siblings)
-		if err != nil {
-			return toast.NewErrorToast(fmt.Sprintf("Failed to get siblings: %v", err))
-		}
+// taskToastText names a task and what happened to it
+func taskToastText(task app.TaskInfo, event string) string {
+	name := task.Description
+	if label := task.Label(); label != "" {
+		name = label
+	}
+	return fmt.Sprintf("Task %s: %s", event, name)
+}
 
-		if len(siblings) <= 1 {
-			return toast.NewInfoToast("No sibling sub-sessions")
+// taskToast is taskToastText for a task known by ID only
+func (a appModel) taskToast(taskID, event string) string {
+	if a.app.TaskClient != nil {
+		if task, ok := a.app.TaskClient.GetTask(taskID); ok {
+			return taskToastText(*task, event)
 		}
+	}
+	return "Task " + event
+}
 
-		// Find current session index
-		currentIndex := -1
-		for i, sibling := range siblings {
-			if id, ok := sibling["id"].(string); ok && id == a.app.Session.ID {
-				currentIndex = i
-				break
+// notifyUnfocused sends a desktop notification when the terminal is not
+// focused, as otherwise the result is already on screen
+func (a appModel) notifyUnfocused(title, body string, priority app.NotificationPriority) tea.Cmd {
+	if a.isFocused {
+		return nil
+	}
+	return a.app.Notify(notify.Notification{Title: title, Body: body}, priority)
+}
+
+// notifyTask sends a desktop notification for a finished task when the
+// terminal is not focused
+func (a appModel) notifyTask(taskID, title, body string, priority app.NotificationPriority) tea.Cmd {
+	if a.isFocused {
+		return nil
+	}
+	if a.app.TaskClient != nil {
+		if task, ok := a.app.TaskClient.GetTask(taskID); ok {
+			if label := task.Label(); label != "" {
+				title = fmt.Sprintf("%s: %s", label, strings.ToLower(title))
+			}
+			if body == "" {
+				body = task.Description
 			}
 		}
+	}
+	return a.notifyUnfocused(title, body, priority)
+}
 
-		if currentIndex == -1 {
-			return toast.NewErrorToast("Current session not found in siblings")
-		}
+// setLayout saves the content width and density and lays the screen out
+// again
+func (a appModel) setLayout(maxWidth int, density layout.Density) tea.Cmd {
+	a.app.State.MaxWidth = maxWidth
+	a.app.State.Density = string(density)
+	a.app.SaveState()
+	a.applyLayout()
+	return tea.Batch(
+		util.CmdHandler(app.LayoutChangedMsg{}),
+		toast.NewInfoToast(fmt.Sprintf("Width %s, %s layout, %d%% zoom", app.FormatMaxWidth(maxWidth), density, a.app.Zoom())),
+	)
+}
 
-		// Calculate next index with wrap-around
-		var nextIndex int
-		if direction == "next" {
-			nextIndex = (currentIndex + 1) % len(siblings)
-		} else {
-			nextIndex = currentIndex - 1
-			if nextIndex < 0 {
-				nextIndex = len(siblings) - 1
-			}
+// setZoom saves the zoom and lays the screen out again with it
+func (a appModel) setZoom(zoom int) tea.Cmd {
+	a.app.State.Zoom = zoom
+	return a.setLayout(a.app.State.MaxWidth, a.app.Density())
+}
+
+// applyLayout sizes the content column and the components for the current
+// terminal size, width and density settings
+func (a appModel) applyLayout() {
+	layout.Current = &layout.LayoutInfo{
+		Viewport: layout.Dimensions{
+			Width:  a.width,
+			Height: a.height,
+		},
+		Container: layout.Dimensions{
+			Width: a.app.ContentWidth(a.width),
+		},
+		Density: a.app.Density(),
+		Zoom:    a.app.Zoom(),
+	}
+	// Update child component sizes
+	messagesHeight := a.height - 6 - a.tabBarHeight() // Leave room for editor, status and tab bar
+	a.messages.SetSize(a.width, messagesHeight)
+	a.home.SetSize(a.width, a.height-5-a.tabBarHeight())
+	a.editor.SetSize(layout.Current.Container.Width, 5)
+}
+
+// bookmark saves the spot at the top of the transcript under a name, a
+// numbered one when name is empty
+func (a appModel) bookmark(name string) tea.Cmd {
+	if a.app.Session == nil || a.app.Session.ID == "" {
+		return toast.NewWarningToast("Start a session before bookmarking it")
+	}
+	messageID, offset, ok := a.messages.ScrollPosition()
+	if !ok {
+		return toast.NewWarningToast("Nothing in view to bookmark")
+	}
+	bookmark := a.app.SetSessionBookmark(a.app.Session.ID, config.Bookmark{
+		Name:      name,
+		MessageID: messageID,
+		Offset:    offset,
+	})
+	hint := ""
+	if key := a.app.CommandKey(commands.MessagesBookmarksCommand); key != "" {
+		hint = ", " + key + " lists the bookmarks"
+	}
+	return toast.NewSuccessToast("Bookmarked as " + bookmark.Name + hint)
+}
+
+// copyCodeBlock copies the nth code block of the selected message, or of
+// the last response with any, and the last block for 0
+func (a appModel) copyCodeBlock(n int) tea.Cmd {
+	block, n, total, err := app.CodeBlockOf(a.app.Messages, a.messages.SelectedMessage(), n)
+	if err != nil {
+		return toast.NewWarningToast(err.Error())
+	}
+	language := block.Language
+	if language == "" {
+		language = "code"
+	}
+	message := fmt.Sprintf("Copied %s block %d of %d, %d lines", language, n, total, block.Lines())
+	return func() tea.Msg {
+		// without a system clipboard, e.g. over ssh, the terminal copies it
+		if err := clipboard.WriteAll(block.Code); err != nil {
+			slog.Debug("System clipboard unavailable, copying through the terminal", "error", err)
+			return tea.Batch(tea.SetClipboard(block.Code), toast.NewSuccessToast(message))()
 		}
+		return toast.NewSuccessToast(message)()
+	}
+}
 
-		// Switch to sibling
-		if nextID, ok := siblings[nextIndex]["id"].(string); ok {
-			return a.app.SwitchToSession(ctx, nextID)()
+// exit saves the unsent prompt and the state before quitting, and tells the
+// user about work that is cut off. Signals go through here too, so closing
+// the terminal loses no more than the quit command does.
+func (a appModel) exit() tea.Cmd {
+	// the viewer keeps the draft and the session to resume of the last
+	// normal run
+	if !a.app.ViewOnly() {
+		a.app.State.Draft = a.editor.Value()
+		a.app.RememberSession()
+	}
+	a.app.SaveState()
+	// deletes still in their undo window are carried out before exiting
+	for _, commit := range a.app.Pending.Flush() {
+		if msg := commit(); msg != nil {
+			slog.Warn("Pending operation failed on exit", "result", msg)
 		}
+	}
+	if err := a.app.Search.Close(); err != nil {
+		slog.Error("Failed to save search index", "error", err)
+	}
+	a.recorder.Close()
+	a.app.Mirror.Close()
+	a.app.Files.Close()
+
+	var cutOff []string
+	if a.app.IsBusy() {
+		cutOff = append(cutOff, "a response was still generating")
+	}
+	if running := a.app.RunningTasks(); running > 0 {
+		cutOff = append(cutOff, fmt.Sprintf("%d agent tasks were still running", running))
+	}
+	if len(cutOff) == 0 {
+		return tea.Sequence(tea.SetWindowTitle(""), tea.Quit)
+	}
+	body := "DGMO exited while " + strings.Join(cutOff, " and ")
+	slog.Warn("Exiting with work in progress", "reason", body)
+	return tea.Sequence(
+		a.app.Notify(notify.Notification{Title: "DGMO exited", Body: body}, app.NotificationNormal),
+		tea.SetWindowTitle(""),
+		tea.Quit,
+	)
+}
+
+// currentWindowTitle names the session in the terminal title, marked when
+// there is an unsent prompt or work in progress so closing the window is an
+// informed choice
+func (a appModel) currentWindowTitle() string {
+	title := "dgmo"
+	if a.app.Session != nil && a.app.Session.Title != "" {
+		title += " · " + a.app.Session.Title
+	}
+	if a.app.IsBusy() || a.app.RunningTasks() > 0 {
+		title += " (working)"
+	}
+	if strings.TrimSpace(a.editor.Value()) != "" {
+		title = "● " + title
+	}
+	return title
+}
 
-		return toast.NewErrorToast("Failed to get sibling ID")
+// promptPermission shows the approval prompt for a tool call on top of any
+// open modal, since the session is blocked until it is answered
+func (a *appModel) promptPermission(request app.PermissionRequest) tea.Cmd {
+	a.pushModal(dialog.NewPermissionDialog(request))
+	return flash.New(flash.SeverityWarning)
+}
+
+func (a appModel) respondPermission(request app.PermissionRequest, response app.PermissionResponse) tea.Cmd {
+	return func() tea.Msg {
+		err := a.app.Permissions.Respond(context.Background(), request, response)
+		return app.PermissionRespondedMsg{Request: request, Response: response, Err: err}
+	}
+}
+
+// navigateToSibling navigates to the next or previous sibling sub-session
+func (a *appModel) navigateToSibling(ctx context.Context, direction string) tea.Cmd {
+	session := a.app.Session
+	step := -1
+	if direction == "next" {
+		step = 1
+	}
+	return func() tea.Msg {
+		siblingID, err := a.app.SubSessions.Sibling(ctx, session, step)
+		switch {
+		case errors.Is(err, app.ErrNotSubSession):
+			return toast.NewInfoToast("Not in a sub-session")()
+		case errors.Is(err, app.ErrNoSiblings):
+			return toast.NewInfoToast("No sibling sub-sessions")()
+		case err != nil:
+			slog.Error("Failed to get siblings", "error", err)
+			return toast.NewErrorToast(fmt.Sprintf("Failed to get siblings: %v", err))()
+		}
+		return a.app.SwitchToSession(ctx, siblingID)()
 	}
 }
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/sst/dgmo/internal/app"
+	"github.com/sst/dgmo/internal/commands"
+	"github.com/sst/dgmo/internal/components/toast"
+	"github.com/sst/dgmo/internal/styles"
+	"github.com/sst/dgmo/internal/theme"
+	"github.com/sst/dgmo/internal/util"
+)
+
+// viewerCommands are the commands of the read-only viewer, none of them
+// changes the session
+var viewerCommands = map[commands.CommandName]bool{
+	commands.AppHelpCommand:              true,
+	commands.AppExitCommand:              true,
+	commands.KeybindsCommand:             true,
+	commands.ThemeListCommand:            true,
+	commands.ToolDetailsCommand:          true,
+	commands.MessagesRawCommand:          true,
+	commands.MessageInspectCommand:       true,
+	commands.DiffViewCommand:             true,
+	commands.SessionExportCommand:        true,
+	commands.SessionPRCommand:            true,
+	commands.SessionTimelineCommand:      true,
+	commands.SearchCommand:               true,
+	commands.LayoutWidthCommand:          true,
+	commands.LayoutDensityCommand:        true,
+	commands.LayoutZoomCommand:           true,
+	commands.LayoutZoomInCommand:         true,
+	commands.LayoutZoomOutCommand:        true,
+	commands.PerfHUDCommand:              true,
+	commands.TraceCommand:                true,
+	commands.MessagesPageUpCommand:       true,
+	commands.MessagesPageDownCommand:     true,
+	commands.MessagesHalfPageUpCommand:   true,
+	commands.MessagesHalfPageDownCommand: true,
+	commands.MessagesPreviousCommand:     true,
+	commands.MessagesNextCommand:         true,
+	commands.MessagesFirstCommand:        true,
+	commands.MessagesLastCommand:         true,
+}
+
+// viewerKeys are single key shortcuts of the viewer, which has no editor
+// to type into
+var viewerKeys = map[string]commands.CommandName{
+	"q":     commands.AppExitCommand,
+	"?":     commands.AppHelpCommand,
+	"x":     commands.SessionExportCommand,
+	"t":     commands.SessionTimelineCommand,
+	"d":     commands.ToolDetailsCommand,
+	"r":     commands.MessagesRawCommand,
+	"/":     commands.SearchCommand,
+	"space": commands.MessagesPageDownCommand,
+	"b":     commands.MessagesPageUpCommand,
+	"j":     commands.MessagesHalfPageDownCommand,
+	"down":  commands.MessagesHalfPageDownCommand,
+	"k":     commands.MessagesHalfPageUpCommand,
+	"up":    commands.MessagesHalfPageUpCommand,
+	"g":     commands.MessagesFirstCommand,
+	"G":     commands.MessagesLastCommand,
+}
+
+// updateViewer handles a key press in the read-only viewer: its own
+// shortcuts first, then the keybindings of the viewer commands
+func (a appModel) updateViewer(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if name, ok := viewerKeys[msg.String()]; ok {
+		return a, util.CmdHandler(commands.ExecuteCommandMsg(a.app.Commands[name]))
+	}
+	if a.leaderBinding != nil && !a.isLeaderSequence && key.Matches(msg, *a.leaderBinding) {
+		a.isLeaderSequence = true
+		return a, nil
+	}
+	matches := a.app.Commands.Matches(msg, a.isLeaderSequence)
+	a.isLeaderSequence = false
+	if len(matches) > 0 {
+		return a, util.CmdHandler(commands.ExecuteCommandsMsg(matches))
+	}
+	return a, nil
+}
+
+// viewerBlocked rejects the commands the viewer does not offer
+func (a appModel) viewerBlocked(command commands.Command) tea.Cmd {
+	if !a.app.ViewOnly() || viewerCommands[command.Name] {
+		return nil
+	}
+	return toast.NewWarningToast(app.ErrViewOnly.Error())
+}
+
+// viewerFooter takes the place of the editor in the viewer
+func (a appModel) viewerFooter(width int, align lipgloss.Position) string {
+	t := theme.CurrentTheme()
+	muted := styles.NewStyle().Foreground(t.TextMuted()).Background(t.Background())
+	text := "read-only · space/b page · g/G first/last · x export · t timeline · q quit"
+	return lipgloss.PlaceHorizontal(
+		width,
+		align,
+		muted.Render(text),
+		styles.WhitespaceStyle(t.Background()),
+	)
+}
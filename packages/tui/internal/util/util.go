@@ -1,12 +1,14 @@
 package util
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func CmdHandler(msg tea.Msg) tea.Cmd {
@@ -23,6 +25,38 @@ func Clamp(v, low, high int) int {
 	return min(high, max(low, v))
 }
 
+// TruncateMiddle shortens s to width cells by replacing its middle with an
+// ellipsis, keeping slightly more of the end. ANSI styles are preserved.
+func TruncateMiddle(s string, width int) string {
+	total := ansi.StringWidth(s)
+	if total <= width {
+		return s
+	}
+	if width <= 1 {
+		return ansi.Truncate("…", width, "")
+	}
+	keep := width - 1
+	head := keep / 2
+	tail := keep - head
+	return ansi.Truncate(s, head, "") + "…" + ansi.TruncateLeft(s, total-tail, "")
+}
+
+// FormatTokens formats a token count for display, e.g. 950, 110K or 1.2M
+func FormatTokens(tokens float64) string {
+	var formatted string
+	switch {
+	case tokens >= 1_000_000:
+		formatted = fmt.Sprintf("%.1fM", tokens/1_000_000)
+	case tokens >= 1_000:
+		formatted = fmt.Sprintf("%.1fK", tokens/1_000)
+	default:
+		return fmt.Sprintf("%d", int(tokens))
+	}
+	// Remove .0 suffix if present
+	formatted = strings.Replace(formatted, ".0K", "K", 1)
+	return strings.Replace(formatted, ".0M", "M", 1)
+}
+
 func IsWsl() bool {
 	// Check for WSL environment variables
 	if os.Getenv("WSL_DISTRO_NAME") != "" {